@@ -0,0 +1,41 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/iotaledger/goshimmer/packages/assetregistry"
+	webapi_assetregistry "github.com/iotaledger/goshimmer/plugins/webapi/assetregistry"
+)
+
+const (
+	routeAsset  = "assetRegistry/asset"
+	routeAssets = "assetRegistry/assets"
+)
+
+// GetAsset gets the published metadata of the asset with the given color.
+func (api *GoShimmerAPI) GetAsset(base58EncodedColor string) (*webapi_assetregistry.AssetResponse, error) {
+	res := &webapi_assetregistry.AssetResponse{}
+	if err := api.do(http.MethodGet, func() string {
+		return fmt.Sprintf("%s?color=%s", routeAsset, base58EncodedColor)
+	}(), nil, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetAssets gets the published metadata of all known assets.
+func (api *GoShimmerAPI) GetAssets() (*webapi_assetregistry.AssetsResponse, error) {
+	res := &webapi_assetregistry.AssetsResponse{}
+	if err := api.do(http.MethodGet, routeAssets, nil, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// PublishAssetMetadata issues a message with the given (signed) AssetMetadata payload and returns its message ID.
+func (api *GoShimmerAPI) PublishAssetMetadata(assetMetadata *assetregistry.AssetMetadata) (string, error) {
+	return api.SendPayload(assetMetadata.Bytes())
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+
+	webapi_mana "github.com/iotaledger/goshimmer/plugins/webapi/mana"
+)
+
+const (
+	routeGetMana                  = "mana"
+	routeGetAllMana               = "mana/all"
+	routeGetNHighestAccessMana    = "mana/access/nhighest"
+	routeGetNHighestConsensusMana = "mana/consensus/nhighest"
+	routeGetManaSnapshot          = "mana/snapshot"
+)
+
+// GetOwnMana returns the access and consensus mana of the node this api client is communicating with.
+func (api *GoShimmerAPI) GetOwnMana() (*webapi_mana.GetManaResponse, error) {
+	return api.GetManaOfNode("")
+}
+
+// GetManaOfNode returns the access and consensus mana of the node with the given base58 encoded node ID.
+func (api *GoShimmerAPI) GetManaOfNode(nodeID string) (*webapi_mana.GetManaResponse, error) {
+	res := &webapi_mana.GetManaResponse{}
+	route := routeGetMana
+	if nodeID != "" {
+		route += "?nodeID=" + nodeID
+	}
+	if err := api.do(http.MethodGet, route, nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetAllMana returns the access and consensus mana of all the nodes known to the node.
+func (api *GoShimmerAPI) GetAllMana() (*webapi_mana.GetAllManaResponse, error) {
+	res := &webapi_mana.GetAllManaResponse{}
+	if err := api.do(http.MethodGet, routeGetAllMana, nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetNHighestAccessMana returns the n nodes with the highest access mana.
+func (api *GoShimmerAPI) GetNHighestAccessMana(n uint) (*webapi_mana.GetNHighestResponse, error) {
+	res := &webapi_mana.GetNHighestResponse{}
+	if err := api.do(http.MethodGet, routeGetNHighestAccessMana+"?number="+strconv.FormatUint(uint64(n), 10), nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetNHighestConsensusMana returns the n nodes with the highest consensus mana.
+func (api *GoShimmerAPI) GetNHighestConsensusMana(n uint) (*webapi_mana.GetNHighestResponse, error) {
+	res := &webapi_mana.GetNHighestResponse{}
+	if err := api.do(http.MethodGet, routeGetNHighestConsensusMana+"?number="+strconv.FormatUint(uint64(n), 10), nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetManaSnapshot returns the most recent snapshot of the given mana type ("access" or "consensus") that was taken at
+// or before the given unix timestamp.
+func (api *GoShimmerAPI) GetManaSnapshot(manaType string, timestamp int64) (*webapi_mana.GetSnapshotResponse, error) {
+	res := &webapi_mana.GetSnapshotResponse{}
+	route := routeGetManaSnapshot + "?type=" + manaType + "&timestamp=" + strconv.FormatInt(timestamp, 10)
+	if err := api.do(http.MethodGet, route, nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,41 @@
+package client
+
+import (
+	"net/http"
+
+	webapi_manualpeering "github.com/iotaledger/goshimmer/plugins/webapi/manualpeering"
+)
+
+const (
+	routeManualPeers = "manualpeering/peers"
+)
+
+// GetManualPeers gets the manually configured peers together with the state of their connection.
+func (api *GoShimmerAPI) GetManualPeers() (*webapi_manualpeering.PeersResponse, error) {
+	res := &webapi_manualpeering.PeersResponse{}
+	if err := api.do(http.MethodGet, routeManualPeers, nil, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// AddManualPeers adds the given peers (base58 encoded public key and gossip address) to the manually configured peers.
+func (api *GoShimmerAPI) AddManualPeers(peers []webapi_manualpeering.Peer) (*webapi_manualpeering.PeersResponse, error) {
+	res := &webapi_manualpeering.PeersResponse{}
+	if err := api.do(http.MethodPost, routeManualPeers, &webapi_manualpeering.AddPeersRequest{Peers: peers}, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// RemoveManualPeers removes the peers with the given base58 encoded public keys from the manually configured peers.
+func (api *GoShimmerAPI) RemoveManualPeers(publicKeys []string) (*webapi_manualpeering.PeersResponse, error) {
+	res := &webapi_manualpeering.PeersResponse{}
+	if err := api.do(http.MethodDelete, routeManualPeers, &webapi_manualpeering.RemovePeersRequest{PublicKeys: publicKeys}, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	webapi_subscriptions "github.com/iotaledger/goshimmer/plugins/webapi/subscriptions"
+)
+
+const (
+	routeSubscriptions = "subscriptions"
+
+	// subscriptionEventBufferSize defines how many Events are buffered before the reader waits for the consumer.
+	subscriptionEventBufferSize = 1024
+)
+
+// Subscribe opens a websocket connection to the node that streams the state changes of the Transactions, addresses and
+// Messages that are added to the returned Subscription.
+func (api *GoShimmerAPI) Subscribe() (subscription *Subscription, err error) {
+	header := http.Header{}
+	if api.basicAuth.IsEnabled() {
+		username, password := api.basicAuth.Credentials()
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(username+":"+password)))
+	}
+
+	url := strings.Replace(api.baseURL, "http", "ws", 1) + "/" + routeSubscriptions
+	conn, _, err := websocket.DefaultDialer.Dial(url, header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", url, err)
+	}
+
+	subscription = &Subscription{
+		conn:   conn,
+		events: make(chan webapi_subscriptions.Event, subscriptionEventBufferSize),
+	}
+	go subscription.readEvents()
+
+	return
+}
+
+// Subscription is a websocket connection to a node that receives the state changes of the subscribed Transactions,
+// addresses and Messages.
+type Subscription struct {
+	conn       *websocket.Conn
+	events     chan webapi_subscriptions.Event
+	writeMutex sync.Mutex
+}
+
+// SubscribeTransactions adds the given base58 encoded TransactionIDs to the Subscription.
+func (s *Subscription) SubscribeTransactions(base58EncodedTransactionIDs ...string) error {
+	return s.send(webapi_subscriptions.Request{Action: webapi_subscriptions.ActionSubscribe, TransactionIDs: base58EncodedTransactionIDs})
+}
+
+// UnsubscribeTransactions removes the given base58 encoded TransactionIDs from the Subscription.
+func (s *Subscription) UnsubscribeTransactions(base58EncodedTransactionIDs ...string) error {
+	return s.send(webapi_subscriptions.Request{Action: webapi_subscriptions.ActionUnsubscribe, TransactionIDs: base58EncodedTransactionIDs})
+}
+
+// SubscribeAddresses adds the given base58 encoded addresses to the Subscription.
+func (s *Subscription) SubscribeAddresses(base58EncodedAddresses ...string) error {
+	return s.send(webapi_subscriptions.Request{Action: webapi_subscriptions.ActionSubscribe, Addresses: base58EncodedAddresses})
+}
+
+// UnsubscribeAddresses removes the given base58 encoded addresses from the Subscription.
+func (s *Subscription) UnsubscribeAddresses(base58EncodedAddresses ...string) error {
+	return s.send(webapi_subscriptions.Request{Action: webapi_subscriptions.ActionUnsubscribe, Addresses: base58EncodedAddresses})
+}
+
+// SubscribeMessages adds the given base58 encoded MessageIDs to the Subscription.
+func (s *Subscription) SubscribeMessages(base58EncodedMessageIDs ...string) error {
+	return s.send(webapi_subscriptions.Request{Action: webapi_subscriptions.ActionSubscribe, MessageIDs: base58EncodedMessageIDs})
+}
+
+// UnsubscribeMessages removes the given base58 encoded MessageIDs from the Subscription.
+func (s *Subscription) UnsubscribeMessages(base58EncodedMessageIDs ...string) error {
+	return s.send(webapi_subscriptions.Request{Action: webapi_subscriptions.ActionUnsubscribe, MessageIDs: base58EncodedMessageIDs})
+}
+
+// Events returns the channel that receives the Events of the Subscription. It is closed when the connection is lost or
+// the Subscription is closed.
+func (s *Subscription) Events() <-chan webapi_subscriptions.Event {
+	return s.events
+}
+
+// Close closes the connection to the node.
+func (s *Subscription) Close() error {
+	return s.conn.Close()
+}
+
+// send writes the given Request to the websocket connection.
+func (s *Subscription) send(request webapi_subscriptions.Request) (err error) {
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	if err = s.conn.WriteJSON(request); err != nil {
+		err = fmt.Errorf("failed to send subscription request: %w", err)
+	}
+
+	return
+}
+
+// readEvents reads the Events from the websocket connection until it is closed.
+func (s *Subscription) readEvents() {
+	defer close(s.events)
+
+	for {
+		var event webapi_subscriptions.Event
+		if err := s.conn.ReadJSON(&event); err != nil {
+			return
+		}
+
+		s.events <- event
+	}
+}
@@ -0,0 +1,31 @@
+package client
+
+import (
+	"net/http"
+
+	webapi_tipselection "github.com/iotaledger/goshimmer/plugins/webapi/tipselection"
+)
+
+const (
+	routeTipSelection = "tipselection"
+)
+
+// GetTipSelectionStrategy returns the tip selection strategy that is currently used by the node.
+func (api *GoShimmerAPI) GetTipSelectionStrategy() (*webapi_tipselection.TipSelectionResponse, error) {
+	res := &webapi_tipselection.TipSelectionResponse{}
+	if err := api.do(http.MethodGet, routeTipSelection, nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SetTipSelectionStrategy replaces the tip selection strategy of the node. The maxAge (i.e. "1m") is only used by the
+// maxAge strategy and can be left empty to use the configured default.
+func (api *GoShimmerAPI) SetTipSelectionStrategy(strategy string, maxAge string) (*webapi_tipselection.TipSelectionResponse, error) {
+	res := &webapi_tipselection.TipSelectionResponse{}
+	if err := api.do(http.MethodPost, routeTipSelection,
+		&webapi_tipselection.TipSelectionRequest{Strategy: strategy, MaxAge: maxAge}, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -3,16 +3,23 @@ package client
 import (
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	webapi_value "github.com/iotaledger/goshimmer/plugins/webapi/value"
 )
 
 const (
-	routeAttachments    = "value/attachments"
-	routeGetTxnByID     = "value/transactionByID"
-	routeSendTxn        = "value/sendTransaction"
-	routeSendTxnByJSON  = "value/sendTransactionByJson"
-	routeUnspentOutputs = "value/unspentOutputs"
+	routeAddressHistory        = "value/address/history"
+	routeAddressBalanceHistory = "value/address/balanceHistory"
+	routeAttachments           = "value/attachments"
+	routeColor                 = "value/color"
+	routeColors                = "value/colors"
+	routeGetTxnByID            = "value/transactionByID"
+	routeSendTxn               = "value/sendTransaction"
+	routeSendTxnByJSON         = "value/sendTransactionByJson"
+	routeUnspentOutputs        = "value/unspentOutputs"
 )
 
 // GetAttachments gets the attachments of a transaction ID
@@ -39,6 +46,90 @@ func (api *GoShimmerAPI) GetTransactionByID(base58EncodedTxnID string) (*webapi_
 	return res, nil
 }
 
+// GetColor gets the supply information of a color.
+func (api *GoShimmerAPI) GetColor(base58EncodedColor string) (*webapi_value.ColorResponse, error) {
+	res := &webapi_value.ColorResponse{}
+	if err := api.do(http.MethodGet, func() string {
+		return fmt.Sprintf("%s?color=%s", routeColor, base58EncodedColor)
+	}(), nil, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetColors gets the supply information of all known colors.
+func (api *GoShimmerAPI) GetColors() (*webapi_value.ColorsResponse, error) {
+	res := &webapi_value.ColorsResponse{}
+	if err := api.do(http.MethodGet, routeColors, nil, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetAddressHistory gets the transactions that credited or debited the given address. The results can be filtered
+// using AddressHistoryOptions.
+func (api *GoShimmerAPI) GetAddressHistory(base58EncodedAddress string, options ...AddressHistoryOption) (*webapi_value.AddressHistoryResponse, error) {
+	res := &webapi_value.AddressHistoryResponse{}
+	if err := api.do(http.MethodGet, addressHistoryRoute(routeAddressHistory, base58EncodedAddress, options...), nil, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetAddressBalanceHistory gets the balances of the given address after each of the transactions that credited or
+// debited it. The results can be filtered using AddressHistoryOptions.
+func (api *GoShimmerAPI) GetAddressBalanceHistory(base58EncodedAddress string, options ...AddressHistoryOption) (*webapi_value.AddressBalanceHistoryResponse, error) {
+	res := &webapi_value.AddressBalanceHistoryResponse{}
+	if err := api.do(http.MethodGet, addressHistoryRoute(routeAddressBalanceHistory, base58EncodedAddress, options...), nil, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// AddressHistoryOption is a function that adds a filter or pagination parameter to an address history request.
+type AddressHistoryOption func(query url.Values)
+
+// AddressHistoryColor only returns the transactions that moved tokens of the given color.
+func AddressHistoryColor(base58EncodedColor string) AddressHistoryOption {
+	return func(query url.Values) {
+		query.Set("color", base58EncodedColor)
+	}
+}
+
+// AddressHistoryTimeRange only returns the transactions whose timestamp lies within the given time range.
+func AddressHistoryTimeRange(from time.Time, to time.Time) AddressHistoryOption {
+	return func(query url.Values) {
+		query.Set("from", strconv.FormatInt(from.Unix(), 10))
+		query.Set("to", strconv.FormatInt(to.Unix(), 10))
+	}
+}
+
+// AddressHistoryPage returns up to limit entries of the (filtered) history that follow the given cursor. The cursor of
+// the next page is returned by the previous request (an empty cursor returns the first page).
+func AddressHistoryPage(cursor string, limit int) AddressHistoryOption {
+	return func(query url.Values) {
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		query.Set("limit", strconv.Itoa(limit))
+	}
+}
+
+// addressHistoryRoute builds the route of an address history request.
+func addressHistoryRoute(route string, base58EncodedAddress string, options ...AddressHistoryOption) string {
+	query := url.Values{}
+	query.Set("address", base58EncodedAddress)
+	for _, option := range options {
+		option(query)
+	}
+
+	return fmt.Sprintf("%s?%s", route, query.Encode())
+}
+
 // GetUnspentOutputs return unspent output IDs of addresses
 func (api *GoShimmerAPI) GetUnspentOutputs(addresses []string) (*webapi_value.UnspentOutputsResponse, error) {
 	res := &webapi_value.UnspentOutputsResponse{}
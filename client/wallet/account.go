@@ -0,0 +1,425 @@
+package wallet
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/iotaledger/goshimmer/client/wallet/packages/address"
+	"github.com/iotaledger/goshimmer/client/wallet/packages/seed"
+	"github.com/iotaledger/hive.go/bitmask"
+	"github.com/iotaledger/hive.go/marshalutil"
+	"github.com/iotaledger/hive.go/typeutils"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// DefaultAccountName is the name of the account that every wallet has. Its receive chain contains the addresses
+	// of wallets that were created before accounts were introduced.
+	DefaultAccountName = "default"
+
+	// DefaultGapLimit defines how many consecutive unused addresses end the discovery of a chain of addresses.
+	DefaultGapLimit = 20
+
+	// maxChainPosition defines the highest position of an address in a chain.
+	maxChainPosition = 1<<32 - 1
+
+	// chainShift and accountShift define where the chain and the account are encoded in the derivation index of a
+	// key (the derivation path account/chain/position is packed into a single uint64).
+	chainShift   = 32
+	accountShift = 33
+)
+
+var (
+	// ErrAccountExists is returned when trying to create an account with a name that is used already.
+	ErrAccountExists = xerrors.New("account exists already")
+
+	// ErrAccountNotFound is returned when referencing an account that does not exist.
+	ErrAccountNotFound = xerrors.New("account not found")
+)
+
+// region Chain ////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Chain represents one of the address chains of an account.
+type Chain uint8
+
+const (
+	// ReceiveChain is the chain of addresses that are handed out to receive funds.
+	ReceiveChain Chain = iota
+
+	// ChangeChain is the chain of addresses that receive the remainders of the transfers of the account.
+	ChangeChain
+)
+
+// String returns a human readable version of the Chain.
+func (c Chain) String() string {
+	if c == ChangeChain {
+		return "change"
+	}
+
+	return "receive"
+}
+
+// DerivationIndex returns the index of the key of the address at the given position of the given chain of an account.
+func DerivationIndex(account uint32, chain Chain, position uint64) uint64 {
+	return uint64(account)<<accountShift | uint64(chain)<<chainShift | position
+}
+
+// DerivationPath returns the account, the chain and the position in the chain that the given derivation index
+// encodes.
+func DerivationPath(derivationIndex uint64) (account uint32, chain Chain, position uint64) {
+	return uint32(derivationIndex >> accountShift), Chain(derivationIndex >> chainShift & 1), derivationIndex & maxChainPosition
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region Account //////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Account is a named set of addresses of a wallet that has independent receive and change chains.
+type Account struct {
+	name             string
+	index            uint32
+	receiveAddresses *AddressManager
+	changeAddresses  *AddressManager
+}
+
+// newAccount creates an Account that does not have any spent addresses, yet.
+func newAccount(seed *seed.Seed, name string, index uint32) *Account {
+	return &Account{
+		name:             name,
+		index:            index,
+		receiveAddresses: newAddressManager(seed, DerivationIndex(index, ReceiveChain, 0), 0, []bitmask.BitMask{}),
+		changeAddresses:  newAddressManager(seed, DerivationIndex(index, ChangeChain, 0), 0, []bitmask.BitMask{}),
+	}
+}
+
+// accountFromMarshalUtil restores an Account from its marshaled state.
+func accountFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil, seed *seed.Seed) (account *Account, err error) {
+	account = &Account{}
+
+	nameLength, err := marshalUtil.ReadUint16()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse length of account name: %w", err)
+	}
+	nameBytes, err := marshalUtil.ReadBytes(int(nameLength))
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse account name: %w", err)
+	}
+	account.name = string(nameBytes)
+
+	if account.index, err = marshalUtil.ReadUint32(); err != nil {
+		return nil, xerrors.Errorf("failed to parse account index: %w", err)
+	}
+	if account.receiveAddresses, err = addressManagerFromMarshalUtil(marshalUtil, seed, DerivationIndex(account.index, ReceiveChain, 0)); err != nil {
+		return nil, xerrors.Errorf("failed to parse receive chain of account '%s': %w", account.name, err)
+	}
+	if account.changeAddresses, err = addressManagerFromMarshalUtil(marshalUtil, seed, DerivationIndex(account.index, ChangeChain, 0)); err != nil {
+		return nil, xerrors.Errorf("failed to parse change chain of account '%s': %w", account.name, err)
+	}
+
+	return
+}
+
+// Name returns the name of the Account.
+func (a *Account) Name() string {
+	return a.name
+}
+
+// Index returns the index of the Account that is used to derive its keys.
+func (a *Account) Index() uint32 {
+	return a.index
+}
+
+// ReceiveAddress returns the last receive address of the Account.
+func (a *Account) ReceiveAddress() address.Address {
+	return a.receiveAddresses.LastUnspentAddress()
+}
+
+// NewReceiveAddress generates and returns a new unused receive address of the Account.
+func (a *Account) NewReceiveAddress() address.Address {
+	return a.receiveAddresses.NewAddress()
+}
+
+// ChangeAddress returns the address of the Account that receives the remainders of its transfers.
+func (a *Account) ChangeAddress() address.Address {
+	return a.changeAddresses.FirstUnspentAddress()
+}
+
+// AddressManager returns the AddressManager of the given chain of the Account.
+func (a *Account) AddressManager(chain Chain) *AddressManager {
+	if chain == ChangeChain {
+		return a.changeAddresses
+	}
+
+	return a.receiveAddresses
+}
+
+// Addresses returns all addresses of both chains of the Account.
+func (a *Account) Addresses() []address.Address {
+	return append(a.receiveAddresses.Addresses(), a.changeAddresses.Addresses()...)
+}
+
+// UnspentAddresses returns the unspent addresses of both chains of the Account.
+func (a *Account) UnspentAddresses() []address.Address {
+	return append(a.receiveAddresses.UnspentAddresses(), a.changeAddresses.UnspentAddresses()...)
+}
+
+// SpentAddresses returns the spent addresses of both chains of the Account.
+func (a *Account) SpentAddresses() []address.Address {
+	return append(a.receiveAddresses.SpentAddresses(), a.changeAddresses.SpentAddresses()...)
+}
+
+// IsAddressSpent returns true if the given address of the Account was spent already.
+func (a *Account) IsAddressSpent(addr address.Address) bool {
+	addressManager := a.addressManager(addr)
+	position, _ := addressManager.Position(addr)
+
+	return addressManager.IsAddressSpent(position)
+}
+
+// MarkAddressSpent marks the given address of the Account as spent.
+func (a *Account) MarkAddressSpent(addr address.Address) {
+	addressManager := a.addressManager(addr)
+	position, _ := addressManager.Position(addr)
+
+	addressManager.MarkAddressSpent(position)
+}
+
+// Bytes returns a marshaled version of the state of the Account.
+func (a *Account) Bytes() []byte {
+	nameBytes := typeutils.StringToBytes(a.name)
+
+	return marshalutil.New().
+		WriteUint16(uint16(len(nameBytes))).
+		WriteBytes(nameBytes).
+		WriteUint32(a.index).
+		WriteBytes(a.receiveAddresses.Bytes()).
+		WriteBytes(a.changeAddresses.Bytes()).
+		Bytes()
+}
+
+// addressManager returns the AddressManager of the chain that the given address belongs to.
+func (a *Account) addressManager(addr address.Address) *AddressManager {
+	_, chain, _ := DerivationPath(addr.Index)
+
+	return a.AddressManager(chain)
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region AccountManager ///////////////////////////////////////////////////////////////////////////////////////////////
+
+// AccountManager manages the accounts that are derived from the seed of a wallet.
+type AccountManager struct {
+	seed     *seed.Seed
+	accounts map[uint32]*Account
+}
+
+// NewAccountManager creates an AccountManager for the given seed that contains the default account.
+func NewAccountManager(seed *seed.Seed) (accountManager *AccountManager) {
+	accountManager = &AccountManager{
+		seed:     seed,
+		accounts: make(map[uint32]*Account),
+	}
+	accountManager.accounts[0] = newAccount(seed, DefaultAccountName, 0)
+
+	return
+}
+
+// newLegacyAccountManager creates an AccountManager from the state of a wallet that was created before accounts were
+// introduced. The addresses of such a wallet form the receive chain of the default account.
+func newLegacyAccountManager(seed *seed.Seed, lastAddressIndex uint64, spentAddresses []bitmask.BitMask) (accountManager *AccountManager) {
+	accountManager = NewAccountManager(seed)
+	accountManager.accounts[0].receiveAddresses = NewAddressManager(seed, lastAddressIndex, spentAddresses)
+
+	return
+}
+
+// accountManagerFromMarshalUtil restores an AccountManager from its marshaled state.
+func accountManagerFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil, seed *seed.Seed) (accountManager *AccountManager, err error) {
+	accountManager = &AccountManager{
+		seed:     seed,
+		accounts: make(map[uint32]*Account),
+	}
+
+	accountCount, err := marshalUtil.ReadUint32()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse account count: %w", err)
+	}
+	for i := uint32(0); i < accountCount; i++ {
+		account, accountErr := accountFromMarshalUtil(marshalUtil, seed)
+		if accountErr != nil {
+			return nil, xerrors.Errorf("failed to parse account: %w", accountErr)
+		}
+		accountManager.accounts[account.index] = account
+	}
+	if _, defaultAccountExists := accountManager.accounts[0]; !defaultAccountExists {
+		return nil, xerrors.Errorf("failed to find default account: %w", ErrAccountNotFound)
+	}
+
+	return
+}
+
+// Seed returns the seed that the accounts are derived from.
+func (a *AccountManager) Seed() *seed.Seed {
+	return a.seed
+}
+
+// DefaultAccount returns the account that is used if no other account is specified.
+func (a *AccountManager) DefaultAccount() *Account {
+	return a.accounts[0]
+}
+
+// Account returns the account with the given name (or nil if it does not exist).
+func (a *AccountManager) Account(name string) *Account {
+	for _, account := range a.accounts {
+		if account.name == name {
+			return account
+		}
+	}
+
+	return nil
+}
+
+// Accounts returns all accounts ordered by their index.
+func (a *AccountManager) Accounts() (accounts []*Account) {
+	accounts = make([]*Account, 0, len(a.accounts))
+	for _, account := range a.accounts {
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].index < accounts[j].index
+	})
+
+	return
+}
+
+// CreateAccount creates a new account with the given name that uses the next unused account index.
+func (a *AccountManager) CreateAccount(name string) (account *Account, err error) {
+	if a.Account(name) != nil {
+		return nil, xerrors.Errorf("failed to create account '%s': %w", name, ErrAccountExists)
+	}
+
+	index := uint32(0)
+	for _, existingAccount := range a.accounts {
+		if existingAccount.index >= index {
+			index = existingAccount.index + 1
+		}
+	}
+	account = newAccount(a.seed, name, index)
+	a.accounts[index] = account
+
+	return
+}
+
+// AddressAccount returns the account that the given address belongs to (or nil if the account is not known).
+func (a *AccountManager) AddressAccount(addr address.Address) *Account {
+	accountIndex, _, _ := DerivationPath(addr.Index)
+
+	return a.accounts[accountIndex]
+}
+
+// Addresses returns the addresses of all accounts.
+func (a *AccountManager) Addresses() (addresses []address.Address) {
+	for _, account := range a.Accounts() {
+		addresses = append(addresses, account.Addresses()...)
+	}
+
+	return
+}
+
+// UnspentAddresses returns the unspent addresses of all accounts.
+func (a *AccountManager) UnspentAddresses() (addresses []address.Address) {
+	for _, account := range a.Accounts() {
+		addresses = append(addresses, account.UnspentAddresses()...)
+	}
+
+	return
+}
+
+// MarkAddressSpent marks the given address as spent in the account that it belongs to.
+func (a *AccountManager) MarkAddressSpent(addr address.Address) {
+	if account := a.AddressAccount(addr); account != nil {
+		account.MarkAddressSpent(addr)
+	}
+}
+
+// Discover scans the chains of the known accounts and of the following account indexes for addresses that were used
+// before (i.e. after restoring a wallet from its seed). The scan of a chain stops after gapLimit consecutive unused
+// addresses and the scan of new accounts stops at the first account without any used addresses.
+func (a *AccountManager) Discover(connector Connector, gapLimit int) (err error) {
+	if gapLimit <= 0 {
+		gapLimit = DefaultGapLimit
+	}
+
+	for accountIndex := uint32(0); ; accountIndex++ {
+		account, accountExists := a.accounts[accountIndex]
+		if !accountExists {
+			account = newAccount(a.seed, "", accountIndex)
+		}
+
+		accountUsed := false
+		for _, chain := range []Chain{ReceiveChain, ChangeChain} {
+			chainUsed, discoverErr := discoverChain(account.AddressManager(chain), connector, gapLimit)
+			if discoverErr != nil {
+				return xerrors.Errorf("failed to discover %s chain of account %d: %w", chain, accountIndex, discoverErr)
+			}
+			accountUsed = accountUsed || chainUsed
+		}
+
+		if !accountExists {
+			if !accountUsed {
+				return
+			}
+
+			account.name = discoveredAccountName(accountIndex)
+			a.accounts[accountIndex] = account
+		}
+	}
+}
+
+// Bytes returns a marshaled version of the state of the AccountManager (without the seed).
+func (a *AccountManager) Bytes() []byte {
+	marshalUtil := marshalutil.New().WriteUint32(uint32(len(a.accounts)))
+	for _, account := range a.Accounts() {
+		marshalUtil.WriteBytes(account.Bytes())
+	}
+
+	return marshalUtil.Bytes()
+}
+
+// discoverChain scans the chain of the given AddressManager in batches of gapLimit addresses until it finds gapLimit
+// consecutive unused addresses. It returns true if any used address was found.
+func discoverChain(addressManager *AddressManager, connector Connector, gapLimit int) (used bool, err error) {
+	lastUsedPosition := -1
+	for batchStart := 0; batchStart <= lastUsedPosition+gapLimit; batchStart += gapLimit {
+		batch := make([]address.Address, gapLimit)
+		for i := range batch {
+			batch[i] = addressManager.seed.Address(addressManager.derivationPrefix + uint64(batchStart+i))
+		}
+
+		usedAddresses, usedErr := connector.UsedAddresses(batch...)
+		if usedErr != nil {
+			return false, usedErr
+		}
+		for i, addr := range batch {
+			if usedAddresses[addr] {
+				lastUsedPosition = batchStart + i
+			}
+		}
+	}
+
+	if lastUsedPosition >= 0 {
+		// generate the addresses up to the last used one, so they are scanned for funds
+		addressManager.Address(uint64(lastUsedPosition))
+		used = true
+	}
+
+	return
+}
+
+// discoveredAccountName returns the name of an account that was found during the discovery of the accounts of a seed.
+func discoveredAccountName(accountIndex uint32) string {
+	return "account-" + strconv.FormatUint(uint64(accountIndex), 10)
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
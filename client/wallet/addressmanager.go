@@ -2,16 +2,23 @@ package wallet
 
 import (
 	"runtime"
+	"unsafe"
 
 	"github.com/iotaledger/goshimmer/client/wallet/packages/address"
 	"github.com/iotaledger/goshimmer/client/wallet/packages/seed"
 	"github.com/iotaledger/hive.go/bitmask"
+	"github.com/iotaledger/hive.go/marshalutil"
+	"golang.org/x/xerrors"
 )
 
-// AddressManager is an manager struct that allows us to keep track of the used and spent addresses.
+// AddressManager is an manager struct that allows us to keep track of the used and spent addresses of a chain of
+// addresses. The indexes that are used by its methods are the positions of the addresses in the chain, while the Index
+// of the returned Addresses is the derivation index of their keys (the position offset by the derivation prefix of the
+// chain).
 type AddressManager struct {
 	// state of the wallet
 	seed             *seed.Seed
+	derivationPrefix uint64
 	lastAddressIndex uint64
 	spentAddresses   []bitmask.BitMask
 
@@ -20,12 +27,20 @@ type AddressManager struct {
 	lastUnspentAddressIndex  uint64
 }
 
-// NewAddressManager is the constructor for the AddressManager type.
+// NewAddressManager is the constructor for the AddressManager type. It manages the addresses that are derived from the
+// seed without a derivation prefix (the receive chain of the default account).
 func NewAddressManager(seed *seed.Seed, lastAddressIndex uint64, spentAddresses []bitmask.BitMask) (addressManager *AddressManager) {
+	return newAddressManager(seed, 0, lastAddressIndex, spentAddresses)
+}
+
+// newAddressManager creates an AddressManager for the chain of addresses whose derivation indexes start at the given
+// prefix.
+func newAddressManager(seed *seed.Seed, derivationPrefix uint64, lastAddressIndex uint64, spentAddresses []bitmask.BitMask) (addressManager *AddressManager) {
 	defer runtime.KeepAlive(spentAddresses)
 
 	addressManager = &AddressManager{
 		seed:             seed,
+		derivationPrefix: derivationPrefix,
 		lastAddressIndex: lastAddressIndex,
 		spentAddresses:   spentAddresses,
 	}
@@ -40,7 +55,57 @@ func (addressManager *AddressManager) Address(addressIndex uint64) address.Addre
 	// update lastUnspentAddressIndex if necessary
 	addressManager.spentAddressIndexes(addressIndex)
 
-	return addressManager.seed.Address(addressIndex)
+	return addressManager.seed.Address(addressManager.derivationPrefix + addressIndex)
+}
+
+// Position returns the position of the given Address in the chain of the AddressManager and a flag that indicates if
+// the Address belongs to the chain at all.
+func (addressManager *AddressManager) Position(addr address.Address) (position uint64, exists bool) {
+	if addr.Index < addressManager.derivationPrefix || addr.Index-addressManager.derivationPrefix > maxChainPosition {
+		return 0, false
+	}
+
+	return addr.Index - addressManager.derivationPrefix, true
+}
+
+// LastAddressIndex returns the position of the last address that was generated in the chain.
+func (addressManager *AddressManager) LastAddressIndex() uint64 {
+	return addressManager.lastAddressIndex
+}
+
+// Bytes returns a marshaled version of the state of the AddressManager (without the seed).
+func (addressManager *AddressManager) Bytes() []byte {
+	spentAddressesBytes := *(*[]byte)(unsafe.Pointer(&addressManager.spentAddresses))
+
+	return marshalutil.New().
+		WriteUint64(addressManager.lastAddressIndex).
+		WriteUint32(uint32(len(spentAddressesBytes))).
+		WriteBytes(spentAddressesBytes).
+		Bytes()
+}
+
+// addressManagerFromMarshalUtil restores the AddressManager of the chain with the given derivation prefix from its
+// marshaled state.
+func addressManagerFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil, seed *seed.Seed, derivationPrefix uint64) (addressManager *AddressManager, err error) {
+	lastAddressIndex, err := marshalUtil.ReadUint64()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse last address index: %w", err)
+	}
+	spentAddressesLength, err := marshalUtil.ReadUint32()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse length of spent addresses: %w", err)
+	}
+	spentAddressesBytes, err := marshalUtil.ReadBytes(int(spentAddressesLength))
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse spent addresses: %w", err)
+	}
+	// copy the bitmasks, so growing them does not overwrite the bytes that follow in the buffer
+	spentAddresses := make([]bitmask.BitMask, len(spentAddressesBytes))
+	for i, spentAddressesByte := range spentAddressesBytes {
+		spentAddresses[i] = bitmask.BitMask(spentAddressesByte)
+	}
+
+	return newAddressManager(seed, derivationPrefix, lastAddressIndex, spentAddresses), nil
 }
 
 // Addresses returns a list of all addresses of the wallet.
@@ -18,9 +18,16 @@ type Asset struct {
 	// Precision defines how many decimal places are shown when showing this asset in wallets
 	Precision int
 
+	// URL links to further information about the asset (optional, only published to the network)
+	URL string
+
 	// Address defines the target address where the asset is supposed to be created
 	Address ledgerstate.Address
 
 	// the amount of tokens that we want to create
 	Amount uint64
+
+	// MintingTransactionID contains the identifier of the transaction that created the asset (only set for assets that
+	// were loaded from the ledger)
+	MintingTransactionID ledgerstate.TransactionID
 }
@@ -98,9 +98,34 @@ func (assetRegistry *AssetRegistry) RegisterAsset(color ledgerstate.Color, asset
 	assetRegistry.assets[color] = asset
 }
 
+// Populate loads the assets of the given colors that are not registered, yet, from the ledger using the given
+// Connector. Assets without a name are loaded again, so metadata that was published later on is picked up.
+func (assetRegistry *AssetRegistry) Populate(connector Connector, colors ...ledgerstate.Color) (err error) {
+	for _, color := range colors {
+		if asset, assetExists := assetRegistry.assets[color]; (assetExists && asset.Name != "") || color == ledgerstate.ColorIOTA {
+			continue
+		}
+
+		asset, assetErr := connector.Asset(color)
+		if assetErr != nil {
+			return assetErr
+		}
+		assetRegistry.RegisterAsset(color, asset)
+	}
+
+	return
+}
+
+// Asset returns the registered asset of the given color.
+func (assetRegistry *AssetRegistry) Asset(color ledgerstate.Color) (asset Asset, assetExists bool) {
+	asset, assetExists = assetRegistry.assets[color]
+
+	return
+}
+
 // Name returns the name of the given asset.
 func (assetRegistry *AssetRegistry) Name(color ledgerstate.Color) string {
-	if asset, assetExists := assetRegistry.assets[color]; assetExists {
+	if asset, assetExists := assetRegistry.assets[color]; assetExists && asset.Name != "" {
 		return asset.Name
 	}
 
@@ -113,7 +138,7 @@ func (assetRegistry *AssetRegistry) Name(color ledgerstate.Color) string {
 
 // Symbol return the symbol of the token.
 func (assetRegistry *AssetRegistry) Symbol(color ledgerstate.Color) string {
-	if asset, assetExists := assetRegistry.assets[color]; assetExists {
+	if asset, assetExists := assetRegistry.assets[color]; assetExists && asset.Symbol != "" {
 		return asset.Symbol
 	}
 
@@ -2,13 +2,41 @@ package wallet
 
 import (
 	"github.com/iotaledger/goshimmer/client/wallet/packages/address"
+	"github.com/iotaledger/goshimmer/packages/assetregistry"
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"golang.org/x/xerrors"
 )
 
 // Connector represents an interface that defines how the wallet interacts with the network. A wallet can either be used
 // locally on a server or it can connect remotely using the web API.
 type Connector interface {
 	UnspentOutputs(addresses ...address.Address) (unspentOutputs map[address.Address]map[ledgerstate.OutputID]*Output, err error)
+	UsedAddresses(addresses ...address.Address) (usedAddresses map[address.Address]bool, err error)
 	SendTransaction(transaction *ledgerstate.Transaction) (err error)
+	TransactionInclusionState(transactionID ledgerstate.TransactionID) (inclusionState InclusionState, conflictingTransactionIDs []ledgerstate.TransactionID, err error)
 	RequestFaucetFunds(address address.Address) (err error)
+	Asset(color ledgerstate.Color) (asset Asset, err error)
+	PublishAssetMetadata(assetMetadata *assetregistry.AssetMetadata) (err error)
+}
+
+var (
+	// ErrTransactionNotFound is returned if the network does not know a Transaction (i.e. because its Message was lost).
+	ErrTransactionNotFound = xerrors.New("transaction not found")
+
+	// ErrTransactionRejected is returned if a Transaction that the wallet waits for is rejected.
+	ErrTransactionRejected = xerrors.New("transaction rejected")
+
+	// ErrConfirmationNotSupported is returned if the Connector can not notify the wallet about confirmed Transactions.
+	ErrConfirmationNotSupported = xerrors.New("waiting for confirmations is not supported by the connector")
+)
+
+// ConfirmationAwaiter is an optional interface of Connectors that get notified by the network about the confirmation of
+// Transactions, so the wallet does not need to poll for them.
+type ConfirmationAwaiter interface {
+	// AwaitTransactionConfirmation blocks until the Transaction with the given TransactionID is confirmed or rejected.
+	AwaitTransactionConfirmation(transactionID ledgerstate.TransactionID) (err error)
+
+	// AwaitAddressConfirmation calls the trigger and blocks until a Transaction that touches the given address is
+	// confirmed or rejected. Calling the trigger after starting to listen ensures that no confirmation is missed.
+	AwaitAddressConfirmation(addr address.Address, trigger func() error) (err error)
 }
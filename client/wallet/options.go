@@ -1,6 +1,8 @@
 package wallet
 
 import (
+	"time"
+
 	"github.com/iotaledger/goshimmer/client"
 	"github.com/iotaledger/goshimmer/client/wallet/packages/seed"
 	"github.com/iotaledger/hive.go/bitmask"
@@ -16,14 +18,40 @@ func WebAPI(baseURL string, setters ...client.Option) Option {
 	}
 }
 
-// Import restores a wallet that has previously been created.
+// Import restores a wallet that has previously been created. The given addresses form the receive chain of the default
+// account.
 func Import(seed *seed.Seed, lastAddressIndex uint64, spentAddresses []bitmask.BitMask, assetRegistry *AssetRegistry) Option {
 	return func(wallet *Wallet) {
-		wallet.addressManager = NewAddressManager(seed, lastAddressIndex, spentAddresses)
+		wallet.accountManager = newLegacyAccountManager(seed, lastAddressIndex, spentAddresses)
 		wallet.assetRegistry = assetRegistry
 	}
 }
 
+// ImportAccounts restores a wallet with the given accounts (see ParseState).
+func ImportAccounts(accountManager *AccountManager, assetRegistry *AssetRegistry) Option {
+	return func(wallet *Wallet) {
+		wallet.accountManager = accountManager
+		wallet.assetRegistry = assetRegistry
+	}
+}
+
+// PendingTransactions restores the transactions that were issued by a previous instance of the wallet and that were not
+// confirmed, yet.
+func PendingTransactions(pendingTransactionManager *PendingTransactionManager) Option {
+	return func(wallet *Wallet) {
+		wallet.pendingTransactionManager = pendingTransactionManager
+	}
+}
+
+// Reattachment configures how long the wallet waits for the inclusion or confirmation of a transaction before it is
+// attached to the Tangle again and how often this happens before the wallet gives up.
+func Reattachment(timeout time.Duration, maxReattachments int) Option {
+	return func(wallet *Wallet) {
+		wallet.reattachmentTimeout = timeout
+		wallet.maxReattachments = maxReattachments
+	}
+}
+
 // ReusableAddress configures the wallet to run in "single address" mode where all the funds are always managed on a
 // single reusable address.
 func ReusableAddress(enabled bool) Option {
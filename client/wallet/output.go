@@ -1,11 +1,18 @@
 package wallet
 
 import (
+	"bytes"
+	"time"
+
 	"github.com/iotaledger/goshimmer/client/wallet/packages/address"
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
 	"github.com/iotaledger/hive.go/stringify"
+	"golang.org/x/xerrors"
 )
 
+// ErrOutputNotUnlockable is returned if an Output can not be unlocked by the wallet Address it is associated to.
+var ErrOutputNotUnlockable = xerrors.New("output can not be unlocked by the wallet")
+
 // region Output ///////////////////////////////////////////////////////////////////////////////////////////////////////
 
 // Output is a wallet specific representation of an output in the IOTA network.
@@ -14,6 +21,55 @@ type Output struct {
 	OutputID       ledgerstate.OutputID
 	Balances       *ledgerstate.ColoredBalances
 	InclusionState InclusionState
+
+	// Object contains the output as it is stored in the ledger (it is nil for outputs that are unlocked by a signature
+	// of Address without further conditions).
+	Object ledgerstate.Output
+}
+
+// Type returns the OutputType of the Output.
+func (o *Output) Type() ledgerstate.OutputType {
+	if o.Object == nil {
+		return ledgerstate.SigLockedColoredOutputType
+	}
+
+	return o.Object.Type()
+}
+
+// TimeLockedNow returns true if the Output can not be spent at the given time because of a timelock.
+func (o *Output) TimeLockedNow(now time.Time) bool {
+	extendedLockedOutput, isExtendedLockedOutput := o.Object.(*ledgerstate.ExtendedLockedOutput)
+
+	return isExtendedLockedOutput && extendedLockedOutput.TimeLockedNow(now)
+}
+
+// UnlockAddressNow returns the Address that is able to unlock the Output at the given time.
+func (o *Output) UnlockAddressNow(now time.Time) ledgerstate.Address {
+	if extendedLockedOutput, isExtendedLockedOutput := o.Object.(*ledgerstate.ExtendedLockedOutput); isExtendedLockedOutput {
+		return extendedLockedOutput.UnlockAddressNow(now)
+	}
+
+	return o.Address.Address()
+}
+
+// UnlockableNow returns true if the Output can be unlocked by a signature of its wallet Address at the given time.
+func (o *Output) UnlockableNow(now time.Time) bool {
+	return bytes.Equal(o.UnlockAddressNow(now).Bytes(), o.Address.Address().Bytes())
+}
+
+// SpendableNow returns true if the Output can be unlocked by its wallet Address and is not timelocked at the given
+// time.
+func (o *Output) SpendableNow(now time.Time) bool {
+	return o.UnlockableNow(now) && !o.TimeLockedNow(now)
+}
+
+// LedgerOutput returns the ledger representation of the Output.
+func (o *Output) LedgerOutput() ledgerstate.Output {
+	if o.Object != nil {
+		return o.Object
+	}
+
+	return ledgerstate.NewSigLockedColoredOutput(o.Balances, o.Address.Address()).SetID(o.OutputID)
 }
 
 // String returns a human-readable representation of the Output.
@@ -23,6 +79,7 @@ func (o *Output) String() string {
 		stringify.StructField("OutputID", o.OutputID),
 		stringify.StructField("Balances", o.Balances),
 		stringify.StructField("InclusionState", o.InclusionState),
+		stringify.StructField("Object", o.Object),
 	)
 }
 
@@ -0,0 +1,473 @@
+package wallet
+
+import (
+	"bytes"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/hive.go/marshalutil"
+	"golang.org/x/xerrors"
+)
+
+// PartiallySignedTransaction is a serializable container for a Transaction that still needs to be signed. Besides the
+// TransactionEssence it carries the consumed Outputs (so that signers can inspect the spent funds without being online)
+// and the signatures that were collected for each Input so far. It allows to sign Transactions on an offline machine
+// and to merge the signatures of several parties before the Transaction is submitted.
+//
+// Inputs that are unlocked by the same Address reference the UnlockBlock of the first of these Inputs, Inputs that are
+// owned by an alias are unlocked by the Input that consumes the corresponding AliasOutput and Inputs of a
+// ThresholdAddress collect the signatures of the co-signers in a ThresholdSignatureUnlockBlock.
+type PartiallySignedTransaction struct {
+	essence         *ledgerstate.TransactionEssence
+	consumedOutputs ledgerstate.Outputs
+	unlockBlocks    ledgerstate.UnlockBlocks
+	signerIndexes   []int
+}
+
+// NewPartiallySignedTransaction creates a new (unsigned) PartiallySignedTransaction from the given TransactionEssence
+// and the Outputs that are consumed by its Inputs.
+func NewPartiallySignedTransaction(essence *ledgerstate.TransactionEssence, consumedOutputs ledgerstate.Outputs) (partiallySignedTransaction *PartiallySignedTransaction, err error) {
+	outputsByID := consumedOutputs.ByID()
+
+	orderedOutputs := make(ledgerstate.Outputs, len(essence.Inputs()))
+	for i, input := range essence.Inputs() {
+		utxoInput, ok := input.(*ledgerstate.UTXOInput)
+		if !ok {
+			err = xerrors.Errorf("unsupported InputType (%s) at index %d", input.Type(), i)
+			return
+		}
+
+		output, outputExists := outputsByID[utxoInput.ReferencedOutputID()]
+		if !outputExists {
+			err = xerrors.Errorf("consumed Output of Input at index %d is missing", i)
+			return
+		}
+		if extendedLockedOutput, isExtendedLockedOutput := output.(*ledgerstate.ExtendedLockedOutput); isExtendedLockedOutput && extendedLockedOutput.TimeLockedNow(essence.Timestamp()) {
+			err = xerrors.Errorf("consumed Output of Input at index %d is timelocked until %s: %w", i, extendedLockedOutput.TimeLock(), ErrOutputNotUnlockable)
+			return
+		}
+		orderedOutputs[i] = output
+	}
+
+	partiallySignedTransaction = &PartiallySignedTransaction{
+		essence:         essence,
+		consumedOutputs: orderedOutputs,
+		unlockBlocks:    make(ledgerstate.UnlockBlocks, len(orderedOutputs)),
+		signerIndexes:   make([]int, len(orderedOutputs)),
+	}
+	if err = partiallySignedTransaction.initUnlockBlocks(); err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// PartiallySignedTransactionFromBytes unmarshals a PartiallySignedTransaction from a sequence of bytes.
+func PartiallySignedTransactionFromBytes(bytes []byte) (partiallySignedTransaction *PartiallySignedTransaction, err error) {
+	marshalUtil := marshalutil.New(bytes)
+
+	essence, err := ledgerstate.TransactionEssenceFromMarshalUtil(marshalUtil)
+	if err != nil {
+		err = xerrors.Errorf("failed to parse TransactionEssence: %w", err)
+		return
+	}
+
+	consumedOutputs := make(ledgerstate.Outputs, len(essence.Inputs()))
+	unlockBlocks := make(ledgerstate.UnlockBlocks, len(essence.Inputs()))
+	for i, input := range essence.Inputs() {
+		utxoInput, ok := input.(*ledgerstate.UTXOInput)
+		if !ok {
+			err = xerrors.Errorf("unsupported InputType (%s) at index %d", input.Type(), i)
+			return
+		}
+
+		if consumedOutputs[i], err = ledgerstate.OutputFromMarshalUtil(marshalUtil); err != nil {
+			err = xerrors.Errorf("failed to parse consumed Output at index %d: %w", i, err)
+			return
+		}
+		consumedOutputs[i].SetID(utxoInput.ReferencedOutputID())
+
+		signed, signedErr := marshalUtil.ReadBool()
+		if signedErr != nil {
+			err = xerrors.Errorf("failed to parse signing status of Input at index %d: %w", i, signedErr)
+			return
+		}
+		if !signed {
+			continue
+		}
+		if unlockBlocks[i], err = partialUnlockBlockFromMarshalUtil(marshalUtil); err != nil {
+			err = xerrors.Errorf("failed to parse UnlockBlock of Input at index %d: %w", i, err)
+			return
+		}
+	}
+
+	if partiallySignedTransaction, err = NewPartiallySignedTransaction(essence, consumedOutputs); err != nil {
+		return
+	}
+	for i, unlockBlock := range unlockBlocks {
+		if unlockBlock == nil {
+			continue
+		}
+
+		if err = partiallySignedTransaction.mergeUnlockBlock(i, unlockBlock); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Essence returns the TransactionEssence that needs to be signed.
+func (p *PartiallySignedTransaction) Essence() *ledgerstate.TransactionEssence {
+	return p.essence
+}
+
+// ConsumedOutputs returns the Outputs that are consumed by the Inputs of the Transaction (in the order of the Inputs).
+func (p *PartiallySignedTransaction) ConsumedOutputs() ledgerstate.Outputs {
+	return p.consumedOutputs
+}
+
+// UnlockAddress returns the Address whose signature is required to unlock the Input at the given index. For
+// AliasOutputs this is either the state or the governing Address, depending on the transition of the alias. For
+// ExtendedLockedOutputs this is either the Address or the fallback Address, depending on the timestamp of the
+// TransactionEssence.
+func (p *PartiallySignedTransaction) UnlockAddress(inputIndex int) ledgerstate.Address {
+	if extendedLockedOutput, isExtendedLockedOutput := p.consumedOutputs[inputIndex].(*ledgerstate.ExtendedLockedOutput); isExtendedLockedOutput {
+		return extendedLockedOutput.UnlockAddressNow(p.essence.Timestamp())
+	}
+
+	aliasOutput, isAliasOutput := p.consumedOutputs[inputIndex].(*ledgerstate.AliasOutput)
+	if !isAliasOutput {
+		return p.consumedOutputs[inputIndex].Address()
+	}
+
+	for _, output := range p.essence.Outputs() {
+		chainedOutput, isChainedOutput := output.(*ledgerstate.AliasOutput)
+		if !isChainedOutput || chainedOutput.IsOrigin() || !chainedOutput.AliasAddress().Equals(aliasOutput.AliasAddress()) {
+			continue
+		}
+
+		if chainedOutput.StateIndex() == aliasOutput.StateIndex()+1 {
+			return aliasOutput.StateAddress()
+		}
+		break
+	}
+
+	return aliasOutput.GoverningAddress()
+}
+
+// Signed returns true if the Input at the given index has been signed already.
+func (p *PartiallySignedTransaction) Signed(inputIndex int) bool {
+	signerIndex := p.signerIndexes[inputIndex]
+
+	switch unlockBlock := p.unlockBlocks[signerIndex].(type) {
+	case *ledgerstate.SignatureUnlockBlock:
+		return true
+	case *ledgerstate.ThresholdSignatureUnlockBlock:
+		return unlockBlock.AddressSignatureValid(p.UnlockAddress(signerIndex), p.essence.Bytes())
+	default:
+		return false
+	}
+}
+
+// SignedInputs returns the amount of Inputs that have been signed already.
+func (p *PartiallySignedTransaction) SignedInputs() (signedInputs int) {
+	for i := range p.unlockBlocks {
+		if p.Signed(i) {
+			signedInputs++
+		}
+	}
+
+	return
+}
+
+// Complete returns true if all Inputs have been signed.
+func (p *PartiallySignedTransaction) Complete() bool {
+	return p.SignedInputs() == len(p.unlockBlocks)
+}
+
+// Sign adds the signature of the given KeyPair to all Inputs that are unlocked by its ED25519Address or by a
+// ThresholdAddress that it is a co-signer of and returns the amount of Inputs that were signed.
+func (p *PartiallySignedTransaction) Sign(keyPair *ed25519.KeyPair) (signedInputs int, err error) {
+	signerAddress := ledgerstate.NewED25519Address(keyPair.PublicKey)
+	signature := keyPair.PrivateKey.Sign(p.essence.Bytes())
+
+	signedSignerIndexes := make(map[int]bool)
+	for i := range p.unlockBlocks {
+		if p.signerIndexes[i] != i || p.Signed(i) {
+			continue
+		}
+
+		switch unlockAddress := p.UnlockAddress(i); unlockAddress.Type() {
+		case ledgerstate.ED25519AddressType:
+			if !bytes.Equal(unlockAddress.Bytes(), signerAddress.Bytes()) {
+				continue
+			}
+			if err = p.AddSignature(i, ledgerstate.NewED25519Signature(keyPair.PublicKey, signature)); err != nil {
+				return
+			}
+		case ledgerstate.ThresholdAddressType:
+			if !p.coSigner(i, keyPair.PublicKey) {
+				continue
+			}
+			if err = p.AddThresholdSignature(i, keyPair.PublicKey, signature); err != nil {
+				return
+			}
+		default:
+			continue
+		}
+		signedSignerIndexes[i] = true
+	}
+
+	for _, signerIndex := range p.signerIndexes {
+		if signedSignerIndexes[signerIndex] {
+			signedInputs++
+		}
+	}
+
+	return
+}
+
+// AddSignature adds the given Signature for the Input at the given index after verifying that it unlocks the consumed
+// Output. Inputs that are unlocked by another Input (through a reference or an alias) receive the Signature for the
+// Input that unlocks them.
+func (p *PartiallySignedTransaction) AddSignature(inputIndex int, signature ledgerstate.Signature) (err error) {
+	if inputIndex < 0 || inputIndex >= len(p.unlockBlocks) {
+		return xerrors.Errorf("Input index %d out of bounds", inputIndex)
+	}
+
+	signerIndex := p.signerIndexes[inputIndex]
+	unlockBlock := ledgerstate.NewSignatureUnlockBlock(signature)
+	if !unlockBlock.AddressSignatureValid(p.UnlockAddress(signerIndex), p.essence.Bytes()) {
+		return xerrors.Errorf("signature does not unlock the Input at index %d", inputIndex)
+	}
+	p.unlockBlocks[signerIndex] = unlockBlock
+
+	return
+}
+
+// SetThresholdPublicKeys reveals the threshold and the public keys of the ThresholdAddress that unlocks the Input at
+// the given index, which is required before the co-signers can add their signatures.
+func (p *PartiallySignedTransaction) SetThresholdPublicKeys(inputIndex int, threshold uint8, publicKeys ...ed25519.PublicKey) (err error) {
+	if inputIndex < 0 || inputIndex >= len(p.unlockBlocks) {
+		return xerrors.Errorf("Input index %d out of bounds", inputIndex)
+	}
+
+	signerIndex := p.signerIndexes[inputIndex]
+	if p.unlockBlocks[signerIndex] != nil {
+		return
+	}
+
+	unlockBlock, err := ledgerstate.NewThresholdSignatureUnlockBlock(threshold, publicKeys...)
+	if err != nil {
+		return xerrors.Errorf("failed to create ThresholdSignatureUnlockBlock: %w", err)
+	}
+	if !bytes.Equal(unlockBlock.Address().Bytes(), p.UnlockAddress(signerIndex).Bytes()) {
+		return xerrors.Errorf("public keys do not belong to the ThresholdAddress of the Input at index %d", inputIndex)
+	}
+	p.unlockBlocks[signerIndex] = unlockBlock
+
+	return
+}
+
+// AddThresholdSignature adds the signature of one of the co-signers of the ThresholdAddress that unlocks the Input at
+// the given index.
+func (p *PartiallySignedTransaction) AddThresholdSignature(inputIndex int, publicKey ed25519.PublicKey, signature ed25519.Signature) (err error) {
+	if inputIndex < 0 || inputIndex >= len(p.unlockBlocks) {
+		return xerrors.Errorf("Input index %d out of bounds", inputIndex)
+	}
+
+	unlockBlock, isThresholdUnlockBlock := p.unlockBlocks[p.signerIndexes[inputIndex]].(*ledgerstate.ThresholdSignatureUnlockBlock)
+	if !isThresholdUnlockBlock {
+		return xerrors.Errorf("public keys of the ThresholdAddress of the Input at index %d are unknown", inputIndex)
+	}
+	if !publicKey.VerifySignature(p.essence.Bytes(), signature) {
+		return xerrors.Errorf("signature does not unlock the Input at index %d", inputIndex)
+	}
+	if err = unlockBlock.AddSignature(publicKey, signature); err != nil {
+		return xerrors.Errorf("failed to add signature to the Input at index %d: %w", inputIndex, err)
+	}
+
+	return
+}
+
+// Combine merges the signatures of another PartiallySignedTransaction of the same TransactionEssence into this one.
+func (p *PartiallySignedTransaction) Combine(other *PartiallySignedTransaction) (err error) {
+	if !bytes.Equal(p.essence.Bytes(), other.essence.Bytes()) {
+		return xerrors.New("PartiallySignedTransactions have different TransactionEssences")
+	}
+
+	for i, unlockBlock := range other.unlockBlocks {
+		if other.signerIndexes[i] != i || unlockBlock == nil {
+			continue
+		}
+
+		if err = p.mergeUnlockBlock(i, unlockBlock); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Transaction returns the signed Transaction.
+func (p *PartiallySignedTransaction) Transaction() (transaction *ledgerstate.Transaction, err error) {
+	if !p.Complete() {
+		err = xerrors.Errorf("only %d of %d Inputs have been signed", p.SignedInputs(), len(p.unlockBlocks))
+		return
+	}
+
+	unlockBlocks := make(ledgerstate.UnlockBlocks, len(p.unlockBlocks))
+	copy(unlockBlocks, p.unlockBlocks)
+	transaction = ledgerstate.NewTransaction(p.essence, unlockBlocks)
+
+	return
+}
+
+// Bytes returns a marshaled version of the PartiallySignedTransaction.
+func (p *PartiallySignedTransaction) Bytes() []byte {
+	marshalUtil := marshalutil.New().WriteBytes(p.essence.Bytes())
+	for i, consumedOutput := range p.consumedOutputs {
+		signed := p.signerIndexes[i] == i && p.unlockBlocks[i] != nil
+
+		marshalUtil.WriteBytes(consumedOutput.Bytes()).WriteBool(signed)
+		if signed {
+			marshalUtil.WriteBytes(p.unlockBlocks[i].Bytes())
+		}
+	}
+
+	return marshalUtil.Bytes()
+}
+
+// initUnlockBlocks is an internal utility function that creates the ReferenceUnlockBlocks and AliasUnlockBlocks of the
+// Inputs that are unlocked by other Inputs and determines the Input that needs to be signed for every Input.
+func (p *PartiallySignedTransaction) initUnlockBlocks() (err error) {
+	signerIndexesByAddress := make(map[string]int)
+	for i := range p.consumedOutputs {
+		unlockAddress := p.UnlockAddress(i)
+		if aliasAddress, isAliasAddress := unlockAddress.(*ledgerstate.AliasAddress); isAliasAddress {
+			aliasInputIndex := p.aliasInputIndex(aliasAddress)
+			if aliasInputIndex == -1 {
+				return xerrors.Errorf("AliasOutput that unlocks the Input at index %d is not consumed", i)
+			}
+
+			p.unlockBlocks[i] = ledgerstate.NewAliasUnlockBlock(uint16(aliasInputIndex))
+			continue
+		}
+
+		addressKey := string(unlockAddress.Bytes())
+		if signerIndex, signerExists := signerIndexesByAddress[addressKey]; signerExists {
+			p.unlockBlocks[i] = ledgerstate.NewReferenceUnlockBlock(uint16(signerIndex))
+			continue
+		}
+		signerIndexesByAddress[addressKey] = i
+	}
+
+	for i := range p.signerIndexes {
+		signerIndex := i
+		for hops := 0; ; hops++ {
+			if hops > len(p.unlockBlocks) {
+				return xerrors.Errorf("AliasUnlockBlocks of the Input at index %d are cyclic", i)
+			}
+
+			switch unlockBlock := p.unlockBlocks[signerIndex].(type) {
+			case *ledgerstate.ReferenceUnlockBlock:
+				signerIndex = int(unlockBlock.ReferencedIndex())
+				continue
+			case *ledgerstate.AliasUnlockBlock:
+				signerIndex = int(unlockBlock.AliasInputIndex())
+				continue
+			}
+			break
+		}
+		p.signerIndexes[i] = signerIndex
+	}
+
+	return
+}
+
+// aliasInputIndex is an internal utility function that returns the index of the Input that consumes the AliasOutput
+// of the given AliasAddress (or -1 if the AliasOutput is not consumed).
+func (p *PartiallySignedTransaction) aliasInputIndex(aliasAddress *ledgerstate.AliasAddress) int {
+	for i, consumedOutput := range p.consumedOutputs {
+		if aliasOutput, isAliasOutput := consumedOutput.(*ledgerstate.AliasOutput); isAliasOutput && aliasOutput.AliasAddress().Equals(aliasAddress) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// coSigner is an internal utility function that returns true if the given public key is one of the co-signers of the
+// ThresholdAddress that unlocks the Input at the given index.
+func (p *PartiallySignedTransaction) coSigner(inputIndex int, publicKey ed25519.PublicKey) bool {
+	unlockBlock, isThresholdUnlockBlock := p.unlockBlocks[p.signerIndexes[inputIndex]].(*ledgerstate.ThresholdSignatureUnlockBlock)
+	if !isThresholdUnlockBlock {
+		return false
+	}
+
+	for _, coSignerPublicKey := range unlockBlock.PublicKeys() {
+		if coSignerPublicKey == publicKey {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mergeUnlockBlock is an internal utility function that adds the signatures of the given (partial) UnlockBlock to the
+// Input at the given index.
+func (p *PartiallySignedTransaction) mergeUnlockBlock(inputIndex int, unlockBlock ledgerstate.UnlockBlock) (err error) {
+	if inputIndex < 0 || inputIndex >= len(p.unlockBlocks) {
+		return xerrors.Errorf("Input index %d out of bounds", inputIndex)
+	}
+
+	switch typedUnlockBlock := unlockBlock.(type) {
+	case *ledgerstate.SignatureUnlockBlock:
+		if p.Signed(inputIndex) {
+			return
+		}
+
+		return p.AddSignature(inputIndex, typedUnlockBlock.Signature())
+	case *ledgerstate.ThresholdSignatureUnlockBlock:
+		if err = p.SetThresholdPublicKeys(inputIndex, typedUnlockBlock.Threshold(), typedUnlockBlock.PublicKeys()...); err != nil {
+			return
+		}
+
+		publicKeys := typedUnlockBlock.PublicKeys()
+		for publicKeyIndex, signature := range typedUnlockBlock.Signatures() {
+			if p.Signed(inputIndex) {
+				return
+			}
+
+			if err = p.AddThresholdSignature(inputIndex, publicKeys[publicKeyIndex], signature); err != nil {
+				return
+			}
+		}
+
+		return
+	default:
+		return xerrors.Errorf("unsupported UnlockBlockType (%s) for Input at index %d", unlockBlock.Type(), inputIndex)
+	}
+}
+
+// partialUnlockBlockFromMarshalUtil is an internal utility function that unmarshals the (partial) UnlockBlock of an
+// Input that is still being signed.
+func partialUnlockBlockFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (unlockBlock ledgerstate.UnlockBlock, err error) {
+	unlockBlockType, err := marshalUtil.ReadByte()
+	if err != nil {
+		err = xerrors.Errorf("failed to parse UnlockBlockType: %w", err)
+		return
+	}
+	marshalUtil.ReadSeek(-1)
+
+	switch ledgerstate.UnlockBlockType(unlockBlockType) {
+	case ledgerstate.SignatureUnlockBlockType:
+		return ledgerstate.SignatureUnlockBlockFromMarshalUtil(marshalUtil)
+	case ledgerstate.ThresholdSignatureUnlockBlockType:
+		return ledgerstate.PartialThresholdSignatureUnlockBlockFromMarshalUtil(marshalUtil)
+	default:
+		err = xerrors.Errorf("unsupported UnlockBlockType (%s)", ledgerstate.UnlockBlockType(unlockBlockType))
+		return
+	}
+}
@@ -0,0 +1,280 @@
+package wallet
+
+import (
+	"sort"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/marshalutil"
+	"github.com/iotaledger/hive.go/typeutils"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// DefaultReattachmentTimeout defines how long the wallet waits for a Transaction to be included (or confirmed)
+	// before it is attached to the Tangle in a new Message.
+	DefaultReattachmentTimeout = time.Minute
+
+	// DefaultMaxReattachments defines how often a Transaction is reattached before the wallet gives up.
+	DefaultMaxReattachments = 5
+)
+
+// region PendingTransactionState //////////////////////////////////////////////////////////////////////////////////////
+
+// PendingTransactionState represents the state of a Transaction that was issued by the wallet.
+type PendingTransactionState uint8
+
+const (
+	// PendingTransactionPending is the state of a Transaction that has not been decided, yet.
+	PendingTransactionPending PendingTransactionState = iota
+
+	// PendingTransactionConflicting is the state of a Transaction that is double spent by other Transactions and waits
+	// for the conflict to be resolved.
+	PendingTransactionConflicting
+
+	// PendingTransactionConfirmed is the state of a Transaction that was confirmed.
+	PendingTransactionConfirmed
+
+	// PendingTransactionRejected is the state of a Transaction that was rejected and could not be reissued.
+	PendingTransactionRejected
+
+	// PendingTransactionReissued is the state of a rejected or outdated Transaction whose transfer was rebuilt in a new
+	// Transaction.
+	PendingTransactionReissued
+
+	// PendingTransactionFailed is the state of a Transaction that was not included after the maximum amount of
+	// reattachments.
+	PendingTransactionFailed
+)
+
+// Final returns true if the PendingTransactionState will not change anymore.
+func (p PendingTransactionState) Final() bool {
+	return p >= PendingTransactionConfirmed
+}
+
+// String returns a human readable version of the PendingTransactionState.
+func (p PendingTransactionState) String() string {
+	switch p {
+	case PendingTransactionPending:
+		return "Pending"
+	case PendingTransactionConflicting:
+		return "Conflicting"
+	case PendingTransactionConfirmed:
+		return "Confirmed"
+	case PendingTransactionRejected:
+		return "Rejected"
+	case PendingTransactionReissued:
+		return "Reissued"
+	case PendingTransactionFailed:
+		return "Failed"
+	default:
+		return "Unknown"
+	}
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region PendingTransaction ///////////////////////////////////////////////////////////////////////////////////////////
+
+// PendingTransaction represents a Transaction that was issued by the wallet together with the information that is
+// required to reattach or rebuild it.
+type PendingTransaction struct {
+	Transaction               *ledgerstate.Transaction
+	State                     PendingTransactionState
+	ConflictingTransactionIDs []ledgerstate.TransactionID
+	IssuingTime               time.Time
+	LastAttachmentTime        time.Time
+	Reattachments             int
+	ReissuedTransactionID     ledgerstate.TransactionID
+	Error                     string
+
+	// sendFundsOptions contains the marshaled options of the transfer (it is empty if the Transaction can not be
+	// rebuilt i.e. because it was signed by several parties).
+	sendFundsOptions []byte
+}
+
+// pendingTransactionFromMarshalUtil unmarshals a PendingTransaction using a MarshalUtil (for easier unmarshaling).
+func pendingTransactionFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (pendingTransaction *PendingTransaction, err error) {
+	pendingTransaction = &PendingTransaction{}
+	if pendingTransaction.Transaction, err = ledgerstate.TransactionFromMarshalUtil(marshalUtil); err != nil {
+		return nil, xerrors.Errorf("failed to parse Transaction: %w", err)
+	}
+
+	state, err := marshalUtil.ReadUint8()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse state: %w", err)
+	}
+	pendingTransaction.State = PendingTransactionState(state)
+
+	conflictingTransactionCount, err := marshalUtil.ReadUint32()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse conflicting Transaction count: %w", err)
+	}
+	pendingTransaction.ConflictingTransactionIDs = make([]ledgerstate.TransactionID, conflictingTransactionCount)
+	for i := range pendingTransaction.ConflictingTransactionIDs {
+		if pendingTransaction.ConflictingTransactionIDs[i], err = ledgerstate.TransactionIDFromMarshalUtil(marshalUtil); err != nil {
+			return nil, xerrors.Errorf("failed to parse conflicting TransactionID: %w", err)
+		}
+	}
+
+	if pendingTransaction.IssuingTime, err = marshalUtil.ReadTime(); err != nil {
+		return nil, xerrors.Errorf("failed to parse issuing time: %w", err)
+	}
+	if pendingTransaction.LastAttachmentTime, err = marshalUtil.ReadTime(); err != nil {
+		return nil, xerrors.Errorf("failed to parse last attachment time: %w", err)
+	}
+	reattachments, err := marshalUtil.ReadUint32()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse reattachment count: %w", err)
+	}
+	pendingTransaction.Reattachments = int(reattachments)
+	if pendingTransaction.ReissuedTransactionID, err = ledgerstate.TransactionIDFromMarshalUtil(marshalUtil); err != nil {
+		return nil, xerrors.Errorf("failed to parse reissued TransactionID: %w", err)
+	}
+
+	errorLength, err := marshalUtil.ReadUint32()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse error length: %w", err)
+	}
+	errorBytes, err := marshalUtil.ReadBytes(int(errorLength))
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse error: %w", err)
+	}
+	pendingTransaction.Error = string(errorBytes)
+
+	optionsLength, err := marshalUtil.ReadUint32()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse transfer options length: %w", err)
+	}
+	if optionsLength != 0 {
+		if pendingTransaction.sendFundsOptions, err = marshalUtil.ReadBytes(int(optionsLength)); err != nil {
+			return nil, xerrors.Errorf("failed to parse transfer options: %w", err)
+		}
+	}
+
+	return
+}
+
+// Reissuable returns true if the transfer of the PendingTransaction can be rebuilt with fresh inputs.
+func (p *PendingTransaction) Reissuable() bool {
+	return len(p.sendFundsOptions) != 0
+}
+
+// Bytes returns a marshaled version of the PendingTransaction.
+func (p *PendingTransaction) Bytes() []byte {
+	marshalUtil := marshalutil.New().
+		WriteBytes(p.Transaction.Bytes()).
+		WriteUint8(uint8(p.State)).
+		WriteUint32(uint32(len(p.ConflictingTransactionIDs)))
+	for _, conflictingTransactionID := range p.ConflictingTransactionIDs {
+		marshalUtil.WriteBytes(conflictingTransactionID.Bytes())
+	}
+
+	errorBytes := typeutils.StringToBytes(p.Error)
+
+	return marshalUtil.
+		WriteTime(p.IssuingTime).
+		WriteTime(p.LastAttachmentTime).
+		WriteUint32(uint32(p.Reattachments)).
+		WriteBytes(p.ReissuedTransactionID.Bytes()).
+		WriteUint32(uint32(len(errorBytes))).
+		WriteBytes(errorBytes).
+		WriteUint32(uint32(len(p.sendFundsOptions))).
+		WriteBytes(p.sendFundsOptions).
+		Bytes()
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region PendingTransactionManager ////////////////////////////////////////////////////////////////////////////////////
+
+// PendingTransactionManager keeps track of the Transactions that were issued by the wallet until they reach a final
+// state.
+type PendingTransactionManager struct {
+	pendingTransactions map[ledgerstate.TransactionID]*PendingTransaction
+}
+
+// NewPendingTransactionManager is the constructor of the PendingTransactionManager.
+func NewPendingTransactionManager() *PendingTransactionManager {
+	return &PendingTransactionManager{
+		pendingTransactions: make(map[ledgerstate.TransactionID]*PendingTransaction),
+	}
+}
+
+// ParsePendingTransactionManager is a utility function that can be used to parse a marshaled version of the
+// PendingTransactionManager.
+func ParsePendingTransactionManager(marshalUtil *marshalutil.MarshalUtil) (pendingTransactionManager *PendingTransactionManager, consumedBytes int, err error) {
+	pendingTransactionManager = NewPendingTransactionManager()
+
+	startingOffset := marshalUtil.ReadOffset()
+
+	pendingTransactionCount, err := marshalUtil.ReadUint32()
+	if err != nil {
+		err = xerrors.Errorf("failed to parse pending Transaction count: %w", err)
+		return
+	}
+	for i := uint32(0); i < pendingTransactionCount; i++ {
+		pendingTransaction, parseErr := pendingTransactionFromMarshalUtil(marshalUtil)
+		if parseErr != nil {
+			err = xerrors.Errorf("failed to parse PendingTransaction: %w", parseErr)
+			return
+		}
+		pendingTransactionManager.pendingTransactions[pendingTransaction.Transaction.ID()] = pendingTransaction
+	}
+
+	consumedBytes = marshalUtil.ReadOffset() - startingOffset
+
+	return
+}
+
+// PendingTransaction returns the PendingTransaction with the given TransactionID (or nil if it is not tracked).
+func (p *PendingTransactionManager) PendingTransaction(transactionID ledgerstate.TransactionID) *PendingTransaction {
+	return p.pendingTransactions[transactionID]
+}
+
+// PendingTransactions returns all tracked Transactions ordered by their issuing time.
+func (p *PendingTransactionManager) PendingTransactions() (pendingTransactions []*PendingTransaction) {
+	pendingTransactions = make([]*PendingTransaction, 0, len(p.pendingTransactions))
+	for _, pendingTransaction := range p.pendingTransactions {
+		pendingTransactions = append(pendingTransactions, pendingTransaction)
+	}
+	sort.Slice(pendingTransactions, func(i, j int) bool {
+		return pendingTransactions[i].IssuingTime.Before(pendingTransactions[j].IssuingTime)
+	})
+
+	return
+}
+
+// Bytes marshals the PendingTransactionManager into a sequence of bytes.
+func (p *PendingTransactionManager) Bytes() []byte {
+	marshalUtil := marshalutil.New().WriteUint32(uint32(len(p.pendingTransactions)))
+	for _, pendingTransaction := range p.PendingTransactions() {
+		marshalUtil.WriteBytes(pendingTransaction.Bytes())
+	}
+
+	return marshalUtil.Bytes()
+}
+
+// add starts tracking the given Transaction. The marshaled sendFundsOptions are used to rebuild the transfer if the
+// Transaction gets rejected.
+func (p *PendingTransactionManager) add(transaction *ledgerstate.Transaction, sendFundsOptions []byte) {
+	now := time.Now()
+	p.pendingTransactions[transaction.ID()] = &PendingTransaction{
+		Transaction:        transaction,
+		State:              PendingTransactionPending,
+		IssuingTime:        now,
+		LastAttachmentTime: now,
+		sendFundsOptions:   sendFundsOptions,
+	}
+}
+
+// pruneFinalized removes the Transactions that reached a final state (they are reported once before being removed).
+func (p *PendingTransactionManager) pruneFinalized() {
+	for transactionID, pendingTransaction := range p.pendingTransactions {
+		if pendingTransaction.State.Final() {
+			delete(p.pendingTransactions, transactionID)
+		}
+	}
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
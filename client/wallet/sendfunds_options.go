@@ -2,9 +2,13 @@ package wallet
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/iotaledger/goshimmer/client/wallet/packages/address"
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/marshalutil"
+	"golang.org/x/xerrors"
 )
 
 // SendFundsOption is the type for the optional parameters for the SendFunds call.
@@ -52,6 +56,26 @@ func Destination(addr address.Address, amount uint64, optionalColor ...ledgersta
 	}
 }
 
+// Burn is an option for the SendFunds call that converts the given amount of colored tokens back to uncolored IOTA
+// tokens which are sent to the remainder address.
+func Burn(color ledgerstate.Color, amount uint64) SendFundsOption {
+	if color == ledgerstate.ColorIOTA || color == ledgerstate.ColorMint {
+		return optionError(errors.New("only colored tokens can be burned"))
+	}
+	if amount == 0 {
+		return optionError(errors.New("the amount of burned tokens needs to be larger than 0"))
+	}
+
+	return func(options *sendFundsOptions) error {
+		if options.Burns == nil {
+			options.Burns = make(map[ledgerstate.Color]uint64)
+		}
+		options.Burns[color] += amount
+
+		return nil
+	}
+}
+
 // Remainder is an option for the SendsFunds call that allows us to specify the remainder address that is
 // supposed to be used in the corresponding transaction.
 func Remainder(addr address.Address) SendFundsOption {
@@ -62,10 +86,192 @@ func Remainder(addr address.Address) SendFundsOption {
 	}
 }
 
+// LockUntil is an option for the SendFunds call that timelocks the funds that are sent to the destinations, so that
+// they can not be spent before the given time.
+func LockUntil(until time.Time) SendFundsOption {
+	return func(options *sendFundsOptions) error {
+		options.LockUntil = until
+
+		return nil
+	}
+}
+
+// Fallback is an option for the SendFunds call that allows the given address to claim the funds that are sent to the
+// destinations if they have not been spent before the given deadline.
+func Fallback(addr address.Address, deadline time.Time) SendFundsOption {
+	if !deadline.After(time.Now()) {
+		return optionError(errors.New("the fallback deadline needs to be in the future"))
+	}
+
+	return func(options *sendFundsOptions) error {
+		options.FallbackAddress = addr
+		options.FallbackDeadline = deadline
+
+		return nil
+	}
+}
+
+// OutputPayload is an option for the SendFunds call that attaches the given data to the outputs of the destinations.
+func OutputPayload(data []byte) SendFundsOption {
+	if len(data) > ledgerstate.MaxOutputPayloadSize {
+		return optionError(fmt.Errorf("the output payload must not be larger than %d bytes", ledgerstate.MaxOutputPayloadSize))
+	}
+
+	return func(options *sendFundsOptions) error {
+		options.OutputPayload = data
+
+		return nil
+	}
+}
+
+// WaitForConfirmation is an option for the SendFunds call that blocks until the issued transaction is confirmed (or
+// rejected). It requires a Connector that implements the ConfirmationAwaiter interface.
+func WaitForConfirmation() SendFundsOption {
+	return func(options *sendFundsOptions) error {
+		options.WaitForConfirmation = true
+
+		return nil
+	}
+}
+
+// SourceAccount is an option for the SendFunds call that defines the account whose funds are used for the transfer (the
+// default account is used if it is not provided).
+func SourceAccount(name string) SendFundsOption {
+	return func(options *sendFundsOptions) error {
+		options.SourceAccount = name
+
+		return nil
+	}
+}
+
 // sendFundsOptions is a struct that is used to aggregate the optional parameters provided in the SendFunds call.
 type sendFundsOptions struct {
-	Destinations     map[address.Address]map[ledgerstate.Color]uint64
-	RemainderAddress address.Address
+	Destinations        map[address.Address]map[ledgerstate.Color]uint64
+	Burns               map[ledgerstate.Color]uint64
+	RemainderAddress    address.Address
+	LockUntil           time.Time
+	FallbackAddress     address.Address
+	FallbackDeadline    time.Time
+	OutputPayload       []byte
+	SourceAccount       string
+	WaitForConfirmation bool
+}
+
+// sendFundsOptionsFromMarshalUtil unmarshals the sendFundsOptions of a transfer that were persisted to be able to
+// rebuild it later (the WaitForConfirmation option is not persisted).
+func sendFundsOptionsFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (options *sendFundsOptions, err error) {
+	options = &sendFundsOptions{}
+
+	destinationCount, err := marshalUtil.ReadUint32()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse destination count: %w", err)
+	}
+	if destinationCount != 0 {
+		options.Destinations = make(map[address.Address]map[ledgerstate.Color]uint64)
+	}
+	for i := uint32(0); i < destinationCount; i++ {
+		destinationAddress, addressErr := walletAddressFromMarshalUtil(marshalUtil)
+		if addressErr != nil {
+			return nil, xerrors.Errorf("failed to parse destination address: %w", addressErr)
+		}
+		if options.Destinations[destinationAddress], err = coloredAmountsFromMarshalUtil(marshalUtil); err != nil {
+			return nil, xerrors.Errorf("failed to parse destination amounts: %w", err)
+		}
+	}
+
+	hasBurns, err := marshalUtil.ReadBool()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse burn flag: %w", err)
+	}
+	if hasBurns {
+		if options.Burns, err = coloredAmountsFromMarshalUtil(marshalUtil); err != nil {
+			return nil, xerrors.Errorf("failed to parse burned amounts: %w", err)
+		}
+	}
+
+	if options.RemainderAddress, err = walletAddressFromMarshalUtil(marshalUtil); err != nil {
+		return nil, xerrors.Errorf("failed to parse remainder address: %w", err)
+	}
+	if options.LockUntil, err = marshalUtil.ReadTime(); err != nil {
+		return nil, xerrors.Errorf("failed to parse timelock: %w", err)
+	}
+	if options.FallbackAddress, err = walletAddressFromMarshalUtil(marshalUtil); err != nil {
+		return nil, xerrors.Errorf("failed to parse fallback address: %w", err)
+	}
+	if options.FallbackDeadline, err = marshalUtil.ReadTime(); err != nil {
+		return nil, xerrors.Errorf("failed to parse fallback deadline: %w", err)
+	}
+
+	payloadLength, err := marshalUtil.ReadUint16()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse output payload length: %w", err)
+	}
+	if payloadLength != 0 {
+		if options.OutputPayload, err = marshalUtil.ReadBytes(int(payloadLength)); err != nil {
+			return nil, xerrors.Errorf("failed to parse output payload: %w", err)
+		}
+	}
+
+	sourceAccountLength, err := marshalUtil.ReadUint16()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse source account length: %w", err)
+	}
+	sourceAccountBytes, err := marshalUtil.ReadBytes(int(sourceAccountLength))
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse source account: %w", err)
+	}
+	options.SourceAccount = string(sourceAccountBytes)
+
+	return
+}
+
+// Bytes returns a marshaled version of the sendFundsOptions (the WaitForConfirmation option is not persisted).
+func (s *sendFundsOptions) Bytes() []byte {
+	marshalUtil := marshalutil.New()
+
+	marshalUtil.WriteUint32(uint32(len(s.Destinations)))
+	for destinationAddress, amounts := range s.Destinations {
+		writeWalletAddress(marshalUtil, destinationAddress)
+		writeColoredAmounts(marshalUtil, amounts)
+	}
+
+	marshalUtil.WriteBool(s.Burns != nil)
+	if s.Burns != nil {
+		writeColoredAmounts(marshalUtil, s.Burns)
+	}
+
+	writeWalletAddress(marshalUtil, s.RemainderAddress)
+	marshalUtil.WriteTime(s.LockUntil)
+	writeWalletAddress(marshalUtil, s.FallbackAddress)
+	marshalUtil.WriteTime(s.FallbackDeadline)
+	marshalUtil.WriteUint16(uint16(len(s.OutputPayload)))
+	marshalUtil.WriteBytes(s.OutputPayload)
+	marshalUtil.WriteUint16(uint16(len(s.SourceAccount)))
+	marshalUtil.WriteBytes([]byte(s.SourceAccount))
+
+	return marshalUtil.Bytes()
+}
+
+// requiresExtendedLockedOutputs returns true if the funds of the destinations need to be sent to ExtendedLockedOutputs.
+func (s *sendFundsOptions) requiresExtendedLockedOutputs() bool {
+	return !s.LockUntil.IsZero() || s.FallbackAddress != address.AddressEmpty || len(s.OutputPayload) != 0
+}
+
+// destinationOutput creates the Output that sends the given balances to the given destination.
+func (s *sendFundsOptions) destinationOutput(addr address.Address, balances map[ledgerstate.Color]uint64) ledgerstate.Output {
+	if !s.requiresExtendedLockedOutputs() {
+		return ledgerstate.NewSigLockedColoredOutput(ledgerstate.NewColoredBalances(balances), addr.Address())
+	}
+
+	output := ledgerstate.NewExtendedLockedOutput(ledgerstate.NewColoredBalances(balances), addr.Address()).WithTimeLock(s.LockUntil)
+	if s.FallbackAddress != address.AddressEmpty {
+		output = output.WithFallbackOptions(s.FallbackAddress.Address(), s.FallbackDeadline)
+	}
+	if err := output.SetPayload(s.OutputPayload); err != nil {
+		panic(err)
+	}
+
+	return output
 }
 
 // buildSendFundsOptions is a utility function that constructs the sendFundsOptions.
@@ -81,7 +287,7 @@ func buildSendFundsOptions(options ...SendFundsOption) (result *sendFundsOptions
 	}
 
 	// sanitize parameters
-	if len(result.Destinations) == 0 {
+	if len(result.Destinations) == 0 && len(result.Burns) == 0 {
 		err = errors.New("you need to provide at least one Destination for a valid transfer to be issued")
 
 		return
@@ -90,6 +296,54 @@ func buildSendFundsOptions(options ...SendFundsOption) (result *sendFundsOptions
 	return
 }
 
+// writeWalletAddress writes the given wallet Address to the MarshalUtil.
+func writeWalletAddress(marshalUtil *marshalutil.MarshalUtil, addr address.Address) {
+	marshalUtil.WriteBytes(addr.AddressBytes[:])
+	marshalUtil.WriteUint64(addr.Index)
+}
+
+// walletAddressFromMarshalUtil reads a wallet Address from the MarshalUtil.
+func walletAddressFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (addr address.Address, err error) {
+	addressBytes, err := marshalUtil.ReadBytes(ledgerstate.AddressLength)
+	if err != nil {
+		return
+	}
+	copy(addr.AddressBytes[:], addressBytes)
+	addr.Index, err = marshalUtil.ReadUint64()
+
+	return
+}
+
+// writeColoredAmounts writes the given amounts per Color to the MarshalUtil.
+func writeColoredAmounts(marshalUtil *marshalutil.MarshalUtil, amounts map[ledgerstate.Color]uint64) {
+	marshalUtil.WriteUint32(uint32(len(amounts)))
+	for color, amount := range amounts {
+		marshalUtil.WriteBytes(color.Bytes())
+		marshalUtil.WriteUint64(amount)
+	}
+}
+
+// coloredAmountsFromMarshalUtil reads the amounts per Color from the MarshalUtil.
+func coloredAmountsFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (amounts map[ledgerstate.Color]uint64, err error) {
+	colorCount, err := marshalUtil.ReadUint32()
+	if err != nil {
+		return
+	}
+
+	amounts = make(map[ledgerstate.Color]uint64)
+	for i := uint32(0); i < colorCount; i++ {
+		color, colorErr := ledgerstate.ColorFromMarshalUtil(marshalUtil)
+		if colorErr != nil {
+			return nil, colorErr
+		}
+		if amounts[color], err = marshalUtil.ReadUint64(); err != nil {
+			return nil, err
+		}
+	}
+
+	return
+}
+
 // optionError is a utility function that returns a Option that returns the error provided in the
 // argument.
 func optionError(err error) SendFundsOption {
@@ -0,0 +1,85 @@
+package wallet
+
+import (
+	"bytes"
+	"unsafe"
+
+	"github.com/iotaledger/goshimmer/client/wallet/packages/seed"
+	"github.com/iotaledger/hive.go/bitmask"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/hive.go/marshalutil"
+	"golang.org/x/xerrors"
+)
+
+// stateVersion is the version of the format of the marshaled state of a wallet that is written by ExportState.
+const stateVersion uint8 = 1
+
+// stateMagic is the prefix of the marshaled state of a wallet that distinguishes the versioned format from the legacy
+// format (which starts with the seed).
+var stateMagic = []byte("GSWALLET")
+
+// ParseState restores the accounts and the AssetRegistry of a wallet from the state that was written by ExportState.
+// The state of wallets that were exported before accounts were introduced is upgraded automatically: their addresses
+// become the receive chain of the default account.
+func ParseState(stateBytes []byte) (accountManager *AccountManager, assetRegistry *AssetRegistry, err error) {
+	if !bytes.HasPrefix(stateBytes, stateMagic) {
+		return parseLegacyState(stateBytes)
+	}
+
+	marshalUtil := marshalutil.New(stateBytes)
+	marshalUtil.ReadSeek(len(stateMagic))
+
+	version, err := marshalUtil.ReadUint8()
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to parse state version: %w", err)
+	}
+	if version != stateVersion {
+		return nil, nil, xerrors.Errorf("unsupported state version %d", version)
+	}
+
+	walletSeed, err := seedFromMarshalUtil(marshalUtil)
+	if err != nil {
+		return
+	}
+	if assetRegistry, _, err = ParseAssetRegistry(marshalUtil); err != nil {
+		return nil, nil, xerrors.Errorf("failed to parse asset registry: %w", err)
+	}
+	if accountManager, err = accountManagerFromMarshalUtil(marshalUtil, walletSeed); err != nil {
+		return nil, nil, xerrors.Errorf("failed to parse accounts: %w", err)
+	}
+
+	return
+}
+
+// parseLegacyState restores the state of a wallet that consists of the seed, the last address index, the AssetRegistry
+// and the spent addresses of a single chain of addresses.
+func parseLegacyState(stateBytes []byte) (accountManager *AccountManager, assetRegistry *AssetRegistry, err error) {
+	marshalUtil := marshalutil.New(stateBytes)
+
+	walletSeed, err := seedFromMarshalUtil(marshalUtil)
+	if err != nil {
+		return
+	}
+	lastAddressIndex, err := marshalUtil.ReadUint64()
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to parse last address index: %w", err)
+	}
+	if assetRegistry, _, err = ParseAssetRegistry(marshalUtil); err != nil {
+		return nil, nil, xerrors.Errorf("failed to parse asset registry: %w", err)
+	}
+
+	spentAddressesBytes := marshalUtil.ReadRemainingBytes()
+	spentAddresses := *(*[]bitmask.BitMask)(unsafe.Pointer(&spentAddressesBytes))
+
+	return newLegacyAccountManager(walletSeed, lastAddressIndex, spentAddresses), assetRegistry, nil
+}
+
+// seedFromMarshalUtil reads the seed of a wallet from the MarshalUtil.
+func seedFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (walletSeed *seed.Seed, err error) {
+	seedBytes, err := marshalUtil.ReadBytes(ed25519.SeedSize)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse seed: %w", err)
+	}
+
+	return seed.NewSeed(seedBytes), nil
+}
@@ -0,0 +1,177 @@
+package wallet
+
+import (
+	"bytes"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/hive.go/marshalutil"
+	"golang.org/x/xerrors"
+)
+
+// ThresholdTransaction represents a partially signed Transaction that spends the funds of a ThresholdAddress. It is
+// passed between the co-signers which add their signatures until the threshold is reached and the Transaction can be
+// issued. It carries the consumed Outputs, so that every co-signer can verify that only the funds of the
+// ThresholdAddress are spent.
+type ThresholdTransaction struct {
+	essence         *ledgerstate.TransactionEssence
+	consumedOutputs ledgerstate.Outputs
+	unlockBlock     *ledgerstate.ThresholdSignatureUnlockBlock
+}
+
+// NewThresholdTransaction creates a new (unsigned) ThresholdTransaction for the given TransactionEssence whose Inputs
+// are the given consumed Outputs, which all have to be locked to the ThresholdAddress with the given threshold and
+// public keys.
+func NewThresholdTransaction(essence *ledgerstate.TransactionEssence, consumedOutputs ledgerstate.Outputs, threshold uint8, publicKeys ...ed25519.PublicKey) (thresholdTransaction *ThresholdTransaction, err error) {
+	unlockBlock, err := ledgerstate.NewThresholdSignatureUnlockBlock(threshold, publicKeys...)
+	if err != nil {
+		err = xerrors.Errorf("failed to create ThresholdSignatureUnlockBlock: %w", err)
+		return
+	}
+
+	thresholdTransaction = &ThresholdTransaction{
+		essence:         essence,
+		consumedOutputs: consumedOutputs,
+		unlockBlock:     unlockBlock,
+	}
+	if err = thresholdTransaction.validateInputs(); err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// ThresholdTransactionFromBytes unmarshals a ThresholdTransaction from a sequence of bytes.
+func ThresholdTransactionFromBytes(bytes []byte) (thresholdTransaction *ThresholdTransaction, err error) {
+	marshalUtil := marshalutil.New(bytes)
+
+	thresholdTransaction = &ThresholdTransaction{}
+	if thresholdTransaction.essence, err = ledgerstate.TransactionEssenceFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse TransactionEssence: %w", err)
+		return
+	}
+	consumedOutputsCount, err := marshalUtil.ReadUint16()
+	if err != nil {
+		err = xerrors.Errorf("failed to parse consumed Outputs count: %w", err)
+		return
+	}
+	if int(consumedOutputsCount) != len(thresholdTransaction.essence.Inputs()) {
+		err = xerrors.Errorf("amount of consumed Outputs (%d) does not match the amount of Inputs (%d)", consumedOutputsCount, len(thresholdTransaction.essence.Inputs()))
+		return
+	}
+	thresholdTransaction.consumedOutputs = make(ledgerstate.Outputs, consumedOutputsCount)
+	for i := range thresholdTransaction.consumedOutputs {
+		outputID, outputIDErr := ledgerstate.OutputIDFromMarshalUtil(marshalUtil)
+		if outputIDErr != nil {
+			err = xerrors.Errorf("failed to parse OutputID: %w", outputIDErr)
+			return
+		}
+		output, outputErr := ledgerstate.OutputFromMarshalUtil(marshalUtil)
+		if outputErr != nil {
+			err = xerrors.Errorf("failed to parse Output: %w", outputErr)
+			return
+		}
+		thresholdTransaction.consumedOutputs[i] = output.SetID(outputID)
+	}
+	if thresholdTransaction.unlockBlock, err = ledgerstate.PartialThresholdSignatureUnlockBlockFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse ThresholdSignatureUnlockBlock: %w", err)
+		return
+	}
+	if err = thresholdTransaction.validateInputs(); err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// Address returns the ThresholdAddress whose funds are spent by the ThresholdTransaction.
+func (t *ThresholdTransaction) Address() *ledgerstate.ThresholdAddress {
+	return t.unlockBlock.Address()
+}
+
+// Essence returns the TransactionEssence that is signed by the co-signers.
+func (t *ThresholdTransaction) Essence() *ledgerstate.TransactionEssence {
+	return t.essence
+}
+
+// Sign adds the signature of the given KeyPair to the ThresholdTransaction.
+func (t *ThresholdTransaction) Sign(keyPair *ed25519.KeyPair) (err error) {
+	if err = t.unlockBlock.AddSignature(keyPair.PublicKey, keyPair.PrivateKey.Sign(t.essence.Bytes())); err != nil {
+		err = xerrors.Errorf("failed to sign ThresholdTransaction: %w", err)
+	}
+
+	return
+}
+
+// Complete returns true if the ThresholdTransaction contains enough valid signatures to be issued.
+func (t *ThresholdTransaction) Complete() bool {
+	return t.unlockBlock.AddressSignatureValid(t.unlockBlock.Address(), t.essence.Bytes())
+}
+
+// Transaction returns the fully signed Transaction that unlocks all Inputs with the collected signatures.
+func (t *ThresholdTransaction) Transaction() (transaction *ledgerstate.Transaction, err error) {
+	if err = t.validateInputs(); err != nil {
+		return
+	}
+	if !t.Complete() {
+		err = xerrors.Errorf("ThresholdTransaction only contains %d of %d required signatures", t.unlockBlock.SignatureCount(), t.unlockBlock.Threshold())
+		return
+	}
+
+	unlockBlocks := make(ledgerstate.UnlockBlocks, len(t.essence.Inputs()))
+	unlockBlocks[0] = t.unlockBlock
+	for i := 1; i < len(unlockBlocks); i++ {
+		unlockBlocks[i] = ledgerstate.NewReferenceUnlockBlock(0)
+	}
+	transaction = ledgerstate.NewTransaction(t.essence, unlockBlocks)
+
+	return
+}
+
+// Bytes returns a marshaled version of the ThresholdTransaction that can be handed to the other co-signers.
+func (t *ThresholdTransaction) Bytes() []byte {
+	marshalUtil := marshalutil.New().
+		WriteBytes(t.essence.Bytes()).
+		WriteUint16(uint16(len(t.consumedOutputs)))
+	for _, consumedOutput := range t.consumedOutputs {
+		marshalUtil.WriteBytes(consumedOutput.ID().Bytes()).WriteBytes(consumedOutput.Bytes())
+	}
+
+	return marshalUtil.
+		WriteBytes(t.unlockBlock.Bytes()).
+		Bytes()
+}
+
+// validateInputs is an internal utility function that checks that the consumed Outputs match the Inputs of the
+// TransactionEssence and that they are all locked to the ThresholdAddress. Otherwise, the ReferenceUnlockBlocks would
+// unlock Outputs of other addresses with the signatures of the co-signers.
+func (t *ThresholdTransaction) validateInputs() (err error) {
+	inputs := t.essence.Inputs()
+	if len(t.consumedOutputs) != len(inputs) {
+		return xerrors.Errorf("amount of consumed Outputs (%d) does not match the amount of Inputs (%d)", len(t.consumedOutputs), len(inputs))
+	}
+
+	thresholdAddress := t.unlockBlock.Address()
+	consumedOutputsByID := ledgerstate.NewOutputsByID(t.consumedOutputs...)
+	for i, input := range inputs {
+		utxoInput, ok := input.(*ledgerstate.UTXOInput)
+		if !ok {
+			return xerrors.Errorf("Input %d is not a UTXOInput", i)
+		}
+		consumedOutput, exists := consumedOutputsByID[utxoInput.ReferencedOutputID()]
+		if !exists {
+			return xerrors.Errorf("consumed Output of Input %d is missing", i)
+		}
+		if !bytes.Equal(consumedOutput.Address().Bytes(), thresholdAddress.Bytes()) {
+			return xerrors.Errorf("Input %d spends the funds of %s instead of %s", i, consumedOutput.Address().Base58(), thresholdAddress.Base58())
+		}
+	}
+
+	return nil
+}
+
+// SignThresholdTransaction adds the signature of the key that belongs to the given Address of the wallet to the
+// ThresholdTransaction.
+func (wallet *Wallet) SignThresholdTransaction(thresholdTransaction *ThresholdTransaction, addressIndex uint64) (err error) {
+	return thresholdTransaction.Sign(wallet.Seed().KeyPair(addressIndex))
+}
@@ -5,21 +5,28 @@ import (
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
 )
 
+// AddressProvider is the interface of the components that manage the addresses of a wallet whose outputs are tracked
+// by the UnspentOutputManager.
+type AddressProvider interface {
+	Addresses() []address.Address
+	UnspentAddresses() []address.Address
+}
+
 // UnspentOutputManager is a manager for the unspent outputs of the addresses of a wallet. It allows us to keep track of
 // the spent state of outputs using our local knowledge about outputs that have already been spent and allows us to
 // cache results that would otherwise have to be requested by the server over and over again.
 type UnspentOutputManager struct {
-	addressManager *AddressManager
-	connector      Connector
-	unspentOutputs map[address.Address]map[ledgerstate.OutputID]*Output
+	addressProvider AddressProvider
+	connector       Connector
+	unspentOutputs  map[address.Address]map[ledgerstate.OutputID]*Output
 }
 
 // NewUnspentOutputManager creates a new UnspentOutputManager.
-func NewUnspentOutputManager(addressManager *AddressManager, connector Connector) (outputManager *UnspentOutputManager) {
+func NewUnspentOutputManager(addressProvider AddressProvider, connector Connector) (outputManager *UnspentOutputManager) {
 	outputManager = &UnspentOutputManager{
-		addressManager: addressManager,
-		connector:      connector,
-		unspentOutputs: make(map[address.Address]map[ledgerstate.OutputID]*Output),
+		addressProvider: addressProvider,
+		connector:       connector,
+		unspentOutputs:  make(map[address.Address]map[ledgerstate.OutputID]*Output),
 	}
 
 	if err := outputManager.Refresh(true); err != nil {
@@ -34,9 +41,9 @@ func NewUnspentOutputManager(addressManager *AddressManager, connector Connector
 func (unspentOutputManager *UnspentOutputManager) Refresh(includeSpentAddresses ...bool) (err error) {
 	var addressesToRefresh []address.Address
 	if len(includeSpentAddresses) >= 1 && includeSpentAddresses[0] {
-		addressesToRefresh = unspentOutputManager.addressManager.Addresses()
+		addressesToRefresh = unspentOutputManager.addressProvider.Addresses()
 	} else {
-		addressesToRefresh = unspentOutputManager.addressManager.UnspentAddresses()
+		addressesToRefresh = unspentOutputManager.addressProvider.UnspentAddresses()
 	}
 
 	unspentOutputs, err := unspentOutputManager.connector.UnspentOutputs(addressesToRefresh...)
@@ -67,9 +74,9 @@ func (unspentOutputManager *UnspentOutputManager) UnspentOutputs(addresses ...ad
 	// prepare result
 	unspentOutputs = make(map[address.Address]map[ledgerstate.OutputID]*Output)
 
-	// retrieve the list of addresses from the address manager if none was provided
+	// retrieve the list of addresses from the address provider if none was provided
 	if len(addresses) == 0 {
-		addresses = unspentOutputManager.addressManager.Addresses()
+		addresses = unspentOutputManager.addressProvider.Addresses()
 	}
 
 	// iterate through addresses and scan for unspent outputs
@@ -2,28 +2,37 @@ package wallet
 
 import (
 	"errors"
+	"math"
 	"reflect"
+	"strconv"
 	"time"
-	"unsafe"
 
 	"github.com/iotaledger/goshimmer/client/wallet/packages/address"
 	"github.com/iotaledger/goshimmer/client/wallet/packages/seed"
+	"github.com/iotaledger/goshimmer/packages/assetregistry"
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
-	"github.com/iotaledger/hive.go/bitmask"
+	"github.com/iotaledger/goshimmer/packages/tangle"
 	"github.com/iotaledger/hive.go/identity"
 	"github.com/iotaledger/hive.go/marshalutil"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/xerrors"
 )
 
 // Wallet represents a simple cryptocurrency wallet for the IOTA tangle. It contains the logic to manage the movement of
 // funds.
 type Wallet struct {
-	addressManager       *AddressManager
-	assetRegistry        *AssetRegistry
-	unspentOutputManager *UnspentOutputManager
-	connector            Connector
+	accountManager            *AccountManager
+	assetRegistry             *AssetRegistry
+	unspentOutputManager      *UnspentOutputManager
+	pendingTransactionManager *PendingTransactionManager
+	connector                 Connector
 
 	// if this option is enabled the wallet will use a single reusable address instead of changing addresses.
 	reusableAddress bool
+
+	// reattachmentTimeout and maxReattachments define when and how often pending transactions are reattached.
+	reattachmentTimeout time.Duration
+	maxReattachments    int
 }
 
 // New is the factory method of the wallet. It either creates a new wallet or restores the wallet backup that is handed
@@ -31,7 +40,9 @@ type Wallet struct {
 func New(options ...Option) (wallet *Wallet) {
 	// create wallet
 	wallet = &Wallet{
-		assetRegistry: NewAssetRegistry(),
+		assetRegistry:       NewAssetRegistry(),
+		reattachmentTimeout: DefaultReattachmentTimeout,
+		maxReattachments:    DefaultMaxReattachments,
 	}
 
 	// configure wallet
@@ -39,9 +50,9 @@ func New(options ...Option) (wallet *Wallet) {
 		option(wallet)
 	}
 
-	// initialize wallet with default address manager if we did not import a previous wallet
-	if wallet.addressManager == nil {
-		wallet.addressManager = NewAddressManager(seed.NewSeed(), 0, []bitmask.BitMask{})
+	// initialize wallet with default account manager if we did not import a previous wallet
+	if wallet.accountManager == nil {
+		wallet.accountManager = NewAccountManager(seed.NewSeed())
 	}
 
 	// initialize asset registry if none was provided in the options.
@@ -49,13 +60,18 @@ func New(options ...Option) (wallet *Wallet) {
 		wallet.assetRegistry = NewAssetRegistry()
 	}
 
+	// initialize pending transaction manager if none was provided in the options.
+	if wallet.pendingTransactionManager == nil {
+		wallet.pendingTransactionManager = NewPendingTransactionManager()
+	}
+
 	// initialize wallet with default connector (server) if none was provided
 	if wallet.connector == nil {
 		panic("you need to provide a connector for your wallet")
 	}
 
 	// initialize output manager
-	wallet.unspentOutputManager = NewUnspentOutputManager(wallet.addressManager, wallet.connector)
+	wallet.unspentOutputManager = NewUnspentOutputManager(wallet.accountManager, wallet.connector)
 	err := wallet.unspentOutputManager.Refresh(true)
 	if err != nil {
 		panic(err)
@@ -71,28 +87,41 @@ func (wallet *Wallet) ServerStatus() (status ServerStatus, err error) {
 
 // SendFunds issues a payment of the given amount to the given address.
 func (wallet *Wallet) SendFunds(options ...SendFundsOption) (tx *ledgerstate.Transaction, err error) {
-	// build options from the parameters
 	sendFundsOptions, err := buildSendFundsOptions(options...)
 	if err != nil {
 		return
 	}
 
-	// determine which outputs to use for our transfer
-	consumedOutputs, err := wallet.determineOutputsToConsume(sendFundsOptions)
-	if err != nil {
+	if tx, err = wallet.sendFunds(sendFundsOptions); err != nil || !sendFundsOptions.WaitForConfirmation {
 		return
 	}
 
-	// build transaction
-	inputs, consumedFunds := wallet.buildInputs(consumedOutputs)
-	outputs := wallet.buildOutputs(sendFundsOptions, consumedFunds)
-	txEssence := ledgerstate.NewTransactionEssence(0, time.Now(), identity.ID{}, identity.ID{}, inputs, outputs)
+	err = wallet.awaitTransactionConfirmation(tx.ID())
+
+	return
+}
+
+// sendFunds builds, signs and issues the transfer that is described by the given sendFundsOptions and tracks the
+// resulting Transaction until it is confirmed.
+func (wallet *Wallet) sendFunds(sendFundsOptions *sendFundsOptions) (tx *ledgerstate.Transaction, err error) {
+	// keep the original options (before the defaults are filled in) to be able to rebuild the transfer later
+	marshaledSendFundsOptions := sendFundsOptions.Bytes()
+
+	txEssence, consumedOutputs, err := wallet.buildTransactionEssence(sendFundsOptions)
+	if err != nil {
+		return
+	}
 	outputsByID := consumedOutputs.OutputsByID()
 
-	unlockBlocks := make([]ledgerstate.UnlockBlock, len(inputs))
+	unlockBlocks := make([]ledgerstate.UnlockBlock, len(txEssence.Inputs()))
 	existingUnlockBlocks := make(map[address.Address]uint16)
-	for outputIndex, input := range inputs {
+	for outputIndex, input := range txEssence.Inputs() {
 		output := outputsByID[input.(*ledgerstate.UTXOInput).ReferencedOutputID()]
+		// the output is signed with the key of the address that is able to unlock it at the time of the transaction
+		if !output.UnlockableNow(txEssence.Timestamp()) {
+			err = xerrors.Errorf("%s can only be unlocked by %s at the time of the transaction: %w", output.OutputID, output.UnlockAddressNow(txEssence.Timestamp()).Base58(), ErrOutputNotUnlockable)
+			return
+		}
 		if unlockBlockIndex, unlockBlockExists := existingUnlockBlocks[output.Address]; unlockBlockExists {
 			unlockBlocks[outputIndex] = ledgerstate.NewReferenceUnlockBlock(unlockBlockIndex)
 			continue
@@ -101,27 +130,67 @@ func (wallet *Wallet) SendFunds(options ...SendFundsOption) (tx *ledgerstate.Tra
 		keyPair := wallet.Seed().KeyPair(output.Address.Index)
 		unlockBlock := ledgerstate.NewSignatureUnlockBlock(ledgerstate.NewED25519Signature(keyPair.PublicKey, keyPair.PrivateKey.Sign(txEssence.Bytes())))
 		unlockBlocks[outputIndex] = unlockBlock
-		existingUnlockBlocks[output.Address] = uint16(len(existingUnlockBlocks))
+		existingUnlockBlocks[output.Address] = uint16(outputIndex)
 	}
 
 	tx = ledgerstate.NewTransaction(txEssence, unlockBlocks)
 
-	// mark outputs as spent
-	for addr, outputs := range consumedOutputs {
-		for transactionID := range outputs {
-			wallet.unspentOutputManager.MarkOutputSpent(addr, transactionID)
-		}
+	// send transaction
+	if err = wallet.connector.SendTransaction(tx); err != nil {
+		return
 	}
+	wallet.pendingTransactionManager.add(tx, marshaledSendFundsOptions)
 
-	// mark addresses as spent
-	if !wallet.reusableAddress {
-		for addr := range consumedOutputs {
-			wallet.addressManager.MarkAddressSpent(addr.Index)
+	return
+}
+
+// PrepareTransaction creates an unsigned PartiallySignedTransaction for a payment of the given amount to the given
+// address. It can be exported, signed (potentially on an offline machine and by several parties) and submitted later.
+func (wallet *Wallet) PrepareTransaction(options ...SendFundsOption) (partiallySignedTransaction *PartiallySignedTransaction, err error) {
+	sendFundsOptions, err := buildSendFundsOptions(options...)
+	if err != nil {
+		return
+	}
+
+	txEssence, consumedOutputs, err := wallet.buildTransactionEssence(sendFundsOptions)
+	if err != nil {
+		return
+	}
+
+	outputs := make(ledgerstate.Outputs, 0)
+	for _, output := range consumedOutputs.OutputsByID() {
+		outputs = append(outputs, output.LedgerOutput())
+	}
+
+	return NewPartiallySignedTransaction(txEssence, outputs)
+}
+
+// SignTransaction signs all unsigned Inputs of the PartiallySignedTransaction that are unlocked by one of the addresses
+// of this wallet (or by a ThresholdAddress that one of them is a co-signer of) and returns the amount of newly signed
+// Inputs.
+func (wallet *Wallet) SignTransaction(partiallySignedTransaction *PartiallySignedTransaction) (signedInputs int, err error) {
+	for _, addr := range wallet.accountManager.Addresses() {
+		signedAddressInputs, signErr := partiallySignedTransaction.Sign(wallet.Seed().KeyPair(addr.Index))
+		if signErr != nil {
+			err = signErr
+			return
 		}
+		signedInputs += signedAddressInputs
 	}
 
-	// send transaction
-	err = wallet.connector.SendTransaction(tx)
+	return
+}
+
+// SubmitTransaction issues the fully signed Transaction of the given PartiallySignedTransaction.
+func (wallet *Wallet) SubmitTransaction(partiallySignedTransaction *PartiallySignedTransaction) (tx *ledgerstate.Transaction, err error) {
+	if tx, err = partiallySignedTransaction.Transaction(); err != nil {
+		return
+	}
+
+	if err = wallet.connector.SendTransaction(tx); err != nil {
+		return
+	}
+	wallet.pendingTransactionManager.add(tx, nil)
 
 	return
 }
@@ -140,19 +209,46 @@ func (wallet *Wallet) CreateAsset(asset Asset) (assetColor ledgerstate.Color, er
 		return
 	}
 
+	if asset.Precision < 0 || asset.Precision > math.MaxUint8 {
+		err = errors.New("the precision of an asset needs to be between 0 and 255")
+
+		return
+	}
+
+	mintingAddress := wallet.ReceiveAddress()
 	tx, err := wallet.SendFunds(
-		Destination(wallet.ReceiveAddress(), asset.Amount, ledgerstate.ColorMint),
+		Destination(mintingAddress, asset.Amount, ledgerstate.ColorMint),
 	)
 	if err != nil {
 		return
 	}
 
-	assetColor, _, err = ledgerstate.ColorFromBytes(tx.ID().Bytes())
+	for _, output := range tx.Essence().Outputs() {
+		if _, mintsColor := output.Balances().Get(ledgerstate.ColorMint); mintsColor {
+			assetColor = blake2b.Sum256(output.ID().Bytes())
+		}
+	}
+	asset.Color = assetColor
+	asset.MintingTransactionID = tx.ID()
+	wallet.assetRegistry.RegisterAsset(assetColor, asset)
+
+	err = wallet.publishAssetMetadata(asset, mintingAddress)
+
+	return
+}
+
+// publishAssetMetadata signs the metadata of the given asset with the key of the address that received the minted
+// tokens and publishes it, so other wallets can resolve the asset.
+func (wallet *Wallet) publishAssetMetadata(asset Asset, mintingAddress address.Address) (err error) {
+	assetMetadata, err := assetregistry.NewAssetMetadata(asset.Color, asset.MintingTransactionID, time.Now(), asset.Name, asset.Symbol, uint8(asset.Precision), asset.URL)
 	if err != nil {
-		return
+		return xerrors.Errorf("failed to create AssetMetadata of %s: %w", asset.Color, err)
 	}
+	assetMetadata.Sign(wallet.Seed().KeyPair(mintingAddress.Index))
 
-	wallet.assetRegistry.RegisterAsset(assetColor, asset)
+	if err = wallet.connector.PublishAssetMetadata(assetMetadata); err != nil {
+		err = xerrors.Errorf("failed to publish AssetMetadata of %s: %w", asset.Color, err)
+	}
 
 	return
 }
@@ -162,19 +258,85 @@ func (wallet *Wallet) AssetRegistry() *AssetRegistry {
 	return wallet.assetRegistry
 }
 
-// ReceiveAddress returns the last receive address of the wallet.
+// RefreshAssetRegistry loads the assets of the colored tokens that are held by the wallet but that are not registered
+// in the AssetRegistry, yet, from the ledger. Names, symbols and precisions are resolved from the metadata that was
+// published by the minters.
+func (wallet *Wallet) RefreshAssetRegistry() (err error) {
+	colors := make([]ledgerstate.Color, 0)
+	for _, outputsOnAddress := range wallet.unspentOutputManager.UnspentOutputs() {
+		for _, output := range outputsOnAddress {
+			output.Balances.ForEach(func(color ledgerstate.Color, balance uint64) bool {
+				colors = append(colors, color)
+
+				return true
+			})
+		}
+	}
+
+	return wallet.assetRegistry.Populate(wallet.connector, colors...)
+}
+
+// ReceiveAddress returns the last receive address of the default account of the wallet.
 func (wallet *Wallet) ReceiveAddress() address.Address {
-	return wallet.addressManager.LastUnspentAddress()
+	return wallet.accountManager.DefaultAccount().ReceiveAddress()
 }
 
-// NewReceiveAddress generates and returns a new unused receive address.
+// NewReceiveAddress generates and returns a new unused receive address of the default account.
 func (wallet *Wallet) NewReceiveAddress() address.Address {
-	return wallet.addressManager.NewAddress()
+	return wallet.accountManager.DefaultAccount().NewReceiveAddress()
 }
 
-// RemainderAddress returns the address that is used for the remainder of funds.
+// RemainderAddress returns the address that is used for the remainder of funds of the default account.
 func (wallet *Wallet) RemainderAddress() address.Address {
-	return wallet.addressManager.FirstUnspentAddress()
+	return wallet.remainderAddress(wallet.accountManager.DefaultAccount())
+}
+
+// Account returns the account with the given name (or nil if it does not exist).
+func (wallet *Wallet) Account(name string) *Account {
+	return wallet.accountManager.Account(name)
+}
+
+// Accounts returns all accounts of the wallet ordered by their index.
+func (wallet *Wallet) Accounts() []*Account {
+	return wallet.accountManager.Accounts()
+}
+
+// CreateAccount creates a new account with the given name that has its own chains of addresses.
+func (wallet *Wallet) CreateAccount(name string) (account *Account, err error) {
+	return wallet.accountManager.CreateAccount(name)
+}
+
+// RestoreAccounts scans the chains of addresses of the accounts of the seed for addresses that were used before and
+// loads their unspent outputs. The scan of a chain stops after gapLimit consecutive unused addresses.
+func (wallet *Wallet) RestoreAccounts(gapLimit int) (err error) {
+	if err = wallet.accountManager.Discover(wallet.connector, gapLimit); err != nil {
+		return xerrors.Errorf("failed to discover accounts: %w", err)
+	}
+
+	return wallet.unspentOutputManager.Refresh(true)
+}
+
+// remainderAddress returns the address that receives the remainder of the transfers of the given account.
+func (wallet *Wallet) remainderAddress(account *Account) address.Address {
+	if wallet.reusableAddress {
+		return account.AddressManager(ReceiveChain).FirstUnspentAddress()
+	}
+
+	return account.ChangeAddress()
+}
+
+// sourceAccount returns the account whose funds are used by the transfer that is described by the given
+// sendFundsOptions.
+func (wallet *Wallet) sourceAccount(sendFundsOptions *sendFundsOptions) (account *Account, err error) {
+	if sendFundsOptions.SourceAccount == "" {
+		return wallet.accountManager.DefaultAccount(), nil
+	}
+
+	if account = wallet.accountManager.Account(sendFundsOptions.SourceAccount); account == nil {
+		err = xerrors.Errorf("failed to find account '%s': %w", sendFundsOptions.SourceAccount, ErrAccountNotFound)
+	}
+
+	return
 }
 
 // UnspentOutputs returns the unspent outputs that are available for spending.
@@ -190,6 +352,15 @@ func (wallet *Wallet) RequestFaucetFunds(waitForConfirmation ...bool) (err error
 		return
 	}
 
+	// let the node notify us about the confirmation if the connector supports it
+	if confirmationAwaiter, supported := wallet.connector.(ConfirmationAwaiter); supported {
+		receiveAddress := wallet.ReceiveAddress()
+
+		return confirmationAwaiter.AwaitAddressConfirmation(receiveAddress, func() error {
+			return wallet.connector.RequestFaucetFunds(receiveAddress)
+		})
+	}
+
 	if err = wallet.Refresh(); err != nil {
 		return
 	}
@@ -222,6 +393,17 @@ func (wallet *Wallet) RequestFaucetFunds(waitForConfirmation ...bool) (err error
 	}
 }
 
+// awaitTransactionConfirmation blocks until the Transaction with the given TransactionID is confirmed. It returns an
+// error if the Transaction is rejected or if the connector can not notify the wallet about the confirmation.
+func (wallet *Wallet) awaitTransactionConfirmation(transactionID ledgerstate.TransactionID) (err error) {
+	confirmationAwaiter, supported := wallet.connector.(ConfirmationAwaiter)
+	if !supported {
+		return xerrors.Errorf("failed to wait for the confirmation of %s: %w", transactionID, ErrConfirmationNotSupported)
+	}
+
+	return confirmationAwaiter.AwaitTransactionConfirmation(transactionID)
+}
+
 // Refresh scans the addresses for incoming transactions. If the optional rescanSpentAddresses parameter is set to true
 // we also scan the spent addresses again (this can take longer).
 func (wallet *Wallet) Refresh(rescanSpentAddresses ...bool) (err error) {
@@ -230,8 +412,20 @@ func (wallet *Wallet) Refresh(rescanSpentAddresses ...bool) (err error) {
 	return
 }
 
-// Balance returns the confirmed and pending balance of the funds managed by this wallet.
-func (wallet *Wallet) Balance() (confirmedBalance map[ledgerstate.Color]uint64, pendingBalance map[ledgerstate.Color]uint64, err error) {
+// Balance returns the confirmed and pending balance of the funds managed by this wallet. If account names are given,
+// only the funds of these accounts are considered.
+func (wallet *Wallet) Balance(accountNames ...string) (confirmedBalance map[ledgerstate.Color]uint64, pendingBalance map[ledgerstate.Color]uint64, err error) {
+	var addresses []address.Address
+	for _, accountName := range accountNames {
+		account := wallet.accountManager.Account(accountName)
+		if account == nil {
+			err = xerrors.Errorf("failed to find account '%s': %w", accountName, ErrAccountNotFound)
+
+			return
+		}
+		addresses = append(addresses, account.Addresses()...)
+	}
+
 	err = wallet.unspentOutputManager.Refresh()
 	if err != nil {
 		return
@@ -239,15 +433,21 @@ func (wallet *Wallet) Balance() (confirmedBalance map[ledgerstate.Color]uint64,
 
 	confirmedBalance = make(map[ledgerstate.Color]uint64)
 	pendingBalance = make(map[ledgerstate.Color]uint64)
+	now := time.Now()
 
 	// iterate through the unspent outputs
-	for _, outputsOnAddress := range wallet.unspentOutputManager.UnspentOutputs() {
+	for _, outputsOnAddress := range wallet.unspentOutputManager.UnspentOutputs(addresses...) {
 		for _, output := range outputsOnAddress {
 			// skip if the output was rejected or spent already
 			if output.InclusionState.Spent || output.InclusionState.Rejected {
 				continue
 			}
 
+			// skip if the output can only be unlocked by another address (i.e. the fallback of an ExtendedLockedOutput)
+			if !output.UnlockableNow(now) {
+				continue
+			}
+
 			// determine target map
 			var targetMap map[ledgerstate.Color]uint64
 			if output.InclusionState.Confirmed {
@@ -267,28 +467,234 @@ func (wallet *Wallet) Balance() (confirmedBalance map[ledgerstate.Color]uint64,
 	return
 }
 
+// PendingTransactions returns the transactions that were issued by this wallet and that are not confirmed, yet. The
+// transactions that reached a final state during the last call of ProcessPendingTransactions are contained as well.
+func (wallet *Wallet) PendingTransactions() []*PendingTransaction {
+	return wallet.pendingTransactionManager.PendingTransactions()
+}
+
+// PendingTransactionManager returns the PendingTransactionManager of the wallet (i.e. to persist it).
+func (wallet *Wallet) PendingTransactionManager() *PendingTransactionManager {
+	return wallet.pendingTransactionManager
+}
+
+// ProcessPendingTransactions updates the state of the transactions that were issued by this wallet. Transactions that
+// were not included or confirmed within the reattachment timeout are reattached in a new message (or rebuilt with a
+// fresh essence once they are too old to be reattached) and the transfers of rejected transactions are rebuilt with
+// fresh inputs.
+func (wallet *Wallet) ProcessPendingTransactions() (err error) {
+	wallet.pendingTransactionManager.pruneFinalized()
+
+	for _, pendingTransaction := range wallet.pendingTransactionManager.PendingTransactions() {
+		if err = wallet.processPendingTransaction(pendingTransaction); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// processPendingTransaction updates the state of a single pending transaction.
+func (wallet *Wallet) processPendingTransaction(pendingTransaction *PendingTransaction) (err error) {
+	inclusionState, conflictingTransactionIDs, err := wallet.connector.TransactionInclusionState(pendingTransaction.Transaction.ID())
+	if err != nil {
+		if xerrors.Is(err, ErrTransactionNotFound) {
+			wallet.reattachIfTimedOut(pendingTransaction)
+			err = nil
+		}
+
+		return
+	}
+	pendingTransaction.ConflictingTransactionIDs = conflictingTransactionIDs
+
+	switch {
+	case inclusionState.Confirmed:
+		pendingTransaction.State = PendingTransactionConfirmed
+	case inclusionState.Rejected:
+		pendingTransaction.State = PendingTransactionRejected
+		wallet.reissue(pendingTransaction)
+	case inclusionState.Conflicting:
+		pendingTransaction.State = PendingTransactionConflicting
+	default:
+		wallet.reattachIfTimedOut(pendingTransaction)
+	}
+
+	return
+}
+
+// reattachIfTimedOut issues the transaction in a new message if it was not included or confirmed within the
+// reattachment timeout. Transactions that are too old to be reattached are rebuilt with a fresh essence instead.
+func (wallet *Wallet) reattachIfTimedOut(pendingTransaction *PendingTransaction) {
+	if time.Since(pendingTransaction.LastAttachmentTime) < wallet.reattachmentTimeout {
+		return
+	}
+	if pendingTransaction.Reattachments >= wallet.maxReattachments {
+		pendingTransaction.State = PendingTransactionFailed
+
+		return
+	}
+	if time.Since(pendingTransaction.Transaction.Essence().Timestamp()) > tangle.MaxReattachmentTimeMin {
+		wallet.refresh(pendingTransaction)
+
+		return
+	}
+
+	if err := wallet.connector.SendTransaction(pendingTransaction.Transaction); err != nil {
+		pendingTransaction.Error = err.Error()
+
+		return
+	}
+	pendingTransaction.Reattachments++
+	pendingTransaction.LastAttachmentTime = time.Now()
+}
+
+// refresh rebuilds a transaction with the same inputs and outputs but a fresh timestamp, so that it can be attached to
+// the Tangle again. Spending the same inputs makes sure that at most one of both transactions gets confirmed.
+func (wallet *Wallet) refresh(pendingTransaction *PendingTransaction) {
+	if !pendingTransaction.Reissuable() {
+		pendingTransaction.State = PendingTransactionFailed
+		pendingTransaction.Error = "transaction is too old to be reattached and can not be rebuilt by this wallet"
+
+		return
+	}
+
+	essence := pendingTransaction.Transaction.Essence()
+	refreshedEssence := ledgerstate.NewTransactionEssence(essence.Version(), time.Now(), essence.AccessPledgeID(), essence.ConsensusPledgeID(), essence.Inputs(), essence.Outputs())
+
+	unlockBlocks := make(ledgerstate.UnlockBlocks, len(pendingTransaction.Transaction.UnlockBlocks()))
+	for i, unlockBlock := range pendingTransaction.Transaction.UnlockBlocks() {
+		signatureUnlockBlock, isSignatureUnlockBlock := unlockBlock.(*ledgerstate.SignatureUnlockBlock)
+		if !isSignatureUnlockBlock {
+			unlockBlocks[i] = unlockBlock
+			continue
+		}
+
+		for _, addr := range wallet.accountManager.Addresses() {
+			if !signatureUnlockBlock.AddressSignatureValid(addr.Address(), essence.Bytes()) {
+				continue
+			}
+
+			keyPair := wallet.Seed().KeyPair(addr.Index)
+			unlockBlocks[i] = ledgerstate.NewSignatureUnlockBlock(ledgerstate.NewED25519Signature(keyPair.PublicKey, keyPair.PrivateKey.Sign(refreshedEssence.Bytes())))
+			break
+		}
+		if unlockBlocks[i] == nil {
+			pendingTransaction.State = PendingTransactionFailed
+			pendingTransaction.Error = "failed to find the address that signed input " + strconv.Itoa(i)
+
+			return
+		}
+	}
+
+	refreshedTransaction := ledgerstate.NewTransaction(refreshedEssence, unlockBlocks)
+	if err := wallet.connector.SendTransaction(refreshedTransaction); err != nil {
+		pendingTransaction.Error = err.Error()
+
+		return
+	}
+	wallet.pendingTransactionManager.add(refreshedTransaction, pendingTransaction.sendFundsOptions)
+
+	pendingTransaction.State = PendingTransactionReissued
+	pendingTransaction.ReissuedTransactionID = refreshedTransaction.ID()
+}
+
+// reissue rebuilds the transfer of a rejected transaction with fresh inputs.
+func (wallet *Wallet) reissue(pendingTransaction *PendingTransaction) {
+	if !pendingTransaction.Reissuable() {
+		return
+	}
+
+	sendFundsOptions, err := sendFundsOptionsFromMarshalUtil(marshalutil.New(pendingTransaction.sendFundsOptions))
+	if err != nil {
+		pendingTransaction.Error = err.Error()
+
+		return
+	}
+
+	// make sure that the inputs of the rejected transaction are not used again
+	consumedOutputIDs := make(map[ledgerstate.OutputID]bool)
+	for _, input := range pendingTransaction.Transaction.Essence().Inputs() {
+		consumedOutputIDs[input.(*ledgerstate.UTXOInput).ReferencedOutputID()] = true
+	}
+	for addr, outputs := range wallet.unspentOutputManager.UnspentOutputs() {
+		for outputID := range outputs {
+			if consumedOutputIDs[outputID] {
+				wallet.unspentOutputManager.MarkOutputSpent(addr, outputID)
+			}
+		}
+	}
+
+	reissuedTransaction, err := wallet.sendFunds(sendFundsOptions)
+	if err != nil {
+		pendingTransaction.Error = err.Error()
+
+		return
+	}
+
+	pendingTransaction.State = PendingTransactionReissued
+	pendingTransaction.ReissuedTransactionID = reissuedTransaction.ID()
+}
+
 // Seed returns the seed of this wallet that is used to generate all of the wallets addresses and private keys.
 func (wallet *Wallet) Seed() *seed.Seed {
-	return wallet.addressManager.seed
+	return wallet.accountManager.Seed()
 }
 
-// AddressManager returns the manager for the addresses of this wallet.
+// AddressManager returns the manager for the receive addresses of the default account of this wallet.
 func (wallet *Wallet) AddressManager() *AddressManager {
-	return wallet.addressManager
+	return wallet.accountManager.DefaultAccount().AddressManager(ReceiveChain)
+}
+
+// AccountManager returns the manager for the accounts of this wallet.
+func (wallet *Wallet) AccountManager() *AccountManager {
+	return wallet.accountManager
 }
 
-// ExportState exports the current state of the wallet to a marshaled version.
+// ExportState exports the current state of the wallet to a marshaled version (see ParseState).
 func (wallet *Wallet) ExportState() []byte {
-	marshalUtil := marshalutil.New()
-	marshalUtil.WriteBytes(wallet.Seed().Bytes())
-	marshalUtil.WriteUint64(wallet.AddressManager().lastAddressIndex)
-	marshalUtil.WriteBytes(wallet.assetRegistry.Bytes())
-	marshalUtil.WriteBytes(*(*[]byte)(unsafe.Pointer(&wallet.addressManager.spentAddresses)))
+	return marshalutil.New().
+		WriteBytes(stateMagic).
+		WriteUint8(stateVersion).
+		WriteBytes(wallet.Seed().Bytes()).
+		WriteBytes(wallet.assetRegistry.Bytes()).
+		WriteBytes(wallet.accountManager.Bytes()).
+		Bytes()
+}
+
+// buildTransactionEssence is an internal utility function that selects the outputs that are required to fund the given
+// transfer and builds the corresponding TransactionEssence. Only outputs that can be unlocked by the wallet at the
+// timestamp of the TransactionEssence are selected. The consumed outputs are marked as spent.
+func (wallet *Wallet) buildTransactionEssence(sendFundsOptions *sendFundsOptions) (txEssence *ledgerstate.TransactionEssence, consumedOutputs OutputsByAddressAndOutputID, err error) {
+	timestamp := time.Now()
+
+	// determine which outputs to use for our transfer
+	if consumedOutputs, err = wallet.determineOutputsToConsume(sendFundsOptions, timestamp); err != nil {
+		return
+	}
+
+	// build transaction essence
+	inputs, consumedFunds := wallet.buildInputs(consumedOutputs)
+	outputs := wallet.buildOutputs(sendFundsOptions, consumedFunds)
+	txEssence = ledgerstate.NewTransactionEssence(0, timestamp, identity.ID{}, identity.ID{}, inputs, outputs)
+
+	// mark outputs as spent
+	for addr, outputs := range consumedOutputs {
+		for transactionID := range outputs {
+			wallet.unspentOutputManager.MarkOutputSpent(addr, transactionID)
+		}
+	}
 
-	return marshalUtil.Bytes()
+	// mark addresses as spent
+	if !wallet.reusableAddress {
+		for addr := range consumedOutputs {
+			wallet.accountManager.MarkAddressSpent(addr)
+		}
+	}
+
+	return
 }
 
-func (wallet *Wallet) determineOutputsToConsume(sendFundsOptions *sendFundsOptions) (outputsToConsume OutputsByAddressAndOutputID, err error) {
+func (wallet *Wallet) determineOutputsToConsume(sendFundsOptions *sendFundsOptions, timestamp time.Time) (outputsToConsume OutputsByAddressAndOutputID, err error) {
 	// initialize return values
 	outputsToConsume = make(OutputsByAddressAndOutputID)
 
@@ -304,19 +710,33 @@ func (wallet *Wallet) determineOutputsToConsume(sendFundsOptions *sendFundsOptio
 			requiredFunds[color] += amount
 		}
 	}
+	for color, amount := range sendFundsOptions.Burns {
+		requiredFunds[color] += amount
+	}
+
+	// determine the account that funds the transfer
+	account, err := wallet.sourceAccount(sendFundsOptions)
+	if err != nil {
+		return
+	}
 
 	// refresh balances so we get the latest changes
 	if err = wallet.unspentOutputManager.Refresh(); err != nil {
 		return
 	}
 
-	// look for the required funds in the available unspent outputs
-	for addr, unspentOutputsOnAddress := range wallet.unspentOutputManager.UnspentOutputs() {
+	// look for the required funds in the available unspent outputs of the account
+	for addr, unspentOutputsOnAddress := range wallet.unspentOutputManager.UnspentOutputs(account.Addresses()...) {
 		// keeps track if outputs from this address are supposed to be spent
 		outputsFromAddressSpent := false
 
 		// scan the outputs on this address for required funds
 		for outputID, output := range unspentOutputsOnAddress {
+			// skip the outputs that are timelocked or that can only be unlocked by another address
+			if !output.SpendableNow(timestamp) {
+				continue
+			}
+
 			// keeps track if the output contains any usable funds
 			requiredColorFoundInOutput := false
 
@@ -351,20 +771,22 @@ func (wallet *Wallet) determineOutputsToConsume(sendFundsOptions *sendFundsOptio
 		// every address if we are not using a reusable address)
 		if !wallet.reusableAddress && outputsFromAddressSpent {
 			for transactionID, output := range unspentOutputsOnAddress {
-				outputsToConsume[addr][transactionID] = output
+				if output.SpendableNow(timestamp) {
+					outputsToConsume[addr][transactionID] = output
+				}
 			}
 		}
 	}
 
-	// update remainder address with default value (first unspent address) if none was provided
+	// update remainder address with default value (change address of the account) if none was provided
 	if sendFundsOptions.RemainderAddress == address.AddressEmpty {
-		sendFundsOptions.RemainderAddress = wallet.RemainderAddress()
+		sendFundsOptions.RemainderAddress = wallet.remainderAddress(account)
 	}
 	if _, remainderAddressInConsumedOutputs := outputsToConsume[sendFundsOptions.RemainderAddress]; remainderAddressInConsumedOutputs && !wallet.reusableAddress {
-		sendFundsOptions.RemainderAddress = wallet.ReceiveAddress()
+		sendFundsOptions.RemainderAddress = account.AddressManager(ChangeChain).LastUnspentAddress()
 	}
 	if _, remainderAddressInConsumedOutputs := outputsToConsume[sendFundsOptions.RemainderAddress]; remainderAddressInConsumedOutputs && !wallet.reusableAddress {
-		sendFundsOptions.RemainderAddress = wallet.NewReceiveAddress()
+		sendFundsOptions.RemainderAddress = account.AddressManager(ChangeChain).NewAddress()
 	}
 
 	// check if we have found all required funds
@@ -420,23 +842,37 @@ func (wallet *Wallet) buildOutputs(sendFundsOptions *sendFundsOptions, consumedF
 		}
 	}
 
-	// build outputs for remainder
-	if len(consumedFunds) != 0 {
-		if _, addressExists := outputsByColor[sendFundsOptions.RemainderAddress]; !addressExists {
-			outputsByColor[sendFundsOptions.RemainderAddress] = make(map[ledgerstate.Color]uint64)
-		}
-
-		for color, amount := range consumedFunds {
-			outputsByColor[sendFundsOptions.RemainderAddress][color] += amount
+	// convert burned tokens back to uncolored tokens (they end up in the remainder)
+	for color, amount := range sendFundsOptions.Burns {
+		consumedFunds[color] -= amount
+		if consumedFunds[color] == 0 {
+			delete(consumedFunds, color)
 		}
+		consumedFunds[ledgerstate.ColorIOTA] += amount
 	}
 
 	// construct result
 	var outputsSlice []ledgerstate.Output
 	for addr, outputs := range outputsByColor {
-		coloredBalances := ledgerstate.NewColoredBalances(outputs)
-		output := ledgerstate.NewSigLockedColoredOutput(coloredBalances, addr.Address())
-		outputsSlice = append(outputsSlice, output)
+		if addr == sendFundsOptions.RemainderAddress && !sendFundsOptions.requiresExtendedLockedOutputs() {
+			continue
+		}
+
+		outputsSlice = append(outputsSlice, sendFundsOptions.destinationOutput(addr, outputs))
+	}
+
+	// build output for remainder (it is never locked and can be merged with a vanilla destination on the same address)
+	remainderBalances := make(map[ledgerstate.Color]uint64)
+	if !sendFundsOptions.requiresExtendedLockedOutputs() {
+		for color, amount := range outputsByColor[sendFundsOptions.RemainderAddress] {
+			remainderBalances[color] += amount
+		}
+	}
+	for color, amount := range consumedFunds {
+		remainderBalances[color] += amount
+	}
+	if len(remainderBalances) != 0 {
+		outputsSlice = append(outputsSlice, ledgerstate.NewSigLockedColoredOutput(ledgerstate.NewColoredBalances(remainderBalances), sendFundsOptions.RemainderAddress.Address()))
 	}
 	outputs = ledgerstate.NewOutputs(outputsSlice...)
 
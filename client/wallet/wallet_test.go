@@ -3,13 +3,21 @@ package wallet
 import (
 	"crypto/rand"
 	"testing"
+	"time"
 
 	"github.com/iotaledger/goshimmer/client/wallet/packages/address"
 	walletaddr "github.com/iotaledger/goshimmer/client/wallet/packages/address"
 	walletseed "github.com/iotaledger/goshimmer/client/wallet/packages/seed"
+	"github.com/iotaledger/goshimmer/packages/assetregistry"
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/goshimmer/packages/tangle"
 	"github.com/iotaledger/hive.go/bitmask"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/hive.go/identity"
+	"github.com/iotaledger/hive.go/marshalutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
 )
 
 func TestWallet_SendFunds(t *testing.T) {
@@ -74,6 +82,83 @@ func TestWallet_SendFunds(t *testing.T) {
 				assert.Nil(t, err)
 			},
 		},
+
+		// test if the funds of the destinations are sent to ExtendedLockedOutputs (while the remainder is not locked)
+		{
+			name: "lockedTransfer",
+			parameters: []SendFundsOption{
+				Destination(receiverSeed.Address(0), 1200),
+				LockUntil(time.Now().Add(time.Hour)),
+				Fallback(senderSeed.Address(1), time.Now().Add(2*time.Hour)),
+				OutputPayload([]byte("escrow")),
+			},
+			validator: func(t *testing.T, tx *ledgerstate.Transaction, err error) {
+				require.NoError(t, err)
+
+				lockedOutputs := 0
+				for _, output := range tx.Essence().Outputs() {
+					if output.Type() != ledgerstate.ExtendedLockedOutputType {
+						assert.NotEqual(t, receiverSeed.Address(0).Address().Bytes(), output.Address().Bytes())
+						continue
+					}
+					lockedOutputs++
+
+					extendedLockedOutput := output.(*ledgerstate.ExtendedLockedOutput)
+					assert.Equal(t, receiverSeed.Address(0).Address().Bytes(), extendedLockedOutput.Address().Bytes())
+					assert.True(t, extendedLockedOutput.TimeLockedNow(time.Now()))
+					fallbackAddress, _ := extendedLockedOutput.FallbackAddress()
+					assert.Equal(t, senderSeed.Address(1).Address().Bytes(), fallbackAddress.Bytes())
+					assert.Equal(t, []byte("escrow"), extendedLockedOutput.Payload())
+				}
+				assert.Equal(t, 1, lockedOutputs)
+			},
+		},
+
+		// test if burned tokens are converted back to IOTA tokens
+		{
+			name: "burn",
+			parameters: []SendFundsOption{
+				Burn(ledgerstate.Color{3}, 1000),
+			},
+			validator: func(t *testing.T, tx *ledgerstate.Transaction, err error) {
+				require.NoError(t, err)
+
+				outputBalances := make(map[ledgerstate.Color]uint64)
+				for _, output := range tx.Essence().Outputs() {
+					output.Balances().ForEach(func(color ledgerstate.Color, balance uint64) bool {
+						outputBalances[color] += balance
+						return true
+					})
+				}
+				assert.Equal(t, uint64(338), outputBalances[ledgerstate.Color{3}])
+				assert.Equal(t, uint64(1337+663+1000), outputBalances[ledgerstate.ColorIOTA])
+			},
+		},
+
+		// test if burning uncolored tokens triggers an error
+		{
+			name: "burnIOTA",
+			parameters: []SendFundsOption{
+				Burn(ledgerstate.ColorIOTA, 1000),
+			},
+			validator: func(t *testing.T, tx *ledgerstate.Transaction, err error) {
+				assert.True(t, tx == nil, "the transaction should be nil")
+				assert.Error(t, err)
+			},
+		},
+
+		// test if a fallback deadline in the past triggers an error
+		{
+			name: "expiredFallback",
+			parameters: []SendFundsOption{
+				Destination(receiverSeed.Address(0), 1200),
+				Fallback(senderSeed.Address(1), time.Now().Add(-time.Hour)),
+			},
+			validator: func(t *testing.T, tx *ledgerstate.Transaction, err error) {
+				assert.True(t, tx == nil, "the transaction should be nil")
+				assert.Error(t, err)
+			},
+		},
 	}
 
 	// execute sub-tests and hand in the results to the validator function
@@ -120,21 +205,696 @@ func TestWallet_SendFunds(t *testing.T) {
 	}
 }
 
+func TestWallet_RefreshAssetRegistry(t *testing.T) {
+	seed := walletseed.NewSeed()
+	mockedConnector := newMockConnector(&Output{
+		Address:  seed.Address(0),
+		OutputID: ledgerstate.NewOutputID(ledgerstate.TransactionID{1}, 0),
+		Balances: ledgerstate.NewColoredBalances(map[ledgerstate.Color]uint64{
+			ledgerstate.ColorIOTA: 1337,
+			{3}:                   1338,
+			{4}:                   1339,
+		}),
+		InclusionState: InclusionState{
+			Liked:     true,
+			Confirmed: true,
+		},
+	})
+	assetRegistry := NewAssetRegistry()
+	assetRegistry.RegisterAsset(ledgerstate.Color{4}, Asset{Color: ledgerstate.Color{4}, Name: "local"})
+	wallet := New(Import(seed, 0, []bitmask.BitMask{}, assetRegistry), GenericConnector(mockedConnector))
+
+	require.NoError(t, wallet.Refresh())
+	require.NoError(t, wallet.RefreshAssetRegistry())
+
+	asset, assetExists := wallet.AssetRegistry().Asset(ledgerstate.Color{3})
+	require.True(t, assetExists)
+	assert.Equal(t, uint64(1000000), asset.Amount)
+	assert.Equal(t, ledgerstate.TransactionID{3}, asset.MintingTransactionID)
+	assert.Equal(t, ledgerstate.Color{3}.String(), wallet.AssetRegistry().Name(ledgerstate.Color{3}))
+	assert.Equal(t, "local", wallet.AssetRegistry().Name(ledgerstate.Color{4}))
+	_, assetExists = wallet.AssetRegistry().Asset(ledgerstate.ColorIOTA)
+	assert.False(t, assetExists)
+}
+
+func TestWallet_CreateAsset(t *testing.T) {
+	minterSeed := walletseed.NewSeed()
+	mockedConnector := newMockConnector(&Output{
+		Address:  minterSeed.Address(0),
+		OutputID: ledgerstate.NewOutputID(ledgerstate.TransactionID{1}, 0),
+		Balances: ledgerstate.NewColoredBalances(map[ledgerstate.Color]uint64{
+			ledgerstate.ColorIOTA: 1337,
+		}),
+		InclusionState: InclusionState{
+			Liked:     true,
+			Confirmed: true,
+		},
+	})
+	minterWallet := New(Import(minterSeed, 0, []bitmask.BitMask{}, NewAssetRegistry()), GenericConnector(mockedConnector))
+	require.NoError(t, minterWallet.Refresh())
+
+	assetColor, err := minterWallet.CreateAsset(Asset{Name: "Test Token", Symbol: "TT", Precision: 2, Amount: 1000})
+	require.NoError(t, err)
+
+	// the published metadata is signed by the address that holds the minted tokens
+	assetMetadata, assetMetadataPublished := mockedConnector.assetMetadata[assetColor]
+	require.True(t, assetMetadataPublished)
+	assert.Equal(t, "Test Token", assetMetadata.Name())
+	assert.Equal(t, uint8(2), assetMetadata.Decimals())
+	assert.True(t, assetMetadata.SignatureValid(minterSeed.Address(0).Address()))
+
+	// other wallets resolve the asset through the network
+	receiverSeed := walletseed.NewSeed()
+	mockedConnector.outputs[receiverSeed.Address(0)] = map[ledgerstate.OutputID]*Output{
+		ledgerstate.NewOutputID(ledgerstate.TransactionID{2}, 0): {
+			Address:  receiverSeed.Address(0),
+			OutputID: ledgerstate.NewOutputID(ledgerstate.TransactionID{2}, 0),
+			Balances: ledgerstate.NewColoredBalances(map[ledgerstate.Color]uint64{
+				assetColor: 100,
+			}),
+			InclusionState: InclusionState{
+				Liked:     true,
+				Confirmed: true,
+			},
+		},
+	}
+	receiverWallet := New(Import(receiverSeed, 0, []bitmask.BitMask{}, NewAssetRegistry()), GenericConnector(mockedConnector))
+	require.NoError(t, receiverWallet.Refresh())
+	require.NoError(t, receiverWallet.RefreshAssetRegistry())
+	assert.Equal(t, "Test Token", receiverWallet.AssetRegistry().Name(assetColor))
+	assert.Equal(t, "TT", receiverWallet.AssetRegistry().Symbol(assetColor))
+	assert.Equal(t, 2, receiverWallet.AssetRegistry().Precision(assetColor))
+}
+
+func TestWallet_PrepareTransaction(t *testing.T) {
+	senderSeed := walletseed.NewSeed()
+	receiverSeed := walletseed.NewSeed()
+
+	mockedConnector := newMockConnector(
+		&Output{
+			Address:  senderSeed.Address(0),
+			OutputID: ledgerstate.NewOutputID(ledgerstate.TransactionID{1}, 0),
+			Balances: ledgerstate.NewColoredBalances(map[ledgerstate.Color]uint64{ledgerstate.ColorIOTA: 1337}),
+			InclusionState: InclusionState{
+				Liked:     true,
+				Confirmed: true,
+			},
+		},
+		&Output{
+			Address:  senderSeed.Address(0),
+			OutputID: ledgerstate.NewOutputID(ledgerstate.TransactionID{2}, 0),
+			Balances: ledgerstate.NewColoredBalances(map[ledgerstate.Color]uint64{ledgerstate.ColorIOTA: 663}),
+			InclusionState: InclusionState{
+				Liked:     true,
+				Confirmed: true,
+			},
+		},
+	)
+	onlineWallet := New(Import(senderSeed, 0, []bitmask.BitMask{}, NewAssetRegistry()), GenericConnector(mockedConnector))
+	offlineWallet := New(Import(senderSeed, 0, []bitmask.BitMask{}, NewAssetRegistry()), GenericConnector(newMockConnector()))
+
+	partiallySignedTransaction, err := onlineWallet.PrepareTransaction(Destination(receiverSeed.Address(0), 2000))
+	require.NoError(t, err)
+	assert.Equal(t, 0, partiallySignedTransaction.SignedInputs())
+	_, err = onlineWallet.SubmitTransaction(partiallySignedTransaction)
+	assert.Error(t, err)
+
+	// sign the exported transaction with a wallet that is not able to see the outputs
+	exportedTransaction, err := PartiallySignedTransactionFromBytes(partiallySignedTransaction.Bytes())
+	require.NoError(t, err)
+	signedInputs, err := offlineWallet.SignTransaction(exportedTransaction)
+	require.NoError(t, err)
+	assert.Equal(t, 2, signedInputs)
+	assert.True(t, exportedTransaction.Complete())
+
+	signedTransaction, err := PartiallySignedTransactionFromBytes(exportedTransaction.Bytes())
+	require.NoError(t, err)
+	tx, err := onlineWallet.SubmitTransaction(signedTransaction)
+	require.NoError(t, err)
+	assert.Equal(t, ledgerstate.SignatureUnlockBlockType, tx.UnlockBlocks()[0].Type())
+	assert.Equal(t, ledgerstate.ReferenceUnlockBlockType, tx.UnlockBlocks()[1].Type())
+}
+
+func TestPartiallySignedTransaction_Combine(t *testing.T) {
+	seeds := []*walletseed.Seed{walletseed.NewSeed(), walletseed.NewSeed()}
+	consumedOutputs := make(ledgerstate.Outputs, len(seeds))
+	for i, seed := range seeds {
+		consumedOutputs[i] = ledgerstate.NewSigLockedSingleOutput(100, seed.Address(0).Address())
+		consumedOutputs[i].SetID(ledgerstate.NewOutputID(ledgerstate.TransactionID{byte(i + 1)}, 0))
+	}
+	essence := ledgerstate.NewTransactionEssence(0, time.Now(), identity.ID{}, identity.ID{},
+		ledgerstate.NewInputs(consumedOutputs[0].Input(), consumedOutputs[1].Input()),
+		ledgerstate.NewOutputs(ledgerstate.NewSigLockedSingleOutput(200, walletseed.NewSeed().Address(0).Address())),
+	)
+	partiallySignedTransaction, err := NewPartiallySignedTransaction(essence, consumedOutputs)
+	require.NoError(t, err)
+
+	// every party signs its own copy
+	signedCopies := make([]*PartiallySignedTransaction, len(seeds))
+	for i, seed := range seeds {
+		signedCopies[i], err = PartiallySignedTransactionFromBytes(partiallySignedTransaction.Bytes())
+		require.NoError(t, err)
+
+		signedInputs, signErr := New(Import(seed, 0, []bitmask.BitMask{}, NewAssetRegistry()), GenericConnector(newMockConnector())).SignTransaction(signedCopies[i])
+		require.NoError(t, signErr)
+		assert.Equal(t, 1, signedInputs)
+		assert.False(t, signedCopies[i].Complete())
+	}
+
+	require.NoError(t, signedCopies[0].Combine(signedCopies[1]))
+	assert.True(t, signedCopies[0].Complete())
+	tx, err := signedCopies[0].Transaction()
+	require.NoError(t, err)
+	consumedOutputsByID := consumedOutputs.ByID()
+	for i, input := range tx.Essence().Inputs() {
+		consumedOutput := consumedOutputsByID[input.(*ledgerstate.UTXOInput).ReferencedOutputID()]
+		require.Equal(t, ledgerstate.SignatureUnlockBlockType, tx.UnlockBlocks()[i].Type())
+		assert.True(t, tx.UnlockBlocks()[i].(*ledgerstate.SignatureUnlockBlock).AddressSignatureValid(consumedOutput.Address(), essence.Bytes()))
+	}
+
+	// signatures for a different essence can not be combined
+	otherEssence := ledgerstate.NewTransactionEssence(0, time.Now().Add(time.Second), identity.ID{}, identity.ID{}, essence.Inputs(), essence.Outputs())
+	otherTransaction, err := NewPartiallySignedTransaction(otherEssence, consumedOutputs)
+	require.NoError(t, err)
+	assert.Error(t, otherTransaction.Combine(signedCopies[1]))
+}
+
+func TestPartiallySignedTransaction_AliasAndThresholdInputs(t *testing.T) {
+	stateController := walletseed.NewSeed()
+	coSigners := []*walletseed.Seed{walletseed.NewSeed(), walletseed.NewSeed(), walletseed.NewSeed()}
+	publicKeys := make([]ed25519.PublicKey, len(coSigners))
+	for i, coSigner := range coSigners {
+		publicKeys[i] = coSigner.KeyPair(0).PublicKey
+	}
+	thresholdAddress, err := ledgerstate.NewThresholdAddress(2, publicKeys...)
+	require.NoError(t, err)
+
+	aliasOutput, err := ledgerstate.NewAliasOutputMint(ledgerstate.NewColoredBalances(map[ledgerstate.Color]uint64{ledgerstate.ColorIOTA: 100}), stateController.Address(0).Address(), walletseed.NewSeed().Address(0).Address(), nil)
+	require.NoError(t, err)
+	aliasOutput = aliasOutput.SetID(ledgerstate.NewOutputID(ledgerstate.TransactionID{1}, 0)).(*ledgerstate.AliasOutput)
+	consumedOutputs := ledgerstate.Outputs{
+		aliasOutput,
+		ledgerstate.NewSigLockedSingleOutput(100, aliasOutput.AliasAddress()).SetID(ledgerstate.NewOutputID(ledgerstate.TransactionID{2}, 0)),
+		ledgerstate.NewSigLockedSingleOutput(100, thresholdAddress).SetID(ledgerstate.NewOutputID(ledgerstate.TransactionID{3}, 0)),
+		ledgerstate.NewSigLockedSingleOutput(100, thresholdAddress).SetID(ledgerstate.NewOutputID(ledgerstate.TransactionID{4}, 0)),
+	}
+	essence := ledgerstate.NewTransactionEssence(0, time.Now(), identity.ID{}, identity.ID{},
+		consumedOutputs.Inputs(),
+		ledgerstate.NewOutputs(aliasOutput.NewAliasOutputNext(false), ledgerstate.NewSigLockedSingleOutput(300, walletseed.NewSeed().Address(0).Address())),
+	)
+	partiallySignedTransaction, err := NewPartiallySignedTransaction(essence, consumedOutputs)
+	require.NoError(t, err)
+	assert.Equal(t, stateController.Address(0).Address().Bytes(), partiallySignedTransaction.UnlockAddress(0).Bytes())
+
+	// the state controller unlocks the AliasOutput and the funds that are owned by the alias
+	signedInputs, err := New(Import(stateController, 1, []bitmask.BitMask{}, NewAssetRegistry()), GenericConnector(newMockConnector())).SignTransaction(partiallySignedTransaction)
+	require.NoError(t, err)
+	assert.Equal(t, 2, signedInputs)
+
+	// the co-signers need to know the public keys of the ThresholdAddress
+	assert.Error(t, partiallySignedTransaction.SetThresholdPublicKeys(2, 1, publicKeys...))
+	require.NoError(t, partiallySignedTransaction.SetThresholdPublicKeys(2, 2, publicKeys...))
+	for _, coSigner := range coSigners[:2] {
+		partiallySignedTransaction, err = PartiallySignedTransactionFromBytes(partiallySignedTransaction.Bytes())
+		require.NoError(t, err)
+		assert.False(t, partiallySignedTransaction.Complete())
+
+		signedInputs, err = partiallySignedTransaction.Sign(coSigner.KeyPair(0))
+		require.NoError(t, err)
+		assert.Equal(t, 2, signedInputs)
+	}
+	assert.True(t, partiallySignedTransaction.Complete())
+
+	tx, err := partiallySignedTransaction.Transaction()
+	require.NoError(t, err)
+	assert.Equal(t, ledgerstate.SignatureUnlockBlockType, tx.UnlockBlocks()[0].Type())
+	assert.Equal(t, ledgerstate.AliasUnlockBlockType, tx.UnlockBlocks()[1].Type())
+	assert.Equal(t, ledgerstate.ThresholdSignatureUnlockBlockType, tx.UnlockBlocks()[2].Type())
+	assert.Equal(t, ledgerstate.ReferenceUnlockBlockType, tx.UnlockBlocks()[3].Type())
+	for i, consumedOutput := range consumedOutputs {
+		unlockBlock := tx.UnlockBlocks()[i]
+		if referenceUnlockBlock, isReferenceUnlockBlock := unlockBlock.(*ledgerstate.ReferenceUnlockBlock); isReferenceUnlockBlock {
+			unlockBlock = tx.UnlockBlocks()[referenceUnlockBlock.ReferencedIndex()]
+		}
+		unlockValid, unlockErr := consumedOutput.UnlockValid(tx, unlockBlock, consumedOutputs)
+		require.NoError(t, unlockErr)
+		assert.True(t, unlockValid)
+	}
+
+	// funds of an alias can not be spent without consuming the AliasOutput
+	_, err = NewPartiallySignedTransaction(ledgerstate.NewTransactionEssence(0, time.Now(), identity.ID{}, identity.ID{},
+		ledgerstate.NewInputs(consumedOutputs[1].Input()),
+		ledgerstate.NewOutputs(ledgerstate.NewSigLockedSingleOutput(100, walletseed.NewSeed().Address(0).Address())),
+	), consumedOutputs)
+	assert.Error(t, err)
+}
+
+func TestPartiallySignedTransaction_ExtendedLockedInputs(t *testing.T) {
+	owner, fallback := walletseed.NewSeed(), walletseed.NewSeed()
+	now := time.Now()
+
+	consumedOutputs := ledgerstate.Outputs{
+		ledgerstate.NewExtendedLockedOutput(ledgerstate.NewColoredBalances(map[ledgerstate.Color]uint64{ledgerstate.ColorIOTA: 100}), owner.Address(0).Address()).
+			WithFallbackOptions(fallback.Address(0).Address(), now.Add(-time.Minute)).
+			SetID(ledgerstate.NewOutputID(ledgerstate.TransactionID{1}, 0)),
+		ledgerstate.NewExtendedLockedOutput(ledgerstate.NewColoredBalances(map[ledgerstate.Color]uint64{ledgerstate.ColorIOTA: 100}), fallback.Address(0).Address()).
+			WithTimeLock(now.Add(-time.Minute)).
+			SetID(ledgerstate.NewOutputID(ledgerstate.TransactionID{2}, 0)),
+	}
+	essence := ledgerstate.NewTransactionEssence(0, now, identity.ID{}, identity.ID{},
+		consumedOutputs.Inputs(),
+		ledgerstate.NewOutputs(ledgerstate.NewSigLockedSingleOutput(200, walletseed.NewSeed().Address(0).Address())),
+	)
+	partiallySignedTransaction, err := NewPartiallySignedTransaction(essence, consumedOutputs)
+	require.NoError(t, err)
+
+	// the fallback took over at the timestamp of the essence, so the owner can not sign anymore
+	assert.Equal(t, fallback.Address(0).Address().Bytes(), partiallySignedTransaction.UnlockAddress(0).Bytes())
+	signedInputs, err := partiallySignedTransaction.Sign(owner.KeyPair(0))
+	require.NoError(t, err)
+	assert.Equal(t, 0, signedInputs)
+	signedInputs, err = partiallySignedTransaction.Sign(fallback.KeyPair(0))
+	require.NoError(t, err)
+	assert.Equal(t, 2, signedInputs)
+
+	tx, err := partiallySignedTransaction.Transaction()
+	require.NoError(t, err)
+	assert.Equal(t, ledgerstate.ReferenceUnlockBlockType, tx.UnlockBlocks()[1].Type())
+	unlockValid, err := consumedOutputs[0].UnlockValid(tx, tx.UnlockBlocks()[0], consumedOutputs)
+	require.NoError(t, err)
+	assert.True(t, unlockValid)
+
+	// inputs that are still timelocked at the timestamp of the essence are rejected
+	_, err = NewPartiallySignedTransaction(ledgerstate.NewTransactionEssence(0, now.Add(-time.Hour), identity.ID{}, identity.ID{}, essence.Inputs(), essence.Outputs()), consumedOutputs)
+	assert.True(t, xerrors.Is(err, ErrOutputNotUnlockable))
+}
+
+func TestThresholdTransaction(t *testing.T) {
+	coSigners := []*walletseed.Seed{walletseed.NewSeed(), walletseed.NewSeed(), walletseed.NewSeed()}
+	publicKeys := make([]ed25519.PublicKey, len(coSigners))
+	for i, coSigner := range coSigners {
+		publicKeys[i] = coSigner.KeyPair(0).PublicKey
+	}
+	thresholdAddress, err := ledgerstate.NewThresholdAddress(2, publicKeys...)
+	require.NoError(t, err)
+
+	consumedOutputs := ledgerstate.Outputs{
+		ledgerstate.NewSigLockedSingleOutput(100, thresholdAddress).SetID(ledgerstate.NewOutputID(ledgerstate.TransactionID{1}, 0)),
+		ledgerstate.NewSigLockedSingleOutput(100, thresholdAddress).SetID(ledgerstate.NewOutputID(ledgerstate.TransactionID{2}, 0)),
+	}
+	essence := ledgerstate.NewTransactionEssence(0, time.Now(), identity.ID{}, identity.ID{},
+		consumedOutputs.Inputs(),
+		ledgerstate.NewOutputs(ledgerstate.NewSigLockedSingleOutput(200, walletseed.NewSeed().Address(0).Address())),
+	)
+	thresholdTransaction, err := NewThresholdTransaction(essence, consumedOutputs, 2, publicKeys...)
+	require.NoError(t, err)
+
+	// Outputs of other addresses can not be spent with the signatures of the co-signers
+	mixedOutputs := ledgerstate.Outputs{
+		consumedOutputs[0],
+		ledgerstate.NewSigLockedSingleOutput(100, coSigners[0].Address(0).Address()).SetID(ledgerstate.NewOutputID(ledgerstate.TransactionID{2}, 0)),
+	}
+	_, err = NewThresholdTransaction(essence, mixedOutputs, 2, publicKeys...)
+	assert.Error(t, err)
+	assert.Equal(t, thresholdAddress.Digest(), thresholdTransaction.Address().Digest())
+
+	// the first co-signer signs and hands the partially signed transaction to the next one
+	require.NoError(t, thresholdTransaction.Sign(coSigners[0].KeyPair(0)))
+	assert.False(t, thresholdTransaction.Complete())
+	_, err = thresholdTransaction.Transaction()
+	assert.Error(t, err)
+	assert.Error(t, thresholdTransaction.Sign(walletseed.NewSeed().KeyPair(0)))
+
+	restoredThresholdTransaction, err := ThresholdTransactionFromBytes(thresholdTransaction.Bytes())
+	require.NoError(t, err)
+	coSignerWallet := New(Import(coSigners[2], 1, []bitmask.BitMask{}, NewAssetRegistry()), GenericConnector(newMockConnector()))
+	require.NoError(t, coSignerWallet.SignThresholdTransaction(restoredThresholdTransaction, 0))
+	assert.True(t, restoredThresholdTransaction.Complete())
+
+	tx, err := restoredThresholdTransaction.Transaction()
+	require.NoError(t, err)
+	require.Len(t, tx.UnlockBlocks(), 2)
+	unlockBlock := tx.UnlockBlocks()[0].(*ledgerstate.ThresholdSignatureUnlockBlock)
+	assert.True(t, unlockBlock.AddressSignatureValid(thresholdAddress, tx.Essence().Bytes()))
+	assert.Equal(t, ledgerstate.ReferenceUnlockBlockType, tx.UnlockBlocks()[1].Type())
+}
+
+func TestWallet_WaitForConfirmation(t *testing.T) {
+	senderSeed := walletseed.NewSeed()
+	receiverSeed := walletseed.NewSeed()
+
+	// connectors that can not be notified about confirmations do not support waiting for transactions
+	mockedConnector := newMockConnector()
+	senderWallet := New(Import(senderSeed, 0, []bitmask.BitMask{}, NewAssetRegistry()), GenericConnector(mockedConnector))
+	require.NoError(t, senderWallet.RequestFaucetFunds(true))
+	_, err := senderWallet.SendFunds(Destination(receiverSeed.Address(0), 100), WaitForConfirmation())
+	assert.True(t, xerrors.Is(err, ErrConfirmationNotSupported))
+
+	// connectors that implement the ConfirmationAwaiter get notified instead of polling
+	awaitingConnector := &mockConfirmationAwaiter{mockConnector: newMockConnector()}
+	senderWallet = New(Import(senderSeed, 0, []bitmask.BitMask{}, NewAssetRegistry()), GenericConnector(awaitingConnector))
+	require.NoError(t, senderWallet.RequestFaucetFunds(true))
+	assert.Equal(t, []walletaddr.Address{senderSeed.Address(0)}, awaitingConnector.awaitedAddresses)
+	require.NoError(t, senderWallet.Refresh())
+
+	tx, err := senderWallet.SendFunds(Destination(receiverSeed.Address(0), 100), WaitForConfirmation())
+	require.NoError(t, err)
+	assert.Equal(t, []ledgerstate.TransactionID{tx.ID()}, awaitingConnector.awaitedTransactions)
+
+	// rejections are reported to the caller
+	require.NoError(t, senderWallet.RequestFaucetFunds(true))
+	require.NoError(t, senderWallet.Refresh())
+	awaitingConnector.reject = true
+	_, err = senderWallet.SendFunds(Destination(receiverSeed.Address(0), 100), WaitForConfirmation())
+	assert.True(t, xerrors.Is(err, ErrTransactionRejected))
+}
+
+func TestWallet_ProcessPendingTransactions(t *testing.T) {
+	senderSeed := walletseed.NewSeed()
+	receiverSeed := walletseed.NewSeed()
+
+	mockedConnector := newMockConnector(
+		&Output{
+			Address:        senderSeed.Address(0),
+			OutputID:       ledgerstate.NewOutputID(ledgerstate.TransactionID{1}, 0),
+			Balances:       ledgerstate.NewColoredBalances(map[ledgerstate.Color]uint64{ledgerstate.ColorIOTA: 1337}),
+			InclusionState: InclusionState{Liked: true, Confirmed: true},
+		},
+		&Output{
+			Address:        senderSeed.Address(1),
+			OutputID:       ledgerstate.NewOutputID(ledgerstate.TransactionID{2}, 0),
+			Balances:       ledgerstate.NewColoredBalances(map[ledgerstate.Color]uint64{ledgerstate.ColorIOTA: 1337}),
+			InclusionState: InclusionState{Liked: true, Confirmed: true},
+		},
+	)
+	senderWallet := New(Import(senderSeed, 1, []bitmask.BitMask{}, NewAssetRegistry()), GenericConnector(mockedConnector), Reattachment(0, 1))
+
+	tx, err := senderWallet.SendFunds(Destination(receiverSeed.Address(0), 100))
+	require.NoError(t, err)
+	require.Len(t, senderWallet.PendingTransactions(), 1)
+	assert.Equal(t, PendingTransactionPending, senderWallet.PendingTransactions()[0].State)
+
+	// transactions that got lost are reattached
+	delete(mockedConnector.inclusionStates, tx.ID())
+	require.NoError(t, senderWallet.ProcessPendingTransactions())
+	assert.Equal(t, 2, mockedConnector.sentMessages)
+	assert.Equal(t, 1, senderWallet.pendingTransactionManager.PendingTransaction(tx.ID()).Reattachments)
+
+	// rejected transactions are rebuilt with fresh inputs
+	conflictingTransactionID := ledgerstate.TransactionID{3}
+	mockedConnector.inclusionStates[tx.ID()] = &mockInclusionState{
+		inclusionState:            InclusionState{Rejected: true, Conflicting: true},
+		conflictingTransactionIDs: []ledgerstate.TransactionID{conflictingTransactionID},
+	}
+	require.NoError(t, senderWallet.ProcessPendingTransactions())
+	rejectedTransaction := senderWallet.pendingTransactionManager.PendingTransaction(tx.ID())
+	assert.Equal(t, PendingTransactionReissued, rejectedTransaction.State)
+	assert.Equal(t, []ledgerstate.TransactionID{conflictingTransactionID}, rejectedTransaction.ConflictingTransactionIDs)
+
+	reissuedTransaction := senderWallet.pendingTransactionManager.PendingTransaction(rejectedTransaction.ReissuedTransactionID)
+	require.NotNil(t, reissuedTransaction)
+	assert.NotEqual(t, tx.Essence().Inputs().Bytes(), reissuedTransaction.Transaction.Essence().Inputs().Bytes())
+
+	// the pending transactions survive a restart of the wallet
+	restoredManager, _, err := ParsePendingTransactionManager(marshalutil.New(senderWallet.PendingTransactionManager().Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, senderWallet.PendingTransactionManager().Bytes(), restoredManager.Bytes())
+	assert.True(t, restoredManager.PendingTransaction(reissuedTransaction.Transaction.ID()).Reissuable())
+
+	// final states are reported once
+	mockedConnector.inclusionStates[reissuedTransaction.Transaction.ID()].inclusionState = InclusionState{Liked: true, Confirmed: true}
+	require.NoError(t, senderWallet.ProcessPendingTransactions())
+	require.Len(t, senderWallet.PendingTransactions(), 1)
+	assert.Equal(t, PendingTransactionConfirmed, senderWallet.PendingTransactions()[0].State)
+	require.NoError(t, senderWallet.ProcessPendingTransactions())
+	assert.Empty(t, senderWallet.PendingTransactions())
+}
+
+func TestWallet_ProcessOutdatedPendingTransactions(t *testing.T) {
+	senderSeed := walletseed.NewSeed()
+	receiverSeed := walletseed.NewSeed()
+
+	mockedConnector := newMockConnector(
+		&Output{
+			Address:        senderSeed.Address(0),
+			OutputID:       ledgerstate.NewOutputID(ledgerstate.TransactionID{1}, 0),
+			Balances:       ledgerstate.NewColoredBalances(map[ledgerstate.Color]uint64{ledgerstate.ColorIOTA: 1337}),
+			InclusionState: InclusionState{Liked: true, Confirmed: true},
+		},
+	)
+	senderWallet := New(Import(senderSeed, 1, []bitmask.BitMask{}, NewAssetRegistry()), GenericConnector(mockedConnector), Reattachment(0, 5))
+
+	tx, err := senderWallet.SendFunds(Destination(receiverSeed.Address(0), 100))
+	require.NoError(t, err)
+	pendingTransaction := senderWallet.pendingTransactionManager.PendingTransaction(tx.ID())
+
+	// failed reattachments are not counted
+	delete(mockedConnector.inclusionStates, tx.ID())
+	mockedConnector.sendErr = xerrors.New("message rejected")
+	require.NoError(t, senderWallet.ProcessPendingTransactions())
+	assert.Equal(t, 0, pendingTransaction.Reattachments)
+	assert.Equal(t, "message rejected", pendingTransaction.Error)
+	mockedConnector.sendErr = nil
+
+	// transactions that are too old to be reattached are rebuilt with the same inputs and a fresh essence
+	keyPair := senderSeed.KeyPair(0)
+	outdatedEssence := ledgerstate.NewTransactionEssence(0, time.Now().Add(-tangle.MaxReattachmentTimeMin-time.Minute), identity.ID{}, identity.ID{}, tx.Essence().Inputs(), tx.Essence().Outputs())
+	outdatedTransaction := ledgerstate.NewTransaction(outdatedEssence, ledgerstate.UnlockBlocks{
+		ledgerstate.NewSignatureUnlockBlock(ledgerstate.NewED25519Signature(keyPair.PublicKey, keyPair.PrivateKey.Sign(outdatedEssence.Bytes()))),
+	})
+	senderWallet.pendingTransactionManager.add(outdatedTransaction, pendingTransaction.sendFundsOptions)
+
+	require.NoError(t, senderWallet.ProcessPendingTransactions())
+	outdatedPendingTransaction := senderWallet.pendingTransactionManager.PendingTransaction(outdatedTransaction.ID())
+	assert.Equal(t, PendingTransactionReissued, outdatedPendingTransaction.State)
+
+	refreshedTransaction := senderWallet.pendingTransactionManager.PendingTransaction(outdatedPendingTransaction.ReissuedTransactionID)
+	require.NotNil(t, refreshedTransaction)
+	assert.Equal(t, outdatedEssence.Inputs().Bytes(), refreshedTransaction.Transaction.Essence().Inputs().Bytes())
+	assert.WithinDuration(t, time.Now(), refreshedTransaction.Transaction.Essence().Timestamp(), time.Minute)
+	signatureUnlockBlock := refreshedTransaction.Transaction.UnlockBlocks()[0].(*ledgerstate.SignatureUnlockBlock)
+	assert.True(t, signatureUnlockBlock.AddressSignatureValid(senderSeed.Address(0).Address(), refreshedTransaction.Transaction.Essence().Bytes()))
+}
+
+func TestWallet_ExtendedLockedOutputs(t *testing.T) {
+	senderSeed := walletseed.NewSeed()
+	receiverSeed := walletseed.NewSeed()
+	otherAddress := walletseed.NewSeed().Address(0).Address()
+
+	extendedLockedOutput := func(outputID ledgerstate.OutputID, addr ledgerstate.Address) *ledgerstate.ExtendedLockedOutput {
+		output := ledgerstate.NewExtendedLockedOutput(ledgerstate.NewColoredBalances(map[ledgerstate.Color]uint64{ledgerstate.ColorIOTA: 1000}), addr)
+		output.SetID(outputID)
+		return output
+	}
+	timelockedOutputID := ledgerstate.NewOutputID(ledgerstate.TransactionID{1}, 0)
+	expiredOutputID := ledgerstate.NewOutputID(ledgerstate.TransactionID{2}, 0)
+	fallbackOutputID := ledgerstate.NewOutputID(ledgerstate.TransactionID{3}, 0)
+
+	mockedConnector := newMockConnector(
+		&Output{
+			Address:        senderSeed.Address(0),
+			OutputID:       timelockedOutputID,
+			Balances:       ledgerstate.NewColoredBalances(map[ledgerstate.Color]uint64{ledgerstate.ColorIOTA: 1000}),
+			InclusionState: InclusionState{Liked: true, Confirmed: true},
+			Object:         extendedLockedOutput(timelockedOutputID, senderSeed.Address(0).Address()).WithTimeLock(time.Now().Add(time.Hour)),
+		},
+		&Output{
+			Address:        senderSeed.Address(1),
+			OutputID:       expiredOutputID,
+			Balances:       ledgerstate.NewColoredBalances(map[ledgerstate.Color]uint64{ledgerstate.ColorIOTA: 1000}),
+			InclusionState: InclusionState{Liked: true, Confirmed: true},
+			Object:         extendedLockedOutput(expiredOutputID, senderSeed.Address(1).Address()).WithFallbackOptions(otherAddress, time.Now().Add(-time.Hour)),
+		},
+		&Output{
+			Address:        senderSeed.Address(2),
+			OutputID:       fallbackOutputID,
+			Balances:       ledgerstate.NewColoredBalances(map[ledgerstate.Color]uint64{ledgerstate.ColorIOTA: 1000}),
+			InclusionState: InclusionState{Liked: true, Confirmed: true},
+			Object:         extendedLockedOutput(fallbackOutputID, otherAddress).WithFallbackOptions(senderSeed.Address(2).Address(), time.Now().Add(-time.Hour)),
+		},
+	)
+	senderWallet := New(Import(senderSeed, 2, []bitmask.BitMask{}, NewAssetRegistry()), GenericConnector(mockedConnector))
+
+	// the output whose fallback took over belongs to the other address
+	confirmedBalance, _, err := senderWallet.Balance()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2000), confirmedBalance[ledgerstate.ColorIOTA])
+
+	// only the output that the wallet can unlock as the fallback is spendable
+	tx, err := senderWallet.SendFunds(Destination(receiverSeed.Address(0), 100))
+	require.NoError(t, err)
+	require.Len(t, tx.Essence().Inputs(), 1)
+	assert.Equal(t, fallbackOutputID, tx.Essence().Inputs()[0].(*ledgerstate.UTXOInput).ReferencedOutputID())
+	signatureUnlockBlock := tx.UnlockBlocks()[0].(*ledgerstate.SignatureUnlockBlock)
+	assert.True(t, signatureUnlockBlock.AddressSignatureValid(senderSeed.Address(2).Address(), tx.Essence().Bytes()))
+
+	_, err = senderWallet.SendFunds(Destination(receiverSeed.Address(0), 1000))
+	assert.Error(t, err)
+}
+
+func TestWallet_Accounts(t *testing.T) {
+	seed := walletseed.NewSeed()
+	receiverSeed := walletseed.NewSeed()
+	mockedConnector := newMockConnector()
+	wallet := New(ImportAccounts(NewAccountManager(seed), NewAssetRegistry()), GenericConnector(mockedConnector))
+
+	savingsAccount, err := wallet.CreateAccount("savings")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), savingsAccount.Index())
+	_, err = wallet.CreateAccount("savings")
+	assert.True(t, xerrors.Is(err, ErrAccountExists))
+
+	// the receive chain of the default account stays compatible with the addresses of legacy wallets
+	assert.Equal(t, seed.Address(0), wallet.ReceiveAddress())
+	assert.Equal(t, seed.Address(DerivationIndex(1, ReceiveChain, 0)), savingsAccount.ReceiveAddress())
+	account, chain, position := DerivationPath(savingsAccount.ChangeAddress().Index)
+	assert.Equal(t, uint32(1), account)
+	assert.Equal(t, ChangeChain, chain)
+	assert.Equal(t, uint64(0), position)
+
+	require.NoError(t, mockedConnector.RequestFaucetFunds(wallet.ReceiveAddress()))
+	require.NoError(t, mockedConnector.RequestFaucetFunds(savingsAccount.ReceiveAddress()))
+	require.NoError(t, wallet.Refresh())
+
+	confirmedBalance, _, err := wallet.Balance()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2674), confirmedBalance[ledgerstate.ColorIOTA])
+	confirmedBalance, _, err = wallet.Balance("savings")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1337), confirmedBalance[ledgerstate.ColorIOTA])
+	_, _, err = wallet.Balance("unknown")
+	assert.True(t, xerrors.Is(err, ErrAccountNotFound))
+
+	// the transfer only consumes the funds of the source account and sends the remainder to its change chain
+	tx, err := wallet.SendFunds(Destination(receiverSeed.Address(0), 100), SourceAccount("savings"))
+	require.NoError(t, err)
+	require.Len(t, tx.Essence().Inputs(), 1)
+	assert.True(t, savingsAccount.IsAddressSpent(savingsAccount.Addresses()[0]))
+	assert.False(t, wallet.AddressManager().IsAddressSpent(0))
+	remainderFound := false
+	for _, output := range tx.Essence().Outputs() {
+		if output.Address().Base58() == savingsAccount.ChangeAddress().Address().Base58() {
+			remainderFound = true
+		}
+	}
+	assert.True(t, remainderFound)
+
+	_, err = wallet.SendFunds(Destination(receiverSeed.Address(0), 100), SourceAccount("unknown"))
+	assert.True(t, xerrors.Is(err, ErrAccountNotFound))
+}
+
+func TestWallet_ExportState(t *testing.T) {
+	seed := walletseed.NewSeed()
+	wallet := New(ImportAccounts(NewAccountManager(seed), NewAssetRegistry()), GenericConnector(newMockConnector()))
+	_, err := wallet.CreateAccount("savings")
+	require.NoError(t, err)
+	wallet.NewReceiveAddress()
+	wallet.AddressManager().MarkAddressSpent(0)
+
+	accountManager, assetRegistry, err := ParseState(wallet.ExportState())
+	require.NoError(t, err)
+	assert.Equal(t, wallet.AccountManager().Bytes(), accountManager.Bytes())
+	assert.Equal(t, wallet.AssetRegistry().Bytes(), assetRegistry.Bytes())
+	assert.Equal(t, seed.Bytes(), accountManager.Seed().Bytes())
+	require.NotNil(t, accountManager.Account("savings"))
+	assert.True(t, accountManager.DefaultAccount().AddressManager(ReceiveChain).IsAddressSpent(0))
+
+	// the state of legacy wallets is upgraded to the default account
+	legacyState := marshalutil.New().
+		WriteBytes(seed.Bytes()).
+		WriteUint64(2).
+		WriteBytes(NewAssetRegistry().Bytes()).
+		WriteBytes([]byte{byte(bitmask.BitMask(0).SetBit(1))}).
+		Bytes()
+	accountManager, _, err = ParseState(legacyState)
+	require.NoError(t, err)
+	require.Len(t, accountManager.Accounts(), 1)
+	assert.Equal(t, DefaultAccountName, accountManager.DefaultAccount().Name())
+	receiveAddresses := accountManager.DefaultAccount().AddressManager(ReceiveChain)
+	assert.Equal(t, uint64(2), receiveAddresses.LastAddressIndex())
+	assert.True(t, receiveAddresses.IsAddressSpent(1))
+	assert.Equal(t, seed.Address(1), receiveAddresses.Address(1))
+}
+
+func TestWallet_RestoreAccounts(t *testing.T) {
+	seed := walletseed.NewSeed()
+
+	usedAddresses := []walletaddr.Address{
+		seed.Address(DerivationIndex(0, ReceiveChain, 0)),
+		seed.Address(DerivationIndex(0, ReceiveChain, 15)),
+		seed.Address(DerivationIndex(0, ReceiveChain, 40)),
+		seed.Address(DerivationIndex(0, ChangeChain, 3)),
+		seed.Address(DerivationIndex(1, ReceiveChain, 2)),
+		seed.Address(DerivationIndex(3, ReceiveChain, 0)),
+	}
+	outputs := make([]*Output, len(usedAddresses))
+	for i, usedAddress := range usedAddresses {
+		outputs[i] = &Output{
+			Address:        usedAddress,
+			OutputID:       ledgerstate.NewOutputID(ledgerstate.TransactionID{byte(i + 1)}, 0),
+			Balances:       ledgerstate.NewColoredBalances(map[ledgerstate.Color]uint64{ledgerstate.ColorIOTA: 1337}),
+			InclusionState: InclusionState{Liked: true, Confirmed: true},
+		}
+	}
+	wallet := New(ImportAccounts(NewAccountManager(seed), NewAssetRegistry()), GenericConnector(newMockConnector(outputs...)))
+
+	require.NoError(t, wallet.RestoreAccounts(20))
+
+	// the address at position 40 of the receive chain and account 3 are behind a gap that exceeds the gap limit
+	require.Len(t, wallet.Accounts(), 2)
+	assert.Equal(t, uint64(15), wallet.Accounts()[0].AddressManager(ReceiveChain).LastAddressIndex())
+	assert.Equal(t, uint64(3), wallet.Accounts()[0].AddressManager(ChangeChain).LastAddressIndex())
+	assert.Equal(t, "account-1", wallet.Accounts()[1].Name())
+	assert.Equal(t, uint64(2), wallet.Accounts()[1].AddressManager(ReceiveChain).LastAddressIndex())
+
+	confirmedBalance, _, err := wallet.Balance()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4*1337), confirmedBalance[ledgerstate.ColorIOTA])
+}
+
+type mockConfirmationAwaiter struct {
+	*mockConnector
+
+	reject              bool
+	awaitedAddresses    []walletaddr.Address
+	awaitedTransactions []ledgerstate.TransactionID
+}
+
+func (connector *mockConfirmationAwaiter) AwaitTransactionConfirmation(transactionID ledgerstate.TransactionID) (err error) {
+	connector.awaitedTransactions = append(connector.awaitedTransactions, transactionID)
+	if connector.reject {
+		return xerrors.Errorf("failed to confirm transaction with %s: %w", transactionID, ErrTransactionRejected)
+	}
+
+	return
+}
+
+func (connector *mockConfirmationAwaiter) AwaitAddressConfirmation(addr walletaddr.Address, trigger func() error) (err error) {
+	connector.awaitedAddresses = append(connector.awaitedAddresses, addr)
+
+	return trigger()
+}
+
 type mockConnector struct {
-	outputs map[address.Address]map[ledgerstate.OutputID]*Output
+	outputs         map[address.Address]map[ledgerstate.OutputID]*Output
+	assetMetadata   map[ledgerstate.Color]*assetregistry.AssetMetadata
+	inclusionStates map[ledgerstate.TransactionID]*mockInclusionState
+	sentMessages    int
+	sendErr         error
+}
+
+type mockInclusionState struct {
+	inclusionState            InclusionState
+	conflictingTransactionIDs []ledgerstate.TransactionID
 }
 
 func (connector *mockConnector) RequestFaucetFunds(addr walletaddr.Address) (err error) {
 	// generate random transaction id
-	idBytes := make([]byte, ledgerstate.OutputIDLength)
-	_, err = rand.Read(idBytes)
-	if err != nil {
-		return
-	}
-	outputID, _, err := ledgerstate.OutputIDFromBytes(idBytes)
-	if err != nil {
+	var transactionID ledgerstate.TransactionID
+	if _, err = rand.Read(transactionID[:]); err != nil {
 		return
 	}
+	outputID := ledgerstate.NewOutputID(transactionID, 0)
 
 	newOutput := &Output{
 		Address:  addr,
@@ -160,6 +920,14 @@ func (connector *mockConnector) RequestFaucetFunds(addr walletaddr.Address) (err
 }
 
 func (connector *mockConnector) SendTransaction(tx *ledgerstate.Transaction) (err error) {
+	connector.sentMessages++
+	if connector.sendErr != nil {
+		return connector.sendErr
+	}
+	if _, exists := connector.inclusionStates[tx.ID()]; !exists {
+		connector.inclusionStates[tx.ID()] = &mockInclusionState{}
+	}
+
 	// mark outputs as spent
 	//for _, input := range tx.Essence().Inputs() {
 	//if input.Type() == ledgerstate.UTXOInputType {
@@ -202,9 +970,42 @@ func (connector *mockConnector) SendTransaction(tx *ledgerstate.Transaction) (er
 	return
 }
 
+func (connector *mockConnector) TransactionInclusionState(transactionID ledgerstate.TransactionID) (inclusionState InclusionState, conflictingTransactionIDs []ledgerstate.TransactionID, err error) {
+	state, exists := connector.inclusionStates[transactionID]
+	if !exists {
+		err = xerrors.Errorf("failed to retrieve Transaction with %s: %w", transactionID, ErrTransactionNotFound)
+		return
+	}
+
+	return state.inclusionState, state.conflictingTransactionIDs, nil
+}
+
+func (connector *mockConnector) Asset(color ledgerstate.Color) (asset Asset, err error) {
+	asset = Asset{
+		Color:                color,
+		Amount:               1000000,
+		MintingTransactionID: ledgerstate.TransactionID(color),
+	}
+	if assetMetadata, assetMetadataExists := connector.assetMetadata[color]; assetMetadataExists {
+		asset.Name = assetMetadata.Name()
+		asset.Symbol = assetMetadata.Symbol()
+		asset.Precision = int(assetMetadata.Decimals())
+	}
+
+	return
+}
+
+func (connector *mockConnector) PublishAssetMetadata(assetMetadata *assetregistry.AssetMetadata) (err error) {
+	connector.assetMetadata[assetMetadata.Color()] = assetMetadata
+
+	return
+}
+
 func newMockConnector(outputs ...*Output) (connector *mockConnector) {
 	connector = &mockConnector{
-		outputs: make(map[address.Address]map[ledgerstate.OutputID]*Output),
+		outputs:         make(map[address.Address]map[ledgerstate.OutputID]*Output),
+		assetMetadata:   make(map[ledgerstate.Color]*assetregistry.AssetMetadata),
+		inclusionStates: make(map[ledgerstate.TransactionID]*mockInclusionState),
 	}
 
 	for _, output := range outputs {
@@ -218,6 +1019,15 @@ func newMockConnector(outputs ...*Output) (connector *mockConnector) {
 	return
 }
 
+func (connector *mockConnector) UsedAddresses(addresses ...walletaddr.Address) (usedAddresses map[walletaddr.Address]bool, err error) {
+	usedAddresses = make(map[walletaddr.Address]bool)
+	for _, addr := range addresses {
+		usedAddresses[addr] = len(connector.outputs[addr]) != 0
+	}
+
+	return
+}
+
 func (connector *mockConnector) UnspentOutputs(addresses ...walletaddr.Address) (outputs map[address.Address]map[ledgerstate.OutputID]*Output, err error) {
 	outputs = make(map[address.Address]map[ledgerstate.OutputID]*Output)
 	for _, addr := range addresses {
@@ -1,11 +1,20 @@
 package wallet
 
 import (
+	"time"
+
 	"github.com/iotaledger/goshimmer/client"
 	"github.com/iotaledger/goshimmer/client/wallet/packages/address"
+	"github.com/iotaledger/goshimmer/packages/assetregistry"
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	webapi_subscriptions "github.com/iotaledger/goshimmer/plugins/webapi/subscriptions"
+	webapi_value "github.com/iotaledger/goshimmer/plugins/webapi/value"
+	"golang.org/x/xerrors"
 )
 
+// confirmationTimeout defines how long the WebConnector waits for the confirmation of a Transaction.
+const confirmationTimeout = 5 * time.Minute
+
 // WebConnector implements a connector that uses the web API to connect to a node to implement the required functions
 // for the wallet.
 type WebConnector struct {
@@ -96,6 +105,11 @@ func (webConnector WebConnector) UnspentOutputs(addresses ...address.Address) (u
 					Spent:       false,
 				},
 			}
+			if output.Type == ledgerstate.ExtendedLockedOutputType.String() {
+				if walletOutput.Object, err = extendedLockedOutputFromJSON(outputID, balances, output); err != nil {
+					return
+				}
+			}
 
 			// store output in result
 			if _, addressExists := unspentOutputs[addr]; !addressExists {
@@ -108,6 +122,44 @@ func (webConnector WebConnector) UnspentOutputs(addresses ...address.Address) (u
 	return
 }
 
+// extendedLockedOutputFromJSON restores the unlock conditions of an ExtendedLockedOutput from its JSON representation.
+func extendedLockedOutputFromJSON(outputID ledgerstate.OutputID, balances *ledgerstate.ColoredBalances, output webapi_value.OutputID) (extendedLockedOutput *ledgerstate.ExtendedLockedOutput, err error) {
+	addr, err := ledgerstate.AddressFromBase58EncodedString(output.Address)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse address of %s: %w", outputID, err)
+	}
+
+	extendedLockedOutput = ledgerstate.NewExtendedLockedOutput(balances, addr)
+	if output.TimeLock != 0 {
+		extendedLockedOutput = extendedLockedOutput.WithTimeLock(time.Unix(output.TimeLock, 0))
+	}
+	if output.FallbackAddress != "" {
+		fallbackAddress, fallbackErr := ledgerstate.AddressFromBase58EncodedString(output.FallbackAddress)
+		if fallbackErr != nil {
+			return nil, xerrors.Errorf("failed to parse fallback address of %s: %w", outputID, fallbackErr)
+		}
+		extendedLockedOutput = extendedLockedOutput.WithFallbackOptions(fallbackAddress, time.Unix(output.FallbackDeadline, 0))
+	}
+	extendedLockedOutput.SetID(outputID)
+
+	return
+}
+
+// UsedAddresses returns which of the given addresses were used in any Transaction before (according to the address
+// history of the node).
+func (webConnector WebConnector) UsedAddresses(addresses ...address.Address) (usedAddresses map[address.Address]bool, err error) {
+	usedAddresses = make(map[address.Address]bool)
+	for _, addr := range addresses {
+		response, historyErr := webConnector.client.GetAddressHistory(addr.Address().Base58(), client.AddressHistoryPage("", 1))
+		if historyErr != nil {
+			return nil, historyErr
+		}
+		usedAddresses[addr] = len(response.Entries) > 0
+	}
+
+	return
+}
+
 // SendTransaction sends a new transaction to the network.
 func (webConnector WebConnector) SendTransaction(tx *ledgerstate.Transaction) (err error) {
 	_, err = webConnector.client.SendTransaction(tx.Bytes())
@@ -115,6 +167,139 @@ func (webConnector WebConnector) SendTransaction(tx *ledgerstate.Transaction) (e
 	return
 }
 
+// TransactionInclusionState returns the InclusionState of the given Transaction and the Transactions that it conflicts
+// with. It returns ErrTransactionNotFound if the node does not know the Transaction.
+func (webConnector WebConnector) TransactionInclusionState(transactionID ledgerstate.TransactionID) (inclusionState InclusionState, conflictingTransactionIDs []ledgerstate.TransactionID, err error) {
+	response, err := webConnector.client.GetTransactionByID(transactionID.Base58())
+	if err != nil {
+		if xerrors.Is(err, client.ErrNotFound) {
+			err = xerrors.Errorf("failed to retrieve Transaction with %s: %w", transactionID, ErrTransactionNotFound)
+		}
+
+		return
+	}
+
+	inclusionState = InclusionState{
+		Liked:       response.InclusionState.Liked,
+		Confirmed:   response.InclusionState.Confirmed,
+		Rejected:    response.InclusionState.Rejected,
+		Conflicting: response.InclusionState.Conflicting,
+	}
+
+	conflictingTransactionIDs = make([]ledgerstate.TransactionID, len(response.ConflictingTransactions))
+	for i, conflictingTransaction := range response.ConflictingTransactions {
+		if conflictingTransactionIDs[i], err = ledgerstate.TransactionIDFromBase58(conflictingTransaction); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Asset loads the supply information of the given color from the ledger and the metadata that was published by its
+// minter from the asset registry of the node.
+func (webConnector WebConnector) Asset(color ledgerstate.Color) (asset Asset, err error) {
+	response, err := webConnector.client.GetColor(color.Base58())
+	if err != nil {
+		return
+	}
+
+	mintingTransactionID, err := ledgerstate.TransactionIDFromBase58(response.Color.MintingTransactionID)
+	if err != nil {
+		return
+	}
+
+	asset = Asset{
+		Color:                color,
+		Amount:               response.Color.MintedSupply,
+		MintingTransactionID: mintingTransactionID,
+	}
+
+	assetResponse, err := webConnector.client.GetAsset(color.Base58())
+	if err != nil {
+		// the minter did not publish any metadata (yet)
+		if xerrors.Is(err, client.ErrNotFound) {
+			err = nil
+		}
+
+		return
+	}
+	asset.Name = assetResponse.Asset.Name
+	asset.Symbol = assetResponse.Asset.Symbol
+	asset.Precision = int(assetResponse.Asset.Decimals)
+	asset.URL = assetResponse.Asset.URL
+
+	return
+}
+
+// PublishAssetMetadata issues the given AssetMetadata payload, so other wallets can resolve the asset.
+func (webConnector WebConnector) PublishAssetMetadata(assetMetadata *assetregistry.AssetMetadata) (err error) {
+	_, err = webConnector.client.PublishAssetMetadata(assetMetadata)
+
+	return
+}
+
+// AwaitTransactionConfirmation subscribes to the state changes of the Transaction and blocks until it is confirmed or
+// rejected.
+func (webConnector WebConnector) AwaitTransactionConfirmation(transactionID ledgerstate.TransactionID) (err error) {
+	return webConnector.awaitConfirmation(func(subscription *client.Subscription) error {
+		return subscription.SubscribeTransactions(transactionID.Base58())
+	}, func(event webapi_subscriptions.Event) bool {
+		return event.TransactionID == transactionID.Base58() && event.Address == ""
+	})
+}
+
+// AwaitAddressConfirmation subscribes to the Transactions of the address, calls the trigger and blocks until one of
+// them is confirmed or rejected.
+func (webConnector WebConnector) AwaitAddressConfirmation(addr address.Address, trigger func() error) (err error) {
+	base58EncodedAddress := addr.Address().Base58()
+
+	return webConnector.awaitConfirmation(func(subscription *client.Subscription) error {
+		if subscribeErr := subscription.SubscribeAddresses(base58EncodedAddress); subscribeErr != nil {
+			return subscribeErr
+		}
+
+		return trigger()
+	}, func(event webapi_subscriptions.Event) bool {
+		return event.Address == base58EncodedAddress
+	})
+}
+
+// awaitConfirmation opens a Subscription, sets it up using the given function and waits for the first confirmed or
+// rejected event that matches the filter.
+func (webConnector WebConnector) awaitConfirmation(setup func(subscription *client.Subscription) error, filter func(event webapi_subscriptions.Event) bool) (err error) {
+	subscription, err := webConnector.client.Subscribe()
+	if err != nil {
+		return
+	}
+	defer subscription.Close()
+
+	if err = setup(subscription); err != nil {
+		return
+	}
+
+	timeout := time.After(confirmationTimeout)
+	for {
+		select {
+		case event, open := <-subscription.Events():
+			if !open {
+				return xerrors.New("lost the connection to the node while waiting for the confirmation")
+			}
+
+			switch {
+			case event.Type == webapi_subscriptions.EventError:
+				return xerrors.Errorf("failed to subscribe: %s", event.Error)
+			case event.Type == webapi_subscriptions.EventConfirmed && filter(event):
+				return
+			case event.Type == webapi_subscriptions.EventRejected && filter(event):
+				return xerrors.Errorf("failed to confirm transaction with %s: %w", event.TransactionID, ErrTransactionRejected)
+			}
+		case <-timeout:
+			return xerrors.Errorf("failed to receive confirmation within %v", confirmationTimeout)
+		}
+	}
+}
+
 // colorFromString is an internal utility method that parses the given string into a Color.
 func colorFromString(colorStr string) (color ledgerstate.Color) {
 	if colorStr == "IOTA" {
@@ -128,3 +313,6 @@ func colorFromString(colorStr string) (color ledgerstate.Color) {
 
 // Interface contract: make compiler warn if the interface is not implemented correctly.
 var _ Connector = &WebConnector{}
+
+// Interface contract: make compiler warn if the interface is not implemented correctly.
+var _ ConfirmationAwaiter = &WebConnector{}
@@ -0,0 +1,310 @@
+package assetregistry
+
+import (
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/goshimmer/packages/tangle/payload"
+	"github.com/iotaledger/hive.go/cerrors"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/hive.go/marshalutil"
+	"github.com/iotaledger/hive.go/stringify"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// ObjectName defines the name of the asset metadata object (payload).
+	ObjectName = "assetMetadata"
+
+	// MaxNameLength defines the maximum length of the name of an asset.
+	MaxNameLength = 64
+
+	// MaxSymbolLength defines the maximum length of the symbol of an asset.
+	MaxSymbolLength = 16
+
+	// MaxURLLength defines the maximum length of the URL of an asset.
+	MaxURLLength = 256
+)
+
+// Type represents the identifier for the AssetMetadata payload type.
+var Type payload.Type
+
+// init defers the initialization of the Type to not have an initialization loop.
+func init() {
+	Type = payload.NewType(4, ObjectName, PayloadUnmarshaler)
+}
+
+// region AssetMetadata ////////////////////////////////////////////////////////////////////////////////////////////////
+
+// AssetMetadata is a payload that allows the minter of a Color to publish the human readable information of the asset.
+// It is bound to the minting Transaction and signed by the key that controls the Output that minted the Color.
+type AssetMetadata struct {
+	color                ledgerstate.Color
+	mintingTransactionID ledgerstate.TransactionID
+	timestamp            time.Time
+	name                 string
+	symbol               string
+	decimals             uint8
+	url                  string
+	signature            ledgerstate.Signature
+}
+
+// NewAssetMetadata creates a new (unsigned) AssetMetadata payload for the Color that was minted by the given
+// Transaction.
+func NewAssetMetadata(color ledgerstate.Color, mintingTransactionID ledgerstate.TransactionID, timestamp time.Time, name string, symbol string, decimals uint8, url string) (assetMetadata *AssetMetadata, err error) {
+	assetMetadata = &AssetMetadata{
+		color:                color,
+		mintingTransactionID: mintingTransactionID,
+		timestamp:            timestamp,
+		name:                 name,
+		symbol:               symbol,
+		decimals:             decimals,
+		url:                  url,
+	}
+	if err = assetMetadata.checkLengths(); err != nil {
+		assetMetadata = nil
+	}
+
+	return
+}
+
+// FromBytes unmarshals an AssetMetadata payload from a sequence of bytes.
+func FromBytes(bytes []byte) (assetMetadata *AssetMetadata, consumedBytes int, err error) {
+	marshalUtil := marshalutil.New(bytes)
+	if assetMetadata, err = FromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse AssetMetadata from MarshalUtil: %w", err)
+		return
+	}
+	consumedBytes = marshalUtil.ReadOffset()
+
+	return
+}
+
+// FromMarshalUtil unmarshals an AssetMetadata payload using a MarshalUtil (for easier unmarshaling).
+func FromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (assetMetadata *AssetMetadata, err error) {
+	readStartOffset := marshalUtil.ReadOffset()
+
+	payloadSize, err := marshalUtil.ReadUint32()
+	if err != nil {
+		err = xerrors.Errorf("failed to parse payload size (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	payloadType, err := payload.TypeFromMarshalUtil(marshalUtil)
+	if err != nil {
+		err = xerrors.Errorf("failed to parse payload Type from MarshalUtil: %w", err)
+		return
+	}
+	if payloadType != Type {
+		err = xerrors.Errorf("payload type '%s' does not match expected '%s': %w", payloadType, Type, cerrors.ErrParseBytesFailed)
+		return
+	}
+
+	assetMetadata = &AssetMetadata{}
+	if assetMetadata.color, err = ledgerstate.ColorFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse Color from MarshalUtil: %w", err)
+		return
+	}
+	if assetMetadata.mintingTransactionID, err = ledgerstate.TransactionIDFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse minting TransactionID from MarshalUtil: %w", err)
+		return
+	}
+	if assetMetadata.timestamp, err = marshalUtil.ReadTime(); err != nil {
+		err = xerrors.Errorf("failed to parse timestamp (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	if assetMetadata.name, err = readString(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse name: %w", err)
+		return
+	}
+	if assetMetadata.symbol, err = readString(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse symbol: %w", err)
+		return
+	}
+	if assetMetadata.decimals, err = marshalUtil.ReadUint8(); err != nil {
+		err = xerrors.Errorf("failed to parse decimals (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	if assetMetadata.url, err = readString(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse URL: %w", err)
+		return
+	}
+	if err = assetMetadata.checkLengths(); err != nil {
+		err = xerrors.Errorf("%v: %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+
+	signed, err := marshalUtil.ReadBool()
+	if err != nil {
+		err = xerrors.Errorf("failed to parse signing status (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	if signed {
+		if assetMetadata.signature, err = ledgerstate.SignatureFromMarshalUtil(marshalUtil); err != nil {
+			err = xerrors.Errorf("failed to parse Signature from MarshalUtil: %w", err)
+			return
+		}
+	}
+
+	parsedBytes := marshalUtil.ReadOffset() - readStartOffset
+	if parsedBytes != int(payloadSize)+marshalutil.Uint32Size {
+		err = xerrors.Errorf("parsed bytes (%d) did not match expected size (%d): %w", parsedBytes, payloadSize, cerrors.ErrParseBytesFailed)
+		return
+	}
+
+	return
+}
+
+// PayloadUnmarshaler sets the generic unmarshaler.
+func PayloadUnmarshaler(data []byte) (payload payload.Payload, err error) {
+	payload, _, err = FromBytes(data)
+	if err != nil {
+		err = xerrors.Errorf("failed to unmarshal AssetMetadata payload from bytes: %w", err)
+	}
+
+	return
+}
+
+// Type returns the Type of the payload.
+func (a *AssetMetadata) Type() payload.Type {
+	return Type
+}
+
+// Color returns the Color that the AssetMetadata describes.
+func (a *AssetMetadata) Color() ledgerstate.Color {
+	return a.color
+}
+
+// MintingTransactionID returns the identifier of the Transaction that minted the Color.
+func (a *AssetMetadata) MintingTransactionID() ledgerstate.TransactionID {
+	return a.mintingTransactionID
+}
+
+// Timestamp returns the time at which the AssetMetadata was created by the minter. Newer AssetMetadata of the same
+// Color replaces older ones.
+func (a *AssetMetadata) Timestamp() time.Time {
+	return a.timestamp
+}
+
+// Name returns the name of the asset.
+func (a *AssetMetadata) Name() string {
+	return a.name
+}
+
+// Symbol returns the currency symbol of the asset.
+func (a *AssetMetadata) Symbol() string {
+	return a.symbol
+}
+
+// Decimals returns the amount of decimal places that are used to display the asset.
+func (a *AssetMetadata) Decimals() uint8 {
+	return a.decimals
+}
+
+// URL returns a link to further information about the asset.
+func (a *AssetMetadata) URL() string {
+	return a.url
+}
+
+// Signature returns the Signature of the minter (or nil if the AssetMetadata has not been signed, yet).
+func (a *AssetMetadata) Signature() ledgerstate.Signature {
+	return a.signature
+}
+
+// Sign signs the AssetMetadata with the given KeyPair.
+func (a *AssetMetadata) Sign(keyPair *ed25519.KeyPair) {
+	a.signature = ledgerstate.NewED25519Signature(keyPair.PublicKey, keyPair.PrivateKey.Sign(a.EssenceBytes()))
+}
+
+// SignatureValid returns true if the AssetMetadata was signed by the key that controls the given Address.
+func (a *AssetMetadata) SignatureValid(address ledgerstate.Address) bool {
+	return a.signature != nil && a.signature.AddressSignatureValid(address, a.EssenceBytes())
+}
+
+// EssenceBytes returns the bytes of the AssetMetadata that are covered by the Signature.
+func (a *AssetMetadata) EssenceBytes() []byte {
+	marshalUtil := marshalutil.New().
+		WriteBytes(a.color.Bytes()).
+		WriteBytes(a.mintingTransactionID.Bytes()).
+		WriteTime(a.timestamp)
+	writeString(marshalUtil, a.name)
+	writeString(marshalUtil, a.symbol)
+	marshalUtil.WriteUint8(a.decimals)
+	writeString(marshalUtil, a.url)
+
+	return marshalUtil.Bytes()
+}
+
+// Bytes returns a marshaled version of the AssetMetadata payload.
+func (a *AssetMetadata) Bytes() []byte {
+	payloadBytes := marshalutil.New().
+		WriteBytes(Type.Bytes()).
+		WriteBytes(a.EssenceBytes()).
+		WriteBool(a.signature != nil)
+	if a.signature != nil {
+		payloadBytes.WriteBytes(a.signature.Bytes())
+	}
+
+	return marshalutil.New().
+		WriteUint32(uint32(payloadBytes.WriteOffset())).
+		WriteBytes(payloadBytes.Bytes()).
+		Bytes()
+}
+
+// String returns a human readable version of the AssetMetadata payload.
+func (a *AssetMetadata) String() string {
+	return stringify.Struct("AssetMetadata",
+		stringify.StructField("color", a.color),
+		stringify.StructField("mintingTransactionID", a.mintingTransactionID),
+		stringify.StructField("timestamp", a.timestamp),
+		stringify.StructField("name", a.name),
+		stringify.StructField("symbol", a.symbol),
+		stringify.StructField("decimals", a.decimals),
+		stringify.StructField("url", a.url),
+		stringify.StructField("signature", a.signature),
+	)
+}
+
+// checkLengths returns an error if one of the strings of the AssetMetadata exceeds its maximum length.
+func (a *AssetMetadata) checkLengths() (err error) {
+	switch {
+	case len(a.name) > MaxNameLength:
+		err = xerrors.Errorf("name exceeds the maximum length of %d bytes", MaxNameLength)
+	case len(a.symbol) > MaxSymbolLength:
+		err = xerrors.Errorf("symbol exceeds the maximum length of %d bytes", MaxSymbolLength)
+	case len(a.url) > MaxURLLength:
+		err = xerrors.Errorf("URL exceeds the maximum length of %d bytes", MaxURLLength)
+	}
+
+	return
+}
+
+// code contract (make sure the type implements all required methods)
+var _ payload.Payload = &AssetMetadata{}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region utility functions ////////////////////////////////////////////////////////////////////////////////////////////
+
+// writeString writes a length prefixed string to the given MarshalUtil.
+func writeString(marshalUtil *marshalutil.MarshalUtil, value string) {
+	marshalUtil.WriteUint16(uint16(len(value))).WriteBytes([]byte(value))
+}
+
+// readString reads a length prefixed string from the given MarshalUtil.
+func readString(marshalUtil *marshalutil.MarshalUtil) (value string, err error) {
+	length, err := marshalUtil.ReadUint16()
+	if err != nil {
+		err = xerrors.Errorf("failed to parse string length (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	valueBytes, err := marshalUtil.ReadBytes(int(length))
+	if err != nil {
+		err = xerrors.Errorf("failed to parse string (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	value = string(valueBytes)
+
+	return
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,248 @@
+package assetregistry
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/identity"
+	"github.com/iotaledger/hive.go/kvstore"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// maxPendingAssetMetadata defines how many AssetMetadata payloads of unknown minting Transactions are kept in
+	// memory. If the limit is reached, the oldest pending AssetMetadata is evicted.
+	maxPendingAssetMetadata = 1000
+
+	// maxPendingAssetMetadataPerIssuer defines how many pending AssetMetadata payloads a single issuer can have, so
+	// that one issuer can not crowd out the payloads of the others.
+	maxPendingAssetMetadataPerIssuer = 10
+
+	// pendingAssetMetadataTTL defines how long AssetMetadata payloads wait for their minting Transaction.
+	pendingAssetMetadataTTL = 10 * time.Minute
+)
+
+var (
+	// ErrMintingTransactionUnknown is returned if the minting Transaction of an AssetMetadata payload is not booked, yet.
+	ErrMintingTransactionUnknown = xerrors.New("minting transaction unknown")
+
+	// ErrInvalidAssetMetadata is returned if an AssetMetadata payload is not bound to its minting Transaction or if it
+	// was not signed by the minter.
+	ErrInvalidAssetMetadata = xerrors.New("invalid asset metadata")
+
+	// ErrOutdatedAssetMetadata is returned if a newer AssetMetadata payload of the same Color is known already.
+	ErrOutdatedAssetMetadata = xerrors.New("outdated asset metadata")
+)
+
+// TransactionRetriever is the type of the function that is used by the Registry to load the minting Transactions. It
+// returns nil if the Transaction is not known.
+type TransactionRetriever func(transactionID ledgerstate.TransactionID) *ledgerstate.Transaction
+
+// OutputRetriever is the type of the function that is used by the Registry to load the Outputs that were consumed by
+// the minting Transactions. It returns nil if the Output is not known.
+type OutputRetriever func(outputID ledgerstate.OutputID) ledgerstate.Output
+
+// Registry is a node side index of the AssetMetadata payloads that were published by the minters of Colors.
+type Registry struct {
+	store                    kvstore.KVStore
+	transactionRetriever     TransactionRetriever
+	outputRetriever          OutputRetriever
+	pendingMetadata          *list.List
+	pendingMetadataPerIssuer map[identity.ID]int
+	mutex                    sync.Mutex
+}
+
+// New creates a new Registry that persists the AssetMetadata in the given KVStore.
+func New(store kvstore.KVStore, transactionRetriever TransactionRetriever, outputRetriever OutputRetriever) *Registry {
+	return &Registry{
+		store:                    store,
+		transactionRetriever:     transactionRetriever,
+		outputRetriever:          outputRetriever,
+		pendingMetadata:          list.New(),
+		pendingMetadataPerIssuer: make(map[identity.ID]int),
+	}
+}
+
+// Register validates the given AssetMetadata against its minting Transaction and stores it if it is newer than the
+// currently known AssetMetadata of the same Color. If the minting Transaction is not known, yet, the AssetMetadata is
+// kept (on behalf of the given issuer) until ProcessBookedTransaction is called for it or until it expires.
+func (r *Registry) Register(assetMetadata *AssetMetadata, issuer identity.ID) (err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	mintingTransaction := r.transactionRetriever(assetMetadata.MintingTransactionID())
+	if mintingTransaction == nil {
+		r.addPendingMetadata(assetMetadata, issuer, time.Now())
+
+		return xerrors.Errorf("failed to register AssetMetadata of %s: %w", assetMetadata.Color(), ErrMintingTransactionUnknown)
+	}
+
+	return r.register(assetMetadata, mintingTransaction)
+}
+
+// ProcessBookedTransaction registers the AssetMetadata that were waiting for the given minting Transaction.
+func (r *Registry) ProcessBookedTransaction(transactionID ledgerstate.TransactionID) (err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.evictExpiredPendingMetadata(time.Now())
+
+	pendingMetadata := make([]*AssetMetadata, 0)
+	for element := r.pendingMetadata.Front(); element != nil; {
+		next := element.Next()
+		if pending := element.Value.(*pendingAssetMetadata); pending.assetMetadata.MintingTransactionID() == transactionID {
+			pendingMetadata = append(pendingMetadata, pending.assetMetadata)
+			r.removePendingMetadata(element)
+		}
+		element = next
+	}
+	if len(pendingMetadata) == 0 {
+		return
+	}
+
+	mintingTransaction := r.transactionRetriever(transactionID)
+	if mintingTransaction == nil {
+		return xerrors.Errorf("failed to load minting Transaction with %s: %w", transactionID, ErrMintingTransactionUnknown)
+	}
+
+	for _, assetMetadata := range pendingMetadata {
+		if registerErr := r.register(assetMetadata, mintingTransaction); registerErr != nil {
+			err = registerErr
+		}
+	}
+
+	return
+}
+
+// AssetMetadata returns the registered AssetMetadata of the given Color (or nil if no AssetMetadata is known).
+func (r *Registry) AssetMetadata(color ledgerstate.Color) (assetMetadata *AssetMetadata, err error) {
+	assetMetadataBytes, err := r.store.Get(color.Bytes())
+	if err != nil {
+		if xerrors.Is(err, kvstore.ErrKeyNotFound) {
+			err = nil
+			return
+		}
+
+		err = xerrors.Errorf("failed to load AssetMetadata of %s: %w", color, err)
+		return
+	}
+
+	if assetMetadata, _, err = FromBytes(assetMetadataBytes); err != nil {
+		err = xerrors.Errorf("failed to parse AssetMetadata of %s: %w", color, err)
+	}
+
+	return
+}
+
+// ForEach iterates through all registered AssetMetadata and calls the consumer for every found entry. The iteration
+// can be aborted by returning false in the consumer.
+func (r *Registry) ForEach(consumer func(assetMetadata *AssetMetadata) bool) (err error) {
+	return r.store.Iterate(kvstore.EmptyPrefix, func(key kvstore.Key, value kvstore.Value) bool {
+		assetMetadata, _, parseErr := FromBytes(value)
+		if parseErr != nil {
+			err = xerrors.Errorf("failed to parse AssetMetadata: %w", parseErr)
+			return false
+		}
+
+		return consumer(assetMetadata)
+	})
+}
+
+// register contains the validation and storage logic of the Register method (it expects the mutex to be locked).
+func (r *Registry) register(assetMetadata *AssetMetadata, mintingTransaction *ledgerstate.Transaction) (err error) {
+	if mintingOutput(mintingTransaction, assetMetadata.Color()) == nil {
+		return xerrors.Errorf("%s was not minted by Transaction with %s: %w", assetMetadata.Color(), mintingTransaction.ID(), ErrInvalidAssetMetadata)
+	}
+	if !r.signedByMinter(assetMetadata, mintingTransaction) {
+		return xerrors.Errorf("AssetMetadata of %s is not signed by the minter: %w", assetMetadata.Color(), ErrInvalidAssetMetadata)
+	}
+
+	existingMetadata, err := r.AssetMetadata(assetMetadata.Color())
+	if err != nil {
+		return
+	}
+	if existingMetadata != nil && !assetMetadata.Timestamp().After(existingMetadata.Timestamp()) {
+		return xerrors.Errorf("AssetMetadata of %s is not newer than the registered one: %w", assetMetadata.Color(), ErrOutdatedAssetMetadata)
+	}
+
+	if err = r.store.Set(assetMetadata.Color().Bytes(), assetMetadata.Bytes()); err != nil {
+		err = xerrors.Errorf("failed to store AssetMetadata of %s: %w", assetMetadata.Color(), err)
+	}
+
+	return
+}
+
+// signedByMinter returns true if the AssetMetadata was signed by the owner of one of the Outputs that were consumed by
+// the minting Transaction (the recipient of the minted tokens is not necessarily the minter).
+func (r *Registry) signedByMinter(assetMetadata *AssetMetadata, mintingTransaction *ledgerstate.Transaction) bool {
+	for _, input := range mintingTransaction.Essence().Inputs() {
+		utxoInput, typeCastOK := input.(*ledgerstate.UTXOInput)
+		if !typeCastOK {
+			continue
+		}
+
+		if consumedOutput := r.outputRetriever(utxoInput.ReferencedOutputID()); consumedOutput != nil && assetMetadata.SignatureValid(consumedOutput.Address()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addPendingMetadata keeps the given AssetMetadata until its minting Transaction is booked. It drops the AssetMetadata
+// if the issuer has reached its limit and evicts the oldest pending AssetMetadata if the Registry is full.
+func (r *Registry) addPendingMetadata(assetMetadata *AssetMetadata, issuer identity.ID, now time.Time) {
+	r.evictExpiredPendingMetadata(now)
+
+	if r.pendingMetadataPerIssuer[issuer] >= maxPendingAssetMetadataPerIssuer {
+		return
+	}
+	if r.pendingMetadata.Len() >= maxPendingAssetMetadata {
+		r.removePendingMetadata(r.pendingMetadata.Front())
+	}
+
+	r.pendingMetadata.PushBack(&pendingAssetMetadata{
+		assetMetadata: assetMetadata,
+		issuer:        issuer,
+		expiryTime:    now.Add(pendingAssetMetadataTTL),
+	})
+	r.pendingMetadataPerIssuer[issuer]++
+}
+
+// evictExpiredPendingMetadata removes the pending AssetMetadata that waited for longer than the pendingAssetMetadataTTL.
+// As all entries have the same TTL, the list is ordered by expiry time.
+func (r *Registry) evictExpiredPendingMetadata(now time.Time) {
+	for element := r.pendingMetadata.Front(); element != nil && now.After(element.Value.(*pendingAssetMetadata).expiryTime); element = r.pendingMetadata.Front() {
+		r.removePendingMetadata(element)
+	}
+}
+
+// removePendingMetadata removes the given element from the pending AssetMetadata.
+func (r *Registry) removePendingMetadata(element *list.Element) {
+	pending := r.pendingMetadata.Remove(element).(*pendingAssetMetadata)
+	if r.pendingMetadataPerIssuer[pending.issuer]--; r.pendingMetadataPerIssuer[pending.issuer] == 0 {
+		delete(r.pendingMetadataPerIssuer, pending.issuer)
+	}
+}
+
+// pendingAssetMetadata is an AssetMetadata payload that waits for its minting Transaction to be booked.
+type pendingAssetMetadata struct {
+	assetMetadata *AssetMetadata
+	issuer        identity.ID
+	expiryTime    time.Time
+}
+
+// mintingOutput returns the Output of the Transaction that minted the given Color (or nil if the Transaction did not
+// mint it).
+func mintingOutput(transaction *ledgerstate.Transaction, color ledgerstate.Color) ledgerstate.Output {
+	for _, output := range transaction.Essence().Outputs() {
+		if _, mintsColor := output.Balances().Get(ledgerstate.ColorMint); mintsColor && ledgerstate.Color(blake2b.Sum256(output.ID().Bytes())) == color {
+			return output
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package assetregistry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/hive.go/identity"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/xerrors"
+)
+
+func TestAssetMetadata_Bytes(t *testing.T) {
+	keyPair := ed25519.GenerateKeyPair()
+	assetMetadata, err := NewAssetMetadata(ledgerstate.Color{1}, ledgerstate.TransactionID{2}, time.Now(), "Test Token", "TT", 2, "https://example.com")
+	require.NoError(t, err)
+	assetMetadata.Sign(&keyPair)
+
+	parsedMetadata, consumedBytes, err := FromBytes(assetMetadata.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, len(assetMetadata.Bytes()), consumedBytes)
+	assert.Equal(t, assetMetadata.Bytes(), parsedMetadata.Bytes())
+	assert.Equal(t, "Test Token", parsedMetadata.Name())
+	assert.Equal(t, uint8(2), parsedMetadata.Decimals())
+	assert.True(t, parsedMetadata.SignatureValid(ledgerstate.NewED25519Address(keyPair.PublicKey)))
+
+	_, err = NewAssetMetadata(ledgerstate.Color{1}, ledgerstate.TransactionID{2}, time.Now(), "Test Token", "TOO LONG TOKEN SYMBOL", 2, "")
+	assert.Error(t, err)
+}
+
+func TestRegistry(t *testing.T) {
+	minter := ed25519.GenerateKeyPair()
+	recipient := ed25519.GenerateKeyPair()
+	mintingTransaction := newMintingTransaction(ledgerstate.NewED25519Address(recipient.PublicKey))
+	color := ledgerstate.Color(blake2b.Sum256(ledgerstate.NewOutputID(mintingTransaction.ID(), 0).Bytes()))
+
+	var mintingTransactionBooked bool
+	registry := New(mapdb.NewMapDB(), func(transactionID ledgerstate.TransactionID) *ledgerstate.Transaction {
+		if !mintingTransactionBooked || transactionID != mintingTransaction.ID() {
+			return nil
+		}
+
+		return mintingTransaction
+	}, newOutputRetriever(ledgerstate.NewED25519Address(minter.PublicKey)))
+
+	// metadata of unknown minting transactions is kept until the transaction is booked
+	assetMetadata := newSignedAssetMetadata(t, color, mintingTransaction.ID(), time.Now(), "Test Token", &minter)
+	assert.True(t, xerrors.Is(registry.Register(assetMetadata, identity.ID{}), ErrMintingTransactionUnknown))
+	mintingTransactionBooked = true
+	require.NoError(t, registry.ProcessBookedTransaction(mintingTransaction.ID()))
+
+	registeredMetadata, err := registry.AssetMetadata(color)
+	require.NoError(t, err)
+	require.NotNil(t, registeredMetadata)
+	assert.Equal(t, "Test Token", registeredMetadata.Name())
+
+	// metadata that is not signed by the minter is rejected
+	otherKeyPair := ed25519.GenerateKeyPair()
+	forgedMetadata := newSignedAssetMetadata(t, color, mintingTransaction.ID(), time.Now().Add(time.Minute), "Forged Token", &otherKeyPair)
+	assert.True(t, xerrors.Is(registry.Register(forgedMetadata, identity.ID{}), ErrInvalidAssetMetadata))
+
+	// the recipient of the minted tokens is not the minter
+	recipientMetadata := newSignedAssetMetadata(t, color, mintingTransaction.ID(), time.Now().Add(time.Minute), "Recipient Token", &recipient)
+	assert.True(t, xerrors.Is(registry.Register(recipientMetadata, identity.ID{}), ErrInvalidAssetMetadata))
+
+	// metadata of colors that were not minted by the transaction is rejected
+	unrelatedMetadata := newSignedAssetMetadata(t, ledgerstate.Color{7}, mintingTransaction.ID(), time.Now(), "Unrelated Token", &minter)
+	assert.True(t, xerrors.Is(registry.Register(unrelatedMetadata, identity.ID{}), ErrInvalidAssetMetadata))
+
+	// older metadata does not replace newer one
+	outdatedMetadata := newSignedAssetMetadata(t, color, mintingTransaction.ID(), time.Now().Add(-time.Minute), "Outdated Token", &minter)
+	assert.True(t, xerrors.Is(registry.Register(outdatedMetadata, identity.ID{}), ErrOutdatedAssetMetadata))
+
+	// newer metadata replaces the registered one
+	updatedMetadata := newSignedAssetMetadata(t, color, mintingTransaction.ID(), time.Now().Add(time.Minute), "Updated Token", &minter)
+	require.NoError(t, registry.Register(updatedMetadata, identity.ID{}))
+
+	registeredNames := make([]string, 0)
+	require.NoError(t, registry.ForEach(func(assetMetadata *AssetMetadata) bool {
+		registeredNames = append(registeredNames, assetMetadata.Name())
+
+		return true
+	}))
+	assert.Equal(t, []string{"Updated Token"}, registeredNames)
+}
+
+func TestRegistry_PendingMetadata(t *testing.T) {
+	minter := ed25519.GenerateKeyPair()
+	registry := New(mapdb.NewMapDB(), func(ledgerstate.TransactionID) *ledgerstate.Transaction {
+		return nil
+	}, newOutputRetriever(ledgerstate.NewED25519Address(minter.PublicKey)))
+
+	// a single issuer can not occupy more than its share of the pending metadata
+	spammer := identity.GenerateIdentity().ID()
+	for i := 0; i < maxPendingAssetMetadataPerIssuer+5; i++ {
+		assetMetadata := newSignedAssetMetadata(t, ledgerstate.Color{byte(i)}, ledgerstate.TransactionID{byte(i)}, time.Now(), "Spam Token", &minter)
+		assert.True(t, xerrors.Is(registry.Register(assetMetadata, spammer), ErrMintingTransactionUnknown))
+	}
+	assert.Equal(t, maxPendingAssetMetadataPerIssuer, registry.pendingMetadata.Len())
+	assert.Equal(t, maxPendingAssetMetadataPerIssuer, registry.pendingMetadataPerIssuer[spammer])
+
+	// other issuers can still add pending metadata
+	honestIssuer := identity.GenerateIdentity().ID()
+	assetMetadata := newSignedAssetMetadata(t, ledgerstate.Color{255}, ledgerstate.TransactionID{255}, time.Now(), "Test Token", &minter)
+	assert.True(t, xerrors.Is(registry.Register(assetMetadata, honestIssuer), ErrMintingTransactionUnknown))
+	assert.Equal(t, maxPendingAssetMetadataPerIssuer+1, registry.pendingMetadata.Len())
+
+	// pending metadata expires after its TTL
+	registry.evictExpiredPendingMetadata(time.Now().Add(pendingAssetMetadataTTL + time.Second))
+	assert.Equal(t, 0, registry.pendingMetadata.Len())
+	assert.Empty(t, registry.pendingMetadataPerIssuer)
+}
+
+func newOutputRetriever(minterAddress ledgerstate.Address) OutputRetriever {
+	return func(outputID ledgerstate.OutputID) ledgerstate.Output {
+		if outputID != ledgerstate.NewOutputID(ledgerstate.GenesisTransactionID, 1) {
+			return nil
+		}
+
+		return ledgerstate.NewSigLockedSingleOutput(1000, minterAddress).SetID(outputID)
+	}
+}
+
+func newMintingTransaction(address ledgerstate.Address) *ledgerstate.Transaction {
+	essence := ledgerstate.NewTransactionEssence(0, time.Now(), identity.ID{}, identity.ID{},
+		ledgerstate.NewInputs(ledgerstate.NewUTXOInput(ledgerstate.NewOutputID(ledgerstate.GenesisTransactionID, 1))),
+		ledgerstate.NewOutputs(ledgerstate.NewSigLockedColoredOutput(ledgerstate.NewColoredBalances(map[ledgerstate.Color]uint64{
+			ledgerstate.ColorMint: 1000,
+		}), address)),
+	)
+
+	return ledgerstate.NewTransaction(essence, ledgerstate.UnlockBlocks{ledgerstate.NewReferenceUnlockBlock(0)})
+}
+
+func newSignedAssetMetadata(t *testing.T, color ledgerstate.Color, mintingTransactionID ledgerstate.TransactionID, timestamp time.Time, name string, keyPair *ed25519.KeyPair) *AssetMetadata {
+	assetMetadata, err := NewAssetMetadata(color, mintingTransactionID, timestamp, name, "TT", 0, "")
+	require.NoError(t, err)
+	assetMetadata.Sign(keyPair)
+
+	return assetMetadata
+}
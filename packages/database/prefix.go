@@ -18,4 +18,10 @@ const (
 
 	// PrefixLedgerState defines the storage prefix for the ledgerstate package.
 	PrefixLedgerState
+
+	// PrefixMana defines the storage prefix for the mana package.
+	PrefixMana
+
+	// PrefixAssetRegistry defines the storage prefix for the assetregistry package.
+	PrefixAssetRegistry
 )
@@ -15,4 +15,14 @@ var (
 	ErrInvalidPacket = errors.New("invalid packet")
 	// ErrNeighborQueueFull is returned when the send queue is already full.
 	ErrNeighborQueueFull = errors.New("send queue is full")
+	// ErrNeighborBanned is returned when a peer is added as a neighbor while it is banned for misbehaving.
+	ErrNeighborBanned = errors.New("neighbor is banned")
+	// ErrSnapshotUnavailable is returned when the neighbor does not have a snapshot that it can serve.
+	ErrSnapshotUnavailable = errors.New("snapshot unavailable")
+	// ErrSnapshotDownloadInProgress is returned when a snapshot is requested while another download is still running.
+	ErrSnapshotDownloadInProgress = errors.New("snapshot download in progress")
+	// ErrInvalidSnapshotChunk is returned when a received snapshot chunk does not match its hash or the snapshot.
+	ErrInvalidSnapshotChunk = errors.New("invalid snapshot chunk")
+	// ErrSnapshotRequestTimeout is returned when a neighbor does not answer a snapshot request in time.
+	ErrSnapshotRequestTimeout = errors.New("snapshot request timed out")
 )
@@ -5,6 +5,7 @@ import (
 	"net"
 	"runtime"
 	"sync"
+	"time"
 
 	pb "github.com/iotaledger/goshimmer/packages/gossip/proto"
 	"github.com/iotaledger/goshimmer/packages/gossip/server"
@@ -14,6 +15,7 @@ import (
 	"github.com/iotaledger/hive.go/identity"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/hive.go/workerpool"
+	"golang.org/x/crypto/blake2b"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -30,15 +32,20 @@ var (
 	messageRequestWorkerQueueSize = 100
 )
 
+// defaultRequestRateLimit defines the default number of objects per second a neighbor is allowed to request.
+const defaultRequestRateLimit = 1000
+
 // LoadMessageFunc defines a function that returns the message for the given id.
 type LoadMessageFunc func(messageId tangle.MessageID) ([]byte, error)
 
 // The Manager handles the connected neighbors.
 type Manager struct {
-	local           *peer.Local
-	loadMessageFunc LoadMessageFunc
-	log             *logger.Logger
-	events          Events
+	local                 *peer.Local
+	loadMessageFunc       LoadMessageFunc
+	loadSnapshotChunkFunc LoadSnapshotChunkFunc
+	messageExistsFunc     MessageExistsFunc
+	log                   *logger.Logger
+	events                Events
 
 	wg sync.WaitGroup
 
@@ -50,10 +57,59 @@ type Manager struct {
 	messageWorkerPool *workerpool.WorkerPool
 
 	messageRequestWorkerPool *workerpool.WorkerPool
+
+	snapshotRequestWorkerPool *workerpool.WorkerPool
+
+	snapshotDownload      *snapshotDownload
+	snapshotDownloadMutex sync.RWMutex
+
+	// rate limits that are applied to every neighbor
+	inboundLimit     int
+	outboundLimit    int
+	requestRateLimit int
+
+	banDuration time.Duration
+	bannedPeers map[identity.ID]time.Time
+
+	// pullRequests contains the pending requests of the announced or requested messages per neighbor.
+	pullRequests map[identity.ID]map[tangle.MessageID]time.Time
+	// announcements contains the neighbors that announced the messages which are currently requested.
+	announcements     map[tangle.MessageID]*pendingAnnouncement
+	pullRequestsMutex sync.Mutex
+
+	closing   chan struct{}
+	closeOnce sync.Once
+}
+
+// ManagerOption is a function setting an optional parameter of the Manager.
+type ManagerOption func(m *Manager)
+
+// LoadSnapshotChunk is a ManagerOption that sets the function that is used to serve the snapshot to neighbors.
+func LoadSnapshotChunk(f LoadSnapshotChunkFunc) ManagerOption {
+	return func(m *Manager) {
+		m.loadSnapshotChunkFunc = f
+	}
+}
+
+// BandwidthLimits is a ManagerOption that limits the number of bytes per second that are received from and sent to
+// each neighbor. A limit of 0 disables the corresponding limitation.
+func BandwidthLimits(inbound int, outbound int) ManagerOption {
+	return func(m *Manager) {
+		m.inboundLimit = inbound
+		m.outboundLimit = outbound
+	}
+}
+
+// RequestRateLimit is a ManagerOption that sets the number of messages or snapshot chunks per second each neighbor
+// is allowed to request. Neighbors exceeding the limit lose reputation. A limit of 0 disables the limitation.
+func RequestRateLimit(requestsPerSecond int) ManagerOption {
+	return func(m *Manager) {
+		m.requestRateLimit = requestsPerSecond
+	}
 }
 
 // NewManager creates a new Manager.
-func NewManager(local *peer.Local, f LoadMessageFunc, log *logger.Logger) *Manager {
+func NewManager(local *peer.Local, f LoadMessageFunc, log *logger.Logger, opts ...ManagerOption) *Manager {
 	m := &Manager{
 		local:           local,
 		loadMessageFunc: f,
@@ -64,24 +120,55 @@ func NewManager(local *peer.Local, f LoadMessageFunc, log *logger.Logger) *Manag
 			NeighborRemoved:  events.NewEvent(neighborCaller),
 			MessageReceived:  events.NewEvent(messageReceived),
 		},
-		srv:       nil,
-		neighbors: make(map[identity.ID]*Neighbor),
+		srv:              nil,
+		neighbors:        make(map[identity.ID]*Neighbor),
+		requestRateLimit: defaultRequestRateLimit,
+		banDuration:      defaultBanDuration,
+		bannedPeers:      make(map[identity.ID]time.Time),
+		pullRequests:     make(map[identity.ID]map[tangle.MessageID]time.Time),
+		announcements:    make(map[tangle.MessageID]*pendingAnnouncement),
+		closing:          make(chan struct{}),
 	}
 
 	m.messageWorkerPool = workerpool.New(func(task workerpool.Task) {
+		data, nbr := task.Param(0).([]byte), task.Param(1).(*Neighbor)
 
-		m.processPacketMessage(task.Param(0).([]byte), task.Param(1).(*Neighbor))
+		switch pb.PacketType(data[0]) {
+		case pb.PacketMessageBatch:
+			m.processMessageBatch(data, nbr)
+		default:
+			m.processPacketMessage(data, nbr)
+		}
 
 		task.Return(nil)
 	}, workerpool.WorkerCount(messageWorkerCount), workerpool.QueueSize(messageWorkerQueueSize))
 
 	m.messageRequestWorkerPool = workerpool.New(func(task workerpool.Task) {
-
-		m.processMessageRequest(task.Param(0).([]byte), task.Param(1).(*Neighbor))
+		data, nbr := task.Param(0).([]byte), task.Param(1).(*Neighbor)
+
+		switch pb.PacketType(data[0]) {
+		case pb.PacketMessageAnnouncement:
+			m.processMessageAnnouncement(data, nbr)
+		case pb.PacketMessageRequestBatch:
+			m.processMessageRequestBatch(data, nbr)
+		default:
+			m.processMessageRequest(data, nbr)
+		}
 
 		task.Return(nil)
 	}, workerpool.WorkerCount(messageRequestWorkerCount), workerpool.QueueSize(messageRequestWorkerQueueSize))
 
+	m.snapshotRequestWorkerPool = workerpool.New(func(task workerpool.Task) {
+
+		m.processSnapshotRequest(task.Param(0).([]byte), task.Param(1).(*Neighbor))
+
+		task.Return(nil)
+	}, workerpool.WorkerCount(snapshotRequestWorkerCount), workerpool.QueueSize(snapshotRequestWorkerQueueSize))
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
 	return m
 }
 
@@ -94,6 +181,10 @@ func (m *Manager) Start(srv *server.TCP) {
 
 	m.messageWorkerPool.Start()
 	m.messageRequestWorkerPool.Start()
+	m.snapshotRequestWorkerPool.Start()
+
+	m.wg.Add(1)
+	go m.retryPullRequestsLoop()
 }
 
 // Close stops the manager and closes all established connections.
@@ -103,6 +194,7 @@ func (m *Manager) Close() {
 
 	m.messageWorkerPool.Stop()
 	m.messageRequestWorkerPool.Stop()
+	m.snapshotRequestWorkerPool.Stop()
 }
 
 // Events returns the events related to the gossip protocol.
@@ -115,6 +207,7 @@ func (m *Manager) stop() {
 	defer m.mu.Unlock()
 
 	m.srv = nil
+	m.closeOnce.Do(func() { close(m.closing) })
 
 	// close all neighbor connections
 	for _, nbr := range m.neighbors {
@@ -122,44 +215,44 @@ func (m *Manager) stop() {
 	}
 }
 
-// AddOutbound tries to add a neighbor by connecting to that peer.
-func (m *Manager) AddOutbound(p *peer.Peer) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	if p.ID() == m.local.ID() {
-		return ErrLoopbackNeighbor
-	}
-	if m.srv == nil {
+// AddOutbound tries to add a neighbor of the given group by connecting to that peer.
+func (m *Manager) AddOutbound(p *peer.Peer, group NeighborsGroup) error {
+	srv := m.server()
+	if srv == nil {
 		return ErrNotRunning
 	}
-	return m.addNeighbor(p, m.srv.DialPeer)
+	return m.addNeighbor(p, group, srv.DialPeer)
 }
 
-// AddInbound tries to add a neighbor by accepting an incoming connection from that peer.
-func (m *Manager) AddInbound(p *peer.Peer) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	if p.ID() == m.local.ID() {
-		return ErrLoopbackNeighbor
-	}
-	if m.srv == nil {
+// AddInbound tries to add a neighbor of the given group by accepting an incoming connection from that peer.
+func (m *Manager) AddInbound(p *peer.Peer, group NeighborsGroup) error {
+	srv := m.server()
+	if srv == nil {
 		return ErrNotRunning
 	}
-	return m.addNeighbor(p, m.srv.AcceptPeer)
+	return m.addNeighbor(p, group, srv.AcceptPeer)
 }
 
-// DropNeighbor disconnects the neighbor with the given ID.
-func (m *Manager) DropNeighbor(id identity.ID) error {
+// DropNeighbor disconnects the neighbor with the given ID, if it belongs to the given group.
+func (m *Manager) DropNeighbor(id identity.ID, group NeighborsGroup) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if _, ok := m.neighbors[id]; !ok {
+	if n, ok := m.neighbors[id]; !ok || n.Group() != group {
+		return ErrUnknownNeighbor
+	}
+
+	return m.dropNeighbor(id)
+}
+
+// dropNeighbor disconnects the neighbor with the given ID regardless of its group. The caller must hold the lock.
+func (m *Manager) dropNeighbor(id identity.ID) error {
+	n, ok := m.neighbors[id]
+	if !ok {
 		return ErrUnknownNeighbor
 	}
-	n := m.neighbors[id]
 	delete(m.neighbors, id)
+	m.dropPullRequests(id)
 
 	return n.Close()
 }
@@ -171,11 +264,64 @@ func (m *Manager) RequestMessage(messageID []byte, to ...identity.ID) {
 	m.send(marshal(msgReq), to...)
 }
 
+// RequestMessages requests the messages with the given ids from the neighbors. If no peer is provided, all neighbors
+// are queried. Neighbors that support the second version of the gossip protocol receive the ids in batches, all other
+// neighbors receive a separate request for every message.
+func (m *Manager) RequestMessages(messageIDs [][]byte, to ...identity.ID) {
+	neighbors := m.getNeighbors(to...)
+	if len(neighbors) == 0 {
+		return
+	}
+
+	for _, nbr := range neighbors {
+		// every queried neighbor is expected to answer, so that none of the responses is considered unsolicited
+		requestedIDs := m.addPullRequests(nbr.ID(), messageIDs)
+
+		if nbr.ProtocolVersion() < server.ProtocolV2 {
+			for _, idBytes := range requestedIDs {
+				if _, err := nbr.Write(marshal(&pb.MessageRequest{Id: idBytes})); err != nil {
+					m.log.Warnw("send error", "peer-id", nbr.ID(), "err", err)
+				}
+			}
+			continue
+		}
+
+		for start := 0; start < len(requestedIDs); start += maxAnnouncementIDs {
+			end := start + maxAnnouncementIDs
+			if end > len(requestedIDs) {
+				end = len(requestedIDs)
+			}
+			if _, err := nbr.Write(marshal(&pb.MessageRequestBatch{Ids: requestedIDs[start:end]})); err != nil {
+				m.log.Warnw("send error", "peer-id", nbr.ID(), "err", err)
+			}
+		}
+	}
+}
+
 // SendMessage adds the given message the send queue of the neighbors.
 // The actual send then happens asynchronously. If no peer is provided, it is send to all neighbors.
+// Neighbors that support the second version of the gossip protocol only receive an announcement of the message and
+// request it, if they have not seen it, yet.
 func (m *Manager) SendMessage(msgData []byte, to ...identity.ID) {
-	msg := &pb.Message{Data: msgData}
-	m.send(marshal(msg), to...)
+	var msg []byte
+	messageID := tangle.MessageID(blake2b.Sum256(msgData))
+
+	for _, nbr := range m.getNeighbors(to...) {
+		if nbr.ProtocolVersion() >= server.ProtocolV2 {
+			if err := nbr.Announce(messageID[:]); err != nil {
+				m.log.Warnw("send error", "peer-id", nbr.ID(), "err", err)
+			}
+			continue
+		}
+
+		// only marshal the message once and only if there is at least one neighbor using the first version
+		if msg == nil {
+			msg = marshal(&pb.Message{Data: msgData})
+		}
+		if _, err := nbr.Write(msg); err != nil {
+			m.log.Warnw("send error", "peer-id", nbr.ID(), "err", err)
+		}
+	}
 }
 
 // AllNeighbors returns all the neighbors that are currently connected.
@@ -221,14 +367,38 @@ func (m *Manager) send(b []byte, to ...identity.ID) {
 	}
 }
 
-func (m *Manager) addNeighbor(peer *peer.Peer, connectorFunc func(*peer.Peer) (net.Conn, error)) error {
+func (m *Manager) server() *server.TCP {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.srv
+}
+
+// addNeighbor establishes the connection to the peer using the connectorFunc and adds it as a neighbor. The lock is not
+// held while the connection is established, so that the other neighbors are not blocked by a slow handshake.
+func (m *Manager) addNeighbor(peer *peer.Peer, group NeighborsGroup, connectorFunc func(*peer.Peer) (net.Conn, error)) error {
+	if peer.ID() == m.local.ID() {
+		return ErrLoopbackNeighbor
+	}
+	if m.IsBanned(peer.ID()) {
+		m.events.ConnectionFailed.Trigger(peer, ErrNeighborBanned)
+		return ErrNeighborBanned
+	}
+
 	conn, err := connectorFunc(peer)
 	if err != nil {
 		m.events.ConnectionFailed.Trigger(peer, err)
 		return err
 	}
 
+	m.mu.Lock()
+	if m.srv == nil {
+		m.mu.Unlock()
+		_ = conn.Close()
+		return ErrNotRunning
+	}
 	if _, ok := m.neighbors[peer.ID()]; ok {
+		m.mu.Unlock()
 		_ = conn.Close()
 		m.events.ConnectionFailed.Trigger(peer, ErrDuplicateNeighbor)
 		return ErrDuplicateNeighbor
@@ -236,9 +406,15 @@ func (m *Manager) addNeighbor(peer *peer.Peer, connectorFunc func(*peer.Peer) (n
 
 	// create and add the neighbor
 	nbr := NewNeighbor(peer, conn, m.log)
+	nbr.group = group
+	nbr.setRateLimits(m.inboundLimit, m.outboundLimit, m.requestRateLimit)
 	nbr.Events.Close.Attach(events.NewClosure(func() {
 		// assure that the neighbor is removed and notify
-		_ = m.DropNeighbor(peer.ID())
+		m.mu.Lock()
+		if m.neighbors[peer.ID()] == nbr {
+			_ = m.dropNeighbor(peer.ID())
+		}
+		m.mu.Unlock()
 		m.events.NeighborRemoved.Trigger(nbr)
 	}))
 	nbr.Events.ReceiveMessage.Attach(events.NewClosure(func(data []byte) {
@@ -251,6 +427,8 @@ func (m *Manager) addNeighbor(peer *peer.Peer, connectorFunc func(*peer.Peer) (n
 
 	m.neighbors[peer.ID()] = nbr
 	nbr.Listen()
+	m.mu.Unlock()
+
 	m.events.NeighborAdded.Trigger(nbr)
 
 	return nil
@@ -264,14 +442,20 @@ func (m *Manager) handlePacket(data []byte, nbr *Neighbor) error {
 
 	switch pb.PacketType(data[0]) {
 
-	case pb.PacketMessage:
+	case pb.PacketMessage, pb.PacketMessageBatch:
 		if _, added := m.messageWorkerPool.TrySubmit(data, nbr); !added {
 			return fmt.Errorf("messageWorkerPool full: packet message discarded")
 		}
-	case pb.PacketMessageRequest:
+	case pb.PacketMessageRequest, pb.PacketMessageRequestBatch, pb.PacketMessageAnnouncement:
 		if _, added := m.messageRequestWorkerPool.TrySubmit(data, nbr); !added {
 			return fmt.Errorf("messageRequestWorkerPool full: message request discarded")
 		}
+	case pb.PacketSnapshotRequest:
+		if _, added := m.snapshotRequestWorkerPool.TrySubmit(data, nbr); !added {
+			return fmt.Errorf("snapshotRequestWorkerPool full: snapshot request discarded")
+		}
+	case pb.PacketSnapshotChunk:
+		return m.processSnapshotChunk(data, nbr)
 
 	default:
 		return ErrInvalidPacket
@@ -308,6 +492,7 @@ func (m *Manager) processPacketMessage(data []byte, nbr *Neighbor) {
 	if err := proto.Unmarshal(data[1:], packet); err != nil {
 		m.log.Debugw("error processing packet", "err", err)
 	}
+	m.completePullRequest(nbr.ID(), packet.GetData())
 	m.events.MessageReceived.Trigger(&MessageReceivedEvent{Data: packet.GetData(), Peer: nbr.Peer})
 }
 
@@ -316,6 +501,10 @@ func (m *Manager) processMessageRequest(data []byte, nbr *Neighbor) {
 	if err := proto.Unmarshal(data[1:], packet); err != nil {
 		m.log.Debugw("invalid packet", "err", err)
 	}
+	if !nbr.allowRequests(1) {
+		m.ReportMisbehavior(nbr.ID(), MisbehaviorRequestFlood)
+		return
+	}
 
 	msgID, _, err := tangle.MessageIDFromBytes(packet.GetId())
 	if err != nil {
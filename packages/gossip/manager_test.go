@@ -1,6 +1,8 @@
 package gossip
 
 import (
+	"bytes"
+	"crypto/rand"
 	"net"
 	"sync"
 	"testing"
@@ -12,11 +14,15 @@ import (
 	"github.com/iotaledger/hive.go/autopeering/peer"
 	"github.com/iotaledger/hive.go/autopeering/peer/service"
 	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/identity"
 	"github.com/iotaledger/hive.go/kvstore/mapdb"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/xerrors"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -50,13 +56,13 @@ func TestClosedConnection(t *testing.T) {
 
 	go func() {
 		defer wg.Done()
-		err := mgrA.AddInbound(peerB)
+		err := mgrA.AddInbound(peerB, NeighborsGroupAuto)
 		assert.NoError(t, err)
 	}()
 	time.Sleep(graceTime)
 	go func() {
 		defer wg.Done()
-		err := mgrB.AddOutbound(peerA)
+		err := mgrB.AddOutbound(peerA, NeighborsGroupAuto)
 		assert.NoError(t, err)
 	}()
 
@@ -67,7 +73,7 @@ func TestClosedConnection(t *testing.T) {
 	mgrB.On("neighborRemoved", mock.Anything).Once()
 
 	// A drops B
-	err := mgrA.DropNeighbor(peerB.ID())
+	err := mgrA.DropNeighbor(peerB.ID(), NeighborsGroupAuto)
 	require.NoError(t, err)
 	time.Sleep(graceTime)
 
@@ -90,13 +96,13 @@ func TestP2PSend(t *testing.T) {
 
 	go func() {
 		defer wg.Done()
-		err := mgrA.AddInbound(peerB)
+		err := mgrA.AddInbound(peerB, NeighborsGroupAuto)
 		assert.NoError(t, err)
 	}()
 	time.Sleep(graceTime)
 	go func() {
 		defer wg.Done()
-		err := mgrB.AddOutbound(peerA)
+		err := mgrB.AddOutbound(peerA, NeighborsGroupAuto)
 		assert.NoError(t, err)
 	}()
 
@@ -137,13 +143,13 @@ func TestP2PSendTwice(t *testing.T) {
 
 	go func() {
 		defer wg.Done()
-		err := mgrA.AddInbound(peerB)
+		err := mgrA.AddInbound(peerB, NeighborsGroupAuto)
 		assert.NoError(t, err)
 	}()
 	time.Sleep(graceTime)
 	go func() {
 		defer wg.Done()
-		err := mgrB.AddOutbound(peerA)
+		err := mgrB.AddOutbound(peerA, NeighborsGroupAuto)
 		assert.NoError(t, err)
 	}()
 
@@ -188,23 +194,23 @@ func TestBroadcast(t *testing.T) {
 
 	go func() {
 		defer wg.Done()
-		err := mgrA.AddInbound(peerB)
+		err := mgrA.AddInbound(peerB, NeighborsGroupAuto)
 		assert.NoError(t, err)
 	}()
 	go func() {
 		defer wg.Done()
-		err := mgrA.AddInbound(peerC)
+		err := mgrA.AddInbound(peerC, NeighborsGroupAuto)
 		assert.NoError(t, err)
 	}()
 	time.Sleep(graceTime)
 	go func() {
 		defer wg.Done()
-		err := mgrB.AddOutbound(peerA)
+		err := mgrB.AddOutbound(peerA, NeighborsGroupAuto)
 		assert.NoError(t, err)
 	}()
 	go func() {
 		defer wg.Done()
-		err := mgrC.AddOutbound(peerA)
+		err := mgrC.AddOutbound(peerA, NeighborsGroupAuto)
 		assert.NoError(t, err)
 	}()
 
@@ -249,23 +255,23 @@ func TestSingleSend(t *testing.T) {
 
 	go func() {
 		defer wg.Done()
-		err := mgrA.AddInbound(peerB)
+		err := mgrA.AddInbound(peerB, NeighborsGroupAuto)
 		assert.NoError(t, err)
 	}()
 	go func() {
 		defer wg.Done()
-		err := mgrA.AddInbound(peerC)
+		err := mgrA.AddInbound(peerC, NeighborsGroupAuto)
 		assert.NoError(t, err)
 	}()
 	time.Sleep(graceTime)
 	go func() {
 		defer wg.Done()
-		err := mgrB.AddOutbound(peerA)
+		err := mgrB.AddOutbound(peerA, NeighborsGroupAuto)
 		assert.NoError(t, err)
 	}()
 	go func() {
 		defer wg.Done()
-		err := mgrC.AddOutbound(peerA)
+		err := mgrC.AddOutbound(peerA, NeighborsGroupAuto)
 		assert.NoError(t, err)
 	}()
 
@@ -302,7 +308,7 @@ func TestDropUnsuccessfulAccept(t *testing.T) {
 
 	mgrA.On("connectionFailed", peerB, mock.Anything).Once()
 
-	err := mgrA.AddInbound(peerB)
+	err := mgrA.AddInbound(peerB, NeighborsGroupAuto)
 	assert.Error(t, err)
 
 	mgrA.AssertExpectations(t)
@@ -323,13 +329,13 @@ func TestMessageRequest(t *testing.T) {
 
 	go func() {
 		defer wg.Done()
-		err := mgrA.AddInbound(peerB)
+		err := mgrA.AddInbound(peerB, NeighborsGroupAuto)
 		assert.NoError(t, err)
 	}()
 	time.Sleep(graceTime)
 	go func() {
 		defer wg.Done()
-		err := mgrB.AddOutbound(peerA)
+		err := mgrB.AddOutbound(peerA, NeighborsGroupAuto)
 		assert.NoError(t, err)
 	}()
 
@@ -376,8 +382,8 @@ func TestDropNeighbor(t *testing.T) {
 		mgrB.Events().NeighborAdded.Attach(signal)
 		defer mgrB.Events().NeighborAdded.Detach(signal)
 
-		go func() { assert.NoError(t, mgrA.AddInbound(peerB)) }()
-		go func() { assert.NoError(t, mgrB.AddOutbound(peerA)) }()
+		go func() { assert.NoError(t, mgrA.AddInbound(peerB, NeighborsGroupAuto)) }()
+		go func() { assert.NoError(t, mgrB.AddOutbound(peerA, NeighborsGroupAuto)) }()
 		wg.Wait() // wait until the events were triggered and the peers are connected
 	}
 	// close connection
@@ -397,11 +403,11 @@ func TestDropNeighbor(t *testing.T) {
 		wg.Add(2)
 		go func() {
 			defer wg.Done()
-			_ = mgrA.DropNeighbor(peerB.ID())
+			_ = mgrA.DropNeighbor(peerB.ID(), NeighborsGroupAuto)
 		}()
 		go func() {
 			defer wg.Done()
-			_ = mgrB.DropNeighbor(peerA.ID())
+			_ = mgrB.DropNeighbor(peerA.ID(), NeighborsGroupAuto)
 		}()
 		wg.Wait() // wait until the events were triggered and the go routines are done
 	}
@@ -417,13 +423,326 @@ func TestDropNeighbor(t *testing.T) {
 	}
 }
 
+func TestRequestSnapshot(t *testing.T) {
+	// the snapshot spans multiple chunks and ends with a partial chunk
+	snapshot := make([]byte, 3*snapshotChunkSize+snapshotChunkSize/2)
+	_, err := rand.Read(snapshot)
+	require.NoError(t, err)
+
+	mgrA, closeA, peerA := newTestManager(t, "A", LoadSnapshotChunk(loadTestSnapshotChunk(snapshot)))
+	defer closeA()
+	mgrB, closeB, peerB := newTestManager(t, "B")
+	defer closeB()
+
+	connectTestManagers(t, mgrA, peerA, mgrB, peerB)
+
+	var buffer bytes.Buffer
+	require.NoError(t, mgrB.RequestSnapshot(&buffer, peerA.ID()))
+	assert.Equal(t, snapshot, buffer.Bytes())
+
+	// B does not serve any snapshot
+	assert.True(t, xerrors.Is(mgrA.RequestSnapshot(&bytes.Buffer{}, peerB.ID()), ErrSnapshotUnavailable))
+
+	// C is not a neighbor of A
+	_, closeC, peerC := newTestManager(t, "C")
+	defer closeC()
+	assert.True(t, xerrors.Is(mgrA.RequestSnapshot(&bytes.Buffer{}, peerC.ID()), ErrUnknownNeighbor))
+}
+
+func TestRequestSnapshotTail(t *testing.T) {
+	// the last chunk of the snapshot is smaller than the requested tail
+	snapshot := make([]byte, 2*snapshotChunkSize+10)
+	_, err := rand.Read(snapshot)
+	require.NoError(t, err)
+
+	mgrA, closeA, peerA := newTestManager(t, "A", LoadSnapshotChunk(loadTestSnapshotChunk(snapshot)))
+	defer closeA()
+	mgrB, closeB, peerB := newTestManager(t, "B")
+	defer closeB()
+
+	connectTestManagers(t, mgrA, peerA, mgrB, peerB)
+
+	tail, err := mgrB.RequestSnapshotTail(peerA.ID(), 32)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot[len(snapshot)-32:], tail)
+
+	tail, err = mgrB.RequestSnapshotTail(peerA.ID(), 5)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot[len(snapshot)-5:], tail)
+
+	_, err = mgrA.RequestSnapshotTail(peerB.ID(), 32)
+	assert.True(t, xerrors.Is(err, ErrSnapshotUnavailable))
+}
+
+func TestLegacyNeighbor(t *testing.T) {
+	mgrA, closeA, peerA := newTestManager(t, "A")
+	defer closeA()
+	mgrB, closeB, peerB := newTestManagerWithServerOptions(t, "B", []server.Option{server.MaxProtocolVersion(server.ProtocolV1)})
+	defer closeB()
+
+	connectTestManagers(t, mgrA, peerA, mgrB, peerB)
+	for _, nbr := range append(mgrA.AllNeighbors(), mgrB.AllNeighbors()...) {
+		require.Equal(t, server.ProtocolV1, nbr.ProtocolVersion())
+	}
+
+	received := make(chan []byte, 1)
+	mgrB.Events().MessageReceived.Attach(events.NewClosure(func(ev *MessageReceivedEvent) { received <- ev.Data }))
+
+	mgrA.SendMessage(testMessageData)
+	select {
+	case data := <-received:
+		assert.Equal(t, testMessageData, data)
+	case <-time.After(time.Second):
+		t.Fatal("message not received")
+	}
+}
+
+func TestUnencryptedNeighbor(t *testing.T) {
+	mgrA, closeA, peerA := newTestManager(t, "A")
+	defer closeA()
+	mgrB, closeB, peerB := newTestManagerWithServerOptions(t, "B", []server.Option{server.Encryption(server.EncryptionDisabled)})
+	defer closeB()
+
+	connectTestManagers(t, mgrA, peerA, mgrB, peerB)
+	for _, nbr := range append(mgrA.AllNeighbors(), mgrB.AllNeighbors()...) {
+		require.False(t, nbr.IsEncrypted())
+	}
+
+	received := make(chan []byte, 1)
+	mgrB.Events().MessageReceived.Attach(events.NewClosure(func(ev *MessageReceivedEvent) { received <- ev.Data }))
+
+	mgrA.SendMessage(testMessageData)
+	select {
+	case data := <-received:
+		assert.Equal(t, testMessageData, data)
+	case <-time.After(time.Second):
+		t.Fatal("message not received")
+	}
+}
+
+func TestKnownMessageAnnouncement(t *testing.T) {
+	mgrA, closeA, peerA := newMockedManager(t, "A")
+	defer closeA()
+	known, closeB, peerB := newTestManager(t, "B", MessageExists(func(tangle.MessageID) bool { return true }))
+	defer closeB()
+	mgrB := mockManager(t, known)
+
+	mgrA.On("neighborAdded", mock.Anything).Once()
+	mgrB.On("neighborAdded", mock.Anything).Once()
+	connectTestManagers(t, mgrA.Manager, peerA, mgrB.Manager, peerB)
+	require.Equal(t, server.LatestProtocolVersion, mgrA.AllNeighbors()[0].ProtocolVersion())
+	require.True(t, mgrA.AllNeighbors()[0].IsEncrypted())
+
+	// mgrB already knows the message and must not request it
+	mgrA.SendMessage(testMessageData)
+	time.Sleep(graceTime)
+
+	mgrA.On("neighborRemoved", mock.Anything).Once()
+	mgrB.On("neighborRemoved", mock.Anything).Once()
+
+	closeA()
+	closeB()
+	time.Sleep(graceTime)
+
+	mgrA.AssertExpectations(t)
+	mgrB.AssertExpectations(t)
+}
+
+func TestMessageBatch(t *testing.T) {
+	const numMessages = 200
+
+	messages := make(map[tangle.MessageID][]byte, numMessages)
+	for i := 0; i < numMessages; i++ {
+		msgData := make([]byte, 1024)
+		_, err := rand.Read(msgData)
+		require.NoError(t, err)
+		messages[blake2b.Sum256(msgData)] = msgData
+	}
+
+	mgrA, closeA, peerA := newTestManager(t, "A")
+	defer closeA()
+	mgrB, closeB, peerB := newTestManager(t, "B")
+	defer closeB()
+
+	var mu sync.Mutex
+	mgrA.loadMessageFunc = func(messageID tangle.MessageID) ([]byte, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if msgData, ok := messages[messageID]; ok {
+			return msgData, nil
+		}
+		return nil, xerrors.New("unknown message")
+	}
+
+	connectTestManagers(t, mgrA, peerA, mgrB, peerB)
+
+	var count atomic.Int32
+	mgrB.Events().MessageReceived.Attach(events.NewClosure(func(ev *MessageReceivedEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		msgData, ok := messages[blake2b.Sum256(ev.Data)]
+		if assert.True(t, ok) && assert.Equal(t, msgData, ev.Data) {
+			count.Inc()
+		}
+	}))
+
+	for _, msgData := range messages {
+		mgrA.SendMessage(msgData)
+	}
+	assert.Eventually(t, func() bool { return count.Load() == numMessages }, time.Second, graceTime)
+}
+
+func TestRequestMessages(t *testing.T) {
+	mgrA, closeA, peerA := newTestManager(t, "A")
+	defer closeA()
+	mgrB, closeB, peerB := newTestManager(t, "B")
+	defer closeB()
+	mgrC, closeC, peerC := newTestManagerWithServerOptions(t, "C", []server.Option{server.MaxProtocolVersion(server.ProtocolV1)})
+	defer closeC()
+
+	connectTestManagers(t, mgrA, peerA, mgrB, peerB)
+	connectTestManagers(t, mgrA, peerA, mgrC, peerC)
+
+	var count atomic.Int32
+	mgrA.Events().MessageReceived.Attach(events.NewClosure(func(ev *MessageReceivedEvent) {
+		if assert.Equal(t, testMessageData, ev.Data) {
+			count.Inc()
+		}
+	}))
+
+	// the message is requested in a batch from B and with a single request from C
+	messageID := tangle.MessageID(blake2b.Sum256(testMessageData))
+	mgrA.RequestMessages([][]byte{messageID.Bytes()})
+	assert.Eventually(t, func() bool { return count.Load() == 2 }, time.Second, graceTime)
+
+	// the responses of all queried neighbors are expected
+	for _, nbr := range mgrA.AllNeighbors() {
+		assert.Equal(t, maxReputation, nbr.Reputation())
+	}
+}
+
+func TestPullRequests(t *testing.T) {
+	mgr, closeMgr, _ := newTestManager(t, "A")
+	defer closeMgr()
+
+	nbrA, nbrB := identity.GenerateIdentity().ID(), identity.GenerateIdentity().ID()
+	msgID := tangle.MessageID(blake2b.Sum256(testMessageData))
+
+	// the message is only requested from the first neighbor that announces it
+	assert.True(t, mgr.startPullRequest(nbrA, msgID))
+	assert.False(t, mgr.startPullRequest(nbrB, msgID))
+	assert.False(t, mgr.completePullRequest(nbrB, testMessageData))
+	assert.True(t, mgr.completePullRequest(nbrA, testMessageData))
+	assert.False(t, mgr.completePullRequest(nbrA, testMessageData))
+
+	// the pending requests of a neighbor are capped until they expire
+	for i := 0; i < maxPullRequestsPerNeighbor; i++ {
+		require.True(t, mgr.startPullRequest(nbrA, tangle.MessageID{byte(i), byte(i >> 8), 1}))
+	}
+	assert.False(t, mgr.startPullRequest(nbrA, msgID))
+	assert.Len(t, mgr.addPullRequests(nbrA, [][]byte{msgID.Bytes()}), 0)
+	assert.Len(t, mgr.addPullRequests(nbrB, [][]byte{msgID.Bytes()}), 1)
+
+	mgr.pullRequestsMutex.Lock()
+	for id := range mgr.pullRequests[nbrA] {
+		mgr.pullRequests[nbrA][id] = time.Now().Add(-pullRequestExpiry)
+	}
+	mgr.pullRequestsMutex.Unlock()
+	assert.Len(t, mgr.addPullRequests(nbrA, [][]byte{msgID.Bytes()}), 1)
+	assert.True(t, mgr.completePullRequest(nbrA, testMessageData))
+
+	// the requests of dropped neighbors are removed
+	mgr.dropPullRequests(nbrB)
+	assert.False(t, mgr.completePullRequest(nbrB, testMessageData))
+}
+
+func TestUnansweredAnnouncement(t *testing.T) {
+	mgrA, closeA, peerA := newTestManager(t, "A")
+	defer closeA()
+	mgrB, closeB, peerB := newTestManager(t, "B")
+	defer closeB()
+	mgrC, closeC, peerC := newTestManager(t, "C")
+	defer closeC()
+
+	// B announces the message first, but never answers the request
+	mgrB.loadMessageFunc = func(tangle.MessageID) ([]byte, error) { return nil, xerrors.New("unknown message") }
+
+	connectTestManagers(t, mgrA, peerA, mgrB, peerB)
+	connectTestManagers(t, mgrA, peerA, mgrC, peerC)
+
+	var count atomic.Int32
+	mgrA.Events().MessageReceived.Attach(events.NewClosure(func(ev *MessageReceivedEvent) {
+		if assert.Equal(t, testMessageData, ev.Data) {
+			count.Inc()
+		}
+	}))
+
+	mgrB.SendMessage(testMessageData)
+	time.Sleep(graceTime)
+	mgrC.SendMessage(testMessageData)
+
+	// the message is only requested from C after the request to B timed out
+	time.Sleep(pullRequestTimeout / 2)
+	assert.EqualValues(t, 0, count.Load())
+	assert.Eventually(t, func() bool { return count.Load() == 1 }, 2*pullRequestTimeout, graceTime)
+
+	// the response of C was solicited
+	for _, nbr := range mgrA.AllNeighbors() {
+		assert.Equal(t, maxReputation, nbr.Reputation())
+	}
+}
+
+func TestMessageBatchEntrySize(t *testing.T) {
+	for _, size := range []int{0, 1, 127, 128, 16383, 16384, maxMessageBatchSize - 4} {
+		msgData := make([]byte, size)
+		b, err := proto.Marshal(&pb.MessageBatch{Data: [][]byte{msgData}})
+		require.NoError(t, err)
+		assert.Equal(t, len(b), messageBatchEntrySize(msgData))
+	}
+}
+
+func loadTestSnapshotChunk(snapshot []byte) LoadSnapshotChunkFunc {
+	return func(offset int64, length int) ([]byte, int64, error) {
+		end := offset + int64(length)
+		if end > int64(len(snapshot)) {
+			end = int64(len(snapshot))
+		}
+
+		return snapshot[offset:end], int64(len(snapshot)), nil
+	}
+}
+
+func connectTestManagers(t *testing.T, mgrA *Manager, peerA *peer.Peer, mgrB *Manager, peerB *peer.Peer) {
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	// connect in the following way
+	// B -> A
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, mgrA.AddInbound(peerB, NeighborsGroupAuto))
+	}()
+	time.Sleep(graceTime)
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, mgrB.AddOutbound(peerA, NeighborsGroupAuto))
+	}()
+
+	// wait for the connections to establish
+	wg.Wait()
+}
+
 func newTestDB(t require.TestingT) *peer.DB {
 	db, err := peer.NewDB(mapdb.NewMapDB())
 	require.NoError(t, err)
 	return db
 }
 
-func newTestManager(t require.TestingT, name string) (*Manager, func(), *peer.Peer) {
+func newTestManager(t require.TestingT, name string, opts ...ManagerOption) (*Manager, func(), *peer.Peer) {
+	return newTestManagerWithServerOptions(t, name, nil, opts...)
+}
+
+func newTestManagerWithServerOptions(t require.TestingT, name string, srvOpts []server.Option, opts ...ManagerOption) (*Manager, func(), *peer.Peer) {
 	l := log.Named(name)
 
 	laddr, err := net.ResolveTCPAddr("tcp", "127.0.0.1:0")
@@ -438,10 +757,10 @@ func newTestManager(t require.TestingT, name string) (*Manager, func(), *peer.Pe
 	local, err := peer.NewLocal(lis.Addr().(*net.TCPAddr).IP, services, newTestDB(t))
 	require.NoError(t, err)
 
-	srv := server.ServeTCP(local, lis, l)
+	srv := server.ServeTCP(local, lis, l, srvOpts...)
 
 	// start the actual gossipping
-	mgr := NewManager(local, loadTestMessage, l)
+	mgr := NewManager(local, loadTestMessage, l, opts...)
 	mgr.Start(srv)
 
 	detach := func() {
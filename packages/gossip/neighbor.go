@@ -7,7 +7,10 @@ import (
 	"sync"
 	"time"
 
+	pb "github.com/iotaledger/goshimmer/packages/gossip/proto"
+	"github.com/iotaledger/goshimmer/packages/gossip/server"
 	"github.com/iotaledger/hive.go/autopeering/peer"
+	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/hive.go/netutil"
 	"github.com/iotaledger/hive.go/netutil/buffconn"
@@ -18,8 +21,35 @@ const (
 	neighborQueueSize        = 5000
 	maxNumReadErrors         = 10
 	droppedMessagesThreshold = 1000
+
+	// maxAnnouncementIDs defines the maximum number of message ids that are announced or requested in a single packet.
+	maxAnnouncementIDs = 1024
+	// maxPendingAnnouncements defines the maximum number of message ids that are waiting to be announced.
+	maxPendingAnnouncements = neighborQueueSize
+)
+
+// NeighborsGroup is the type of the groups of neighbors, which are managed independently of each other.
+type NeighborsGroup uint8
+
+const (
+	// NeighborsGroupAuto contains the neighbors that were selected by the autopeering.
+	NeighborsGroupAuto NeighborsGroup = iota
+	// NeighborsGroupManual contains the neighbors that were configured manually.
+	NeighborsGroupManual
 )
 
+// String returns a human readable version of the NeighborsGroup.
+func (g NeighborsGroup) String() string {
+	switch g {
+	case NeighborsGroupAuto:
+		return "autopeering"
+	case NeighborsGroupManual:
+		return "manual"
+	default:
+		return "unknown"
+	}
+}
+
 // Neighbor describes the established gossip connection to another peer.
 type Neighbor struct {
 	*peer.Peer
@@ -28,6 +58,23 @@ type Neighbor struct {
 	log             *logger.Logger
 	queue           chan []byte
 	messagesDropped atomic.Int32
+	protocolVersion uint32
+	encrypted       bool
+	group           NeighborsGroup
+
+	packetsRead    atomic.Uint64
+	packetsWritten atomic.Uint64
+	reputation     *reputation
+
+	// rate limits of the neighbor, which are nil if they are unlimited
+	inboundLimiter  *tokenBucket
+	outboundLimiter *tokenBucket
+	requestLimiter  *tokenBucket
+
+	// announcements contains the ids of the messages that are announced with the next packet
+	announcements       [][]byte
+	announcementsMutex  sync.Mutex
+	announcementsSignal chan struct{}
 
 	wg             sync.WaitGroup
 	closing        chan struct{}
@@ -47,16 +94,30 @@ func NewNeighbor(peer *peer.Peer, conn net.Conn, log *logger.Logger) *Neighbor {
 		"id", peer.ID(),
 		"network", conn.LocalAddr().Network(),
 		"addr", conn.RemoteAddr().String(),
+		"protocol", server.ProtocolVersion(conn),
+		"encrypted", server.IsEncrypted(conn),
 	)
 
-	return &Neighbor{
+	n := &Neighbor{
 		Peer:                  peer,
 		BufferedConnection:    buffconn.NewBufferedConnection(conn, maxPacketSize),
 		log:                   log,
 		queue:                 make(chan []byte, neighborQueueSize),
+		protocolVersion:       server.ProtocolVersion(conn),
+		encrypted:             server.IsEncrypted(conn),
+		reputation:            newReputation(),
+		announcementsSignal:   make(chan struct{}, 1),
 		closing:               make(chan struct{}),
 		connectionEstablished: time.Now(),
 	}
+
+	// count and throttle the received packets before they are processed
+	n.Events.ReceiveMessage.Attach(events.NewClosure(func(data []byte) {
+		n.packetsRead.Inc()
+		n.throttle(n.inboundLimiter, len(data))
+	}))
+
+	return n
 }
 
 // ConnectionEstablished returns the connection established.
@@ -64,6 +125,76 @@ func (n *Neighbor) ConnectionEstablished() time.Time {
 	return n.connectionEstablished
 }
 
+// ProtocolVersion returns the gossip protocol version that was negotiated with the neighbor.
+func (n *Neighbor) ProtocolVersion() uint32 {
+	return n.protocolVersion
+}
+
+// IsEncrypted returns whether the connection to the neighbor is encrypted and authenticated.
+func (n *Neighbor) IsEncrypted() bool {
+	return n.encrypted
+}
+
+// Group returns the NeighborsGroup the neighbor belongs to.
+func (n *Neighbor) Group() NeighborsGroup {
+	return n.group
+}
+
+// PacketsRead returns the total number of packets received from the neighbor.
+func (n *Neighbor) PacketsRead() uint64 {
+	return n.packetsRead.Load()
+}
+
+// PacketsWritten returns the total number of packets sent to the neighbor.
+func (n *Neighbor) PacketsWritten() uint64 {
+	return n.packetsWritten.Load()
+}
+
+// Reputation returns the current reputation score of the neighbor. The neighbor is banned, once it falls below zero.
+func (n *Neighbor) Reputation() int {
+	return int(n.reputation.value())
+}
+
+// setRateLimits sets the inbound and outbound bandwidth limits in bytes per second and the number of requests per
+// second the neighbor is allowed to make. It must be called before Listen and a limit of 0 disables the limitation.
+func (n *Neighbor) setRateLimits(inbound int, outbound int, requests int) {
+	n.inboundLimiter = newTokenBucket(inbound, maxPacketSize)
+	n.outboundLimiter = newTokenBucket(outbound, maxPacketSize)
+	n.requestLimiter = newTokenBucket(requests, 2*requests)
+}
+
+// allowRequests returns whether the neighbor is allowed to request the given number of objects.
+func (n *Neighbor) allowRequests(count int) bool {
+	return n.requestLimiter.allow(count)
+}
+
+// throttle blocks until the rate limiter allows n more bytes or the neighbor is closed.
+func (n *Neighbor) throttle(limiter *tokenBucket, size int) {
+	delay := limiter.reserve(size)
+	if delay <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-n.closing:
+	}
+}
+
+// writePacket writes the packet to the connection respecting the outbound bandwidth limit.
+func (n *Neighbor) writePacket(packet []byte) error {
+	n.throttle(n.outboundLimiter, len(packet))
+	if _, err := n.BufferedConnection.Write(packet); err != nil {
+		return err
+	}
+	n.packetsWritten.Inc()
+
+	return nil
+}
+
 // Listen starts the communication to the neighbor.
 func (n *Neighbor) Listen() {
 	n.wg.Add(2)
@@ -105,7 +236,13 @@ func (n *Neighbor) writeLoop() {
 			if len(msg) == 0 {
 				continue
 			}
-			if _, err := n.BufferedConnection.Write(msg); err != nil {
+			if err := n.writePacket(msg); err != nil {
+				n.log.Warnw("Write error", "err", err)
+				_ = n.BufferedConnection.Close()
+				return
+			}
+		case <-n.announcementsSignal:
+			if err := n.writeAnnouncements(); err != nil {
 				n.log.Warnw("Write error", "err", err)
 				_ = n.BufferedConnection.Close()
 				return
@@ -116,6 +253,26 @@ func (n *Neighbor) writeLoop() {
 	}
 }
 
+// writeAnnouncements sends all pending announcements to the neighbor using as few packets as possible.
+func (n *Neighbor) writeAnnouncements() error {
+	n.announcementsMutex.Lock()
+	ids := n.announcements
+	n.announcements = nil
+	n.announcementsMutex.Unlock()
+
+	for start := 0; start < len(ids); start += maxAnnouncementIDs {
+		end := start + maxAnnouncementIDs
+		if end > len(ids) {
+			end = len(ids)
+		}
+		if err := n.writePacket(marshal(&pb.MessageAnnouncement{Ids: ids[start:end]})); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (n *Neighbor) readLoop() {
 	defer n.wg.Done()
 
@@ -164,3 +321,26 @@ func (n *Neighbor) Write(b []byte) (int, error) {
 		return 0, nil
 	}
 }
+
+// Announce adds the given message id to the announcements that are sent to the neighbor. All the ids that are announced
+// before the neighbor is able to write them, are combined into a single packet.
+func (n *Neighbor) Announce(messageID []byte) error {
+	n.announcementsMutex.Lock()
+	if len(n.announcements) >= maxPendingAnnouncements {
+		n.announcementsMutex.Unlock()
+		if n.messagesDropped.Inc() >= droppedMessagesThreshold {
+			n.messagesDropped.Store(0)
+			return ErrNeighborQueueFull
+		}
+		return nil
+	}
+	n.announcements = append(n.announcements, messageID)
+	n.announcementsMutex.Unlock()
+
+	// wake up the write loop, if it is not already signaled
+	select {
+	case n.announcementsSignal <- struct{}{}:
+	default:
+	}
+	return nil
+}
@@ -11,6 +11,11 @@ type PacketType uint
 const (
 	PacketMessage PacketType = 20 + iota
 	PacketMessageRequest
+	PacketSnapshotRequest
+	PacketSnapshotChunk
+	PacketMessageAnnouncement
+	PacketMessageRequestBatch
+	PacketMessageBatch
 )
 
 // Packet extends the proto.Message interface with additional util functions.
@@ -34,3 +39,33 @@ func (m *MessageRequest) Name() string { return "message_request" }
 
 // Type returns the packet type id of the message request packet.
 func (m *MessageRequest) Type() PacketType { return PacketMessageRequest }
+
+// Name returns the name of the snapshot request packet.
+func (m *SnapshotRequest) Name() string { return "snapshot_request" }
+
+// Type returns the packet type id of the snapshot request packet.
+func (m *SnapshotRequest) Type() PacketType { return PacketSnapshotRequest }
+
+// Name returns the name of the snapshot chunk packet.
+func (m *SnapshotChunk) Name() string { return "snapshot_chunk" }
+
+// Type returns the packet type id of the snapshot chunk packet.
+func (m *SnapshotChunk) Type() PacketType { return PacketSnapshotChunk }
+
+// Name returns the name of the message announcement packet.
+func (m *MessageAnnouncement) Name() string { return "message_announcement" }
+
+// Type returns the packet type id of the message announcement packet.
+func (m *MessageAnnouncement) Type() PacketType { return PacketMessageAnnouncement }
+
+// Name returns the name of the message request batch packet.
+func (m *MessageRequestBatch) Name() string { return "message_request_batch" }
+
+// Type returns the packet type id of the message request batch packet.
+func (m *MessageRequestBatch) Type() PacketType { return PacketMessageRequestBatch }
+
+// Name returns the name of the message batch packet.
+func (m *MessageBatch) Name() string { return "message_batch" }
+
+// Type returns the packet type id of the message batch packet.
+func (m *MessageBatch) Type() PacketType { return PacketMessageBatch }
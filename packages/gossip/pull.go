@@ -0,0 +1,359 @@
+package gossip
+
+import (
+	"time"
+
+	pb "github.com/iotaledger/goshimmer/packages/gossip/proto"
+	"github.com/iotaledger/goshimmer/packages/tangle"
+	"github.com/iotaledger/hive.go/identity"
+	"golang.org/x/crypto/blake2b"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// pullRequestTimeout defines the time after which an announced message is requested again, if it was not received.
+	pullRequestTimeout = 2 * time.Second
+
+	// pullRequestExpiry defines the time after which an unanswered request is dropped, so that late responses are
+	// considered unsolicited.
+	pullRequestExpiry = time.Minute
+
+	// maxAnnouncersPerMessage defines the maximum number of neighbors that are remembered as alternative sources of an
+	// announced message.
+	maxAnnouncersPerMessage = 8
+
+	// maxPullRequestsPerNeighbor defines the maximum number of pending requests per neighbor.
+	maxPullRequestsPerNeighbor = maxPendingAnnouncements
+
+	// maxMessageBatchSize defines the maximum size of a marshaled message batch (without the packet type).
+	maxMessageBatchSize = maxPacketSize - 1
+)
+
+// pendingAnnouncement contains the neighbor an announced message was requested from and the other neighbors that
+// announced it, so that the message can be requested from them if the request times out.
+type pendingAnnouncement struct {
+	requestedFrom identity.ID
+	requested     time.Time
+	announcers    []identity.ID
+}
+
+// MessageExistsFunc defines a function that returns whether the message with the given id is already known.
+type MessageExistsFunc func(messageID tangle.MessageID) bool
+
+// MessageExists is a ManagerOption that sets the function that is used to decide whether an announced message needs to
+// be requested from the neighbor.
+func MessageExists(f MessageExistsFunc) ManagerOption {
+	return func(m *Manager) {
+		m.messageExistsFunc = f
+	}
+}
+
+// processMessageAnnouncement requests all the announced messages that are neither known nor already requested.
+func (m *Manager) processMessageAnnouncement(data []byte, nbr *Neighbor) {
+	packet := new(pb.MessageAnnouncement)
+	if err := proto.Unmarshal(data[1:], packet); err != nil {
+		m.log.Debugw("invalid packet", "err", err)
+		return
+	}
+
+	ids := make([][]byte, 0, len(packet.GetIds()))
+	for _, idBytes := range packet.GetIds() {
+		if len(idBytes) != tangle.MessageIDLength {
+			m.log.Debugw("invalid message id", "len", len(idBytes))
+			continue
+		}
+		msgID, _, err := tangle.MessageIDFromBytes(idBytes)
+		if err != nil {
+			m.log.Debugw("invalid message id", "err", err)
+			continue
+		}
+		if m.messageExistsFunc != nil && m.messageExistsFunc(msgID) {
+			continue
+		}
+		if !m.startPullRequest(nbr.ID(), msgID) {
+			continue
+		}
+		ids = append(ids, idBytes)
+	}
+
+	for start := 0; start < len(ids); start += maxAnnouncementIDs {
+		end := start + maxAnnouncementIDs
+		if end > len(ids) {
+			end = len(ids)
+		}
+		if _, err := nbr.Write(marshal(&pb.MessageRequestBatch{Ids: ids[start:end]})); err != nil {
+			m.log.Warnw("send error", "peer-id", nbr.ID(), "err", err)
+		}
+	}
+}
+
+// processMessageRequestBatch answers the requested messages with as few message batches as possible.
+func (m *Manager) processMessageRequestBatch(data []byte, nbr *Neighbor) {
+	packet := new(pb.MessageRequestBatch)
+	if err := proto.Unmarshal(data[1:], packet); err != nil {
+		m.log.Debugw("invalid packet", "err", err)
+		return
+	}
+	if !nbr.allowRequests(len(packet.GetIds())) {
+		m.ReportMisbehavior(nbr.ID(), MisbehaviorRequestFlood)
+		return
+	}
+
+	batch := &pb.MessageBatch{}
+	batchSize := 0
+	for _, idBytes := range packet.GetIds() {
+		msgID, _, err := tangle.MessageIDFromBytes(idBytes)
+		if err != nil {
+			m.log.Debugw("invalid message id", "err", err)
+			continue
+		}
+
+		msgBytes, err := m.loadMessageFunc(msgID)
+		if err != nil {
+			m.log.Debugw("error loading message", "msg-id", msgID, "err", err)
+			continue
+		}
+
+		entrySize := messageBatchEntrySize(msgBytes)
+		if entrySize > maxMessageBatchSize {
+			m.log.Debugw("message too large", "msg-id", msgID, "len", len(msgBytes))
+			continue
+		}
+		if batchSize+entrySize > maxMessageBatchSize {
+			_, _ = nbr.Write(marshal(batch))
+			batch, batchSize = &pb.MessageBatch{}, 0
+		}
+		batch.Data = append(batch.Data, msgBytes)
+		batchSize += entrySize
+	}
+
+	if len(batch.GetData()) > 0 {
+		_, _ = nbr.Write(marshal(batch))
+	}
+}
+
+// processMessageBatch triggers the MessageReceived event for every message of the batch. Neighbors that send messages
+// which were not requested lose reputation.
+func (m *Manager) processMessageBatch(data []byte, nbr *Neighbor) {
+	packet := new(pb.MessageBatch)
+	if err := proto.Unmarshal(data[1:], packet); err != nil {
+		m.log.Debugw("invalid packet", "err", err)
+		return
+	}
+
+	unsolicited := false
+	for _, msgBytes := range packet.GetData() {
+		if !m.completePullRequest(nbr.ID(), msgBytes) {
+			unsolicited = true
+		}
+		m.events.MessageReceived.Trigger(&MessageReceivedEvent{Data: msgBytes, Peer: nbr.Peer})
+	}
+
+	if unsolicited {
+		m.ReportMisbehavior(nbr.ID(), MisbehaviorUnsolicitedResponse)
+	}
+}
+
+// startPullRequest marks the message with the given id as requested from the given neighbor. It returns false if the
+// message has already been requested from another neighbor and the request did not time out, yet, or if the neighbor
+// has too many pending requests. In the first case the neighbor is remembered as an alternative source of the message.
+func (m *Manager) startPullRequest(nbrID identity.ID, msgID tangle.MessageID) bool {
+	m.pullRequestsMutex.Lock()
+	defer m.pullRequestsMutex.Unlock()
+
+	now := time.Now()
+	if announcement, exists := m.announcements[msgID]; exists && (now.Sub(announcement.requested) < pullRequestTimeout || len(announcement.announcers) > 0) {
+		if announcement.requestedFrom != nbrID && !containsID(announcement.announcers, nbrID) && len(announcement.announcers) < maxAnnouncersPerMessage {
+			announcement.announcers = append(announcement.announcers, nbrID)
+		}
+		return false
+	}
+
+	if !m.storePullRequest(nbrID, msgID, now) {
+		return false
+	}
+	m.announcements[msgID] = &pendingAnnouncement{requestedFrom: nbrID, requested: now}
+
+	return true
+}
+
+// retryPullRequestsLoop periodically requests the announced messages whose requests timed out from the next neighbor
+// that announced them.
+func (m *Manager) retryPullRequestsLoop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(pullRequestTimeout / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.retryPullRequests(time.Now())
+		case <-m.closing:
+			return
+		}
+	}
+}
+
+// retryPullRequests sends a MessageRequestBatch for every announced message whose request timed out to the next
+// neighbor that announced it.
+func (m *Manager) retryPullRequests(now time.Time) {
+	for nbrID, ids := range m.timedOutPullRequests(now) {
+		for _, nbr := range m.getNeighbors(nbrID) {
+			for start := 0; start < len(ids); start += maxAnnouncementIDs {
+				end := start + maxAnnouncementIDs
+				if end > len(ids) {
+					end = len(ids)
+				}
+				if _, err := nbr.Write(marshal(&pb.MessageRequestBatch{Ids: ids[start:end]})); err != nil {
+					m.log.Warnw("send error", "peer-id", nbr.ID(), "err", err)
+				}
+			}
+		}
+	}
+}
+
+// timedOutPullRequests moves the timed out requests of the announced messages to the next neighbor that announced them
+// and returns the ids that need to be requested per neighbor. Announcements without further announcers are dropped
+// once their request expired.
+func (m *Manager) timedOutPullRequests(now time.Time) (retries map[identity.ID][][]byte) {
+	m.pullRequestsMutex.Lock()
+	defer m.pullRequestsMutex.Unlock()
+
+	retries = make(map[identity.ID][][]byte)
+	for msgID, announcement := range m.announcements {
+		if now.Sub(announcement.requested) < pullRequestTimeout {
+			continue
+		}
+
+		for len(announcement.announcers) > 0 {
+			nbrID := announcement.announcers[0]
+			announcement.announcers = announcement.announcers[1:]
+			if !m.storePullRequest(nbrID, msgID, now) {
+				continue
+			}
+
+			announcement.requestedFrom, announcement.requested = nbrID, now
+			retries[nbrID] = append(retries[nbrID], msgID.Bytes())
+			break
+		}
+
+		if len(announcement.announcers) == 0 && now.Sub(announcement.requested) >= pullRequestExpiry {
+			delete(m.announcements, msgID)
+		}
+	}
+
+	return retries
+}
+
+// addPullRequests marks the messages with the given ids as requested from the given neighbor, so that its responses are
+// accepted. It returns the ids that could be stored without exceeding the pending requests of the neighbor.
+func (m *Manager) addPullRequests(nbrID identity.ID, messageIDs [][]byte) (added [][]byte) {
+	m.pullRequestsMutex.Lock()
+	defer m.pullRequestsMutex.Unlock()
+
+	now := time.Now()
+	added = make([][]byte, 0, len(messageIDs))
+	for _, idBytes := range messageIDs {
+		msgID, _, err := tangle.MessageIDFromBytes(idBytes)
+		if err != nil {
+			continue
+		}
+		if m.storePullRequest(nbrID, msgID, now) {
+			added = append(added, idBytes)
+		}
+	}
+
+	return added
+}
+
+// storePullRequest stores the request of the given message from the given neighbor and returns false if the neighbor
+// has too many pending requests. The caller must hold the lock.
+func (m *Manager) storePullRequest(nbrID identity.ID, msgID tangle.MessageID, now time.Time) bool {
+	requests, exists := m.pullRequests[nbrID]
+	if !exists {
+		requests = make(map[tangle.MessageID]time.Time)
+		m.pullRequests[nbrID] = requests
+	}
+
+	// remove the expired requests before rejecting new ones
+	if _, pending := requests[msgID]; !pending && len(requests) >= maxPullRequestsPerNeighbor {
+		for id, requested := range requests {
+			if now.Sub(requested) >= pullRequestExpiry {
+				delete(requests, id)
+			}
+		}
+		if len(requests) >= maxPullRequestsPerNeighbor {
+			return false
+		}
+	}
+	requests[msgID] = now
+
+	return true
+}
+
+// completePullRequest removes the pending request of the given message from the given neighbor and returns whether it
+// was requested and did not expire, yet. The message is no longer requested from other neighbors that announced it.
+func (m *Manager) completePullRequest(nbrID identity.ID, msgBytes []byte) bool {
+	msgID := tangle.MessageID(blake2b.Sum256(msgBytes))
+
+	m.pullRequestsMutex.Lock()
+	defer m.pullRequestsMutex.Unlock()
+
+	delete(m.announcements, msgID)
+
+	requested, exists := m.pullRequests[nbrID][msgID]
+	if !exists {
+		return false
+	}
+	delete(m.pullRequests[nbrID], msgID)
+
+	return time.Since(requested) < pullRequestExpiry
+}
+
+// dropPullRequests removes all the pending requests of the given neighbor and no longer considers it as a source of
+// announced messages.
+func (m *Manager) dropPullRequests(nbrID identity.ID) {
+	m.pullRequestsMutex.Lock()
+	defer m.pullRequestsMutex.Unlock()
+
+	delete(m.pullRequests, nbrID)
+	for _, announcement := range m.announcements {
+		// the message is requested from the next announcer right away
+		if announcement.requestedFrom == nbrID {
+			announcement.requested = time.Time{}
+		}
+		for i, announcer := range announcement.announcers {
+			if announcer == nbrID {
+				announcement.announcers = append(announcement.announcers[:i], announcement.announcers[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// containsID returns true if the given ids contain the given id.
+func containsID(ids []identity.ID, id identity.ID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// messageBatchEntrySize returns the number of bytes that are needed to add the given message to a marshaled batch.
+func messageBatchEntrySize(msgBytes []byte) int {
+	// field tag, length prefix and the message itself
+	return 1 + varintSize(uint64(len(msgBytes))) + len(msgBytes)
+}
+
+// varintSize returns the number of bytes of the protobuf varint encoding of the given value.
+func varintSize(v uint64) int {
+	size := 1
+	for v >= 0x80 {
+		v >>= 7
+		size++
+	}
+	return size
+}
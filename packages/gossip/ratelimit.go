@@ -0,0 +1,78 @@
+package gossip
+
+import (
+	"sync"
+	"time"
+)
+
+// tokenBucket is a rate limiter that allows bursts of up to burst tokens and refills at a constant rate. A nil
+// tokenBucket does not limit at all.
+type tokenBucket struct {
+	mu      sync.Mutex
+	rate    float64 // tokens that are added per second
+	burst   float64 // maximum number of tokens
+	tokens  float64 // currently available tokens
+	updated time.Time
+}
+
+// newTokenBucket creates a new tokenBucket that is initially full. It returns nil, if the rate is not positive.
+func newTokenBucket(rate int, burst int) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < rate {
+		burst = rate
+	}
+
+	return &tokenBucket{
+		rate:    float64(rate),
+		burst:   float64(burst),
+		tokens:  float64(burst),
+		updated: time.Now(),
+	}
+}
+
+// allow takes n tokens from the bucket and returns true, if there are enough tokens available.
+func (b *tokenBucket) allow(n int) bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(time.Now())
+	if b.tokens < float64(n) {
+		return false
+	}
+	b.tokens -= float64(n)
+
+	return true
+}
+
+// reserve takes n tokens from the bucket, even if they are not available, yet, and returns how long the caller has to
+// wait until the tokens are refilled.
+func (b *tokenBucket) reserve(n int) time.Duration {
+	if b == nil {
+		return 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(time.Now())
+	b.tokens -= float64(n)
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.updated).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.updated = now
+}
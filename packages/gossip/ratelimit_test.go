@@ -0,0 +1,32 @@
+package gossip
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket(t *testing.T) {
+	bucket := newTokenBucket(100, 200)
+
+	// the bucket starts full
+	assert.True(t, bucket.allow(200))
+	assert.False(t, bucket.allow(1))
+
+	// reserving more tokens than available returns the time to wait for them
+	delay := bucket.reserve(50)
+	assert.InDelta(t, float64(500*time.Millisecond), float64(delay), float64(20*time.Millisecond))
+
+	time.Sleep(delay)
+	assert.True(t, bucket.allow(0))
+	assert.False(t, bucket.allow(10))
+}
+
+func TestTokenBucketUnlimited(t *testing.T) {
+	bucket := newTokenBucket(0, 0)
+
+	assert.Nil(t, bucket)
+	assert.True(t, bucket.allow(maxPacketSize))
+	assert.Zero(t, bucket.reserve(maxPacketSize))
+}
@@ -0,0 +1,166 @@
+package gossip
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iotaledger/hive.go/identity"
+)
+
+const (
+	// maxReputation defines the reputation of a new neighbor and the maximum reputation a neighbor can reach.
+	maxReputation = 100
+
+	// banThreshold defines the reputation below which a neighbor is dropped and banned.
+	banThreshold = 0
+
+	// reputationRecoveryRate defines the number of reputation points a neighbor regains per second.
+	reputationRecoveryRate = 1
+
+	// defaultBanDuration defines how long a misbehaving neighbor is banned by default.
+	defaultBanDuration = 10 * time.Minute
+)
+
+// Misbehavior is the type of a misbehavior of a neighbor that decreases its reputation.
+type Misbehavior uint8
+
+const (
+	// MisbehaviorInvalidMessage is reported when a neighbor sends a message that does not pass the validation
+	// (i.e. an invalid signature or PoW).
+	MisbehaviorInvalidMessage Misbehavior = iota
+
+	// MisbehaviorUnsolicitedResponse is reported when a neighbor sends a response that was never requested.
+	MisbehaviorUnsolicitedResponse
+
+	// MisbehaviorRequestFlood is reported when a neighbor exceeds the request rate limit.
+	MisbehaviorRequestFlood
+)
+
+// String returns a human readable version of the Misbehavior.
+func (m Misbehavior) String() string {
+	switch m {
+	case MisbehaviorInvalidMessage:
+		return "MisbehaviorInvalidMessage"
+	case MisbehaviorUnsolicitedResponse:
+		return "MisbehaviorUnsolicitedResponse"
+	case MisbehaviorRequestFlood:
+		return "MisbehaviorRequestFlood"
+	default:
+		return "MisbehaviorUnknown"
+	}
+}
+
+// penalty returns the number of reputation points a neighbor loses for the Misbehavior.
+func (m Misbehavior) penalty() float64 {
+	switch m {
+	case MisbehaviorInvalidMessage:
+		return 25
+	case MisbehaviorUnsolicitedResponse:
+		return 5
+	case MisbehaviorRequestFlood:
+		return 10
+	default:
+		return 0
+	}
+}
+
+// BanDuration is a ManagerOption that sets how long a neighbor is banned after its reputation dropped below the
+// threshold.
+func BanDuration(duration time.Duration) ManagerOption {
+	return func(m *Manager) {
+		m.banDuration = duration
+	}
+}
+
+// ReportMisbehavior decreases the reputation of the neighbor with the given ID. The neighbor is dropped and banned, if
+// its reputation falls below the threshold.
+func (m *Manager) ReportMisbehavior(id identity.ID, misbehavior Misbehavior) {
+	neighbors := m.getNeighborsByID([]identity.ID{id})
+	if len(neighbors) == 0 {
+		return
+	}
+
+	nbr := neighbors[0]
+	if nbr.reputation.penalize(misbehavior.penalty()) >= banThreshold {
+		nbr.log.Debugw("misbehavior", "type", misbehavior, "reputation", nbr.Reputation())
+		return
+	}
+
+	m.banNeighbor(id)
+	nbr.log.Infow("banned neighbor", "type", misbehavior, "duration", m.banDuration)
+}
+
+// IsBanned returns whether the peer with the given ID is currently banned.
+func (m *Manager) IsBanned(id identity.ID) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.isBanned(id)
+}
+
+func (m *Manager) isBanned(id identity.ID) bool {
+	bannedUntil, banned := m.bannedPeers[id]
+	if banned && time.Now().After(bannedUntil) {
+		delete(m.bannedPeers, id)
+		return false
+	}
+
+	return banned
+}
+
+func (m *Manager) banNeighbor(id identity.ID) {
+	m.mu.Lock()
+	m.bannedPeers[id] = time.Now().Add(m.banDuration)
+	m.mu.Unlock()
+
+	// the misbehavior might be reported from within the neighbor's read loop, so the neighbor is dropped asynchronously
+	go func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		if err := m.dropNeighbor(id); err != nil {
+			m.log.Debugw("error dropping banned neighbor", "id", id, "err", err)
+		}
+	}()
+}
+
+// reputation tracks the reputation score of a neighbor that slowly recovers over time.
+type reputation struct {
+	mu      sync.Mutex
+	score   float64
+	updated time.Time
+}
+
+func newReputation() *reputation {
+	return &reputation{
+		score:   maxReputation,
+		updated: time.Now(),
+	}
+}
+
+// value returns the current reputation score.
+func (r *reputation) value() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.recover(time.Now())
+	return r.score
+}
+
+// penalize decreases the reputation by the given penalty and returns the new score.
+func (r *reputation) penalize(penalty float64) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.recover(time.Now())
+	r.score -= penalty
+	return r.score
+}
+
+func (r *reputation) recover(now time.Time) {
+	r.score += now.Sub(r.updated).Seconds() * reputationRecoveryRate
+	if r.score > maxReputation {
+		r.score = maxReputation
+	}
+	r.updated = now
+}
@@ -0,0 +1,81 @@
+package gossip
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/iotaledger/goshimmer/packages/gossip/proto"
+	"github.com/iotaledger/goshimmer/packages/tangle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNeighborCounters(t *testing.T) {
+	mgrA, closeA, peerA := newTestManager(t, "A")
+	defer closeA()
+	mgrB, closeB, peerB := newTestManager(t, "B")
+	defer closeB()
+
+	connectTestManagers(t, mgrA, peerA, mgrB, peerB)
+
+	id := tangle.MessageID{}
+	mgrB.RequestMessage(id[:])
+	time.Sleep(graceTime)
+
+	nbrA, nbrB := mgrA.AllNeighbors()[0], mgrB.AllNeighbors()[0]
+	assert.EqualValues(t, 1, nbrB.PacketsWritten())
+	assert.EqualValues(t, 1, nbrA.PacketsRead())
+	assert.EqualValues(t, 1, nbrA.PacketsWritten())
+	assert.EqualValues(t, 1, nbrB.PacketsRead())
+	assert.Equal(t, nbrA.BytesRead(), nbrB.BytesWritten())
+	assert.Equal(t, maxReputation, nbrA.Reputation())
+}
+
+func TestBanMisbehavingNeighbor(t *testing.T) {
+	mgrA, closeA, peerA := newTestManager(t, "A", BanDuration(time.Minute))
+	defer closeA()
+	mgrB, closeB, peerB := newTestManager(t, "B")
+	defer closeB()
+
+	connectTestManagers(t, mgrA, peerA, mgrB, peerB)
+
+	// a single misbehavior only decreases the reputation
+	mgrA.ReportMisbehavior(peerB.ID(), MisbehaviorInvalidMessage)
+	require.Len(t, mgrA.AllNeighbors(), 1)
+	assert.Less(t, mgrA.AllNeighbors()[0].Reputation(), maxReputation)
+	assert.False(t, mgrA.IsBanned(peerB.ID()))
+
+	for i := 0; i < maxReputation/int(MisbehaviorInvalidMessage.penalty()); i++ {
+		mgrA.ReportMisbehavior(peerB.ID(), MisbehaviorInvalidMessage)
+	}
+	assert.True(t, mgrA.IsBanned(peerB.ID()))
+	assert.Eventually(t, func() bool { return len(mgrA.AllNeighbors()) == 0 }, time.Second, graceTime)
+
+	// banned peers cannot reconnect
+	assert.Equal(t, ErrNeighborBanned, mgrA.AddOutbound(peerB, NeighborsGroupAuto))
+}
+
+func TestRequestFlood(t *testing.T) {
+	const requestRateLimit = 10
+
+	mgrA, closeA, peerA := newTestManager(t, "A", RequestRateLimit(requestRateLimit))
+	defer closeA()
+	mgrB, closeB, peerB := newTestManager(t, "B")
+	defer closeB()
+
+	connectTestManagers(t, mgrA, peerA, mgrB, peerB)
+
+	// request more messages than allowed by the burst of the rate limit
+	ids := make([][]byte, 2*requestRateLimit+1)
+	for i := range ids {
+		id := tangle.MessageID{byte(i)}
+		ids[i] = id[:]
+	}
+	mgrB.send(marshal(&pb.MessageRequestBatch{Ids: ids}))
+
+	assert.Eventually(t, func() bool {
+		nbr := mgrA.AllNeighbors()[0]
+		return nbr.Reputation() <= maxReputation-int(MisbehaviorRequestFlood.penalty())
+	}, time.Second, graceTime)
+	assert.Zero(t, mgrB.AllNeighbors()[0].PacketsRead())
+}
@@ -6,6 +6,7 @@ import (
 
 	pb "github.com/iotaledger/goshimmer/packages/gossip/server/proto"
 	"github.com/iotaledger/hive.go/autopeering/server"
+	"golang.org/x/crypto/curve25519"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -14,41 +15,78 @@ const (
 	handshakeExpiration = 20 * time.Second
 )
 
+const (
+	// ProtocolV1 is the gossip protocol that pushes every full message to all neighbors. It is also used by peers that
+	// do not negotiate a protocol version during the handshake.
+	ProtocolV1 uint32 = 1 + iota
+
+	// ProtocolV2 is the gossip protocol that announces the ids of new messages and lets the neighbors pull the
+	// messages that they have not seen, yet. Messages and requests are batched into a single packet where possible.
+	ProtocolV2
+
+	// LatestProtocolVersion is the highest gossip protocol version that is supported by this node.
+	LatestProtocolVersion = ProtocolV2
+)
+
+// negotiateProtocolVersion returns the gossip protocol version that is used by two peers that support the given versions.
+func negotiateProtocolVersion(local uint32, remote uint32) uint32 {
+	// peers without negotiation only speak the first version of the protocol
+	if local < ProtocolV1 || remote < ProtocolV1 {
+		return ProtocolV1
+	}
+	if remote < local {
+		return remote
+	}
+
+	return local
+}
+
+// isValidEphemeralKey checks whether the given ephemeral key is either missing or a valid X25519 public key.
+func isValidEphemeralKey(key []byte) bool {
+	return len(key) == 0 || len(key) == curve25519.PointSize
+}
+
 // isExpired checks whether the given UNIX time stamp is too far in the past.
 func isExpired(ts int64) bool {
 	return time.Since(time.Unix(ts, 0)) >= handshakeExpiration
 }
 
-func newHandshakeRequest(toAddr string) ([]byte, error) {
+func newHandshakeRequest(toAddr string, protocolVersion uint32, ephemeralKey []byte) ([]byte, error) {
 	m := &pb.HandshakeRequest{
-		Version:   versionNum,
-		To:        toAddr,
-		Timestamp: time.Now().Unix(),
+		Version:       versionNum,
+		To:            toAddr,
+		Timestamp:     time.Now().Unix(),
+		GossipVersion: protocolVersion,
+		EphemeralKey:  ephemeralKey,
 	}
 	return proto.Marshal(m)
 }
 
-func newHandshakeResponse(reqData []byte) ([]byte, error) {
+func newHandshakeResponse(reqData []byte, protocolVersion uint32, ephemeralKey []byte) ([]byte, error) {
 	m := &pb.HandshakeResponse{
-		ReqHash: server.PacketHash(reqData),
+		ReqHash:       server.PacketHash(reqData),
+		GossipVersion: protocolVersion,
+		EphemeralKey:  ephemeralKey,
 	}
 	return proto.Marshal(m)
 }
 
-func (t *TCP) validateHandshakeRequest(reqData []byte) bool {
+// validateHandshakeRequest validates the handshake request and returns the gossip protocol version that is used for
+// the connection together with the ephemeral key of the peer, if it supports encryption.
+func (t *TCP) validateHandshakeRequest(reqData []byte) (uint32, []byte, bool) {
 	m := new(pb.HandshakeRequest)
 	if err := proto.Unmarshal(reqData, m); err != nil {
 		t.log.Debugw("invalid handshake",
 			"err", err,
 		)
-		return false
+		return 0, nil, false
 	}
 	if m.GetVersion() != versionNum {
 		t.log.Debugw("invalid handshake",
 			"version", m.GetVersion(),
 			"want", versionNum,
 		)
-		return false
+		return 0, nil, false
 	}
 	if isExpired(m.GetTimestamp()) {
 		t.log.Debugw("invalid handshake",
@@ -56,23 +94,39 @@ func (t *TCP) validateHandshakeRequest(reqData []byte) bool {
 		)
 	}
 
-	return true
+	if !isValidEphemeralKey(m.GetEphemeralKey()) {
+		t.log.Debugw("invalid handshake",
+			"ephemeralKey", m.GetEphemeralKey(),
+		)
+		return 0, nil, false
+	}
+
+	return negotiateProtocolVersion(t.maxProtocolVersion, m.GetGossipVersion()), m.GetEphemeralKey(), true
 }
 
-func (t *TCP) validateHandshakeResponse(resData []byte, reqData []byte) bool {
+// validateHandshakeResponse validates the handshake response and returns the gossip protocol version that was chosen
+// by the peer together with its ephemeral key, if the connection is encrypted.
+func (t *TCP) validateHandshakeResponse(resData []byte, reqData []byte) (uint32, []byte, bool) {
 	m := new(pb.HandshakeResponse)
 	if err := proto.Unmarshal(resData, m); err != nil {
 		t.log.Debugw("invalid handshake",
 			"err", err,
 		)
-		return false
+		return 0, nil, false
 	}
 	if !bytes.Equal(m.GetReqHash(), server.PacketHash(reqData)) {
 		t.log.Debugw("invalid handshake",
 			"hash", m.GetReqHash(),
 		)
-		return false
+		return 0, nil, false
+	}
+
+	if !isValidEphemeralKey(m.GetEphemeralKey()) {
+		t.log.Debugw("invalid handshake",
+			"ephemeralKey", m.GetEphemeralKey(),
+		)
+		return 0, nil, false
 	}
 
-	return true
+	return negotiateProtocolVersion(t.maxProtocolVersion, m.GetGossipVersion()), m.GetEphemeralKey(), true
 }
@@ -0,0 +1,253 @@
+package server
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+)
+
+var (
+	// ErrEncryptionRequired is returned when the remote peer does not support an encrypted connection, but the local
+	// encryption policy requires it.
+	ErrEncryptionRequired = errors.New("encryption required")
+	// ErrInvalidRecord is returned when an encrypted record could not be authenticated.
+	ErrInvalidRecord = errors.New("invalid encrypted record")
+)
+
+const (
+	// maxRecordPayloadSize defines the maximum number of plaintext bytes that are sealed in a single record.
+	maxRecordPayloadSize = 16 * 1024
+	// recordHeaderSize defines the size of the length prefix of every record.
+	recordHeaderSize = 4
+
+	// keyDerivationInfo binds the derived session keys to the gossip transport.
+	keyDerivationInfo = "goshimmer gossip transport"
+)
+
+// region EncryptionPolicy /////////////////////////////////////////////////////////////////////////////////////////////
+
+// EncryptionPolicy defines whether the TCP server offers, accepts or requires encrypted connections.
+type EncryptionPolicy uint8
+
+const (
+	// EncryptionDisabled never offers nor accepts encrypted connections.
+	EncryptionDisabled EncryptionPolicy = iota
+	// EncryptionPreferred encrypts the connection whenever the remote peer supports it.
+	EncryptionPreferred
+	// EncryptionRequired rejects all peers that do not support encrypted connections.
+	EncryptionRequired
+)
+
+// String returns a human readable version of the EncryptionPolicy.
+func (e EncryptionPolicy) String() string {
+	switch e {
+	case EncryptionDisabled:
+		return "disabled"
+	case EncryptionPreferred:
+		return "preferred"
+	case EncryptionRequired:
+		return "required"
+	default:
+		return fmt.Sprintf("EncryptionPolicy(%d)", uint8(e))
+	}
+}
+
+// ParseEncryptionPolicy returns the EncryptionPolicy with the given name.
+func ParseEncryptionPolicy(name string) (EncryptionPolicy, error) {
+	for _, policy := range []EncryptionPolicy{EncryptionDisabled, EncryptionPreferred, EncryptionRequired} {
+		if strings.EqualFold(name, policy.String()) {
+			return policy, nil
+		}
+	}
+
+	return EncryptionDisabled, fmt.Errorf("unknown encryption policy: %s", name)
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region ephemeralKey /////////////////////////////////////////////////////////////////////////////////////////////////
+
+// ephemeralKey is the X25519 key pair that is generated for a single handshake. Its public key is part of the signed
+// handshake packets, so that it is authenticated by the identity of the peer.
+type ephemeralKey struct {
+	private []byte
+	public  []byte
+}
+
+// newEphemeralKey generates a new random ephemeral key pair.
+func newEphemeralKey() (*ephemeralKey, error) {
+	private := make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(private); err != nil {
+		return nil, err
+	}
+	public, err := curve25519.X25519(private, curve25519.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ephemeralKey{private: private, public: public}, nil
+}
+
+// publicKey returns the public key of the key pair or nil if no key pair was generated.
+func (e *ephemeralKey) publicKey() []byte {
+	if e == nil {
+		return nil
+	}
+
+	return e.public
+}
+
+// sharedSecret computes the X25519 shared secret with the given remote public key.
+func (e *ephemeralKey) sharedSecret(remote []byte) ([]byte, error) {
+	return curve25519.X25519(e.private, remote)
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region secureConn ///////////////////////////////////////////////////////////////////////////////////////////////////
+
+// secureConn is a net.Conn that encrypts and authenticates all data using ChaCha20-Poly1305. The data is sent in
+// records consisting of the length of the sealed payload followed by the sealed payload itself. Every direction uses its
+// own key and a counter as nonce, so that reordered, replayed or modified records are detected.
+type secureConn struct {
+	net.Conn
+
+	sendAEAD  cipher.AEAD
+	sendNonce uint64
+	sendMutex sync.Mutex
+
+	recvAEAD   cipher.AEAD
+	recvNonce  uint64
+	recvBuffer []byte
+	recvMutex  sync.Mutex
+}
+
+// newSecureConn derives the session keys from the shared secret and the handshake transcript and wraps the given
+// connection. The initiator is the peer that sent the handshake request.
+func newSecureConn(conn net.Conn, secret []byte, reqData []byte, resData []byte, initiator bool) (*secureConn, error) {
+	transcript, err := blake2b.New256(nil)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = transcript.Write(reqData)
+	_, _ = transcript.Write(resData)
+
+	kdf := hkdf.New(newBlake2b256, secret, transcript.Sum(nil), []byte(keyDerivationInfo))
+	keys := make([]byte, 2*chacha20poly1305.KeySize)
+	if _, err = io.ReadFull(kdf, keys); err != nil {
+		return nil, err
+	}
+	initiatorKey, responderKey := keys[:chacha20poly1305.KeySize], keys[chacha20poly1305.KeySize:]
+	if !initiator {
+		initiatorKey, responderKey = responderKey, initiatorKey
+	}
+
+	sendAEAD, err := chacha20poly1305.New(initiatorKey)
+	if err != nil {
+		return nil, err
+	}
+	recvAEAD, err := chacha20poly1305.New(responderKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &secureConn{
+		Conn:     conn,
+		sendAEAD: sendAEAD,
+		recvAEAD: recvAEAD,
+	}, nil
+}
+
+// Write encrypts the given data and writes it to the underlying connection.
+func (c *secureConn) Write(b []byte) (n int, err error) {
+	c.sendMutex.Lock()
+	defer c.sendMutex.Unlock()
+
+	for n < len(b) {
+		size := len(b) - n
+		if size > maxRecordPayloadSize {
+			size = maxRecordPayloadSize
+		}
+
+		record := make([]byte, recordHeaderSize, recordHeaderSize+size+c.sendAEAD.Overhead())
+		binary.BigEndian.PutUint32(record, uint32(size+c.sendAEAD.Overhead()))
+		record = c.sendAEAD.Seal(record, nonce(c.sendNonce, c.sendAEAD.NonceSize()), b[n:n+size], record[:recordHeaderSize])
+		c.sendNonce++
+
+		if _, err = c.Conn.Write(record); err != nil {
+			return n, err
+		}
+		n += size
+	}
+
+	return n, nil
+}
+
+// Read reads and decrypts data from the underlying connection.
+func (c *secureConn) Read(b []byte) (int, error) {
+	c.recvMutex.Lock()
+	defer c.recvMutex.Unlock()
+
+	if len(c.recvBuffer) == 0 {
+		if err := c.readRecord(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(b, c.recvBuffer)
+	c.recvBuffer = c.recvBuffer[n:]
+
+	return n, nil
+}
+
+// readRecord reads the next record from the underlying connection and stores its plaintext in the receive buffer.
+func (c *secureConn) readRecord() error {
+	header := make([]byte, recordHeaderSize)
+	if _, err := io.ReadFull(c.Conn, header); err != nil {
+		return err
+	}
+	size := binary.BigEndian.Uint32(header)
+	if size < uint32(c.recvAEAD.Overhead()) || size > uint32(maxRecordPayloadSize+c.recvAEAD.Overhead()) {
+		return fmt.Errorf("%w: size %d", ErrInvalidRecord, size)
+	}
+
+	sealed := make([]byte, size)
+	if _, err := io.ReadFull(c.Conn, sealed); err != nil {
+		return err
+	}
+	plaintext, err := c.recvAEAD.Open(sealed[:0], nonce(c.recvNonce, c.recvAEAD.NonceSize()), sealed, header)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidRecord, err.Error())
+	}
+	c.recvNonce++
+	c.recvBuffer = plaintext
+
+	return nil
+}
+
+// nonce encodes the given record counter as an AEAD nonce.
+func nonce(counter uint64, size int) []byte {
+	n := make([]byte, size)
+	binary.BigEndian.PutUint64(n[size-8:], counter)
+
+	return n
+}
+
+func newBlake2b256() hash.Hash {
+	h, _ := blake2b.New256(nil)
+	return h
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSecureConns(t *testing.T) (*secureConn, *secureConn, net.Conn) {
+	connA, connB := net.Pipe()
+
+	keyA, err := newEphemeralKey()
+	require.NoError(t, err)
+	keyB, err := newEphemeralKey()
+	require.NoError(t, err)
+
+	secretA, err := keyA.sharedSecret(keyB.publicKey())
+	require.NoError(t, err)
+	secretB, err := keyB.sharedSecret(keyA.publicKey())
+	require.NoError(t, err)
+	require.Equal(t, secretA, secretB)
+
+	reqData, resData := []byte("request"), []byte("response")
+	secureA, err := newSecureConn(connA, secretA, reqData, resData, true)
+	require.NoError(t, err)
+	secureB, err := newSecureConn(connB, secretB, reqData, resData, false)
+	require.NoError(t, err)
+
+	return secureA, secureB, connA
+}
+
+func TestSecureConnRoundTrip(t *testing.T) {
+	secureA, secureB, _ := newTestSecureConns(t)
+	defer secureA.Close()
+	defer secureB.Close()
+
+	// the data spans multiple records
+	data := bytes.Repeat([]byte("gossip"), 2*maxRecordPayloadSize)
+
+	go func() {
+		n, err := secureA.Write(data)
+		assert.NoError(t, err)
+		assert.Equal(t, len(data), n)
+	}()
+
+	received := make([]byte, len(data))
+	_, err := io.ReadFull(secureB, received)
+	require.NoError(t, err)
+	assert.Equal(t, data, received)
+
+	go func() {
+		_, err := secureB.Write([]byte("pong"))
+		assert.NoError(t, err)
+	}()
+
+	received = make([]byte, 4)
+	_, err = io.ReadFull(secureA, received)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("pong"), received)
+}
+
+func TestSecureConnTampering(t *testing.T) {
+	secureA, secureB, rawA := newTestSecureConns(t)
+	defer secureA.Close()
+	defer secureB.Close()
+
+	go func() {
+		// write a record with a valid header, but a modified payload
+		record := make([]byte, recordHeaderSize, recordHeaderSize+4+secureA.sendAEAD.Overhead())
+		record[recordHeaderSize-1] = byte(4 + secureA.sendAEAD.Overhead())
+		record = secureA.sendAEAD.Seal(record, nonce(0, secureA.sendAEAD.NonceSize()), []byte("ping"), record[:recordHeaderSize])
+		record[len(record)-1] ^= 1
+		_, _ = rawA.Write(record)
+	}()
+
+	_, err := secureB.Read(make([]byte, 4))
+	assert.True(t, errors.Is(err, ErrInvalidRecord))
+}
+
+func TestSecureConnReplay(t *testing.T) {
+	secureA, secureB, rawA := newTestSecureConns(t)
+	defer secureA.Close()
+	defer secureB.Close()
+
+	// seal the same record twice with the same nonce
+	record := make([]byte, recordHeaderSize, recordHeaderSize+4+secureA.sendAEAD.Overhead())
+	record[recordHeaderSize-1] = byte(4 + secureA.sendAEAD.Overhead())
+	record = secureA.sendAEAD.Seal(record, nonce(0, secureA.sendAEAD.NonceSize()), []byte("ping"), record[:recordHeaderSize])
+
+	go func() {
+		_, _ = rawA.Write(record)
+		_, _ = rawA.Write(record)
+	}()
+
+	b := make([]byte, 4)
+	_, err := io.ReadFull(secureB, b)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("ping"), b)
+
+	_, err = secureB.Read(b)
+	assert.True(t, errors.Is(err, ErrInvalidRecord))
+}
+
+func TestParseEncryptionPolicy(t *testing.T) {
+	for _, policy := range []EncryptionPolicy{EncryptionDisabled, EncryptionPreferred, EncryptionRequired} {
+		parsed, err := ParseEncryptionPolicy(policy.String())
+		require.NoError(t, err)
+		assert.Equal(t, policy, parsed)
+	}
+
+	_, err := ParseEncryptionPolicy("unknown")
+	assert.Error(t, err)
+}
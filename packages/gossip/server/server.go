@@ -53,6 +53,11 @@ type TCP struct {
 	listener *net.TCPListener
 	log      *zap.SugaredLogger
 
+	// maxProtocolVersion is the highest gossip protocol version that is offered during the handshake.
+	maxProtocolVersion uint32
+	// encryption defines whether encrypted connections are offered, accepted or required.
+	encryption EncryptionPolicy
+
 	addAcceptMatcher chan *acceptMatcher
 	acceptReceived   chan accept
 
@@ -74,20 +79,84 @@ type acceptMatcher struct {
 }
 
 type accept struct {
-	fromID identity.ID // ID of the connecting peer
-	req    []byte      // raw data of the handshake request
-	conn   net.Conn    // the actual network connection
+	fromID          identity.ID // ID of the connecting peer
+	req             []byte      // raw data of the handshake request
+	conn            net.Conn    // the actual network connection
+	protocolVersion uint32      // negotiated gossip protocol version
+	ephemeralKey    []byte      // ephemeral key of the connecting peer, if it supports encryption
+}
+
+// Connection is an established gossip connection together with the gossip protocol version and the encryption that
+// were negotiated during the handshake.
+type Connection struct {
+	net.Conn
+
+	protocolVersion uint32
+	encrypted       bool
+}
+
+// ProtocolVersion returns the gossip protocol version that is used for the connection.
+func (c *Connection) ProtocolVersion() uint32 {
+	return c.protocolVersion
+}
+
+// IsEncrypted returns whether all data sent over the connection is encrypted and authenticated.
+func (c *Connection) IsEncrypted() bool {
+	return c.encrypted
+}
+
+// ProtocolVersion returns the gossip protocol version of the given connection. Connections that were not established
+// by the TCP server use the first version of the protocol.
+func ProtocolVersion(conn net.Conn) uint32 {
+	if c, ok := conn.(*Connection); ok {
+		return c.ProtocolVersion()
+	}
+
+	return ProtocolV1
+}
+
+// IsEncrypted returns whether the given connection is encrypted. Connections that were not established by the TCP
+// server are never encrypted.
+func IsEncrypted(conn net.Conn) bool {
+	if c, ok := conn.(*Connection); ok {
+		return c.IsEncrypted()
+	}
+
+	return false
+}
+
+// Option is a function setting an optional parameter of the TCP server.
+type Option func(t *TCP)
+
+// MaxProtocolVersion is an Option that limits the gossip protocol version that is offered to other peers.
+func MaxProtocolVersion(version uint32) Option {
+	return func(t *TCP) {
+		t.maxProtocolVersion = version
+	}
+}
+
+// Encryption is an Option that defines whether encrypted connections are offered, accepted or required.
+func Encryption(policy EncryptionPolicy) Option {
+	return func(t *TCP) {
+		t.encryption = policy
+	}
 }
 
 // ServeTCP creates the object and starts listening for incoming connections.
-func ServeTCP(local *peer.Local, listener *net.TCPListener, log *zap.SugaredLogger) *TCP {
+func ServeTCP(local *peer.Local, listener *net.TCPListener, log *zap.SugaredLogger, opts ...Option) *TCP {
 	t := &TCP{
-		local:            local,
-		listener:         listener,
-		log:              log,
-		addAcceptMatcher: make(chan *acceptMatcher),
-		acceptReceived:   make(chan accept),
-		closing:          make(chan struct{}),
+		local:              local,
+		listener:           listener,
+		log:                log,
+		maxProtocolVersion: LatestProtocolVersion,
+		encryption:         EncryptionPreferred,
+		addAcceptMatcher:   make(chan *acceptMatcher),
+		acceptReceived:     make(chan accept),
+		closing:            make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(t)
 	}
 
 	t.log.Debugw("server started",
@@ -126,17 +195,22 @@ func (t *TCP) DialPeer(p *peer.Peer) (net.Conn, error) {
 		return nil, ErrNoGossip
 	}
 
-	var conn net.Conn
+	var conn *Connection
 	if err := backoff.Retry(dialRetryPolicy, func() error {
-		var err error
 		address := net.JoinHostPort(p.IP().String(), strconv.Itoa(gossipEndpoint.Port()))
-		conn, err = net.DialTimeout("tcp", address, dialTimeout)
+		c, err := net.DialTimeout("tcp", address, dialTimeout)
 		if err != nil {
 			return fmt.Errorf("dial %s / %s failed: %w", address, p.ID(), err)
 		}
 
-		if err = t.doHandshake(p.PublicKey(), address, conn); err != nil {
-			return fmt.Errorf("handshake %s / %s failed: %w", address, p.ID(), err)
+		if conn, err = t.doHandshake(p.PublicKey(), address, c); err != nil {
+			t.closeConnection(c)
+			err = fmt.Errorf("handshake %s / %s failed: %w", address, p.ID(), err)
+			// retrying is pointless, if the peer does not support encryption
+			if errors.Is(err, ErrEncryptionRequired) {
+				return backoff.Permanent(err)
+			}
+			return err
 		}
 		return nil
 	}); err != nil {
@@ -146,6 +220,8 @@ func (t *TCP) DialPeer(p *peer.Peer) (net.Conn, error) {
 	t.log.Debugw("outgoing connection established",
 		"id", p.ID(),
 		"addr", conn.RemoteAddr(),
+		"protocol", conn.ProtocolVersion(),
+		"encrypted", conn.IsEncrypted(),
 	)
 	return conn, nil
 }
@@ -226,7 +302,7 @@ func (t *TCP) run() {
 					matched = true
 					matcherList.Remove(e)
 					// finish the handshake
-					go t.matchAccept(m, a.req, a.conn)
+					go t.matchAccept(m, a)
 				}
 			}
 			// close the connection if not matched
@@ -260,18 +336,58 @@ func (t *TCP) run() {
 	}
 }
 
-func (t *TCP) matchAccept(m *acceptMatcher, req []byte, conn net.Conn) {
+func (t *TCP) matchAccept(m *acceptMatcher, a accept) {
 	t.wg.Add(1)
 	defer t.wg.Done()
 
-	if err := t.writeHandshakeResponse(req, conn); err != nil {
+	// only answer with an ephemeral key, if the connecting peer offered encryption
+	var localKey *ephemeralKey
+	if len(a.ephemeralKey) > 0 && t.encryption != EncryptionDisabled {
+		var err error
+		if localKey, err = newEphemeralKey(); err != nil {
+			m.connected <- connect{nil, fmt.Errorf("incoming handshake failed: %w", err)}
+			t.closeConnection(a.conn)
+			return
+		}
+	}
+
+	resData, err := t.writeHandshakeResponse(a.req, a.protocolVersion, localKey.publicKey(), a.conn)
+	if err != nil {
+		m.connected <- connect{nil, fmt.Errorf("incoming handshake failed: %w", err)}
+		t.closeConnection(a.conn)
+		return
+	}
+	conn, err := t.newConnection(a.conn, a.protocolVersion, localKey, a.ephemeralKey, a.req, resData, false)
+	if err != nil {
 		m.connected <- connect{nil, fmt.Errorf("incoming handshake failed: %w", err)}
-		t.closeConnection(conn)
+		t.closeConnection(a.conn)
 		return
 	}
 	m.connected <- connect{conn, nil}
 }
 
+// newConnection creates the Connection that was negotiated during the handshake. If both peers exchanged an ephemeral
+// key, all data is encrypted with session keys derived from the shared secret and the handshake transcript.
+func (t *TCP) newConnection(conn net.Conn, protocolVersion uint32, localKey *ephemeralKey, remoteKey []byte, reqData []byte, resData []byte, initiator bool) (*Connection, error) {
+	if localKey == nil || len(remoteKey) == 0 {
+		if t.encryption == EncryptionRequired {
+			return nil, ErrEncryptionRequired
+		}
+		return &Connection{Conn: conn, protocolVersion: protocolVersion}, nil
+	}
+
+	secret, err := localKey.sharedSecret(remoteKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidHandshake, err.Error())
+	}
+	secure, err := newSecureConn(conn, secret, reqData, resData, initiator)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Connection{Conn: secure, protocolVersion: protocolVersion, encrypted: true}, nil
+}
+
 func (t *TCP) listenLoop() {
 	defer t.wg.Done()
 
@@ -290,7 +406,10 @@ func (t *TCP) listenLoop() {
 			return
 		}
 
-		key, req, err := t.readHandshakeRequest(conn)
+		key, req, protocolVersion, ephemeralKey, err := t.readHandshakeRequest(conn)
+		if err == nil && len(ephemeralKey) == 0 && t.encryption == EncryptionRequired {
+			err = ErrEncryptionRequired
+		}
 		if err != nil {
 			t.log.Warnw("failed handshake", "addr", conn.RemoteAddr(), "err", err)
 			t.closeConnection(conn)
@@ -299,9 +418,11 @@ func (t *TCP) listenLoop() {
 
 		select {
 		case t.acceptReceived <- accept{
-			fromID: identity.NewID(key),
-			req:    req,
-			conn:   conn,
+			fromID:          identity.NewID(key),
+			req:             req,
+			conn:            conn,
+			protocolVersion: protocolVersion,
+			ephemeralKey:    ephemeralKey,
 		}:
 		case <-t.closing:
 			t.closeConnection(conn)
@@ -310,10 +431,18 @@ func (t *TCP) listenLoop() {
 	}
 }
 
-func (t *TCP) doHandshake(key ed25519.PublicKey, remoteAddr string, conn net.Conn) error {
-	reqData, err := newHandshakeRequest(remoteAddr)
+func (t *TCP) doHandshake(key ed25519.PublicKey, remoteAddr string, conn net.Conn) (*Connection, error) {
+	var localKey *ephemeralKey
+	if t.encryption != EncryptionDisabled {
+		var err error
+		if localKey, err = newEphemeralKey(); err != nil {
+			return nil, err
+		}
+	}
+
+	reqData, err := newHandshakeRequest(remoteAddr, t.maxProtocolVersion, localKey.publicKey())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	pkt := &pb.Packet{
@@ -323,80 +452,82 @@ func (t *TCP) doHandshake(key ed25519.PublicKey, remoteAddr string, conn net.Con
 	}
 	b, err := proto.Marshal(pkt)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if l := len(b); l > maxHandshakePacketSize {
-		return fmt.Errorf("handshake size too large: %d, max %d", l, maxHandshakePacketSize)
+		return nil, fmt.Errorf("handshake size too large: %d, max %d", l, maxHandshakePacketSize)
 	}
 
 	err = conn.SetWriteDeadline(time.Now().Add(handshakeTimeout))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	_, err = conn.Write(b)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	err = conn.SetReadDeadline(time.Now().Add(handshakeTimeout))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	b = make([]byte, maxHandshakePacketSize)
 	n, err := conn.Read(b)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	pkt = &pb.Packet{}
 	err = proto.Unmarshal(b[:n], pkt)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	signer, err := peer.RecoverKeyFromSignedData(pkt)
 	if err != nil || !bytes.Equal(key.Bytes(), signer.Bytes()) {
-		return ErrInvalidHandshake
+		return nil, ErrInvalidHandshake
 	}
-	if !t.validateHandshakeResponse(pkt.GetData(), reqData) {
-		return ErrInvalidHandshake
+	protocolVersion, remoteKey, ok := t.validateHandshakeResponse(pkt.GetData(), reqData)
+	if !ok {
+		return nil, ErrInvalidHandshake
 	}
 
-	return nil
+	return t.newConnection(conn, protocolVersion, localKey, remoteKey, reqData, pkt.GetData(), true)
 }
 
-func (t *TCP) readHandshakeRequest(conn net.Conn) (ed25519.PublicKey, []byte, error) {
+func (t *TCP) readHandshakeRequest(conn net.Conn) (ed25519.PublicKey, []byte, uint32, []byte, error) {
 	if err := conn.SetReadDeadline(time.Now().Add(handshakeTimeout)); err != nil {
-		return ed25519.PublicKey{}, nil, err
+		return ed25519.PublicKey{}, nil, 0, nil, err
 	}
 	b := make([]byte, maxHandshakePacketSize)
 	n, err := conn.Read(b)
 	if err != nil {
-		return ed25519.PublicKey{}, nil, fmt.Errorf("%w: %s", ErrInvalidHandshake, err.Error())
+		return ed25519.PublicKey{}, nil, 0, nil, fmt.Errorf("%w: %s", ErrInvalidHandshake, err.Error())
 	}
 
 	pkt := &pb.Packet{}
 	err = proto.Unmarshal(b[:n], pkt)
 	if err != nil {
-		return ed25519.PublicKey{}, nil, err
+		return ed25519.PublicKey{}, nil, 0, nil, err
 	}
 
 	key, err := peer.RecoverKeyFromSignedData(pkt)
 	if err != nil {
-		return ed25519.PublicKey{}, nil, err
+		return ed25519.PublicKey{}, nil, 0, nil, err
 	}
 
-	if !t.validateHandshakeRequest(pkt.GetData()) {
-		return ed25519.PublicKey{}, nil, ErrInvalidHandshake
+	protocolVersion, ephemeralKey, ok := t.validateHandshakeRequest(pkt.GetData())
+	if !ok {
+		return ed25519.PublicKey{}, nil, 0, nil, ErrInvalidHandshake
 	}
 
-	return key, pkt.GetData(), nil
+	return key, pkt.GetData(), protocolVersion, ephemeralKey, nil
 }
 
-func (t *TCP) writeHandshakeResponse(reqData []byte, conn net.Conn) error {
-	data, err := newHandshakeResponse(reqData)
+func (t *TCP) writeHandshakeResponse(reqData []byte, protocolVersion uint32, ephemeralKey []byte, conn net.Conn) ([]byte, error) {
+	data, err := newHandshakeResponse(reqData, protocolVersion, ephemeralKey)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	pkt := &pb.Packet{
@@ -406,20 +537,20 @@ func (t *TCP) writeHandshakeResponse(reqData []byte, conn net.Conn) error {
 	}
 	b, err := proto.Marshal(pkt)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if l := len(b); l > maxHandshakePacketSize {
-		return fmt.Errorf("handshake size too large: %d, max %d", l, maxHandshakePacketSize)
+		return nil, fmt.Errorf("handshake size too large: %d, max %d", l, maxHandshakePacketSize)
 	}
 
 	err = conn.SetWriteDeadline(time.Now().Add(handshakeTimeout))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	_, err = conn.Write(b)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return data, nil
 }
@@ -1,6 +1,8 @@
 package server
 
 import (
+	"errors"
+	"io"
 	"net"
 	"sync"
 	"testing"
@@ -165,13 +167,130 @@ func TestWrongConnect(t *testing.T) {
 	wg.Wait()
 }
 
+func TestProtocolVersionNegotiation(t *testing.T) {
+	tests := []struct {
+		versionA uint32
+		versionB uint32
+		want     uint32
+	}{
+		{LatestProtocolVersion, LatestProtocolVersion, LatestProtocolVersion},
+		{ProtocolV1, ProtocolV2, ProtocolV1},
+		{ProtocolV2, ProtocolV1, ProtocolV1},
+		{ProtocolV2, 0, ProtocolV1},
+	}
+
+	for _, test := range tests {
+		transA, closeA := newTestServer(t, "A", MaxProtocolVersion(test.versionA))
+		transB, closeB := newTestServer(t, "B", MaxProtocolVersion(test.versionB))
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			c, err := transA.AcceptPeer(getPeer(transB))
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.want, ProtocolVersion(c))
+				_ = c.Close()
+			}
+		}()
+		time.Sleep(graceTime)
+		go func() {
+			defer wg.Done()
+			c, err := transB.DialPeer(getPeer(transA))
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.want, ProtocolVersion(c))
+				_ = c.Close()
+			}
+		}()
+
+		wg.Wait()
+		closeA()
+		closeB()
+	}
+}
+
+func TestEncryptionNegotiation(t *testing.T) {
+	tests := []struct {
+		policyA EncryptionPolicy
+		policyB EncryptionPolicy
+		want    bool
+	}{
+		{EncryptionPreferred, EncryptionPreferred, true},
+		{EncryptionRequired, EncryptionPreferred, true},
+		{EncryptionPreferred, EncryptionDisabled, false},
+		{EncryptionDisabled, EncryptionPreferred, false},
+	}
+
+	for _, test := range tests {
+		transA, closeA := newTestServer(t, "A", Encryption(test.policyA))
+		transB, closeB := newTestServer(t, "B", Encryption(test.policyB))
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			c, err := transA.AcceptPeer(getPeer(transB))
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.want, IsEncrypted(c))
+				b := make([]byte, 4)
+				_, err = io.ReadFull(c, b)
+				assert.NoError(t, err)
+				assert.Equal(t, []byte("ping"), b)
+				_ = c.Close()
+			}
+		}()
+		time.Sleep(graceTime)
+		go func() {
+			defer wg.Done()
+			c, err := transB.DialPeer(getPeer(transA))
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.want, IsEncrypted(c))
+				_, err = c.Write([]byte("ping"))
+				assert.NoError(t, err)
+				_ = c.Close()
+			}
+		}()
+
+		wg.Wait()
+		closeA()
+		closeB()
+	}
+}
+
+func TestEncryptionRequired(t *testing.T) {
+	transA, closeA := newTestServer(t, "A", Encryption(EncryptionDisabled))
+	defer closeA()
+	transB, closeB := newTestServer(t, "B", Encryption(EncryptionRequired))
+	defer closeB()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		c, err := transA.AcceptPeer(getPeer(transB))
+		if err == nil {
+			_ = c.Close()
+		}
+	}()
+	time.Sleep(graceTime)
+
+	// the dialing peer must reject the unencrypted connection
+	_, err := transB.DialPeer(getPeer(transA))
+	assert.True(t, errors.Is(err, ErrEncryptionRequired))
+
+	wg.Wait()
+}
+
 func newTestDB(t require.TestingT) *peer.DB {
 	db, err := peer.NewDB(mapdb.NewMapDB())
 	require.NoError(t, err)
 	return db
 }
 
-func newTestServer(t require.TestingT, name string) (*TCP, func()) {
+func newTestServer(t require.TestingT, name string, opts ...Option) (*TCP, func()) {
 	l := log.Named(name)
 
 	laddr, err := net.ResolveTCPAddr("tcp", "127.0.0.1:0")
@@ -186,7 +305,7 @@ func newTestServer(t require.TestingT, name string) (*TCP, func()) {
 	local, err := peer.NewLocal(lis.Addr().(*net.TCPAddr).IP, services, newTestDB(t))
 	require.NoError(t, err)
 
-	srv := ServeTCP(local, lis, l)
+	srv := ServeTCP(local, lis, l, opts...)
 
 	teardown := func() {
 		srv.Close()
@@ -0,0 +1,239 @@
+package gossip
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	pb "github.com/iotaledger/goshimmer/packages/gossip/proto"
+	"github.com/iotaledger/hive.go/identity"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// snapshotChunkSize defines the amount of snapshot bytes that are sent in a single packet.
+	snapshotChunkSize = 60 * 1024
+
+	// snapshotChunkTimeout defines the time to wait for a requested chunk before the request is repeated.
+	snapshotChunkTimeout = 5 * time.Second
+
+	// maxSnapshotChunkRequests defines how often a chunk is requested before the download is aborted.
+	maxSnapshotChunkRequests = 3
+)
+
+var (
+	snapshotRequestWorkerCount     = 1
+	snapshotRequestWorkerQueueSize = 100
+)
+
+// LoadSnapshotChunkFunc defines a function that returns up to length bytes of the latest snapshot starting at the given
+// offset together with the total size of the snapshot.
+type LoadSnapshotChunkFunc func(offset int64, length int) (data []byte, size int64, err error)
+
+// RequestSnapshot downloads the latest snapshot of the neighbor with the given ID chunk by chunk and writes it to the
+// given writer. Every chunk is verified against its hash, while the integrity of the whole snapshot has to be verified
+// by the consumer (i.e. using the checksum of the snapshot format). Only a single download can be active at a time.
+func (m *Manager) RequestSnapshot(writer io.Writer, from identity.ID) (err error) {
+	if len(m.getNeighborsByID([]identity.ID{from})) == 0 {
+		return ErrUnknownNeighbor
+	}
+
+	download, err := m.startSnapshotDownload(from)
+	if err != nil {
+		return err
+	}
+	defer m.stopSnapshotDownload()
+
+	for index, total := uint32(0), uint32(1); index < total; index++ {
+		chunk, requestErr := m.requestSnapshotChunk(download, index)
+		if requestErr != nil {
+			return requestErr
+		}
+
+		switch {
+		case index == 0:
+			total = chunk.GetTotal()
+		case chunk.GetTotal() != total:
+			return xerrors.Errorf("snapshot changed during the download (%d instead of %d chunks): %w", chunk.GetTotal(), total, ErrInvalidSnapshotChunk)
+		}
+
+		if _, err = writer.Write(chunk.GetData()); err != nil {
+			return xerrors.Errorf("failed to write snapshot chunk %d: %w", index, err)
+		}
+	}
+
+	return nil
+}
+
+// RequestSnapshotTail returns the last length bytes of the latest snapshot of the neighbor with the given ID, without
+// downloading the whole snapshot. As snapshots end with their checksum, this allows to compare the snapshots of
+// different neighbors before one of them is downloaded.
+func (m *Manager) RequestSnapshotTail(from identity.ID, length int) (tail []byte, err error) {
+	if length > snapshotChunkSize {
+		return nil, xerrors.Errorf("tail of %d bytes exceeds the chunk size of %d bytes", length, snapshotChunkSize)
+	}
+	if len(m.getNeighborsByID([]identity.ID{from})) == 0 {
+		return nil, ErrUnknownNeighbor
+	}
+
+	download, err := m.startSnapshotDownload(from)
+	if err != nil {
+		return nil, err
+	}
+	defer m.stopSnapshotDownload()
+
+	// the first chunk tells us the amount of chunks
+	chunk, err := m.requestSnapshotChunk(download, 0)
+	if err != nil {
+		return nil, err
+	}
+	total := chunk.GetTotal()
+
+	// the tail is contained in the last two chunks at most
+	for index := total - 1; ; index-- {
+		if index != chunk.GetIndex() {
+			if chunk, err = m.requestSnapshotChunk(download, index); err != nil {
+				return nil, err
+			}
+			if chunk.GetTotal() != total {
+				return nil, xerrors.Errorf("snapshot changed during the download (%d instead of %d chunks): %w", chunk.GetTotal(), total, ErrInvalidSnapshotChunk)
+			}
+		}
+
+		tail = append(append([]byte{}, chunk.GetData()...), tail...)
+		if len(tail) >= length {
+			return tail[len(tail)-length:], nil
+		}
+		if index == 0 {
+			return nil, xerrors.Errorf("snapshot is smaller than %d bytes: %w", length, ErrInvalidSnapshotChunk)
+		}
+	}
+}
+
+// SnapshotRequestWorkerPoolStatus returns the name and the load of the workerpool.
+func (m *Manager) SnapshotRequestWorkerPoolStatus() (name string, load int) {
+	return "snapshotRequestWorkerPool", m.snapshotRequestWorkerPool.GetPendingQueueSize()
+}
+
+func (m *Manager) startSnapshotDownload(from identity.ID) (download *snapshotDownload, err error) {
+	m.snapshotDownloadMutex.Lock()
+	defer m.snapshotDownloadMutex.Unlock()
+
+	if m.snapshotDownload != nil {
+		return nil, ErrSnapshotDownloadInProgress
+	}
+	m.snapshotDownload = &snapshotDownload{
+		peerID: from,
+		chunks: make(chan *pb.SnapshotChunk, maxSnapshotChunkRequests),
+	}
+
+	return m.snapshotDownload, nil
+}
+
+func (m *Manager) stopSnapshotDownload() {
+	m.snapshotDownloadMutex.Lock()
+	defer m.snapshotDownloadMutex.Unlock()
+
+	m.snapshotDownload = nil
+}
+
+func (m *Manager) requestSnapshotChunk(download *snapshotDownload, index uint32) (chunk *pb.SnapshotChunk, err error) {
+	for i := 0; i < maxSnapshotChunkRequests; i++ {
+		m.send(marshal(&pb.SnapshotRequest{Index: index}), download.peerID)
+
+		if chunk = download.waitForChunk(index, snapshotChunkTimeout); chunk == nil {
+			continue
+		}
+
+		if chunk.GetTotal() == 0 {
+			return nil, ErrSnapshotUnavailable
+		}
+
+		if hash := blake2b.Sum256(chunk.GetData()); !bytes.Equal(hash[:], chunk.GetHash()) {
+			return nil, xerrors.Errorf("hash of snapshot chunk %d does not match: %w", index, ErrInvalidSnapshotChunk)
+		}
+
+		return chunk, nil
+	}
+
+	return nil, xerrors.Errorf("failed to receive snapshot chunk %d: %w", index, ErrSnapshotRequestTimeout)
+}
+
+func (m *Manager) processSnapshotRequest(data []byte, nbr *Neighbor) {
+	packet := new(pb.SnapshotRequest)
+	if err := proto.Unmarshal(data[1:], packet); err != nil {
+		m.log.Debugw("invalid packet", "err", err)
+		return
+	}
+	if !nbr.allowRequests(1) {
+		m.ReportMisbehavior(nbr.ID(), MisbehaviorRequestFlood)
+		return
+	}
+
+	// an empty chunk (with a total of 0 chunks) signals that no snapshot is available
+	chunk := &pb.SnapshotChunk{Index: packet.GetIndex()}
+	if m.loadSnapshotChunkFunc != nil {
+		chunkData, size, err := m.loadSnapshotChunkFunc(int64(packet.GetIndex())*snapshotChunkSize, snapshotChunkSize)
+		if err != nil {
+			m.log.Debugw("error loading snapshot chunk", "index", packet.GetIndex(), "err", err)
+		} else {
+			hash := blake2b.Sum256(chunkData)
+
+			chunk.Total = uint32((size + snapshotChunkSize - 1) / snapshotChunkSize)
+			chunk.Data = chunkData
+			chunk.Hash = hash[:]
+		}
+	}
+
+	// send the loaded chunk directly to the neighbor
+	_, _ = nbr.Write(marshal(chunk))
+}
+
+func (m *Manager) processSnapshotChunk(data []byte, nbr *Neighbor) error {
+	packet := new(pb.SnapshotChunk)
+	if err := proto.Unmarshal(data[1:], packet); err != nil {
+		return xerrors.Errorf("invalid packet: %w", err)
+	}
+
+	m.snapshotDownloadMutex.RLock()
+	defer m.snapshotDownloadMutex.RUnlock()
+
+	// ignore chunks that were not requested
+	if m.snapshotDownload == nil || m.snapshotDownload.peerID != nbr.ID() {
+		m.ReportMisbehavior(nbr.ID(), MisbehaviorUnsolicitedResponse)
+		return nil
+	}
+
+	select {
+	case m.snapshotDownload.chunks <- packet:
+	default:
+	}
+
+	return nil
+}
+
+// snapshotDownload contains the state of an ongoing snapshot download.
+type snapshotDownload struct {
+	peerID identity.ID
+	chunks chan *pb.SnapshotChunk
+}
+
+// waitForChunk waits for the chunk with the given index and discards all other (outdated) chunks. It returns nil if
+// the chunk was not received within the given timeout.
+func (s *snapshotDownload) waitForChunk(index uint32, timeout time.Duration) *pb.SnapshotChunk {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case chunk := <-s.chunks:
+			if chunk.GetIndex() == index {
+				return chunk
+			}
+		case <-timer.C:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,448 @@
+package ledgerstate
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/iotaledger/hive.go/byteutils"
+	"github.com/iotaledger/hive.go/cerrors"
+	"github.com/iotaledger/hive.go/marshalutil"
+	"github.com/iotaledger/hive.go/objectstorage"
+	"github.com/iotaledger/hive.go/stringify"
+	"github.com/mr-tron/base58"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/xerrors"
+)
+
+// region AddressHistoryCursor /////////////////////////////////////////////////////////////////////////////////////////
+
+// AddressHistoryCursorLength contains the amount of bytes that a marshaled version of the AddressHistoryCursor contains.
+const AddressHistoryCursorLength = marshalutil.Int64Size + TransactionIDLength
+
+// AddressHistoryCursor represents the position of an AddressHistoryEntry in the history of its Address. It consists of
+// the timestamp (encoded in big endian, so that the byte order matches the chronological order) and the TransactionID
+// of the entry and forms the part of the storage key that follows the Address.
+type AddressHistoryCursor [AddressHistoryCursorLength]byte
+
+// NewAddressHistoryCursor creates a new AddressHistoryCursor from the given timestamp and TransactionID.
+func NewAddressHistoryCursor(timestamp time.Time, transactionID TransactionID) (cursor AddressHistoryCursor) {
+	binary.BigEndian.PutUint64(cursor[:marshalutil.Int64Size], uint64(timestamp.UnixNano()))
+	copy(cursor[marshalutil.Int64Size:], transactionID.Bytes())
+
+	return
+}
+
+// AddressHistoryCursorFromBytes unmarshals an AddressHistoryCursor from a sequence of bytes.
+func AddressHistoryCursorFromBytes(bytes []byte) (cursor AddressHistoryCursor, consumedBytes int, err error) {
+	marshalUtil := marshalutil.New(bytes)
+	if cursor, err = AddressHistoryCursorFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse AddressHistoryCursor from MarshalUtil: %w", err)
+		return
+	}
+	consumedBytes = marshalUtil.ReadOffset()
+
+	return
+}
+
+// AddressHistoryCursorFromBase58 creates an AddressHistoryCursor from a base58 encoded string.
+func AddressHistoryCursorFromBase58(base58String string) (cursor AddressHistoryCursor, err error) {
+	bytes, err := base58.Decode(base58String)
+	if err != nil {
+		err = xerrors.Errorf("error while decoding base58 encoded AddressHistoryCursor (%v): %w", err, cerrors.ErrBase58DecodeFailed)
+		return
+	}
+
+	if cursor, _, err = AddressHistoryCursorFromBytes(bytes); err != nil {
+		err = xerrors.Errorf("failed to parse AddressHistoryCursor from bytes: %w", err)
+		return
+	}
+
+	return
+}
+
+// AddressHistoryCursorFromMarshalUtil unmarshals an AddressHistoryCursor using a MarshalUtil (for easier unmarshaling).
+func AddressHistoryCursorFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (cursor AddressHistoryCursor, err error) {
+	cursorBytes, err := marshalUtil.ReadBytes(AddressHistoryCursorLength)
+	if err != nil {
+		err = xerrors.Errorf("failed to parse AddressHistoryCursor (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	copy(cursor[:], cursorBytes)
+
+	return
+}
+
+// Timestamp returns the timestamp of the AddressHistoryEntry that the AddressHistoryCursor points to.
+func (a AddressHistoryCursor) Timestamp() time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(a[:marshalutil.Int64Size])))
+}
+
+// TransactionID returns the TransactionID of the AddressHistoryEntry that the AddressHistoryCursor points to.
+func (a AddressHistoryCursor) TransactionID() (transactionID TransactionID) {
+	copy(transactionID[:], a[marshalutil.Int64Size:])
+
+	return
+}
+
+// Before returns true if the AddressHistoryCursor is ordered before the given one in the history of an Address.
+func (a AddressHistoryCursor) Before(other AddressHistoryCursor) bool {
+	return bytes.Compare(a[:], other[:]) < 0
+}
+
+// Bytes returns a marshaled version of the AddressHistoryCursor.
+func (a AddressHistoryCursor) Bytes() []byte {
+	return a[:]
+}
+
+// Base58 returns a base58 encoded version of the AddressHistoryCursor.
+func (a AddressHistoryCursor) Base58() string {
+	return base58.Encode(a[:])
+}
+
+// String returns a human readable version of the AddressHistoryCursor.
+func (a AddressHistoryCursor) String() string {
+	return "AddressHistoryCursor(" + a.Base58() + ")"
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region AddressHistoryEntry //////////////////////////////////////////////////////////////////////////////////////////
+
+// AddressHistoryEntryPartitionKeys defines the "layout" of the key. This enables prefix iterations in the objectstorage.
+var AddressHistoryEntryPartitionKeys = objectstorage.PartitionKey([]int{AddressLength, AddressHistoryCursorLength}...)
+
+// AddressHistoryEntry represents a Transaction that credited or debited an Address. It contains the tokens that the
+// Transaction sent to the Address and the tokens that it spent from the Address, so the history of an Address can be
+// retrieved without loading the Transactions and their consumed Outputs.
+type AddressHistoryEntry struct {
+	address       Address
+	transactionID TransactionID
+	timestamp     time.Time
+	received      *ColoredBalances
+	spent         *ColoredBalances
+
+	objectstorage.StorableObjectFlags
+}
+
+// NewAddressHistoryEntry creates a new AddressHistoryEntry from the given details.
+func NewAddressHistoryEntry(address Address, transactionID TransactionID, timestamp time.Time, received *ColoredBalances, spent *ColoredBalances) *AddressHistoryEntry {
+	return &AddressHistoryEntry{
+		address:       address,
+		transactionID: transactionID,
+		timestamp:     timestamp,
+		received:      received,
+		spent:         spent,
+	}
+}
+
+// AddressHistoryEntryFromBytes unmarshals an AddressHistoryEntry from a sequence of bytes.
+func AddressHistoryEntryFromBytes(bytes []byte) (addressHistoryEntry *AddressHistoryEntry, consumedBytes int, err error) {
+	marshalUtil := marshalutil.New(bytes)
+	if addressHistoryEntry, err = AddressHistoryEntryFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse AddressHistoryEntry from MarshalUtil: %w", err)
+		return
+	}
+	consumedBytes = marshalUtil.ReadOffset()
+
+	return
+}
+
+// AddressHistoryEntryFromMarshalUtil unmarshals an AddressHistoryEntry using a MarshalUtil (for easier unmarshaling).
+func AddressHistoryEntryFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (addressHistoryEntry *AddressHistoryEntry, err error) {
+	addressHistoryEntry = &AddressHistoryEntry{}
+	if addressHistoryEntry.address, err = AddressFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse Address from MarshalUtil: %w", err)
+		return
+	}
+	cursor, err := AddressHistoryCursorFromMarshalUtil(marshalUtil)
+	if err != nil {
+		err = xerrors.Errorf("failed to parse AddressHistoryCursor from MarshalUtil: %w", err)
+		return
+	}
+	addressHistoryEntry.timestamp = cursor.Timestamp()
+	addressHistoryEntry.transactionID = cursor.TransactionID()
+	if addressHistoryEntry.received, err = ColoredBalancesFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse received ColoredBalances from MarshalUtil: %w", err)
+		return
+	}
+	if addressHistoryEntry.spent, err = ColoredBalancesFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse spent ColoredBalances from MarshalUtil: %w", err)
+		return
+	}
+
+	return
+}
+
+// AddressHistoryEntryFromObjectStorage is a factory method that creates a new AddressHistoryEntry instance from a
+// storage key of the object storage. It is used by the object storage, to create new instances of this entity.
+func AddressHistoryEntryFromObjectStorage(key []byte, data []byte) (result objectstorage.StorableObject, err error) {
+	if result, _, err = AddressHistoryEntryFromBytes(byteutils.ConcatBytes(key, data)); err != nil {
+		err = xerrors.Errorf("failed to parse AddressHistoryEntry from bytes: %w", err)
+		return
+	}
+
+	return
+}
+
+// Address returns the Address whose history the AddressHistoryEntry belongs to.
+func (a *AddressHistoryEntry) Address() Address {
+	return a.address
+}
+
+// TransactionID returns the identifier of the Transaction that credited or debited the Address.
+func (a *AddressHistoryEntry) TransactionID() TransactionID {
+	return a.transactionID
+}
+
+// Timestamp returns the timestamp of the Transaction.
+func (a *AddressHistoryEntry) Timestamp() time.Time {
+	return a.timestamp
+}
+
+// Cursor returns the position of the AddressHistoryEntry in the history of its Address.
+func (a *AddressHistoryEntry) Cursor() AddressHistoryCursor {
+	return NewAddressHistoryCursor(a.timestamp, a.transactionID)
+}
+
+// Received returns the tokens that the Transaction sent to the Address.
+func (a *AddressHistoryEntry) Received() *ColoredBalances {
+	return a.received
+}
+
+// Spent returns the tokens that the Transaction spent from the Address.
+func (a *AddressHistoryEntry) Spent() *ColoredBalances {
+	return a.spent
+}
+
+// ContainsColor returns true if the Transaction moved tokens of the given Color from or to the Address.
+func (a *AddressHistoryEntry) ContainsColor(color Color) (containsColor bool) {
+	if _, containsColor = a.received.Get(color); containsColor {
+		return
+	}
+	_, containsColor = a.spent.Get(color)
+
+	return
+}
+
+// Before returns true if the AddressHistoryEntry is ordered before the given one in the history of the Address (by
+// their timestamp and by their TransactionID if the timestamps are equal).
+func (a *AddressHistoryEntry) Before(other *AddressHistoryEntry) bool {
+	return a.Cursor().Before(other.Cursor())
+}
+
+// Bytes returns a marshaled version of the AddressHistoryEntry.
+func (a *AddressHistoryEntry) Bytes() []byte {
+	return byteutils.ConcatBytes(a.ObjectStorageKey(), a.ObjectStorageValue())
+}
+
+// String returns a human readable version of the AddressHistoryEntry.
+func (a *AddressHistoryEntry) String() string {
+	return stringify.Struct("AddressHistoryEntry",
+		stringify.StructField("address", a.address),
+		stringify.StructField("transactionID", a.transactionID),
+		stringify.StructField("timestamp", a.timestamp),
+		stringify.StructField("received", a.received),
+		stringify.StructField("spent", a.spent),
+	)
+}
+
+// Update is disabled and panics if it ever gets called - it is required to match the StorableObject interface.
+func (a *AddressHistoryEntry) Update(other objectstorage.StorableObject) {
+	panic("updates disabled")
+}
+
+// ObjectStorageKey returns the key that is used to store the object in the database. It is required to match the
+// StorableObject interface. The entries of an Address are sorted by their timestamp in the database, so that the
+// history can be iterated starting at an AddressHistoryCursor.
+func (a *AddressHistoryEntry) ObjectStorageKey() []byte {
+	return byteutils.ConcatBytes(a.address.Bytes(), a.Cursor().Bytes())
+}
+
+// ObjectStorageValue marshals the AddressHistoryEntry into a sequence of bytes that are used as the value part in the
+// object storage.
+func (a *AddressHistoryEntry) ObjectStorageValue() []byte {
+	return marshalutil.New().
+		WriteBytes(a.received.Bytes()).
+		WriteBytes(a.spent.Bytes()).
+		Bytes()
+}
+
+// code contract (make sure the struct implements all required methods)
+var _ objectstorage.StorableObject = &AddressHistoryEntry{}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region AddressHistoryEntries ////////////////////////////////////////////////////////////////////////////////////////
+
+// AddressHistoryEntries represents a collection of AddressHistoryEntry objects.
+type AddressHistoryEntries []*AddressHistoryEntry
+
+// Sort sorts the AddressHistoryEntries by their timestamp (and their TransactionID if the timestamps are equal).
+func (a AddressHistoryEntries) Sort() AddressHistoryEntries {
+	sort.Slice(a, func(i, j int) bool {
+		return a[i].Before(a[j])
+	})
+
+	return a
+}
+
+// Filter returns the AddressHistoryEntries for which the given filter returns true.
+func (a AddressHistoryEntries) Filter(filter func(addressHistoryEntry *AddressHistoryEntry) bool) (filteredEntries AddressHistoryEntries) {
+	filteredEntries = make(AddressHistoryEntries, 0, len(a))
+	for _, addressHistoryEntry := range a {
+		if filter(addressHistoryEntry) {
+			filteredEntries = append(filteredEntries, addressHistoryEntry)
+		}
+	}
+
+	return
+}
+
+// String returns a human readable version of the AddressHistoryEntries.
+func (a AddressHistoryEntries) String() string {
+	structBuilder := stringify.StructBuilder("AddressHistoryEntries")
+	for i, addressHistoryEntry := range a {
+		structBuilder.AddField(stringify.StructField(strconv.Itoa(i), addressHistoryEntry))
+	}
+
+	return structBuilder.String()
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region CachedAddressHistoryEntry ////////////////////////////////////////////////////////////////////////////////////
+
+// CachedAddressHistoryEntry is a wrapper for the generic CachedObject returned by the object storage that overrides
+// the accessor methods with a type-casted one.
+type CachedAddressHistoryEntry struct {
+	objectstorage.CachedObject
+}
+
+// Retain marks the CachedObject to still be in use by the program.
+func (c *CachedAddressHistoryEntry) Retain() *CachedAddressHistoryEntry {
+	return &CachedAddressHistoryEntry{c.CachedObject.Retain()}
+}
+
+// Unwrap is the type-casted equivalent of Get. It returns nil if the object does not exist.
+func (c *CachedAddressHistoryEntry) Unwrap() *AddressHistoryEntry {
+	untypedObject := c.Get()
+	if untypedObject == nil {
+		return nil
+	}
+
+	typedObject := untypedObject.(*AddressHistoryEntry)
+	if typedObject == nil || typedObject.IsDeleted() {
+		return nil
+	}
+
+	return typedObject
+}
+
+// Consume unwraps the CachedObject and passes a type-casted version to the consumer (if the object is not empty - it
+// exists). It automatically releases the object when the consumer finishes.
+func (c *CachedAddressHistoryEntry) Consume(consumer func(addressHistoryEntry *AddressHistoryEntry), forceRelease ...bool) (consumed bool) {
+	return c.CachedObject.Consume(func(object objectstorage.StorableObject) {
+		consumer(object.(*AddressHistoryEntry))
+	}, forceRelease...)
+}
+
+// String returns a human readable version of the CachedAddressHistoryEntry.
+func (c *CachedAddressHistoryEntry) String() string {
+	return stringify.Struct("CachedAddressHistoryEntry",
+		stringify.StructField("CachedObject", c.Unwrap()),
+	)
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region CachedAddressHistoryEntries //////////////////////////////////////////////////////////////////////////////////
+
+// CachedAddressHistoryEntries represents a collection of CachedAddressHistoryEntry objects.
+type CachedAddressHistoryEntries []*CachedAddressHistoryEntry
+
+// Unwrap is the type-casted equivalent of Get. It returns a slice of unwrapped objects with the object being nil if it
+// does not exist.
+func (c CachedAddressHistoryEntries) Unwrap() (unwrappedEntries AddressHistoryEntries) {
+	unwrappedEntries = make(AddressHistoryEntries, len(c))
+	for i, cachedAddressHistoryEntry := range c {
+		unwrappedEntries[i] = cachedAddressHistoryEntry.Unwrap()
+	}
+
+	return
+}
+
+// Consume iterates over the CachedObjects, unwraps them and passes a type-casted version to the consumer (if the object
+// is not empty - it exists). It automatically releases the object when the consumer finishes. It returns true, if at
+// least one object was consumed.
+func (c CachedAddressHistoryEntries) Consume(consumer func(addressHistoryEntry *AddressHistoryEntry), forceRelease ...bool) (consumed bool) {
+	for _, cachedAddressHistoryEntry := range c {
+		consumed = cachedAddressHistoryEntry.Consume(consumer, forceRelease...) || consumed
+	}
+
+	return
+}
+
+// Release is a utility function that allows us to release all CachedObjects in the collection.
+func (c CachedAddressHistoryEntries) Release(force ...bool) {
+	for _, cachedAddressHistoryEntry := range c {
+		cachedAddressHistoryEntry.Release(force...)
+	}
+}
+
+// String returns a human readable version of the CachedAddressHistoryEntries.
+func (c CachedAddressHistoryEntries) String() string {
+	structBuilder := stringify.StructBuilder("CachedAddressHistoryEntries")
+	for i, cachedAddressHistoryEntry := range c {
+		structBuilder.AddField(stringify.StructField(strconv.Itoa(i), cachedAddressHistoryEntry))
+	}
+
+	return structBuilder.String()
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region utility functions ////////////////////////////////////////////////////////////////////////////////////////////
+
+// addressHistoryEntries computes the AddressHistoryEntries of all Addresses that are credited or debited by the given
+// Transaction.
+func addressHistoryEntries(transaction *Transaction, consumedOutputs Outputs) (addressHistoryEntries AddressHistoryEntries) {
+	received := make(map[string]map[Color]uint64)
+	spent := make(map[string]map[Color]uint64)
+	addresses := make(map[string]Address)
+
+	addBalances := func(target map[string]map[Color]uint64, address Address, balances *ColoredBalances, output Output) {
+		addressKey := string(address.Bytes())
+		addresses[addressKey] = address
+		if _, exists := target[addressKey]; !exists {
+			target[addressKey] = make(map[Color]uint64)
+		}
+
+		balances.ForEach(func(color Color, balance uint64) bool {
+			if color == ColorMint {
+				color = blake2b.Sum256(output.ID().Bytes())
+			}
+			target[addressKey][color] += balance
+
+			return true
+		})
+	}
+
+	for _, consumedOutput := range consumedOutputs {
+		addBalances(spent, consumedOutput.Address(), consumedOutput.Balances(), consumedOutput)
+	}
+	for _, output := range transaction.Essence().Outputs() {
+		addBalances(received, output.Address(), output.Balances(), output)
+	}
+
+	addressHistoryEntries = make(AddressHistoryEntries, 0, len(addresses))
+	for addressKey, address := range addresses {
+		addressHistoryEntries = append(addressHistoryEntries, NewAddressHistoryEntry(address, transaction.ID(), transaction.Essence().Timestamp(), NewColoredBalances(received[addressKey]), NewColoredBalances(spent[addressKey])))
+	}
+
+	return
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
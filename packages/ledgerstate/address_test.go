@@ -36,6 +36,57 @@ func TestED25519Address(t *testing.T) {
 	assert.Equal(t, address.Digest(), addressFromBase58.Digest())
 }
 
+func TestAliasAddress(t *testing.T) {
+	address := NewAliasAddress(NewOutputID(GenesisTransactionID, 1).Bytes())
+	assert.False(t, address.IsEmpty())
+
+	// alias address from bytes using AddressFromBytes
+	address1, _, err := AddressFromBytes(address.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, AliasAddressType, address1.Type())
+	assert.True(t, address.Equals(address1.(*AliasAddress)))
+
+	// alias address from base58 string
+	addressFromBase58, err := AliasAddressFromBase58EncodedString(address.Base58())
+	require.NoError(t, err)
+	assert.Equal(t, address.Digest(), addressFromBase58.Digest())
+}
+
+func TestThresholdAddress(t *testing.T) {
+	keyPairs := []ed25519.KeyPair{ed25519.GenerateKeyPair(), ed25519.GenerateKeyPair(), ed25519.GenerateKeyPair()}
+	address, err := NewThresholdAddress(2, keyPairs[0].PublicKey, keyPairs[1].PublicKey, keyPairs[2].PublicKey)
+	require.NoError(t, err)
+
+	// the order of the public keys does not influence the address
+	permutedAddress, err := NewThresholdAddress(2, keyPairs[2].PublicKey, keyPairs[0].PublicKey, keyPairs[1].PublicKey)
+	require.NoError(t, err)
+	assert.Equal(t, address.Digest(), permutedAddress.Digest())
+
+	// the threshold is part of the address
+	otherThresholdAddress, err := NewThresholdAddress(3, keyPairs[0].PublicKey, keyPairs[1].PublicKey, keyPairs[2].PublicKey)
+	require.NoError(t, err)
+	assert.NotEqual(t, address.Digest(), otherThresholdAddress.Digest())
+
+	// threshold address from bytes using AddressFromBytes
+	address1, _, err := AddressFromBytes(address.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, ThresholdAddressType, address1.Type())
+	assert.Equal(t, address.Digest(), address1.Digest())
+
+	// threshold address from base58 string
+	addressFromBase58, err := ThresholdAddressFromBase58EncodedString(address.Base58())
+	require.NoError(t, err)
+	assert.Equal(t, address.Digest(), addressFromBase58.Digest())
+
+	// invalid parameters
+	_, err = NewThresholdAddress(0, keyPairs[0].PublicKey)
+	assert.Error(t, err)
+	_, err = NewThresholdAddress(2, keyPairs[0].PublicKey)
+	assert.Error(t, err)
+	_, err = NewThresholdAddress(1, keyPairs[0].PublicKey, keyPairs[0].PublicKey)
+	assert.Error(t, err)
+}
+
 func TestBLSAddress(t *testing.T) {
 	// generate BLS public key
 	suite := bn256.NewSuite()
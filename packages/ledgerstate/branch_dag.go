@@ -287,6 +287,54 @@ func (b *BranchDAG) ConflictMembers(conflictID ConflictID) (cachedConflictMember
 	return
 }
 
+// ForEachBranch iterates over all the Branches in the BranchDAG.
+func (b *BranchDAG) ForEachBranch(consumer func(branch Branch)) {
+	b.branchStorage.ForEach(func(key []byte, cachedObject objectstorage.CachedObject) bool {
+		(&CachedBranch{CachedObject: cachedObject}).Consume(consumer)
+
+		return true
+	})
+}
+
+// PruneBranch removes a rejected and finalized ConflictBranch (that has no ChildBranches anymore) together with its
+// ConflictMember and ChildBranch references from the BranchDAG.
+func (b *BranchDAG) PruneBranch(branchID BranchID) (err error) {
+	cachedBranch := b.Branch(branchID)
+	defer cachedBranch.Release()
+
+	conflictBranch, err := cachedBranch.UnwrapConflictBranch()
+	if err != nil {
+		err = xerrors.Errorf("failed to load ConflictBranch with %s: %w", branchID, err)
+		return
+	}
+	if conflictBranch == nil {
+		err = xerrors.Errorf("failed to load ConflictBranch with %s: %w", branchID, cerrors.ErrFatal)
+		return
+	}
+	if !conflictBranch.Finalized() || conflictBranch.InclusionState() != Rejected {
+		err = xerrors.Errorf("failed to prune ConflictBranch with %s that is not finalized and rejected: %w", branchID, ErrInvalidStateTransition)
+		return
+	}
+
+	childBranches := b.ChildBranches(branchID)
+	hasChildBranches := len(childBranches) != 0
+	childBranches.Release()
+	if hasChildBranches {
+		err = xerrors.Errorf("failed to prune ConflictBranch with %s that still has ChildBranches: %w", branchID, ErrInvalidStateTransition)
+		return
+	}
+
+	for conflictID := range conflictBranch.Conflicts() {
+		b.unregisterConflictMember(conflictID, branchID)
+	}
+	for parentBranchID := range conflictBranch.Parents() {
+		b.childBranchStorage.Delete(NewChildBranch(parentBranchID, branchID, ConflictBranchType).ObjectStorageKey())
+	}
+	cachedBranch.Unwrap().Delete()
+
+	return
+}
+
 // BranchIDsContainRejectedBranch is an utility function that checks if the given BranchIDs contain a Rejected
 // Branch. It returns the BranchID of the first Rejected Branch that it finds.
 func (b *BranchDAG) BranchIDsContainRejectedBranch(branchIDs BranchIDs) (rejected bool, rejectedBranchID BranchID) {
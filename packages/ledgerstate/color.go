@@ -3,10 +3,13 @@ package ledgerstate
 import (
 	"bytes"
 	"sort"
+	"sync"
 
+	"github.com/iotaledger/hive.go/byteutils"
 	"github.com/iotaledger/hive.go/cerrors"
 	"github.com/iotaledger/hive.go/datastructure/orderedmap"
 	"github.com/iotaledger/hive.go/marshalutil"
+	"github.com/iotaledger/hive.go/objectstorage"
 	"github.com/iotaledger/hive.go/stringify"
 	"github.com/mr-tron/base58"
 	"golang.org/x/xerrors"
@@ -175,6 +178,9 @@ func ColoredBalancesFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (color
 // Get returns the balance of the given Color and a boolean value indicating if the requested Color existed.
 func (c *ColoredBalances) Get(color Color) (uint64, bool) {
 	balance, exists := c.balances.Get(color)
+	if !exists {
+		return 0, false
+	}
 
 	return balance.(uint64), exists
 }
@@ -243,3 +249,243 @@ func (c *ColoredBalances) String() string {
 }
 
 // endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region ColorMetadata ////////////////////////////////////////////////////////////////////////////////////////////////
+
+// ColorMetadata contains the supply information of a Color that is tracked by the UTXODAG. It keeps track of the
+// Transaction that minted the Color, the amount of tokens that were minted and the amount of tokens that were burned
+// (converted back to ColorIOTA) afterwards.
+type ColorMetadata struct {
+	color                Color
+	mintingTransactionID TransactionID
+	mintedSupply         uint64
+	burnedSupply         uint64
+	supplyMutex          sync.RWMutex
+
+	objectstorage.StorableObjectFlags
+}
+
+// NewColorMetadata creates a new ColorMetadata object for a Color that was minted by the given Transaction. Colors
+// that were imported from a snapshot use the GenesisTransactionID as their minting Transaction.
+func NewColorMetadata(color Color, mintingTransactionID TransactionID) *ColorMetadata {
+	return &ColorMetadata{
+		color:                color,
+		mintingTransactionID: mintingTransactionID,
+	}
+}
+
+// ColorMetadataFromBytes unmarshals a ColorMetadata object from a sequence of bytes.
+func ColorMetadataFromBytes(bytes []byte) (colorMetadata *ColorMetadata, consumedBytes int, err error) {
+	marshalUtil := marshalutil.New(bytes)
+	if colorMetadata, err = ColorMetadataFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse ColorMetadata from MarshalUtil: %w", err)
+		return
+	}
+	consumedBytes = marshalUtil.ReadOffset()
+
+	return
+}
+
+// ColorMetadataFromMarshalUtil unmarshals a ColorMetadata object using a MarshalUtil (for easier unmarshaling).
+func ColorMetadataFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (colorMetadata *ColorMetadata, err error) {
+	colorMetadata = &ColorMetadata{}
+	if colorMetadata.color, err = ColorFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse Color: %w", err)
+		return
+	}
+	if colorMetadata.mintingTransactionID, err = TransactionIDFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse minting TransactionID: %w", err)
+		return
+	}
+	if colorMetadata.mintedSupply, err = marshalUtil.ReadUint64(); err != nil {
+		err = xerrors.Errorf("failed to parse minted supply (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	if colorMetadata.burnedSupply, err = marshalUtil.ReadUint64(); err != nil {
+		err = xerrors.Errorf("failed to parse burned supply (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+
+	return
+}
+
+// ColorMetadataFromObjectStorage restores ColorMetadata that were stored in the ObjectStorage.
+func ColorMetadataFromObjectStorage(key []byte, data []byte) (colorMetadata objectstorage.StorableObject, err error) {
+	if colorMetadata, _, err = ColorMetadataFromBytes(byteutils.ConcatBytes(key, data)); err != nil {
+		err = xerrors.Errorf("failed to parse ColorMetadata from bytes: %w", err)
+		return
+	}
+
+	return
+}
+
+// Color returns the Color that the ColorMetadata belongs to.
+func (c *ColorMetadata) Color() Color {
+	return c.color
+}
+
+// MintingTransactionID returns the identifier of the Transaction that minted the Color.
+func (c *ColorMetadata) MintingTransactionID() TransactionID {
+	return c.mintingTransactionID
+}
+
+// MintedSupply returns the total amount of tokens that were minted with the Color.
+func (c *ColorMetadata) MintedSupply() uint64 {
+	c.supplyMutex.RLock()
+	defer c.supplyMutex.RUnlock()
+
+	return c.mintedSupply
+}
+
+// BurnedSupply returns the amount of tokens of the Color that were burned (converted back to ColorIOTA).
+func (c *ColorMetadata) BurnedSupply() uint64 {
+	c.supplyMutex.RLock()
+	defer c.supplyMutex.RUnlock()
+
+	return c.burnedSupply
+}
+
+// CirculatingSupply returns the amount of tokens of the Color that are still in circulation.
+func (c *ColorMetadata) CirculatingSupply() uint64 {
+	c.supplyMutex.RLock()
+	defer c.supplyMutex.RUnlock()
+
+	if c.burnedSupply > c.mintedSupply {
+		return 0
+	}
+
+	return c.mintedSupply - c.burnedSupply
+}
+
+// IncreaseMintedSupply adds the given amount to the minted supply of the Color.
+func (c *ColorMetadata) IncreaseMintedSupply(amount uint64) {
+	c.supplyMutex.Lock()
+	defer c.supplyMutex.Unlock()
+
+	c.mintedSupply += amount
+	c.SetModified()
+}
+
+// IncreaseBurnedSupply adds the given amount to the burned supply of the Color.
+func (c *ColorMetadata) IncreaseBurnedSupply(amount uint64) {
+	c.supplyMutex.Lock()
+	defer c.supplyMutex.Unlock()
+
+	c.burnedSupply += amount
+	c.SetModified()
+}
+
+// DecreaseMintedSupply subtracts the given amount from the minted supply of the Color (i.e. if the minting Transaction
+// was rejected).
+func (c *ColorMetadata) DecreaseMintedSupply(amount uint64) {
+	c.supplyMutex.Lock()
+	defer c.supplyMutex.Unlock()
+
+	if amount > c.mintedSupply {
+		amount = c.mintedSupply
+	}
+	c.mintedSupply -= amount
+	c.SetModified()
+}
+
+// DecreaseBurnedSupply subtracts the given amount from the burned supply of the Color (i.e. if the burning Transaction
+// was rejected).
+func (c *ColorMetadata) DecreaseBurnedSupply(amount uint64) {
+	c.supplyMutex.Lock()
+	defer c.supplyMutex.Unlock()
+
+	if amount > c.burnedSupply {
+		amount = c.burnedSupply
+	}
+	c.burnedSupply -= amount
+	c.SetModified()
+}
+
+// Bytes marshals the ColorMetadata into a sequence of bytes.
+func (c *ColorMetadata) Bytes() []byte {
+	return byteutils.ConcatBytes(c.ObjectStorageKey(), c.ObjectStorageValue())
+}
+
+// String returns a human readable version of the ColorMetadata.
+func (c *ColorMetadata) String() string {
+	return stringify.Struct("ColorMetadata",
+		stringify.StructField("color", c.Color()),
+		stringify.StructField("mintingTransactionID", c.MintingTransactionID()),
+		stringify.StructField("mintedSupply", c.MintedSupply()),
+		stringify.StructField("burnedSupply", c.BurnedSupply()),
+	)
+}
+
+// Update is disabled and panics if it ever gets called - it is required to match the StorableObject interface.
+func (c *ColorMetadata) Update(objectstorage.StorableObject) {
+	panic("updates disabled")
+}
+
+// ObjectStorageKey returns the key that is used to store the object in the database. It is required to match the
+// StorableObject interface.
+func (c *ColorMetadata) ObjectStorageKey() []byte {
+	return c.color.Bytes()
+}
+
+// ObjectStorageValue marshals the ColorMetadata into a sequence of bytes. The Color is not serialized here as it is
+// only used as a key in the ObjectStorage.
+func (c *ColorMetadata) ObjectStorageValue() []byte {
+	c.supplyMutex.RLock()
+	defer c.supplyMutex.RUnlock()
+
+	return marshalutil.New().
+		Write(c.mintingTransactionID).
+		WriteUint64(c.mintedSupply).
+		WriteUint64(c.burnedSupply).
+		Bytes()
+}
+
+// code contract (make sure the type implements all required methods)
+var _ objectstorage.StorableObject = &ColorMetadata{}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region CachedColorMetadata //////////////////////////////////////////////////////////////////////////////////////////
+
+// CachedColorMetadata is a wrapper for the generic CachedObject returned by the object storage that overrides the
+// accessor methods with a type-casted one.
+type CachedColorMetadata struct {
+	objectstorage.CachedObject
+}
+
+// Retain marks the CachedObject to still be in use by the program.
+func (c *CachedColorMetadata) Retain() *CachedColorMetadata {
+	return &CachedColorMetadata{c.CachedObject.Retain()}
+}
+
+// Unwrap is the type-casted equivalent of Get. It returns nil if the object does not exist.
+func (c *CachedColorMetadata) Unwrap() *ColorMetadata {
+	untypedObject := c.Get()
+	if untypedObject == nil {
+		return nil
+	}
+
+	typedObject := untypedObject.(*ColorMetadata)
+	if typedObject == nil || typedObject.IsDeleted() {
+		return nil
+	}
+
+	return typedObject
+}
+
+// Consume unwraps the CachedObject and passes a type-casted version to the consumer (if the object is not empty - it
+// exists). It automatically releases the object when the consumer finishes.
+func (c *CachedColorMetadata) Consume(consumer func(colorMetadata *ColorMetadata), forceRelease ...bool) (consumed bool) {
+	return c.CachedObject.Consume(func(object objectstorage.StorableObject) {
+		consumer(object.(*ColorMetadata))
+	}, forceRelease...)
+}
+
+// String returns a human readable version of the CachedColorMetadata.
+func (c *CachedColorMetadata) String() string {
+	return stringify.Struct("CachedColorMetadata",
+		stringify.StructField("CachedObject", c.Unwrap()),
+	)
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
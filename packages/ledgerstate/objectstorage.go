@@ -36,6 +36,12 @@ const (
 
 	// PrefixAddressOutputMappingStorage defines the storage prefix for the AddressOutputMapping object storage.
 	PrefixAddressOutputMappingStorage
+
+	// PrefixColorMetadataStorage defines the storage prefix for the ColorMetadata object storage.
+	PrefixColorMetadataStorage
+
+	// PrefixAddressHistoryStorage defines the storage prefix for the AddressHistoryEntry object storage.
+	PrefixAddressHistoryStorage
 )
 
 // branchStorageOptions contains a list of default settings for the Branch object storage.
@@ -101,3 +107,16 @@ var addressOutputMappingStorageOptions = []objectstorage.Option{
 	objectstorage.PartitionKey(AddressLength, OutputIDLength),
 	objectstorage.LeakDetectionEnabled(false),
 }
+
+// addressHistoryStorageOptions contains a list of default settings for the AddressHistoryEntry object storage.
+var addressHistoryStorageOptions = []objectstorage.Option{
+	AddressHistoryEntryPartitionKeys,
+	objectstorage.CacheTime(10 * time.Second),
+	objectstorage.LeakDetectionEnabled(false),
+}
+
+// colorMetadataStorageOptions contains a list of default settings for the ColorMetadata object storage.
+var colorMetadataStorageOptions = []objectstorage.Option{
+	objectstorage.CacheTime(10 * time.Second),
+	objectstorage.LeakDetectionEnabled(false),
+}
@@ -51,6 +51,12 @@ const (
 
 	// SigLockedColoredOutputType represents an Output that holds colored coins that gets unlocked by a signature.
 	SigLockedColoredOutputType
+
+	// AliasOutputType represents an Output which makes a chain with optional governance.
+	AliasOutputType
+
+	// ExtendedLockedOutputType represents an Output that supports additional unlock conditions and a data payload.
+	ExtendedLockedOutputType
 )
 
 // String returns a human readable representation of the OutputType.
@@ -58,6 +64,8 @@ func (o OutputType) String() string {
 	return [...]string{
 		"SigLockedSingleOutputType",
 		"SigLockedColoredOutputType",
+		"AliasOutputType",
+		"ExtendedLockedOutputType",
 	}[o]
 }
 
@@ -187,8 +195,8 @@ type Output interface {
 	Address() Address
 
 	// UnlockValid determines if the given Transaction and the corresponding UnlockBlock are allowed to spend the
-	// Output.
-	UnlockValid(tx *Transaction, unlockBlock UnlockBlock) (bool, error)
+	// Output. The consumed Outputs of the Transaction are required to validate UnlockBlocks that reference other Inputs.
+	UnlockValid(tx *Transaction, unlockBlock UnlockBlock, inputs []Output) (bool, error)
 
 	// Input returns an Input that references the Output.
 	Input() Input
@@ -241,6 +249,16 @@ func OutputFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (output Output,
 			err = xerrors.Errorf("failed to parse SigLockedColoredOutput: %w", err)
 			return
 		}
+	case AliasOutputType:
+		if output, err = AliasOutputFromMarshalUtil(marshalUtil); err != nil {
+			err = xerrors.Errorf("failed to parse AliasOutput: %w", err)
+			return
+		}
+	case ExtendedLockedOutputType:
+		if output, err = ExtendedLockedOutputFromMarshalUtil(marshalUtil); err != nil {
+			err = xerrors.Errorf("failed to parse ExtendedLockedOutput: %w", err)
+			return
+		}
 	default:
 		err = xerrors.Errorf("unsupported OutputType (%X): %w", outputType, cerrors.ErrParseBytesFailed)
 		return
@@ -266,6 +284,40 @@ func OutputFromObjectStorage(key []byte, data []byte) (output objectstorage.Stor
 	return
 }
 
+// unlockBlockValidForAddress is an internal utility function that checks if the given UnlockBlock authorizes the
+// spending of Outputs that are locked to the given Address. Addresses that are secured by a signature scheme require a
+// SignatureUnlockBlock, ThresholdAddresses require a ThresholdSignatureUnlockBlock and AliasAddresses require an
+// AliasUnlockBlock that references the consumed AliasOutput.
+func unlockBlockValidForAddress(address Address, tx *Transaction, unlockBlock UnlockBlock, inputs []Output) (unlockValid bool, err error) {
+	switch typedUnlockBlock := unlockBlock.(type) {
+	case *SignatureUnlockBlock:
+		unlockValid = typedUnlockBlock.AddressSignatureValid(address, tx.Essence().Bytes())
+	case *ThresholdSignatureUnlockBlock:
+		unlockValid = typedUnlockBlock.AddressSignatureValid(address, tx.Essence().Bytes())
+	case *AliasUnlockBlock:
+		aliasAddress, isAliasAddress := address.(*AliasAddress)
+		if !isAliasAddress {
+			err = xerrors.Errorf("AliasUnlockBlock can not unlock Address of type %s: %w", address.Type(), ErrTransactionInvalid)
+			return
+		}
+		if int(typedUnlockBlock.AliasInputIndex()) >= len(inputs) {
+			err = xerrors.Errorf("AliasUnlockBlock references non-existing Input with index %d: %w", typedUnlockBlock.AliasInputIndex(), ErrTransactionInvalid)
+			return
+		}
+		aliasOutput, isAliasOutput := inputs[typedUnlockBlock.AliasInputIndex()].(*AliasOutput)
+		if !isAliasOutput {
+			err = xerrors.Errorf("AliasUnlockBlock does not reference an AliasOutput: %w", ErrTransactionInvalid)
+			return
+		}
+
+		unlockValid = aliasOutput.AliasAddress().Equals(aliasAddress)
+	default:
+		err = xerrors.Errorf("UnlockBlock of type %s can not unlock Address of type %s: %w", unlockBlock.Type(), address.Type(), ErrTransactionInvalid)
+	}
+
+	return
+}
+
 // endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 // region Outputs //////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -580,16 +632,8 @@ func (s *SigLockedSingleOutput) Balances() *ColoredBalances {
 }
 
 // UnlockValid determines if the given Transaction and the corresponding UnlockBlock are allowed to spend the Output.
-func (s *SigLockedSingleOutput) UnlockValid(tx *Transaction, unlockBlock UnlockBlock) (unlockValid bool, err error) {
-	signatureUnlockBlock, correctType := unlockBlock.(*SignatureUnlockBlock)
-	if !correctType {
-		err = xerrors.Errorf("UnlockBlock does not match expected OutputType: %w", cerrors.ErrParseBytesFailed)
-		return
-	}
-
-	unlockValid = signatureUnlockBlock.AddressSignatureValid(s.address, tx.Essence().Bytes())
-
-	return
+func (s *SigLockedSingleOutput) UnlockValid(tx *Transaction, unlockBlock UnlockBlock, inputs []Output) (unlockValid bool, err error) {
+	return unlockBlockValidForAddress(s.address, tx, unlockBlock, inputs)
 }
 
 // Address returns the Address that the Output is associated to.
@@ -751,16 +795,8 @@ func (s *SigLockedColoredOutput) Balances() *ColoredBalances {
 }
 
 // UnlockValid determines if the given Transaction and the corresponding UnlockBlock are allowed to spend the Output.
-func (s *SigLockedColoredOutput) UnlockValid(tx *Transaction, unlockBlock UnlockBlock) (unlockValid bool, err error) {
-	signatureUnlockBlock, correctType := unlockBlock.(*SignatureUnlockBlock)
-	if !correctType {
-		err = xerrors.Errorf("UnlockBlock does not match expected OutputType: %w", cerrors.ErrParseBytesFailed)
-		return
-	}
-
-	unlockValid = signatureUnlockBlock.AddressSignatureValid(s.address, tx.Essence().Bytes())
-
-	return
+func (s *SigLockedColoredOutput) UnlockValid(tx *Transaction, unlockBlock UnlockBlock, inputs []Output) (unlockValid bool, err error) {
+	return unlockBlockValidForAddress(s.address, tx, unlockBlock, inputs)
 }
 
 // Address returns the Address that the Output is associated to.
@@ -846,6 +882,742 @@ var _ Output = &SigLockedColoredOutput{}
 
 // endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
 
+// region AliasOutput //////////////////////////////////////////////////////////////////////////////////////////////////
+
+// MaxAliasOutputDataSize defines the maximum size of the state data and the immutable data of an AliasOutput.
+const MaxAliasOutputDataSize = 4 * 1024
+
+// AliasOutput is an Output that represents a long-lived account on the ledger. Its identity (the AliasAddress) survives
+// being spent, as every Transaction that consumes an AliasOutput has to create exactly one chained AliasOutput with the
+// same AliasAddress (unless the alias gets destroyed by its governor). State transitions increase the state index and
+// are authorized by the state controller, while governance transitions keep the state and are authorized by the
+// governor.
+type AliasOutput struct {
+	id               OutputID
+	idMutex          sync.RWMutex
+	balances         *ColoredBalances
+	aliasAddress     *AliasAddress
+	stateAddress     Address
+	governingAddress Address
+	stateIndex       uint32
+	stateData        []byte
+	immutableData    []byte
+
+	objectstorage.StorableObjectFlags
+}
+
+// NewAliasOutputMint is the constructor of an AliasOutput that creates a new alias. The AliasAddress of the new alias
+// is derived from the OutputID once the Output has been booked.
+func NewAliasOutputMint(balances *ColoredBalances, stateAddress Address, governingAddress Address, immutableData []byte) (output *AliasOutput, err error) {
+	if len(immutableData) > MaxAliasOutputDataSize {
+		err = xerrors.Errorf("size of immutable data (%d) exceeds MaxAliasOutputDataSize (%d): %w", len(immutableData), MaxAliasOutputDataSize, ErrTransactionInvalid)
+		return
+	}
+
+	output = &AliasOutput{
+		balances:         balances,
+		aliasAddress:     &AliasAddress{digest: make([]byte, AliasAddressDigestSize)},
+		stateAddress:     stateAddress,
+		governingAddress: governingAddress,
+		immutableData:    immutableData,
+	}
+
+	return
+}
+
+// AliasOutputFromBytes unmarshals an AliasOutput from a sequence of bytes.
+func AliasOutputFromBytes(bytes []byte) (output *AliasOutput, consumedBytes int, err error) {
+	marshalUtil := marshalutil.New(bytes)
+	if output, err = AliasOutputFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse AliasOutput from MarshalUtil: %w", err)
+		return
+	}
+	consumedBytes = marshalUtil.ReadOffset()
+
+	return
+}
+
+// AliasOutputFromMarshalUtil unmarshals an AliasOutput using a MarshalUtil (for easier unmarshaling).
+func AliasOutputFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (output *AliasOutput, err error) {
+	outputType, err := marshalUtil.ReadByte()
+	if err != nil {
+		err = xerrors.Errorf("failed to parse OutputType (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	if OutputType(outputType) != AliasOutputType {
+		err = xerrors.Errorf("invalid OutputType (%X): %w", outputType, cerrors.ErrParseBytesFailed)
+		return
+	}
+
+	output = &AliasOutput{}
+	if output.balances, err = ColoredBalancesFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse ColoredBalances: %w", err)
+		return
+	}
+	if output.aliasAddress, err = AliasAddressFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse AliasAddress: %w", err)
+		return
+	}
+	if output.stateAddress, err = AddressFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse state Address: %w", err)
+		return
+	}
+	if output.governingAddress, err = AddressFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse governing Address: %w", err)
+		return
+	}
+	if output.stateIndex, err = marshalUtil.ReadUint32(); err != nil {
+		err = xerrors.Errorf("failed to parse state index (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	if output.stateData, err = readAliasOutputData(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse state data: %w", err)
+		return
+	}
+	if output.immutableData, err = readAliasOutputData(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse immutable data: %w", err)
+		return
+	}
+
+	return
+}
+
+// readAliasOutputData is an internal utility function that reads length prefixed data of an AliasOutput.
+func readAliasOutputData(marshalUtil *marshalutil.MarshalUtil) (data []byte, err error) {
+	dataLength, err := marshalUtil.ReadUint16()
+	if err != nil {
+		err = xerrors.Errorf("failed to parse data length (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	if dataLength > MaxAliasOutputDataSize {
+		err = xerrors.Errorf("data length (%d) exceeds MaxAliasOutputDataSize (%d): %w", dataLength, MaxAliasOutputDataSize, cerrors.ErrParseBytesFailed)
+		return
+	}
+	if data, err = marshalUtil.ReadBytes(int(dataLength)); err != nil {
+		err = xerrors.Errorf("failed to parse data (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+
+	return
+}
+
+// ID returns the identifier of the Output that is used to address the Output in the UTXODAG.
+func (a *AliasOutput) ID() OutputID {
+	a.idMutex.RLock()
+	defer a.idMutex.RUnlock()
+
+	return a.id
+}
+
+// SetID allows to set the identifier of the Output. We offer a setter for the property since Outputs that are
+// created to become part of a transaction usually do not have an identifier, yet as their identifier depends on
+// the TransactionID that is only determinable after the Transaction has been fully constructed. The ID is therefore
+// only accessed when the Output is supposed to be persisted by the node.
+func (a *AliasOutput) SetID(outputID OutputID) Output {
+	a.idMutex.Lock()
+	defer a.idMutex.Unlock()
+
+	a.id = outputID
+
+	return a
+}
+
+// Type returns the type of the Output which allows us to generically handle Outputs of different types.
+func (a *AliasOutput) Type() OutputType {
+	return AliasOutputType
+}
+
+// Balances returns the funds that are associated with the Output.
+func (a *AliasOutput) Balances() *ColoredBalances {
+	return a.balances
+}
+
+// Address returns the AliasAddress of the Output (other Outputs can be locked to it).
+func (a *AliasOutput) Address() Address {
+	return a.AliasAddress()
+}
+
+// AliasAddress returns the stable identifier of the alias. Newly created aliases derive it from their OutputID.
+func (a *AliasOutput) AliasAddress() *AliasAddress {
+	if a.IsOrigin() {
+		return NewAliasAddress(a.ID().Bytes())
+	}
+
+	return a.aliasAddress
+}
+
+// IsOrigin returns true if the AliasOutput creates a new alias.
+func (a *AliasOutput) IsOrigin() bool {
+	return a.aliasAddress.IsEmpty()
+}
+
+// StateAddress returns the Address of the state controller that is allowed to perform state transitions.
+func (a *AliasOutput) StateAddress() Address {
+	return a.stateAddress
+}
+
+// GoverningAddress returns the Address of the governor that is allowed to change the controlling Addresses and to
+// destroy the alias.
+func (a *AliasOutput) GoverningAddress() Address {
+	return a.governingAddress
+}
+
+// StateIndex returns the amount of state transitions that the alias went through.
+func (a *AliasOutput) StateIndex() uint32 {
+	return a.stateIndex
+}
+
+// StateData returns the mutable state data of the alias.
+func (a *AliasOutput) StateData() []byte {
+	return a.stateData
+}
+
+// ImmutableData returns the data that was attached to the alias when it was created.
+func (a *AliasOutput) ImmutableData() []byte {
+	return a.immutableData
+}
+
+// NewAliasOutputNext creates the chained AliasOutput that continues the alias in a new Transaction. State transitions
+// increase the state index while governance transitions keep it.
+func (a *AliasOutput) NewAliasOutputNext(governanceUpdate bool) (next *AliasOutput) {
+	next = a.Clone().(*AliasOutput)
+	next.id = EmptyOutputID
+	next.aliasAddress = a.AliasAddress().Clone().(*AliasAddress)
+	if !governanceUpdate {
+		next.stateIndex++
+	}
+
+	return
+}
+
+// UpdateMintingColor replaces the ColorMint in the balances of the Output with the hash of the OutputID. It returns a
+// copy of the original Output with the modified balances.
+func (a *AliasOutput) UpdateMintingColor() (updatedOutput *AliasOutput) {
+	coloredBalances := a.Balances().Map()
+	if mintedCoins, mintedCoinsExist := coloredBalances[ColorMint]; mintedCoinsExist {
+		delete(coloredBalances, ColorMint)
+		coloredBalances[Color(blake2b.Sum256(a.ID().Bytes()))] = mintedCoins
+	}
+	updatedOutput = a.Clone().(*AliasOutput)
+	updatedOutput.balances = NewColoredBalances(coloredBalances)
+
+	return
+}
+
+// SetBalances sets the funds that are associated with the Output.
+func (a *AliasOutput) SetBalances(balances *ColoredBalances) {
+	a.balances = balances
+}
+
+// SetStateAddress sets the Address of the state controller.
+func (a *AliasOutput) SetStateAddress(stateAddress Address) {
+	a.stateAddress = stateAddress
+}
+
+// SetGoverningAddress sets the Address of the governor.
+func (a *AliasOutput) SetGoverningAddress(governingAddress Address) {
+	a.governingAddress = governingAddress
+}
+
+// SetStateData sets the mutable state data of the alias.
+func (a *AliasOutput) SetStateData(stateData []byte) (err error) {
+	if len(stateData) > MaxAliasOutputDataSize {
+		return xerrors.Errorf("size of state data (%d) exceeds MaxAliasOutputDataSize (%d): %w", len(stateData), MaxAliasOutputDataSize, ErrTransactionInvalid)
+	}
+	a.stateData = stateData
+
+	return
+}
+
+// UnlockValid determines if the given Transaction and the corresponding UnlockBlock are allowed to spend the Output.
+// The Transaction has to contain a valid successor of the alias (or none if the governor destroys the alias) and the
+// UnlockBlock has to unlock the controlling Address that is required for the performed transition.
+func (a *AliasOutput) UnlockValid(tx *Transaction, unlockBlock UnlockBlock, inputs []Output) (unlockValid bool, err error) {
+	chainedOutput, err := a.chainedOutput(tx)
+	if err != nil {
+		return
+	}
+
+	var controllingAddress Address
+	switch {
+	case chainedOutput == nil:
+		controllingAddress = a.governingAddress
+	case chainedOutput.stateIndex == a.stateIndex+1:
+		if err = a.validateStateTransition(chainedOutput); err != nil {
+			return
+		}
+		controllingAddress = a.stateAddress
+	case chainedOutput.stateIndex == a.stateIndex:
+		if err = a.validateGovernanceTransition(chainedOutput); err != nil {
+			return
+		}
+		controllingAddress = a.governingAddress
+	default:
+		err = xerrors.Errorf("invalid state index (%d) of chained AliasOutput (expected %d or %d): %w", chainedOutput.stateIndex, a.stateIndex, a.stateIndex+1, ErrTransactionInvalid)
+		return
+	}
+
+	return unlockBlockValidForAddress(controllingAddress, tx, unlockBlock, inputs)
+}
+
+// chainedOutput is an internal utility function that returns the AliasOutput of the given Transaction that continues
+// the alias (or nil if the alias is destroyed).
+func (a *AliasOutput) chainedOutput(tx *Transaction) (chainedOutput *AliasOutput, err error) {
+	aliasAddress := a.AliasAddress()
+	for _, output := range tx.Essence().Outputs() {
+		aliasOutput, isAliasOutput := output.(*AliasOutput)
+		if !isAliasOutput || aliasOutput.IsOrigin() || !aliasOutput.aliasAddress.Equals(aliasAddress) {
+			continue
+		}
+
+		if chainedOutput != nil {
+			err = xerrors.Errorf("more than one chained AliasOutput for %s: %w", aliasAddress.Base58(), ErrTransactionInvalid)
+			return
+		}
+		chainedOutput = aliasOutput
+	}
+
+	return
+}
+
+// validateStateTransition is an internal utility function that checks if the chained AliasOutput only modifies the
+// parts of the alias that the state controller is allowed to modify.
+func (a *AliasOutput) validateStateTransition(chainedOutput *AliasOutput) (err error) {
+	if !bytes.Equal(a.stateAddress.Bytes(), chainedOutput.stateAddress.Bytes()) || !bytes.Equal(a.governingAddress.Bytes(), chainedOutput.governingAddress.Bytes()) {
+		return xerrors.Errorf("state transition must not modify the controlling Addresses: %w", ErrTransactionInvalid)
+	}
+	if !bytes.Equal(a.immutableData, chainedOutput.immutableData) {
+		return xerrors.Errorf("state transition must not modify the immutable data: %w", ErrTransactionInvalid)
+	}
+
+	return
+}
+
+// validateGovernanceTransition is an internal utility function that checks if the chained AliasOutput only modifies
+// the parts of the alias that the governor is allowed to modify.
+func (a *AliasOutput) validateGovernanceTransition(chainedOutput *AliasOutput) (err error) {
+	if !bytes.Equal(a.balances.Bytes(), chainedOutput.balances.Bytes()) {
+		return xerrors.Errorf("governance transition must not modify the balances: %w", ErrTransactionInvalid)
+	}
+	if !bytes.Equal(a.stateData, chainedOutput.stateData) || !bytes.Equal(a.immutableData, chainedOutput.immutableData) {
+		return xerrors.Errorf("governance transition must not modify the data of the alias: %w", ErrTransactionInvalid)
+	}
+
+	return
+}
+
+// Input returns an Input that references the Output.
+func (a *AliasOutput) Input() Input {
+	if a.ID() == EmptyOutputID {
+		panic("Outputs that haven't been assigned an ID yet cannot be converted to an Input")
+	}
+
+	return NewUTXOInput(a.ID())
+}
+
+// Clone creates a copy of the Output.
+func (a *AliasOutput) Clone() Output {
+	return &AliasOutput{
+		id:               a.ID(),
+		balances:         a.balances.Clone(),
+		aliasAddress:     a.aliasAddress.Clone().(*AliasAddress),
+		stateAddress:     a.stateAddress.Clone(),
+		governingAddress: a.governingAddress.Clone(),
+		stateIndex:       a.stateIndex,
+		stateData:        append([]byte{}, a.stateData...),
+		immutableData:    append([]byte{}, a.immutableData...),
+	}
+}
+
+// Bytes returns a marshaled version of the Output.
+func (a *AliasOutput) Bytes() []byte {
+	return a.ObjectStorageValue()
+}
+
+// Update is disabled and panics if it ever gets called - it is required to match the StorableObject interface.
+func (a *AliasOutput) Update(objectstorage.StorableObject) {
+	panic("updates disabled")
+}
+
+// ObjectStorageKey returns the key that is used to store the object in the database. It is required to match the
+// StorableObject interface.
+func (a *AliasOutput) ObjectStorageKey() []byte {
+	return a.ID().Bytes()
+}
+
+// ObjectStorageValue marshals the Output into a sequence of bytes. The ID is not serialized here as it is only used as
+// a key in the ObjectStorage.
+func (a *AliasOutput) ObjectStorageValue() []byte {
+	return marshalutil.New().
+		WriteByte(byte(AliasOutputType)).
+		WriteBytes(a.balances.Bytes()).
+		WriteBytes(a.aliasAddress.Bytes()).
+		WriteBytes(a.stateAddress.Bytes()).
+		WriteBytes(a.governingAddress.Bytes()).
+		WriteUint32(a.stateIndex).
+		WriteUint16(uint16(len(a.stateData))).
+		WriteBytes(a.stateData).
+		WriteUint16(uint16(len(a.immutableData))).
+		WriteBytes(a.immutableData).
+		Bytes()
+}
+
+// Compare offers a comparator for Outputs which returns -1 if the other Output is bigger, 1 if it is smaller and 0 if
+// they are the same.
+func (a *AliasOutput) Compare(other Output) int {
+	return bytes.Compare(a.Bytes(), other.Bytes())
+}
+
+// String returns a human readable version of the Output.
+func (a *AliasOutput) String() string {
+	return stringify.Struct("AliasOutput",
+		stringify.StructField("id", a.ID()),
+		stringify.StructField("aliasAddress", a.AliasAddress()),
+		stringify.StructField("balances", a.balances),
+		stringify.StructField("stateAddress", a.stateAddress),
+		stringify.StructField("governingAddress", a.governingAddress),
+		stringify.StructField("stateIndex", a.stateIndex),
+		stringify.StructField("stateData", a.stateData),
+		stringify.StructField("immutableData", a.immutableData),
+	)
+}
+
+// code contract (make sure the type implements all required methods)
+var _ Output = &AliasOutput{}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region ExtendedLockedOutput /////////////////////////////////////////////////////////////////////////////////////////
+
+// MaxOutputPayloadSize defines the maximum size of the data payload of an ExtendedLockedOutput.
+const MaxOutputPayloadSize = 4 * 1024
+
+const (
+	// flagExtendedLockedOutputFallbackPresent marks that the ExtendedLockedOutput contains a fallback Address.
+	flagExtendedLockedOutputFallbackPresent = 1 << iota
+
+	// flagExtendedLockedOutputTimeLockPresent marks that the ExtendedLockedOutput contains a timelock.
+	flagExtendedLockedOutputTimeLockPresent
+
+	// flagExtendedLockedOutputPayloadPresent marks that the ExtendedLockedOutput contains a data payload.
+	flagExtendedLockedOutputPayloadPresent
+)
+
+// ExtendedLockedOutput is an Output that holds colored balances and that can be unlocked by providing a signature for
+// an Address. It supports an optional timelock (the Output can not be spent before the given time), an optional
+// fallback Address (that is the only one that can unlock the Output once the fallback deadline has passed) and an
+// optional data payload. All time based conditions are evaluated against the timestamp of the spending Transaction.
+type ExtendedLockedOutput struct {
+	id               OutputID
+	idMutex          sync.RWMutex
+	balances         *ColoredBalances
+	address          Address
+	fallbackAddress  Address
+	fallbackDeadline time.Time
+	timelock         time.Time
+	payload          []byte
+
+	objectstorage.StorableObjectFlags
+}
+
+// NewExtendedLockedOutput is the constructor for an ExtendedLockedOutput.
+func NewExtendedLockedOutput(balances *ColoredBalances, address Address) *ExtendedLockedOutput {
+	return &ExtendedLockedOutput{
+		balances: balances,
+		address:  address,
+	}
+}
+
+// ExtendedLockedOutputFromBytes unmarshals an ExtendedLockedOutput from a sequence of bytes.
+func ExtendedLockedOutputFromBytes(bytes []byte) (output *ExtendedLockedOutput, consumedBytes int, err error) {
+	marshalUtil := marshalutil.New(bytes)
+	if output, err = ExtendedLockedOutputFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse ExtendedLockedOutput from MarshalUtil: %w", err)
+		return
+	}
+	consumedBytes = marshalUtil.ReadOffset()
+
+	return
+}
+
+// ExtendedLockedOutputFromMarshalUtil unmarshals an ExtendedLockedOutput using a MarshalUtil (for easier unmarshaling).
+func ExtendedLockedOutputFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (output *ExtendedLockedOutput, err error) {
+	outputType, err := marshalUtil.ReadByte()
+	if err != nil {
+		err = xerrors.Errorf("failed to parse OutputType (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	if OutputType(outputType) != ExtendedLockedOutputType {
+		err = xerrors.Errorf("invalid OutputType (%X): %w", outputType, cerrors.ErrParseBytesFailed)
+		return
+	}
+
+	output = &ExtendedLockedOutput{}
+	if output.balances, err = ColoredBalancesFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse ColoredBalances: %w", err)
+		return
+	}
+	if output.address, err = AddressFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse Address (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	flags, err := marshalUtil.ReadByte()
+	if err != nil {
+		err = xerrors.Errorf("failed to parse flags (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	if flags&flagExtendedLockedOutputFallbackPresent != 0 {
+		if output.fallbackAddress, err = AddressFromMarshalUtil(marshalUtil); err != nil {
+			err = xerrors.Errorf("failed to parse fallback Address (%v): %w", err, cerrors.ErrParseBytesFailed)
+			return
+		}
+		if output.fallbackDeadline, err = marshalUtil.ReadTime(); err != nil {
+			err = xerrors.Errorf("failed to parse fallback deadline (%v): %w", err, cerrors.ErrParseBytesFailed)
+			return
+		}
+	}
+	if flags&flagExtendedLockedOutputTimeLockPresent != 0 {
+		if output.timelock, err = marshalUtil.ReadTime(); err != nil {
+			err = xerrors.Errorf("failed to parse timelock (%v): %w", err, cerrors.ErrParseBytesFailed)
+			return
+		}
+	}
+	if flags&flagExtendedLockedOutputPayloadPresent != 0 {
+		payloadSize, payloadSizeErr := marshalUtil.ReadUint16()
+		if payloadSizeErr != nil {
+			err = xerrors.Errorf("failed to parse payload size (%v): %w", payloadSizeErr, cerrors.ErrParseBytesFailed)
+			return
+		}
+		if payloadSize > MaxOutputPayloadSize {
+			err = xerrors.Errorf("payload size (%d) exceeds MaxOutputPayloadSize (%d): %w", payloadSize, MaxOutputPayloadSize, cerrors.ErrParseBytesFailed)
+			return
+		}
+		if output.payload, err = marshalUtil.ReadBytes(int(payloadSize)); err != nil {
+			err = xerrors.Errorf("failed to parse payload (%v): %w", err, cerrors.ErrParseBytesFailed)
+			return
+		}
+	}
+
+	return
+}
+
+// WithFallbackOptions adds a fallback Address to the Output that is the only Address that can unlock the Output after
+// the given deadline.
+func (o *ExtendedLockedOutput) WithFallbackOptions(fallbackAddress Address, fallbackDeadline time.Time) *ExtendedLockedOutput {
+	o.fallbackAddress = fallbackAddress
+	o.fallbackDeadline = fallbackDeadline
+
+	return o
+}
+
+// WithTimeLock adds a timelock to the Output which prevents it from being spent before the given time.
+func (o *ExtendedLockedOutput) WithTimeLock(timelock time.Time) *ExtendedLockedOutput {
+	o.timelock = timelock
+
+	return o
+}
+
+// SetPayload sets the data payload of the Output.
+func (o *ExtendedLockedOutput) SetPayload(payload []byte) (err error) {
+	if len(payload) > MaxOutputPayloadSize {
+		return xerrors.Errorf("payload size (%d) exceeds MaxOutputPayloadSize (%d): %w", len(payload), MaxOutputPayloadSize, ErrTransactionInvalid)
+	}
+	o.payload = payload
+
+	return
+}
+
+// ID returns the identifier of the Output that is used to address the Output in the UTXODAG.
+func (o *ExtendedLockedOutput) ID() OutputID {
+	o.idMutex.RLock()
+	defer o.idMutex.RUnlock()
+
+	return o.id
+}
+
+// SetID allows to set the identifier of the Output. We offer a setter for the property since Outputs that are
+// created to become part of a transaction usually do not have an identifier, yet as their identifier depends on
+// the TransactionID that is only determinable after the Transaction has been fully constructed. The ID is therefore
+// only accessed when the Output is supposed to be persisted by the node.
+func (o *ExtendedLockedOutput) SetID(outputID OutputID) Output {
+	o.idMutex.Lock()
+	defer o.idMutex.Unlock()
+
+	o.id = outputID
+
+	return o
+}
+
+// Type returns the type of the Output which allows us to generically handle Outputs of different types.
+func (o *ExtendedLockedOutput) Type() OutputType {
+	return ExtendedLockedOutputType
+}
+
+// Balances returns the funds that are associated with the Output.
+func (o *ExtendedLockedOutput) Balances() *ColoredBalances {
+	return o.balances
+}
+
+// Address returns the Address that the Output is associated to.
+func (o *ExtendedLockedOutput) Address() Address {
+	return o.address
+}
+
+// FallbackAddress returns the fallback Address of the Output and the deadline after which it can unlock the Output (or
+// nil if the Output has no fallback).
+func (o *ExtendedLockedOutput) FallbackAddress() (fallbackAddress Address, fallbackDeadline time.Time) {
+	return o.fallbackAddress, o.fallbackDeadline
+}
+
+// TimeLock returns the time before which the Output can not be spent (the zero time if the Output has no timelock).
+func (o *ExtendedLockedOutput) TimeLock() time.Time {
+	return o.timelock
+}
+
+// TimeLockedNow returns true if the timelock of the Output prevents it from being spent at the given time.
+func (o *ExtendedLockedOutput) TimeLockedNow(now time.Time) bool {
+	return now.Before(o.timelock)
+}
+
+// UnlockAddressNow returns the Address that is able to unlock the Output at the given time.
+func (o *ExtendedLockedOutput) UnlockAddressNow(now time.Time) Address {
+	if o.fallbackAddress != nil && !now.Before(o.fallbackDeadline) {
+		return o.fallbackAddress
+	}
+
+	return o.address
+}
+
+// Payload returns the data payload of the Output.
+func (o *ExtendedLockedOutput) Payload() []byte {
+	return o.payload
+}
+
+// UnlockValid determines if the given Transaction and the corresponding UnlockBlock are allowed to spend the Output.
+// The timestamp of the Transaction determines if the timelock expired and which Address is able to unlock the Output.
+func (o *ExtendedLockedOutput) UnlockValid(tx *Transaction, unlockBlock UnlockBlock, inputs []Output) (unlockValid bool, err error) {
+	if o.TimeLockedNow(tx.Essence().Timestamp()) {
+		err = xerrors.Errorf("Output is timelocked until %s: %w", o.timelock, ErrTransactionInvalid)
+		return
+	}
+
+	return unlockBlockValidForAddress(o.UnlockAddressNow(tx.Essence().Timestamp()), tx, unlockBlock, inputs)
+}
+
+// Input returns an Input that references the Output.
+func (o *ExtendedLockedOutput) Input() Input {
+	if o.ID() == EmptyOutputID {
+		panic("Outputs that haven't been assigned an ID yet cannot be converted to an Input")
+	}
+
+	return NewUTXOInput(o.ID())
+}
+
+// Clone creates a copy of the Output.
+func (o *ExtendedLockedOutput) Clone() Output {
+	clonedOutput := &ExtendedLockedOutput{
+		id:               o.ID(),
+		balances:         o.balances.Clone(),
+		address:          o.address.Clone(),
+		fallbackDeadline: o.fallbackDeadline,
+		timelock:         o.timelock,
+	}
+	if o.fallbackAddress != nil {
+		clonedOutput.fallbackAddress = o.fallbackAddress.Clone()
+	}
+	if o.payload != nil {
+		clonedOutput.payload = append([]byte{}, o.payload...)
+	}
+
+	return clonedOutput
+}
+
+// UpdateMintingColor replaces the ColorMint in the balances of the Output with the hash of the OutputID. It returns a
+// copy of the original Output with the modified balances.
+func (o *ExtendedLockedOutput) UpdateMintingColor() (updatedOutput *ExtendedLockedOutput) {
+	coloredBalances := o.Balances().Map()
+	if mintedCoins, mintedCoinsExist := coloredBalances[ColorMint]; mintedCoinsExist {
+		delete(coloredBalances, ColorMint)
+		coloredBalances[Color(blake2b.Sum256(o.ID().Bytes()))] = mintedCoins
+	}
+	updatedOutput = o.Clone().(*ExtendedLockedOutput)
+	updatedOutput.balances = NewColoredBalances(coloredBalances)
+
+	return
+}
+
+// Bytes returns a marshaled version of the Output.
+func (o *ExtendedLockedOutput) Bytes() []byte {
+	return o.ObjectStorageValue()
+}
+
+// Update is disabled and panics if it ever gets called - it is required to match the StorableObject interface.
+func (o *ExtendedLockedOutput) Update(objectstorage.StorableObject) {
+	panic("updates disabled")
+}
+
+// ObjectStorageKey returns the key that is used to store the object in the database. It is required to match the
+// StorableObject interface.
+func (o *ExtendedLockedOutput) ObjectStorageKey() []byte {
+	return o.ID().Bytes()
+}
+
+// ObjectStorageValue marshals the Output into a sequence of bytes. The ID is not serialized here as it is only used as
+// a key in the ObjectStorage.
+func (o *ExtendedLockedOutput) ObjectStorageValue() []byte {
+	flags := byte(0)
+	if o.fallbackAddress != nil {
+		flags |= flagExtendedLockedOutputFallbackPresent
+	}
+	if !o.timelock.IsZero() {
+		flags |= flagExtendedLockedOutputTimeLockPresent
+	}
+	if len(o.payload) != 0 {
+		flags |= flagExtendedLockedOutputPayloadPresent
+	}
+
+	marshalUtil := marshalutil.New().
+		WriteByte(byte(ExtendedLockedOutputType)).
+		WriteBytes(o.balances.Bytes()).
+		WriteBytes(o.address.Bytes()).
+		WriteByte(flags)
+	if flags&flagExtendedLockedOutputFallbackPresent != 0 {
+		marshalUtil.WriteBytes(o.fallbackAddress.Bytes()).WriteTime(o.fallbackDeadline)
+	}
+	if flags&flagExtendedLockedOutputTimeLockPresent != 0 {
+		marshalUtil.WriteTime(o.timelock)
+	}
+	if flags&flagExtendedLockedOutputPayloadPresent != 0 {
+		marshalUtil.WriteUint16(uint16(len(o.payload))).WriteBytes(o.payload)
+	}
+
+	return marshalUtil.Bytes()
+}
+
+// Compare offers a comparator for Outputs which returns -1 if the other Output is bigger, 1 if it is smaller and 0 if
+// they are the same.
+func (o *ExtendedLockedOutput) Compare(other Output) int {
+	return bytes.Compare(o.Bytes(), other.Bytes())
+}
+
+// String returns a human readable version of the Output.
+func (o *ExtendedLockedOutput) String() string {
+	return stringify.Struct("ExtendedLockedOutput",
+		stringify.StructField("id", o.ID()),
+		stringify.StructField("address", o.address),
+		stringify.StructField("balances", o.balances),
+		stringify.StructField("fallbackAddress", o.fallbackAddress),
+		stringify.StructField("fallbackDeadline", o.fallbackDeadline),
+		stringify.StructField("timelock", o.timelock),
+		stringify.StructField("payload", o.payload),
+	)
+}
+
+// code contract (make sure the type implements all required methods)
+var _ Output = &ExtendedLockedOutput{}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
 // region CachedOutput /////////////////////////////////////////////////////////////////////////////////////////////////
 
 // CachedOutput is a wrapper for the generic CachedObject returned by the object storage that overrides the accessor
@@ -1,14 +1,50 @@
 package ledgerstate
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
 	"io"
+	"sort"
 )
 
 // Snapshot defines a snapshot of the ledger state.
 type Snapshot map[TransactionID]map[Address]*ColoredBalances
 
+// Outputs returns the Outputs that are described by the snapshot. Like in the legacy loader, the Output indexes are
+// assigned by a counter that runs across all the transactions of the snapshot. The transactions and their addresses
+// are visited in the order of their bytes, so that every node derives the same OutputIDs from the same snapshot.
+func (s Snapshot) Outputs() (outputs Outputs) {
+	transactionIDs := make([]TransactionID, 0, len(s))
+	for transactionID := range s {
+		transactionIDs = append(transactionIDs, transactionID)
+	}
+	sort.Slice(transactionIDs, func(i, j int) bool {
+		return bytes.Compare(transactionIDs[i].Bytes(), transactionIDs[j].Bytes()) < 0
+	})
+
+	outputs = make(Outputs, 0, len(s))
+	index := uint16(0)
+	for _, transactionID := range transactionIDs {
+		addressBalances := s[transactionID]
+		addresses := make([]Address, 0, len(addressBalances))
+		for address := range addressBalances {
+			addresses = append(addresses, address)
+		}
+		sort.Slice(addresses, func(i, j int) bool {
+			return bytes.Compare(addresses[i].Bytes(), addresses[j].Bytes()) < 0
+		})
+
+		for _, address := range addresses {
+			output := NewSigLockedColoredOutput(addressBalances[address], address)
+			outputs = append(outputs, output.SetID(NewOutputID(transactionID, index)))
+			index++
+		}
+	}
+
+	return
+}
+
 // WriteTo writes the snapshot data to the given writer in the following format:
 // 	transaction_count(int64)
 //	-> transaction_count * transaction_id(32byte)
@@ -0,0 +1,48 @@
+package ledgerstate
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot_LegacyOutputIDs(t *testing.T) {
+	legacyFile, err := os.Open("testdata/legacy_snapshot.bin")
+	require.NoError(t, err)
+	defer legacyFile.Close()
+
+	snapshot := Snapshot{}
+	_, err = snapshot.ReadFrom(legacyFile)
+	require.NoError(t, err)
+
+	// the fixture contains two transactions with two addresses each, and the legacy loader assigned the Output indexes
+	// with a counter that runs across all of them
+	expectedBalances := map[OutputID]uint64{}
+	for index, balance := range []uint64{200, 300, 400, 500} {
+		expectedBalances[NewOutputID(TransactionID{byte(1 + index/2)}, uint16(index))] = balance
+	}
+	assertOutputBalances(t, expectedBalances, snapshot.Outputs())
+
+	// the OutputIDs survive writing and reading the snapshot again
+	var buffer bytes.Buffer
+	_, err = snapshot.WriteTo(&buffer)
+	require.NoError(t, err)
+	restoredSnapshot := Snapshot{}
+	_, err = restoredSnapshot.ReadFrom(&buffer)
+	require.NoError(t, err)
+	assertOutputBalances(t, expectedBalances, restoredSnapshot.Outputs())
+}
+
+func assertOutputBalances(t *testing.T, expectedBalances map[OutputID]uint64, outputs Outputs) {
+	require.Len(t, outputs, len(expectedBalances))
+	for _, output := range outputs {
+		expectedBalance, exists := expectedBalances[output.ID()]
+		require.True(t, exists, "unexpected Output with %s", output.ID())
+
+		balance, _ := output.Balances().Get(ColorIOTA)
+		assert.Equal(t, expectedBalance, balance)
+	}
+}
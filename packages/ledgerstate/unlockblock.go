@@ -1,10 +1,13 @@
 package ledgerstate
 
 import (
+	"bytes"
+	"sort"
 	"strconv"
 
 	"github.com/iotaledger/hive.go/byteutils"
 	"github.com/iotaledger/hive.go/cerrors"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
 	"github.com/iotaledger/hive.go/marshalutil"
 	"github.com/iotaledger/hive.go/stringify"
 	"golang.org/x/xerrors"
@@ -18,6 +21,12 @@ const (
 
 	// ReferenceUnlockBlockType represents the type of a ReferenceUnlockBlock.
 	ReferenceUnlockBlockType
+
+	// AliasUnlockBlockType represents the type of an AliasUnlockBlock.
+	AliasUnlockBlockType
+
+	// ThresholdSignatureUnlockBlockType represents the type of a ThresholdSignatureUnlockBlock.
+	ThresholdSignatureUnlockBlockType
 )
 
 // UnlockBlockType represents the type of the UnlockBlock. Different types of UnlockBlocks can unlock different types of
@@ -29,6 +38,8 @@ func (a UnlockBlockType) String() string {
 	return [...]string{
 		"SignatureUnlockBlockType",
 		"ReferenceUnlockBlockType",
+		"AliasUnlockBlockType",
+		"ThresholdSignatureUnlockBlockType",
 	}[a]
 }
 
@@ -81,6 +92,16 @@ func UnlockBlockFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (unlockBlo
 			err = xerrors.Errorf("failed to parse ReferenceUnlockBlock from MarshalUtil: %w", err)
 			return
 		}
+	case AliasUnlockBlockType:
+		if unlockBlock, err = AliasUnlockBlockFromMarshalUtil(marshalUtil); err != nil {
+			err = xerrors.Errorf("failed to parse AliasUnlockBlock from MarshalUtil: %w", err)
+			return
+		}
+	case ThresholdSignatureUnlockBlockType:
+		if unlockBlock, err = ThresholdSignatureUnlockBlockFromMarshalUtil(marshalUtil); err != nil {
+			err = xerrors.Errorf("failed to parse ThresholdSignatureUnlockBlock from MarshalUtil: %w", err)
+			return
+		}
 	default:
 		err = xerrors.Errorf("unsupported UnlockBlockType (%X): %w", unlockBlockType, cerrors.ErrParseBytesFailed)
 		return
@@ -201,6 +222,11 @@ func (s *SignatureUnlockBlock) AddressSignatureValid(address Address, signedData
 	return s.signature.AddressSignatureValid(address, signedData)
 }
 
+// Signature returns the Signature of the UnlockBlock.
+func (s *SignatureUnlockBlock) Signature() Signature {
+	return s.signature
+}
+
 // Type returns the UnlockBlockType of the UnlockBlock.
 func (s *SignatureUnlockBlock) Type() UnlockBlockType {
 	return SignatureUnlockBlockType
@@ -299,3 +325,316 @@ func (r *ReferenceUnlockBlock) String() string {
 var _ UnlockBlock = &ReferenceUnlockBlock{}
 
 // endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region AliasUnlockBlock /////////////////////////////////////////////////////////////////////////////////////////////
+
+// AliasUnlockBlock defines an UnlockBlock which unlocks an Output that is locked to an AliasAddress by referencing the
+// Input that consumes the corresponding AliasOutput (which has to be unlocked by a previous UnlockBlock).
+type AliasUnlockBlock struct {
+	referencedIndex uint16
+}
+
+// NewAliasUnlockBlock is the constructor for AliasUnlockBlocks.
+func NewAliasUnlockBlock(referencedIndex uint16) *AliasUnlockBlock {
+	return &AliasUnlockBlock{
+		referencedIndex: referencedIndex,
+	}
+}
+
+// AliasUnlockBlockFromBytes unmarshals an AliasUnlockBlock from a sequence of bytes.
+func AliasUnlockBlockFromBytes(bytes []byte) (unlockBlock *AliasUnlockBlock, consumedBytes int, err error) {
+	marshalUtil := marshalutil.New(bytes)
+	if unlockBlock, err = AliasUnlockBlockFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse AliasUnlockBlock from MarshalUtil: %w", err)
+		return
+	}
+	consumedBytes = marshalUtil.ReadOffset()
+
+	return
+}
+
+// AliasUnlockBlockFromMarshalUtil unmarshals an AliasUnlockBlock using a MarshalUtil (for easier unmarshaling).
+func AliasUnlockBlockFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (unlockBlock *AliasUnlockBlock, err error) {
+	unlockBlockType, err := marshalUtil.ReadByte()
+	if err != nil {
+		err = xerrors.Errorf("failed to parse UnlockBlockType (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	if UnlockBlockType(unlockBlockType) != AliasUnlockBlockType {
+		err = xerrors.Errorf("invalid UnlockBlockType (%X): %w", unlockBlockType, cerrors.ErrParseBytesFailed)
+		return
+	}
+
+	unlockBlock = &AliasUnlockBlock{}
+	if unlockBlock.referencedIndex, err = marshalUtil.ReadUint16(); err != nil {
+		err = xerrors.Errorf("failed to parse referencedIndex (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	return
+}
+
+// AliasInputIndex returns the index of the Input that consumes the referenced AliasOutput.
+func (a *AliasUnlockBlock) AliasInputIndex() uint16 {
+	return a.referencedIndex
+}
+
+// Type returns the UnlockBlockType of the UnlockBlock.
+func (a *AliasUnlockBlock) Type() UnlockBlockType {
+	return AliasUnlockBlockType
+}
+
+// Bytes returns a marshaled version of the UnlockBlock.
+func (a *AliasUnlockBlock) Bytes() []byte {
+	return marshalutil.New(1 + marshalutil.Uint16Size).
+		WriteByte(byte(AliasUnlockBlockType)).
+		WriteUint16(a.referencedIndex).
+		Bytes()
+}
+
+// String returns a human readable version of the UnlockBlock.
+func (a *AliasUnlockBlock) String() string {
+	return stringify.Struct("AliasUnlockBlock",
+		stringify.StructField("referencedIndex", int(a.referencedIndex)),
+	)
+}
+
+// code contract (make sure the type implements all required methods)
+var _ UnlockBlock = &AliasUnlockBlock{}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region ThresholdSignatureUnlockBlock ////////////////////////////////////////////////////////////////////////////////
+
+// ThresholdSignatureUnlockBlock represents an UnlockBlock that unlocks a ThresholdAddress. It reveals the threshold and
+// the public keys that the ThresholdAddress was derived from and carries the ED25519 signatures of the co-signers.
+type ThresholdSignatureUnlockBlock struct {
+	threshold  uint8
+	publicKeys []ed25519.PublicKey
+	signatures map[uint8]ed25519.Signature
+}
+
+// NewThresholdSignatureUnlockBlock is the constructor of an (unsigned) ThresholdSignatureUnlockBlock for the
+// ThresholdAddress with the given threshold and public keys.
+func NewThresholdSignatureUnlockBlock(threshold uint8, publicKeys ...ed25519.PublicKey) (unlockBlock *ThresholdSignatureUnlockBlock, err error) {
+	sortedPublicKeys, err := sortThresholdPublicKeys(threshold, publicKeys)
+	if err != nil {
+		err = xerrors.Errorf("failed to create ThresholdSignatureUnlockBlock: %w", err)
+		return
+	}
+
+	return &ThresholdSignatureUnlockBlock{
+		threshold:  threshold,
+		publicKeys: sortedPublicKeys,
+		signatures: make(map[uint8]ed25519.Signature),
+	}, nil
+}
+
+// ThresholdSignatureUnlockBlockFromBytes unmarshals a ThresholdSignatureUnlockBlock from a sequence of bytes.
+func ThresholdSignatureUnlockBlockFromBytes(bytes []byte) (unlockBlock *ThresholdSignatureUnlockBlock, consumedBytes int, err error) {
+	marshalUtil := marshalutil.New(bytes)
+	if unlockBlock, err = ThresholdSignatureUnlockBlockFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse ThresholdSignatureUnlockBlock from MarshalUtil: %w", err)
+		return
+	}
+	consumedBytes = marshalUtil.ReadOffset()
+
+	return
+}
+
+// ThresholdSignatureUnlockBlockFromMarshalUtil unmarshals a ThresholdSignatureUnlockBlock using a MarshalUtil (for
+// easier unmarshaling). The UnlockBlock has to contain exactly as many signatures as the threshold requires.
+func ThresholdSignatureUnlockBlockFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (unlockBlock *ThresholdSignatureUnlockBlock, err error) {
+	return thresholdSignatureUnlockBlockFromMarshalUtil(marshalUtil, false)
+}
+
+// PartialThresholdSignatureUnlockBlockFromMarshalUtil unmarshals a ThresholdSignatureUnlockBlock that is still being
+// signed by the co-signers using a MarshalUtil (for easier unmarshaling). The UnlockBlock may contain fewer signatures
+// than the threshold requires.
+func PartialThresholdSignatureUnlockBlockFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (unlockBlock *ThresholdSignatureUnlockBlock, err error) {
+	return thresholdSignatureUnlockBlockFromMarshalUtil(marshalUtil, true)
+}
+
+// thresholdSignatureUnlockBlockFromMarshalUtil is an internal utility function that unmarshals a complete or partial
+// ThresholdSignatureUnlockBlock.
+func thresholdSignatureUnlockBlockFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil, partial bool) (unlockBlock *ThresholdSignatureUnlockBlock, err error) {
+	unlockBlockType, err := marshalUtil.ReadByte()
+	if err != nil {
+		err = xerrors.Errorf("failed to parse UnlockBlockType (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	if UnlockBlockType(unlockBlockType) != ThresholdSignatureUnlockBlockType {
+		err = xerrors.Errorf("invalid UnlockBlockType (%X): %w", unlockBlockType, cerrors.ErrParseBytesFailed)
+		return
+	}
+
+	threshold, err := marshalUtil.ReadUint8()
+	if err != nil {
+		err = xerrors.Errorf("failed to parse threshold (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	publicKeysCount, err := marshalUtil.ReadUint8()
+	if err != nil {
+		err = xerrors.Errorf("failed to parse public keys count (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	publicKeys := make([]ed25519.PublicKey, publicKeysCount)
+	for i := range publicKeys {
+		if publicKeys[i], err = ed25519.ParsePublicKey(marshalUtil); err != nil {
+			err = xerrors.Errorf("failed to parse public key (%v): %w", err, cerrors.ErrParseBytesFailed)
+			return
+		}
+		if i > 0 && bytes.Compare(publicKeys[i-1][:], publicKeys[i][:]) >= 0 {
+			err = xerrors.Errorf("public keys are not sorted or contain duplicates: %w", cerrors.ErrParseBytesFailed)
+			return
+		}
+	}
+	if unlockBlock, err = NewThresholdSignatureUnlockBlock(threshold, publicKeys...); err != nil {
+		return
+	}
+
+	signaturesCount, err := marshalUtil.ReadUint8()
+	if err != nil {
+		err = xerrors.Errorf("failed to parse signatures count (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	var previousPublicKeyIndex uint8
+	if signaturesCount > threshold || (!partial && signaturesCount != threshold) {
+		err = xerrors.Errorf("amount of signatures (%d) does not match the threshold (%d): %w", signaturesCount, threshold, cerrors.ErrParseBytesFailed)
+		return
+	}
+	for i := uint8(0); i < signaturesCount; i++ {
+		publicKeyIndex, publicKeyIndexErr := marshalUtil.ReadUint8()
+		if publicKeyIndexErr != nil {
+			err = xerrors.Errorf("failed to parse public key index (%v): %w", publicKeyIndexErr, cerrors.ErrParseBytesFailed)
+			return
+		}
+		if int(publicKeyIndex) >= len(unlockBlock.publicKeys) {
+			err = xerrors.Errorf("public key index (%d) out of bounds: %w", publicKeyIndex, cerrors.ErrParseBytesFailed)
+			return
+		}
+		if i > 0 && publicKeyIndex <= previousPublicKeyIndex {
+			err = xerrors.Errorf("public key indexes are not sorted or contain duplicates: %w", cerrors.ErrParseBytesFailed)
+			return
+		}
+		previousPublicKeyIndex = publicKeyIndex
+		if unlockBlock.signatures[publicKeyIndex], err = ed25519.ParseSignature(marshalUtil); err != nil {
+			err = xerrors.Errorf("failed to parse signature (%v): %w", err, cerrors.ErrParseBytesFailed)
+			return
+		}
+	}
+
+	return
+}
+
+// AddSignature adds the signature of one of the co-signers to the UnlockBlock. Signatures beyond the threshold are
+// rejected, as a valid UnlockBlock contains exactly as many signatures as the threshold requires.
+func (t *ThresholdSignatureUnlockBlock) AddSignature(publicKey ed25519.PublicKey, signature ed25519.Signature) (err error) {
+	for i, existingPublicKey := range t.publicKeys {
+		if existingPublicKey == publicKey {
+			if _, exists := t.signatures[uint8(i)]; !exists && len(t.signatures) >= int(t.threshold) {
+				return xerrors.Errorf("UnlockBlock already contains the %d required signatures: %w", t.threshold, ErrTransactionInvalid)
+			}
+
+			t.signatures[uint8(i)] = signature
+			return
+		}
+	}
+
+	return xerrors.Errorf("public key %s is not part of the ThresholdAddress: %w", publicKey, ErrTransactionInvalid)
+}
+
+// Threshold returns the amount of signatures that are required to unlock the ThresholdAddress.
+func (t *ThresholdSignatureUnlockBlock) Threshold() uint8 {
+	return t.threshold
+}
+
+// PublicKeys returns the sorted public keys that control the ThresholdAddress.
+func (t *ThresholdSignatureUnlockBlock) PublicKeys() []ed25519.PublicKey {
+	return t.publicKeys
+}
+
+// Signatures returns a copy of the signatures that were added to the UnlockBlock (indexed by the position of the public
+// key of the co-signer in the sorted public keys).
+func (t *ThresholdSignatureUnlockBlock) Signatures() map[uint8]ed25519.Signature {
+	signatures := make(map[uint8]ed25519.Signature, len(t.signatures))
+	for publicKeyIndex, signature := range t.signatures {
+		signatures[publicKeyIndex] = signature
+	}
+
+	return signatures
+}
+
+// SignatureCount returns the amount of signatures that were added to the UnlockBlock.
+func (t *ThresholdSignatureUnlockBlock) SignatureCount() int {
+	return len(t.signatures)
+}
+
+// Address returns the ThresholdAddress that the UnlockBlock is able to unlock.
+func (t *ThresholdSignatureUnlockBlock) Address() *ThresholdAddress {
+	return &ThresholdAddress{
+		digest: thresholdAddressDigest(t.threshold, t.publicKeys),
+	}
+}
+
+// AddressSignatureValid returns true if the UnlockBlock contains exactly as many signatures as the threshold requires
+// and all of them are valid signatures of the given data for the given Address.
+func (t *ThresholdSignatureUnlockBlock) AddressSignatureValid(address Address, signedData []byte) bool {
+	if address.Type() != ThresholdAddressType || !bytes.Equal(address.Digest(), t.Address().Digest()) {
+		return false
+	}
+	if len(t.signatures) != int(t.threshold) {
+		return false
+	}
+
+	for publicKeyIndex, signature := range t.signatures {
+		if !t.publicKeys[publicKeyIndex].VerifySignature(signedData, signature) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Type returns the UnlockBlockType of the UnlockBlock.
+func (t *ThresholdSignatureUnlockBlock) Type() UnlockBlockType {
+	return ThresholdSignatureUnlockBlockType
+}
+
+// Bytes returns a marshaled version of the UnlockBlock.
+func (t *ThresholdSignatureUnlockBlock) Bytes() []byte {
+	marshalUtil := marshalutil.New().
+		WriteByte(byte(ThresholdSignatureUnlockBlockType)).
+		WriteUint8(t.threshold).
+		WriteUint8(uint8(len(t.publicKeys)))
+	for _, publicKey := range t.publicKeys {
+		marshalUtil.WriteBytes(publicKey.Bytes())
+	}
+
+	publicKeyIndexes := make([]int, 0, len(t.signatures))
+	for publicKeyIndex := range t.signatures {
+		publicKeyIndexes = append(publicKeyIndexes, int(publicKeyIndex))
+	}
+	sort.Ints(publicKeyIndexes)
+
+	marshalUtil.WriteUint8(uint8(len(publicKeyIndexes)))
+	for _, publicKeyIndex := range publicKeyIndexes {
+		signature := t.signatures[uint8(publicKeyIndex)]
+		marshalUtil.WriteUint8(uint8(publicKeyIndex)).WriteBytes(signature.Bytes())
+	}
+
+	return marshalUtil.Bytes()
+}
+
+// String returns a human readable version of the UnlockBlock.
+func (t *ThresholdSignatureUnlockBlock) String() string {
+	return stringify.Struct("ThresholdSignatureUnlockBlock",
+		stringify.StructField("threshold", t.threshold),
+		stringify.StructField("publicKeys", t.publicKeys),
+		stringify.StructField("signatureCount", len(t.signatures)),
+	)
+}
+
+// code contract (make sure the type implements all required methods)
+var _ UnlockBlock = &ThresholdSignatureUnlockBlock{}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1,10 +1,13 @@
 package ledgerstate
 
 import (
+	"bytes"
 	"container/list"
 	"fmt"
+	"sort"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/iotaledger/goshimmer/packages/database"
 	"github.com/iotaledger/hive.go/byteutils"
@@ -17,6 +20,7 @@ import (
 	"github.com/iotaledger/hive.go/stringify"
 	"github.com/iotaledger/hive.go/types"
 	"github.com/iotaledger/hive.go/typeutils"
+	"golang.org/x/crypto/blake2b"
 	"golang.org/x/xerrors"
 )
 
@@ -33,6 +37,8 @@ type UTXODAG struct {
 	outputMetadataStorage       *objectstorage.ObjectStorage
 	consumerStorage             *objectstorage.ObjectStorage
 	addressOutputMappingStorage *objectstorage.ObjectStorage
+	colorMetadataStorage        *objectstorage.ObjectStorage
+	addressHistoryStorage       *objectstorage.ObjectStorage
 	branchDAG                   *BranchDAG
 	shutdownOnce                sync.Once
 }
@@ -42,6 +48,7 @@ func NewUTXODAG(store kvstore.KVStore, branchDAG *BranchDAG) (utxoDAG *UTXODAG)
 	osFactory := objectstorage.NewFactory(store, database.PrefixLedgerState)
 	utxoDAG = &UTXODAG{
 		Events: &UTXODAGEvents{
+			TransactionBooked:          events.NewEvent(transactionIDEventHandler),
 			TransactionBranchIDUpdated: events.NewEvent(transactionIDEventHandler),
 		},
 		transactionStorage:          osFactory.New(PrefixTransactionStorage, TransactionFromObjectStorage, transactionStorageOptions...),
@@ -50,8 +57,12 @@ func NewUTXODAG(store kvstore.KVStore, branchDAG *BranchDAG) (utxoDAG *UTXODAG)
 		outputMetadataStorage:       osFactory.New(PrefixOutputMetadataStorage, OutputMetadataFromObjectStorage, outputMetadataStorageOptions...),
 		consumerStorage:             osFactory.New(PrefixConsumerStorage, ConsumerFromObjectStorage, consumerStorageOptions...),
 		addressOutputMappingStorage: osFactory.New(PrefixAddressOutputMappingStorage, AddressOutputMappingFromObjectStorage, addressOutputMappingStorageOptions...),
+		colorMetadataStorage:        osFactory.New(PrefixColorMetadataStorage, ColorMetadataFromObjectStorage, colorMetadataStorageOptions...),
+		addressHistoryStorage:       osFactory.New(PrefixAddressHistoryStorage, AddressHistoryEntryFromObjectStorage, addressHistoryStorageOptions...),
 		branchDAG:                   branchDAG,
 	}
+	branchDAG.Events.BranchRejected.Attach(events.NewClosure(utxoDAG.revertColorSupplyOfRejectedBranch))
+
 	return
 }
 
@@ -64,6 +75,8 @@ func (u *UTXODAG) Shutdown() {
 		u.outputMetadataStorage.Shutdown()
 		u.consumerStorage.Shutdown()
 		u.addressOutputMappingStorage.Shutdown()
+		u.colorMetadataStorage.Shutdown()
+		u.addressHistoryStorage.Shutdown()
 	})
 }
 
@@ -86,6 +99,10 @@ func (u *UTXODAG) CheckTransaction(transaction *Transaction) (valid bool, err er
 		err = xerrors.Errorf("spending of referenced consumedOutputs is not authorized: %w", ErrTransactionInvalid)
 		return
 	}
+	if !u.aliasOutputsValid(consumedOutputs, transaction.Essence().Outputs()) {
+		err = xerrors.Errorf("transaction continues aliases that it does not consume: %w", ErrTransactionInvalid)
+		return
+	}
 
 	valid = true
 	return
@@ -119,6 +136,13 @@ func (u *UTXODAG) BookTransaction(transaction *Transaction) (targetBranch Branch
 	}
 	defer cachedTransactionMetadata.Release()
 
+	// trigger the TransactionBooked event once the Transaction was booked without errors
+	defer func() {
+		if err == nil {
+			u.Events.TransactionBooked.Trigger(transaction.ID())
+		}
+	}()
+
 	// store Transaction
 	u.transactionStorage.Store(transaction).Release()
 
@@ -178,6 +202,14 @@ func (u *UTXODAG) BookTransaction(transaction *Transaction) (targetBranch Branch
 		targetBranch = u.bookConflictingTransaction(transaction, transactionMetadata, inputsMetadata, normalizedBranchIDs, conflictingInputs.ByID())
 	}
 
+	// index the Transaction in the history of the Addresses that it credits or debits (invalid and rejected
+	// Transactions are not part of the history)
+	for _, addressHistoryEntry := range addressHistoryEntries(transaction, consumedOutputs) {
+		u.storeAddressHistoryEntry(addressHistoryEntry)
+	}
+
+	u.bookColorSupply(transaction, consumedOutputs)
+
 	return
 }
 
@@ -248,38 +280,63 @@ func (u *UTXODAG) Consumers(outputID OutputID) (cachedConsumers CachedConsumers)
 
 // LoadSnapshot creates a set of outputs in the UTXO-DAG, that are forming the genesis for future transactions.
 func (u *UTXODAG) LoadSnapshot(snapshot map[TransactionID]map[Address]*ColoredBalances) {
-	index := uint16(0)
-	fmt.Println("Loading snapshot...")
-	for transactionID, addressBalance := range snapshot {
-		fmt.Println("TransactionID: ", transactionID.Base58())
-		for address, balance := range addressBalance {
-			fmt.Println("Address: ", address)
-			fmt.Println("Address Base58: ", address.Base58())
-			fmt.Println("Balance: ", balance)
-			output := NewSigLockedColoredOutput(balance, address)
-			output.SetID(NewOutputID(transactionID, index))
-			cachedOutput, stored := u.outputStorage.StoreIfAbsent(output)
-			if stored {
-				cachedOutput.Release()
-			}
+	u.LoadOutputs(Snapshot(snapshot).Outputs())
+}
 
-			//store addressOutputMapping
-			u.StoreAddressOutputMapping(address, output.ID())
-
-			// store OutputMetadata
-			metadata := NewOutputMetadata(output.ID())
-			metadata.SetBranchID(MasterBranchID)
-			metadata.SetSolid(true)
-			metadata.SetFinalized(true)
-			cachedMetadata, stored := u.outputMetadataStorage.StoreIfAbsent(metadata)
-			if stored {
-				cachedMetadata.Release()
-			}
+// LoadOutputs stores the given Outputs (that need to have their ID set) as confirmed and unspent Outputs in the
+// MasterBranch of the UTXO-DAG. It is used to restore the ledger state from a local snapshot. The colored tokens of the
+// Outputs are added to the supply of their Colors and the Outputs are added to the history of their Addresses.
+func (u *UTXODAG) LoadOutputs(outputs Outputs) {
+	transactionIDs := make(map[TransactionID]types.Empty)
+	receivedBalances := make(map[string]map[Color]uint64)
+	receivingOutputs := make(map[string]Output)
+	for _, output := range outputs {
+		cachedOutput, stored := u.outputStorage.StoreIfAbsent(output)
+		if stored {
+			cachedOutput.Release()
+
+			output.Balances().ForEach(func(color Color, balance uint64) bool {
+				if color != ColorIOTA {
+					u.colorMetadata(color, GenesisTransactionID).Consume(func(colorMetadata *ColorMetadata) {
+						colorMetadata.IncreaseMintedSupply(balance)
+					})
+				}
+
+				return true
+			})
+		}
+
+		u.StoreAddressOutputMapping(output.Address(), output.ID())
+
+		historyKey := string(byteutils.ConcatBytes(output.Address().Bytes(), output.ID().TransactionID().Bytes()))
+		if _, exists := receivingOutputs[historyKey]; !exists {
+			receivingOutputs[historyKey] = output
+			receivedBalances[historyKey] = make(map[Color]uint64)
+		}
+		output.Balances().ForEach(func(color Color, balance uint64) bool {
+			receivedBalances[historyKey][color] += balance
+
+			return true
+		})
 
-			index++
+		metadata := NewOutputMetadata(output.ID())
+		metadata.SetBranchID(MasterBranchID)
+		metadata.SetSolid(true)
+		metadata.SetFinalized(true)
+		cachedMetadata, stored := u.outputMetadataStorage.StoreIfAbsent(metadata)
+		if stored {
+			cachedMetadata.Release()
 		}
 
-		// store TransactionMetadata
+		transactionIDs[output.ID().TransactionID()] = types.Void
+	}
+
+	// snapshotted Transactions are not available, so their history starts at the beginning of the unix epoch
+	for historyKey, output := range receivingOutputs {
+		u.storeAddressHistoryEntry(NewAddressHistoryEntry(output.Address(), output.ID().TransactionID(), time.Unix(0, 0), NewColoredBalances(receivedBalances[historyKey]), NewColoredBalances(nil)))
+	}
+
+	for transactionID := range transactionIDs {
 		transactionMetadata := NewTransactionMetadata(transactionID)
 		transactionMetadata.SetSolid(true)
 		transactionMetadata.SetBranchID(MasterBranchID)
@@ -293,6 +350,126 @@ func (u *UTXODAG) LoadSnapshot(snapshot map[TransactionID]map[Address]*ColoredBa
 	}
 }
 
+// ForEachConfirmedUnspentOutput iterates over all Outputs that are part of the confirmed ledger state and that have not
+// been spent by a confirmed Transaction, yet. The iteration stops if the consumer returns false.
+func (u *UTXODAG) ForEachConfirmedUnspentOutput(consumer func(output Output, outputMetadata *OutputMetadata) bool) {
+	u.outputMetadataStorage.ForEach(func(key []byte, cachedObject objectstorage.CachedObject) bool {
+		continueIteration := true
+		(&CachedOutputMetadata{CachedObject: cachedObject}).Consume(func(outputMetadata *OutputMetadata) {
+			if !u.outputConfirmed(outputMetadata) || u.outputSpentByConfirmedTransaction(outputMetadata) {
+				return
+			}
+
+			u.Output(outputMetadata.ID()).Consume(func(output Output) {
+				continueIteration = consumer(output, outputMetadata)
+			})
+		})
+
+		return continueIteration
+	})
+}
+
+// outputConfirmed is an internal utility function that checks if the Output belonging to the given OutputMetadata is
+// finalized and booked into a confirmed Branch.
+func (u *UTXODAG) outputConfirmed(outputMetadata *OutputMetadata) (confirmed bool) {
+	if !outputMetadata.Finalized() {
+		return false
+	}
+
+	u.branchDAG.Branch(outputMetadata.BranchID()).Consume(func(branch Branch) {
+		confirmed = branch.InclusionState() == Confirmed
+	})
+
+	return
+}
+
+// outputSpentByConfirmedTransaction is an internal utility function that checks if the Output belonging to the given
+// OutputMetadata was spent by a confirmed Transaction.
+func (u *UTXODAG) outputSpentByConfirmedTransaction(outputMetadata *OutputMetadata) (spent bool) {
+	if outputMetadata.ConsumerCount() == 0 {
+		return false
+	}
+
+	u.Consumers(outputMetadata.ID()).Consume(func(consumer *Consumer) {
+		if spent {
+			return
+		}
+
+		inclusionState, err := u.InclusionState(consumer.TransactionID())
+		spent = err == nil && inclusionState == Confirmed
+	})
+
+	return
+}
+
+// ForEachTransaction iterates over all the Transactions that were booked into the UTXODAG. The iteration stops if the
+// consumer returns false.
+func (u *UTXODAG) ForEachTransaction(consumer func(transaction *Transaction) bool) {
+	u.transactionStorage.ForEach(func(key []byte, cachedObject objectstorage.CachedObject) bool {
+		continueIteration := true
+		(&CachedTransaction{CachedObject: cachedObject}).Consume(func(transaction *Transaction) {
+			continueIteration = consumer(transaction)
+		})
+
+		return continueIteration
+	})
+}
+
+// ColorMetadata retrieves the ColorMetadata with the supply information of the given Color from the object storage.
+func (u *UTXODAG) ColorMetadata(color Color) (cachedColorMetadata *CachedColorMetadata) {
+	return &CachedColorMetadata{CachedObject: u.colorMetadataStorage.Load(color.Bytes())}
+}
+
+// ForEachColorMetadata iterates over the ColorMetadata of all known Colors. The iteration stops if the consumer returns
+// false.
+func (u *UTXODAG) ForEachColorMetadata(consumer func(colorMetadata *ColorMetadata) bool) {
+	u.colorMetadataStorage.ForEach(func(key []byte, cachedObject objectstorage.CachedObject) bool {
+		continueIteration := true
+		(&CachedColorMetadata{CachedObject: cachedObject}).Consume(func(colorMetadata *ColorMetadata) {
+			continueIteration = consumer(colorMetadata)
+		})
+
+		return continueIteration
+	})
+}
+
+// AddressHistory retrieves the AddressHistoryEntries of all Transactions that credited or debited the given Address.
+func (u *UTXODAG) AddressHistory(address Address) (cachedAddressHistoryEntries CachedAddressHistoryEntries) {
+	u.addressHistoryStorage.ForEach(func(key []byte, cachedObject objectstorage.CachedObject) bool {
+		cachedAddressHistoryEntries = append(cachedAddressHistoryEntries, &CachedAddressHistoryEntry{cachedObject})
+		return true
+	}, address.Bytes())
+	return
+}
+
+// ForEachAddressHistoryEntry iterates over the AddressHistoryEntries of all Transactions that credited or debited the
+// given Address in the order of their timestamps. If a cursor is given, the iteration starts after the entry that it
+// points to without loading the preceding entries. Only the keys of the entries are held in memory and the iteration
+// stops if the consumer returns false.
+func (u *UTXODAG) ForEachAddressHistoryEntry(address Address, consumer func(addressHistoryEntry *AddressHistoryEntry) bool, optionalCursor ...AddressHistoryCursor) {
+	keys := make([][]byte, 0)
+	u.addressHistoryStorage.ForEachKeyOnly(func(key []byte) bool {
+		if len(optionalCursor) == 0 || bytes.Compare(key[AddressLength:], optionalCursor[0].Bytes()) > 0 {
+			keys = append(keys, key)
+		}
+
+		return true
+	}, false, address.Bytes())
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) < 0
+	})
+
+	for _, key := range keys {
+		continueIteration := true
+		(&CachedAddressHistoryEntry{CachedObject: u.addressHistoryStorage.Load(key)}).Consume(func(addressHistoryEntry *AddressHistoryEntry) {
+			continueIteration = consumer(addressHistoryEntry)
+		})
+		if !continueIteration {
+			return
+		}
+	}
+}
+
 // AddressOutputMapping retrieves the outputs for the given address.
 func (u *UTXODAG) AddressOutputMapping(address Address) (cachedAddressOutputMappings CachedAddressOutputMappings) {
 	u.addressOutputMappingStorage.ForEach(func(key []byte, cachedObject objectstorage.CachedObject) bool {
@@ -514,8 +691,13 @@ func (u *UTXODAG) bookConsumers(inputsMetadata OutputsMetadata, transactionID Tr
 func (u *UTXODAG) bookOutputs(transaction *Transaction, targetBranch BranchID) {
 	for _, output := range transaction.Essence().Outputs() {
 		// replace ColorMint color with unique color based on OutputID
-		if output.Type() == SigLockedColoredOutputType {
+		switch output.Type() {
+		case SigLockedColoredOutputType:
 			output = output.(*SigLockedColoredOutput).UpdateMintingColor()
+		case AliasOutputType:
+			output = output.(*AliasOutput).UpdateMintingColor()
+		case ExtendedLockedOutputType:
+			output = output.(*ExtendedLockedOutput).UpdateMintingColor()
 		}
 
 		// store Output
@@ -529,6 +711,117 @@ func (u *UTXODAG) bookOutputs(transaction *Transaction, targetBranch BranchID) {
 	}
 }
 
+// bookColorSupply updates the supply information of the Colors that are minted or burned by the given Transaction.
+// Tokens are minted by Outputs that contain ColorMint and burned by converting colored tokens back to ColorIOTA.
+func (u *UTXODAG) bookColorSupply(transaction *Transaction, consumedOutputs Outputs) {
+	u.updateColorSupply(transaction, consumedOutputs, false)
+}
+
+// revertColorSupplyOfRejectedBranch reverts the supply changes of all the Transactions that are booked into the given
+// rejected Branch. The Transactions are searched in the future cone of the ConflictBranches that the Branch consists
+// of. As every Transaction is booked into exactly one Branch and every Branch is rejected only once, the supply of a
+// Transaction is reverted at most once.
+func (u *UTXODAG) revertColorSupplyOfRejectedBranch(branchDAGEvent *BranchDAGEvent) {
+	defer branchDAGEvent.Release()
+
+	branch := branchDAGEvent.Branch.Unwrap()
+	if branch == nil {
+		return
+	}
+
+	conflictBranchIDs := NewBranchIDs(branch.ID())
+	if branch.Type() == AggregatedBranchType {
+		conflictBranchIDs = branch.Parents()
+	}
+
+	revertIfRejected := func(transactionID TransactionID) (createdOutputs []OutputID) {
+		u.TransactionMetadata(transactionID).Consume(func(transactionMetadata *TransactionMetadata) {
+			if transactionMetadata.BranchID() != branch.ID() {
+				return
+			}
+
+			u.Transaction(transactionID).Consume(func(transaction *Transaction) {
+				cachedConsumedOutputs := u.consumedOutputs(transaction)
+				defer cachedConsumedOutputs.Release()
+
+				u.updateColorSupply(transaction, cachedConsumedOutputs.Unwrap(), true)
+			})
+		})
+
+		return u.createdOutputIDsOfTransaction(transactionID)
+	}
+
+	for conflictBranchID := range conflictBranchIDs {
+		conflictTransactionID := TransactionID(conflictBranchID)
+		u.walkFutureCone(revertIfRejected(conflictTransactionID), revertIfRejected)
+	}
+}
+
+// updateColorSupply is an internal utility function that adds (or removes if revert is true) the supply changes of the
+// given Transaction to (or from) the ColorMetadata of the affected Colors.
+func (u *UTXODAG) updateColorSupply(transaction *Transaction, consumedOutputs Outputs, revert bool) {
+	burnedCoins := make(map[Color]uint64)
+	for _, consumedOutput := range consumedOutputs {
+		if consumedOutput == nil {
+			continue
+		}
+
+		consumedOutput.Balances().ForEach(func(color Color, balance uint64) bool {
+			if color != ColorIOTA {
+				burnedCoins[color] += balance
+			}
+
+			return true
+		})
+	}
+
+	for _, output := range transaction.Essence().Outputs() {
+		output.Balances().ForEach(func(color Color, balance uint64) bool {
+			switch color {
+			case ColorIOTA:
+			case ColorMint:
+				u.colorMetadata(blake2b.Sum256(output.ID().Bytes()), transaction.ID()).Consume(func(colorMetadata *ColorMetadata) {
+					if revert {
+						colorMetadata.DecreaseMintedSupply(balance)
+						return
+					}
+					colorMetadata.IncreaseMintedSupply(balance)
+				})
+			default:
+				burnedCoins[color] -= balance
+			}
+
+			return true
+		})
+	}
+
+	for color, amount := range burnedCoins {
+		if amount == 0 {
+			continue
+		}
+
+		u.colorMetadata(color, GenesisTransactionID).Consume(func(colorMetadata *ColorMetadata) {
+			if revert {
+				colorMetadata.DecreaseBurnedSupply(amount)
+				return
+			}
+			colorMetadata.IncreaseBurnedSupply(amount)
+		})
+	}
+}
+
+// colorMetadata is an internal utility function that retrieves the ColorMetadata of the given Color and creates it
+// with the given minting Transaction if it does not exist, yet.
+func (u *UTXODAG) colorMetadata(color Color, mintingTransactionID TransactionID) *CachedColorMetadata {
+	return &CachedColorMetadata{CachedObject: u.colorMetadataStorage.ComputeIfAbsent(color.Bytes(), func(key []byte) objectstorage.StorableObject {
+		colorMetadata := NewColorMetadata(color, mintingTransactionID)
+		colorMetadata.Persist()
+		colorMetadata.SetModified()
+
+		return colorMetadata
+	})}
+}
+
 // determineBookingDetails is an internal utility function that determines the information that are required to fully
 // book a newly arrived Transaction into the UTXODAG using the metadata of its referenced Inputs.
 func (u *UTXODAG) determineBookingDetails(inputsMetadata OutputsMetadata) (branchesOfInputsConflicting bool, normalizedBranchIDs BranchIDs, conflictingInputs OutputsMetadata, err error) {
@@ -628,10 +921,27 @@ func (u *UTXODAG) transactionBalancesValid(inputs Outputs, outputs Outputs) (val
 }
 
 // unlockBlocksValid is an internal utility function that checks if the UnlockBlocks are matching the referenced Inputs.
+// ReferenceUnlockBlocks are resolved to the (threshold) signature UnlockBlock that they reference and chains of
+// AliasUnlockBlocks have to end in a different kind of UnlockBlock (to prevent aliases from unlocking each other).
 func (u *UTXODAG) unlockBlocksValid(inputs Outputs, transaction *Transaction) (valid bool) {
 	unlockBlocks := transaction.UnlockBlocks()
 	for i, input := range inputs {
-		unlockValid, unlockErr := input.UnlockValid(transaction, unlockBlocks[i])
+		unlockBlock := unlockBlocks[i]
+		switch typedUnlockBlock := unlockBlock.(type) {
+		case *ReferenceUnlockBlock:
+			if int(typedUnlockBlock.ReferencedIndex()) >= len(unlockBlocks) {
+				return false
+			}
+			if unlockBlock = unlockBlocks[typedUnlockBlock.ReferencedIndex()]; unlockBlock.Type() != SignatureUnlockBlockType && unlockBlock.Type() != ThresholdSignatureUnlockBlockType {
+				return false
+			}
+		case *AliasUnlockBlock:
+			if !aliasUnlockBlocksAcyclic(unlockBlocks, i) {
+				return false
+			}
+		}
+
+		unlockValid, unlockErr := input.UnlockValid(transaction, unlockBlock, inputs)
 		if !unlockValid || unlockErr != nil {
 			return false
 		}
@@ -640,6 +950,51 @@ func (u *UTXODAG) unlockBlocksValid(inputs Outputs, transaction *Transaction) (v
 	return true
 }
 
+// aliasUnlockBlocksAcyclic is an internal utility function that follows the references of the AliasUnlockBlock at the
+// given index and returns true if they end in an UnlockBlock that is not an AliasUnlockBlock.
+func aliasUnlockBlocksAcyclic(unlockBlocks UnlockBlocks, index int) bool {
+	visitedIndexes := make(map[int]types.Empty)
+	for {
+		aliasUnlockBlock, isAliasUnlockBlock := unlockBlocks[index].(*AliasUnlockBlock)
+		if !isAliasUnlockBlock {
+			return true
+		}
+
+		if _, visited := visitedIndexes[index]; visited {
+			return false
+		}
+		visitedIndexes[index] = types.Void
+
+		if index = int(aliasUnlockBlock.AliasInputIndex()); index >= len(unlockBlocks) {
+			return false
+		}
+	}
+}
+
+// aliasOutputsValid is an internal utility function that checks if all the AliasOutputs that continue an existing
+// alias are chained to an AliasOutput that is consumed by the Transaction.
+func (u *UTXODAG) aliasOutputsValid(inputs Outputs, outputs Outputs) (valid bool) {
+	consumedAliases := make(map[[AddressLength]byte]types.Empty)
+	for _, input := range inputs {
+		if aliasInput, isAliasOutput := input.(*AliasOutput); isAliasOutput {
+			consumedAliases[aliasInput.AliasAddress().Array()] = types.Void
+		}
+	}
+
+	for _, output := range outputs {
+		aliasOutput, isAliasOutput := output.(*AliasOutput)
+		if !isAliasOutput || aliasOutput.IsOrigin() {
+			continue
+		}
+
+		if _, consumed := consumedAliases[aliasOutput.AliasAddress().Array()]; !consumed {
+			return false
+		}
+	}
+
+	return true
+}
+
 // transactionInputsMetadata is an internal utility function that returns the Metadata of the Outputs that are used as
 // Inputs by the given Transaction.
 func (u *UTXODAG) transactionInputsMetadata(transaction *Transaction) (cachedInputsMetadata CachedOutputsMetadata) {
@@ -851,6 +1206,13 @@ func (u *UTXODAG) consumedBranchIDs(transactionID TransactionID) (branchIDs Bran
 	return
 }
 
+// storeAddressHistoryEntry stores the given AddressHistoryEntry if it does not exist, yet.
+func (u *UTXODAG) storeAddressHistoryEntry(addressHistoryEntry *AddressHistoryEntry) {
+	if cachedAddressHistoryEntry, stored := u.addressHistoryStorage.StoreIfAbsent(addressHistoryEntry); stored {
+		cachedAddressHistoryEntry.Release()
+	}
+}
+
 // StoreAddressOutputMapping stores the address-output mapping.
 func (u *UTXODAG) StoreAddressOutputMapping(address Address, outputID OutputID) {
 	result, stored := u.addressOutputMappingStorage.StoreIfAbsent(NewAddressOutputMapping(address, outputID))
@@ -882,6 +1244,9 @@ func (u *UTXODAG) lockTransaction(transaction *Transaction) {
 
 // UTXODAGEvents is a container for all of the UTXODAG related events.
 type UTXODAGEvents struct {
+	// TransactionBooked gets triggered whenever a new Transaction gets booked into the UTXODAG.
+	TransactionBooked *events.Event
+
 	// TransactionBranchIDUpdated gets triggered when the BranchID of a Transaction is changed after the initial booking.
 	TransactionBranchIDUpdated *events.Event
 }
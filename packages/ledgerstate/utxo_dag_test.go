@@ -8,10 +8,13 @@ import (
 	"github.com/iotaledger/hive.go/crypto/ed25519"
 	"github.com/iotaledger/hive.go/identity"
 	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/hive.go/marshalutil"
 	"github.com/iotaledger/hive.go/objectstorage"
 	"github.com/iotaledger/hive.go/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/xerrors"
 )
 
 var (
@@ -760,6 +763,390 @@ func TestUnlockBlocksValid(t *testing.T) {
 
 }
 
+func TestAliasOutput(t *testing.T) {
+	branchDAG, utxoDAG := setupDependencies(t)
+	defer branchDAG.Shutdown()
+
+	wallets := createWallets(4)
+	stateController, governor := wallets[1], wallets[2]
+
+	// buildAliasTransaction creates a Transaction whose UnlockBlocks are created by the given function (that receives
+	// the positions of the consumed Outputs in the sorted Inputs)
+	buildAliasTransaction := func(inputs []Output, outputs []Output, unlockBlocks func(essence *TransactionEssence, inputIndexes map[OutputID]uint16) UnlockBlocks) *Transaction {
+		utxoInputs := make([]Input, len(inputs))
+		for i, input := range inputs {
+			utxoInputs[i] = input.Input()
+		}
+		essence := NewTransactionEssence(0, time.Now(), identity.ID{}, identity.ID{}, NewInputs(utxoInputs...), NewOutputs(outputs...))
+
+		inputIndexes := make(map[OutputID]uint16)
+		for i, input := range essence.Inputs() {
+			inputIndexes[input.(*UTXOInput).ReferencedOutputID()] = uint16(i)
+		}
+
+		return NewTransaction(essence, unlockBlocks(essence, inputIndexes))
+	}
+	signedBy := func(signer wallet) func(essence *TransactionEssence, _ map[OutputID]uint16) UnlockBlocks {
+		return func(essence *TransactionEssence, _ map[OutputID]uint16) UnlockBlocks {
+			return signer.unlockBlocks(essence)
+		}
+	}
+	storedAliasOutput := func(transaction *Transaction) (aliasOutput *AliasOutput) {
+		for _, output := range transaction.Essence().Outputs() {
+			if output.Type() == AliasOutputType {
+				utxoDAG.Output(output.ID()).Consume(func(output Output) {
+					aliasOutput = output.(*AliasOutput)
+				})
+			}
+		}
+		return
+	}
+
+	// mint a new alias
+	mintOutput, err := NewAliasOutputMint(NewColoredBalances(map[Color]uint64{ColorIOTA: 100}), stateController.address, governor.address, []byte("immutable"))
+	require.NoError(t, err)
+	mintTransaction := buildAliasTransaction([]Output{generateOutput(utxoDAG, wallets[0].address, 1)}, []Output{mintOutput}, signedBy(wallets[0]))
+	valid, err := utxoDAG.CheckTransaction(mintTransaction)
+	require.NoError(t, err)
+	require.True(t, valid)
+	_, err = utxoDAG.BookTransaction(mintTransaction)
+	require.NoError(t, err)
+
+	aliasOutput := storedAliasOutput(mintTransaction)
+	require.NotNil(t, aliasOutput)
+	assert.True(t, aliasOutput.IsOrigin())
+	assert.True(t, NewAliasAddress(aliasOutput.ID().Bytes()).Equals(aliasOutput.AliasAddress()))
+
+	t.Run("StateTransition", func(t *testing.T) {
+		nextOutput := aliasOutput.NewAliasOutputNext(false)
+		require.NoError(t, nextOutput.SetStateData([]byte("state")))
+		assert.Equal(t, uint32(1), nextOutput.StateIndex())
+
+		assert.True(t, utxoDAG.unlockBlocksValid(Outputs{aliasOutput}, buildAliasTransaction([]Output{aliasOutput}, []Output{nextOutput}, signedBy(stateController))))
+		assert.False(t, utxoDAG.unlockBlocksValid(Outputs{aliasOutput}, buildAliasTransaction([]Output{aliasOutput}, []Output{nextOutput}, signedBy(governor))))
+
+		// the state controller is not allowed to modify the controlling addresses
+		nextOutput.SetGoverningAddress(stateController.address)
+		assert.False(t, utxoDAG.unlockBlocksValid(Outputs{aliasOutput}, buildAliasTransaction([]Output{aliasOutput}, []Output{nextOutput}, signedBy(stateController))))
+	})
+
+	t.Run("GovernanceTransition", func(t *testing.T) {
+		nextOutput := aliasOutput.NewAliasOutputNext(true)
+		nextOutput.SetStateAddress(wallets[3].address)
+
+		assert.True(t, utxoDAG.unlockBlocksValid(Outputs{aliasOutput}, buildAliasTransaction([]Output{aliasOutput}, []Output{nextOutput}, signedBy(governor))))
+		assert.False(t, utxoDAG.unlockBlocksValid(Outputs{aliasOutput}, buildAliasTransaction([]Output{aliasOutput}, []Output{nextOutput}, signedBy(stateController))))
+
+		// the governor is not allowed to modify the state
+		require.NoError(t, nextOutput.SetStateData([]byte("state")))
+		assert.False(t, utxoDAG.unlockBlocksValid(Outputs{aliasOutput}, buildAliasTransaction([]Output{aliasOutput}, []Output{nextOutput}, signedBy(governor))))
+	})
+
+	t.Run("Destroy", func(t *testing.T) {
+		output := NewSigLockedSingleOutput(100, governor.address)
+
+		assert.True(t, utxoDAG.unlockBlocksValid(Outputs{aliasOutput}, buildAliasTransaction([]Output{aliasOutput}, []Output{output}, signedBy(governor))))
+		assert.False(t, utxoDAG.unlockBlocksValid(Outputs{aliasOutput}, buildAliasTransaction([]Output{aliasOutput}, []Output{output}, signedBy(stateController))))
+	})
+
+	t.Run("AliasUnlockBlock", func(t *testing.T) {
+		lockedOutput := generateOutput(utxoDAG, aliasOutput.AliasAddress(), 2)
+		nextOutput := aliasOutput.NewAliasOutputNext(false)
+		nextOutput.SetBalances(NewColoredBalances(map[Color]uint64{ColorIOTA: 200}))
+
+		transaction := buildAliasTransaction([]Output{aliasOutput, lockedOutput}, []Output{nextOutput}, func(essence *TransactionEssence, inputIndexes map[OutputID]uint16) UnlockBlocks {
+			unlockBlocks := make(UnlockBlocks, 2)
+			unlockBlocks[inputIndexes[aliasOutput.ID()]] = NewSignatureUnlockBlock(stateController.sign(essence))
+			unlockBlocks[inputIndexes[lockedOutput.ID()]] = NewAliasUnlockBlock(inputIndexes[aliasOutput.ID()])
+			return unlockBlocks
+		})
+		valid, err := utxoDAG.CheckTransaction(transaction)
+		require.NoError(t, err)
+		assert.True(t, valid)
+
+		// AliasUnlockBlocks that reference each other can not unlock anything
+		signatureUnlockBlock := NewSignatureUnlockBlock(stateController.sign(transaction.Essence()))
+		assert.True(t, aliasUnlockBlocksAcyclic(UnlockBlocks{NewAliasUnlockBlock(1), NewAliasUnlockBlock(2), signatureUnlockBlock}, 0))
+		assert.False(t, aliasUnlockBlocksAcyclic(UnlockBlocks{NewAliasUnlockBlock(1), NewAliasUnlockBlock(0), signatureUnlockBlock}, 0))
+		assert.False(t, aliasUnlockBlocksAcyclic(UnlockBlocks{NewAliasUnlockBlock(5)}, 0))
+	})
+
+	t.Run("ForgedAlias", func(t *testing.T) {
+		// a Transaction can not continue an alias that it does not consume
+		forgedOutput := aliasOutput.NewAliasOutputNext(false)
+		forgedOutput.SetBalances(NewColoredBalances(map[Color]uint64{ColorIOTA: 100}))
+		transaction := buildAliasTransaction([]Output{generateOutput(utxoDAG, stateController.address, 3)}, []Output{forgedOutput}, signedBy(stateController))
+
+		_, err := utxoDAG.CheckTransaction(transaction)
+		assert.True(t, xerrors.Is(err, ErrTransactionInvalid))
+	})
+}
+
+func TestAliasOutput_Bytes(t *testing.T) {
+	wallets := createWallets(2)
+	aliasOutput, err := NewAliasOutputMint(NewColoredBalances(map[Color]uint64{ColorIOTA: 100, color1: 5}), wallets[0].address, wallets[1].address, []byte("immutable"))
+	require.NoError(t, err)
+	aliasOutput.SetID(NewOutputID(GenesisTransactionID, 3))
+	nextOutput := aliasOutput.NewAliasOutputNext(false)
+	require.NoError(t, nextOutput.SetStateData([]byte("state")))
+
+	restoredOutput, _, err := OutputFromBytes(nextOutput.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, nextOutput.Bytes(), restoredOutput.Bytes())
+	assert.True(t, aliasOutput.AliasAddress().Equals(restoredOutput.(*AliasOutput).AliasAddress()))
+	assert.Equal(t, uint32(1), restoredOutput.(*AliasOutput).StateIndex())
+	assert.Equal(t, []byte("state"), restoredOutput.(*AliasOutput).StateData())
+	assert.Equal(t, []byte("immutable"), restoredOutput.(*AliasOutput).ImmutableData())
+
+	assert.Error(t, nextOutput.SetStateData(make([]byte, MaxAliasOutputDataSize+1)))
+}
+
+func TestExtendedLockedOutput(t *testing.T) {
+	branchDAG, utxoDAG := setupDependencies(t)
+	defer branchDAG.Shutdown()
+
+	wallets := createWallets(2)
+	owner, fallback := wallets[0], wallets[1]
+	now := time.Now()
+
+	lockedOutput := NewExtendedLockedOutput(NewColoredBalances(map[Color]uint64{ColorIOTA: 100}), owner.address).
+		WithTimeLock(now.Add(time.Hour)).
+		WithFallbackOptions(fallback.address, now.Add(2*time.Hour))
+	require.NoError(t, lockedOutput.SetPayload([]byte("vesting")))
+	lockedOutput.SetID(NewOutputID(GenesisTransactionID, 1))
+	utxoDAG.outputStorage.Store(lockedOutput).Release()
+	metadata := NewOutputMetadata(lockedOutput.ID())
+	metadata.SetBranchID(MasterBranchID)
+	metadata.SetSolid(true)
+	utxoDAG.outputMetadataStorage.Store(metadata).Release()
+
+	checkTransaction := func(signer wallet, timestamp time.Time) (err error) {
+		essence := NewTransactionEssence(0, timestamp, identity.ID{}, identity.ID{}, NewInputs(lockedOutput.Input()), NewOutputs(NewSigLockedSingleOutput(100, signer.address)))
+		_, err = utxoDAG.CheckTransaction(NewTransaction(essence, signer.unlockBlocks(essence)))
+		return
+	}
+
+	// nobody can spend the Output before the timelock expired
+	assert.True(t, xerrors.Is(checkTransaction(owner, now.Add(30*time.Minute)), ErrTransactionInvalid))
+
+	// only the owner can spend the Output before the fallback deadline
+	assert.NoError(t, checkTransaction(owner, now.Add(90*time.Minute)))
+	assert.True(t, xerrors.Is(checkTransaction(fallback, now.Add(90*time.Minute)), ErrTransactionInvalid))
+
+	// only the fallback address can spend the Output after the fallback deadline
+	assert.NoError(t, checkTransaction(fallback, now.Add(3*time.Hour)))
+	assert.True(t, xerrors.Is(checkTransaction(owner, now.Add(3*time.Hour)), ErrTransactionInvalid))
+}
+
+func TestExtendedLockedOutput_Bytes(t *testing.T) {
+	wallets := createWallets(2)
+	deadline := time.Unix(1600000000, 0)
+
+	for _, output := range []*ExtendedLockedOutput{
+		NewExtendedLockedOutput(NewColoredBalances(map[Color]uint64{ColorIOTA: 100}), wallets[0].address),
+		NewExtendedLockedOutput(NewColoredBalances(map[Color]uint64{color1: 100}), wallets[0].address).WithTimeLock(deadline),
+		NewExtendedLockedOutput(NewColoredBalances(map[Color]uint64{ColorIOTA: 100}), wallets[0].address).WithFallbackOptions(wallets[1].address, deadline),
+	} {
+		require.NoError(t, output.SetPayload([]byte("payload")))
+
+		restoredOutput, consumedBytes, err := OutputFromBytes(output.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, len(output.Bytes()), consumedBytes)
+		assert.Equal(t, output.Bytes(), restoredOutput.Bytes())
+		assert.True(t, output.TimeLock().Equal(restoredOutput.(*ExtendedLockedOutput).TimeLock()))
+		assert.Equal(t, []byte("payload"), restoredOutput.(*ExtendedLockedOutput).Payload())
+	}
+
+	assert.Error(t, NewExtendedLockedOutput(NewColoredBalances(map[Color]uint64{ColorIOTA: 100}), wallets[0].address).SetPayload(make([]byte, MaxOutputPayloadSize+1)))
+}
+
+func TestThresholdSignatureUnlockBlock(t *testing.T) {
+	branchDAG, utxoDAG := setupDependencies(t)
+	defer branchDAG.Shutdown()
+
+	wallets := createWallets(4)
+	thresholdAddress, err := NewThresholdAddress(2, wallets[0].publicKey(), wallets[1].publicKey(), wallets[2].publicKey())
+	require.NoError(t, err)
+
+	inputs := []Output{generateOutput(utxoDAG, thresholdAddress, 1), generateOutput(utxoDAG, thresholdAddress, 2)}
+	essence := NewTransactionEssence(0, time.Now(), identity.ID{}, identity.ID{}, NewInputs(inputs[0].Input(), inputs[1].Input()), NewOutputs(NewSigLockedSingleOutput(200, wallets[3].address)))
+
+	// buildThresholdTransaction creates a Transaction that is signed by the given wallets
+	buildThresholdTransaction := func(signers ...wallet) *Transaction {
+		unlockBlock, err := NewThresholdSignatureUnlockBlock(2, wallets[2].publicKey(), wallets[1].publicKey(), wallets[0].publicKey())
+		require.NoError(t, err)
+		for _, signer := range signers {
+			if err := unlockBlock.AddSignature(signer.publicKey(), signer.privateKey().Sign(essence.Bytes())); err != nil {
+				return nil
+			}
+		}
+
+		return NewTransaction(essence, UnlockBlocks{unlockBlock, NewReferenceUnlockBlock(0)})
+	}
+
+	assert.True(t, utxoDAG.unlockBlocksValid(inputs, buildThresholdTransaction(wallets[0], wallets[2])))
+	assert.False(t, utxoDAG.unlockBlocksValid(inputs, buildThresholdTransaction(wallets[1])))
+	assert.Nil(t, buildThresholdTransaction(wallets[3]))
+
+	// signatures beyond the threshold are rejected
+	assert.Nil(t, buildThresholdTransaction(wallets[0], wallets[1], wallets[2]))
+
+	// signatures for different data are not counted
+	invalidUnlockBlock, err := NewThresholdSignatureUnlockBlock(2, wallets[0].publicKey(), wallets[1].publicKey(), wallets[2].publicKey())
+	require.NoError(t, err)
+	require.NoError(t, invalidUnlockBlock.AddSignature(wallets[0].publicKey(), wallets[0].privateKey().Sign(essence.Bytes())))
+	require.NoError(t, invalidUnlockBlock.AddSignature(wallets[1].publicKey(), wallets[1].privateKey().Sign([]byte("other data"))))
+	assert.False(t, utxoDAG.unlockBlocksValid(inputs, NewTransaction(essence, UnlockBlocks{invalidUnlockBlock, NewReferenceUnlockBlock(0)})))
+
+	// the UnlockBlock survives a round trip through its serialized form
+	transaction := buildThresholdTransaction(wallets[1], wallets[2])
+	restoredTransaction, _, err := TransactionFromBytes(transaction.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, transaction.Bytes(), restoredTransaction.Bytes())
+	assert.True(t, utxoDAG.unlockBlocksValid(inputs, restoredTransaction))
+	assert.Equal(t, 2, restoredTransaction.UnlockBlocks()[0].(*ThresholdSignatureUnlockBlock).SignatureCount())
+
+	// the UnlockBlock of a Transaction has to contain exactly the required signatures in ascending order
+	unlockBlock := restoredTransaction.UnlockBlocks()[0].(*ThresholdSignatureUnlockBlock)
+	unlockBlockBytes := unlockBlock.Bytes()
+	signaturesOffset := len(unlockBlockBytes) - 2*(1+ed25519.SignatureSize) - 1
+	_, _, err = ThresholdSignatureUnlockBlockFromBytes(unlockBlockBytes)
+	require.NoError(t, err)
+
+	swappedSignatures := append(append(append([]byte{}, unlockBlockBytes[:signaturesOffset+1]...),
+		unlockBlockBytes[signaturesOffset+2+ed25519.SignatureSize:]...),
+		unlockBlockBytes[signaturesOffset+1:signaturesOffset+2+ed25519.SignatureSize]...)
+	_, _, err = ThresholdSignatureUnlockBlockFromBytes(swappedSignatures)
+	assert.Error(t, err)
+
+	missingSignature := append([]byte{}, unlockBlockBytes[:signaturesOffset+1+1+ed25519.SignatureSize]...)
+	missingSignature[signaturesOffset] = 1
+	_, _, err = ThresholdSignatureUnlockBlockFromBytes(missingSignature)
+	assert.Error(t, err)
+	_, err = PartialThresholdSignatureUnlockBlockFromMarshalUtil(marshalutil.New(missingSignature))
+	assert.NoError(t, err)
+}
+
+func TestColorMetadata(t *testing.T) {
+	branchDAG, utxoDAG := setupDependencies(t)
+	defer branchDAG.Shutdown()
+
+	wallets := createWallets(1)
+	bookTransaction := func(inputs []Output, outputs ...Output) *Transaction {
+		utxoInputs := make([]Input, len(inputs))
+		for i, input := range inputs {
+			utxoInputs[i] = input.Input()
+		}
+		essence := NewTransactionEssence(0, time.Now(), identity.ID{}, identity.ID{}, NewInputs(utxoInputs...), NewOutputs(outputs...))
+		transaction := NewTransaction(essence, wallets[0].unlockBlocks(essence))
+
+		valid, err := utxoDAG.CheckTransaction(transaction)
+		require.NoError(t, err)
+		require.True(t, valid)
+		_, err = utxoDAG.BookTransaction(transaction)
+		require.NoError(t, err)
+
+		return transaction
+	}
+	colorMetadata := func(color Color) (colorMetadata *ColorMetadata) {
+		require.True(t, utxoDAG.ColorMetadata(color).Consume(func(metadata *ColorMetadata) {
+			colorMetadata = metadata
+		}))
+		return
+	}
+
+	// mint new tokens
+	mintTransaction := bookTransaction([]Output{generateOutput(utxoDAG, wallets[0].address, 1)}, NewSigLockedColoredOutput(NewColoredBalances(map[Color]uint64{ColorMint: 60, ColorIOTA: 40}), wallets[0].address))
+	mintedOutputID := mintTransaction.Essence().Outputs()[0].ID()
+	mintedColor := Color(blake2b.Sum256(mintedOutputID.Bytes()))
+	assert.Equal(t, mintTransaction.ID(), colorMetadata(mintedColor).MintingTransactionID())
+	assert.Equal(t, uint64(60), colorMetadata(mintedColor).MintedSupply())
+	assert.Equal(t, uint64(60), colorMetadata(mintedColor).CirculatingSupply())
+
+	// burn some of the minted tokens
+	var mintedOutput Output
+	require.True(t, utxoDAG.Output(mintedOutputID).Consume(func(output Output) {
+		mintedOutput = output
+	}))
+	bookTransaction([]Output{mintedOutput}, NewSigLockedColoredOutput(NewColoredBalances(map[Color]uint64{mintedColor: 20, ColorIOTA: 80}), wallets[0].address))
+	assert.Equal(t, uint64(60), colorMetadata(mintedColor).MintedSupply())
+	assert.Equal(t, uint64(40), colorMetadata(mintedColor).BurnedSupply())
+	assert.Equal(t, uint64(20), colorMetadata(mintedColor).CirculatingSupply())
+
+	// colored tokens of a snapshot are added to the supply
+	snapshotOutput := NewSigLockedColoredOutput(NewColoredBalances(map[Color]uint64{color1: 10}), wallets[0].address)
+	snapshotOutput.SetID(NewOutputID(TransactionID{7}, 0))
+	utxoDAG.LoadOutputs(Outputs{snapshotOutput})
+	assert.Equal(t, GenesisTransactionID, colorMetadata(color1).MintingTransactionID())
+	assert.Equal(t, uint64(10), colorMetadata(color1).MintedSupply())
+
+	colorsCount := 0
+	utxoDAG.ForEachColorMetadata(func(*ColorMetadata) bool {
+		colorsCount++
+		return true
+	})
+	assert.Equal(t, 2, colorsCount)
+
+	// the ColorMetadata survives a round trip through its serialized form
+	restoredColorMetadata, _, err := ColorMetadataFromBytes(colorMetadata(mintedColor).Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, colorMetadata(mintedColor).Bytes(), restoredColorMetadata.Bytes())
+}
+
+func TestColorMetadata_ConflictingMint(t *testing.T) {
+	branchDAG, utxoDAG := setupDependencies(t)
+	defer branchDAG.Shutdown()
+
+	wallets := createWallets(1)
+	bookTransaction := func(inputs []Output, outputs ...Output) *Transaction {
+		utxoInputs := make([]Input, len(inputs))
+		for i, input := range inputs {
+			utxoInputs[i] = input.Input()
+		}
+		essence := NewTransactionEssence(0, time.Now(), identity.ID{}, identity.ID{}, NewInputs(utxoInputs...), NewOutputs(outputs...))
+		transaction := NewTransaction(essence, wallets[0].unlockBlocks(essence))
+
+		_, err := utxoDAG.BookTransaction(transaction)
+		require.NoError(t, err)
+
+		return transaction
+	}
+	mintedSupplyAndColor := func(transaction *Transaction) (supply uint64, color Color) {
+		color = blake2b.Sum256(transaction.Essence().Outputs()[0].ID().Bytes())
+		utxoDAG.ColorMetadata(color).Consume(func(colorMetadata *ColorMetadata) {
+			supply = colorMetadata.CirculatingSupply()
+		})
+		return
+	}
+
+	// two conflicting Transactions mint tokens from the same Output and the second one burns some of them right away
+	genesisOutput := generateOutput(utxoDAG, wallets[0].address, 1)
+	acceptedMint := bookTransaction([]Output{genesisOutput}, NewSigLockedColoredOutput(NewColoredBalances(map[Color]uint64{ColorMint: 60, ColorIOTA: 40}), wallets[0].address))
+	rejectedMint := bookTransaction([]Output{genesisOutput}, NewSigLockedColoredOutput(NewColoredBalances(map[Color]uint64{ColorMint: 70, ColorIOTA: 30}), wallets[0].address))
+	_, rejectedColor := mintedSupplyAndColor(rejectedMint)
+	var rejectedOutput Output
+	require.True(t, utxoDAG.Output(rejectedMint.Essence().Outputs()[0].ID()).Consume(func(output Output) {
+		rejectedOutput = output
+	}))
+	bookTransaction([]Output{rejectedOutput}, NewSigLockedColoredOutput(NewColoredBalances(map[Color]uint64{rejectedColor: 50, ColorIOTA: 50}), wallets[0].address))
+
+	acceptedSupply, _ := mintedSupplyAndColor(acceptedMint)
+	rejectedSupply, _ := mintedSupplyAndColor(rejectedMint)
+	assert.Equal(t, uint64(60), acceptedSupply)
+	assert.Equal(t, uint64(50), rejectedSupply)
+
+	// once the conflict is decided, only the supply of the accepted Transaction remains
+	_, err := branchDAG.SetBranchLiked(NewBranchID(acceptedMint.ID()), true)
+	require.NoError(t, err)
+	_, err = branchDAG.SetBranchFinalized(NewBranchID(acceptedMint.ID()), true)
+	require.NoError(t, err)
+
+	acceptedSupply, _ = mintedSupplyAndColor(acceptedMint)
+	rejectedSupply, _ = mintedSupplyAndColor(rejectedMint)
+	assert.Equal(t, uint64(60), acceptedSupply)
+	assert.Equal(t, uint64(0), rejectedSupply)
+}
+
 func TestAddressOutputMapping(t *testing.T) {
 	branchDAG, utxoDAG := setupDependencies(t)
 	defer branchDAG.Shutdown()
@@ -776,6 +1163,72 @@ func TestAddressOutputMapping(t *testing.T) {
 	assert.Equal(t, 1, len(res))
 }
 
+func TestAddressHistory(t *testing.T) {
+	branchDAG, utxoDAG := setupDependencies(t)
+	defer branchDAG.Shutdown()
+	defer utxoDAG.Shutdown()
+
+	wallets := createWallets(2)
+	addressHistory := func(address Address) (addressHistoryEntries AddressHistoryEntries) {
+		cachedAddressHistoryEntries := utxoDAG.AddressHistory(address)
+		defer cachedAddressHistoryEntries.Release()
+
+		return cachedAddressHistoryEntries.Unwrap().Sort()
+	}
+
+	// snapshotted Outputs start the history of their Address
+	snapshotOutput := NewSigLockedSingleOutput(100, wallets[0].address)
+	snapshotOutput.SetID(NewOutputID(TransactionID{7}, 0))
+	utxoDAG.LoadOutputs(Outputs{snapshotOutput})
+
+	// booked Transactions are added to the history of the Addresses that they credit or debit
+	essence := NewTransactionEssence(0, time.Now(), identity.ID{}, identity.ID{}, NewInputs(snapshotOutput.Input()), NewOutputs(
+		NewSigLockedSingleOutput(60, wallets[1].address),
+		NewSigLockedSingleOutput(40, wallets[0].address),
+	))
+	transaction := NewTransaction(essence, wallets[0].unlockBlocks(essence))
+	_, err := utxoDAG.BookTransaction(transaction)
+	require.NoError(t, err)
+
+	senderHistory := addressHistory(wallets[0].address)
+	require.Len(t, senderHistory, 2)
+	assert.Equal(t, TransactionID{7}, senderHistory[0].TransactionID())
+	assert.Equal(t, map[Color]uint64{ColorIOTA: 100}, senderHistory[0].Received().Map())
+	assert.Equal(t, transaction.ID(), senderHistory[1].TransactionID())
+	assert.Equal(t, map[Color]uint64{ColorIOTA: 40}, senderHistory[1].Received().Map())
+	assert.Equal(t, map[Color]uint64{ColorIOTA: 100}, senderHistory[1].Spent().Map())
+
+	receiverHistory := addressHistory(wallets[1].address)
+	require.Len(t, receiverHistory, 1)
+	assert.Equal(t, map[Color]uint64{ColorIOTA: 60}, receiverHistory[0].Received().Map())
+	assert.Equal(t, 0, receiverHistory[0].Spent().Size())
+	assert.True(t, receiverHistory[0].ContainsColor(ColorIOTA))
+	assert.False(t, receiverHistory[0].ContainsColor(color1))
+
+	// the AddressHistoryEntry survives a round trip through its serialized form
+	restoredEntry, _, err := AddressHistoryEntryFromBytes(receiverHistory[0].Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, receiverHistory[0].Bytes(), restoredEntry.Bytes())
+
+	// the history is iterated in the order of the timestamps and can be continued after a cursor
+	iteratedHistory := func(optionalCursor ...AddressHistoryCursor) (transactionIDs []TransactionID) {
+		utxoDAG.ForEachAddressHistoryEntry(wallets[0].address, func(addressHistoryEntry *AddressHistoryEntry) bool {
+			transactionIDs = append(transactionIDs, addressHistoryEntry.TransactionID())
+			return true
+		}, optionalCursor...)
+
+		return
+	}
+	assert.Equal(t, []TransactionID{{7}, transaction.ID()}, iteratedHistory())
+	assert.Equal(t, []TransactionID{transaction.ID()}, iteratedHistory(senderHistory[0].Cursor()))
+	assert.Empty(t, iteratedHistory(senderHistory[1].Cursor()))
+
+	cursor, err := AddressHistoryCursorFromBase58(senderHistory[1].Cursor().Base58())
+	require.NoError(t, err)
+	assert.Equal(t, transaction.ID(), cursor.TransactionID())
+	assert.True(t, essence.Timestamp().Equal(cursor.Timestamp()))
+}
+
 func setupDependencies(t *testing.T) (*BranchDAG, *UTXODAG) {
 	store := mapdb.NewMapDB()
 	branchDAG := NewBranchDAG(store)
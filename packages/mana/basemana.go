@@ -0,0 +1,109 @@
+package mana
+
+import (
+	"math"
+	"time"
+)
+
+// BaseMana holds the different mana values of a single node as well as the time they were last updated.
+type BaseMana struct {
+	BaseMana1          float64
+	EffectiveBaseMana1 float64
+	BaseMana2          float64
+	EffectiveBaseMana2 float64
+	LastUpdated        time.Time
+}
+
+// EffectiveValue returns the combination of the effective base mana values that uses the given weight for Effective
+// Base Mana 1 and the remainder for Effective Base Mana 2.
+func (b *BaseMana) EffectiveValue(weight float64) float64 {
+	return weight*b.EffectiveBaseMana1 + (1-weight)*b.EffectiveBaseMana2
+}
+
+// update updates all the mana values with respect to the given time.
+func (b *BaseMana) update(t time.Time) {
+	if !t.After(b.LastUpdated) {
+		return
+	}
+
+	n := t.Sub(b.LastUpdated)
+	b.updateEBM1(n)
+	b.updateBM2(n)
+	b.updateEBM2(n)
+
+	b.LastUpdated = t
+}
+
+// revokeBaseMana1 revokes the given amount of Base Mana 1 at the given time. If the time is older than the last
+// update, the revocation is applied retroactively.
+func (b *BaseMana) revokeBaseMana1(amount float64, t time.Time) {
+	if t.After(b.LastUpdated) {
+		b.update(t)
+		b.BaseMana1 -= amount
+
+		return
+	}
+
+	// update in the past
+	n := b.LastUpdated.Sub(t)
+	b.BaseMana1 -= amount
+	b.EffectiveBaseMana1 -= amount * (1 - math.Exp(-EMACoeff1*n.Seconds()))
+}
+
+// pledgeAndUpdate pledges the mana of the given Transaction and updates the mana values with respect to its timestamp.
+// It returns the amount of Base Mana 1 and Base Mana 2 that were pledged.
+func (b *BaseMana) pledgeAndUpdate(txInfo *TxInfo) (bm1Pledged float64, bm2Pledged float64) {
+	t := txInfo.TimeStamp
+	bm1Pledged = txInfo.TotalBalance
+
+	if t.After(b.LastUpdated) {
+		b.update(t)
+		b.BaseMana1 += bm1Pledged
+
+		// pending mana is awarded depending on how long the funds were sitting on their address
+		for _, input := range txInfo.InputInfos {
+			bm2Add := input.Amount * (1 - math.Exp(-Decay*t.Sub(input.TimeStamp).Seconds()))
+			b.BaseMana2 += bm2Add
+			bm2Pledged += bm2Add
+		}
+
+		return bm1Pledged, bm2Pledged
+	}
+
+	// update in the past
+	n := b.LastUpdated.Sub(t).Seconds()
+	b.BaseMana1 += bm1Pledged
+	for _, input := range txInfo.InputInfos {
+		bm2Add := input.Amount * (1 - math.Exp(-Decay*t.Sub(input.TimeStamp).Seconds())) * math.Exp(-Decay*n)
+		b.BaseMana2 += bm2Add
+		bm2Pledged += bm2Add
+	}
+
+	b.EffectiveBaseMana1 += bm1Pledged * (1 - math.Exp(-EMACoeff1*n))
+	if EMACoeff2 != Decay {
+		b.EffectiveBaseMana2 += bm2Pledged * EMACoeff2 * (math.Exp(-Decay*n) - math.Exp(-EMACoeff2*n)) / (EMACoeff2 - Decay) / math.Exp(-Decay*n)
+	} else {
+		b.EffectiveBaseMana2 += bm2Pledged * Decay * n
+	}
+
+	return bm1Pledged, bm2Pledged
+}
+
+func (b *BaseMana) updateEBM1(n time.Duration) {
+	b.EffectiveBaseMana1 = math.Exp(-EMACoeff1*n.Seconds())*b.EffectiveBaseMana1 + (1-math.Exp(-EMACoeff1*n.Seconds()))*b.BaseMana1
+}
+
+func (b *BaseMana) updateBM2(n time.Duration) {
+	b.BaseMana2 *= math.Exp(-Decay * n.Seconds())
+}
+
+func (b *BaseMana) updateEBM2(n time.Duration) {
+	// Base Mana 2 has already been decayed to the end of the interval, so we need to use its value at the start.
+	bm2AtStart := b.BaseMana2 / math.Exp(-Decay*n.Seconds())
+	if EMACoeff2 != Decay {
+		b.EffectiveBaseMana2 = math.Exp(-EMACoeff2*n.Seconds())*b.EffectiveBaseMana2 +
+			(math.Exp(-Decay*n.Seconds())-math.Exp(-EMACoeff2*n.Seconds()))/(EMACoeff2-Decay)*EMACoeff2*bm2AtStart
+	} else {
+		b.EffectiveBaseMana2 = math.Exp(-Decay*n.Seconds())*b.EffectiveBaseMana2 + Decay*n.Seconds()*math.Exp(-Decay*n.Seconds())*bm2AtStart
+	}
+}
@@ -0,0 +1,88 @@
+package mana
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaseMana_update(t *testing.T) {
+	now := time.Now()
+	baseMana := &BaseMana{BaseMana1: 1, EffectiveBaseMana1: 0, BaseMana2: 1, EffectiveBaseMana2: 0, LastUpdated: now}
+
+	halfLifeSeconds := math.Ln2 / Decay
+	halfLife := time.Duration(halfLifeSeconds * float64(time.Second))
+	baseMana.update(now.Add(halfLife))
+
+	// Base Mana 1 does not decay, Base Mana 2 halves after one half life
+	assert.Equal(t, 1.0, baseMana.BaseMana1)
+	assert.InDelta(t, 0.5, baseMana.BaseMana2, 1e-6)
+	// the effective values follow the base values
+	assert.InDelta(t, 0.5, baseMana.EffectiveBaseMana1, 1e-6)
+	assert.InDelta(t, Decay*halfLife.Seconds()*0.5, baseMana.EffectiveBaseMana2, 1e-6)
+	assert.Equal(t, now.Add(halfLife), baseMana.LastUpdated)
+
+	// updates in the past are ignored
+	baseMana.update(now)
+	assert.Equal(t, now.Add(halfLife), baseMana.LastUpdated)
+}
+
+func TestBaseMana_pledgeAndUpdate(t *testing.T) {
+	now := time.Now()
+	baseMana := &BaseMana{}
+
+	txInfo := &TxInfo{
+		TimeStamp:    now,
+		TotalBalance: 10,
+		InputInfos: []InputInfo{
+			// funds that were sitting on their address forever generate the full pending mana
+			{TimeStamp: time.Time{}, Amount: 4},
+			// funds that were just moved generate no pending mana
+			{TimeStamp: now, Amount: 6},
+		},
+	}
+
+	bm1Pledged, bm2Pledged := baseMana.pledgeAndUpdate(txInfo)
+	assert.Equal(t, 10.0, bm1Pledged)
+	assert.InDelta(t, 4.0, bm2Pledged, 1e-6)
+	assert.Equal(t, 10.0, baseMana.BaseMana1)
+	assert.InDelta(t, 4.0, baseMana.BaseMana2, 1e-6)
+	assert.Equal(t, now, baseMana.LastUpdated)
+
+	// pledging in the past also updates the effective values retroactively
+	pastTxInfo := &TxInfo{
+		TimeStamp:    now.Add(-time.Hour),
+		TotalBalance: 5,
+		InputInfos:   []InputInfo{{TimeStamp: now.Add(-time.Hour), Amount: 5}},
+	}
+	bm1Pledged, bm2Pledged = baseMana.pledgeAndUpdate(pastTxInfo)
+	assert.Equal(t, 5.0, bm1Pledged)
+	assert.Equal(t, 0.0, bm2Pledged)
+	assert.Equal(t, 15.0, baseMana.BaseMana1)
+	assert.InDelta(t, 5*(1-math.Exp(-EMACoeff1*time.Hour.Seconds())), baseMana.EffectiveBaseMana1, 1e-6)
+	assert.Equal(t, now, baseMana.LastUpdated)
+}
+
+func TestBaseMana_revokeBaseMana1(t *testing.T) {
+	now := time.Now()
+	baseMana := &BaseMana{BaseMana1: 10, EffectiveBaseMana1: 10, LastUpdated: now}
+
+	baseMana.revokeBaseMana1(4, now.Add(time.Minute))
+	assert.Equal(t, 6.0, baseMana.BaseMana1)
+	assert.Equal(t, now.Add(time.Minute), baseMana.LastUpdated)
+
+	baseMana.revokeBaseMana1(6, now)
+	assert.Equal(t, 0.0, baseMana.BaseMana1)
+	assert.InDelta(t, 10-6*(1-math.Exp(-EMACoeff1*time.Minute.Seconds())), baseMana.EffectiveBaseMana1, 1e-6)
+	assert.Equal(t, now.Add(time.Minute), baseMana.LastUpdated)
+}
+
+func TestBaseMana_EffectiveValue(t *testing.T) {
+	baseMana := &BaseMana{EffectiveBaseMana1: 10, EffectiveBaseMana2: 2}
+
+	assert.Equal(t, 10.0, baseMana.EffectiveValue(1))
+	assert.Equal(t, 2.0, baseMana.EffectiveValue(0))
+	assert.Equal(t, 6.0, baseMana.EffectiveValue(DefaultWeight))
+}
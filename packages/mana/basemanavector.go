@@ -0,0 +1,281 @@
+package mana
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/hive.go/identity"
+	"golang.org/x/xerrors"
+)
+
+// region BaseManaVector ///////////////////////////////////////////////////////////////////////////////////////////////
+
+// BaseManaVector maps the nodes to their BaseMana for a single Type of mana.
+type BaseManaVector struct {
+	vector     map[identity.ID]*BaseMana
+	vectorType Type
+	mutex      sync.RWMutex
+}
+
+// NewBaseManaVector creates a new, empty BaseManaVector for the given Type of mana.
+func NewBaseManaVector(vectorType Type) *BaseManaVector {
+	return &BaseManaVector{
+		vector:     make(map[identity.ID]*BaseMana),
+		vectorType: vectorType,
+	}
+}
+
+// Type returns the Type of mana that is tracked by the BaseManaVector.
+func (b *BaseManaVector) Type() Type {
+	return b.vectorType
+}
+
+// Has returns true if the given node has an entry in the BaseManaVector.
+func (b *BaseManaVector) Has(nodeID identity.ID) bool {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	_, exists := b.vector[nodeID]
+	return exists
+}
+
+// Size returns the number of nodes in the BaseManaVector.
+func (b *BaseManaVector) Size() int {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return len(b.vector)
+}
+
+// BookMana revokes Base Mana 1 from the nodes that the consumed Inputs pledged their mana to and pledges the mana of
+// the Transaction to the node that it names for the Type of the BaseManaVector.
+func (b *BaseManaVector) BookMana(txInfo *TxInfo) {
+	revokeEvents, pledgeEvents, updateEvents := b.bookMana(txInfo)
+
+	for _, ev := range revokeEvents {
+		Events().Revoked.Trigger(ev)
+	}
+	for _, ev := range pledgeEvents {
+		Events().Pledged.Trigger(ev)
+	}
+	for _, ev := range updateEvents {
+		Events().Updated.Trigger(ev)
+	}
+}
+
+func (b *BaseManaVector) bookMana(txInfo *TxInfo) (revokeEvents []*RevokedEvent, pledgeEvents []*PledgedEvent, updateEvents []*UpdatedEvent) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, inputInfo := range txInfo.InputInfos {
+		inputPledgeID, exists := inputInfo.PledgeID[b.vectorType]
+		if !exists || inputPledgeID == (identity.ID{}) {
+			continue
+		}
+
+		baseMana := b.baseMana(inputPledgeID)
+		oldMana := *baseMana
+		baseMana.revokeBaseMana1(inputInfo.Amount, txInfo.TimeStamp)
+
+		revokeEvents = append(revokeEvents, &RevokedEvent{inputPledgeID, inputInfo.Amount, txInfo.TimeStamp, b.vectorType})
+		updateEvents = append(updateEvents, &UpdatedEvent{inputPledgeID, oldMana, *baseMana, b.vectorType})
+	}
+
+	pledgeID, exists := txInfo.PledgeID[b.vectorType]
+	if !exists || pledgeID == (identity.ID{}) {
+		return
+	}
+
+	baseMana := b.baseMana(pledgeID)
+	oldMana := *baseMana
+	bm1Pledged, bm2Pledged := baseMana.pledgeAndUpdate(txInfo)
+
+	pledgeEvents = append(pledgeEvents, &PledgedEvent{pledgeID, bm1Pledged, bm2Pledged, txInfo.TimeStamp, b.vectorType})
+	updateEvents = append(updateEvents, &UpdatedEvent{pledgeID, oldMana, *baseMana, b.vectorType})
+
+	return
+}
+
+// GetMana returns the combined effective mana of the given node (see DefaultWeight) updated to the current time (or
+// the optionally given time) together with the time of the update.
+func (b *BaseManaVector) GetMana(nodeID identity.ID, optionalUpdateTime ...time.Time) (float64, time.Time, error) {
+	return b.GetWeightedMana(nodeID, DefaultWeight, optionalUpdateTime...)
+}
+
+// GetWeightedMana returns the combined effective mana of the given node where weight (in [0,1]) determines the share
+// of Effective Base Mana 1 and the remainder the share of Effective Base Mana 2. The mana is projected to the update
+// time without modifying the stored BaseMana, so reading it does not trigger any events.
+func (b *BaseManaVector) GetWeightedMana(nodeID identity.ID, weight float64, optionalUpdateTime ...time.Time) (mana float64, updateTime time.Time, err error) {
+	updateTime = updateTimeFromOptionalParameter(optionalUpdateTime)
+
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	baseMana, exists := b.vector[nodeID]
+	if !exists {
+		err = xerrors.Errorf("failed to retrieve %s mana of node %s: %w", b.vectorType, nodeID, ErrNodeNotFoundInBaseManaVector)
+		return
+	}
+	projectedMana := *baseMana
+	projectedMana.update(updateTime)
+
+	return projectedMana.EffectiveValue(weight), updateTime, nil
+}
+
+// GetManaMap returns the combined effective mana of all the nodes updated to the current time (or the optionally given
+// time) together with the time of the update.
+func (b *BaseManaVector) GetManaMap(optionalUpdateTime ...time.Time) (nodeMap NodeMap, updateTime time.Time) {
+	updateTime = updateTimeFromOptionalParameter(optionalUpdateTime)
+
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	nodeMap = make(NodeMap, len(b.vector))
+	for nodeID, baseMana := range b.vector {
+		projectedMana := *baseMana
+		projectedMana.update(updateTime)
+		nodeMap[nodeID] = projectedMana.EffectiveValue(DefaultWeight)
+	}
+
+	return
+}
+
+// GetHighestManaNodes returns the n nodes with the highest mana in descending order (all nodes if n is 0).
+func (b *BaseManaVector) GetHighestManaNodes(n uint, optionalUpdateTime ...time.Time) (nodes []Node, updateTime time.Time) {
+	nodeMap, updateTime := b.GetManaMap(optionalUpdateTime...)
+
+	return nodeMap.ToNodes().Highest(n), updateTime
+}
+
+// SetMana overrides the BaseMana of the given node.
+func (b *BaseManaVector) SetMana(nodeID identity.ID, baseMana *BaseMana) {
+	b.mutex.Lock()
+	oldMana := *b.baseMana(nodeID)
+	b.vector[nodeID] = baseMana
+	b.mutex.Unlock()
+
+	if oldMana == *baseMana {
+		return
+	}
+	Events().Updated.Trigger(&UpdatedEvent{nodeID, oldMana, *baseMana, b.vectorType})
+}
+
+// ForEach iterates through the BaseManaVector and calls the consumer for every node. It aborts the iteration if the
+// consumer returns false.
+func (b *BaseManaVector) ForEach(consumer func(nodeID identity.ID, baseMana BaseMana) bool) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	for nodeID, baseMana := range b.vector {
+		if !consumer(nodeID, *baseMana) {
+			return
+		}
+	}
+}
+
+// ToPersistables converts the BaseManaVector to a list of PersistableBaseMana that can be stored in the database.
+func (b *BaseManaVector) ToPersistables() (persistables []*PersistableBaseMana) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	persistables = make([]*PersistableBaseMana, 0, len(b.vector))
+	for nodeID, baseMana := range b.vector {
+		persistables = append(persistables, NewPersistableBaseMana(b.vectorType, nodeID, *baseMana))
+	}
+
+	return
+}
+
+// FromPersistable restores the BaseMana of a node from a PersistableBaseMana.
+func (b *BaseManaVector) FromPersistable(persistable *PersistableBaseMana) (err error) {
+	if persistable.ManaType() != b.vectorType {
+		err = xerrors.Errorf("persistable of type %s can not be loaded into a %s BaseManaVector: %w", persistable.ManaType(), b.vectorType, ErrUnknownManaType)
+		return
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	baseMana := persistable.BaseMana()
+	b.vector[persistable.NodeID()] = &baseMana
+
+	return
+}
+
+// baseMana returns the BaseMana of the given node and creates it if it does not exist, yet.
+func (b *BaseManaVector) baseMana(nodeID identity.ID) *BaseMana {
+	baseMana, exists := b.vector[nodeID]
+	if !exists {
+		baseMana = &BaseMana{}
+		b.vector[nodeID] = baseMana
+	}
+
+	return baseMana
+}
+
+func updateTimeFromOptionalParameter(optionalUpdateTime []time.Time) time.Time {
+	if len(optionalUpdateTime) == 0 {
+		return time.Now()
+	}
+
+	return optionalUpdateTime[0]
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region Node /////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Node represents a node and its mana value.
+type Node struct {
+	ID   identity.ID
+	Mana float64
+}
+
+// NodeMap is a map of nodes and their mana values.
+type NodeMap map[identity.ID]float64
+
+// ToNodes converts the NodeMap into a list of Nodes.
+func (n NodeMap) ToNodes() (nodes NodesByMana) {
+	nodes = make(NodesByMana, 0, len(n))
+	for nodeID, mana := range n {
+		nodes = append(nodes, Node{ID: nodeID, Mana: mana})
+	}
+
+	return
+}
+
+// TotalMana returns the sum of the mana of all the nodes.
+func (n NodeMap) TotalMana() (total float64) {
+	for _, mana := range n {
+		total += mana
+	}
+
+	return
+}
+
+// NodesByMana is a list of Nodes that can be sorted by their mana.
+type NodesByMana []Node
+
+// Highest returns the n Nodes with the highest mana in descending order (all Nodes if n is 0). Ties are broken by the
+// node identifier to keep the result deterministic.
+func (n NodesByMana) Highest(amount uint) NodesByMana {
+	sorted := make(NodesByMana, len(n))
+	copy(sorted, n)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Mana == sorted[j].Mana {
+			return bytes.Compare(sorted[i].ID.Bytes(), sorted[j].ID.Bytes()) < 0
+		}
+
+		return sorted[i].Mana > sorted[j].Mana
+	})
+
+	if amount == 0 || int(amount) > len(sorted) {
+		return sorted
+	}
+
+	return sorted[:amount]
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
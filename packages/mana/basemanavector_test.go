@@ -0,0 +1,158 @@
+package mana
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+)
+
+func TestBaseManaVector_BookMana(t *testing.T) {
+	nodeA, nodeB, nodeC := randomNodeID(t), randomNodeID(t), randomNodeID(t)
+	now := time.Now()
+
+	bmv := NewBaseManaVector(ConsensusMana)
+
+	var pledged []*PledgedEvent
+	var revoked []*RevokedEvent
+	pledgedClosure := events.NewClosure(func(ev *PledgedEvent) { pledged = append(pledged, ev) })
+	revokedClosure := events.NewClosure(func(ev *RevokedEvent) { revoked = append(revoked, ev) })
+	Events().Pledged.Attach(pledgedClosure)
+	Events().Revoked.Attach(revokedClosure)
+	defer Events().Pledged.Detach(pledgedClosure)
+	defer Events().Revoked.Detach(revokedClosure)
+
+	// genesis funds have no previous pledge
+	bmv.BookMana(&TxInfo{
+		TimeStamp:    now,
+		TotalBalance: 10,
+		PledgeID:     map[Type]identity.ID{AccessMana: nodeC, ConsensusMana: nodeA},
+		InputInfos:   []InputInfo{{Amount: 10}},
+	})
+	assert.Len(t, revoked, 0)
+	require.Len(t, pledged, 1)
+	assert.Equal(t, nodeA, pledged[0].NodeID)
+	assert.Equal(t, 10.0, pledged[0].AmountBM1)
+	assert.Equal(t, ConsensusMana, pledged[0].ManaType)
+
+	// the funds move on and their Base Mana 1 is moved from A to B
+	bmv.BookMana(&TxInfo{
+		TimeStamp:    now.Add(time.Minute),
+		TotalBalance: 10,
+		PledgeID:     map[Type]identity.ID{AccessMana: nodeC, ConsensusMana: nodeB},
+		InputInfos:   []InputInfo{{TimeStamp: now, Amount: 10, PledgeID: map[Type]identity.ID{ConsensusMana: nodeA}}},
+	})
+	require.Len(t, revoked, 1)
+	assert.Equal(t, nodeA, revoked[0].NodeID)
+	assert.Len(t, pledged, 2)
+
+	assert.True(t, bmv.Has(nodeA))
+	assert.True(t, bmv.Has(nodeB))
+	assert.False(t, bmv.Has(nodeC))
+	assert.Equal(t, 2, bmv.Size())
+
+	bmv.ForEach(func(nodeID identity.ID, baseMana BaseMana) bool {
+		switch nodeID {
+		case nodeA:
+			assert.Equal(t, 0.0, baseMana.BaseMana1)
+		case nodeB:
+			assert.Equal(t, 10.0, baseMana.BaseMana1)
+		}
+		return true
+	})
+}
+
+func TestBaseManaVector_GetMana(t *testing.T) {
+	nodeA, nodeB := randomNodeID(t), randomNodeID(t)
+	now := time.Now()
+
+	bmv := NewBaseManaVector(AccessMana)
+	bmv.SetMana(nodeA, &BaseMana{BaseMana1: 10, EffectiveBaseMana1: 10, LastUpdated: now})
+	bmv.SetMana(nodeB, &BaseMana{BaseMana1: 20, EffectiveBaseMana1: 20, LastUpdated: now})
+
+	mana, updateTime, err := bmv.GetMana(nodeA, now)
+	require.NoError(t, err)
+	assert.Equal(t, 5.0, mana)
+	assert.Equal(t, now, updateTime)
+
+	mana, _, err = bmv.GetWeightedMana(nodeB, 1, now)
+	require.NoError(t, err)
+	assert.Equal(t, 20.0, mana)
+
+	_, _, err = bmv.GetMana(randomNodeID(t))
+	assert.True(t, xerrors.Is(err, ErrNodeNotFoundInBaseManaVector))
+
+	nodeMap, _ := bmv.GetManaMap(now)
+	assert.Equal(t, NodeMap{nodeA: 5, nodeB: 10}, nodeMap)
+	assert.Equal(t, 15.0, nodeMap.TotalMana())
+
+	highest, _ := bmv.GetHighestManaNodes(1, now)
+	assert.Equal(t, []Node{{ID: nodeB, Mana: 10}}, highest)
+
+	highest, _ = bmv.GetHighestManaNodes(0, now)
+	assert.Equal(t, []Node{{ID: nodeB, Mana: 10}, {ID: nodeA, Mana: 5}}, highest)
+
+	// reading the mana at a later time neither modifies the stored BaseMana nor triggers an Updated event
+	updated := 0
+	onUpdated := events.NewClosure(func(*UpdatedEvent) { updated++ })
+	Events().Updated.Attach(onUpdated)
+	defer Events().Updated.Detach(onUpdated)
+
+	_, _, err = bmv.GetMana(nodeA, now.Add(time.Hour))
+	require.NoError(t, err)
+	bmv.GetManaMap(now.Add(time.Hour))
+	assert.Equal(t, 0, updated)
+	bmv.ForEach(func(_ identity.ID, baseMana BaseMana) bool {
+		assert.Equal(t, now, baseMana.LastUpdated)
+		return true
+	})
+}
+
+func TestBaseManaVector_Persistables(t *testing.T) {
+	nodeA := randomNodeID(t)
+
+	bmv := NewBaseManaVector(AccessMana)
+	bmv.SetMana(nodeA, &BaseMana{BaseMana1: 1, EffectiveBaseMana1: 2, BaseMana2: 3, EffectiveBaseMana2: 4, LastUpdated: time.Unix(1000, 0)})
+
+	persistables := bmv.ToPersistables()
+	require.Len(t, persistables, 1)
+
+	restored, _, err := PersistableBaseManaFromBytes(persistables[0].Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, AccessMana, restored.ManaType())
+	assert.Equal(t, nodeA, restored.NodeID())
+	assert.Equal(t, persistables[0].BaseMana(), restored.BaseMana())
+
+	restoredVector := NewBaseManaVector(AccessMana)
+	require.NoError(t, restoredVector.FromPersistable(restored))
+	assert.True(t, restoredVector.Has(nodeA))
+
+	assert.Error(t, NewBaseManaVector(ConsensusMana).FromPersistable(restored))
+}
+
+func TestSnapshot(t *testing.T) {
+	nodeA, nodeB := randomNodeID(t), randomNodeID(t)
+	now := time.Unix(1000, 0)
+
+	bmv := NewBaseManaVector(ConsensusMana)
+	bmv.SetMana(nodeA, &BaseMana{EffectiveBaseMana1: 10, LastUpdated: now})
+	bmv.SetMana(nodeB, &BaseMana{EffectiveBaseMana2: 10, LastUpdated: now})
+
+	snapshot := NewSnapshot(bmv, now)
+	restored, _, err := SnapshotFromBytes(snapshot.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, ConsensusMana, restored.ManaType())
+	assert.True(t, now.Equal(restored.Timestamp()))
+	assert.Equal(t, NodeMap{nodeA: 5, nodeB: 5}, restored.NodeMap())
+}
+
+func randomNodeID(t *testing.T) identity.ID {
+	nodeID, err := identity.RandomID()
+	require.NoError(t, err)
+
+	return nodeID
+}
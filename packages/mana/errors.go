@@ -0,0 +1,17 @@
+package mana
+
+import "errors"
+
+var (
+	// ErrNodeNotFoundInBaseManaVector is returned if the node is not found in the base mana vector.
+	ErrNodeNotFoundInBaseManaVector = errors.New("node not present in base mana vector")
+
+	// ErrUnknownManaType is returned if mana type could not be identified.
+	ErrUnknownManaType = errors.New("unknown mana type")
+
+	// ErrQueryNotAllowed is returned if a query is not allowed for a base mana vector type.
+	ErrQueryNotAllowed = errors.New("query not allowed for this mana type")
+
+	// ErrSnapshotNotFound is returned if no snapshot of the base mana vector exists for the requested time.
+	ErrSnapshotNotFound = errors.New("mana snapshot not found")
+)
@@ -0,0 +1,77 @@
+package mana
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/identity"
+)
+
+var (
+	once       sync.Once
+	manaEvents *EventDefinitions
+)
+
+func newEvents() *EventDefinitions {
+	return &EventDefinitions{
+		Pledged: events.NewEvent(pledgeEventCaller),
+		Revoked: events.NewEvent(revokedEventCaller),
+		Updated: events.NewEvent(updatedEventCaller),
+	}
+}
+
+// Events returns the events defined in the package.
+func Events() *EventDefinitions {
+	once.Do(func() {
+		manaEvents = newEvents()
+	})
+	return manaEvents
+}
+
+// EventDefinitions represents events happening in the mana package.
+type EventDefinitions struct {
+	// Pledged is triggered when mana was pledged to a node.
+	Pledged *events.Event
+	// Revoked is triggered when Base Mana 1 was revoked from a node.
+	Revoked *events.Event
+	// Updated is triggered when the mana of a node was updated.
+	Updated *events.Event
+}
+
+// PledgedEvent is the struct that is passed along with triggering a Pledged event.
+type PledgedEvent struct {
+	NodeID    identity.ID
+	AmountBM1 float64
+	AmountBM2 float64
+	Time      time.Time
+	ManaType  Type
+}
+
+// RevokedEvent is the struct that is passed along with triggering a Revoked event.
+type RevokedEvent struct {
+	NodeID    identity.ID
+	AmountBM1 float64
+	Time      time.Time
+	ManaType  Type
+}
+
+// UpdatedEvent is the struct that is passed along with triggering an Updated event.
+type UpdatedEvent struct {
+	NodeID   identity.ID
+	OldMana  BaseMana
+	NewMana  BaseMana
+	ManaType Type
+}
+
+func pledgeEventCaller(handler interface{}, params ...interface{}) {
+	handler.(func(ev *PledgedEvent))(params[0].(*PledgedEvent))
+}
+
+func revokedEventCaller(handler interface{}, params ...interface{}) {
+	handler.(func(ev *RevokedEvent))(params[0].(*RevokedEvent))
+}
+
+func updatedEventCaller(handler interface{}, params ...interface{}) {
+	handler.(func(ev *UpdatedEvent))(params[0].(*UpdatedEvent))
+}
@@ -0,0 +1,19 @@
+package mana
+
+const (
+	// Decay is the decay rate (gamma) of Base Mana 2 and the rate at which pending mana grows (in unit of 1/s). It
+	// corresponds to a half life of 6 hours.
+	Decay = 0.00003209
+
+	// EMACoeff1 is the coefficient of the exponential moving average that is used for Effective Base Mana 1 (in unit
+	// of 1/s).
+	EMACoeff1 = 0.00003209
+
+	// EMACoeff2 is the coefficient of the exponential moving average that is used for Effective Base Mana 2 (in unit
+	// of 1/s).
+	EMACoeff2 = 0.00003209
+
+	// DefaultWeight is the weight of Effective Base Mana 1 that is used when combining the effective base mana values
+	// into a single mana value (the remainder is taken from Effective Base Mana 2).
+	DefaultWeight = 0.5
+)
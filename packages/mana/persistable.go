@@ -0,0 +1,178 @@
+package mana
+
+import (
+	"math"
+
+	"github.com/iotaledger/hive.go/byteutils"
+	"github.com/iotaledger/hive.go/cerrors"
+	"github.com/iotaledger/hive.go/identity"
+	"github.com/iotaledger/hive.go/marshalutil"
+	"github.com/iotaledger/hive.go/objectstorage"
+	"github.com/iotaledger/hive.go/stringify"
+	"golang.org/x/xerrors"
+)
+
+// region PersistableBaseMana //////////////////////////////////////////////////////////////////////////////////////////
+
+// PersistableBaseMana is a storable version of the BaseMana of a node that is used to persist the BaseManaVectors.
+type PersistableBaseMana struct {
+	manaType Type
+	nodeID   identity.ID
+	baseMana BaseMana
+
+	objectstorage.StorableObjectFlags
+}
+
+// NewPersistableBaseMana creates a new PersistableBaseMana from the given details.
+func NewPersistableBaseMana(manaType Type, nodeID identity.ID, baseMana BaseMana) *PersistableBaseMana {
+	return &PersistableBaseMana{
+		manaType: manaType,
+		nodeID:   nodeID,
+		baseMana: baseMana,
+	}
+}
+
+// PersistableBaseManaFromBytes unmarshals a PersistableBaseMana from a sequence of bytes.
+func PersistableBaseManaFromBytes(bytes []byte) (persistableBaseMana *PersistableBaseMana, consumedBytes int, err error) {
+	marshalUtil := marshalutil.New(bytes)
+	if persistableBaseMana, err = PersistableBaseManaFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse PersistableBaseMana from MarshalUtil: %w", err)
+		return
+	}
+	consumedBytes = marshalUtil.ReadOffset()
+
+	return
+}
+
+// PersistableBaseManaFromMarshalUtil unmarshals a PersistableBaseMana using a MarshalUtil (for easier unmarshaling).
+func PersistableBaseManaFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (persistableBaseMana *PersistableBaseMana, err error) {
+	persistableBaseMana = &PersistableBaseMana{}
+	if persistableBaseMana.manaType, err = typeFromMarshalUtil(marshalUtil); err != nil {
+		return
+	}
+	if persistableBaseMana.nodeID, err = nodeIDFromMarshalUtil(marshalUtil); err != nil {
+		return
+	}
+	values := make([]float64, 4)
+	for i := range values {
+		if values[i], err = float64FromMarshalUtil(marshalUtil); err != nil {
+			return
+		}
+	}
+	persistableBaseMana.baseMana.BaseMana1 = values[0]
+	persistableBaseMana.baseMana.EffectiveBaseMana1 = values[1]
+	persistableBaseMana.baseMana.BaseMana2 = values[2]
+	persistableBaseMana.baseMana.EffectiveBaseMana2 = values[3]
+	if persistableBaseMana.baseMana.LastUpdated, err = marshalUtil.ReadTime(); err != nil {
+		err = xerrors.Errorf("failed to parse LastUpdated (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+
+	return
+}
+
+// PersistableBaseManaFromObjectStorage restores a PersistableBaseMana that was stored in the object storage.
+func PersistableBaseManaFromObjectStorage(key []byte, data []byte) (persistableBaseMana objectstorage.StorableObject, err error) {
+	if persistableBaseMana, _, err = PersistableBaseManaFromBytes(byteutils.ConcatBytes(key, data)); err != nil {
+		err = xerrors.Errorf("failed to parse PersistableBaseMana from bytes: %w", err)
+		return
+	}
+
+	return
+}
+
+// ManaType returns the Type of mana.
+func (p *PersistableBaseMana) ManaType() Type {
+	return p.manaType
+}
+
+// NodeID returns the identifier of the node.
+func (p *PersistableBaseMana) NodeID() identity.ID {
+	return p.nodeID
+}
+
+// BaseMana returns the BaseMana of the node.
+func (p *PersistableBaseMana) BaseMana() BaseMana {
+	return p.baseMana
+}
+
+// Bytes returns a marshaled version of the PersistableBaseMana.
+func (p *PersistableBaseMana) Bytes() []byte {
+	return byteutils.ConcatBytes(p.ObjectStorageKey(), p.ObjectStorageValue())
+}
+
+// String returns a human readable version of the PersistableBaseMana.
+func (p *PersistableBaseMana) String() string {
+	return stringify.Struct("PersistableBaseMana",
+		stringify.StructField("manaType", p.manaType),
+		stringify.StructField("nodeID", p.nodeID),
+		stringify.StructField("baseMana1", p.baseMana.BaseMana1),
+		stringify.StructField("effectiveBaseMana1", p.baseMana.EffectiveBaseMana1),
+		stringify.StructField("baseMana2", p.baseMana.BaseMana2),
+		stringify.StructField("effectiveBaseMana2", p.baseMana.EffectiveBaseMana2),
+		stringify.StructField("lastUpdated", p.baseMana.LastUpdated),
+	)
+}
+
+// Update is disabled and panics if it ever gets called - it is required to match the StorableObject interface.
+func (p *PersistableBaseMana) Update(objectstorage.StorableObject) {
+	panic("updates disabled")
+}
+
+// ObjectStorageKey returns the key that is used to store the object in the database. It is required to match the
+// StorableObject interface.
+func (p *PersistableBaseMana) ObjectStorageKey() []byte {
+	return byteutils.ConcatBytes([]byte{byte(p.manaType)}, p.nodeID.Bytes())
+}
+
+// ObjectStorageValue marshals the PersistableBaseMana into a sequence of bytes that are used as the value part in the
+// object storage.
+func (p *PersistableBaseMana) ObjectStorageValue() []byte {
+	return marshalutil.New().
+		WriteUint64(math.Float64bits(p.baseMana.BaseMana1)).
+		WriteUint64(math.Float64bits(p.baseMana.EffectiveBaseMana1)).
+		WriteUint64(math.Float64bits(p.baseMana.BaseMana2)).
+		WriteUint64(math.Float64bits(p.baseMana.EffectiveBaseMana2)).
+		WriteTime(p.baseMana.LastUpdated).
+		Bytes()
+}
+
+// code contract (make sure the struct implements all required methods)
+var _ objectstorage.StorableObject = &PersistableBaseMana{}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func typeFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (manaType Type, err error) {
+	typeByte, err := marshalUtil.ReadByte()
+	if err != nil {
+		err = xerrors.Errorf("failed to parse mana Type (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	if manaType = Type(typeByte); manaType != AccessMana && manaType != ConsensusMana {
+		err = xerrors.Errorf("invalid mana Type (%d): %w", typeByte, cerrors.ErrParseBytesFailed)
+		return
+	}
+
+	return
+}
+
+func nodeIDFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (nodeID identity.ID, err error) {
+	nodeIDBytes, err := marshalUtil.ReadBytes(len(identity.ID{}))
+	if err != nil {
+		err = xerrors.Errorf("failed to parse node ID (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	copy(nodeID[:], nodeIDBytes)
+
+	return
+}
+
+func float64FromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (value float64, err error) {
+	bits, err := marshalUtil.ReadUint64()
+	if err != nil {
+		err = xerrors.Errorf("failed to parse float64 (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+
+	return math.Float64frombits(bits), nil
+}
@@ -0,0 +1,149 @@
+package mana
+
+import (
+	"math"
+	"time"
+
+	"github.com/iotaledger/hive.go/byteutils"
+	"github.com/iotaledger/hive.go/cerrors"
+	"github.com/iotaledger/hive.go/marshalutil"
+	"github.com/iotaledger/hive.go/objectstorage"
+	"github.com/iotaledger/hive.go/stringify"
+	"golang.org/x/xerrors"
+)
+
+// region Snapshot /////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Snapshot is a storable record of the mana of all the nodes of a BaseManaVector at a certain point in time. Snapshots
+// are used to answer queries about the historical mana distribution.
+type Snapshot struct {
+	manaType  Type
+	timestamp time.Time
+	nodeMap   NodeMap
+
+	objectstorage.StorableObjectFlags
+}
+
+// NewSnapshot creates a new Snapshot of the given BaseManaVector at the given time.
+func NewSnapshot(baseManaVector *BaseManaVector, timestamp time.Time) *Snapshot {
+	nodeMap, _ := baseManaVector.GetManaMap(timestamp)
+
+	return &Snapshot{
+		manaType:  baseManaVector.Type(),
+		timestamp: timestamp,
+		nodeMap:   nodeMap,
+	}
+}
+
+// SnapshotFromBytes unmarshals a Snapshot from a sequence of bytes.
+func SnapshotFromBytes(bytes []byte) (snapshot *Snapshot, consumedBytes int, err error) {
+	marshalUtil := marshalutil.New(bytes)
+	if snapshot, err = SnapshotFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse Snapshot from MarshalUtil: %w", err)
+		return
+	}
+	consumedBytes = marshalUtil.ReadOffset()
+
+	return
+}
+
+// SnapshotFromMarshalUtil unmarshals a Snapshot using a MarshalUtil (for easier unmarshaling).
+func SnapshotFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (snapshot *Snapshot, err error) {
+	snapshot = &Snapshot{}
+	if snapshot.manaType, err = typeFromMarshalUtil(marshalUtil); err != nil {
+		return
+	}
+	unixNano, err := marshalUtil.ReadUint64()
+	if err != nil {
+		err = xerrors.Errorf("failed to parse timestamp (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	snapshot.timestamp = time.Unix(0, int64(unixNano))
+	nodeCount, err := marshalUtil.ReadUint32()
+	if err != nil {
+		err = xerrors.Errorf("failed to parse node count (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	snapshot.nodeMap = make(NodeMap, nodeCount)
+	for i := uint32(0); i < nodeCount; i++ {
+		nodeID, nodeIDErr := nodeIDFromMarshalUtil(marshalUtil)
+		if nodeIDErr != nil {
+			err = nodeIDErr
+			return
+		}
+		if snapshot.nodeMap[nodeID], err = float64FromMarshalUtil(marshalUtil); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// SnapshotFromObjectStorage restores a Snapshot that was stored in the object storage.
+func SnapshotFromObjectStorage(key []byte, data []byte) (snapshot objectstorage.StorableObject, err error) {
+	if snapshot, _, err = SnapshotFromBytes(byteutils.ConcatBytes(key, data)); err != nil {
+		err = xerrors.Errorf("failed to parse Snapshot from bytes: %w", err)
+		return
+	}
+
+	return
+}
+
+// ManaType returns the Type of mana that was recorded in the Snapshot.
+func (s *Snapshot) ManaType() Type {
+	return s.manaType
+}
+
+// Timestamp returns the time the Snapshot was taken at.
+func (s *Snapshot) Timestamp() time.Time {
+	return s.timestamp
+}
+
+// NodeMap returns the mana of all the nodes at the time of the Snapshot.
+func (s *Snapshot) NodeMap() NodeMap {
+	return s.nodeMap
+}
+
+// Bytes returns a marshaled version of the Snapshot.
+func (s *Snapshot) Bytes() []byte {
+	return byteutils.ConcatBytes(s.ObjectStorageKey(), s.ObjectStorageValue())
+}
+
+// String returns a human readable version of the Snapshot.
+func (s *Snapshot) String() string {
+	return stringify.Struct("Snapshot",
+		stringify.StructField("manaType", s.manaType),
+		stringify.StructField("timestamp", s.timestamp),
+		stringify.StructField("nodes", len(s.nodeMap)),
+	)
+}
+
+// Update is disabled and panics if it ever gets called - it is required to match the StorableObject interface.
+func (s *Snapshot) Update(objectstorage.StorableObject) {
+	panic("updates disabled")
+}
+
+// ObjectStorageKey returns the key that is used to store the object in the database. It is required to match the
+// StorableObject interface.
+func (s *Snapshot) ObjectStorageKey() []byte {
+	return marshalutil.New(1 + marshalutil.Uint64Size).
+		WriteByte(byte(s.manaType)).
+		WriteUint64(uint64(s.timestamp.UnixNano())).
+		Bytes()
+}
+
+// ObjectStorageValue marshals the Snapshot into a sequence of bytes that are used as the value part in the object
+// storage.
+func (s *Snapshot) ObjectStorageValue() []byte {
+	marshalUtil := marshalutil.New().WriteUint32(uint32(len(s.nodeMap)))
+	for nodeID, mana := range s.nodeMap {
+		marshalUtil.WriteBytes(nodeID.Bytes()).WriteUint64(math.Float64bits(mana))
+	}
+
+	return marshalUtil.Bytes()
+}
+
+// code contract (make sure the struct implements all required methods)
+var _ objectstorage.StorableObject = &Snapshot{}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,152 @@
+package mana
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/database"
+	"github.com/iotaledger/hive.go/cerrors"
+	"github.com/iotaledger/hive.go/identity"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/marshalutil"
+	"github.com/iotaledger/hive.go/objectstorage"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// PrefixPersistableBaseMana defines the storage prefix for the PersistableBaseMana object storage.
+	PrefixPersistableBaseMana byte = iota
+
+	// PrefixSnapshot defines the storage prefix for the Snapshot object storage.
+	PrefixSnapshot
+)
+
+// persistedTimeKey is the key that is used to store the time up to which the persisted BaseManaVectors are up to date.
+var persistedTimeKey = kvstore.Key("persistedTime")
+
+// Storage persists the BaseManaVectors and their historical Snapshots in the database.
+type Storage struct {
+	store                      kvstore.KVStore
+	persistableBaseManaStorage *objectstorage.ObjectStorage
+	snapshotStorage            *objectstorage.ObjectStorage
+	shutdownOnce               sync.Once
+}
+
+// NewStorage creates a new Storage that uses the given KVStore.
+func NewStorage(store kvstore.KVStore) (storage *Storage) {
+	osFactory := objectstorage.NewFactory(store, database.PrefixMana)
+
+	return &Storage{
+		store:                      store.WithRealm([]byte{database.PrefixMana}),
+		persistableBaseManaStorage: osFactory.New(PrefixPersistableBaseMana, PersistableBaseManaFromObjectStorage, objectstorage.CacheTime(0), objectstorage.PartitionKey(1, len(identity.ID{}))),
+		snapshotStorage:            osFactory.New(PrefixSnapshot, SnapshotFromObjectStorage, objectstorage.CacheTime(0), objectstorage.PartitionKey(1, marshalutil.Uint64Size)),
+	}
+}
+
+// StoreBaseManaVector persists the current state of the given BaseManaVector.
+func (s *Storage) StoreBaseManaVector(baseManaVector *BaseManaVector) {
+	for _, persistable := range baseManaVector.ToPersistables() {
+		persistable.Persist()
+		persistable.SetModified()
+		s.persistableBaseManaStorage.Store(persistable).Release()
+	}
+}
+
+// LoadBaseManaVector restores the given BaseManaVector from the previously persisted state.
+func (s *Storage) LoadBaseManaVector(baseManaVector *BaseManaVector) (err error) {
+	s.persistableBaseManaStorage.ForEach(func(key []byte, cachedObject objectstorage.CachedObject) bool {
+		cachedObject.Consume(func(object objectstorage.StorableObject) {
+			err = baseManaVector.FromPersistable(object.(*PersistableBaseMana))
+		})
+
+		return err == nil
+	}, []byte{byte(baseManaVector.Type())})
+
+	if err != nil {
+		err = xerrors.Errorf("failed to load %s BaseManaVector: %w", baseManaVector.Type(), err)
+	}
+
+	return
+}
+
+// StorePersistedTime persists the time up to which the stored BaseManaVectors contain the booked transactions.
+func (s *Storage) StorePersistedTime(persistedTime time.Time) (err error) {
+	if err = s.store.Set(persistedTimeKey, marshalutil.New(marshalutil.TimeSize).WriteTime(persistedTime).Bytes()); err != nil {
+		err = xerrors.Errorf("failed to store the persisted time: %w", err)
+	}
+
+	return
+}
+
+// PersistedTime returns the time up to which the stored BaseManaVectors contain the booked transactions. The returned
+// flag is false if no BaseManaVectors have been persisted, yet.
+func (s *Storage) PersistedTime() (persistedTime time.Time, exists bool, err error) {
+	persistedTimeBytes, err := s.store.Get(persistedTimeKey)
+	if err != nil {
+		if xerrors.Is(err, kvstore.ErrKeyNotFound) {
+			err = nil
+			return
+		}
+		err = xerrors.Errorf("failed to load the persisted time: %w", err)
+		return
+	}
+
+	if persistedTime, err = marshalutil.New(persistedTimeBytes).ReadTime(); err != nil {
+		err = xerrors.Errorf("failed to parse the persisted time (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	exists = true
+
+	return
+}
+
+// StoreSnapshot persists the given Snapshot.
+func (s *Storage) StoreSnapshot(snapshot *Snapshot) {
+	snapshot.Persist()
+	snapshot.SetModified()
+	s.snapshotStorage.Store(snapshot).Release()
+}
+
+// Snapshot returns the most recent Snapshot of the given Type that was taken at or before the given time.
+func (s *Storage) Snapshot(manaType Type, t time.Time) (snapshot *Snapshot, err error) {
+	s.snapshotStorage.ForEach(func(key []byte, cachedObject objectstorage.CachedObject) bool {
+		cachedObject.Consume(func(object objectstorage.StorableObject) {
+			candidate := object.(*Snapshot)
+			if candidate.Timestamp().After(t) {
+				return
+			}
+			if snapshot == nil || candidate.Timestamp().After(snapshot.Timestamp()) {
+				snapshot = candidate
+			}
+		})
+
+		return true
+	}, []byte{byte(manaType)})
+
+	if snapshot == nil {
+		err = xerrors.Errorf("failed to find %s mana snapshot before %s: %w", manaType, t, ErrSnapshotNotFound)
+	}
+
+	return
+}
+
+// PruneSnapshots deletes all the Snapshots that were taken before the given time.
+func (s *Storage) PruneSnapshots(before time.Time) {
+	s.snapshotStorage.ForEach(func(key []byte, cachedObject objectstorage.CachedObject) bool {
+		cachedObject.Consume(func(object objectstorage.StorableObject) {
+			if object.(*Snapshot).Timestamp().Before(before) {
+				object.Delete()
+			}
+		})
+
+		return true
+	})
+}
+
+// Shutdown shuts down the Storage and persists its state.
+func (s *Storage) Shutdown() {
+	s.shutdownOnce.Do(func() {
+		s.persistableBaseManaStorage.Shutdown()
+		s.snapshotStorage.Shutdown()
+	})
+}
@@ -0,0 +1,89 @@
+package mana
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+)
+
+func TestStorage_BaseManaVector(t *testing.T) {
+	store := mapdb.NewMapDB()
+	nodeA, nodeB := randomNodeID(t), randomNodeID(t)
+
+	accessVector := NewBaseManaVector(AccessMana)
+	accessVector.SetMana(nodeA, &BaseMana{BaseMana2: 1, LastUpdated: time.Unix(1000, 0)})
+	consensusVector := NewBaseManaVector(ConsensusMana)
+	consensusVector.SetMana(nodeB, &BaseMana{BaseMana1: 1, LastUpdated: time.Unix(1000, 0)})
+
+	storage := NewStorage(store)
+	storage.StoreBaseManaVector(accessVector)
+	storage.StoreBaseManaVector(consensusVector)
+	storage.Shutdown()
+
+	storage = NewStorage(store)
+	defer storage.Shutdown()
+
+	restoredAccessVector := NewBaseManaVector(AccessMana)
+	require.NoError(t, storage.LoadBaseManaVector(restoredAccessVector))
+	assert.Equal(t, 1, restoredAccessVector.Size())
+	assert.True(t, restoredAccessVector.Has(nodeA))
+
+	restoredConsensusVector := NewBaseManaVector(ConsensusMana)
+	require.NoError(t, storage.LoadBaseManaVector(restoredConsensusVector))
+	assert.Equal(t, 1, restoredConsensusVector.Size())
+	assert.True(t, restoredConsensusVector.Has(nodeB))
+}
+
+func TestStorage_PersistedTime(t *testing.T) {
+	store := mapdb.NewMapDB()
+
+	storage := NewStorage(store)
+	_, exists, err := storage.PersistedTime()
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, storage.StorePersistedTime(time.Unix(1000, 0)))
+	storage.Shutdown()
+
+	storage = NewStorage(store)
+	defer storage.Shutdown()
+
+	persistedTime, exists, err := storage.PersistedTime()
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.True(t, time.Unix(1000, 0).Equal(persistedTime))
+}
+
+func TestStorage_Snapshot(t *testing.T) {
+	storage := NewStorage(mapdb.NewMapDB())
+	defer storage.Shutdown()
+
+	nodeA := randomNodeID(t)
+	start := time.Unix(1000, 0)
+
+	bmv := NewBaseManaVector(AccessMana)
+	bmv.SetMana(nodeA, &BaseMana{EffectiveBaseMana2: 10, LastUpdated: start})
+	for i := 0; i < 3; i++ {
+		storage.StoreSnapshot(NewSnapshot(bmv, start.Add(time.Duration(i)*time.Hour)))
+	}
+
+	_, err := storage.Snapshot(AccessMana, start.Add(-time.Second))
+	assert.True(t, xerrors.Is(err, ErrSnapshotNotFound))
+	_, err = storage.Snapshot(ConsensusMana, start.Add(time.Hour))
+	assert.True(t, xerrors.Is(err, ErrSnapshotNotFound))
+
+	snapshot, err := storage.Snapshot(AccessMana, start.Add(90*time.Minute))
+	require.NoError(t, err)
+	assert.True(t, start.Add(time.Hour).Equal(snapshot.Timestamp()))
+	assert.Contains(t, snapshot.NodeMap(), nodeA)
+
+	storage.PruneSnapshots(start.Add(2 * time.Hour))
+	_, err = storage.Snapshot(AccessMana, start.Add(90*time.Minute))
+	assert.True(t, xerrors.Is(err, ErrSnapshotNotFound))
+	_, err = storage.Snapshot(AccessMana, start.Add(2*time.Hour))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,32 @@
+package mana
+
+import (
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/identity"
+)
+
+// TxInfo contains the information of a Transaction that is needed to book its mana.
+type TxInfo struct {
+	// TimeStamp is the timestamp of the Transaction.
+	TimeStamp time.Time
+	// TransactionID is the identifier of the Transaction.
+	TransactionID ledgerstate.TransactionID
+	// TotalBalance is the sum of the balances of all the consumed Inputs.
+	TotalBalance float64
+	// PledgeID contains the node that the Transaction pledges its mana to for every Type.
+	PledgeID map[Type]identity.ID
+	// InputInfos contains the details of the consumed Inputs.
+	InputInfos []InputInfo
+}
+
+// InputInfo contains the information of a consumed Input that is needed to book mana.
+type InputInfo struct {
+	// TimeStamp is the timestamp of the Transaction that created the Input.
+	TimeStamp time.Time
+	// Amount is the sum of all the balances of the Input.
+	Amount float64
+	// PledgeID contains the node that the Transaction which created the Input pledged its mana to for every Type.
+	PledgeID map[Type]identity.ID
+}
@@ -0,0 +1,43 @@
+package mana
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// AccessMana is the type of mana that is used to gain access to the network (i.e. to issue messages).
+	AccessMana Type = iota
+
+	// ConsensusMana is the type of mana that is used to weight the opinions of nodes in the consensus.
+	ConsensusMana
+)
+
+// Type defines the type of mana that is tracked by a BaseManaVector.
+type Type uint8
+
+// TypeFromString parses a Type from its string representation ("access" or "consensus").
+func TypeFromString(typeString string) (Type, error) {
+	switch strings.ToLower(typeString) {
+	case "access":
+		return AccessMana, nil
+	case "consensus":
+		return ConsensusMana, nil
+	default:
+		return 0, xerrors.Errorf("unknown mana type '%s': %w", typeString, ErrUnknownManaType)
+	}
+}
+
+// String returns a human readable version of the Type.
+func (t Type) String() string {
+	switch t {
+	case AccessMana:
+		return "Access"
+	case ConsensusMana:
+		return "Consensus"
+	default:
+		return fmt.Sprintf("Type(%X)", uint8(t))
+	}
+}
@@ -0,0 +1,312 @@
+package manualpeering
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/gossip"
+	"github.com/iotaledger/hive.go/autopeering/peer"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/identity"
+	"github.com/iotaledger/hive.go/logger"
+	"golang.org/x/xerrors"
+)
+
+// defaultReconnectInterval defines how often the Manager tries to connect to the known peers that are not connected.
+const defaultReconnectInterval = 5 * time.Second
+
+// ErrNoGossip is returned when a peer without a gossip service is added.
+var ErrNoGossip = xerrors.New("peer does not have a gossip service")
+
+// region Manager //////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Manager keeps the gossip connections to a set of manually configured peers alive, independently of the autopeering.
+// Both peers have to know each other: the peer with the smaller ID establishes the connection, while the other one
+// accepts it.
+type Manager struct {
+	gossipManager     *gossip.Manager
+	local             *peer.Local
+	log               *logger.Logger
+	reconnectInterval time.Duration
+
+	knownPeers      map[identity.ID]*knownPeer
+	knownPeersMutex sync.RWMutex
+
+	neighborRemovedClosure *events.Closure
+	reconnect              chan struct{}
+	startOnce              sync.Once
+	stopOnce               sync.Once
+	stopping               chan struct{}
+	wg                     sync.WaitGroup
+}
+
+// Option is a function setting an optional parameter of the Manager.
+type Option func(m *Manager)
+
+// ReconnectInterval is an Option that sets how often the Manager tries to reconnect to disconnected peers.
+func ReconnectInterval(interval time.Duration) Option {
+	return func(m *Manager) {
+		m.reconnectInterval = interval
+	}
+}
+
+// NewManager creates a new Manager that uses the given gossip Manager to connect to the known peers.
+func NewManager(gossipManager *gossip.Manager, local *peer.Local, log *logger.Logger, opts ...Option) *Manager {
+	m := &Manager{
+		gossipManager:     gossipManager,
+		local:             local,
+		log:               log,
+		reconnectInterval: defaultReconnectInterval,
+		knownPeers:        make(map[identity.ID]*knownPeer),
+		reconnect:         make(chan struct{}, 1),
+		stopping:          make(chan struct{}),
+	}
+	m.neighborRemovedClosure = events.NewClosure(func(nbr *gossip.Neighbor) {
+		if m.isKnown(nbr.ID()) {
+			m.triggerReconnect()
+		}
+	})
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// Start starts connecting to the known peers.
+func (m *Manager) Start() {
+	m.startOnce.Do(func() {
+		m.gossipManager.Events().NeighborRemoved.Attach(m.neighborRemovedClosure)
+
+		m.wg.Add(1)
+		go m.run()
+	})
+}
+
+// Stop stops connecting to the known peers. Already established connections are kept.
+func (m *Manager) Stop() {
+	m.stopOnce.Do(func() {
+		m.gossipManager.Events().NeighborRemoved.Detach(m.neighborRemovedClosure)
+
+		close(m.stopping)
+		m.wg.Wait()
+	})
+}
+
+// AddPeer adds the given peers to the known peers and tries to connect to them.
+func (m *Manager) AddPeer(peers ...*peer.Peer) error {
+	for _, p := range peers {
+		if !gossip.IsSupported(p) {
+			return xerrors.Errorf("failed to add peer %s: %w", p.ID(), ErrNoGossip)
+		}
+	}
+
+	m.knownPeersMutex.Lock()
+	for _, p := range peers {
+		if p.ID() == m.local.ID() {
+			continue
+		}
+		if _, exists := m.knownPeers[p.ID()]; !exists {
+			m.knownPeers[p.ID()] = &knownPeer{peer: p}
+		}
+	}
+	m.knownPeersMutex.Unlock()
+
+	m.triggerReconnect()
+
+	return nil
+}
+
+// RemovePeer removes the peers with the given public keys from the known peers and drops their connections.
+func (m *Manager) RemovePeer(keys ...ed25519.PublicKey) error {
+	for _, key := range keys {
+		id := identity.NewID(key)
+
+		m.knownPeersMutex.Lock()
+		delete(m.knownPeers, id)
+		m.knownPeersMutex.Unlock()
+
+		if err := m.gossipManager.DropNeighbor(id, gossip.NeighborsGroupManual); err != nil && !xerrors.Is(err, gossip.ErrUnknownNeighbor) {
+			return xerrors.Errorf("failed to drop neighbor %s: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// GetPeers returns the known peers together with the state of their connection.
+func (m *Manager) GetPeers() []*KnownPeer {
+	connected := make(map[identity.ID]bool)
+	for _, nbr := range m.gossipManager.AllNeighbors() {
+		connected[nbr.ID()] = true
+	}
+
+	m.knownPeersMutex.RLock()
+	defer m.knownPeersMutex.RUnlock()
+
+	result := make([]*KnownPeer, 0, len(m.knownPeers))
+	for id, kp := range m.knownPeers {
+		status := ConnectionStatusDisconnected
+		if connected[id] {
+			status = ConnectionStatusConnected
+		}
+
+		result = append(result, &KnownPeer{
+			PublicKey:        kp.peer.PublicKey(),
+			Address:          gossip.GetAddress(kp.peer),
+			Direction:        m.direction(kp.peer),
+			ConnectionStatus: status,
+		})
+	}
+
+	return result
+}
+
+func (m *Manager) run() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.reconnectInterval)
+	defer ticker.Stop()
+
+	m.connectAll()
+	for {
+		select {
+		case <-ticker.C:
+			m.connectAll()
+		case <-m.reconnect:
+			m.connectAll()
+		case <-m.stopping:
+			return
+		}
+	}
+}
+
+// connectAll starts to connect to all known peers that are neither connected nor connecting.
+func (m *Manager) connectAll() {
+	connected := make(map[identity.ID]bool)
+	for _, nbr := range m.gossipManager.AllNeighbors() {
+		connected[nbr.ID()] = true
+	}
+
+	m.knownPeersMutex.Lock()
+	defer m.knownPeersMutex.Unlock()
+
+	for id, kp := range m.knownPeers {
+		if connected[id] || kp.connecting {
+			continue
+		}
+
+		kp.connecting = true
+		m.wg.Add(1)
+		go m.connect(kp)
+	}
+}
+
+func (m *Manager) connect(kp *knownPeer) {
+	defer m.wg.Done()
+
+	var err error
+	if m.direction(kp.peer) == ConnectionDirectionOutbound {
+		err = m.gossipManager.AddOutbound(kp.peer, gossip.NeighborsGroupManual)
+	} else {
+		err = m.gossipManager.AddInbound(kp.peer, gossip.NeighborsGroupManual)
+	}
+	if err != nil {
+		m.log.Debugw("failed to connect to known peer", "id", kp.peer.ID(), "err", err)
+	}
+
+	m.knownPeersMutex.Lock()
+	kp.connecting = false
+	removed := m.knownPeers[kp.peer.ID()] != kp
+	m.knownPeersMutex.Unlock()
+
+	// the peer might have been removed while the connection was established
+	if err == nil && removed {
+		_ = m.gossipManager.DropNeighbor(kp.peer.ID(), gossip.NeighborsGroupManual)
+	}
+}
+
+// direction returns the direction of the connection to the given peer.
+func (m *Manager) direction(p *peer.Peer) ConnectionDirection {
+	if bytes.Compare(m.local.ID().Bytes(), p.ID().Bytes()) < 0 {
+		return ConnectionDirectionOutbound
+	}
+
+	return ConnectionDirectionInbound
+}
+
+func (m *Manager) isKnown(id identity.ID) bool {
+	m.knownPeersMutex.RLock()
+	defer m.knownPeersMutex.RUnlock()
+
+	_, known := m.knownPeers[id]
+	return known
+}
+
+func (m *Manager) triggerReconnect() {
+	select {
+	case m.reconnect <- struct{}{}:
+	default:
+	}
+}
+
+// knownPeer contains a manually configured peer and whether a connection attempt is in progress.
+type knownPeer struct {
+	peer       *peer.Peer
+	connecting bool
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region KnownPeer ////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// KnownPeer contains the information about a manually configured peer.
+type KnownPeer struct {
+	PublicKey        ed25519.PublicKey
+	Address          string
+	Direction        ConnectionDirection
+	ConnectionStatus ConnectionStatus
+}
+
+// ConnectionDirection is the type of the direction of a connection to a known peer.
+type ConnectionDirection uint8
+
+const (
+	// ConnectionDirectionOutbound means that the local node establishes the connection.
+	ConnectionDirectionOutbound ConnectionDirection = iota
+	// ConnectionDirectionInbound means that the local node accepts the connection from the known peer.
+	ConnectionDirectionInbound
+)
+
+// String returns a human readable version of the ConnectionDirection.
+func (c ConnectionDirection) String() string {
+	if c == ConnectionDirectionOutbound {
+		return "outbound"
+	}
+
+	return "inbound"
+}
+
+// ConnectionStatus is the type of the status of the connection to a known peer.
+type ConnectionStatus uint8
+
+const (
+	// ConnectionStatusDisconnected means that there is no gossip connection to the known peer.
+	ConnectionStatusDisconnected ConnectionStatus = iota
+	// ConnectionStatusConnected means that the known peer is a neighbor.
+	ConnectionStatusConnected
+)
+
+// String returns a human readable version of the ConnectionStatus.
+func (c ConnectionStatus) String() string {
+	if c == ConnectionStatusConnected {
+		return "connected"
+	}
+
+	return "disconnected"
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
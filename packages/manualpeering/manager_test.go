@@ -0,0 +1,118 @@
+package manualpeering
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/gossip"
+	"github.com/iotaledger/goshimmer/packages/gossip/server"
+	"github.com/iotaledger/goshimmer/packages/tangle"
+	"github.com/iotaledger/hive.go/autopeering/peer"
+	"github.com/iotaledger/hive.go/autopeering/peer/service"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+)
+
+const (
+	testReconnectInterval = 100 * time.Millisecond
+	testTimeout           = 5 * time.Second
+)
+
+var log = logger.NewExampleLogger("manualpeering")
+
+func TestManager(t *testing.T) {
+	mgrA, gossipA, closeA := newTestManager(t, "A")
+	defer closeA()
+	mgrB, gossipB, closeB := newTestManager(t, "B")
+	defer closeB()
+
+	// the peers only connect once both of them know each other
+	require.NoError(t, mgrA.AddPeer(mgrB.local.Peer))
+	require.NoError(t, mgrB.AddPeer(mgrA.local.Peer))
+
+	assert.Eventually(t, func() bool {
+		return isManualNeighbor(gossipA, mgrB.local.Peer) && isManualNeighbor(gossipB, mgrA.local.Peer)
+	}, testTimeout, testReconnectInterval)
+
+	peers := mgrA.GetPeers()
+	require.Len(t, peers, 1)
+	assert.Equal(t, mgrB.local.PublicKey(), peers[0].PublicKey)
+	assert.Equal(t, ConnectionStatusConnected, peers[0].ConnectionStatus)
+	assert.NotEqual(t, mgrB.GetPeers()[0].Direction, peers[0].Direction)
+
+	// dropped connections are reestablished
+	require.NoError(t, gossipA.DropNeighbor(mgrB.local.ID(), gossip.NeighborsGroupManual))
+	assert.Eventually(t, func() bool {
+		return isManualNeighbor(gossipA, mgrB.local.Peer) && isManualNeighbor(gossipB, mgrA.local.Peer)
+	}, testTimeout, testReconnectInterval)
+
+	// removed peers are dropped and not reconnected
+	require.NoError(t, mgrA.RemovePeer(mgrB.local.PublicKey()))
+	assert.Empty(t, mgrA.GetPeers())
+	assert.Eventually(t, func() bool { return len(gossipB.AllNeighbors()) == 0 }, testTimeout, testReconnectInterval)
+	time.Sleep(3 * testReconnectInterval)
+	assert.Empty(t, gossipA.AllNeighbors())
+}
+
+func TestAddPeerWithoutGossip(t *testing.T) {
+	mgr, _, teardown := newTestManager(t, "A")
+	defer teardown()
+
+	services := service.New()
+	services.Update(service.PeeringKey, "peering", 0)
+	local, err := peer.NewLocal(net.IPv4zero, services, newTestDB(t))
+	require.NoError(t, err)
+
+	assert.True(t, xerrors.Is(mgr.AddPeer(local.Peer), ErrNoGossip))
+	assert.Empty(t, mgr.GetPeers())
+}
+
+func isManualNeighbor(mgr *gossip.Manager, p *peer.Peer) bool {
+	for _, nbr := range mgr.AllNeighbors() {
+		if nbr.ID() == p.ID() {
+			return nbr.Group() == gossip.NeighborsGroupManual
+		}
+	}
+	return false
+}
+
+func newTestDB(t require.TestingT) *peer.DB {
+	db, err := peer.NewDB(mapdb.NewMapDB())
+	require.NoError(t, err)
+	return db
+}
+
+func newTestManager(t require.TestingT, name string) (*Manager, *gossip.Manager, func()) {
+	l := log.Named(name)
+
+	laddr, err := net.ResolveTCPAddr("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	lis, err := net.ListenTCP("tcp", laddr)
+	require.NoError(t, err)
+
+	services := service.New()
+	services.Update(service.PeeringKey, "peering", 0)
+	services.Update(service.GossipKey, lis.Addr().Network(), lis.Addr().(*net.TCPAddr).Port)
+
+	local, err := peer.NewLocal(lis.Addr().(*net.TCPAddr).IP, services, newTestDB(t))
+	require.NoError(t, err)
+
+	srv := server.ServeTCP(local, lis, l)
+	gossipMgr := gossip.NewManager(local, func(tangle.MessageID) ([]byte, error) { return nil, nil }, l)
+	gossipMgr.Start(srv)
+
+	mgr := NewManager(gossipMgr, local, l, ReconnectInterval(testReconnectInterval))
+	mgr.Start()
+
+	teardown := func() {
+		mgr.Stop()
+		gossipMgr.Close()
+		srv.Close()
+		_ = lis.Close()
+	}
+	return mgr, gossipMgr, teardown
+}
@@ -0,0 +1,61 @@
+package manualpeering
+
+import (
+	"net"
+	"strings"
+
+	"github.com/iotaledger/hive.go/autopeering/peer"
+	"github.com/iotaledger/hive.go/autopeering/peer/service"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/hive.go/identity"
+	"github.com/mr-tron/base58"
+	"golang.org/x/xerrors"
+)
+
+// ErrInvalidPeerDefinition is returned when a peer cannot be parsed from its definition.
+var ErrInvalidPeerDefinition = xerrors.New("invalid peer definition")
+
+// NewPeer creates a peer with the given public key that offers its gossip service at the given address ("host:port").
+func NewPeer(publicKey ed25519.PublicKey, gossipAddress string) (*peer.Peer, error) {
+	addr, err := net.ResolveTCPAddr("tcp", gossipAddress)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to resolve gossip address %s: %w", gossipAddress, ErrInvalidPeerDefinition)
+	}
+
+	services := service.New()
+	// the peering service is required by every peer, but it is not used for manual peering
+	services.Update(service.PeeringKey, "udp", 0)
+	services.Update(service.GossipKey, addr.Network(), addr.Port)
+
+	return peer.NewPeer(identity.New(publicKey), addr.IP, services), nil
+}
+
+// ParsePeer parses a peer from its definition in the form "base58PublicKey@host:port", where the address is the one
+// of the gossip service.
+func ParsePeer(definition string) (*peer.Peer, error) {
+	parts := strings.Split(definition, "@")
+	if len(parts) != 2 {
+		return nil, xerrors.Errorf("peer definition must consist of 2 parts, is %d: %w", len(parts), ErrInvalidPeerDefinition)
+	}
+
+	publicKey, err := ParsePublicKey(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPeer(publicKey, parts[1])
+}
+
+// ParsePublicKey parses a base58 encoded public key.
+func ParsePublicKey(base58EncodedPublicKey string) (ed25519.PublicKey, error) {
+	publicKeyBytes, err := base58.Decode(base58EncodedPublicKey)
+	if err != nil {
+		return ed25519.PublicKey{}, xerrors.Errorf("invalid public key %s: %w", base58EncodedPublicKey, ErrInvalidPeerDefinition)
+	}
+	publicKey, _, err := ed25519.PublicKeyFromBytes(publicKeyBytes)
+	if err != nil {
+		return ed25519.PublicKey{}, xerrors.Errorf("invalid public key %s: %w", base58EncodedPublicKey, ErrInvalidPeerDefinition)
+	}
+
+	return publicKey, nil
+}
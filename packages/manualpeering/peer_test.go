@@ -0,0 +1,27 @@
+package manualpeering
+
+import (
+	"testing"
+
+	"github.com/iotaledger/goshimmer/packages/gossip"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/hive.go/identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+)
+
+func TestParsePeer(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey()
+	require.NoError(t, err)
+
+	p, err := ParsePeer(publicKey.String() + "@127.0.0.1:14666")
+	require.NoError(t, err)
+	assert.Equal(t, identity.NewID(publicKey), p.ID())
+	assert.Equal(t, "127.0.0.1:14666", gossip.GetAddress(p))
+
+	for _, definition := range []string{"", "127.0.0.1:14666", publicKey.String(), "foo@127.0.0.1:14666", publicKey.String() + "@foo"} {
+		_, err = ParsePeer(definition)
+		assert.True(t, xerrors.Is(err, ErrInvalidPeerDefinition), definition)
+	}
+}
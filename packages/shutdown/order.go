@@ -3,8 +3,12 @@ package shutdown
 const (
 	// PriorityDatabase defines the shutdown priority for the database.
 	PriorityDatabase = iota
+	// PriorityMana defines the shutdown priority for the mana plugin.
+	PriorityMana
 	// PriorityTangle defines the shutdown priority for the tangle.
 	PriorityTangle
+	// PriorityLocalSnapshot defines the shutdown priority for the local snapshots.
+	PriorityLocalSnapshot
 	// PriorityValueTangle defines the shutdown priority for the value tangle.
 	PriorityFPC
 	// PriorityFaucet defines the shutdown priority for the faucet.
@@ -21,6 +25,8 @@ const (
 	PriorityAutopeering
 	// PriorityGossip defines the shutdown priority for gossip.
 	PriorityGossip
+	// PriorityManualPeering defines the shutdown priority for manual peering.
+	PriorityManualPeering
 	// PriorityWebAPI defines the shutdown priority for webapi.
 	PriorityWebAPI
 	// PriorityDashboard defines the shutdown priority for dashboard.
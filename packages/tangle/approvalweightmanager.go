@@ -0,0 +1,1131 @@
+package tangle
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/goshimmer/packages/markers"
+	"github.com/iotaledger/hive.go/byteutils"
+	"github.com/iotaledger/hive.go/cerrors"
+	"github.com/iotaledger/hive.go/datastructure/walker"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/identity"
+	"github.com/iotaledger/hive.go/marshalutil"
+	"github.com/iotaledger/hive.go/objectstorage"
+	"github.com/iotaledger/hive.go/stringify"
+	"github.com/iotaledger/hive.go/types"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// DefaultConfirmationThreshold defines the default share of the total consensus mana that needs to approve a Marker
+	// for it to be confirmed.
+	DefaultConfirmationThreshold = 0.66
+)
+
+// region ApprovalWeightManager ////////////////////////////////////////////////////////////////////////////////////////
+
+// ApprovalWeightManager is a Tangle component that keeps track of the issuers (the supporters) that directly or
+// indirectly approve the Markers and Branches of the Tangle. It confirms the Markers (and the Messages in their past
+// cone) and the Branches once the consensus mana of their supporters reaches the configured threshold and it likes the
+// Branch with the highest approval weight of every Conflict.
+type ApprovalWeightManager struct {
+	// Events is a dictionary for the ApprovalWeightManager related Events.
+	Events *ApprovalWeightManagerEvents
+
+	tangle             *Tangle
+	supportMutex       sync.Mutex
+	confirmationsMutex sync.Mutex
+	branchSupportMutex sync.Mutex
+}
+
+// NewApprovalWeightManager is the constructor of the ApprovalWeightManager.
+func NewApprovalWeightManager(tangle *Tangle) (approvalWeightManager *ApprovalWeightManager) {
+	approvalWeightManager = &ApprovalWeightManager{
+		Events: &ApprovalWeightManagerEvents{
+			MarkerConfirmed:  events.NewEvent(markerEventHandler),
+			MessageConfirmed: events.NewEvent(messageIDEventHandler),
+			BranchConfirmed:  events.NewEvent(branchIDEventHandler),
+		},
+		tangle: tangle,
+	}
+
+	return
+}
+
+// Setup sets up the behavior of the component by making it attach to the relevant events of other components.
+func (a *ApprovalWeightManager) Setup() {
+	a.tangle.Booker.Events.MessageBooked.Attach(events.NewClosure(a.ProcessMessage))
+}
+
+// ProcessMessage adds the issuer of the given (booked) Message as a supporter of all the Markers in its past cone and
+// of the Branches that the Message is booked into. It confirms the Markers and Branches whose approval weight reaches
+// the threshold.
+func (a *ApprovalWeightManager) ProcessMessage(messageID MessageID) {
+	var supporter identity.ID
+	var structureDetails *markers.StructureDetails
+	var branchID ledgerstate.BranchID
+	a.tangle.Storage.Message(messageID).Consume(func(message *Message) {
+		supporter = identity.NewID(message.IssuerPublicKey())
+
+		a.tangle.Storage.MessageMetadata(messageID).Consume(func(messageMetadata *MessageMetadata) {
+			structureDetails = messageMetadata.StructureDetails()
+			branchID = messageMetadata.BranchID()
+		})
+	})
+	if structureDetails == nil {
+		return
+	}
+
+	if structureDetails.IsPastMarker {
+		marker := structureDetails.PastMarkers.FirstMarker()
+		if cachedMarkerMessageMapping, stored := a.tangle.Storage.StoreMarkerMessageMapping(NewMarkerMessageMapping(marker, messageID)); stored {
+			cachedMarkerMessageMapping.Release()
+		}
+	}
+
+	structureDetails.PastMarkers.ForEach(func(sequenceID markers.SequenceID, index markers.Index) bool {
+		a.addSupportToMarker(markers.NewMarker(sequenceID, index), supporter)
+		return true
+	})
+
+	for supportedBranchID := range a.supportedConflictBranchIDs(branchID, structureDetails.PastMarkers) {
+		a.addSupportToBranch(supportedBranchID, supporter)
+	}
+}
+
+// Weight returns the share of the total consensus mana that supports the given Marker.
+func (a *ApprovalWeightManager) Weight(marker *markers.Marker) (weight float64) {
+	return a.weight(marker, a.tangle.Options.ApprovalWeightParams.TotalConsensusManaRetrieveFunc())
+}
+
+// weight is an internal utility function that returns the share of the given total consensus mana that supports the
+// given Marker, so that the total consensus mana only has to be retrieved once when evaluating multiple Markers.
+func (a *ApprovalWeightManager) weight(marker *markers.Marker, totalConsensusMana float64) (weight float64) {
+	if totalConsensusMana <= 0 {
+		return 0
+	}
+
+	a.tangle.Storage.SequenceSupporters(marker.SequenceID()).Consume(func(sequenceSupporters *SequenceSupporters) {
+		for _, supporter := range sequenceSupporters.Supporters(marker.Index()) {
+			weight += a.tangle.Options.ApprovalWeightParams.ConsensusManaRetrieveFunc(supporter)
+		}
+	})
+
+	return weight / totalConsensusMana
+}
+
+// BranchWeight returns the share of the total consensus mana that supports the given ConflictBranch.
+func (a *ApprovalWeightManager) BranchWeight(branchID ledgerstate.BranchID) (weight float64) {
+	return a.branchWeight(branchID, a.tangle.Options.ApprovalWeightParams.TotalConsensusManaRetrieveFunc())
+}
+
+// branchWeight is an internal utility function that returns the share of the given total consensus mana that supports
+// the given ConflictBranch.
+func (a *ApprovalWeightManager) branchWeight(branchID ledgerstate.BranchID, totalConsensusMana float64) (weight float64) {
+	if totalConsensusMana <= 0 {
+		return 0
+	}
+
+	a.tangle.Storage.BranchSupporters(branchID).Consume(func(branchSupporters *BranchSupporters) {
+		for _, supporter := range branchSupporters.Supporters() {
+			weight += a.tangle.Options.ApprovalWeightParams.ConsensusManaRetrieveFunc(supporter)
+		}
+	})
+
+	return weight / totalConsensusMana
+}
+
+// IsMarkerConfirmed returns true if the given Marker was confirmed.
+func (a *ApprovalWeightManager) IsMarkerConfirmed(marker *markers.Marker) (confirmed bool) {
+	a.tangle.Storage.SequenceSupporters(marker.SequenceID()).Consume(func(sequenceSupporters *SequenceSupporters) {
+		confirmed = marker.Index() <= sequenceSupporters.ConfirmedIndex()
+	})
+
+	return
+}
+
+// IsMessageConfirmed returns true if the Message with the given MessageID was confirmed.
+func (a *ApprovalWeightManager) IsMessageConfirmed(messageID MessageID) (confirmed bool) {
+	a.tangle.Storage.MessageMetadata(messageID).Consume(func(messageMetadata *MessageMetadata) {
+		confirmed = messageMetadata.IsConfirmed()
+	})
+
+	return
+}
+
+// addSupportToMarker adds the supporter to the given Marker and propagates the support to the Markers in its past cone.
+// The Markers with a lower Index of the same Sequence are supported implicitly, while the Markers of other Sequences are
+// derived from the StructureDetails of the parents of the Messages that represent the newly supported Markers.
+func (a *ApprovalWeightManager) addSupportToMarker(marker *markers.Marker, supporter identity.ID) {
+	supportWalker := walker.New(false)
+	supportWalker.Push(marker)
+
+	for supportWalker.HasNext() {
+		currentMarker := supportWalker.Next().(*markers.Marker)
+
+		previouslySupportedIndex, supportIncreased := a.addSupportToSequence(currentMarker, supporter)
+		if !supportIncreased {
+			continue
+		}
+		a.checkConfirmations(currentMarker.SequenceID())
+
+		for index := currentMarker.Index(); index > previouslySupportedIndex; index-- {
+			a.parentMarkers(markers.NewMarker(currentMarker.SequenceID(), index)).ForEach(func(sequenceID markers.SequenceID, index markers.Index) bool {
+				if sequenceID != currentMarker.SequenceID() {
+					supportWalker.Push(markers.NewMarker(sequenceID, index))
+				}
+				return true
+			})
+		}
+	}
+}
+
+// addSupportToSequence adds the supporter to the SequenceSupporters of the given Marker and returns true if the support
+// was increased (which means that it has to be propagated further). It also returns the Index up to which the support
+// was already propagated before (the Markers up to the confirmed Index do not need to be propagated anymore).
+func (a *ApprovalWeightManager) addSupportToSequence(marker *markers.Marker, supporter identity.ID) (previouslySupportedIndex markers.Index, supportIncreased bool) {
+	a.supportMutex.Lock()
+	defer a.supportMutex.Unlock()
+
+	a.tangle.Storage.SequenceSupporters(marker.SequenceID(), a.newSequenceSupporters).Consume(func(sequenceSupporters *SequenceSupporters) {
+		previouslySupportedIndex = sequenceSupporters.ConfirmedIndex()
+		if supportedIndex, exists := sequenceSupporters.SupportedIndex(supporter); exists && supportedIndex > previouslySupportedIndex {
+			previouslySupportedIndex = supportedIndex
+		}
+
+		supportIncreased = sequenceSupporters.AddSupporter(supporter, marker.Index())
+	})
+
+	return
+}
+
+// parentMarkers returns the Markers that are referenced by the parents of the Message that represents the given Marker.
+func (a *ApprovalWeightManager) parentMarkers(marker *markers.Marker) (parentMarkers *markers.Markers) {
+	parentMarkers = markers.NewMarkers()
+	a.tangle.Storage.MarkerMessageMapping(marker).Consume(func(markerMessageMapping *MarkerMessageMapping) {
+		a.tangle.Storage.Message(markerMessageMapping.MessageID()).Consume(func(message *Message) {
+			for _, strongParentMessageID := range message.StrongParents() {
+				a.tangle.Storage.MessageMetadata(strongParentMessageID).Consume(func(messageMetadata *MessageMetadata) {
+					if structureDetails := messageMetadata.StructureDetails(); structureDetails != nil {
+						parentMarkers.Merge(structureDetails.PastMarkers)
+					}
+				})
+			}
+		})
+	})
+
+	return
+}
+
+// checkConfirmations confirms all the Markers of the given Sequence (starting with the lowest unconfirmed one) whose
+// approval weight reached the confirmation threshold. Since the supporters of a Marker are always a superset of the
+// supporters of the Markers with a higher Index, we can stop at the first Marker that is not confirmed.
+func (a *ApprovalWeightManager) checkConfirmations(sequenceID markers.SequenceID) {
+	a.confirmationsMutex.Lock()
+	defer a.confirmationsMutex.Unlock()
+
+	totalConsensusMana := a.tangle.Options.ApprovalWeightParams.TotalConsensusManaRetrieveFunc()
+	confirmedMarkers := make([]*markers.Marker, 0)
+	a.tangle.Storage.SequenceSupporters(sequenceID).Consume(func(sequenceSupporters *SequenceSupporters) {
+		for index := sequenceSupporters.ConfirmedIndex() + 1; index <= sequenceSupporters.HighestSupportedIndex(); index++ {
+			marker := markers.NewMarker(sequenceID, index)
+			if a.weight(marker, totalConsensusMana) < a.tangle.Options.ApprovalWeightParams.ConfirmationThreshold {
+				return
+			}
+
+			sequenceSupporters.SetConfirmedIndex(index)
+			confirmedMarkers = append(confirmedMarkers, marker)
+		}
+	})
+
+	for _, confirmedMarker := range confirmedMarkers {
+		a.Events.MarkerConfirmed.Trigger(confirmedMarker)
+		a.confirmPastCone(confirmedMarker)
+	}
+}
+
+// confirmPastCone marks the Message that represents the given Marker and all the unconfirmed Messages in its past cone
+// as confirmed. The past cone is determined by the MarkersManager using the StructureDetails of the Messages.
+func (a *ApprovalWeightManager) confirmPastCone(marker *markers.Marker) {
+	var messageID MessageID
+	if !a.tangle.Storage.MarkerMessageMapping(marker).Consume(func(markerMessageMapping *MarkerMessageMapping) {
+		messageID = markerMessageMapping.MessageID()
+	}) {
+		return
+	}
+
+	var markerStructureDetails *markers.StructureDetails
+	a.tangle.Storage.MessageMetadata(messageID).Consume(func(messageMetadata *MessageMetadata) {
+		markerStructureDetails = messageMetadata.StructureDetails()
+	})
+	if markerStructureDetails == nil {
+		return
+	}
+
+	a.tangle.Utils.WalkMessageAndMetadata(func(message *Message, messageMetadata *MessageMetadata, walker *walker.Walker) {
+		if message.ID() != messageID && !a.inPastCone(messageMetadata.StructureDetails(), markerStructureDetails) {
+			return
+		}
+		if !messageMetadata.SetConfirmed(true) {
+			return
+		}
+		a.Events.MessageConfirmed.Trigger(message.ID())
+
+		message.ForEachParent(func(parent Parent) {
+			walker.Push(parent.ID)
+		})
+	}, MessageIDs{messageID})
+}
+
+// inPastCone is an internal utility function that checks if the Message with the given earlier StructureDetails is
+// part of the past cone of the Message with the given later StructureDetails. Entry points of the Tangle (the genesis
+// and the solid entry points) have no Markers and are never part of it.
+func (a *ApprovalWeightManager) inPastCone(earlierStructureDetails, laterStructureDetails *markers.StructureDetails) bool {
+	if earlierStructureDetails == nil || earlierStructureDetails.PastMarkers.Size() == 0 {
+		return false
+	}
+
+	return a.tangle.Booker.MarkersManager.IsInPastCone(earlierStructureDetails, laterStructureDetails) == types.True
+}
+
+// supportedConflictBranchIDs returns the ConflictBranches (including their ancestors) that are supported by a Message
+// that is booked into the given Branch and that references the given Markers.
+func (a *ApprovalWeightManager) supportedConflictBranchIDs(branchID ledgerstate.BranchID, pastMarkers *markers.Markers) (conflictBranchIDs ledgerstate.BranchIDs) {
+	branchIDs := ledgerstate.NewBranchIDs(branchID)
+	pastMarkers.ForEach(func(sequenceID markers.SequenceID, index markers.Index) bool {
+		branchIDs.Add(a.tangle.Booker.MarkerBranchIDMappingManager.BranchID(markers.NewMarker(sequenceID, index)))
+		return true
+	})
+
+	conflictBranchIDs = ledgerstate.NewBranchIDs()
+	branchWalker := walker.New()
+	for supportedBranchID := range branchIDs {
+		branchWalker.Push(supportedBranchID)
+	}
+	for branchWalker.HasNext() {
+		currentBranchID := branchWalker.Next().(ledgerstate.BranchID)
+		if currentBranchID == ledgerstate.MasterBranchID {
+			continue
+		}
+
+		a.tangle.LedgerState.BranchDAG.Branch(currentBranchID).Consume(func(branch ledgerstate.Branch) {
+			if branch.Type() == ledgerstate.ConflictBranchType && len(branch.(*ledgerstate.ConflictBranch).Conflicts()) != 0 {
+				conflictBranchIDs.Add(currentBranchID)
+			}
+
+			for parentBranchID := range branch.Parents() {
+				branchWalker.Push(parentBranchID)
+			}
+		})
+	}
+
+	return
+}
+
+// addSupportToBranch adds the supporter to the given ConflictBranch and removes it from the Branches that are
+// conflicting with it (a supporter can only support one Branch of every Conflict). It then updates the liked and
+// finalized flags of the ConflictBranch according to its new approval weight.
+func (a *ApprovalWeightManager) addSupportToBranch(branchID ledgerstate.BranchID, supporter identity.ID) {
+	if !a.updateBranchSupporters(branchID, supporter) {
+		return
+	}
+
+	if err := a.updateBranchOpinion(branchID); err != nil {
+		a.tangle.Events.Error.Trigger(xerrors.Errorf("failed to update opinion of Branch with %s: %w", branchID, err))
+	}
+}
+
+// updateBranchSupporters adds the supporter to the BranchSupporters of the given ConflictBranch and removes it from the
+// conflicting Branches. It returns true if the supporter was added.
+func (a *ApprovalWeightManager) updateBranchSupporters(branchID ledgerstate.BranchID, supporter identity.ID) (added bool) {
+	a.branchSupportMutex.Lock()
+	defer a.branchSupportMutex.Unlock()
+
+	a.tangle.Storage.BranchSupporters(branchID, NewBranchSupporters).Consume(func(branchSupporters *BranchSupporters) {
+		added = branchSupporters.AddSupporter(supporter)
+	})
+	if !added {
+		return
+	}
+
+	for conflictingBranchID := range a.conflictingBranchIDs(branchID) {
+		a.tangle.Storage.BranchSupporters(conflictingBranchID).Consume(func(branchSupporters *BranchSupporters) {
+			branchSupporters.DeleteSupporter(supporter)
+		})
+	}
+
+	return
+}
+
+// updateBranchOpinion confirms the given ConflictBranch (and thereby rejects its conflicting Branches) if its approval
+// weight reached the confirmation threshold. Otherwise, it likes the ConflictBranch if it has a higher approval weight
+// than all of its conflicting Branches.
+func (a *ApprovalWeightManager) updateBranchOpinion(branchID ledgerstate.BranchID) (err error) {
+	a.confirmationsMutex.Lock()
+	defer a.confirmationsMutex.Unlock()
+
+	var liked, finalized bool
+	a.tangle.LedgerState.BranchDAG.Branch(branchID).Consume(func(branch ledgerstate.Branch) {
+		liked = branch.Liked()
+		finalized = branch.Finalized()
+	})
+	if finalized {
+		return
+	}
+
+	totalConsensusMana := a.tangle.Options.ApprovalWeightParams.TotalConsensusManaRetrieveFunc()
+	weight := a.branchWeight(branchID, totalConsensusMana)
+	if weight >= a.tangle.Options.ApprovalWeightParams.ConfirmationThreshold {
+		if _, err = a.tangle.LedgerState.BranchDAG.SetBranchLiked(branchID, true); err != nil {
+			return xerrors.Errorf("failed to like Branch with %s: %w", branchID, err)
+		}
+		if _, err = a.tangle.LedgerState.BranchDAG.SetBranchFinalized(branchID, true); err != nil {
+			return xerrors.Errorf("failed to finalize Branch with %s: %w", branchID, err)
+		}
+		a.Events.BranchConfirmed.Trigger(branchID)
+
+		return
+	}
+
+	if liked {
+		return
+	}
+
+	for conflictingBranchID := range a.conflictingBranchIDs(branchID) {
+		conflictingBranchFinalized := false
+		a.tangle.LedgerState.BranchDAG.Branch(conflictingBranchID).Consume(func(branch ledgerstate.Branch) {
+			conflictingBranchFinalized = branch.Finalized()
+		})
+
+		if conflictingBranchFinalized || a.branchWeight(conflictingBranchID, totalConsensusMana) >= weight {
+			return
+		}
+	}
+
+	if _, err = a.tangle.LedgerState.BranchDAG.SetBranchLiked(branchID, true); err != nil {
+		return xerrors.Errorf("failed to like Branch with %s: %w", branchID, err)
+	}
+
+	return
+}
+
+// conflictingBranchIDs returns the BranchIDs of all the Branches that are conflicting with the given ConflictBranch.
+func (a *ApprovalWeightManager) conflictingBranchIDs(branchID ledgerstate.BranchID) (conflictingBranchIDs ledgerstate.BranchIDs) {
+	conflictingBranchIDs = ledgerstate.NewBranchIDs()
+	a.tangle.LedgerState.BranchDAG.Branch(branchID).Consume(func(branch ledgerstate.Branch) {
+		if branch.Type() != ledgerstate.ConflictBranchType {
+			return
+		}
+
+		for conflictID := range branch.(*ledgerstate.ConflictBranch).Conflicts() {
+			a.tangle.LedgerState.BranchDAG.ConflictMembers(conflictID).Consume(func(conflictMember *ledgerstate.ConflictMember) {
+				if conflictMember.BranchID() != branchID {
+					conflictingBranchIDs.Add(conflictMember.BranchID())
+				}
+			})
+		}
+	})
+
+	return
+}
+
+// newSequenceSupporters is an internal utility function that creates the SequenceSupporters of a Sequence that was not
+// supported before.
+func (a *ApprovalWeightManager) newSequenceSupporters(sequenceID markers.SequenceID) (sequenceSupporters *SequenceSupporters) {
+	if !a.tangle.Booker.MarkersManager.Sequence(sequenceID).Consume(func(sequence *markers.Sequence) {
+		sequenceSupporters = NewSequenceSupporters(sequenceID, sequence.LowestIndex()-1)
+	}) {
+		panic(fmt.Sprintf("failed to load Sequence with %s", sequenceID))
+	}
+
+	return
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region ApprovalWeightParams /////////////////////////////////////////////////////////////////////////////////////////
+
+// ApprovalWeightParams represents the parameters for the ApprovalWeightManager.
+type ApprovalWeightParams struct {
+	// ConsensusManaRetrieveFunc returns the consensus mana of the given node that is used to weight its approval.
+	ConsensusManaRetrieveFunc func(identity.ID) float64
+
+	// TotalConsensusManaRetrieveFunc returns the total consensus mana that the approval weight is measured against.
+	TotalConsensusManaRetrieveFunc func() float64
+
+	// ConfirmationThreshold defines the share of the total consensus mana that needs to approve a Marker for it to be
+	// confirmed.
+	ConfirmationThreshold float64
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region ApprovalWeightManagerEvents //////////////////////////////////////////////////////////////////////////////////
+
+// ApprovalWeightManagerEvents represents events happening in the ApprovalWeightManager.
+type ApprovalWeightManagerEvents struct {
+	// MarkerConfirmed is triggered when the approval weight of a Marker reaches the confirmation threshold.
+	MarkerConfirmed *events.Event
+
+	// MessageConfirmed is triggered when a Message is confirmed because it is part of the past cone of a confirmed
+	// Marker.
+	MessageConfirmed *events.Event
+
+	// BranchConfirmed is triggered when the approval weight of a ConflictBranch reaches the confirmation threshold.
+	BranchConfirmed *events.Event
+}
+
+func markerEventHandler(handler interface{}, params ...interface{}) {
+	handler.(func(*markers.Marker))(params[0].(*markers.Marker))
+}
+
+func branchIDEventHandler(handler interface{}, params ...interface{}) {
+	handler.(func(ledgerstate.BranchID))(params[0].(ledgerstate.BranchID))
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region SequenceSupporters ///////////////////////////////////////////////////////////////////////////////////////////
+
+// SequenceSupporters is a data structure that keeps track of the highest Index of a Sequence that every supporter
+// (directly or indirectly) approves. The supporters of a Marker are all the supporters whose highest supported Index is
+// at least as high as the Index of the Marker.
+type SequenceSupporters struct {
+	sequenceID     markers.SequenceID
+	confirmedIndex markers.Index
+	supporters     map[identity.ID]markers.Index
+	mutex          sync.RWMutex
+
+	objectstorage.StorableObjectFlags
+}
+
+// NewSequenceSupporters creates a new SequenceSupporters for the given Sequence whose Markers up to the given Index are
+// considered to be confirmed.
+func NewSequenceSupporters(sequenceID markers.SequenceID, confirmedIndex markers.Index) (sequenceSupporters *SequenceSupporters) {
+	sequenceSupporters = &SequenceSupporters{
+		sequenceID:     sequenceID,
+		confirmedIndex: confirmedIndex,
+		supporters:     make(map[identity.ID]markers.Index),
+	}
+
+	sequenceSupporters.SetModified()
+	sequenceSupporters.Persist()
+
+	return
+}
+
+// SequenceSupportersFromBytes unmarshals a SequenceSupporters object from a sequence of bytes.
+func SequenceSupportersFromBytes(bytes []byte) (sequenceSupporters *SequenceSupporters, consumedBytes int, err error) {
+	marshalUtil := marshalutil.New(bytes)
+	if sequenceSupporters, err = SequenceSupportersFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse SequenceSupporters from MarshalUtil: %w", err)
+		return
+	}
+	consumedBytes = marshalUtil.ReadOffset()
+
+	return
+}
+
+// SequenceSupportersFromMarshalUtil unmarshals a SequenceSupporters object using a MarshalUtil (for easier
+// unmarshaling).
+func SequenceSupportersFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (sequenceSupporters *SequenceSupporters, err error) {
+	sequenceSupporters = &SequenceSupporters{
+		supporters: make(map[identity.ID]markers.Index),
+	}
+	if sequenceSupporters.sequenceID, err = markers.SequenceIDFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse SequenceID from MarshalUtil: %w", err)
+		return
+	}
+	if sequenceSupporters.confirmedIndex, err = markers.IndexFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse confirmed Index from MarshalUtil: %w", err)
+		return
+	}
+	supportersCount, err := marshalUtil.ReadUint32()
+	if err != nil {
+		err = xerrors.Errorf("failed to parse supporters count (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	for i := uint32(0); i < supportersCount; i++ {
+		supporterBytes, supporterErr := marshalUtil.ReadBytes(len(identity.ID{}))
+		if supporterErr != nil {
+			err = xerrors.Errorf("failed to parse supporter (%v): %w", supporterErr, cerrors.ErrParseBytesFailed)
+			return
+		}
+		index, indexErr := markers.IndexFromMarshalUtil(marshalUtil)
+		if indexErr != nil {
+			err = xerrors.Errorf("failed to parse Index from MarshalUtil: %w", indexErr)
+			return
+		}
+
+		var supporter identity.ID
+		copy(supporter[:], supporterBytes)
+		sequenceSupporters.supporters[supporter] = index
+	}
+
+	return
+}
+
+// SequenceSupportersFromObjectStorage restores a SequenceSupporters object that was stored in the object storage.
+func SequenceSupportersFromObjectStorage(key []byte, data []byte) (sequenceSupporters objectstorage.StorableObject, err error) {
+	if sequenceSupporters, _, err = SequenceSupportersFromBytes(byteutils.ConcatBytes(key, data)); err != nil {
+		err = xerrors.Errorf("failed to parse SequenceSupporters from bytes: %w", err)
+		return
+	}
+
+	return
+}
+
+// SequenceID returns the SequenceID of the Sequence that the SequenceSupporters belong to.
+func (s *SequenceSupporters) SequenceID() markers.SequenceID {
+	return s.sequenceID
+}
+
+// AddSupporter sets the highest Index of the Sequence that is supported by the given supporter. It returns true if the
+// support was increased.
+func (s *SequenceSupporters) AddSupporter(supporter identity.ID, index markers.Index) (increased bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if supportedIndex, exists := s.supporters[supporter]; exists && supportedIndex >= index {
+		return false
+	}
+
+	s.supporters[supporter] = index
+	s.SetModified()
+
+	return true
+}
+
+// SupportedIndex returns the highest Index of the Sequence that is supported by the given supporter.
+func (s *SequenceSupporters) SupportedIndex(supporter identity.ID) (index markers.Index, exists bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	index, exists = s.supporters[supporter]
+
+	return
+}
+
+// Supporters returns the supporters of the Marker with the given Index.
+func (s *SequenceSupporters) Supporters(index markers.Index) (supporters []identity.ID) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	supporters = make([]identity.ID, 0)
+	for supporter, supportedIndex := range s.supporters {
+		if supportedIndex >= index {
+			supporters = append(supporters, supporter)
+		}
+	}
+
+	return
+}
+
+// HighestSupportedIndex returns the highest Index of the Sequence that has at least one supporter.
+func (s *SequenceSupporters) HighestSupportedIndex() (highestSupportedIndex markers.Index) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, supportedIndex := range s.supporters {
+		if supportedIndex > highestSupportedIndex {
+			highestSupportedIndex = supportedIndex
+		}
+	}
+
+	return
+}
+
+// ConfirmedIndex returns the highest Index of the Sequence that was confirmed.
+func (s *SequenceSupporters) ConfirmedIndex() markers.Index {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.confirmedIndex
+}
+
+// SetConfirmedIndex sets the highest Index of the Sequence that was confirmed.
+func (s *SequenceSupporters) SetConfirmedIndex(index markers.Index) (modified bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.confirmedIndex >= index {
+		return false
+	}
+
+	s.confirmedIndex = index
+	s.SetModified()
+
+	return true
+}
+
+// Bytes returns a marshaled version of the SequenceSupporters.
+func (s *SequenceSupporters) Bytes() []byte {
+	return byteutils.ConcatBytes(s.ObjectStorageKey(), s.ObjectStorageValue())
+}
+
+// String returns a human readable version of the SequenceSupporters.
+func (s *SequenceSupporters) String() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	supporters := stringify.StructBuilder("Supporters")
+	for supporter, index := range s.supporters {
+		supporters.AddField(stringify.StructField(supporter.String(), index))
+	}
+
+	return stringify.Struct("SequenceSupporters",
+		stringify.StructField("sequenceID", s.sequenceID),
+		stringify.StructField("confirmedIndex", s.confirmedIndex),
+		stringify.StructField("supporters", supporters),
+	)
+}
+
+// Update is disabled and panics if it ever gets called - it is required to match the StorableObject interface.
+func (s *SequenceSupporters) Update(other objectstorage.StorableObject) {
+	panic("updates disabled")
+}
+
+// ObjectStorageKey returns the key that is used to store the object in the database. It is required to match the
+// StorableObject interface.
+func (s *SequenceSupporters) ObjectStorageKey() []byte {
+	return s.sequenceID.Bytes()
+}
+
+// ObjectStorageValue marshals the SequenceSupporters into a sequence of bytes that are used as the value part in the
+// object storage.
+func (s *SequenceSupporters) ObjectStorageValue() []byte {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// sort the supporters to get a deterministic serialization
+	supporters := make([]identity.ID, 0, len(s.supporters))
+	for supporter := range s.supporters {
+		supporters = append(supporters, supporter)
+	}
+	sort.Slice(supporters, func(i, j int) bool {
+		return string(supporters[i][:]) < string(supporters[j][:])
+	})
+
+	marshalUtil := marshalutil.New()
+	marshalUtil.Write(s.confirmedIndex)
+	marshalUtil.WriteUint32(uint32(len(supporters)))
+	for _, supporter := range supporters {
+		marshalUtil.WriteBytes(supporter.Bytes())
+		marshalUtil.Write(s.supporters[supporter])
+	}
+
+	return marshalUtil.Bytes()
+}
+
+// code contract (make sure the type implements all required methods)
+var _ objectstorage.StorableObject = &SequenceSupporters{}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region CachedSequenceSupporters /////////////////////////////////////////////////////////////////////////////////////
+
+// CachedSequenceSupporters is a wrapper for the generic CachedObject returned by the object storage that overrides the
+// accessor methods with a type-casted one.
+type CachedSequenceSupporters struct {
+	objectstorage.CachedObject
+}
+
+// Retain marks the CachedObject to still be in use by the program.
+func (c *CachedSequenceSupporters) Retain() *CachedSequenceSupporters {
+	return &CachedSequenceSupporters{c.CachedObject.Retain()}
+}
+
+// Unwrap is the type-casted equivalent of Get. It returns nil if the object does not exist.
+func (c *CachedSequenceSupporters) Unwrap() *SequenceSupporters {
+	untypedObject := c.Get()
+	if untypedObject == nil {
+		return nil
+	}
+
+	typedObject := untypedObject.(*SequenceSupporters)
+	if typedObject == nil || typedObject.IsDeleted() {
+		return nil
+	}
+
+	return typedObject
+}
+
+// Consume unwraps the CachedObject and passes a type-casted version to the consumer (if the object is not empty - it
+// exists). It automatically releases the object when the consumer finishes.
+func (c *CachedSequenceSupporters) Consume(consumer func(sequenceSupporters *SequenceSupporters), forceRelease ...bool) (consumed bool) {
+	return c.CachedObject.Consume(func(object objectstorage.StorableObject) {
+		consumer(object.(*SequenceSupporters))
+	}, forceRelease...)
+}
+
+// String returns a human readable version of the CachedSequenceSupporters.
+func (c *CachedSequenceSupporters) String() string {
+	return stringify.Struct("CachedSequenceSupporters",
+		stringify.StructField("CachedObject", c.Unwrap()),
+	)
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region MarkerMessageMapping /////////////////////////////////////////////////////////////////////////////////////////
+
+// MarkerMessageMapping is a data structure that denotes a mapping from a Marker to the Message that it represents.
+type MarkerMessageMapping struct {
+	marker    *markers.Marker
+	messageID MessageID
+
+	objectstorage.StorableObjectFlags
+}
+
+// NewMarkerMessageMapping is the constructor for the MarkerMessageMapping.
+func NewMarkerMessageMapping(marker *markers.Marker, messageID MessageID) *MarkerMessageMapping {
+	return &MarkerMessageMapping{
+		marker:    marker,
+		messageID: messageID,
+	}
+}
+
+// MarkerMessageMappingFromBytes unmarshals a MarkerMessageMapping from a sequence of bytes.
+func MarkerMessageMappingFromBytes(bytes []byte) (markerMessageMapping *MarkerMessageMapping, consumedBytes int, err error) {
+	marshalUtil := marshalutil.New(bytes)
+	if markerMessageMapping, err = MarkerMessageMappingFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse MarkerMessageMapping from MarshalUtil: %w", err)
+		return
+	}
+	consumedBytes = marshalUtil.ReadOffset()
+
+	return
+}
+
+// MarkerMessageMappingFromMarshalUtil unmarshals a MarkerMessageMapping using a MarshalUtil (for easier unmarshaling).
+func MarkerMessageMappingFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (markerMessageMapping *MarkerMessageMapping, err error) {
+	markerMessageMapping = &MarkerMessageMapping{}
+	if markerMessageMapping.marker, err = markers.MarkerFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse Marker from MarshalUtil: %w", err)
+		return
+	}
+	if markerMessageMapping.messageID, err = MessageIDFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse MessageID from MarshalUtil: %w", err)
+		return
+	}
+
+	return
+}
+
+// MarkerMessageMappingFromObjectStorage restores a MarkerMessageMapping that was stored in the object storage.
+func MarkerMessageMappingFromObjectStorage(key []byte, data []byte) (markerMessageMapping objectstorage.StorableObject, err error) {
+	if markerMessageMapping, _, err = MarkerMessageMappingFromBytes(byteutils.ConcatBytes(key, data)); err != nil {
+		err = xerrors.Errorf("failed to parse MarkerMessageMapping from bytes: %w", err)
+		return
+	}
+
+	return
+}
+
+// Marker returns the Marker that is mapped to a MessageID.
+func (m *MarkerMessageMapping) Marker() *markers.Marker {
+	return m.marker
+}
+
+// MessageID returns the MessageID of the Marker.
+func (m *MarkerMessageMapping) MessageID() MessageID {
+	return m.messageID
+}
+
+// Bytes returns a marshaled version of the MarkerMessageMapping.
+func (m *MarkerMessageMapping) Bytes() []byte {
+	return byteutils.ConcatBytes(m.ObjectStorageKey(), m.ObjectStorageValue())
+}
+
+// String returns a human readable version of the MarkerMessageMapping.
+func (m *MarkerMessageMapping) String() string {
+	return stringify.Struct("MarkerMessageMapping",
+		stringify.StructField("marker", m.marker),
+		stringify.StructField("messageID", m.messageID),
+	)
+}
+
+// Update is disabled and panics if it ever gets called - it is required to match the StorableObject interface.
+func (m *MarkerMessageMapping) Update(other objectstorage.StorableObject) {
+	panic("updates disabled")
+}
+
+// ObjectStorageKey returns the key that is used to store the object in the database. It is required to match the
+// StorableObject interface.
+func (m *MarkerMessageMapping) ObjectStorageKey() []byte {
+	return m.marker.Bytes()
+}
+
+// ObjectStorageValue marshals the MarkerMessageMapping into a sequence of bytes that are used as the value part in the
+// object storage.
+func (m *MarkerMessageMapping) ObjectStorageValue() []byte {
+	return m.messageID.Bytes()
+}
+
+// code contract (make sure the type implements all required methods)
+var _ objectstorage.StorableObject = &MarkerMessageMapping{}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region CachedMarkerMessageMapping ///////////////////////////////////////////////////////////////////////////////////
+
+// CachedMarkerMessageMapping is a wrapper for the generic CachedObject returned by the object storage that overrides
+// the accessor methods with a type-casted one.
+type CachedMarkerMessageMapping struct {
+	objectstorage.CachedObject
+}
+
+// Retain marks the CachedObject to still be in use by the program.
+func (c *CachedMarkerMessageMapping) Retain() *CachedMarkerMessageMapping {
+	return &CachedMarkerMessageMapping{c.CachedObject.Retain()}
+}
+
+// Unwrap is the type-casted equivalent of Get. It returns nil if the object does not exist.
+func (c *CachedMarkerMessageMapping) Unwrap() *MarkerMessageMapping {
+	untypedObject := c.Get()
+	if untypedObject == nil {
+		return nil
+	}
+
+	typedObject := untypedObject.(*MarkerMessageMapping)
+	if typedObject == nil || typedObject.IsDeleted() {
+		return nil
+	}
+
+	return typedObject
+}
+
+// Consume unwraps the CachedObject and passes a type-casted version to the consumer (if the object is not empty - it
+// exists). It automatically releases the object when the consumer finishes.
+func (c *CachedMarkerMessageMapping) Consume(consumer func(markerMessageMapping *MarkerMessageMapping), forceRelease ...bool) (consumed bool) {
+	return c.CachedObject.Consume(func(object objectstorage.StorableObject) {
+		consumer(object.(*MarkerMessageMapping))
+	}, forceRelease...)
+}
+
+// String returns a human readable version of the CachedMarkerMessageMapping.
+func (c *CachedMarkerMessageMapping) String() string {
+	return stringify.Struct("CachedMarkerMessageMapping",
+		stringify.StructField("CachedObject", c.Unwrap()),
+	)
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region BranchSupporters /////////////////////////////////////////////////////////////////////////////////////////////
+
+// BranchSupporters is a data structure that keeps track of the supporters of a ConflictBranch.
+type BranchSupporters struct {
+	branchID   ledgerstate.BranchID
+	supporters map[identity.ID]types.Empty
+	mutex      sync.RWMutex
+
+	objectstorage.StorableObjectFlags
+}
+
+// NewBranchSupporters creates new (empty) BranchSupporters for the given ConflictBranch.
+func NewBranchSupporters(branchID ledgerstate.BranchID) (branchSupporters *BranchSupporters) {
+	branchSupporters = &BranchSupporters{
+		branchID:   branchID,
+		supporters: make(map[identity.ID]types.Empty),
+	}
+
+	branchSupporters.SetModified()
+	branchSupporters.Persist()
+
+	return
+}
+
+// BranchSupportersFromBytes unmarshals a BranchSupporters object from a sequence of bytes.
+func BranchSupportersFromBytes(bytes []byte) (branchSupporters *BranchSupporters, consumedBytes int, err error) {
+	marshalUtil := marshalutil.New(bytes)
+	if branchSupporters, err = BranchSupportersFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse BranchSupporters from MarshalUtil: %w", err)
+		return
+	}
+	consumedBytes = marshalUtil.ReadOffset()
+
+	return
+}
+
+// BranchSupportersFromMarshalUtil unmarshals a BranchSupporters object using a MarshalUtil (for easier unmarshaling).
+func BranchSupportersFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (branchSupporters *BranchSupporters, err error) {
+	branchSupporters = &BranchSupporters{
+		supporters: make(map[identity.ID]types.Empty),
+	}
+	if branchSupporters.branchID, err = ledgerstate.BranchIDFromMarshalUtil(marshalUtil); err != nil {
+		err = xerrors.Errorf("failed to parse BranchID from MarshalUtil: %w", err)
+		return
+	}
+	supportersCount, err := marshalUtil.ReadUint32()
+	if err != nil {
+		err = xerrors.Errorf("failed to parse supporters count (%v): %w", err, cerrors.ErrParseBytesFailed)
+		return
+	}
+	for i := uint32(0); i < supportersCount; i++ {
+		supporterBytes, supporterErr := marshalUtil.ReadBytes(len(identity.ID{}))
+		if supporterErr != nil {
+			err = xerrors.Errorf("failed to parse supporter (%v): %w", supporterErr, cerrors.ErrParseBytesFailed)
+			return
+		}
+
+		var supporter identity.ID
+		copy(supporter[:], supporterBytes)
+		branchSupporters.supporters[supporter] = types.Void
+	}
+
+	return
+}
+
+// BranchSupportersFromObjectStorage restores a BranchSupporters object that was stored in the object storage.
+func BranchSupportersFromObjectStorage(key []byte, data []byte) (branchSupporters objectstorage.StorableObject, err error) {
+	if branchSupporters, _, err = BranchSupportersFromBytes(byteutils.ConcatBytes(key, data)); err != nil {
+		err = xerrors.Errorf("failed to parse BranchSupporters from bytes: %w", err)
+		return
+	}
+
+	return
+}
+
+// BranchID returns the BranchID of the ConflictBranch that the BranchSupporters belong to.
+func (b *BranchSupporters) BranchID() ledgerstate.BranchID {
+	return b.branchID
+}
+
+// AddSupporter adds the given supporter to the ConflictBranch. It returns true if the supporter was added.
+func (b *BranchSupporters) AddSupporter(supporter identity.ID) (added bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if _, exists := b.supporters[supporter]; exists {
+		return false
+	}
+
+	b.supporters[supporter] = types.Void
+	b.SetModified()
+
+	return true
+}
+
+// DeleteSupporter removes the given supporter from the ConflictBranch. It returns true if the supporter was removed.
+func (b *BranchSupporters) DeleteSupporter(supporter identity.ID) (deleted bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if _, exists := b.supporters[supporter]; !exists {
+		return false
+	}
+
+	delete(b.supporters, supporter)
+	b.SetModified()
+
+	return true
+}
+
+// Supporters returns the supporters of the ConflictBranch.
+func (b *BranchSupporters) Supporters() (supporters []identity.ID) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	supporters = make([]identity.ID, 0, len(b.supporters))
+	for supporter := range b.supporters {
+		supporters = append(supporters, supporter)
+	}
+
+	return
+}
+
+// Bytes returns a marshaled version of the BranchSupporters.
+func (b *BranchSupporters) Bytes() []byte {
+	return byteutils.ConcatBytes(b.ObjectStorageKey(), b.ObjectStorageValue())
+}
+
+// String returns a human readable version of the BranchSupporters.
+func (b *BranchSupporters) String() string {
+	return stringify.Struct("BranchSupporters",
+		stringify.StructField("branchID", b.branchID),
+		stringify.StructField("supporters", b.Supporters()),
+	)
+}
+
+// Update is disabled and panics if it ever gets called - it is required to match the StorableObject interface.
+func (b *BranchSupporters) Update(other objectstorage.StorableObject) {
+	panic("updates disabled")
+}
+
+// ObjectStorageKey returns the key that is used to store the object in the database. It is required to match the
+// StorableObject interface.
+func (b *BranchSupporters) ObjectStorageKey() []byte {
+	return b.branchID.Bytes()
+}
+
+// ObjectStorageValue marshals the BranchSupporters into a sequence of bytes that are used as the value part in the
+// object storage.
+func (b *BranchSupporters) ObjectStorageValue() []byte {
+	// sort the supporters to get a deterministic serialization
+	supporters := b.Supporters()
+	sort.Slice(supporters, func(i, j int) bool {
+		return string(supporters[i][:]) < string(supporters[j][:])
+	})
+
+	marshalUtil := marshalutil.New()
+	marshalUtil.WriteUint32(uint32(len(supporters)))
+	for _, supporter := range supporters {
+		marshalUtil.WriteBytes(supporter.Bytes())
+	}
+
+	return marshalUtil.Bytes()
+}
+
+// code contract (make sure the type implements all required methods)
+var _ objectstorage.StorableObject = &BranchSupporters{}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region CachedBranchSupporters ///////////////////////////////////////////////////////////////////////////////////////
+
+// CachedBranchSupporters is a wrapper for the generic CachedObject returned by the object storage that overrides the
+// accessor methods with a type-casted one.
+type CachedBranchSupporters struct {
+	objectstorage.CachedObject
+}
+
+// Retain marks the CachedObject to still be in use by the program.
+func (c *CachedBranchSupporters) Retain() *CachedBranchSupporters {
+	return &CachedBranchSupporters{c.CachedObject.Retain()}
+}
+
+// Unwrap is the type-casted equivalent of Get. It returns nil if the object does not exist.
+func (c *CachedBranchSupporters) Unwrap() *BranchSupporters {
+	untypedObject := c.Get()
+	if untypedObject == nil {
+		return nil
+	}
+
+	typedObject := untypedObject.(*BranchSupporters)
+	if typedObject == nil || typedObject.IsDeleted() {
+		return nil
+	}
+
+	return typedObject
+}
+
+// Consume unwraps the CachedObject and passes a type-casted version to the consumer (if the object is not empty - it
+// exists). It automatically releases the object when the consumer finishes.
+func (c *CachedBranchSupporters) Consume(consumer func(branchSupporters *BranchSupporters), forceRelease ...bool) (consumed bool) {
+	return c.CachedObject.Consume(func(object objectstorage.StorableObject) {
+		consumer(object.(*BranchSupporters))
+	}, forceRelease...)
+}
+
+// String returns a human readable version of the CachedBranchSupporters.
+func (c *CachedBranchSupporters) String() string {
+	return stringify.Struct("CachedBranchSupporters",
+		stringify.StructField("CachedObject", c.Unwrap()),
+	)
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,209 @@
+package tangle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/goshimmer/packages/markers"
+	"github.com/iotaledger/goshimmer/packages/tangle/payload"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApprovalWeightManager_ProcessMessage(t *testing.T) {
+	issuers := make(map[string]ed25519.PublicKey)
+	consensusMana := make(map[identity.ID]float64)
+	for _, name := range []string{"A", "B", "C", "D"} {
+		issuers[name] = ed25519.GenerateKeyPair().PublicKey
+		consensusMana[identity.NewID(issuers[name])] = 1
+	}
+
+	totalConsensusManaRetrievals := 0
+	tangle := New(WithoutOpinionFormer(true), ApprovalWeightConfig(ApprovalWeightParams{
+		ConsensusManaRetrieveFunc: func(nodeID identity.ID) float64 { return consensusMana[nodeID] },
+		TotalConsensusManaRetrieveFunc: func() float64 {
+			totalConsensusManaRetrievals++
+			return 4
+		},
+		ConfirmationThreshold: 0.66,
+	}))
+	defer tangle.Shutdown()
+	tangle.Booker.Setup()
+	tangle.ApprovalWeightManager.Setup()
+
+	confirmedMarkers := make([]*markers.Marker, 0)
+	tangle.ApprovalWeightManager.Events.MarkerConfirmed.Attach(events.NewClosure(func(marker *markers.Marker) {
+		confirmedMarkers = append(confirmedMarkers, marker)
+	}))
+	confirmedMessages := make(map[MessageID]bool)
+	tangle.ApprovalWeightManager.Events.MessageConfirmed.Attach(events.NewClosure(func(messageID MessageID) {
+		confirmedMessages[messageID] = true
+	}))
+
+	messages := make(map[string]*Message)
+	issueMessage := func(alias string, issuer string, strongParents ...MessageID) {
+		messages[alias] = NewMessage(strongParents, []MessageID{}, time.Now(), issuers[issuer], 0, payload.NewGenericDataPayload([]byte(alias)), 0, ed25519.Signature{})
+		tangle.Storage.StoreMessage(messages[alias])
+		require.NoError(t, tangle.Booker.Book(messages[alias].ID()))
+	}
+
+	// each message of the chain creates a new Marker in the same Sequence
+	issueMessage("1", "A", EmptyMessageID)
+	issueMessage("2", "B", messages["1"].ID())
+	marker1 := markers.NewMarker(1, 1)
+	marker2 := markers.NewMarker(1, 2)
+	assert.Equal(t, 0.5, tangle.ApprovalWeightManager.Weight(marker1))
+	assert.Equal(t, 0.25, tangle.ApprovalWeightManager.Weight(marker2))
+	assert.Empty(t, confirmedMarkers)
+	assert.False(t, tangle.ApprovalWeightManager.IsMessageConfirmed(messages["1"].ID()))
+
+	// the third supporter pushes the first Marker over the threshold
+	issueMessage("3", "C", messages["2"].ID())
+	assert.Equal(t, 0.75, tangle.ApprovalWeightManager.Weight(marker1))
+	assert.Equal(t, []*markers.Marker{marker1}, confirmedMarkers)
+	assert.True(t, tangle.ApprovalWeightManager.IsMarkerConfirmed(marker1))
+	assert.False(t, tangle.ApprovalWeightManager.IsMarkerConfirmed(marker2))
+	assert.True(t, tangle.ApprovalWeightManager.IsMessageConfirmed(messages["1"].ID()))
+	assert.False(t, tangle.ApprovalWeightManager.IsMessageConfirmed(messages["2"].ID()))
+	assert.Equal(t, map[MessageID]bool{messages["1"].ID(): true}, confirmedMessages)
+
+	// a repeated approval of the same issuer does not increase the weight
+	issueMessage("4", "C", messages["3"].ID())
+	assert.Equal(t, 0.5, tangle.ApprovalWeightManager.Weight(marker2))
+	assert.False(t, tangle.ApprovalWeightManager.IsMarkerConfirmed(marker2))
+
+	issueMessage("5", "D", messages["4"].ID())
+	assert.Equal(t, 0.75, tangle.ApprovalWeightManager.Weight(marker2))
+	assert.Equal(t, []*markers.Marker{marker1, marker2}, confirmedMarkers)
+	assert.True(t, tangle.ApprovalWeightManager.IsMessageConfirmed(messages["2"].ID()))
+	assert.False(t, tangle.ApprovalWeightManager.IsMessageConfirmed(messages["3"].ID()))
+
+	// the total consensus mana is retrieved once per evaluation
+	totalConsensusManaRetrievals = 0
+	tangle.ApprovalWeightManager.checkConfirmations(marker1.SequenceID())
+	assert.Equal(t, 1, totalConsensusManaRetrievals)
+}
+
+func TestApprovalWeightManager_ProcessBranches(t *testing.T) {
+	issuers := make(map[string]ed25519.PublicKey)
+	consensusMana := make(map[identity.ID]float64)
+	for _, name := range []string{"A", "B", "C", "D"} {
+		issuers[name] = ed25519.GenerateKeyPair().PublicKey
+		consensusMana[identity.NewID(issuers[name])] = 1
+	}
+
+	tangle := New(WithoutOpinionFormer(true), ApprovalWeightConfig(ApprovalWeightParams{
+		ConsensusManaRetrieveFunc:      func(nodeID identity.ID) float64 { return consensusMana[nodeID] },
+		TotalConsensusManaRetrieveFunc: func() float64 { return 4 },
+		ConfirmationThreshold:          0.66,
+	}))
+	defer tangle.Shutdown()
+	tangle.Booker.Setup()
+	tangle.ApprovalWeightManager.Setup()
+
+	confirmedBranches := make([]ledgerstate.BranchID, 0)
+	tangle.ApprovalWeightManager.Events.BranchConfirmed.Attach(events.NewClosure(func(branchID ledgerstate.BranchID) {
+		confirmedBranches = append(confirmedBranches, branchID)
+	}))
+
+	wallets := createWallets(3)
+	tangle.LedgerState.LoadSnapshot(map[ledgerstate.TransactionID]map[ledgerstate.Address]*ledgerstate.ColoredBalances{
+		ledgerstate.GenesisTransactionID: {
+			wallets[0].address: ledgerstate.NewColoredBalances(map[ledgerstate.Color]uint64{ledgerstate.ColorIOTA: 1}),
+		},
+	})
+
+	// create two transactions that spend the same genesis output
+	genesisInput := ledgerstate.NewUTXOInput(ledgerstate.NewOutputID(ledgerstate.GenesisTransactionID, 0))
+	transactions := map[string]*ledgerstate.Transaction{
+		"1": makeTransaction(ledgerstate.NewInputs(genesisInput), ledgerstate.NewOutputs(ledgerstate.NewSigLockedSingleOutput(1, wallets[1].address)), nil, nil, wallets[0]),
+		"2": makeTransaction(ledgerstate.NewInputs(genesisInput), ledgerstate.NewOutputs(ledgerstate.NewSigLockedSingleOutput(1, wallets[2].address)), nil, nil, wallets[0]),
+	}
+	branch1 := ledgerstate.NewBranchID(transactions["1"].ID())
+	branch2 := ledgerstate.NewBranchID(transactions["2"].ID())
+
+	messages := make(map[string]*Message)
+	issueMessage := func(alias string, issuer string, messagePayload payload.Payload, strongParents ...MessageID) {
+		messages[alias] = NewMessage(strongParents, []MessageID{}, time.Now(), issuers[issuer], 0, messagePayload, 0, ed25519.Signature{})
+		tangle.Storage.StoreMessage(messages[alias])
+		require.NoError(t, tangle.Booker.Book(messages[alias].ID()))
+	}
+	branchLiked := func(branchID ledgerstate.BranchID) (liked bool) {
+		tangle.LedgerState.BranchDAG.Branch(branchID).Consume(func(branch ledgerstate.Branch) {
+			liked = branch.Liked()
+		})
+		return
+	}
+
+	issueMessage("1", "A", transactions["1"], EmptyMessageID)
+	issueMessage("2", "B", transactions["2"], EmptyMessageID)
+	assert.Equal(t, 0.25, tangle.ApprovalWeightManager.BranchWeight(branch2))
+	assert.True(t, branchLiked(branch2))
+
+	// A supports the first branch now that it is a conflict
+	issueMessage("3", "A", payload.NewGenericDataPayload([]byte("3")), messages["1"].ID())
+	assert.Equal(t, 0.25, tangle.ApprovalWeightManager.BranchWeight(branch1))
+	assert.False(t, branchLiked(branch1))
+
+	// the first branch gains more weight than the second one, so we switch our liked branch
+	issueMessage("4", "C", payload.NewGenericDataPayload([]byte("4")), messages["3"].ID())
+	assert.Equal(t, 0.5, tangle.ApprovalWeightManager.BranchWeight(branch1))
+	assert.True(t, branchLiked(branch1))
+	assert.False(t, branchLiked(branch2))
+
+	issueMessage("5", "D", payload.NewGenericDataPayload([]byte("5")), messages["2"].ID())
+	assert.Equal(t, 0.5, tangle.ApprovalWeightManager.BranchWeight(branch2))
+	assert.True(t, branchLiked(branch1))
+
+	// D changes its mind, which moves its weight and confirms the first branch
+	issueMessage("6", "D", payload.NewGenericDataPayload([]byte("6")), messages["4"].ID())
+	assert.Equal(t, 0.75, tangle.ApprovalWeightManager.BranchWeight(branch1))
+	assert.Equal(t, 0.25, tangle.ApprovalWeightManager.BranchWeight(branch2))
+	assert.Equal(t, []ledgerstate.BranchID{branch1}, confirmedBranches)
+
+	tangle.LedgerState.BranchDAG.Branch(branch1).Consume(func(branch ledgerstate.Branch) {
+		assert.Equal(t, ledgerstate.Confirmed, branch.InclusionState())
+	})
+	tangle.LedgerState.BranchDAG.Branch(branch2).Consume(func(branch ledgerstate.Branch) {
+		assert.Equal(t, ledgerstate.Rejected, branch.InclusionState())
+	})
+}
+
+func TestSequenceSupporters_Bytes(t *testing.T) {
+	supporter1 := identity.GenerateIdentity().ID()
+	supporter2 := identity.GenerateIdentity().ID()
+
+	sequenceSupporters := NewSequenceSupporters(3, 4)
+	assert.True(t, sequenceSupporters.AddSupporter(supporter1, 7))
+	assert.True(t, sequenceSupporters.AddSupporter(supporter2, 5))
+	assert.False(t, sequenceSupporters.AddSupporter(supporter2, 5))
+
+	restoredSequenceSupporters, _, err := SequenceSupportersFromBytes(sequenceSupporters.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, sequenceSupporters.SequenceID(), restoredSequenceSupporters.SequenceID())
+	assert.Equal(t, markers.Index(4), restoredSequenceSupporters.ConfirmedIndex())
+	assert.Equal(t, markers.Index(7), restoredSequenceSupporters.HighestSupportedIndex())
+	assert.ElementsMatch(t, []identity.ID{supporter1, supporter2}, restoredSequenceSupporters.Supporters(5))
+	assert.ElementsMatch(t, []identity.ID{supporter1}, restoredSequenceSupporters.Supporters(6))
+}
+
+func TestBranchSupporters_Bytes(t *testing.T) {
+	supporter1 := identity.GenerateIdentity().ID()
+	supporter2 := identity.GenerateIdentity().ID()
+
+	branchSupporters := NewBranchSupporters(ledgerstate.BranchID{2})
+	assert.True(t, branchSupporters.AddSupporter(supporter1))
+	assert.True(t, branchSupporters.AddSupporter(supporter2))
+	assert.False(t, branchSupporters.AddSupporter(supporter2))
+	assert.True(t, branchSupporters.DeleteSupporter(supporter2))
+	assert.False(t, branchSupporters.DeleteSupporter(supporter2))
+
+	restoredBranchSupporters, _, err := BranchSupportersFromBytes(branchSupporters.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, branchSupporters.BranchID(), restoredBranchSupporters.BranchID())
+	assert.ElementsMatch(t, []identity.ID{supporter1}, restoredBranchSupporters.Supporters())
+}
@@ -37,7 +37,8 @@ type Booker struct {
 func NewBooker(tangle *Tangle) (messageBooker *Booker) {
 	messageBooker = &Booker{
 		Events: &BookerEvents{
-			MessageBooked: events.NewEvent(messageIDEventHandler),
+			MessageBooked:        events.NewEvent(messageIDEventHandler),
+			MessageBranchUpdated: events.NewEvent(messageIDEventHandler),
 		},
 		tangle:                       tangle,
 		MarkersManager:               NewMarkersManager(tangle),
@@ -60,7 +61,7 @@ func (b *Booker) Setup() {
 			b.tangle.Events.Error.Trigger(err)
 		}
 	}))
-	b.tangle.LedgerState.utxoDAG.Events.TransactionBranchIDUpdated.Attach(events.NewClosure(b.UpdateMessagesBranch))
+	b.tangle.LedgerState.UTXODAG.Events.TransactionBranchIDUpdated.Attach(events.NewClosure(b.UpdateMessagesBranch))
 }
 
 // UpdateMessagesBranch propagates the update of the message's branchID (and its future cone) in case on changes of it contained transction's branchID.
@@ -72,6 +73,9 @@ func (b *Booker) UpdateMessagesBranch(transactionID ledgerstate.TransactionID) {
 				panic(xerrors.Errorf("failed to inherit Branch when booking Message with %s: %w", message.ID(), inheritErr))
 			}
 			if messageMetadata.SetBranchID(inheritedBranch) {
+				b.updateMarkerBranchID(messageMetadata.StructureDetails(), inheritedBranch)
+				b.Events.MessageBranchUpdated.Trigger(message.ID())
+
 				for _, approvingMessageID := range b.tangle.Utils.ApprovingMessageIDs(message.ID(), StrongApprover) {
 					walker.Push(approvingMessageID)
 				}
@@ -111,7 +115,14 @@ func (b *Booker) Book(messageID MessageID) (err error) {
 				}
 
 				for _, output := range transaction.Essence().Outputs() {
-					b.tangle.LedgerState.utxoDAG.StoreAddressOutputMapping(output.Address(), output.ID())
+					b.tangle.LedgerState.UTXODAG.StoreAddressOutputMapping(output.Address(), output.ID())
+
+					// the fallback address needs to be able to discover the outputs that it might be able to unlock
+					if extendedLockedOutput, isExtendedLockedOutput := output.(*ledgerstate.ExtendedLockedOutput); isExtendedLockedOutput {
+						if fallbackAddress, _ := extendedLockedOutput.FallbackAddress(); fallbackAddress != nil {
+							b.tangle.LedgerState.UTXODAG.StoreAddressOutputMapping(fallbackAddress, output.ID())
+						}
+					}
 				}
 
 				attachment, stored := b.tangle.Storage.StoreAttachment(transaction.ID(), messageID)
@@ -126,8 +137,11 @@ func (b *Booker) Book(messageID MessageID) (err error) {
 				return
 			}
 
+			structureDetails := b.MarkersManager.InheritStructureDetails(message, sequenceAlias...)
+			b.updateMarkerBranchID(structureDetails, inheritedBranch)
+
 			messageMetadata.SetBranchID(inheritedBranch)
-			messageMetadata.SetStructureDetails(b.MarkersManager.InheritStructureDetails(message, sequenceAlias...))
+			messageMetadata.SetStructureDetails(structureDetails)
 			messageMetadata.SetBooked(true)
 
 			b.Events.MessageBooked.Trigger(messageID)
@@ -137,6 +151,15 @@ func (b *Booker) Book(messageID MessageID) (err error) {
 	return
 }
 
+// updateMarkerBranchID associates the BranchID of a Message with its Marker (if the Message represents a Marker).
+func (b *Booker) updateMarkerBranchID(structureDetails *markers.StructureDetails, branchID ledgerstate.BranchID) {
+	if structureDetails == nil || !structureDetails.IsPastMarker {
+		return
+	}
+
+	b.MarkerBranchIDMappingManager.SetBranchID(structureDetails.PastMarkers.FirstMarker(), branchID)
+}
+
 func (b *Booker) branchIDOfPayload(message *Message) (branchIDOfPayload ledgerstate.BranchID) {
 	payload := message.Payload()
 	if payload == nil || payload.Type() != ledgerstate.TransactionType {
@@ -144,7 +167,7 @@ func (b *Booker) branchIDOfPayload(message *Message) (branchIDOfPayload ledgerst
 		return
 	}
 	transactionID := payload.(*ledgerstate.Transaction).ID()
-	if !b.tangle.LedgerState.utxoDAG.TransactionMetadata(transactionID).Consume(func(transactionMetadata *ledgerstate.TransactionMetadata) {
+	if !b.tangle.LedgerState.UTXODAG.TransactionMetadata(transactionID).Consume(func(transactionMetadata *ledgerstate.TransactionMetadata) {
 		branchIDOfPayload = transactionMetadata.BranchID()
 	}) {
 		panic(fmt.Sprintf("failed to load TransactionMetadata of %s: ", transactionID))
@@ -176,7 +199,7 @@ func (b *Booker) branchIDsOfParents(message *Message) (branchIDs ledgerstate.Bra
 			if payload := message.Payload(); payload != nil && payload.Type() == ledgerstate.TransactionType {
 				transactionID := payload.(*ledgerstate.Transaction).ID()
 
-				if !b.tangle.LedgerState.utxoDAG.TransactionMetadata(transactionID).Consume(func(transactionMetadata *ledgerstate.TransactionMetadata) {
+				if !b.tangle.LedgerState.UTXODAG.TransactionMetadata(transactionID).Consume(func(transactionMetadata *ledgerstate.TransactionMetadata) {
 					branchIDs[transactionMetadata.BranchID()] = types.Void
 				}) {
 					panic(fmt.Errorf("failed to load TransactionMetadata with %s", transactionID))
@@ -199,6 +222,9 @@ func (b *Booker) branchIDsOfParents(message *Message) (branchIDs ledgerstate.Bra
 type BookerEvents struct {
 	// MessageBooked is triggered when a Message was booked (it's Branch and it's Payload's Branch where determined).
 	MessageBooked *events.Event
+
+	// MessageBranchUpdated is triggered when the Branch of an already booked Message was changed.
+	MessageBranchUpdated *events.Event
 }
 
 // endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1,6 +1,8 @@
 package tangle
 
 import (
+	"time"
+
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
 	"github.com/iotaledger/hive.go/types"
 	"golang.org/x/xerrors"
@@ -11,25 +13,26 @@ import (
 // LedgerState is a Tangle component that wraps the components of the ledgerstate package and makes them available at a
 // "single point of contact".
 type LedgerState struct {
-	tangle    *Tangle
-	branchDAG *ledgerstate.BranchDAG
-	utxoDAG   *ledgerstate.UTXODAG
+	BranchDAG *ledgerstate.BranchDAG
+	UTXODAG   *ledgerstate.UTXODAG
+
+	tangle *Tangle
 }
 
 // NewLedgerState is the constructor of the LedgerState component.
 func NewLedgerState(tangle *Tangle) (ledgerState *LedgerState) {
 	branchDAG := ledgerstate.NewBranchDAG(tangle.Options.Store)
 	return &LedgerState{
+		BranchDAG: branchDAG,
+		UTXODAG:   ledgerstate.NewUTXODAG(tangle.Options.Store, branchDAG),
 		tangle:    tangle,
-		branchDAG: branchDAG,
-		utxoDAG:   ledgerstate.NewUTXODAG(tangle.Options.Store, branchDAG),
 	}
 }
 
 // Shutdown shuts down the LedgerState and persists its state.
 func (l *LedgerState) Shutdown() {
-	l.utxoDAG.Shutdown()
-	l.branchDAG.Shutdown()
+	l.UTXODAG.Shutdown()
+	l.BranchDAG.Shutdown()
 }
 
 // InheritBranch implements the inheritance rules for Branches in the Tangle. It returns a single inherited Branch
@@ -40,12 +43,12 @@ func (l *LedgerState) InheritBranch(referencedBranchIDs ledgerstate.BranchIDs) (
 		return
 	}
 
-	branchIDsContainRejectedBranch, inheritedBranch := l.branchDAG.BranchIDsContainRejectedBranch(referencedBranchIDs)
+	branchIDsContainRejectedBranch, inheritedBranch := l.BranchDAG.BranchIDsContainRejectedBranch(referencedBranchIDs)
 	if branchIDsContainRejectedBranch {
 		return
 	}
 
-	cachedAggregatedBranch, _, err := l.branchDAG.AggregateBranches(referencedBranchIDs)
+	cachedAggregatedBranch, _, err := l.BranchDAG.AggregateBranches(referencedBranchIDs)
 	if err != nil {
 		if xerrors.Is(err, ledgerstate.ErrInvalidStateTransition) {
 			inheritedBranch = ledgerstate.InvalidBranchID
@@ -65,7 +68,7 @@ func (l *LedgerState) InheritBranch(referencedBranchIDs ledgerstate.BranchIDs) (
 // TransactionValid performs some fast checks of the Transaction and triggers a MessageInvalid event if the checks do
 // not pass.
 func (l *LedgerState) TransactionValid(transaction *ledgerstate.Transaction, messageID MessageID) (valid bool, err error) {
-	valid, err = l.utxoDAG.CheckTransaction(transaction)
+	valid, err = l.UTXODAG.CheckTransaction(transaction)
 	if err != nil {
 		l.tangle.Events.MessageInvalid.Trigger(messageID)
 	}
@@ -80,18 +83,18 @@ func (l *LedgerState) TransactionConflicting(transactionID ledgerstate.Transacti
 
 // TransactionMetadata retrieves the TransactionMetadata with the given TransactionID from the object storage.
 func (l *LedgerState) TransactionMetadata(transactionID ledgerstate.TransactionID) (cachedTransactionMetadata *ledgerstate.CachedTransactionMetadata) {
-	return l.utxoDAG.TransactionMetadata(transactionID)
+	return l.UTXODAG.TransactionMetadata(transactionID)
 }
 
 // Transaction retrieves the Transaction with the given TransactionID from the object storage.
 func (l *LedgerState) Transaction(transactionID ledgerstate.TransactionID) *ledgerstate.CachedTransaction {
-	return l.utxoDAG.Transaction(transactionID)
+	return l.UTXODAG.Transaction(transactionID)
 }
 
 // BookTransaction books the given Transaction into the underlying LedgerState and returns the target Branch and an
 // eventual error.
 func (l *LedgerState) BookTransaction(transaction *ledgerstate.Transaction, messageID MessageID) (targetBranch ledgerstate.BranchID, err error) {
-	targetBranch, err = l.utxoDAG.BookTransaction(transaction)
+	targetBranch, err = l.UTXODAG.BookTransaction(transaction)
 	if err != nil {
 		if !xerrors.Is(err, ledgerstate.ErrTransactionInvalid) && !xerrors.Is(err, ledgerstate.ErrTransactionNotSolid) {
 			err = xerrors.Errorf("failed to book Transaction: %w", err)
@@ -113,12 +116,12 @@ func (l *LedgerState) ConflictSet(transactionID ledgerstate.TransactionID) (conf
 	conflictIDs := make(ledgerstate.ConflictIDs)
 	conflictSet = make(ledgerstate.TransactionIDs)
 
-	l.branchDAG.Branch(ledgerstate.NewBranchID(transactionID)).Consume(func(branch ledgerstate.Branch) {
+	l.BranchDAG.Branch(ledgerstate.NewBranchID(transactionID)).Consume(func(branch ledgerstate.Branch) {
 		conflictIDs = branch.(*ledgerstate.ConflictBranch).Conflicts()
 	})
 
 	for conflictID := range conflictIDs {
-		l.branchDAG.ConflictMembers(conflictID).Consume(func(conflictMember *ledgerstate.ConflictMember) {
+		l.BranchDAG.ConflictMembers(conflictID).Consume(func(conflictMember *ledgerstate.ConflictMember) {
 			conflictSet[ledgerstate.TransactionID(conflictMember.BranchID())] = types.Void
 		})
 	}
@@ -129,13 +132,13 @@ func (l *LedgerState) ConflictSet(transactionID ledgerstate.TransactionID) (conf
 // TransactionInclusionState returns the InclusionState of the Transaction with the given TransactionID which can either be
 // Pending, Confirmed or Rejected.
 func (l *LedgerState) TransactionInclusionState(transactionID ledgerstate.TransactionID) (ledgerstate.InclusionState, error) {
-	return l.utxoDAG.InclusionState(transactionID)
+	return l.UTXODAG.InclusionState(transactionID)
 }
 
 // BranchInclusionState returns the InclusionState of the Branch with the given BranchID which can either be
 // Pending, Confirmed or Rejected.
 func (l *LedgerState) BranchInclusionState(branchID ledgerstate.BranchID) (inclusionState ledgerstate.InclusionState) {
-	l.branchDAG.Branch(branchID).Consume(func(branch ledgerstate.Branch) {
+	l.BranchDAG.Branch(branchID).Consume(func(branch ledgerstate.Branch) {
 		inclusionState = branch.InclusionState()
 	})
 	return
@@ -143,7 +146,7 @@ func (l *LedgerState) BranchInclusionState(branchID ledgerstate.BranchID) (inclu
 
 // BranchID returns the branchID of the given transactionID.
 func (l *LedgerState) BranchID(transactionID ledgerstate.TransactionID) (branchID ledgerstate.BranchID) {
-	l.utxoDAG.TransactionMetadata(transactionID).Consume(func(transactionMetadata *ledgerstate.TransactionMetadata) {
+	l.UTXODAG.TransactionMetadata(transactionID).Consume(func(transactionMetadata *ledgerstate.TransactionMetadata) {
 		branchID = transactionMetadata.BranchID()
 	})
 	return
@@ -151,39 +154,162 @@ func (l *LedgerState) BranchID(transactionID ledgerstate.TransactionID) (branchI
 
 // Branch returns the branch with the given ID.
 func (l *LedgerState) Branch(branchID ledgerstate.BranchID) *ledgerstate.CachedBranch {
-	return l.branchDAG.Branch(branchID)
+	return l.BranchDAG.Branch(branchID)
 }
 
 // LoadSnapshot creates a set of outputs in the UTXO-DAG, that are forming the genesis for future transactions.
 func (l *LedgerState) LoadSnapshot(snapshot map[ledgerstate.TransactionID]map[ledgerstate.Address]*ledgerstate.ColoredBalances) {
-	l.utxoDAG.LoadSnapshot(snapshot)
+	l.UTXODAG.LoadSnapshot(snapshot)
 	attachment, _ := l.tangle.Storage.StoreAttachment(ledgerstate.GenesisTransactionID, EmptyMessageID)
 	if attachment != nil {
 		attachment.Release()
 	}
 }
 
+// ConfirmedUnspentOutputs returns all Outputs that are part of the confirmed ledger state and that have not been spent
+// by a confirmed Transaction, yet.
+func (l *LedgerState) ConfirmedUnspentOutputs() (outputs ledgerstate.Outputs) {
+	outputs = make(ledgerstate.Outputs, 0)
+	l.UTXODAG.ForEachConfirmedUnspentOutput(func(output ledgerstate.Output, _ *ledgerstate.OutputMetadata) bool {
+		outputs = append(outputs, output.Clone())
+		return true
+	})
+
+	return
+}
+
+// LoadOutputs stores the given Outputs as the confirmed and unspent Outputs that form the genesis for future
+// transactions (i.e. when restoring the ledger state from a local snapshot).
+func (l *LedgerState) LoadOutputs(outputs ledgerstate.Outputs) {
+	l.UTXODAG.LoadOutputs(outputs)
+
+	transactionIDs := make(map[ledgerstate.TransactionID]types.Empty)
+	for _, output := range outputs {
+		transactionIDs[output.ID().TransactionID()] = types.Void
+	}
+	for transactionID := range transactionIDs {
+		attachment, _ := l.tangle.Storage.StoreAttachment(transactionID, EmptyMessageID)
+		if attachment != nil {
+			attachment.Release()
+		}
+	}
+}
+
+// PruneFinalizedBranches removes all rejected and finalized ConflictBranches whose Transactions were issued before the
+// given time. Confirmed Branches are kept, since they are still referenced by the Outputs of the confirmed ledger
+// state. It returns the number of pruned Branches.
+func (l *LedgerState) PruneFinalizedBranches(cutoff time.Time) (prunedBranches int, err error) {
+	prunableBranchIDs := make([]ledgerstate.BranchID, 0)
+	l.BranchDAG.ForEachBranch(func(branch ledgerstate.Branch) {
+		if branch.Type() != ledgerstate.ConflictBranchType || !branch.Finalized() || branch.InclusionState() != ledgerstate.Rejected {
+			return
+		}
+
+		l.Transaction(ledgerstate.TransactionID(branch.ID())).Consume(func(transaction *ledgerstate.Transaction) {
+			if transaction.Essence().Timestamp().Before(cutoff) {
+				prunableBranchIDs = append(prunableBranchIDs, branch.ID())
+			}
+		})
+	})
+
+	for _, branchID := range prunableBranchIDs {
+		// Branches that still have ChildBranches are pruned after their children
+		cachedChildBranches := l.BranchDAG.ChildBranches(branchID)
+		hasChildBranches := len(cachedChildBranches) != 0
+		cachedChildBranches.Release()
+		if hasChildBranches {
+			continue
+		}
+
+		if err = l.BranchDAG.PruneBranch(branchID); err != nil {
+			err = xerrors.Errorf("failed to prune Branch with %s: %w", branchID, err)
+			return
+		}
+		prunedBranches++
+	}
+
+	return
+}
+
 // Output returns the Output with the given ID.
 func (l *LedgerState) Output(outputID ledgerstate.OutputID) *ledgerstate.CachedOutput {
-	return l.utxoDAG.Output(outputID)
+	return l.UTXODAG.Output(outputID)
 }
 
 // OutputMetadata returns the OutputMetadata with the given ID.
 func (l *LedgerState) OutputMetadata(outputID ledgerstate.OutputID) *ledgerstate.CachedOutputMetadata {
-	return l.utxoDAG.OutputMetadata(outputID)
+	return l.UTXODAG.OutputMetadata(outputID)
 }
 
 // OutputsOnAddress retrieves all the Outputs that are associated with an address.
 func (l *LedgerState) OutputsOnAddress(address ledgerstate.Address) (cachedOutputs ledgerstate.CachedOutputs) {
-	l.utxoDAG.AddressOutputMapping(address).Consume(func(addressOutputMapping *ledgerstate.AddressOutputMapping) {
+	l.UTXODAG.AddressOutputMapping(address).Consume(func(addressOutputMapping *ledgerstate.AddressOutputMapping) {
 		cachedOutputs = append(cachedOutputs, l.Output(addressOutputMapping.OutputID()))
 	})
 	return
 }
 
+// AddressHistory retrieves the AddressHistoryEntries of all Transactions that credited or debited the given Address
+// (sorted by their timestamp).
+func (l *LedgerState) AddressHistory(address ledgerstate.Address) (addressHistoryEntries ledgerstate.AddressHistoryEntries) {
+	l.UTXODAG.AddressHistory(address).Consume(func(addressHistoryEntry *ledgerstate.AddressHistoryEntry) {
+		addressHistoryEntries = append(addressHistoryEntries, addressHistoryEntry)
+	})
+	return addressHistoryEntries.Sort()
+}
+
+// ForEachAddressHistoryEntry iterates over the AddressHistoryEntries of the given Address in the order of their
+// timestamps (starting after the given cursor). The iteration stops if the consumer returns false.
+func (l *LedgerState) ForEachAddressHistoryEntry(address ledgerstate.Address, consumer func(addressHistoryEntry *ledgerstate.AddressHistoryEntry) bool, optionalCursor ...ledgerstate.AddressHistoryCursor) {
+	l.UTXODAG.ForEachAddressHistoryEntry(address, consumer, optionalCursor...)
+}
+
+// AddressHistoryPage retrieves (sorted by their timestamp) up to limit AddressHistoryEntries of the given Address that
+// pass the filter and that follow the given cursor (or start at the beginning of the history if the cursor is nil). It
+// also returns whether there are more entries after the returned page.
+func (l *LedgerState) AddressHistoryPage(address ledgerstate.Address, cursor *ledgerstate.AddressHistoryCursor, limit int, filter func(addressHistoryEntry *ledgerstate.AddressHistoryEntry) bool) (page ledgerstate.AddressHistoryEntries, more bool) {
+	optionalCursor := make([]ledgerstate.AddressHistoryCursor, 0, 1)
+	if cursor != nil {
+		optionalCursor = append(optionalCursor, *cursor)
+	}
+
+	page = make(ledgerstate.AddressHistoryEntries, 0, limit)
+	l.UTXODAG.ForEachAddressHistoryEntry(address, func(addressHistoryEntry *ledgerstate.AddressHistoryEntry) bool {
+		if !filter(addressHistoryEntry) {
+			return true
+		}
+		if len(page) == limit {
+			more = true
+			return false
+		}
+		page = append(page, addressHistoryEntry)
+
+		return true
+	}, optionalCursor...)
+
+	return page, more
+}
+
+// ForEachTransaction iterates over all the Transactions that were booked into the ledger. The iteration stops if the
+// consumer returns false.
+func (l *LedgerState) ForEachTransaction(consumer func(transaction *ledgerstate.Transaction) bool) {
+	l.UTXODAG.ForEachTransaction(consumer)
+}
+
+// ColorMetadata returns the ColorMetadata with the supply information of the given Color.
+func (l *LedgerState) ColorMetadata(color ledgerstate.Color) *ledgerstate.CachedColorMetadata {
+	return l.UTXODAG.ColorMetadata(color)
+}
+
+// ForEachColorMetadata iterates over the ColorMetadata of all known Colors. The iteration stops if the consumer returns
+// false.
+func (l *LedgerState) ForEachColorMetadata(consumer func(colorMetadata *ledgerstate.ColorMetadata) bool) {
+	l.UTXODAG.ForEachColorMetadata(consumer)
+}
+
 // CheckTransaction contains fast checks that have to be performed before booking a Transaction.
 func (l *LedgerState) CheckTransaction(transaction *ledgerstate.Transaction) (valid bool, err error) {
-	return l.utxoDAG.CheckTransaction(transaction)
+	return l.UTXODAG.CheckTransaction(transaction)
 }
 
 // endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
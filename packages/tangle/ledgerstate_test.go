@@ -29,3 +29,29 @@ func TestLoadSnapshot(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, ledgerstate.Confirmed, inclusionState)
 }
+
+func TestLedgerState_AddressHistoryPage(t *testing.T) {
+	tangle := New()
+	defer tangle.Shutdown()
+
+	wallets := createWallets(1)
+	outputs := make(ledgerstate.Outputs, 0)
+	for i := byte(1); i <= 5; i++ {
+		outputs = append(outputs, ledgerstate.NewSigLockedSingleOutput(uint64(i), wallets[0].address).SetID(ledgerstate.NewOutputID(ledgerstate.TransactionID{i}, 0)))
+	}
+	tangle.LedgerState.LoadOutputs(outputs)
+
+	matchAll := func(*ledgerstate.AddressHistoryEntry) bool { return true }
+	var cursor *ledgerstate.AddressHistoryCursor
+	for _, expectedPage := range [][]byte{{1, 2}, {3, 4}, {5}} {
+		page, more := tangle.LedgerState.AddressHistoryPage(wallets[0].address, cursor, 2, matchAll)
+		require.Equal(t, expectedPage[len(expectedPage)-1] != 5, more)
+		require.Len(t, page, len(expectedPage))
+		for i, transactionIDPrefix := range expectedPage {
+			require.Equal(t, ledgerstate.TransactionID{transactionIDPrefix}, page[i].TransactionID())
+		}
+
+		nextCursor := page[len(page)-1].Cursor()
+		cursor = &nextCursor
+	}
+}
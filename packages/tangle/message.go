@@ -633,6 +633,7 @@ type MessageMetadata struct {
 	booked             bool
 	eligible           bool
 	invalid            bool
+	confirmed          bool
 
 	solidMutex              sync.RWMutex
 	solidificationTimeMutex sync.RWMutex
@@ -642,6 +643,7 @@ type MessageMetadata struct {
 	bookedMutex             sync.RWMutex
 	eligibleMutex           sync.RWMutex
 	invalidMutex            sync.RWMutex
+	confirmedMutex          sync.RWMutex
 }
 
 // NewMessageMetadata creates a new MessageMetadata from the specified messageID.
@@ -705,6 +707,10 @@ func MessageMetadataFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (resul
 		err = fmt.Errorf("failed to parse invalid flag of message metadata: %w", err)
 		return
 	}
+	if result.confirmed, err = marshalUtil.ReadBool(); err != nil {
+		err = fmt.Errorf("failed to parse confirmed flag of message metadata: %w", err)
+		return
+	}
 
 	return
 }
@@ -918,6 +924,33 @@ func (m *MessageMetadata) SetInvalid(invalid bool) (modified bool) {
 	return
 }
 
+// IsConfirmed returns true if the message represented by this metadata is confirmed by the approval weight of its
+// future cone. False otherwise.
+func (m *MessageMetadata) IsConfirmed() (result bool) {
+	m.confirmedMutex.RLock()
+	defer m.confirmedMutex.RUnlock()
+	result = m.confirmed
+
+	return
+}
+
+// SetConfirmed sets the message associated with this metadata as confirmed.
+// It returns true if the confirmed status is modified. False otherwise.
+func (m *MessageMetadata) SetConfirmed(confirmed bool) (modified bool) {
+	m.confirmedMutex.Lock()
+	defer m.confirmedMutex.Unlock()
+
+	if m.confirmed == confirmed {
+		return false
+	}
+
+	m.confirmed = confirmed
+	m.SetModified()
+	modified = true
+
+	return
+}
+
 // Bytes returns a marshaled version of the whole MessageMetadata object.
 func (m *MessageMetadata) Bytes() []byte {
 	return byteutils.ConcatBytes(m.ObjectStorageKey(), m.ObjectStorageValue())
@@ -942,6 +975,7 @@ func (m *MessageMetadata) ObjectStorageValue() []byte {
 		WriteBool(m.IsEligible()).
 		WriteBool(m.IsBooked()).
 		WriteBool(m.IsInvalid()).
+		WriteBool(m.IsConfirmed()).
 		Bytes()
 }
 
@@ -964,6 +998,7 @@ func (m *MessageMetadata) String() string {
 		stringify.StructField("booked", m.IsBooked()),
 		stringify.StructField("eligible", m.IsEligible()),
 		stringify.StructField("invalid", m.IsInvalid()),
+		stringify.StructField("confirmed", m.IsConfirmed()),
 	)
 }
 
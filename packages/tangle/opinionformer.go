@@ -139,9 +139,9 @@ func (o *OpinionFormer) onPayloadOpinionFormed(ev *OpinionFormedEvent) {
 			transactionMetadata.SetFinalized(true)
 		})
 		if o.tangle.LedgerState.TransactionConflicting(transactionID) {
-			o.tangle.LedgerState.branchDAG.SetBranchLiked(o.tangle.LedgerState.BranchID(transactionID), ev.Opinion)
+			o.tangle.LedgerState.BranchDAG.SetBranchLiked(o.tangle.LedgerState.BranchID(transactionID), ev.Opinion)
 			// TODO: move this to approval weight logic
-			o.tangle.LedgerState.branchDAG.SetBranchFinalized(o.tangle.LedgerState.BranchID(transactionID), true)
+			o.tangle.LedgerState.BranchDAG.SetBranchFinalized(o.tangle.LedgerState.BranchID(transactionID), true)
 		}
 	})
 
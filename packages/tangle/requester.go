@@ -5,24 +5,46 @@ import (
 	"time"
 
 	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/identity"
+	"github.com/iotaledger/hive.go/types"
 )
 
 const (
 	// DefaultRetryInterval defines the Default Retry Interval of the message requester.
-	DefaultRetryInterval = 10 * time.Second
+	DefaultRetryInterval = 2 * time.Second
 
-	// the maximum amount of requests before we abort
-	maxRequestThreshold = 500
+	// DefaultMaxRetryInterval defines the default upper bound of the exponentially growing retry interval.
+	DefaultMaxRetryInterval = 1 * time.Minute
+
+	// DefaultMaxRequestAttempts defines the default number of requests after which a missing message is given up.
+	DefaultMaxRequestAttempts = 20
+
+	// DefaultRequestBatchInterval defines the default time that new requests are collected before they are sent.
+	DefaultRequestBatchInterval = 50 * time.Millisecond
+
+	// DefaultMaxRequestBatchSize defines the default maximum number of message ids that are requested at once.
+	DefaultMaxRequestBatchSize = 1024
+
+	// maxMessageSources defines the maximum number of remembered senders of messages that are not yet requested.
+	maxMessageSources = 10000
 )
 
 // RequesterOptions holds options for a message requester.
 type RequesterOptions struct {
-	retryInterval time.Duration
+	retryInterval      time.Duration
+	maxRetryInterval   time.Duration
+	maxRequestAttempts int
+	batchInterval      time.Duration
+	maxBatchSize       int
 }
 
 func newRequesterOptions(optionalOptions []RequesterOption) *RequesterOptions {
 	result := &RequesterOptions{
-		retryInterval: 10 * time.Second,
+		retryInterval:      DefaultRetryInterval,
+		maxRetryInterval:   DefaultMaxRetryInterval,
+		maxRequestAttempts: DefaultMaxRequestAttempts,
+		batchInterval:      DefaultRequestBatchInterval,
+		maxBatchSize:       DefaultMaxRequestBatchSize,
 	}
 
 	for _, optionalOption := range optionalOptions {
@@ -35,21 +57,57 @@ func newRequesterOptions(optionalOptions []RequesterOption) *RequesterOptions {
 // RequesterOption is a function which inits an option.
 type RequesterOption func(*RequesterOptions)
 
-// RetryInterval creates an option which sets the retry interval to the given value.
+// RetryInterval creates an option which sets the retry interval to the given value. The interval is doubled after
+// every request of the same message.
 func RetryInterval(interval time.Duration) RequesterOption {
 	return func(args *RequesterOptions) {
 		args.retryInterval = interval
 	}
 }
 
-// region Requester /////////////////////////////////////////////////////////////////////////////////////////////
+// MaxRetryInterval creates an option which sets the upper bound of the exponentially growing retry interval.
+func MaxRetryInterval(interval time.Duration) RequesterOption {
+	return func(args *RequesterOptions) {
+		args.maxRetryInterval = interval
+	}
+}
 
-// Requester takes care of requesting messages.
+// MaxRequestAttempts creates an option which sets the number of requests after which a missing message is given up.
+func MaxRequestAttempts(attempts int) RequesterOption {
+	return func(args *RequesterOptions) {
+		args.maxRequestAttempts = attempts
+	}
+}
+
+// RequestBatchInterval creates an option which sets the time that new requests are collected before they are sent.
+func RequestBatchInterval(interval time.Duration) RequesterOption {
+	return func(args *RequesterOptions) {
+		args.batchInterval = interval
+	}
+}
+
+// MaxRequestBatchSize creates an option which sets the maximum number of message ids that are requested at once.
+func MaxRequestBatchSize(size int) RequesterOption {
+	return func(args *RequesterOptions) {
+		args.maxBatchSize = size
+	}
+}
+
+// region Requester ////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Requester takes care of requesting messages. Missing messages are requested in batches: The first request is sent to
+// the neighbor that sent the message referencing the missing one, every retry is sent to the next neighbor that was not
+// asked yet and only once all neighbors were asked, the requests are sent to all neighbors at once. Retries happen with
+// an exponentially growing interval until the message is received or the maximum number of attempts is reached.
 type Requester struct {
-	tangle            *Tangle
-	scheduledRequests map[MessageID]*time.Timer
-	options           *RequesterOptions
-	Events            *MessageRequesterEvents
+	tangle             *Tangle
+	scheduledRequests  map[MessageID]*messageRequest
+	messageSources     map[MessageID]identity.ID
+	pendingRequests    MessageIDs
+	batchTimer         *time.Timer
+	neighborsRetriever NeighborsRetrieveFunc
+	options            *RequesterOptions
+	Events             *MessageRequesterEvents
 
 	scheduledRequestsMutex sync.RWMutex
 }
@@ -57,14 +115,19 @@ type Requester struct {
 // MessageExistsFunc is a function that tells if a message exists.
 type MessageExistsFunc func(messageId MessageID) bool
 
+// NeighborsRetrieveFunc is a function that returns the IDs of the currently connected neighbors.
+type NeighborsRetrieveFunc func() []identity.ID
+
 // NewRequester creates a new message requester.
 func NewRequester(tangle *Tangle, optionalOptions ...RequesterOption) *Requester {
 	requester := &Requester{
 		tangle:            tangle,
-		scheduledRequests: make(map[MessageID]*time.Timer),
+		scheduledRequests: make(map[MessageID]*messageRequest),
+		messageSources:    make(map[MessageID]identity.ID),
 		options:           newRequesterOptions(optionalOptions),
 		Events: &MessageRequesterEvents{
-			SendRequest: events.NewEvent(sendRequestEventHandler),
+			SendRequest:   events.NewEvent(sendRequestEventHandler),
+			RequestFailed: events.NewEvent(messageIDEventHandler),
 		},
 	}
 
@@ -73,7 +136,9 @@ func NewRequester(tangle *Tangle, optionalOptions ...RequesterOption) *Requester
 	defer requester.scheduledRequestsMutex.Unlock()
 
 	for _, id := range tangle.Storage.MissingMessages() {
-		requester.scheduledRequests[id] = time.AfterFunc(requester.options.retryInterval, requester.createReRequest(id, 0))
+		request := &messageRequest{}
+		request.timer = time.AfterFunc(requester.options.retryInterval, requester.createReRequest(id))
+		requester.scheduledRequests[id] = request
 	}
 
 	return requester
@@ -81,24 +146,33 @@ func NewRequester(tangle *Tangle, optionalOptions ...RequesterOption) *Requester
 
 // Setup sets up the behavior of the component by making it attach to the relevant events of other components.
 func (r *Requester) Setup() {
+	r.tangle.Parser.Events.MessageParsed.Attach(events.NewClosure(r.rememberSource))
 	r.tangle.Solidifier.Events.MessageMissing.Attach(events.NewClosure(r.StartRequest))
 	r.tangle.Storage.Events.MissingMessageStored.Attach(events.NewClosure(r.StopRequest))
+	r.tangle.Scheduler.Events.MessageDiscarded.Attach(events.NewClosure(r.StopRequest))
+}
+
+// SetNeighborsRetriever sets the function that is used to retrieve the neighbors that retries are sent to. Without it,
+// all retries are sent to all neighbors.
+func (r *Requester) SetNeighborsRetriever(neighborsRetriever NeighborsRetrieveFunc) {
+	r.scheduledRequestsMutex.Lock()
+	defer r.scheduledRequestsMutex.Unlock()
+
+	r.neighborsRetriever = neighborsRetriever
 }
 
-// StartRequest initiates a regular triggering of the StartRequest event until it has been stopped using StopRequest.
+// StartRequest schedules the requests of the given message until it has been stopped using StopRequest.
 func (r *Requester) StartRequest(id MessageID) {
 	r.scheduledRequestsMutex.Lock()
+	defer r.scheduledRequestsMutex.Unlock()
 
 	// ignore already scheduled requests
 	if _, exists := r.scheduledRequests[id]; exists {
-		r.scheduledRequestsMutex.Unlock()
 		return
 	}
 
-	// schedule the next request and trigger the event
-	r.scheduledRequests[id] = time.AfterFunc(r.options.retryInterval, r.createReRequest(id, 0))
-	r.scheduledRequestsMutex.Unlock()
-	r.Events.SendRequest.Trigger(&SendRequestEvent{ID: id})
+	r.scheduledRequests[id] = &messageRequest{}
+	r.enqueueRequest(id)
 }
 
 // StopRequest stops requests for the given message to further happen.
@@ -106,45 +180,196 @@ func (r *Requester) StopRequest(id MessageID) {
 	r.scheduledRequestsMutex.Lock()
 	defer r.scheduledRequestsMutex.Unlock()
 
-	if timer, ok := r.scheduledRequests[id]; ok {
-		timer.Stop()
+	if request, ok := r.scheduledRequests[id]; ok {
+		if request.timer != nil {
+			request.timer.Stop()
+		}
 		delete(r.scheduledRequests, id)
 	}
+	delete(r.messageSources, id)
+}
+
+// RequestQueueSize returns the number of scheduled message requests.
+func (r *Requester) RequestQueueSize() int {
+	r.scheduledRequestsMutex.RLock()
+	defer r.scheduledRequestsMutex.RUnlock()
+	return len(r.scheduledRequests)
+}
+
+// rememberSource remembers the sender of the parsed message as the neighbor that the missing parents of the message
+// are requested from first.
+func (r *Requester) rememberSource(msgParsedEvent *MessageParsedEvent) {
+	if msgParsedEvent.Peer == nil {
+		return
+	}
+	source := msgParsedEvent.Peer.ID()
+
+	msgParsedEvent.Message.ForEachParent(func(parent Parent) {
+		if parent.ID == EmptyMessageID || r.tangle.Storage.Message(parent.ID).Consume(func(*Message) {}) {
+			return
+		}
+
+		r.scheduledRequestsMutex.Lock()
+		defer r.scheduledRequestsMutex.Unlock()
+
+		if request, exists := r.scheduledRequests[parent.ID]; exists {
+			if request.attempts == 0 && !request.hasSource {
+				request.source, request.hasSource = source, true
+			}
+			return
+		}
+
+		// the parent might be marked as missing after the message is parsed, so we keep the sender until then
+		if _, exists := r.messageSources[parent.ID]; !exists {
+			// forget the senders of messages that never went missing, if there are too many
+			if len(r.messageSources) >= maxMessageSources {
+				r.messageSources = make(map[MessageID]identity.ID)
+			}
+			r.messageSources[parent.ID] = source
+		}
+	})
+}
+
+// enqueueRequest adds the given message to the next batch of requests. The caller must hold the lock.
+func (r *Requester) enqueueRequest(id MessageID) {
+	r.pendingRequests = append(r.pendingRequests, id)
+
+	if r.batchTimer == nil {
+		r.batchTimer = time.AfterFunc(r.options.batchInterval, r.sendRequests)
+	}
+	if len(r.pendingRequests) >= r.options.maxBatchSize {
+		r.batchTimer.Reset(0)
+	}
 }
 
-func (r *Requester) reRequest(id MessageID, count int) {
-	r.Events.SendRequest.Trigger(&SendRequestEvent{ID: id})
+// sendRequests sends all pending requests grouped by the neighbor they are sent to and gives up the messages that were
+// requested too often.
+func (r *Requester) sendRequests() {
+	batches, failedRequests := r.createRequestBatches()
 
-	// as we schedule a request at most once per id we do not need to make the trigger and the re-schedule atomic
+	for _, id := range failedRequests {
+		r.tangle.Storage.DeleteMissingMessage(id)
+		r.Events.RequestFailed.Trigger(id)
+	}
+
+	for _, batch := range batches {
+		for start := 0; start < len(batch.IDs); start += r.options.maxBatchSize {
+			end := start + r.options.maxBatchSize
+			if end > len(batch.IDs) {
+				end = len(batch.IDs)
+			}
+			r.Events.SendRequest.Trigger(&SendRequestEvent{IDs: batch.IDs[start:end], Peer: batch.Peer})
+		}
+	}
+}
+
+// createRequestBatches groups the pending requests by the neighbor they are sent to and schedules their retries.
+func (r *Requester) createRequestBatches() (batches []*SendRequestEvent, failedRequests MessageIDs) {
 	r.scheduledRequestsMutex.Lock()
 	defer r.scheduledRequestsMutex.Unlock()
 
-	// reschedule, if the request has not been stopped in the meantime
-	if _, exists := r.scheduledRequests[id]; exists {
-		// increase the request counter
-		count++
+	pendingRequests := r.pendingRequests
+	r.pendingRequests = nil
+	r.batchTimer = nil
+
+	var neighbors []identity.ID
+	if r.neighborsRetriever != nil {
+		neighbors = r.neighborsRetriever()
+	}
 
-		// if we have requested too often => stop the requests
-		if count > maxRequestThreshold {
+	batchesByPeer := make(map[identity.ID]*SendRequestEvent)
+	for _, id := range pendingRequests {
+		request, exists := r.scheduledRequests[id]
+		if !exists {
+			continue
+		}
+
+		if request.attempts >= r.options.maxRequestAttempts {
 			delete(r.scheduledRequests, id)
+			failedRequests = append(failedRequests, id)
+			continue
+		}
 
-			return
+		if source, exists := r.messageSources[id]; exists {
+			if request.attempts == 0 && !request.hasSource {
+				request.source, request.hasSource = source, true
+			}
+			delete(r.messageSources, id)
 		}
 
-		r.scheduledRequests[id] = time.AfterFunc(r.options.retryInterval, r.createReRequest(id, count))
-		return
+		peer := request.nextPeer(neighbors)
+		batch, exists := batchesByPeer[peer]
+		if !exists {
+			batch = &SendRequestEvent{Peer: peer}
+			batchesByPeer[peer] = batch
+			batches = append(batches, batch)
+		}
+		batch.IDs = append(batch.IDs, id)
+
+		request.timer = time.AfterFunc(r.retryInterval(request.attempts), r.createReRequest(id))
+		request.attempts++
 	}
+
+	return batches, failedRequests
 }
 
-// RequestQueueSize returns the number of scheduled message requests.
-func (r *Requester) RequestQueueSize() int {
-	r.scheduledRequestsMutex.RLock()
-	defer r.scheduledRequestsMutex.RUnlock()
-	return len(r.scheduledRequests)
+// retryInterval returns the time to wait before the message is requested again after the given number of attempts.
+func (r *Requester) retryInterval(attempts int) time.Duration {
+	interval := r.options.retryInterval
+	for i := 0; i < attempts && interval < r.options.maxRetryInterval; i++ {
+		interval *= 2
+	}
+	if interval > r.options.maxRetryInterval {
+		interval = r.options.maxRetryInterval
+	}
+
+	return interval
 }
 
-func (r *Requester) createReRequest(msgID MessageID, count int) func() {
-	return func() { r.reRequest(msgID, count) }
+func (r *Requester) reRequest(id MessageID) {
+	r.scheduledRequestsMutex.Lock()
+	defer r.scheduledRequestsMutex.Unlock()
+
+	// reschedule, if the request has not been stopped in the meantime
+	if _, exists := r.scheduledRequests[id]; exists {
+		r.enqueueRequest(id)
+	}
+}
+
+func (r *Requester) createReRequest(msgID MessageID) func() {
+	return func() { r.reRequest(msgID) }
+}
+
+// messageRequest contains the state of the requests of a single missing message.
+type messageRequest struct {
+	timer              *time.Timer
+	attempts           int
+	source             identity.ID
+	hasSource          bool
+	requestedNeighbors map[identity.ID]types.Empty
+}
+
+// nextPeer returns the neighbor that the next request is sent to. The first request is sent to the neighbor that sent
+// the referencing message, every retry to the next one of the given neighbors that was not asked yet. Once all of them
+// were asked, it returns an empty ID, so that the request is sent to all neighbors.
+func (m *messageRequest) nextPeer(neighbors []identity.ID) (peer identity.ID) {
+	if m.requestedNeighbors == nil {
+		m.requestedNeighbors = make(map[identity.ID]types.Empty)
+	}
+
+	if m.attempts == 0 && m.hasSource {
+		m.requestedNeighbors[m.source] = types.Void
+		return m.source
+	}
+
+	for _, neighbor := range neighbors {
+		if _, requested := m.requestedNeighbors[neighbor]; !requested {
+			m.requestedNeighbors[neighbor] = types.Void
+			return neighbor
+		}
+	}
+
+	return identity.ID{}
 }
 
 // endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -153,8 +378,11 @@ func (r *Requester) createReRequest(msgID MessageID, count int) func() {
 
 // MessageRequesterEvents represents events happening on a message requester.
 type MessageRequesterEvents struct {
-	// Fired when a request for a given message should be sent.
+	// Fired when a request for a given batch of messages should be sent.
 	SendRequest *events.Event
+
+	// Fired when a missing message was given up after the maximum number of request attempts.
+	RequestFailed *events.Event
 }
 
 // endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -163,7 +391,12 @@ type MessageRequesterEvents struct {
 
 // SendRequestEvent represents the parameters of sendRequestEventHandler
 type SendRequestEvent struct {
-	ID MessageID
+	// IDs contains the ids of the requested messages.
+	IDs MessageIDs
+
+	// Peer contains the ID of the neighbor that the messages are requested from. If it is empty, the messages are
+	// requested from all neighbors.
+	Peer identity.ID
 }
 
 func sendRequestEventHandler(handler interface{}, params ...interface{}) {
@@ -0,0 +1,205 @@
+package tangle
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/hive.go/autopeering/peer"
+	"github.com/iotaledger/hive.go/autopeering/peer/service"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRequesterTimeout = time.Second
+
+func TestRequester_Batching(t *testing.T) {
+	tangle := New()
+	defer tangle.Shutdown()
+
+	requester := NewRequester(tangle, RetryInterval(time.Minute), RequestBatchInterval(10*time.Millisecond))
+	sentRequests := make(chan *SendRequestEvent, 10)
+	requester.Events.SendRequest.Attach(events.NewClosure(func(ev *SendRequestEvent) { sentRequests <- ev }))
+
+	ids := MessageIDs{randomMessageID(), randomMessageID(), randomMessageID()}
+	for _, id := range ids {
+		requester.StartRequest(id)
+	}
+	// requests of already scheduled messages are ignored
+	requester.StartRequest(ids[0])
+	assert.Equal(t, len(ids), requester.RequestQueueSize())
+
+	select {
+	case ev := <-sentRequests:
+		assert.ElementsMatch(t, ids, ev.IDs)
+		assert.Equal(t, identity.ID{}, ev.Peer)
+	case <-time.After(testRequesterTimeout):
+		t.Fatal("request not sent")
+	}
+
+	select {
+	case ev := <-sentRequests:
+		t.Fatalf("unexpected request: %v", ev.IDs)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRequester_MaxBatchSize(t *testing.T) {
+	tangle := New()
+	defer tangle.Shutdown()
+
+	requester := NewRequester(tangle, RetryInterval(time.Minute), RequestBatchInterval(time.Minute), MaxRequestBatchSize(2))
+	sentRequests := make(chan *SendRequestEvent, 10)
+	requester.Events.SendRequest.Attach(events.NewClosure(func(ev *SendRequestEvent) { sentRequests <- ev }))
+
+	// a full batch is sent without waiting for the batch interval
+	requester.StartRequest(randomMessageID())
+	requester.StartRequest(randomMessageID())
+
+	select {
+	case ev := <-sentRequests:
+		assert.Len(t, ev.IDs, 2)
+	case <-time.After(testRequesterTimeout):
+		t.Fatal("request not sent")
+	}
+}
+
+func TestRequester_Source(t *testing.T) {
+	tangle := New()
+	defer tangle.Shutdown()
+
+	requester := NewRequester(tangle, RetryInterval(20*time.Millisecond), RequestBatchInterval(10*time.Millisecond))
+	sentRequests := make(chan *SendRequestEvent, 10)
+	requester.Events.SendRequest.Attach(events.NewClosure(func(ev *SendRequestEvent) { sentRequests <- ev }))
+
+	// the referencing message is parsed before its parent is marked as missing
+	missingID := randomMessageID()
+	source := newTestPeer()
+	requester.rememberSource(&MessageParsedEvent{
+		Message: newTestParentsDataMessage("child", []MessageID{missingID}, []MessageID{}),
+		Peer:    source,
+	})
+	requester.StartRequest(missingID)
+
+	// the first request is sent to the neighbor that sent the referencing message
+	select {
+	case ev := <-sentRequests:
+		assert.Equal(t, MessageIDs{missingID}, ev.IDs)
+		assert.Equal(t, source.ID(), ev.Peer)
+	case <-time.After(testRequesterTimeout):
+		t.Fatal("request not sent")
+	}
+
+	// all further requests are sent to all neighbors
+	select {
+	case ev := <-sentRequests:
+		assert.Equal(t, MessageIDs{missingID}, ev.IDs)
+		assert.Equal(t, identity.ID{}, ev.Peer)
+	case <-time.After(testRequesterTimeout):
+		t.Fatal("request not retried")
+	}
+}
+
+func TestRequester_NeighborRotation(t *testing.T) {
+	tangle := New()
+	defer tangle.Shutdown()
+
+	requester := NewRequester(tangle, RetryInterval(10*time.Millisecond), MaxRetryInterval(10*time.Millisecond), RequestBatchInterval(time.Millisecond))
+	sentRequests := make(chan *SendRequestEvent, 10)
+	requester.Events.SendRequest.Attach(events.NewClosure(func(ev *SendRequestEvent) { sentRequests <- ev }))
+
+	source, neighborA, neighborB := newTestPeer(), newTestPeer(), newTestPeer()
+	requester.SetNeighborsRetriever(func() []identity.ID {
+		return []identity.ID{source.ID(), neighborA.ID(), neighborB.ID()}
+	})
+
+	missingID := randomMessageID()
+	requester.rememberSource(&MessageParsedEvent{
+		Message: newTestParentsDataMessage("child", []MessageID{missingID}, []MessageID{}),
+		Peer:    source,
+	})
+	requester.StartRequest(missingID)
+
+	// every neighbor is asked once before the message is requested from all neighbors at once
+	for _, expectedPeer := range []identity.ID{source.ID(), neighborA.ID(), neighborB.ID(), {}, {}} {
+		select {
+		case ev := <-sentRequests:
+			assert.Equal(t, MessageIDs{missingID}, ev.IDs)
+			assert.Equal(t, expectedPeer, ev.Peer)
+		case <-time.After(testRequesterTimeout):
+			t.Fatal("request not retried")
+		}
+	}
+	requester.StopRequest(missingID)
+}
+
+func TestRequester_RequestFailed(t *testing.T) {
+	tangle := New()
+	defer tangle.Shutdown()
+
+	const maxAttempts = 3
+	requester := NewRequester(tangle, RetryInterval(5*time.Millisecond), RequestBatchInterval(time.Millisecond), MaxRequestAttempts(maxAttempts))
+
+	sentRequests := make(chan *SendRequestEvent, 10)
+	requester.Events.SendRequest.Attach(events.NewClosure(func(ev *SendRequestEvent) { sentRequests <- ev }))
+	failedRequests := make(chan MessageID, 1)
+	requester.Events.RequestFailed.Attach(events.NewClosure(func(id MessageID) { failedRequests <- id }))
+
+	missingID := randomMessageID()
+	cachedMissingMessage, stored := tangle.Storage.StoreMissingMessage(NewMissingMessage(missingID))
+	require.True(t, stored)
+	cachedMissingMessage.Release()
+	requester.StartRequest(missingID)
+
+	select {
+	case id := <-failedRequests:
+		assert.Equal(t, missingID, id)
+	case <-time.After(testRequesterTimeout):
+		t.Fatal("request not given up")
+	}
+
+	assert.Len(t, sentRequests, maxAttempts)
+	assert.Zero(t, requester.RequestQueueSize())
+	assert.NotContains(t, tangle.Storage.MissingMessages(), missingID)
+}
+
+func TestRequester_StopRequest(t *testing.T) {
+	tangle := New()
+	defer tangle.Shutdown()
+
+	requester := NewRequester(tangle, RetryInterval(10*time.Millisecond), RequestBatchInterval(10*time.Millisecond))
+	sentRequests := make(chan *SendRequestEvent, 10)
+	requester.Events.SendRequest.Attach(events.NewClosure(func(ev *SendRequestEvent) { sentRequests <- ev }))
+
+	missingID := randomMessageID()
+	requester.StartRequest(missingID)
+	requester.StopRequest(missingID)
+	assert.Zero(t, requester.RequestQueueSize())
+
+	select {
+	case ev := <-sentRequests:
+		t.Fatalf("unexpected request: %v", ev.IDs)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRequester_RetryInterval(t *testing.T) {
+	tangle := New()
+	defer tangle.Shutdown()
+
+	requester := NewRequester(tangle, RetryInterval(time.Second), MaxRetryInterval(5*time.Second))
+	assert.Equal(t, time.Second, requester.retryInterval(0))
+	assert.Equal(t, 2*time.Second, requester.retryInterval(1))
+	assert.Equal(t, 4*time.Second, requester.retryInterval(2))
+	assert.Equal(t, 5*time.Second, requester.retryInterval(3))
+	assert.Equal(t, 5*time.Second, requester.retryInterval(100))
+}
+
+func newTestPeer() *peer.Peer {
+	services := service.New()
+	services.Update(service.PeeringKey, "udp", 8000)
+
+	return peer.NewPeer(identity.GenerateIdentity(), net.IPv4zero, services)
+}
@@ -1,12 +1,29 @@
 package tangle
 
 import (
-	"fmt"
+	"math"
+	"sync"
 	"time"
 
 	"github.com/iotaledger/goshimmer/packages/clock"
 	"github.com/iotaledger/hive.go/async"
 	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/identity"
+)
+
+const (
+	// DefaultSchedulerRate defines the default time that the Scheduler waits between scheduling two messages.
+	DefaultSchedulerRate = 200 * time.Microsecond
+
+	// DefaultMaxBufferSize defines the default maximum size (in bytes) of all the messages in the buffer of the Scheduler.
+	DefaultMaxBufferSize = 100 * MaxMessageSize
+
+	// MinMana defines the access mana that is assumed for issuers that have less (or no) access mana at all, so that
+	// they can still issue messages at a minimal rate.
+	MinMana = 1.0
+
+	// MaxDeficit defines the maximum deficit (in bytes) that an issuer can accumulate while it has nothing to schedule.
+	MaxDeficit = MaxMessageSize
 )
 
 var (
@@ -22,21 +39,26 @@ type MessageToSchedule struct {
 	ID          MessageID
 	parents     []MessageID
 	issuingTime time.Time
-	scheduled   bool
+	issuerID    identity.ID
+	size        int
 }
 
-// Scheduler implements the scheduler.
+// Scheduler implements a deficit round robin scheduler that keeps one queue per issuer and serves the queues
+// proportionally to the access mana of the issuers, so that a single (spamming) issuer can not starve the others.
 type Scheduler struct {
 	Events *SchedulerEvents
 
 	tangle *Tangle
 
 	inbox            chan *MessageToSchedule
-	timeQueue        *TimeMessageQueue
+	buffer           *BufferQueue
+	deficits         map[identity.ID]float64
 	parentsMap       SchedulerParentPriorityMap
 	messagesBooked   chan MessageID
 	outboxWorkerPool async.WorkerPool
+	ticker           *time.Ticker
 	close            chan interface{}
+	bufferMutex      sync.RWMutex
 }
 
 // NewScheduler returns a new Scheduler.
@@ -44,10 +66,12 @@ func NewScheduler(tangle *Tangle) (scheduler *Scheduler) {
 	scheduler = &Scheduler{
 		Events: &SchedulerEvents{
 			MessageScheduled: events.NewEvent(messageIDEventHandler),
+			MessageDiscarded: events.NewEvent(messageIDEventHandler),
 		},
 		tangle:         tangle,
 		inbox:          make(chan *MessageToSchedule, capacity),
-		timeQueue:      NewTimeMessageQueue(capacity),
+		buffer:         NewBufferQueue(tangle.Options.SchedulerParams.MaxBufferSize),
+		deficits:       make(map[identity.ID]float64),
 		parentsMap:     make(SchedulerParentPriorityMap),
 		messagesBooked: make(chan MessageID, capacity),
 		close:          make(chan interface{}),
@@ -65,12 +89,44 @@ func (s *Scheduler) Setup() {
 	s.start()
 }
 
+// BufferSize returns the total size (in bytes) of all the messages that are waiting in the buffer of the Scheduler.
+func (s *Scheduler) BufferSize() int {
+	s.bufferMutex.RLock()
+	defer s.bufferMutex.RUnlock()
+
+	return s.buffer.Size()
+}
+
+// NodeQueueSize returns the total size (in bytes) of all the messages of the given issuer that are waiting in the
+// buffer of the Scheduler.
+func (s *Scheduler) NodeQueueSize(nodeID identity.ID) int {
+	s.bufferMutex.RLock()
+	defer s.bufferMutex.RUnlock()
+
+	nodeQueue := s.buffer.NodeQueue(nodeID)
+	if nodeQueue == nil {
+		return 0
+	}
+
+	return nodeQueue.Size()
+}
+
+// Deficit returns the current deficit (in bytes) of the given issuer.
+func (s *Scheduler) Deficit(nodeID identity.ID) float64 {
+	s.bufferMutex.RLock()
+	defer s.bufferMutex.RUnlock()
+
+	return s.deficits[nodeID]
+}
+
 func (s *Scheduler) onMessageSolid(messageID MessageID) {
 	s.tangle.Storage.Message(messageID).Consume(func(message *Message) {
 		s.inbox <- &MessageToSchedule{
 			ID:          messageID,
 			issuingTime: message.IssuingTime(),
 			parents:     message.Parents(),
+			issuerID:    identity.NewID(message.IssuerPublicKey()),
+			size:        len(message.Bytes()),
 		}
 	})
 }
@@ -81,33 +137,27 @@ func (s *Scheduler) onMessageBooked(messageID MessageID) {
 
 // start starts the scheduler.
 func (s *Scheduler) start() {
+	s.ticker = time.NewTicker(s.tangle.Options.SchedulerParams.Rate)
+
 	go func() {
-		s.timeQueue.Start()
 		for {
 			select {
 
-			// read message from the inbox, add them to the timeQueue if their timestamp
-			// is in the future or schedule it if its parents have been booked already.
+			// add new messages to the buffer and mark them ready if their parents have been booked already.
 			case message := <-s.inbox:
-				if message != nil && message.issuingTime.After(clock.SyncedTime()) {
-					s.timeQueue.Add(message)
-					break
+				if message != nil {
+					s.submit(message)
 				}
-				s.trySchedule(message)
 
-			// try to schedule messsage that have been waiting and are now current.
-			case message := <-s.timeQueue.C:
-				s.trySchedule(message)
-
-			// schedule messages that were waiting for their parents to be booked.
+			// mark messages as ready that were waiting for their parents to be booked.
 			case messageID := <-s.messagesBooked:
-				for _, child := range s.parentsMap[messageID] {
-					if s.messageReady(child) && !child.scheduled {
-						child.scheduled = true
-						s.schedule(child.ID)
-					}
+				s.onParentBooked(messageID)
+
+			// schedule the next message according to the deficit round robin.
+			case <-s.ticker.C:
+				if message := s.schedule(); message != nil {
+					s.triggerScheduled(message.ID)
 				}
-				delete(s.parentsMap, messageID)
 
 			case <-s.close:
 				return
@@ -121,169 +171,181 @@ func (s *Scheduler) Shutdown() {
 	close(s.close)
 	close(s.inbox)
 
+	if s.ticker != nil {
+		s.ticker.Stop()
+	}
 	s.outboxWorkerPool.ShutdownGracefully()
-	s.timeQueue.Stop()
 }
 
-func (s *Scheduler) schedule(messageID MessageID) {
-	s.outboxWorkerPool.Submit(func() {
-		s.Events.MessageScheduled.Trigger(messageID)
-	})
-}
+// submit adds the message to the buffer and drops the messages of the worst offender if the buffer is full.
+func (s *Scheduler) submit(message *MessageToSchedule) {
+	s.bufferMutex.Lock()
+	dropped := s.buffer.Submit(message, s.weight)
+	s.bufferMutex.Unlock()
 
-func (s *Scheduler) trySchedule(message *MessageToSchedule) {
-	if message == nil {
-		return
+	for _, droppedMessage := range dropped {
+		s.discard(droppedMessage)
 	}
 
-	// schedule if all the parents have been booked already.
+	// the message itself might have been dropped
+	for _, droppedMessage := range dropped {
+		if droppedMessage.ID == message.ID {
+			return
+		}
+	}
+
+	// mark the message as ready if all the parents have been booked already.
 	parentsToBook := s.parentsToBook(message)
 	if len(parentsToBook) == 0 {
-		s.schedule(message.ID)
+		s.ready(message)
 		return
 	}
 
 	// append the message to the unbooked parent(s) queue(s).
 	for _, parent := range parentsToBook {
-		if _, exist := s.parentsMap[parent]; !exist {
-			s.parentsMap[parent] = make([]*MessageToSchedule, 0)
-		}
 		s.parentsMap[parent] = append(s.parentsMap[parent], message)
 	}
 }
 
-func (s *Scheduler) messageReady(message *MessageToSchedule) (ready bool) {
-	return len(s.parentsToBook(message)) == 0
+// onParentBooked marks all the children that were waiting for the given message as ready.
+func (s *Scheduler) onParentBooked(messageID MessageID) {
+	for _, child := range s.parentsMap[messageID] {
+		if s.messageReady(child) {
+			s.ready(child)
+		}
+	}
+	delete(s.parentsMap, messageID)
 }
 
-func (s *Scheduler) parentsToBook(message *MessageToSchedule) (parents MessageIDs) {
-	for _, parentID := range message.parents {
-		s.tangle.Storage.MessageMetadata(parentID).Consume(func(messageMetadata *MessageMetadata) {
-			if !messageMetadata.IsBooked() {
-				parents = append(parents, parentID)
-			}
-		})
-	}
+// ready marks the message as ready to be scheduled.
+func (s *Scheduler) ready(message *MessageToSchedule) {
+	s.bufferMutex.Lock()
+	defer s.bufferMutex.Unlock()
 
-	return parents
+	s.buffer.Ready(message)
 }
 
-// region TimeMessageQueue /////////////////////////////////////////////////////////////////////////////////////////////
+// discard triggers the MessageDiscarded event for the given message and for all of its children that were waiting for
+// it to be booked, since they can not become ready anymore.
+func (s *Scheduler) discard(message *MessageToSchedule) {
+	s.Events.MessageDiscarded.Trigger(message.ID)
 
-// TimeMessageQueue is a time-based ordered queue.
-type TimeMessageQueue struct {
-	list    timeIssuanceSortedList
-	C       chan *MessageToSchedule
-	addChan chan *MessageToSchedule
-	timer   *time.Timer
-	close   chan interface{}
-}
+	children := s.parentsMap[message.ID]
+	delete(s.parentsMap, message.ID)
+	for _, child := range children {
+		s.bufferMutex.Lock()
+		removedChild := s.buffer.Remove(child)
+		s.bufferMutex.Unlock()
 
-// NewTimeMessageQueue returns a new TimeMessageQueue.
-func NewTimeMessageQueue(capacity int) *TimeMessageQueue {
-	return &TimeMessageQueue{
-		list:    make(timeIssuanceSortedList, 0),
-		timer:   time.NewTimer(0),
-		addChan: make(chan *MessageToSchedule, capacity),
-		C:       make(chan *MessageToSchedule, capacity),
-		close:   make(chan interface{}),
+		if removedChild != nil {
+			s.discard(removedChild)
+		}
 	}
 }
 
-// Start starts the TimeMessageQueue.
-func (t *TimeMessageQueue) Start() {
-	go func() {
-		<-t.timer.C // ignore first timeout
-		var msg *MessageToSchedule
-		for {
-			select {
-			case <-t.close:
-				return
-			case msg = <-t.addChan:
-				t.add(msg)
-			case <-t.timer.C:
-				msg = t.Pop()
-				if msg != nil {
-					t.C <- msg
-				}
-			}
-		}
-	}()
-}
+// schedule selects the next message according to the deficit round robin and removes it from the buffer. Instead of
+// iterating over the rounds one by one, it computes how many rounds are needed until the first issuer has accumulated
+// enough deficit to schedule its oldest ready message and credits all issuers accordingly.
+func (s *Scheduler) schedule() *MessageToSchedule {
+	s.bufferMutex.Lock()
+	defer s.bufferMutex.Unlock()
 
-// Stop stops the TimeMessageQueue.
-func (t *TimeMessageQueue) Stop() {
-	t.timer.Stop()
-	close(t.close)
-}
+	start := s.buffer.Current()
+	if start == nil {
+		return nil
+	}
 
-// Add adds a message to the TimeMessageQueue.
-func (t *TimeMessageQueue) Add(message *MessageToSchedule) {
-	t.addChan <- message
-}
+	now := clock.SyncedTime()
+	rounds := math.Inf(1)
+	var schedulingNode *NodeQueue
+	for q := start; ; {
+		// a message can be scheduled, if it is ready and its issuing time is not in the future
+		if message := q.Front(); message != nil && !message.issuingTime.After(now) {
+			remainingDeficit := float64(message.size) - s.deficits[q.NodeID()]
+			if r := math.Max(remainingDeficit, 0) / s.weight(q.NodeID()); r < rounds {
+				rounds = r
+				schedulingNode = q
+			}
+		}
 
-// add adds a message to the TimeMessageQueue.
-func (t *TimeMessageQueue) add(message *MessageToSchedule) {
-	if (t.list.insert(message)) == 0 {
-		t.timer.Stop()
-		t.timer.Reset(time.Until(message.issuingTime))
+		if q = s.buffer.Next(); q == start {
+			break
+		}
 	}
-}
 
-// Pop returns the first message to schedule.
-func (t *TimeMessageQueue) Pop() (message *MessageToSchedule) {
-	if len(t.list) > 1 {
-		t.timer.Reset(time.Until(t.list[1].issuingTime))
+	if schedulingNode == nil {
+		return nil
 	}
 
-	return t.list.pop()
-}
-
-// region timeIssuanceSortedList /////////////////////////////////////////////////////////////////////////////////////////////
-
-type timeIssuanceSortedList []*MessageToSchedule
+	// increment the deficit of every issuer for the required number of rounds
+	if rounds > 0 {
+		for q := start; ; {
+			s.updateDeficit(q.NodeID(), s.weight(q.NodeID())*rounds)
 
-func (t *timeIssuanceSortedList) insert(message *MessageToSchedule) (position int) {
-	position = -1
-	if len(*t) == 0 {
-		position = 0
-	}
-	for i, m := range *t {
-		if message.issuingTime.Before(m.issuingTime) {
-			position = i
-			break
+			if q = s.buffer.Next(); q == start {
+				break
+			}
 		}
 	}
-	switch position {
-	case 0:
-		*t = append(timeIssuanceSortedList{message}, (*t)[:]...)
-	case -1:
-		*t = append(*t, message)
-	default:
-		*t = append((*t)[:position], append(timeIssuanceSortedList{message}, (*t)[position:]...)...)
+
+	// issuers before the scheduling node get their quantum for the current round as well
+	for q := start; q != schedulingNode; q = s.buffer.Next() {
+		s.updateDeficit(q.NodeID(), s.weight(q.NodeID()))
 	}
-	return
-}
 
-func (t *timeIssuanceSortedList) pop() (message *MessageToSchedule) {
-	if len(*t) == 0 {
-		return nil
+	message := s.buffer.PopFront()
+	s.updateDeficit(message.issuerID, -float64(message.size))
+	if s.buffer.NodeQueue(message.issuerID) == nil {
+		delete(s.deficits, message.issuerID)
 	}
 
-	message = (*t)[0]
-	*t = append(timeIssuanceSortedList{}, (*t)[1:]...)
+	return message
+}
+
+func (s *Scheduler) updateDeficit(nodeID identity.ID, delta float64) {
+	s.deficits[nodeID] = math.Min(s.deficits[nodeID]+delta, MaxDeficit)
+}
 
-	return
+// weight returns the access mana of the given issuer which determines its share of the throughput.
+func (s *Scheduler) weight(nodeID identity.ID) float64 {
+	return math.Max(s.tangle.Options.SchedulerParams.AccessManaRetrieveFunc(nodeID), MinMana)
 }
 
-func (t *timeIssuanceSortedList) String() (s string) {
-	for i, m := range *t {
-		s += fmt.Sprintf("%d - %v\n", i, m.issuingTime)
+func (s *Scheduler) triggerScheduled(messageID MessageID) {
+	s.outboxWorkerPool.Submit(func() {
+		s.Events.MessageScheduled.Trigger(messageID)
+	})
+}
+
+func (s *Scheduler) messageReady(message *MessageToSchedule) (ready bool) {
+	return len(s.parentsToBook(message)) == 0
+}
+
+func (s *Scheduler) parentsToBook(message *MessageToSchedule) (parents MessageIDs) {
+	for _, parentID := range message.parents {
+		s.tangle.Storage.MessageMetadata(parentID).Consume(func(messageMetadata *MessageMetadata) {
+			if !messageMetadata.IsBooked() {
+				parents = append(parents, parentID)
+			}
+		})
 	}
-	return
+
+	return parents
 }
 
-// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+// region SchedulerParams //////////////////////////////////////////////////////////////////////////////////////////////
+
+// SchedulerParams defines the parameters of the Scheduler.
+type SchedulerParams struct {
+	// Rate defines the time that the Scheduler waits between scheduling two messages.
+	Rate time.Duration
+
+	// MaxBufferSize defines the maximum size (in bytes) of all the messages in the buffer.
+	MaxBufferSize int
+
+	// AccessManaRetrieveFunc returns the access mana of the given node that is used to weight its share of the throughput.
+	AccessManaRetrieveFunc func(identity.ID) float64
+}
 
 // endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
 
@@ -293,6 +355,9 @@ func (t *timeIssuanceSortedList) String() (s string) {
 type SchedulerEvents struct {
 	// MessageScheduled is triggered when a message is ready to be scheduled.
 	MessageScheduled *events.Event
+
+	// MessageDiscarded is triggered when a message is dropped from the buffer of the Scheduler.
+	MessageDiscarded *events.Event
 }
 
 // endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -6,8 +6,12 @@ import (
 	"time"
 
 	"github.com/iotaledger/goshimmer/packages/clock"
+	"github.com/iotaledger/goshimmer/packages/tangle/payload"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
 	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/identity"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestScheduler(t *testing.T) {
@@ -90,24 +94,181 @@ func TestScheduler(t *testing.T) {
 	}
 }
 
-func TestTimeIssuanceSortedList(t *testing.T) {
-	now := time.Now()
-	list := timeIssuanceSortedList{
-		&MessageToSchedule{issuingTime: now},
-		&MessageToSchedule{issuingTime: now.Add(1 * time.Second)},
-		&MessageToSchedule{issuingTime: now.Add(3 * time.Second)},
+func TestSchedulerFairness(t *testing.T) {
+	issuers := createWallets(2)
+	mana := map[identity.ID]float64{
+		identity.NewID(issuers[0].publicKey()): 10,
+		identity.NewID(issuers[1].publicKey()): 30,
 	}
 
-	before := &MessageToSchedule{issuingTime: now.Add(-1 * time.Second)}
-	list.insert(before)
-	assert.Equal(t, before, list[0])
+	tangle := New(SchedulerConfig(SchedulerParams{
+		Rate:                   time.Hour,
+		MaxBufferSize:          DefaultMaxBufferSize,
+		AccessManaRetrieveFunc: func(nodeID identity.ID) float64 { return mana[nodeID] },
+	}))
+	defer tangle.Shutdown()
+
+	// both issuers fill the buffer with messages of the same size
+	for i := 0; i < 100; i++ {
+		for _, issuer := range issuers {
+			tangle.Scheduler.submit(newTestMessageToSchedule(newTestIssuerMessage(issuer.publicKey(), time.Now().Add(-time.Minute))))
+		}
+	}
+
+	scheduled := make(map[identity.ID]int)
+	for i := 0; i < 80; i++ {
+		message := tangle.Scheduler.schedule()
+		require.NotNil(t, message)
+		scheduled[message.issuerID]++
+	}
+
+	// the throughput is shared proportionally to the access mana
+	assert.InDelta(t, 20, scheduled[identity.NewID(issuers[0].publicKey())], 1)
+	assert.InDelta(t, 60, scheduled[identity.NewID(issuers[1].publicKey())], 1)
+}
+
+func TestSchedulerSpammer(t *testing.T) {
+	spammer, honest := createWallets(1)[0], createWallets(1)[0]
+
+	tangle := New(SchedulerConfig(SchedulerParams{
+		Rate:                   time.Hour,
+		MaxBufferSize:          DefaultMaxBufferSize,
+		AccessManaRetrieveFunc: func(identity.ID) float64 { return MinMana },
+	}))
+	defer tangle.Shutdown()
+
+	// the spammer submits its messages before the honest issuer
+	for i := 0; i < 90; i++ {
+		tangle.Scheduler.submit(newTestMessageToSchedule(newTestIssuerMessage(spammer.publicKey(), time.Now().Add(-time.Minute))))
+	}
+	for i := 0; i < 10; i++ {
+		tangle.Scheduler.submit(newTestMessageToSchedule(newTestIssuerMessage(honest.publicKey(), time.Now().Add(-time.Minute))))
+	}
+
+	scheduledHonest := 0
+	for i := 0; i < 20; i++ {
+		message := tangle.Scheduler.schedule()
+		require.NotNil(t, message)
+		if message.issuerID == identity.NewID(honest.publicKey()) {
+			scheduledHonest++
+		}
+	}
+
+	// the honest issuer is not starved by the spammer
+	assert.InDelta(t, 10, scheduledHonest, 1)
+}
+
+func TestSchedulerFutureMessages(t *testing.T) {
+	issuer := createWallets(1)[0]
+
+	tangle := New(SchedulerConfig(SchedulerParams{
+		Rate:                   time.Hour,
+		MaxBufferSize:          DefaultMaxBufferSize,
+		AccessManaRetrieveFunc: func(identity.ID) float64 { return MinMana },
+	}))
+	defer tangle.Shutdown()
+
+	tangle.Scheduler.submit(newTestMessageToSchedule(newTestIssuerMessage(issuer.publicKey(), time.Now().Add(time.Hour))))
+	assert.Nil(t, tangle.Scheduler.schedule())
+
+	current := newTestMessageToSchedule(newTestIssuerMessage(issuer.publicKey(), time.Now().Add(-time.Minute)))
+	tangle.Scheduler.submit(current)
+	assert.Equal(t, current, tangle.Scheduler.schedule())
+	assert.Nil(t, tangle.Scheduler.schedule())
+}
+
+func TestSchedulerDiscard(t *testing.T) {
+	spammer, honest := createWallets(1)[0], createWallets(1)[0]
+
+	message := newTestIssuerMessage(spammer.publicKey(), time.Now())
+	messageSize := len(message.Bytes())
+
+	tangle := New(SchedulerConfig(SchedulerParams{
+		Rate:                   time.Hour,
+		MaxBufferSize:          10 * messageSize,
+		AccessManaRetrieveFunc: func(identity.ID) float64 { return MinMana },
+	}))
+	defer tangle.Shutdown()
+	tangle.Storage.Setup()
+
+	discarded := make(map[MessageID]bool)
+	tangle.Scheduler.Events.MessageDiscarded.Attach(events.NewClosure(func(messageID MessageID) {
+		discarded[messageID] = true
+	}))
+
+	honestMessages := make([]*MessageToSchedule, 3)
+	for i := range honestMessages {
+		honestMessages[i] = newTestMessageToSchedule(newTestIssuerMessage(honest.publicKey(), time.Now().Add(-time.Minute)))
+		tangle.Scheduler.submit(honestMessages[i])
+	}
 
-	after := &MessageToSchedule{issuingTime: now.Add(5 * time.Second)}
-	list.insert(after)
-	assert.Equal(t, after, list[len(list)-1])
+	spamMessages := make([]*MessageToSchedule, 20)
+	for i := range spamMessages {
+		spamMessage := newTestIssuerMessage(spammer.publicKey(), time.Now().Add(time.Duration(i)*time.Millisecond))
+		tangle.Storage.StoreMessage(spamMessage)
+		spamMessages[i] = newTestMessageToSchedule(spamMessage)
+		tangle.Scheduler.submit(spamMessages[i])
+	}
 
-	between := &MessageToSchedule{issuingTime: now.Add(2 * time.Second)}
-	list.insert(between)
-	assert.Equal(t, between, list[3])
+	assert.LessOrEqual(t, tangle.Scheduler.BufferSize(), 10*messageSize)
+	assert.Equal(t, 3*messageSize, tangle.Scheduler.NodeQueueSize(identity.NewID(honest.publicKey())))
 
+	// only the oldest messages of the spammer got dropped
+	for _, honestMessage := range honestMessages {
+		assert.False(t, discarded[honestMessage.ID])
+	}
+	for i, spamMessage := range spamMessages {
+		assert.Equal(t, i < 13, discarded[spamMessage.ID])
+		// discarded messages are removed from the storage
+		assert.Equal(t, i >= 13, tangle.Storage.Message(spamMessage.ID).Consume(func(*Message) {}))
+	}
+}
+
+func TestBufferQueue(t *testing.T) {
+	issuers := createWallets(3)
+	weight := func(identity.ID) float64 { return MinMana }
+
+	buffer := NewBufferQueue(DefaultMaxBufferSize)
+	assert.Nil(t, buffer.Current())
+
+	messages := make([]*MessageToSchedule, len(issuers))
+	for i, issuer := range issuers {
+		messages[i] = newTestMessageToSchedule(newTestIssuerMessage(issuer.publicKey(), time.Now()))
+		assert.Empty(t, buffer.Submit(messages[i], weight))
+	}
+	assert.Empty(t, buffer.Submit(messages[0], weight))
+	assert.Equal(t, 3, buffer.NumActiveNodes())
+
+	// the nodes are served in the order they became active
+	for i := range issuers {
+		assert.Equal(t, messages[i].issuerID, buffer.Current().NodeID())
+		assert.Nil(t, buffer.Current().Front())
+		assert.True(t, buffer.Ready(messages[i]))
+		assert.Equal(t, messages[i], buffer.Current().Front())
+		buffer.Next()
+	}
+
+	assert.Equal(t, messages[0], buffer.PopFront())
+	assert.Equal(t, messages[1].issuerID, buffer.Current().NodeID())
+	assert.Equal(t, messages[2], buffer.Remove(messages[2]))
+	assert.Nil(t, buffer.Remove(messages[2]))
+	assert.Equal(t, messages[1], buffer.PopFront())
+
+	assert.Nil(t, buffer.Current())
+	assert.Equal(t, 0, buffer.NumActiveNodes())
+	assert.Equal(t, 0, buffer.Size())
+}
+
+func newTestIssuerMessage(issuerPublicKey ed25519.PublicKey, issuingTime time.Time) *Message {
+	return NewMessage([]MessageID{EmptyMessageID}, []MessageID{}, issuingTime, issuerPublicKey, 0, payload.NewGenericDataPayload([]byte("test")), 0, ed25519.Signature{})
+}
+
+func newTestMessageToSchedule(message *Message) *MessageToSchedule {
+	return &MessageToSchedule{
+		ID:          message.ID(),
+		parents:     message.Parents(),
+		issuingTime: message.IssuingTime(),
+		issuerID:    identity.NewID(message.IssuerPublicKey()),
+		size:        len(message.Bytes()),
+	}
 }
@@ -0,0 +1,366 @@
+package tangle
+
+import (
+	"container/heap"
+	"container/ring"
+
+	"github.com/iotaledger/hive.go/identity"
+)
+
+// region NodeQueue ////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// NodeQueue keeps track of all the messages of a single issuer that are waiting in the buffer of the Scheduler. Messages
+// that are ready to be scheduled are kept in a heap that is ordered by their issuing time.
+type NodeQueue struct {
+	nodeID    identity.ID
+	submitted map[MessageID]*MessageToSchedule
+	inbox     messageHeap
+	size      int
+}
+
+// NewNodeQueue returns a new, empty NodeQueue for the given issuer.
+func NewNodeQueue(nodeID identity.ID) *NodeQueue {
+	return &NodeQueue{
+		nodeID:    nodeID,
+		submitted: make(map[MessageID]*MessageToSchedule),
+		inbox:     make(messageHeap, 0),
+	}
+}
+
+// NodeID returns the identifier of the issuer whose messages are stored in the NodeQueue.
+func (q *NodeQueue) NodeID() identity.ID {
+	return q.nodeID
+}
+
+// Size returns the total size (in bytes) of all the messages in the NodeQueue.
+func (q *NodeQueue) Size() int {
+	return q.size
+}
+
+// Len returns the number of messages in the NodeQueue.
+func (q *NodeQueue) Len() int {
+	return len(q.submitted) + q.inbox.Len()
+}
+
+// Submit adds a message that is not yet ready to the NodeQueue. It returns false if the message was already contained.
+func (q *NodeQueue) Submit(message *MessageToSchedule) bool {
+	if q.contains(message.ID) {
+		return false
+	}
+
+	q.submitted[message.ID] = message
+	q.size += message.size
+
+	return true
+}
+
+// Ready marks a previously submitted message as ready to be scheduled. It returns false if the message was unknown.
+func (q *NodeQueue) Ready(message *MessageToSchedule) bool {
+	if _, exists := q.submitted[message.ID]; !exists {
+		return false
+	}
+
+	delete(q.submitted, message.ID)
+	heap.Push(&q.inbox, message)
+
+	return true
+}
+
+// Front returns the ready message with the oldest issuing time (or nil if no message is ready).
+func (q *NodeQueue) Front() *MessageToSchedule {
+	if q.inbox.Len() == 0 {
+		return nil
+	}
+
+	return q.inbox[0]
+}
+
+// PopFront removes and returns the ready message with the oldest issuing time (or nil if no message is ready).
+func (q *NodeQueue) PopFront() *MessageToSchedule {
+	if q.inbox.Len() == 0 {
+		return nil
+	}
+
+	message := heap.Pop(&q.inbox).(*MessageToSchedule)
+	q.size -= message.size
+
+	return message
+}
+
+// Remove removes the message with the given ID from the NodeQueue and returns it (or nil if it was not contained).
+func (q *NodeQueue) Remove(messageID MessageID) *MessageToSchedule {
+	if message, exists := q.submitted[messageID]; exists {
+		delete(q.submitted, messageID)
+		q.size -= message.size
+
+		return message
+	}
+
+	for i, message := range q.inbox {
+		if message.ID == messageID {
+			heap.Remove(&q.inbox, i)
+			q.size -= message.size
+
+			return message
+		}
+	}
+
+	return nil
+}
+
+// DropCandidate returns the message that gets dropped first if the issuer exceeds its share of the buffer: the one
+// with the oldest issuing time and, in case of a tie, the largest one.
+func (q *NodeQueue) DropCandidate() (candidate *MessageToSchedule) {
+	consider := func(message *MessageToSchedule) {
+		if candidate == nil || message.issuingTime.Before(candidate.issuingTime) ||
+			(message.issuingTime.Equal(candidate.issuingTime) && message.size > candidate.size) {
+			candidate = message
+		}
+	}
+
+	for _, message := range q.submitted {
+		consider(message)
+	}
+	for _, message := range q.inbox {
+		consider(message)
+	}
+
+	return
+}
+
+func (q *NodeQueue) contains(messageID MessageID) bool {
+	if _, exists := q.submitted[messageID]; exists {
+		return true
+	}
+	for _, message := range q.inbox {
+		if message.ID == messageID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region BufferQueue //////////////////////////////////////////////////////////////////////////////////////////////////
+
+// BufferQueue is the buffer of the Scheduler. It keeps one NodeQueue per active issuer and arranges them in a ring so
+// that they can be served in a round-robin fashion.
+type BufferQueue struct {
+	maxSize    int
+	activeNode map[identity.ID]*ring.Ring
+	ring       *ring.Ring
+	size       int
+}
+
+// NewBufferQueue returns a new BufferQueue that holds at most maxSize bytes.
+func NewBufferQueue(maxSize int) *BufferQueue {
+	return &BufferQueue{
+		maxSize:    maxSize,
+		activeNode: make(map[identity.ID]*ring.Ring),
+	}
+}
+
+// Size returns the total size (in bytes) of all the messages in the BufferQueue.
+func (b *BufferQueue) Size() int {
+	return b.size
+}
+
+// MaxSize returns the maximum size (in bytes) of the BufferQueue.
+func (b *BufferQueue) MaxSize() int {
+	return b.maxSize
+}
+
+// NumActiveNodes returns the number of issuers that currently have messages in the BufferQueue.
+func (b *BufferQueue) NumActiveNodes() int {
+	return len(b.activeNode)
+}
+
+// NodeQueue returns the NodeQueue of the given issuer (or nil if the issuer has no messages in the BufferQueue).
+func (b *BufferQueue) NodeQueue(nodeID identity.ID) *NodeQueue {
+	element, exists := b.activeNode[nodeID]
+	if !exists {
+		return nil
+	}
+
+	return element.Value.(*NodeQueue)
+}
+
+// Submit adds a message to the BufferQueue. If the buffer exceeds its maximum size afterwards, messages of the issuer
+// with the largest buffer usage relative to its weight get dropped until the size is within the limit again. All
+// dropped messages (which might include the submitted one) are returned.
+func (b *BufferQueue) Submit(message *MessageToSchedule, weight func(identity.ID) float64) (dropped []*MessageToSchedule) {
+	element, exists := b.activeNode[message.issuerID]
+	if !exists {
+		element = ring.New(1)
+		element.Value = NewNodeQueue(message.issuerID)
+	}
+
+	if !element.Value.(*NodeQueue).Submit(message) {
+		return nil
+	}
+	b.size += message.size
+
+	// the issuer only becomes active once it has at least one message in the buffer
+	if !exists {
+		b.activeNode[message.issuerID] = element
+		b.insertNode(element)
+	}
+
+	for b.size > b.maxSize {
+		worstOffender := b.worstOffender(weight)
+		droppedMessage := b.Remove(worstOffender.DropCandidate())
+		dropped = append(dropped, droppedMessage)
+	}
+
+	return dropped
+}
+
+// Ready marks a previously submitted message as ready to be scheduled.
+func (b *BufferQueue) Ready(message *MessageToSchedule) bool {
+	element, exists := b.activeNode[message.issuerID]
+	if !exists {
+		return false
+	}
+
+	return element.Value.(*NodeQueue).Ready(message)
+}
+
+// Remove removes the given message from the BufferQueue and returns it (or nil if it was not contained).
+func (b *BufferQueue) Remove(message *MessageToSchedule) *MessageToSchedule {
+	element, exists := b.activeNode[message.issuerID]
+	if !exists {
+		return nil
+	}
+
+	nodeQueue := element.Value.(*NodeQueue)
+	removedMessage := nodeQueue.Remove(message.ID)
+	if removedMessage == nil {
+		return nil
+	}
+	b.size -= removedMessage.size
+
+	if nodeQueue.Len() == 0 {
+		b.removeNode(nodeQueue.NodeID())
+	}
+
+	return removedMessage
+}
+
+// Current returns the NodeQueue that the round-robin currently points to (or nil if the BufferQueue is empty).
+func (b *BufferQueue) Current() *NodeQueue {
+	if b.ring == nil {
+		return nil
+	}
+
+	return b.ring.Value.(*NodeQueue)
+}
+
+// Next advances the round-robin to the next NodeQueue and returns it (or nil if the BufferQueue is empty).
+func (b *BufferQueue) Next() *NodeQueue {
+	if b.ring == nil {
+		return nil
+	}
+	b.ring = b.ring.Next()
+
+	return b.ring.Value.(*NodeQueue)
+}
+
+// PopFront removes and returns the oldest ready message of the current NodeQueue.
+func (b *BufferQueue) PopFront() *MessageToSchedule {
+	nodeQueue := b.Current()
+	if nodeQueue == nil {
+		return nil
+	}
+
+	message := nodeQueue.PopFront()
+	if message == nil {
+		return nil
+	}
+	b.size -= message.size
+
+	if nodeQueue.Len() == 0 {
+		b.removeNode(nodeQueue.NodeID())
+	}
+
+	return message
+}
+
+// insertNode adds a new NodeQueue right before the current position so that it gets served last in the current round.
+func (b *BufferQueue) insertNode(element *ring.Ring) {
+	if b.ring == nil {
+		b.ring = element
+		return
+	}
+
+	b.ring.Prev().Link(element)
+}
+
+// removeNode removes the NodeQueue of the given issuer from the round-robin.
+func (b *BufferQueue) removeNode(nodeID identity.ID) {
+	element, exists := b.activeNode[nodeID]
+	if !exists {
+		return
+	}
+	delete(b.activeNode, nodeID)
+
+	if element.Len() == 1 {
+		b.ring = nil
+		return
+	}
+
+	if element == b.ring {
+		b.ring = element.Next()
+	}
+	element.Prev().Unlink(1)
+}
+
+// worstOffender returns the NodeQueue that uses the largest part of the buffer relative to the weight of its issuer.
+func (b *BufferQueue) worstOffender(weight func(identity.ID) float64) (worstOffender *NodeQueue) {
+	maxScore := -1.0
+	for nodeID, element := range b.activeNode {
+		nodeQueue := element.Value.(*NodeQueue)
+		if score := float64(nodeQueue.Size()) / weight(nodeID); score > maxScore {
+			maxScore = score
+			worstOffender = nodeQueue
+		}
+	}
+
+	return
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region messageHeap //////////////////////////////////////////////////////////////////////////////////////////////////
+
+// messageHeap implements a heap of MessageToSchedule ordered by their issuing time.
+type messageHeap []*MessageToSchedule
+
+func (h messageHeap) Len() int {
+	return len(h)
+}
+
+func (h messageHeap) Less(i, j int) bool {
+	return h[i].issuingTime.Before(h[j].issuingTime)
+}
+
+func (h messageHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *messageHeap) Push(x interface{}) {
+	*h = append(*h, x.(*MessageToSchedule))
+}
+
+func (h *messageHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	message := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+
+	return message
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,556 @@
+package tangle
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"hash"
+	"io"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/marshalutil"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// SnapshotVersion defines the version of the snapshot file format that is written by the SnapshotWriter.
+	SnapshotVersion byte = 1
+
+	// SnapshotChecksumLength contains the length of the checksum that every snapshot file ends with.
+	SnapshotChecksumLength = blake2b.Size256
+
+	// snapshotMagicLength contains the amount of bytes of the magic that snapshot files start with.
+	snapshotMagicLength = 4
+
+	// snapshotRecordEnd marks the end of the Outputs in a snapshot file.
+	snapshotRecordEnd byte = 0
+
+	// snapshotRecordOutput marks an Output (and its OutputMetadata) in a snapshot file.
+	snapshotRecordOutput byte = 1
+
+	// snapshotLoadBatchSize contains the amount of Outputs that are stored at once when loading a snapshot.
+	snapshotLoadBatchSize = 1000
+
+	// maxSnapshotSolidEntryPoints contains the maximum amount of solid entry points that a snapshot file may contain.
+	maxSnapshotSolidEntryPoints = 1 << 18
+
+	// maxSnapshotRecordSize contains the maximum size of a marshaled Output or OutputMetadata in a snapshot file. As
+	// Outputs are created by Transactions, they can never exceed the size of a Message.
+	maxSnapshotRecordSize = MaxMessageSize
+
+	// snapshotHeaderFixedLength contains the amount of bytes of the header before the solid entry points.
+	snapshotHeaderFixedLength = snapshotMagicLength + marshalutil.Uint8Size + marshalutil.Uint32Size + marshalutil.TimeSize + marshalutil.Uint32Size
+)
+
+var (
+	// snapshotMagic contains the bytes that every snapshot file (except the legacy format) starts with.
+	snapshotMagic = []byte{'G', 'S', 'S', 'P'}
+
+	// ErrInvalidSnapshot is returned if a snapshot file is malformed or was written in an unsupported version.
+	ErrInvalidSnapshot = errors.New("invalid snapshot")
+
+	// ErrSnapshotChecksumMismatch is returned if the trailing hash of a snapshot file does not match its content.
+	ErrSnapshotChecksumMismatch = errors.New("snapshot checksum mismatch")
+)
+
+// IsVersionedSnapshot checks if the given reader starts with a snapshot in the versioned format (as opposed to the
+// legacy format) without consuming any bytes of it.
+func IsVersionedSnapshot(reader *bufio.Reader) bool {
+	magic, err := reader.Peek(snapshotMagicLength)
+
+	return err == nil && bytes.Equal(magic, snapshotMagic)
+}
+
+// region Snapshot /////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Snapshot represents a local snapshot of the node. It contains the confirmed and unspent Outputs of the ledger state
+// together with the solid entry points that allow to solidify the Messages that were issued after the snapshot.
+type Snapshot struct {
+	// NetworkID contains the identifier of the network that the snapshot belongs to.
+	NetworkID uint32
+
+	// Timestamp contains the time at which the snapshot was taken.
+	Timestamp time.Time
+
+	// SolidEntryPoints contains the MessageIDs that are considered solid without knowing their past cone.
+	SolidEntryPoints MessageIDs
+
+	// Outputs contains the confirmed and unspent Outputs (including their OutputIDs).
+	Outputs ledgerstate.Outputs
+}
+
+// SnapshotFromLegacy converts a snapshot in the legacy format into a Snapshot.
+func SnapshotFromLegacy(legacySnapshot ledgerstate.Snapshot, networkID uint32, timestamp time.Time) *Snapshot {
+	return &Snapshot{
+		NetworkID:        networkID,
+		Timestamp:        timestamp,
+		SolidEntryPoints: MessageIDs{},
+		Outputs:          legacySnapshot.Outputs(),
+	}
+}
+
+// Legacy converts the Snapshot into the legacy format. The legacy format only knows the balances per address of every
+// Transaction, so the solid entry points, the types and the indexes of the Outputs get lost in the conversion.
+func (s *Snapshot) Legacy() (legacySnapshot ledgerstate.Snapshot) {
+	legacySnapshot = make(ledgerstate.Snapshot)
+	for _, output := range s.Outputs {
+		transactionID := output.ID().TransactionID()
+		if _, exists := legacySnapshot[transactionID]; !exists {
+			legacySnapshot[transactionID] = make(map[ledgerstate.Address]*ledgerstate.ColoredBalances)
+		}
+
+		balances := make(map[ledgerstate.Color]uint64)
+		if existingBalances, exists := legacySnapshot[transactionID][output.Address()]; exists {
+			existingBalances.ForEach(func(color ledgerstate.Color, balance uint64) bool {
+				balances[color] += balance
+				return true
+			})
+		}
+		output.Balances().ForEach(func(color ledgerstate.Color, balance uint64) bool {
+			balances[color] += balance
+			return true
+		})
+		legacySnapshot[transactionID][output.Address()] = ledgerstate.NewColoredBalances(balances)
+	}
+
+	return
+}
+
+// WriteTo writes the Snapshot to the given writer using the versioned format of the SnapshotWriter.
+func (s *Snapshot) WriteTo(writer io.Writer) (int64, error) {
+	counter := &countingWriter{writer: writer}
+	snapshotWriter, err := NewSnapshotWriter(counter, &SnapshotHeader{
+		NetworkID:        s.NetworkID,
+		Timestamp:        s.Timestamp,
+		SolidEntryPoints: s.SolidEntryPoints,
+	})
+	if err != nil {
+		return counter.count, err
+	}
+
+	for _, output := range s.Outputs {
+		outputMetadata := ledgerstate.NewOutputMetadata(output.ID())
+		outputMetadata.SetBranchID(ledgerstate.MasterBranchID)
+		outputMetadata.SetSolid(true)
+		outputMetadata.SetFinalized(true)
+
+		if err = snapshotWriter.WriteOutput(output, outputMetadata); err != nil {
+			return counter.count, err
+		}
+	}
+
+	err = snapshotWriter.Close()
+
+	return counter.count, err
+}
+
+// ReadFrom reads a Snapshot in the versioned format from the given reader.
+// This function overrides existing content of the snapshot.
+func (s *Snapshot) ReadFrom(reader io.Reader) (int64, error) {
+	counter := &countingReader{reader: reader}
+	snapshotReader, err := NewSnapshotReader(counter)
+	if err != nil {
+		return counter.count, err
+	}
+
+	s.NetworkID = snapshotReader.Header().NetworkID
+	s.Timestamp = snapshotReader.Header().Timestamp
+	s.SolidEntryPoints = snapshotReader.Header().SolidEntryPoints
+	s.Outputs = make(ledgerstate.Outputs, 0)
+	for {
+		output, outputMetadata, readErr := snapshotReader.ReadOutput()
+		if readErr == io.EOF {
+			return counter.count, nil
+		}
+		if readErr != nil {
+			return counter.count, readErr
+		}
+		if !outputMetadata.Finalized() {
+			return counter.count, xerrors.Errorf("Output %s in snapshot is not finalized: %w", output.ID(), ErrInvalidSnapshot)
+		}
+
+		s.Outputs = append(s.Outputs, output)
+	}
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region SnapshotHeader ///////////////////////////////////////////////////////////////////////////////////////////////
+
+// SnapshotHeader contains the information that is stored at the beginning of a snapshot file.
+type SnapshotHeader struct {
+	// Version contains the version of the file format.
+	Version byte
+
+	// NetworkID contains the identifier of the network that the snapshot belongs to.
+	NetworkID uint32
+
+	// Timestamp contains the time at which the snapshot was taken.
+	Timestamp time.Time
+
+	// SolidEntryPoints contains the MessageIDs that are considered solid without knowing their past cone.
+	SolidEntryPoints MessageIDs
+}
+
+// Bytes returns a marshaled version of the SnapshotHeader.
+func (h *SnapshotHeader) Bytes() []byte {
+	marshalUtil := marshalutil.New(snapshotHeaderFixedLength + len(h.SolidEntryPoints)*MessageIDLength)
+	marshalUtil.WriteBytes(snapshotMagic)
+	marshalUtil.WriteByte(h.Version)
+	marshalUtil.WriteUint32(h.NetworkID)
+	marshalUtil.WriteTime(h.Timestamp)
+	marshalUtil.WriteUint32(uint32(len(h.SolidEntryPoints)))
+	for _, solidEntryPoint := range h.SolidEntryPoints {
+		marshalUtil.Write(solidEntryPoint)
+	}
+
+	return marshalUtil.Bytes()
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region SnapshotWriter ///////////////////////////////////////////////////////////////////////////////////////////////
+
+// SnapshotWriter writes a snapshot file incrementally in the following format:
+//
+//	magic(4byte) version(byte) network_id(uint32) timestamp(time)
+//	solid_entry_point_count(uint32)
+//	-> solid_entry_point_count * message_id(32byte)
+//	-> output_count * record_type(byte=1)+output_length(uint32)+output+metadata_length(uint32)+output_metadata
+//	record_type(byte=0) output_count(uint64)
+//	blake2b_256(32byte) of all the preceding bytes
+type SnapshotWriter struct {
+	bufferedWriter *bufio.Writer
+	hash           hash.Hash
+	writer         io.Writer
+	outputCount    uint64
+	closed         bool
+}
+
+// NewSnapshotWriter creates a new SnapshotWriter that writes the given header (using the current SnapshotVersion) to
+// the given writer.
+func NewSnapshotWriter(writer io.Writer, header *SnapshotHeader) (snapshotWriter *SnapshotWriter, err error) {
+	hasher, err := blake2b.New256(nil)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create hash: %w", err)
+	}
+
+	bufferedWriter := bufio.NewWriter(writer)
+	snapshotWriter = &SnapshotWriter{
+		bufferedWriter: bufferedWriter,
+		hash:           hasher,
+		writer:         io.MultiWriter(bufferedWriter, hasher),
+	}
+
+	if len(header.SolidEntryPoints) > maxSnapshotSolidEntryPoints {
+		return nil, xerrors.Errorf("snapshot contains %d solid entry points (max %d): %w", len(header.SolidEntryPoints), maxSnapshotSolidEntryPoints, ErrInvalidSnapshot)
+	}
+
+	versionedHeader := *header
+	versionedHeader.Version = SnapshotVersion
+	if _, err = snapshotWriter.writer.Write(versionedHeader.Bytes()); err != nil {
+		return nil, xerrors.Errorf("unable to write snapshot header: %w", err)
+	}
+
+	return
+}
+
+// WriteOutput writes the given Output together with its OutputMetadata to the snapshot.
+func (s *SnapshotWriter) WriteOutput(output ledgerstate.Output, outputMetadata *ledgerstate.OutputMetadata) (err error) {
+	if s.closed {
+		return xerrors.Errorf("unable to write output to closed snapshot: %w", ErrInvalidSnapshot)
+	}
+
+	outputBytes := output.Bytes()
+	outputMetadataBytes := outputMetadata.Bytes()
+
+	marshalUtil := marshalutil.New(marshalutil.Uint8Size + 2*marshalutil.Uint32Size + len(outputBytes) + len(outputMetadataBytes))
+	marshalUtil.WriteByte(snapshotRecordOutput)
+	marshalUtil.WriteUint32(uint32(len(outputBytes)))
+	marshalUtil.WriteBytes(outputBytes)
+	marshalUtil.WriteUint32(uint32(len(outputMetadataBytes)))
+	marshalUtil.WriteBytes(outputMetadataBytes)
+
+	if _, err = s.writer.Write(marshalUtil.Bytes()); err != nil {
+		return xerrors.Errorf("unable to write output: %w", err)
+	}
+	s.outputCount++
+
+	return
+}
+
+// Close writes the end of the snapshot (including its checksum) and flushes all buffered data to the underlying writer.
+func (s *SnapshotWriter) Close() (err error) {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	marshalUtil := marshalutil.New(marshalutil.Uint8Size + marshalutil.Uint64Size)
+	marshalUtil.WriteByte(snapshotRecordEnd)
+	marshalUtil.WriteUint64(s.outputCount)
+	if _, err = s.writer.Write(marshalUtil.Bytes()); err != nil {
+		return xerrors.Errorf("unable to write end of snapshot: %w", err)
+	}
+
+	if _, err = s.bufferedWriter.Write(s.hash.Sum(nil)); err != nil {
+		return xerrors.Errorf("unable to write snapshot checksum: %w", err)
+	}
+	if err = s.bufferedWriter.Flush(); err != nil {
+		return xerrors.Errorf("unable to flush snapshot: %w", err)
+	}
+
+	return
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region SnapshotReader ///////////////////////////////////////////////////////////////////////////////////////////////
+
+// SnapshotReader reads a snapshot file (that was written by the SnapshotWriter) incrementally, so that the Outputs of
+// big snapshots do not have to be loaded into memory at once.
+type SnapshotReader struct {
+	bufferedReader *bufio.Reader
+	hash           hash.Hash
+	reader         io.Reader
+	header         *SnapshotHeader
+	outputCount    uint64
+	finished       bool
+}
+
+// NewSnapshotReader creates a new SnapshotReader that reads and validates the header of the snapshot from the given
+// reader.
+func NewSnapshotReader(reader io.Reader) (snapshotReader *SnapshotReader, err error) {
+	hasher, err := blake2b.New256(nil)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create hash: %w", err)
+	}
+
+	bufferedReader := bufio.NewReader(reader)
+	snapshotReader = &SnapshotReader{
+		bufferedReader: bufferedReader,
+		hash:           hasher,
+		reader:         io.TeeReader(bufferedReader, hasher),
+	}
+
+	if snapshotReader.header, err = snapshotReader.readHeader(); err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// VerifySnapshot reads the snapshot from the given reader without keeping any of its Outputs in memory and checks that
+// it is well-formed, that all of its Outputs are finalized and that its checksum matches. It returns the header of the
+// snapshot.
+func VerifySnapshot(reader io.Reader) (header *SnapshotHeader, err error) {
+	snapshotReader, err := NewSnapshotReader(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		output, outputMetadata, readErr := snapshotReader.ReadOutput()
+		if readErr == io.EOF {
+			return snapshotReader.Header(), nil
+		}
+		if readErr != nil {
+			return nil, readErr
+		}
+		if !outputMetadata.Finalized() {
+			return nil, xerrors.Errorf("Output %s in snapshot is not finalized: %w", output.ID(), ErrInvalidSnapshot)
+		}
+	}
+}
+
+// Header returns the header of the snapshot.
+func (s *SnapshotReader) Header() *SnapshotHeader {
+	return s.header
+}
+
+// ReadOutput reads the next Output together with its OutputMetadata from the snapshot. It returns io.EOF after the last
+// Output has been read and the checksum of the snapshot has been verified.
+func (s *SnapshotReader) ReadOutput() (output ledgerstate.Output, outputMetadata *ledgerstate.OutputMetadata, err error) {
+	if s.finished {
+		return nil, nil, io.EOF
+	}
+
+	recordType, err := s.readBytes(marshalutil.Uint8Size)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("unable to read record type: %w", err)
+	}
+
+	switch recordType[0] {
+	case snapshotRecordOutput:
+		outputBytes, readErr := s.readLengthPrefixedBytes()
+		if readErr != nil {
+			return nil, nil, xerrors.Errorf("unable to read output: %w", readErr)
+		}
+		outputMetadataBytes, readErr := s.readLengthPrefixedBytes()
+		if readErr != nil {
+			return nil, nil, xerrors.Errorf("unable to read output metadata: %w", readErr)
+		}
+
+		if output, _, err = ledgerstate.OutputFromBytes(outputBytes); err != nil {
+			return nil, nil, xerrors.Errorf("unable to parse output (%v): %w", err, ErrInvalidSnapshot)
+		}
+		if outputMetadata, _, err = ledgerstate.OutputMetadataFromBytes(outputMetadataBytes); err != nil {
+			return nil, nil, xerrors.Errorf("unable to parse output metadata (%v): %w", err, ErrInvalidSnapshot)
+		}
+		s.outputCount++
+
+		return output.SetID(outputMetadata.ID()), outputMetadata, nil
+	case snapshotRecordEnd:
+		if err = s.readEnd(); err != nil {
+			return nil, nil, err
+		}
+
+		return nil, nil, io.EOF
+	default:
+		return nil, nil, xerrors.Errorf("unknown record type %d: %w", recordType[0], ErrInvalidSnapshot)
+	}
+}
+
+// readHeader is an internal utility function that reads the SnapshotHeader.
+func (s *SnapshotReader) readHeader() (header *SnapshotHeader, err error) {
+	fixedBytes, err := s.readBytes(snapshotHeaderFixedLength)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to read snapshot header: %w", err)
+	}
+
+	marshalUtil := marshalutil.New(fixedBytes)
+	magic, _ := marshalUtil.ReadBytes(snapshotMagicLength)
+	if !bytes.Equal(magic, snapshotMagic) {
+		return nil, xerrors.Errorf("unknown snapshot format: %w", ErrInvalidSnapshot)
+	}
+
+	header = &SnapshotHeader{}
+	if header.Version, err = marshalUtil.ReadByte(); err != nil {
+		return nil, xerrors.Errorf("unable to parse version: %w", err)
+	}
+	if header.Version != SnapshotVersion {
+		return nil, xerrors.Errorf("unsupported snapshot version %d: %w", header.Version, ErrInvalidSnapshot)
+	}
+	if header.NetworkID, err = marshalUtil.ReadUint32(); err != nil {
+		return nil, xerrors.Errorf("unable to parse network ID: %w", err)
+	}
+	if header.Timestamp, err = marshalUtil.ReadTime(); err != nil {
+		return nil, xerrors.Errorf("unable to parse timestamp: %w", err)
+	}
+	solidEntryPointCount, err := marshalUtil.ReadUint32()
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse solid entry point count: %w", err)
+	}
+
+	if solidEntryPointCount > maxSnapshotSolidEntryPoints {
+		return nil, xerrors.Errorf("snapshot contains %d solid entry points (max %d): %w", solidEntryPointCount, maxSnapshotSolidEntryPoints, ErrInvalidSnapshot)
+	}
+
+	// the solid entry points are appended as they are read, so that a wrong count can not force a big allocation
+	header.SolidEntryPoints = make(MessageIDs, 0)
+	for i := uint32(0); i < solidEntryPointCount; i++ {
+		solidEntryPointBytes, readErr := s.readBytes(MessageIDLength)
+		if readErr != nil {
+			return nil, xerrors.Errorf("unable to read solid entry point: %w", readErr)
+		}
+		solidEntryPoint, _, parseErr := MessageIDFromBytes(solidEntryPointBytes)
+		if parseErr != nil {
+			return nil, xerrors.Errorf("unable to parse solid entry point: %w", parseErr)
+		}
+		header.SolidEntryPoints = append(header.SolidEntryPoints, solidEntryPoint)
+	}
+
+	return
+}
+
+// readEnd is an internal utility function that reads the end of the snapshot and verifies its checksum.
+func (s *SnapshotReader) readEnd() (err error) {
+	outputCountBytes, err := s.readBytes(marshalutil.Uint64Size)
+	if err != nil {
+		return xerrors.Errorf("unable to read output count: %w", err)
+	}
+	outputCount, err := marshalutil.New(outputCountBytes).ReadUint64()
+	if err != nil {
+		return xerrors.Errorf("unable to parse output count: %w", err)
+	}
+	if outputCount != s.outputCount {
+		return xerrors.Errorf("snapshot contains %d outputs instead of %d: %w", s.outputCount, outputCount, ErrInvalidSnapshot)
+	}
+
+	expectedChecksum := s.hash.Sum(nil)
+	checksum := make([]byte, len(expectedChecksum))
+	if _, err = io.ReadFull(s.bufferedReader, checksum); err != nil {
+		return xerrors.Errorf("unable to read snapshot checksum: %w", err)
+	}
+	if !bytes.Equal(checksum, expectedChecksum) {
+		return ErrSnapshotChecksumMismatch
+	}
+	s.finished = true
+
+	return
+}
+
+// readLengthPrefixedBytes is an internal utility function that reads a uint32 length followed by that many bytes. The
+// length may not exceed the maximum size of a record.
+func (s *SnapshotReader) readLengthPrefixedBytes() (result []byte, err error) {
+	lengthBytes, err := s.readBytes(marshalutil.Uint32Size)
+	if err != nil {
+		return
+	}
+	length, err := marshalutil.New(lengthBytes).ReadUint32()
+	if err != nil {
+		return
+	}
+	if length > maxSnapshotRecordSize {
+		return nil, xerrors.Errorf("record size %d exceeds the maximum of %d: %w", length, maxSnapshotRecordSize, ErrInvalidSnapshot)
+	}
+
+	return s.readBytes(int(length))
+}
+
+// readBytes is an internal utility function that reads exactly the given amount of bytes (and adds them to the hash).
+func (s *SnapshotReader) readBytes(length int) (result []byte, err error) {
+	result = make([]byte, length)
+	if _, err = io.ReadFull(s.reader, result); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	return
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region countingWriter/countingReader ////////////////////////////////////////////////////////////////////////////////
+
+// countingWriter is a writer that counts the bytes that were written to the underlying writer.
+type countingWriter struct {
+	writer io.Writer
+	count  int64
+}
+
+func (c *countingWriter) Write(p []byte) (n int, err error) {
+	n, err = c.writer.Write(p)
+	c.count += int64(n)
+
+	return
+}
+
+// countingReader is a reader that counts the bytes that were read from the underlying reader.
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (c *countingReader) Read(p []byte) (n int, err error) {
+	n, err = c.reader.Read(p)
+	c.count += int64(n)
+
+	return
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
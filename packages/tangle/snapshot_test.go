@@ -0,0 +1,216 @@
+package tangle
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/hive.go/marshalutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/xerrors"
+)
+
+func TestSnapshot_WriteToReadFrom(t *testing.T) {
+	snapshot := newTestSnapshot(t)
+
+	var buffer bytes.Buffer
+	bytesWritten, err := snapshot.WriteTo(&buffer)
+	require.NoError(t, err)
+
+	restoredSnapshot := &Snapshot{}
+	bytesRead, err := restoredSnapshot.ReadFrom(&buffer)
+	require.NoError(t, err)
+	assert.Equal(t, bytesWritten, bytesRead)
+
+	assert.Equal(t, snapshot.NetworkID, restoredSnapshot.NetworkID)
+	assert.True(t, snapshot.Timestamp.Equal(restoredSnapshot.Timestamp))
+	assert.Equal(t, snapshot.SolidEntryPoints, restoredSnapshot.SolidEntryPoints)
+	require.Len(t, restoredSnapshot.Outputs, len(snapshot.Outputs))
+	for i, output := range snapshot.Outputs {
+		assert.Equal(t, output.ID(), restoredSnapshot.Outputs[i].ID())
+		assert.Equal(t, output.Bytes(), restoredSnapshot.Outputs[i].Bytes())
+	}
+}
+
+func TestSnapshotReader_Checksum(t *testing.T) {
+	snapshot := newTestSnapshot(t)
+
+	var buffer bytes.Buffer
+	_, err := snapshot.WriteTo(&buffer)
+	require.NoError(t, err)
+
+	// flip a bit in the middle of the Outputs
+	tamperedBytes := buffer.Bytes()
+	tamperedBytes[len(tamperedBytes)-blake2b.Size-20] ^= 1
+
+	_, err = (&Snapshot{}).ReadFrom(bytes.NewReader(tamperedBytes))
+	assert.True(t, xerrors.Is(err, ErrSnapshotChecksumMismatch))
+
+	// truncate the snapshot
+	_, err = (&Snapshot{}).ReadFrom(bytes.NewReader(buffer.Bytes()[:buffer.Len()-1]))
+	assert.Error(t, err)
+}
+
+func TestSnapshotReader_InvalidHeader(t *testing.T) {
+	snapshot := newTestSnapshot(t)
+
+	var buffer bytes.Buffer
+	_, err := snapshot.WriteTo(&buffer)
+	require.NoError(t, err)
+
+	invalidVersionBytes := buffer.Bytes()
+	invalidVersionBytes[snapshotMagicLength] = SnapshotVersion + 1
+	_, err = NewSnapshotReader(bytes.NewReader(invalidVersionBytes))
+	assert.True(t, xerrors.Is(err, ErrInvalidSnapshot))
+
+	var legacyBuffer bytes.Buffer
+	_, err = snapshot.Legacy().WriteTo(&legacyBuffer)
+	require.NoError(t, err)
+	assert.False(t, IsVersionedSnapshot(bufio.NewReader(bytes.NewReader(legacyBuffer.Bytes()))))
+	_, err = NewSnapshotReader(&legacyBuffer)
+	assert.Error(t, err)
+}
+
+func TestSnapshotReader_Limits(t *testing.T) {
+	snapshot := newTestSnapshot(t)
+
+	var buffer bytes.Buffer
+	_, err := snapshot.WriteTo(&buffer)
+	require.NoError(t, err)
+
+	// announce more solid entry points than allowed
+	tooManySolidEntryPoints := append([]byte{}, buffer.Bytes()...)
+	binary.LittleEndian.PutUint32(tooManySolidEntryPoints[snapshotHeaderFixedLength-marshalutil.Uint32Size:], maxSnapshotSolidEntryPoints+1)
+	_, err = NewSnapshotReader(bytes.NewReader(tooManySolidEntryPoints))
+	assert.True(t, xerrors.Is(err, ErrInvalidSnapshot))
+
+	// announce an Output that is bigger than allowed
+	tooBigOutput := append([]byte{}, buffer.Bytes()...)
+	firstRecord := snapshotHeaderFixedLength + len(snapshot.SolidEntryPoints)*MessageIDLength
+	require.Equal(t, snapshotRecordOutput, tooBigOutput[firstRecord])
+	binary.LittleEndian.PutUint32(tooBigOutput[firstRecord+1:], maxSnapshotRecordSize+1)
+	snapshotReader, err := NewSnapshotReader(bytes.NewReader(tooBigOutput))
+	require.NoError(t, err)
+	_, _, err = snapshotReader.ReadOutput()
+	assert.True(t, xerrors.Is(err, ErrInvalidSnapshot))
+}
+
+func TestSnapshot_Legacy(t *testing.T) {
+	snapshot := newTestSnapshot(t)
+
+	convertedSnapshot := SnapshotFromLegacy(snapshot.Legacy(), snapshot.NetworkID, snapshot.Timestamp)
+	assert.Empty(t, convertedSnapshot.SolidEntryPoints)
+
+	// the legacy format does not contain the Output indexes, so we compare the remaining properties
+	require.Len(t, convertedSnapshot.Outputs, len(snapshot.Outputs))
+	for _, output := range snapshot.Outputs {
+		found := false
+		for _, convertedOutput := range convertedSnapshot.Outputs {
+			if convertedOutput.ID().TransactionID() == output.ID().TransactionID() {
+				found = true
+				assert.Equal(t, output.Address().Bytes(), convertedOutput.Address().Bytes())
+				assert.Equal(t, output.Balances().Bytes(), convertedOutput.Balances().Bytes())
+			}
+		}
+		assert.True(t, found)
+	}
+}
+
+func TestTangle_WriteSnapshotLoadSnapshotFrom(t *testing.T) {
+	sourceTangle := New()
+	defer sourceTangle.Shutdown()
+
+	snapshot := newTestSnapshot(t)
+	sourceTangle.LoadSnapshot(snapshot)
+
+	var buffer bytes.Buffer
+	require.NoError(t, sourceTangle.WriteSnapshot(&buffer, snapshot.NetworkID, time.Now().Add(-time.Hour)))
+
+	tangle := New()
+	defer tangle.Shutdown()
+
+	header, err := tangle.LoadSnapshotFrom(bytes.NewReader(buffer.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, SnapshotVersion, header.Version)
+	assert.Equal(t, snapshot.NetworkID, header.NetworkID)
+	assert.ElementsMatch(t, snapshot.SolidEntryPoints, header.SolidEntryPoints)
+
+	for _, solidEntryPoint := range snapshot.SolidEntryPoints {
+		assert.True(t, tangle.Storage.IsSolidEntryPoint(solidEntryPoint))
+	}
+	assert.ElementsMatch(t, outputIDs(snapshot.Outputs), outputIDs(tangle.LedgerState.ConfirmedUnspentOutputs()))
+}
+
+func TestTangle_LoadSnapshotFromTampered(t *testing.T) {
+	snapshot := newTestSnapshot(t)
+
+	var buffer bytes.Buffer
+	_, err := snapshot.WriteTo(&buffer)
+	require.NoError(t, err)
+
+	// flip a bit in the checksum, so that all Outputs can be read before the error is detected
+	tamperedBytes := buffer.Bytes()
+	tamperedBytes[len(tamperedBytes)-1] ^= 1
+
+	tangle := New()
+	defer tangle.Shutdown()
+
+	_, err = tangle.LoadSnapshotFrom(bytes.NewReader(tamperedBytes))
+	assert.True(t, xerrors.Is(err, ErrSnapshotChecksumMismatch))
+	assert.Empty(t, tangle.LedgerState.ConfirmedUnspentOutputs())
+	for _, solidEntryPoint := range snapshot.SolidEntryPoints {
+		assert.False(t, tangle.Storage.IsSolidEntryPoint(solidEntryPoint))
+	}
+}
+
+func TestTangle_LoadSnapshot(t *testing.T) {
+	tangle := New()
+	defer tangle.Shutdown()
+
+	snapshot := newTestSnapshot(t)
+	tangle.LoadSnapshot(snapshot)
+
+	for _, solidEntryPoint := range snapshot.SolidEntryPoints {
+		assert.True(t, tangle.Storage.IsSolidEntryPoint(solidEntryPoint))
+		assert.True(t, tangle.Storage.MessageMetadata(solidEntryPoint).Consume(func(messageMetadata *MessageMetadata) {
+			assert.True(t, messageMetadata.IsSolid())
+			assert.True(t, messageMetadata.IsBooked())
+		}))
+	}
+
+	createdSnapshot := tangle.CreateSnapshot(time.Now().Add(-time.Hour))
+	assert.ElementsMatch(t, snapshot.SolidEntryPoints, createdSnapshot.SolidEntryPoints)
+	assert.ElementsMatch(t, outputIDs(snapshot.Outputs), outputIDs(createdSnapshot.Outputs))
+}
+
+func outputIDs(outputs ledgerstate.Outputs) (outputIDs []ledgerstate.OutputID) {
+	for _, output := range outputs {
+		outputIDs = append(outputIDs, output.ID())
+	}
+
+	return
+}
+
+func newTestSnapshot(t *testing.T) *Snapshot {
+	outputs := make(ledgerstate.Outputs, 0)
+	for i := 0; i < 3; i++ {
+		transactionID, err := ledgerstate.TransactionIDFromRandomness()
+		require.NoError(t, err)
+
+		address := ledgerstate.NewED25519Address(ed25519.GenerateKeyPair().PublicKey)
+		outputs = append(outputs, ledgerstate.NewSigLockedSingleOutput(uint64(100*(i+1)), address).SetID(ledgerstate.NewOutputID(transactionID, uint16(i))))
+	}
+
+	return &Snapshot{
+		NetworkID:        16,
+		Timestamp:        time.Now(),
+		SolidEntryPoints: MessageIDs{randomMessageID(), randomMessageID()},
+		Outputs:          outputs,
+	}
+}
@@ -126,7 +126,8 @@ func (s *Solidifier) areParentMessagesValid(message *Message) (valid bool) {
 
 // isParentMessageValid checks whether the given parent Message is valid.
 func (s *Solidifier) isParentMessageValid(parentMessageID MessageID, childMessageIssuingTime time.Time) (valid bool) {
-	if parentMessageID == EmptyMessageID {
+	// the past cone of solid entry points might not be available anymore, so they are treated like the genesis
+	if parentMessageID == EmptyMessageID || s.tangle.Storage.IsSolidEntryPoint(parentMessageID) {
 		return true
 	}
 
@@ -0,0 +1,21 @@
+package tangle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSolidifier_ParentsOlderThanMaxTipAge(t *testing.T) {
+	tangle := New(TipEvictionConfig(TipEvictionParams{MaxTipAge: time.Minute}))
+	defer tangle.Shutdown()
+
+	parent := newTestParentsDataWithTimestamp("parent", []MessageID{EmptyMessageID}, []MessageID{}, time.Now().Add(-time.Hour))
+	tangle.Storage.StoreMessage(parent)
+
+	// the local tip age does not affect the validity of the parents, only the protocol wide time difference does
+	assert.True(t, tangle.Solidifier.isParentMessageValid(parent.ID(), parent.IssuingTime().Add(2*time.Minute)))
+	assert.True(t, tangle.Solidifier.isParentMessageValid(parent.ID(), parent.IssuingTime().Add(maxParentsTimeDifference)))
+	assert.False(t, tangle.Solidifier.isParentMessageValid(parent.ID(), parent.IssuingTime().Add(maxParentsTimeDifference+time.Second)))
+}
@@ -3,6 +3,7 @@ package tangle
 import (
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/iotaledger/goshimmer/packages/database"
@@ -15,6 +16,7 @@ import (
 	"github.com/iotaledger/hive.go/marshalutil"
 	"github.com/iotaledger/hive.go/objectstorage"
 	"github.com/iotaledger/hive.go/stringify"
+	"github.com/iotaledger/hive.go/types"
 	"golang.org/x/xerrors"
 )
 
@@ -40,6 +42,18 @@ const (
 	// PrefixFCoB defines the storage prefix for FCoB.
 	PrefixFCoB
 
+	// PrefixSolidEntryPoint defines the storage prefix for the solid entry points.
+	PrefixSolidEntryPoint
+
+	// PrefixMarkerMessageMapping defines the storage prefix for the MarkerMessageMapping.
+	PrefixMarkerMessageMapping
+
+	// PrefixSequenceSupporters defines the storage prefix for the SequenceSupporters.
+	PrefixSequenceSupporters
+
+	// PrefixBranchSupporters defines the storage prefix for the BranchSupporters.
+	PrefixBranchSupporters
+
 	cacheTime = 20 * time.Second
 
 	// DBSequenceNumber defines the db sequence number.
@@ -57,6 +71,11 @@ type Storage struct {
 	missingMessageStorage             *objectstorage.ObjectStorage
 	attachmentStorage                 *objectstorage.ObjectStorage
 	markerIndexBranchIDMappingStorage *objectstorage.ObjectStorage
+	solidEntryPointStorage            *objectstorage.ObjectStorage
+	markerMessageMappingStorage       *objectstorage.ObjectStorage
+	sequenceSupportersStorage         *objectstorage.ObjectStorage
+	branchSupportersStorage           *objectstorage.ObjectStorage
+	pruningMutex                      sync.RWMutex
 
 	Events   *StorageEvents
 	shutdown chan struct{}
@@ -75,6 +94,10 @@ func NewStorage(tangle *Tangle) (storage *Storage) {
 		missingMessageStorage:             osFactory.New(PrefixMissingMessage, MissingMessageFromObjectStorage, objectstorage.CacheTime(cacheTime), objectstorage.LeakDetectionEnabled(false)),
 		attachmentStorage:                 osFactory.New(PrefixAttachments, AttachmentFromObjectStorage, objectstorage.CacheTime(cacheTime), objectstorage.PartitionKey(ledgerstate.TransactionIDLength, MessageIDLength), objectstorage.LeakDetectionEnabled(false)),
 		markerIndexBranchIDMappingStorage: osFactory.New(PrefixMarkerBranchIDMapping, MarkerIndexBranchIDMappingFromObjectStorage, objectstorage.CacheTime(cacheTime), objectstorage.LeakDetectionEnabled(false)),
+		solidEntryPointStorage:            osFactory.New(PrefixSolidEntryPoint, SolidEntryPointFromObjectStorage, objectstorage.CacheTime(cacheTime), objectstorage.LeakDetectionEnabled(false)),
+		markerMessageMappingStorage:       osFactory.New(PrefixMarkerMessageMapping, MarkerMessageMappingFromObjectStorage, objectstorage.CacheTime(cacheTime), objectstorage.LeakDetectionEnabled(false)),
+		sequenceSupportersStorage:         osFactory.New(PrefixSequenceSupporters, SequenceSupportersFromObjectStorage, objectstorage.CacheTime(cacheTime), objectstorage.LeakDetectionEnabled(false)),
+		branchSupportersStorage:           osFactory.New(PrefixBranchSupporters, BranchSupportersFromObjectStorage, objectstorage.CacheTime(cacheTime), objectstorage.LeakDetectionEnabled(false)),
 
 		Events: &StorageEvents{
 			MessageStored:        events.NewEvent(messageIDEventHandler),
@@ -94,6 +117,7 @@ func (s *Storage) Setup() {
 		s.tangle.Storage.StoreMessage(msgParsedEvent.Message)
 	}))
 	s.tangle.MessageFactory.Events.MessageConstructed.Attach(events.NewClosure(s.StoreMessage))
+	s.tangle.Scheduler.Events.MessageDiscarded.Attach(events.NewClosure(s.DeleteMessage))
 }
 
 // StoreMessage stores a new message to the message store.
@@ -101,6 +125,29 @@ func (s *Storage) StoreMessage(message *Message) {
 	// retrieve MessageID
 	messageID := message.ID()
 
+	// the Approvers of the pruned Messages must not change while the Tangle is pruned
+	s.pruningMutex.RLock()
+	stored := s.storeMessage(message)
+	s.pruningMutex.RUnlock()
+	if !stored {
+		return
+	}
+
+	// trigger events
+	if s.missingMessageStorage.DeleteIfPresent(messageID[:]) {
+		s.tangle.Storage.Events.MissingMessageStored.Trigger(messageID)
+	}
+
+	// messages are stored, trigger MessageStored event to move on next check
+	s.Events.MessageStored.Trigger(message.ID())
+}
+
+// storeMessage is an internal utility function that stores the given Message together with its MessageMetadata and its
+// Approvers. It returns false if the Message was stored before.
+func (s *Storage) storeMessage(message *Message) (stored bool) {
+	// retrieve MessageID
+	messageID := message.ID()
+
 	// store Messages only once by using the existence of the Metadata as a guard
 	storedMetadata, stored := s.messageMetadataStorage.StoreIfAbsent(NewMessageMetadata(messageID))
 	if !stored {
@@ -124,13 +171,7 @@ func (s *Storage) StoreMessage(message *Message) {
 		s.approverStorage.Store(NewApprover(WeakApprover, parentMessageID, messageID)).Release()
 	})
 
-	// trigger events
-	if s.missingMessageStorage.DeleteIfPresent(messageID[:]) {
-		s.tangle.Storage.Events.MissingMessageStored.Trigger(messageID)
-	}
-
-	// messages are stored, trigger MessageStored event to move on next check
-	s.Events.MessageStored.Trigger(message.ID())
+	return
 }
 
 // Message retrieves a message from the message store.
@@ -248,18 +289,234 @@ func (s *Storage) DeleteMissingMessage(messageID MessageID) {
 // yet.
 func (s *Storage) MarkerIndexBranchIDMapping(sequenceID markers.SequenceID, computeIfAbsentCallback ...func(sequenceID markers.SequenceID) *MarkerIndexBranchIDMapping) *CachedMarkerIndexBranchIDMapping {
 	if len(computeIfAbsentCallback) >= 1 {
-		return &CachedMarkerIndexBranchIDMapping{s.messageMetadataStorage.ComputeIfAbsent(sequenceID.Bytes(), func(key []byte) objectstorage.StorableObject {
+		return &CachedMarkerIndexBranchIDMapping{s.markerIndexBranchIDMappingStorage.ComputeIfAbsent(sequenceID.Bytes(), func(key []byte) objectstorage.StorableObject {
+			return computeIfAbsentCallback[0](sequenceID)
+		})}
+	}
+
+	return &CachedMarkerIndexBranchIDMapping{CachedObject: s.markerIndexBranchIDMappingStorage.Load(sequenceID.Bytes())}
+}
+
+// StoreMarkerMessageMapping stores the MarkerMessageMapping of a Marker if it doesn't exist, yet.
+func (s *Storage) StoreMarkerMessageMapping(markerMessageMapping *MarkerMessageMapping) (cachedMarkerMessageMapping *CachedMarkerMessageMapping, stored bool) {
+	cachedObject, stored := s.markerMessageMappingStorage.StoreIfAbsent(markerMessageMapping)
+	cachedMarkerMessageMapping = &CachedMarkerMessageMapping{CachedObject: cachedObject}
+
+	return
+}
+
+// MarkerMessageMapping retrieves the MarkerMessageMapping of the given Marker.
+func (s *Storage) MarkerMessageMapping(marker *markers.Marker) *CachedMarkerMessageMapping {
+	return &CachedMarkerMessageMapping{CachedObject: s.markerMessageMappingStorage.Load(marker.Bytes())}
+}
+
+// SequenceSupporters retrieves the SequenceSupporters of the given Sequence. It accepts an optional computeIfAbsent
+// callback that can be used to dynamically create the SequenceSupporters if they don't exist, yet.
+func (s *Storage) SequenceSupporters(sequenceID markers.SequenceID, computeIfAbsentCallback ...func(sequenceID markers.SequenceID) *SequenceSupporters) *CachedSequenceSupporters {
+	if len(computeIfAbsentCallback) >= 1 {
+		return &CachedSequenceSupporters{s.sequenceSupportersStorage.ComputeIfAbsent(sequenceID.Bytes(), func(key []byte) objectstorage.StorableObject {
 			return computeIfAbsentCallback[0](sequenceID)
 		})}
 	}
 
-	return &CachedMarkerIndexBranchIDMapping{CachedObject: s.messageMetadataStorage.Load(sequenceID.Bytes())}
+	return &CachedSequenceSupporters{CachedObject: s.sequenceSupportersStorage.Load(sequenceID.Bytes())}
+}
+
+// BranchSupporters retrieves the BranchSupporters of the given Branch. It accepts an optional computeIfAbsent callback
+// that can be used to dynamically create the BranchSupporters if they don't exist, yet.
+func (s *Storage) BranchSupporters(branchID ledgerstate.BranchID, computeIfAbsentCallback ...func(branchID ledgerstate.BranchID) *BranchSupporters) *CachedBranchSupporters {
+	if len(computeIfAbsentCallback) >= 1 {
+		return &CachedBranchSupporters{s.branchSupportersStorage.ComputeIfAbsent(branchID.Bytes(), func(key []byte) objectstorage.StorableObject {
+			return computeIfAbsentCallback[0](branchID)
+		})}
+	}
+
+	return &CachedBranchSupporters{CachedObject: s.branchSupportersStorage.Load(branchID.Bytes())}
+}
+
+// StoreSolidEntryPoint marks the given Message as a solid entry point, so that Messages referencing it can become solid
+// even though its past cone is not known (i.e. because it was pruned or the node started from a snapshot). It creates
+// the MessageMetadata of the solid entry point if it doesn't exist, yet.
+func (s *Storage) StoreSolidEntryPoint(messageID MessageID) {
+	if cachedSolidEntryPoint, stored := s.solidEntryPointStorage.StoreIfAbsent(NewSolidEntryPoint(messageID)); stored {
+		cachedSolidEntryPoint.Release()
+	}
+
+	s.storeEntryPointMetadata(messageID)
+}
+
+// IsSolidEntryPoint checks if the given Message is a solid entry point.
+func (s *Storage) IsSolidEntryPoint(messageID MessageID) bool {
+	return s.solidEntryPointStorage.Contains(messageID.Bytes())
+}
+
+// SolidEntryPoints returns the MessageIDs of all the solid entry points.
+func (s *Storage) SolidEntryPoints() (solidEntryPoints MessageIDs) {
+	s.solidEntryPointStorage.ForEach(func(key []byte, cachedObject objectstorage.CachedObject) bool {
+		cachedObject.Consume(func(object objectstorage.StorableObject) {
+			solidEntryPoints = append(solidEntryPoints, object.(*SolidEntryPoint).MessageID())
+		})
+
+		return true
+	})
+
+	return
+}
+
+// HasMessages returns true if at least one Message was stored in the Tangle.
+func (s *Storage) HasMessages() (hasMessages bool) {
+	s.messageStorage.ForEachKeyOnly(func(key []byte) bool {
+		hasMessages = true
+
+		return false
+	}, false)
+
+	return
+}
+
+// PruneMessagesOlderThan deletes all the Messages (together with their metadata, approvers and attachments) that were
+// issued before the given time and that are either confirmed or part of a rejected and finalized Branch. Pruned
+// Messages that are still referenced by a remaining Message become solid entry points and are kept, so that the
+// remaining Messages can still be solidified. It returns the number of pruned Messages.
+//
+// No new Messages are stored while the Tangle is pruned, so that no Message can attach to a Message that is about to be
+// pruned. Components that still process a pruned Message in parallel only find it missing from the Storage, as pruned
+// Messages are decided and can not change their state anymore.
+func (s *Storage) PruneMessagesOlderThan(cutoff time.Time) (prunedMessages int) {
+	s.pruningMutex.Lock()
+	defer s.pruningMutex.Unlock()
+
+	prunableMessages, solidEntryPoints := s.prunableMessages(cutoff)
+
+	for messageID := range solidEntryPoints {
+		s.StoreSolidEntryPoint(messageID)
+	}
+
+	// solid entry points that get pruned themselves or whose approvers get pruned are not needed anymore
+	for _, messageID := range s.SolidEntryPoints() {
+		if _, stillNeeded := solidEntryPoints[messageID]; stillNeeded {
+			continue
+		}
+		if _, pruned := prunableMessages[messageID]; !pruned && !s.allApproversPrunable(messageID, prunableMessages) {
+			continue
+		}
+
+		s.solidEntryPointStorage.Delete(messageID.Bytes())
+		prunableMessages[messageID] = types.Void
+	}
+
+	for messageID := range prunableMessages {
+		s.pruneMessage(messageID)
+		prunedMessages++
+	}
+
+	return
+}
+
+// SolidEntryPointsOlderThan returns the solid entry points that would be created by pruning all (prunable) Messages that
+// were issued before the given time.
+func (s *Storage) SolidEntryPointsOlderThan(cutoff time.Time) (solidEntryPoints MessageIDs) {
+	_, solidEntryPointsSet := s.prunableMessages(cutoff)
+	for _, messageID := range s.SolidEntryPoints() {
+		solidEntryPointsSet[messageID] = types.Void
+	}
+
+	for messageID := range solidEntryPointsSet {
+		solidEntryPoints = append(solidEntryPoints, messageID)
+	}
+
+	return
+}
+
+// prunableMessages is an internal utility function that determines the Messages that were issued before the given time
+// and that can be pruned, as well as the ones that need to be kept as solid entry points.
+func (s *Storage) prunableMessages(cutoff time.Time) (prunableMessages map[MessageID]types.Empty, solidEntryPoints map[MessageID]types.Empty) {
+	prunableMessages = make(map[MessageID]types.Empty)
+	s.messageStorage.ForEach(func(key []byte, cachedObject objectstorage.CachedObject) bool {
+		(&CachedMessage{CachedObject: cachedObject}).Consume(func(message *Message) {
+			if message.IssuingTime().Before(cutoff) && s.messageDecided(message.ID()) {
+				prunableMessages[message.ID()] = types.Void
+			}
+		})
+
+		return true
+	})
+
+	solidEntryPoints = make(map[MessageID]types.Empty)
+	for messageID := range prunableMessages {
+		if !s.allApproversPrunable(messageID, prunableMessages) {
+			solidEntryPoints[messageID] = types.Void
+		}
+	}
+	for messageID := range solidEntryPoints {
+		delete(prunableMessages, messageID)
+	}
+
+	return
+}
+
+// messageDecided is an internal utility function that checks if the given Message can not change its state anymore,
+// because it is either confirmed or part of a rejected Branch that is finalized.
+func (s *Storage) messageDecided(messageID MessageID) (decided bool) {
+	s.MessageMetadata(messageID).Consume(func(messageMetadata *MessageMetadata) {
+		if decided = messageMetadata.IsConfirmed(); decided {
+			return
+		}
+
+		s.tangle.LedgerState.BranchDAG.Branch(messageMetadata.BranchID()).Consume(func(branch ledgerstate.Branch) {
+			decided = branch.Finalized() && branch.InclusionState() == ledgerstate.Rejected
+		})
+	})
+
+	return
+}
+
+// allApproversPrunable is an internal utility function that checks if the given Message has at least one Approver and
+// if all of its Approvers are contained in the given set of prunable Messages.
+func (s *Storage) allApproversPrunable(messageID MessageID, prunableMessages map[MessageID]types.Empty) (allPrunable bool) {
+	cachedApprovers := s.Approvers(messageID)
+	defer cachedApprovers.Release()
+
+	allPrunable = len(cachedApprovers) != 0
+	for _, approver := range cachedApprovers.Unwrap() {
+		if approver == nil {
+			continue
+		}
+		if _, prunable := prunableMessages[approver.ApproverMessageID()]; !prunable {
+			return false
+		}
+	}
+
+	return
+}
+
+// pruneMessage is an internal utility function that removes a Message together with all of its associated objects.
+func (s *Storage) pruneMessage(messageID MessageID) {
+	s.Message(messageID).Consume(func(message *Message) {
+		if message.Payload().Type() == ledgerstate.TransactionType {
+			s.attachmentStorage.Delete(NewAttachment(message.Payload().(*ledgerstate.Transaction).ID(), messageID).ObjectStorageKey())
+		}
+	})
+
+	s.Approvers(messageID).Consume(func(approver *Approver) {
+		s.approverStorage.Delete(approver.ObjectStorageKey())
+	})
+
+	s.DeleteMessage(messageID)
+	s.DeleteMissingMessage(messageID)
+	s.messageMetadataStorage.Delete(messageID.Bytes())
 }
 
 func (s *Storage) storeGenesis() {
-	s.MessageMetadata(EmptyMessageID, func() *MessageMetadata {
-		genesisMetadata := &MessageMetadata{
-			messageID: EmptyMessageID,
+	s.storeEntryPointMetadata(EmptyMessageID)
+}
+
+// storeEntryPointMetadata stores the MessageMetadata of an entry point of the Tangle (i.e. the genesis or a solid entry
+// point) that is solid and booked into the MasterBranch if it doesn't exist, yet.
+func (s *Storage) storeEntryPointMetadata(messageID MessageID) {
+	s.MessageMetadata(messageID, func() *MessageMetadata {
+		entryPointMetadata := &MessageMetadata{
+			messageID: messageID,
 			solid:     true,
 			branchID:  ledgerstate.MasterBranchID,
 			structureDetails: &markers.StructureDetails{
@@ -276,12 +533,11 @@ func (s *Storage) storeGenesis() {
 			eligible: true,
 		}
 
-		genesisMetadata.Persist()
-		genesisMetadata.SetModified()
+		entryPointMetadata.Persist()
+		entryPointMetadata.SetModified()
 
-		return genesisMetadata
+		return entryPointMetadata
 	}).Release()
-
 }
 
 // deleteStrongApprover deletes an Approver from the object storage that was created by a strong parent.
@@ -301,6 +557,11 @@ func (s *Storage) Shutdown() {
 	s.approverStorage.Shutdown()
 	s.missingMessageStorage.Shutdown()
 	s.attachmentStorage.Shutdown()
+	s.markerIndexBranchIDMappingStorage.Shutdown()
+	s.solidEntryPointStorage.Shutdown()
+	s.markerMessageMappingStorage.Shutdown()
+	s.sequenceSupportersStorage.Shutdown()
+	s.branchSupportersStorage.Shutdown()
 
 	close(s.shutdown)
 }
@@ -313,6 +574,11 @@ func (s *Storage) Prune() error {
 		s.approverStorage,
 		s.missingMessageStorage,
 		s.attachmentStorage,
+		s.markerIndexBranchIDMappingStorage,
+		s.solidEntryPointStorage,
+		s.markerMessageMappingStorage,
+		s.sequenceSupportersStorage,
+		s.branchSupportersStorage,
 	} {
 		if err := storage.Prune(); err != nil {
 			err = fmt.Errorf("failed to prune storage: %w", err)
@@ -964,3 +1230,81 @@ func (c *CachedMissingMessage) String() string {
 }
 
 // endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region SolidEntryPoint //////////////////////////////////////////////////////////////////////////////////////////////
+
+// SolidEntryPoint represents a Message whose past cone is not available anymore (or has never been), but that is
+// considered solid so that the Messages referencing it can be solidified.
+type SolidEntryPoint struct {
+	objectstorage.StorableObjectFlags
+
+	messageID MessageID
+}
+
+// NewSolidEntryPoint creates a new SolidEntryPoint for the given MessageID.
+func NewSolidEntryPoint(messageID MessageID) *SolidEntryPoint {
+	return &SolidEntryPoint{
+		messageID: messageID,
+	}
+}
+
+// SolidEntryPointFromBytes parses the given bytes into a SolidEntryPoint.
+func SolidEntryPointFromBytes(bytes []byte) (result *SolidEntryPoint, consumedBytes int, err error) {
+	marshalUtil := marshalutil.New(bytes)
+	result, err = SolidEntryPointFromMarshalUtil(marshalUtil)
+	consumedBytes = marshalUtil.ReadOffset()
+
+	return
+}
+
+// SolidEntryPointFromMarshalUtil parses a SolidEntryPoint from the given MarshalUtil.
+func SolidEntryPointFromMarshalUtil(marshalUtil *marshalutil.MarshalUtil) (result *SolidEntryPoint, err error) {
+	result = &SolidEntryPoint{}
+
+	if result.messageID, err = MessageIDFromMarshalUtil(marshalUtil); err != nil {
+		err = fmt.Errorf("failed to parse message ID of solid entry point: %w", err)
+		return
+	}
+
+	return
+}
+
+// SolidEntryPointFromObjectStorage restores a SolidEntryPoint from the ObjectStorage.
+func SolidEntryPointFromObjectStorage(key []byte, data []byte) (result objectstorage.StorableObject, err error) {
+	result, _, err = SolidEntryPointFromBytes(byteutils.ConcatBytes(key, data))
+	if err != nil {
+		err = fmt.Errorf("failed to parse solid entry point from object storage: %w", err)
+		return
+	}
+
+	return
+}
+
+// MessageID returns the id of the message.
+func (s *SolidEntryPoint) MessageID() MessageID {
+	return s.messageID
+}
+
+// Bytes returns a marshaled version of this SolidEntryPoint.
+func (s *SolidEntryPoint) Bytes() []byte {
+	return byteutils.ConcatBytes(s.ObjectStorageKey(), s.ObjectStorageValue())
+}
+
+// Update updates the solid entry point.
+// It should never happen and will panic if called.
+func (s *SolidEntryPoint) Update(other objectstorage.StorableObject) {
+	panic("solid entry points should never be overwritten and only stored once to optimize IO")
+}
+
+// ObjectStorageKey returns the key of the stored solid entry point.
+// This returns the bytes of the messageID of the solid entry point.
+func (s *SolidEntryPoint) ObjectStorageKey() []byte {
+	return s.messageID[:]
+}
+
+// ObjectStorageValue returns the value of the stored solid entry point.
+func (s *SolidEntryPoint) ObjectStorageValue() (result []byte) {
+	return nil
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -3,8 +3,11 @@ package tangle
 import (
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/goshimmer/packages/tangle/payload"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -51,3 +54,50 @@ func TestStorage_Attachments(t *testing.T) {
 	}
 
 }
+
+func TestStorage_PruneMessagesOlderThan(t *testing.T) {
+	tangle := New()
+	defer tangle.Shutdown()
+
+	now := time.Now()
+	newMessage := func(parent MessageID, issuingTime time.Time) *Message {
+		return NewMessage([]MessageID{parent}, []MessageID{}, issuingTime, ed25519.PublicKey{}, 0, payload.NewGenericDataPayload([]byte("test")), 0, ed25519.Signature{})
+	}
+
+	// genesis <- oldest <- old <- recent
+	oldest := newMessage(EmptyMessageID, now.Add(-3*time.Hour))
+	old := newMessage(oldest.ID(), now.Add(-2*time.Hour))
+	recent := newMessage(old.ID(), now)
+	// only decided messages are pruned
+	unconfirmed := newMessage(EmptyMessageID, now.Add(-4*time.Hour))
+	tangle.Storage.StoreMessage(unconfirmed)
+	for _, message := range []*Message{oldest, old, recent} {
+		tangle.Storage.StoreMessage(message)
+		tangle.Storage.MessageMetadata(message.ID()).Consume(func(messageMetadata *MessageMetadata) {
+			messageMetadata.SetConfirmed(true)
+		})
+	}
+
+	prunedMessages := tangle.Storage.PruneMessagesOlderThan(now.Add(-time.Hour))
+	assert.Equal(t, 1, prunedMessages)
+
+	// the oldest message is gone, while the old one is kept as a solid entry point for the recent one
+	assert.False(t, tangle.Storage.Message(oldest.ID()).Consume(func(*Message) {}))
+	assert.False(t, tangle.Storage.MessageMetadata(oldest.ID()).Consume(func(*MessageMetadata) {}))
+	assert.True(t, tangle.Storage.IsSolidEntryPoint(old.ID()))
+	assert.False(t, tangle.Storage.IsSolidEntryPoint(recent.ID()))
+	assert.Equal(t, MessageIDs{old.ID()}, tangle.Storage.SolidEntryPoints())
+	assert.True(t, tangle.Storage.Message(recent.ID()).Consume(func(*Message) {}))
+	assert.True(t, tangle.Storage.Message(unconfirmed.ID()).Consume(func(*Message) {}))
+
+	// once the recent message is pruned as well, the solid entry point is not needed anymore
+	newest := newMessage(recent.ID(), now.Add(time.Hour))
+	tangle.Storage.StoreMessage(newest)
+	tangle.Storage.MessageMetadata(newest.ID()).Consume(func(messageMetadata *MessageMetadata) {
+		messageMetadata.SetConfirmed(true)
+	})
+	prunedMessages = tangle.Storage.PruneMessagesOlderThan(now.Add(time.Minute))
+	assert.Equal(t, 1, prunedMessages)
+	assert.Equal(t, MessageIDs{recent.ID()}, tangle.Storage.SolidEntryPoints())
+	assert.False(t, tangle.Storage.Message(old.ID()).Consume(func(*Message) {}))
+}
@@ -1,8 +1,11 @@
 package tangle
 
 import (
+	"io"
 	"sync"
+	"time"
 
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
 	"github.com/iotaledger/goshimmer/packages/markers"
 	"github.com/iotaledger/hive.go/autopeering/peer"
 	"github.com/iotaledger/hive.go/events"
@@ -16,18 +19,19 @@ import (
 
 // Tangle is the central data structure of the IOTA protocol.
 type Tangle struct {
-	Parser         *Parser
-	Storage        *Storage
-	Solidifier     *Solidifier
-	Scheduler      *Scheduler
-	Booker         *Booker
-	TipManager     *TipManager
-	Requester      *Requester
-	MessageFactory *MessageFactory
-	LedgerState    *LedgerState
-	Utils          *Utils
-	Options        *Options
-	Events         *Events
+	Parser                *Parser
+	Storage               *Storage
+	Solidifier            *Solidifier
+	Scheduler             *Scheduler
+	Booker                *Booker
+	ApprovalWeightManager *ApprovalWeightManager
+	TipManager            *TipManager
+	Requester             *Requester
+	MessageFactory        *MessageFactory
+	LedgerState           *LedgerState
+	Utils                 *Utils
+	Options               *Options
+	Events                *Events
 
 	OpinionFormer            *OpinionFormer
 	PayloadOpinionProvider   OpinionVoterProvider
@@ -53,7 +57,8 @@ func New(options ...Option) (tangle *Tangle) {
 	tangle.Scheduler = NewScheduler(tangle)
 	tangle.LedgerState = NewLedgerState(tangle)
 	tangle.Booker = NewBooker(tangle)
-	tangle.Requester = NewRequester(tangle)
+	tangle.ApprovalWeightManager = NewApprovalWeightManager(tangle)
+	tangle.Requester = NewRequester(tangle, tangle.Options.RequesterOptions...)
 	tangle.TipManager = NewTipManager(tangle)
 	tangle.MessageFactory = NewMessageFactory(tangle, tangle.TipManager)
 	tangle.Utils = NewUtils(tangle)
@@ -73,6 +78,7 @@ func (t *Tangle) Setup() {
 	t.Requester.Setup()
 	t.Scheduler.Setup()
 	t.Booker.Setup()
+	t.ApprovalWeightManager.Setup()
 
 	// Booker and LedgerState setup is left out until the old value tangle is in use.
 	if !t.Options.WithoutOpinionFormer {
@@ -98,6 +104,115 @@ func (t *Tangle) Prune() (err error) {
 	return t.Storage.Prune()
 }
 
+// CreateSnapshot creates a Snapshot of the confirmed and unspent Outputs of the ledger state. Its solid entry points are
+// the ones that result from pruning all Messages that were issued before the given time.
+func (t *Tangle) CreateSnapshot(cutoff time.Time) (snapshot *Snapshot) {
+	return &Snapshot{
+		Timestamp:        time.Now(),
+		SolidEntryPoints: t.Storage.SolidEntryPointsOlderThan(cutoff),
+		Outputs:          t.LedgerState.ConfirmedUnspentOutputs(),
+	}
+}
+
+// LoadSnapshot restores the ledger state and the solid entry points from the given Snapshot.
+func (t *Tangle) LoadSnapshot(snapshot *Snapshot) {
+	t.LedgerState.LoadOutputs(snapshot.Outputs)
+	for _, solidEntryPoint := range snapshot.SolidEntryPoints {
+		t.Storage.StoreSolidEntryPoint(solidEntryPoint)
+	}
+}
+
+// WriteSnapshot streams a snapshot of the confirmed and unspent Outputs of the ledger state (including their
+// OutputMetadata) to the given writer. Its solid entry points are the ones that result from pruning all Messages that
+// were issued before the given time.
+func (t *Tangle) WriteSnapshot(writer io.Writer, networkID uint32, cutoff time.Time) (err error) {
+	snapshotWriter, err := NewSnapshotWriter(writer, &SnapshotHeader{
+		NetworkID:        networkID,
+		Timestamp:        time.Now(),
+		SolidEntryPoints: t.Storage.SolidEntryPointsOlderThan(cutoff),
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to create SnapshotWriter: %w", err)
+	}
+
+	t.LedgerState.UTXODAG.ForEachConfirmedUnspentOutput(func(output ledgerstate.Output, outputMetadata *ledgerstate.OutputMetadata) bool {
+		err = snapshotWriter.WriteOutput(output, outputMetadata)
+
+		return err == nil
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to write Output: %w", err)
+	}
+
+	return snapshotWriter.Close()
+}
+
+// LoadSnapshotFrom restores the ledger state and the solid entry points from the snapshot that is streamed from the
+// given reader and returns its header. The whole snapshot is verified before anything is applied, so that a corrupt or
+// truncated snapshot does not leave a partial ledger state behind.
+func (t *Tangle) LoadSnapshotFrom(reader io.ReadSeeker) (header *SnapshotHeader, err error) {
+	if _, err = VerifySnapshot(reader); err != nil {
+		return nil, xerrors.Errorf("failed to verify snapshot: %w", err)
+	}
+	if _, err = reader.Seek(0, io.SeekStart); err != nil {
+		return nil, xerrors.Errorf("failed to rewind snapshot: %w", err)
+	}
+
+	snapshotReader, err := NewSnapshotReader(reader)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read snapshot header: %w", err)
+	}
+
+	if err = t.LoadSnapshotFromReader(snapshotReader); err != nil {
+		return nil, err
+	}
+
+	return snapshotReader.Header(), nil
+}
+
+// LoadSnapshotFromReader restores the ledger state and the solid entry points from the given SnapshotReader. The
+// Outputs are stored in batches as soon as they are read, so that big snapshots do not have to be held in memory at
+// once. Outputs that were read before an error is detected stay applied, so the snapshot should be checked with
+// VerifySnapshot first. Only finalized Outputs are accepted.
+func (t *Tangle) LoadSnapshotFromReader(snapshotReader *SnapshotReader) (err error) {
+	outputs := make(ledgerstate.Outputs, 0, snapshotLoadBatchSize)
+	for {
+		output, outputMetadata, readErr := snapshotReader.ReadOutput()
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return xerrors.Errorf("failed to read Output from snapshot: %w", readErr)
+		}
+		if !outputMetadata.Finalized() {
+			return xerrors.Errorf("Output %s in snapshot is not finalized: %w", output.ID(), ErrInvalidSnapshot)
+		}
+
+		if outputs = append(outputs, output); len(outputs) == snapshotLoadBatchSize {
+			t.LedgerState.LoadOutputs(outputs)
+			outputs = make(ledgerstate.Outputs, 0, snapshotLoadBatchSize)
+		}
+	}
+	t.LedgerState.LoadOutputs(outputs)
+
+	for _, solidEntryPoint := range snapshotReader.Header().SolidEntryPoints {
+		t.Storage.StoreSolidEntryPoint(solidEntryPoint)
+	}
+
+	return nil
+}
+
+// PruneOlderThan removes the decided Messages and the finalized Branches that are older than the given time while
+// keeping the solid entry points that are necessary to solidify the remaining Messages.
+func (t *Tangle) PruneOlderThan(cutoff time.Time) (prunedMessages int, prunedBranches int, err error) {
+	prunedMessages = t.Storage.PruneMessagesOlderThan(cutoff)
+	if prunedBranches, err = t.LedgerState.PruneFinalizedBranches(cutoff); err != nil {
+		err = xerrors.Errorf("failed to prune finalized Branches: %w", err)
+	}
+
+	return
+}
+
 // Shutdown marks the tangle as stopped, so it will not accept any new messages (waits for all backgroundTasks to finish).
 func (t *Tangle) Shutdown() {
 	t.MessageFactory.Shutdown()
@@ -146,6 +261,11 @@ type Options struct {
 	WithoutOpinionFormer         bool
 	IncreaseMarkersIndexCallback markers.IncreaseIndexCallback
 	TangleWidth                  int
+	SchedulerParams              SchedulerParams
+	ApprovalWeightParams         ApprovalWeightParams
+	TipSelectionStrategy         TipSelectionStrategy
+	TipEvictionParams            TipEvictionParams
+	RequesterOptions             []RequesterOption
 }
 
 // buildOptions generates the Options object use by the Tangle.
@@ -154,6 +274,17 @@ func buildOptions(options ...Option) (builtOptions *Options) {
 		Store:                        mapdb.NewMapDB(),
 		Identity:                     identity.GenerateLocalIdentity(),
 		IncreaseMarkersIndexCallback: increaseMarkersIndexCallbackStrategy,
+		SchedulerParams: SchedulerParams{
+			Rate:                   DefaultSchedulerRate,
+			MaxBufferSize:          DefaultMaxBufferSize,
+			AccessManaRetrieveFunc: func(identity.ID) float64 { return MinMana },
+		},
+		ApprovalWeightParams: ApprovalWeightParams{
+			ConsensusManaRetrieveFunc:      func(identity.ID) float64 { return 0 },
+			TotalConsensusManaRetrieveFunc: func() float64 { return 0 },
+			ConfirmationThreshold:          DefaultConfirmationThreshold,
+		},
+		TipSelectionStrategy: &UniformStrategy{},
 	}
 
 	for _, option := range options {
@@ -199,4 +330,39 @@ func TangleWidth(width int) Option {
 	}
 }
 
+// SchedulerConfig is an Option for the Tangle that allows to set the parameters of the Scheduler.
+func SchedulerConfig(config SchedulerParams) Option {
+	return func(options *Options) {
+		options.SchedulerParams = config
+	}
+}
+
+// TipSelection is an Option for the Tangle that allows to set the TipSelectionStrategy that is used by the TipManager.
+func TipSelection(strategy TipSelectionStrategy) Option {
+	return func(options *Options) {
+		options.TipSelectionStrategy = strategy
+	}
+}
+
+// TipEvictionConfig is an Option for the Tangle that allows to set the limits that are used to evict stale tips.
+func TipEvictionConfig(config TipEvictionParams) Option {
+	return func(options *Options) {
+		options.TipEvictionParams = config
+	}
+}
+
+// RequesterConfig is an Option for the Tangle that allows to set the RequesterOptions that are used by the Requester.
+func RequesterConfig(options ...RequesterOption) Option {
+	return func(opts *Options) {
+		opts.RequesterOptions = options
+	}
+}
+
+// ApprovalWeightConfig is an Option for the Tangle that allows to set the parameters of the ApprovalWeightManager.
+func ApprovalWeightConfig(config ApprovalWeightParams) Option {
+	return func(options *Options) {
+		options.ApprovalWeightParams = config
+	}
+}
+
 // endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
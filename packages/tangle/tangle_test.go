@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
 	"github.com/iotaledger/goshimmer/packages/pow"
 	"github.com/iotaledger/goshimmer/packages/tangle/payload"
 	"github.com/iotaledger/hive.go/autopeering/peer"
@@ -107,6 +108,82 @@ func TestTangle_InvalidParentsAgeMessage(t *testing.T) {
 	assert.EqualValues(t, 2, atomic.LoadInt32(&invalidMessages))
 }
 
+func TestTangle_BookWhilePruning(t *testing.T) {
+	const conflictCount = 20
+
+	tangle := New(WithoutOpinionFormer(true))
+	defer tangle.Shutdown()
+	tangle.Booker.Setup()
+
+	wallets := createWallets(conflictCount + 3)
+	genesisWallet, spenders, targets := wallets[0], wallets[1:conflictCount+1], wallets[conflictCount+1:]
+	walletsByAddress := make(map[ledgerstate.Address]wallet)
+	for _, w := range wallets {
+		walletsByAddress[w.address] = w
+	}
+
+	tangle.LedgerState.LoadSnapshot(map[ledgerstate.TransactionID]map[ledgerstate.Address]*ledgerstate.ColoredBalances{
+		ledgerstate.GenesisTransactionID: {
+			genesisWallet.address: ledgerstate.NewColoredBalances(map[ledgerstate.Color]uint64{ledgerstate.ColorIOTA: conflictCount}),
+		},
+	})
+
+	outputsByID := make(map[ledgerstate.OutputID]ledgerstate.Output)
+	splitOutputs := make([]ledgerstate.Output, conflictCount)
+	for i, spender := range spenders {
+		splitOutputs[i] = ledgerstate.NewSigLockedSingleOutput(1, spender.address)
+	}
+	splitTransaction := makeTransaction(ledgerstate.NewInputs(ledgerstate.NewUTXOInput(ledgerstate.NewOutputID(ledgerstate.GenesisTransactionID, 0))), ledgerstate.NewOutputs(splitOutputs...), outputsByID, walletsByAddress, genesisWallet)
+	splitMessage := newTestParentsPayloadMessage(splitTransaction, []MessageID{EmptyMessageID}, []MessageID{})
+	tangle.Storage.StoreMessage(splitMessage)
+	require.NoError(t, tangle.Booker.Book(splitMessage.ID()))
+
+	// prune concurrently while every second booked Message forks the Branch of an already booked Transaction
+	stopPruning := make(chan struct{})
+	var pruning sync.WaitGroup
+	pruning.Add(1)
+	go func() {
+		defer pruning.Done()
+		for {
+			select {
+			case <-stopPruning:
+				return
+			default:
+				_, _, _ = tangle.PruneOlderThan(time.Now().Add(-time.Hour))
+			}
+		}
+	}()
+
+	booked := make(chan error, 1)
+	go func() {
+		for _, spender := range spenders {
+			input := ledgerstate.NewUTXOInput(ledgerstate.NewOutputID(splitTransaction.ID(), selectIndex(splitTransaction, spender)))
+			outputsByID[input.ReferencedOutputID()] = ledgerstate.NewSigLockedSingleOutput(1, spender.address)
+
+			for _, target := range targets {
+				transaction := makeTransaction(ledgerstate.NewInputs(input), ledgerstate.NewOutputs(ledgerstate.NewSigLockedSingleOutput(1, target.address)), outputsByID, walletsByAddress)
+				message := newTestParentsPayloadMessage(transaction, []MessageID{splitMessage.ID()}, []MessageID{})
+				tangle.Storage.StoreMessage(message)
+				if err := tangle.Booker.Book(message.ID()); err != nil {
+					booked <- err
+					return
+				}
+			}
+		}
+		booked <- nil
+	}()
+
+	select {
+	case err := <-booked:
+		require.NoError(t, err)
+	case <-time.After(30 * time.Second):
+		require.FailNow(t, "booking deadlocked while pruning")
+	}
+
+	close(stopPruning)
+	pruning.Wait()
+}
+
 func TestTangle_StoreMessage(t *testing.T) {
 	messageTangle := New()
 	defer messageTangle.Shutdown()
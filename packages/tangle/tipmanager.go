@@ -2,9 +2,12 @@ package tangle
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/iotaledger/goshimmer/packages/clock"
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/goshimmer/packages/markers"
 	"github.com/iotaledger/goshimmer/packages/tangle/payload"
 	"github.com/iotaledger/hive.go/datastructure/randommap"
 	"github.com/iotaledger/hive.go/events"
@@ -43,21 +46,30 @@ func (t TipType) String() string {
 
 // TipManager manages a map of tips and emits events for their removal and addition.
 type TipManager struct {
-	tangle     *Tangle
-	strongTips *randommap.RandomMap
-	weakTips   *randommap.RandomMap
-	Events     *TipManagerEvents
+	tangle                    *Tangle
+	strongTips                *randommap.RandomMap
+	weakTips                  *randommap.RandomMap
+	tipsByBranch              map[ledgerstate.BranchID]map[MessageID]types.Empty
+	tipBranches               map[MessageID]ledgerstate.BranchID
+	tipsByBranchMutex         sync.Mutex
+	tipSelectionStrategy      TipSelectionStrategy
+	tipSelectionStrategyMutex sync.RWMutex
+	Events                    *TipManagerEvents
 }
 
 // NewTipManager creates a new tip-selector.
 func NewTipManager(tangle *Tangle, tips ...MessageID) *TipManager {
 	tipSelector := &TipManager{
-		tangle:     tangle,
-		strongTips: randommap.New(),
-		weakTips:   randommap.New(),
+		tangle:               tangle,
+		strongTips:           randommap.New(),
+		weakTips:             randommap.New(),
+		tipsByBranch:         make(map[ledgerstate.BranchID]map[MessageID]types.Empty),
+		tipBranches:          make(map[MessageID]ledgerstate.BranchID),
+		tipSelectionStrategy: tangle.Options.TipSelectionStrategy,
 		Events: &TipManagerEvents{
 			TipAdded:   events.NewEvent(tipEventHandler),
 			TipRemoved: events.NewEvent(tipEventHandler),
+			TipEvicted: events.NewEvent(tipEventHandler),
 		},
 	}
 
@@ -73,12 +85,36 @@ func (t *TipManager) Setup() {
 	t.tangle.OpinionFormer.Events.MessageOpinionFormed.Attach(events.NewClosure(func(messageID MessageID) {
 		t.tangle.Storage.Message(messageID).Consume(t.AddTip)
 	}))
+
+	// switch the type of the affected tips if the liked Branch of a Conflict changes (i.e. due to its approval weight)
+	t.tangle.LedgerState.BranchDAG.Events.BranchMonotonicallyLiked.Attach(events.NewClosure(func(branchDAGEvent *ledgerstate.BranchDAGEvent) {
+		defer branchDAGEvent.Release()
+
+		t.UpdateTipTypes(branchDAGEvent.Branch.ID())
+	}))
+	t.tangle.LedgerState.BranchDAG.Events.BranchMonotonicallyDisliked.Attach(events.NewClosure(func(branchDAGEvent *ledgerstate.BranchDAGEvent) {
+		defer branchDAGEvent.Release()
+
+		t.UpdateTipTypes(branchDAGEvent.Branch.ID())
+	}))
+
+	// move tips whose Branch changed (i.e. due to a new Conflict in their past cone) to the index of their new Branch
+	t.tangle.Booker.Events.MessageBranchUpdated.Attach(events.NewClosure(func(messageID MessageID) {
+		t.tangle.Storage.MessageMetadata(messageID).Consume(func(messageMetadata *MessageMetadata) {
+			if t.moveTip(messageID, messageMetadata.BranchID()) {
+				t.updateTipType(messageID)
+			}
+		})
+	}))
 }
 
 // Set adds the given messageIDs as tips.
 func (t *TipManager) Set(tips ...MessageID) {
 	for _, messageID := range tips {
 		t.strongTips.Set(messageID, messageID)
+		t.tangle.Storage.MessageMetadata(messageID).Consume(func(messageMetadata *MessageMetadata) {
+			t.indexTip(messageID, messageMetadata.BranchID())
+		})
 	}
 }
 
@@ -103,13 +139,20 @@ func (t *TipManager) AddTip(message *Message) {
 		return
 	}
 
+	// stale messages would be evicted right away, so they never become tips
+	if t.IsStaleTip(messageID) {
+		return
+	}
+
 	// TODO: possible logical race condition if a child message gets added before its parents.
 	//  To be sure we probably need to check "It is not directly referenced by any strong message via strong/weak parent"
 	//  before adding a message as a tip. For now we're using only 1 worker after the scheduler and it shouldn't be a problem.
 
 	// if branch is monotonically liked: strong message
 	// if branch is not monotonically liked: weak message
-	t.tangle.LedgerState.branchDAG.Branch(messageMetadata.BranchID()).Consume(func(branch ledgerstate.Branch) {
+	t.tangle.LedgerState.BranchDAG.Branch(messageMetadata.BranchID()).Consume(func(branch ledgerstate.Branch) {
+		t.indexTip(messageID, branch.ID())
+
 		if branch.MonotonicallyLiked() {
 			if t.strongTips.Set(messageID, messageID) {
 				t.Events.TipAdded.Trigger(&TipEvent{
@@ -126,6 +169,7 @@ func (t *TipManager) AddTip(message *Message) {
 			// a strong tip loses its tip status if it is referenced by a strong message via strong parent
 			message.ForEachStrongParent(func(parent MessageID) {
 				if _, deleted := t.strongTips.Delete(parent); deleted {
+					t.unindexTip(parent)
 					t.Events.TipRemoved.Trigger(&TipEvent{
 						MessageID: parent,
 						TipType:   StrongTip,
@@ -135,6 +179,7 @@ func (t *TipManager) AddTip(message *Message) {
 			// a weak tip loses its tip status if it is referenced by a strong message via weak parent
 			message.ForEachWeakParent(func(parent MessageID) {
 				if _, deleted := t.weakTips.Delete(parent); deleted {
+					t.unindexTip(parent)
 					t.Events.TipRemoved.Trigger(&TipEvent{
 						MessageID: parent,
 						TipType:   WeakTip,
@@ -152,6 +197,136 @@ func (t *TipManager) AddTip(message *Message) {
 	})
 }
 
+// TipSelectionStrategy returns the TipSelectionStrategy that is used to select the strong tips.
+func (t *TipManager) TipSelectionStrategy() TipSelectionStrategy {
+	t.tipSelectionStrategyMutex.RLock()
+	defer t.tipSelectionStrategyMutex.RUnlock()
+
+	return t.tipSelectionStrategy
+}
+
+// SetTipSelectionStrategy replaces the TipSelectionStrategy that is used to select the strong tips.
+func (t *TipManager) SetTipSelectionStrategy(tipSelectionStrategy TipSelectionStrategy) {
+	t.tipSelectionStrategyMutex.Lock()
+	defer t.tipSelectionStrategyMutex.Unlock()
+
+	t.tipSelectionStrategy = tipSelectionStrategy
+}
+
+// UpdateTipTypes re-evaluates the type of the tips of the given Branch: strong tips whose Branch is not monotonically
+// liked anymore become weak tips, while weak tips whose Branch became monotonically liked become strong tips.
+func (t *TipManager) UpdateTipTypes(branchID ledgerstate.BranchID) {
+	for _, messageID := range t.branchTips(branchID) {
+		t.updateTipType(messageID)
+	}
+}
+
+// updateTipType moves the given tip to the tip pool that corresponds to the liked status of its Branch.
+func (t *TipManager) updateTipType(messageID MessageID) {
+	if t.isMonotonicallyLiked(messageID) {
+		t.switchTipType(messageID, t.weakTips, WeakTip, t.strongTips, StrongTip)
+		return
+	}
+
+	t.switchTipType(messageID, t.strongTips, StrongTip, t.weakTips, WeakTip)
+}
+
+// indexTip adds the given tip to the index of the tips of the given Branch.
+func (t *TipManager) indexTip(messageID MessageID, branchID ledgerstate.BranchID) {
+	t.tipsByBranchMutex.Lock()
+	defer t.tipsByBranchMutex.Unlock()
+
+	t.indexTipWithoutLocking(messageID, branchID)
+}
+
+// moveTip moves an indexed tip to the index of the given Branch. It returns false if the Message is not a tip.
+func (t *TipManager) moveTip(messageID MessageID, branchID ledgerstate.BranchID) (moved bool) {
+	t.tipsByBranchMutex.Lock()
+	defer t.tipsByBranchMutex.Unlock()
+
+	if currentBranchID, isTip := t.tipBranches[messageID]; !isTip || currentBranchID == branchID {
+		return isTip
+	}
+
+	t.indexTipWithoutLocking(messageID, branchID)
+
+	return true
+}
+
+// indexTipWithoutLocking is the non-locking version of indexTip that expects the caller to hold the lock.
+func (t *TipManager) indexTipWithoutLocking(messageID MessageID, branchID ledgerstate.BranchID) {
+	t.unindexTipWithoutLocking(messageID)
+
+	if _, exists := t.tipsByBranch[branchID]; !exists {
+		t.tipsByBranch[branchID] = make(map[MessageID]types.Empty)
+	}
+	t.tipsByBranch[branchID][messageID] = types.Void
+	t.tipBranches[messageID] = branchID
+}
+
+// unindexTip removes the given tip from the index of the tips of its Branch.
+func (t *TipManager) unindexTip(messageID MessageID) {
+	t.tipsByBranchMutex.Lock()
+	defer t.tipsByBranchMutex.Unlock()
+
+	t.unindexTipWithoutLocking(messageID)
+}
+
+// unindexTipWithoutLocking is the non-locking version of unindexTip that expects the caller to hold the lock.
+func (t *TipManager) unindexTipWithoutLocking(messageID MessageID) {
+	branchID, indexed := t.tipBranches[messageID]
+	if !indexed {
+		return
+	}
+
+	delete(t.tipBranches, messageID)
+	if delete(t.tipsByBranch[branchID], messageID); len(t.tipsByBranch[branchID]) == 0 {
+		delete(t.tipsByBranch, branchID)
+	}
+}
+
+// branchTips returns the tips whose Messages are booked in the given Branch.
+func (t *TipManager) branchTips(branchID ledgerstate.BranchID) (tips MessageIDs) {
+	t.tipsByBranchMutex.Lock()
+	defer t.tipsByBranchMutex.Unlock()
+
+	tips = make(MessageIDs, 0, len(t.tipsByBranch[branchID]))
+	for messageID := range t.tipsByBranch[branchID] {
+		tips = append(tips, messageID)
+	}
+
+	return
+}
+
+// isMonotonicallyLiked returns true if the Branch of the given Message is monotonically liked.
+func (t *TipManager) isMonotonicallyLiked(messageID MessageID) (monotonicallyLiked bool) {
+	t.tangle.Storage.MessageMetadata(messageID).Consume(func(messageMetadata *MessageMetadata) {
+		t.tangle.LedgerState.BranchDAG.Branch(messageMetadata.BranchID()).Consume(func(branch ledgerstate.Branch) {
+			monotonicallyLiked = branch.MonotonicallyLiked()
+		})
+	})
+
+	return
+}
+
+// switchTipType moves a tip from one tip pool to the other and triggers the corresponding events.
+func (t *TipManager) switchTipType(messageID MessageID, from *randommap.RandomMap, fromType TipType, to *randommap.RandomMap, toType TipType) {
+	if _, deleted := from.Delete(messageID); !deleted {
+		return
+	}
+	t.Events.TipRemoved.Trigger(&TipEvent{
+		MessageID: messageID,
+		TipType:   fromType,
+	})
+
+	if to.Set(messageID, messageID) {
+		t.Events.TipAdded.Trigger(&TipEvent{
+			MessageID: messageID,
+			TipType:   toType,
+		})
+	}
+}
+
 // Tips returns count number of tips, maximum MaxParentsCount.
 func (t *TipManager) Tips(p payload.Payload, countStrongParents, countWeakParents int) (strongParents, weakParents MessageIDs, err error) {
 	if countStrongParents > MaxParentsCount {
@@ -239,7 +414,7 @@ func (t *TipManager) selectStrongTips(p payload.Payload, count int) (parents Mes
 		count = MaxParentsCount - len(parents)
 	}
 
-	tips := t.strongTips.RandomUniqueEntries(count)
+	tips := t.selectTipsWithStrategy(count)
 	// count is invalid or there are no tips
 	if len(tips) == 0 {
 		// only add genesis if no tip was found and not previously referenced (in case of a transaction)
@@ -249,8 +424,7 @@ func (t *TipManager) selectStrongTips(p payload.Payload, count int) (parents Mes
 		return
 	}
 	// at least one tip is returned
-	for _, tip := range tips {
-		messageID := tip.(MessageID)
+	for _, messageID := range tips {
 		if _, ok := parentsMap[messageID]; !ok {
 			parentsMap[messageID] = types.Void
 			parents = append(parents, messageID)
@@ -260,6 +434,20 @@ func (t *TipManager) selectStrongTips(p payload.Payload, count int) (parents Mes
 	return
 }
 
+// selectTipsWithStrategy selects up to count strong tips by using the current TipSelectionStrategy.
+func (t *TipManager) selectTipsWithStrategy(count int) (tips MessageIDs) {
+	if count <= 0 {
+		return
+	}
+
+	candidates := make(MessageIDs, 0, t.strongTips.Size())
+	for _, key := range t.strongTips.Keys() {
+		candidates = append(candidates, key.(MessageID))
+	}
+
+	return t.TipSelectionStrategy().SelectTips(t.tangle, candidates, count)
+}
+
 // selectWeakTips returns a list of randomly selected weak parents.
 func (t *TipManager) selectWeakTips(count int) (parents MessageIDs) {
 	parents = make([]MessageID, 0, count)
@@ -277,6 +465,84 @@ func (t *TipManager) selectWeakTips(count int) (parents MessageIDs) {
 	return
 }
 
+// EvictStaleTips removes all the strong and weak tips that exceed the configured maximum tip age or maximum tip depth
+// and returns the amount of evicted tips.
+func (t *TipManager) EvictStaleTips() (evictedTips int) {
+	evictedTips += t.evictStaleTips(t.strongTips, StrongTip)
+	evictedTips += t.evictStaleTips(t.weakTips, WeakTip)
+
+	return
+}
+
+// IsStaleTip returns true if the given Message exceeds the configured maximum tip age or maximum tip depth and should
+// therefore not be used as a tip anymore.
+func (t *TipManager) IsStaleTip(messageID MessageID) (stale bool) {
+	if messageID == EmptyMessageID {
+		return false
+	}
+
+	params := t.tangle.Options.TipEvictionParams
+	if params.MaxTipAge > 0 && clock.SyncedTime().Sub(issuingTime(t.tangle, messageID)) > params.MaxTipAge {
+		return true
+	}
+
+	return params.MaxTipDepth > 0 && t.tipDepth(messageID) > params.MaxTipDepth
+}
+
+// evictStaleTips removes the stale tips from the given tip pool and triggers the corresponding events.
+func (t *TipManager) evictStaleTips(tips *randommap.RandomMap, tipType TipType) (evictedTips int) {
+	for _, key := range tips.Keys() {
+		messageID := key.(MessageID)
+		if !t.IsStaleTip(messageID) {
+			continue
+		}
+
+		if _, deleted := tips.Delete(messageID); deleted {
+			t.unindexTip(messageID)
+			tipEvent := &TipEvent{
+				MessageID: messageID,
+				TipType:   tipType,
+			}
+			t.Events.TipRemoved.Trigger(tipEvent)
+			t.Events.TipEvicted.Trigger(tipEvent)
+
+			evictedTips++
+		}
+	}
+
+	return
+}
+
+// tipDepth returns the distance (in Marker indexes) between the given tip and the most recent Marker of the Sequences
+// that it references. If the tip references multiple Sequences, the smallest distance is returned.
+func (t *TipManager) tipDepth(messageID MessageID) (depth markers.Index) {
+	t.tangle.Storage.MessageMetadata(messageID).Consume(func(messageMetadata *MessageMetadata) {
+		structureDetails := messageMetadata.StructureDetails()
+		if structureDetails == nil {
+			return
+		}
+
+		firstSequence := true
+		structureDetails.PastMarkers.ForEach(func(sequenceID markers.SequenceID, index markers.Index) bool {
+			t.tangle.Booker.MarkersManager.Sequence(sequenceID).Consume(func(sequence *markers.Sequence) {
+				var sequenceDepth markers.Index
+				if highestIndex := sequence.HighestIndex(); highestIndex > index {
+					sequenceDepth = highestIndex - index
+				}
+
+				if firstSequence || sequenceDepth < depth {
+					depth = sequenceDepth
+					firstSequence = false
+				}
+			})
+
+			return true
+		})
+	})
+
+	return
+}
+
 // StrongTipCount the amount of strong tips.
 func (t *TipManager) StrongTipCount() int {
 	return t.strongTips.Size()
@@ -289,6 +555,21 @@ func (t *TipManager) WeakTipCount() int {
 
 // endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
 
+// region TipEvictionParams ////////////////////////////////////////////////////////////////////////////////////////////
+
+// TipEvictionParams represents the parameters that define when a tip is considered to be stale.
+type TipEvictionParams struct {
+	// MaxTipAge defines the maximum age of a tip. It is a local eviction policy and does not affect the validity of
+	// Messages. A value of 0 disables the limit.
+	MaxTipAge time.Duration
+
+	// MaxTipDepth defines the maximum distance in Marker indexes between a tip and the most recent Marker of the
+	// Sequences it references. A value of 0 disables the limit.
+	MaxTipDepth markers.Index
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
 // region TipManagerEvents /////////////////////////////////////////////////////////////////////////////////////////////
 
 // TipManagerEvents represents events happening on the TipManager.
@@ -298,6 +579,9 @@ type TipManagerEvents struct {
 
 	// Fired when a tip is removed.
 	TipRemoved *events.Event
+
+	// Fired when a tip is removed because it exceeded the maximum tip age or depth (TipRemoved is fired as well).
+	TipEvicted *events.Event
 }
 
 // endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
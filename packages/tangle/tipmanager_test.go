@@ -360,7 +360,7 @@ func TestTipManager_TransactionTips(t *testing.T) {
 	tangle.LedgerState.LoadSnapshot(snapshot)
 	// determine genesis index so that correct output can be referenced
 	var g1, g2 uint16
-	tangle.LedgerState.utxoDAG.Output(ledgerstate.NewOutputID(ledgerstate.GenesisTransactionID, 0)).Consume(func(output ledgerstate.Output) {
+	tangle.LedgerState.UTXODAG.Output(ledgerstate.NewOutputID(ledgerstate.GenesisTransactionID, 0)).Consume(func(output ledgerstate.Output) {
 		balance, _ := output.Balances().Get(ledgerstate.ColorIOTA)
 		if balance == uint64(5) {
 			g1 = 0
@@ -760,12 +760,117 @@ func TestTipManager_TransactionTips(t *testing.T) {
 	}
 }
 
+func TestTipManager_UpdateTipTypes(t *testing.T) {
+	tangle := New()
+	defer tangle.Shutdown()
+	tipManager := tangle.TipManager
+	tipManager.Setup()
+
+	conflictBranchID := ledgerstate.BranchID{2}
+	cachedConflictBranch, _, err := tangle.LedgerState.BranchDAG.CreateConflictBranch(conflictBranchID, ledgerstate.NewBranchIDs(ledgerstate.MasterBranchID), ledgerstate.NewConflictIDs(ledgerstate.ConflictID{1}))
+	require.NoError(t, err)
+	cachedConflictBranch.Release()
+
+	masterMessage := createAndStoreEligibleTestParentsDataMessageInMasterBranch(tangle, []MessageID{EmptyMessageID}, []MessageID{})
+	tipManager.AddTip(masterMessage)
+	conflictMessage := newTestParentsDataMessage("conflict", []MessageID{EmptyMessageID}, []MessageID{})
+	tangle.Storage.StoreMessage(conflictMessage)
+	conflictMessage.setMessageMetadata(tangle, true, conflictBranchID)
+	tipManager.AddTip(conflictMessage)
+
+	assert.Equal(t, MessageIDs{masterMessage.ID()}, tipManager.branchTips(ledgerstate.MasterBranchID))
+	assert.Equal(t, MessageIDs{conflictMessage.ID()}, tipManager.branchTips(conflictBranchID))
+	assert.ElementsMatch(t, []interface{}{masterMessage.ID()}, tipManager.strongTips.Keys())
+	assert.ElementsMatch(t, []interface{}{conflictMessage.ID()}, tipManager.weakTips.Keys())
+
+	// only the tips of the liked Branch become strong tips
+	_, err = tangle.LedgerState.BranchDAG.SetBranchMonotonicallyLiked(conflictBranchID, true)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []interface{}{masterMessage.ID(), conflictMessage.ID()}, tipManager.strongTips.Keys())
+	assert.Equal(t, 0, tipManager.WeakTipCount())
+
+	// tips follow their Message to a new Branch
+	masterMessage.setMessageMetadata(tangle, true, conflictBranchID)
+	tangle.Booker.Events.MessageBranchUpdated.Trigger(masterMessage.ID())
+	assert.Empty(t, tipManager.branchTips(ledgerstate.MasterBranchID))
+	assert.ElementsMatch(t, MessageIDs{masterMessage.ID(), conflictMessage.ID()}, tipManager.branchTips(conflictBranchID))
+
+	_, err = tangle.LedgerState.BranchDAG.SetBranchMonotonicallyLiked(conflictBranchID, false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, tipManager.StrongTipCount())
+	assert.ElementsMatch(t, []interface{}{masterMessage.ID(), conflictMessage.ID()}, tipManager.weakTips.Keys())
+}
+
+func TestTipManager_EvictStaleTips(t *testing.T) {
+	t.Run("MaxTipAge", func(t *testing.T) {
+		tangle := New(TipEvictionConfig(TipEvictionParams{MaxTipAge: time.Minute}))
+		defer tangle.Shutdown()
+
+		evictedTips := make(map[MessageID]TipType)
+		tangle.TipManager.Events.TipEvicted.Attach(events.NewClosure(func(tipEvent *TipEvent) {
+			evictedTips[tipEvent.MessageID] = tipEvent.TipType
+		}))
+
+		messages := make(map[string]*Message)
+		storeMessage := func(alias string, age time.Duration, branchID ledgerstate.BranchID) {
+			messages[alias] = newTestParentsDataWithTimestamp(alias, []MessageID{EmptyMessageID}, []MessageID{}, time.Now().Add(-age))
+			tangle.Storage.StoreMessage(messages[alias])
+			messages[alias].setMessageMetadata(tangle, true, branchID)
+		}
+		storeMessage("old", 2*time.Minute, ledgerstate.MasterBranchID)
+		storeMessage("oldWeak", 2*time.Minute, ledgerstate.InvalidBranchID)
+		storeMessage("young", time.Second, ledgerstate.MasterBranchID)
+
+		// stale messages do not become tips in the first place
+		tangle.TipManager.AddTip(messages["old"])
+		tangle.TipManager.AddTip(messages["young"])
+		assert.Equal(t, 1, tangle.TipManager.StrongTipCount())
+
+		tangle.TipManager.Set(messages["old"].ID())
+		tangle.TipManager.weakTips.Set(messages["oldWeak"].ID(), messages["oldWeak"].ID())
+		assert.Equal(t, 2, tangle.TipManager.StrongTipCount())
+		assert.Equal(t, 1, tangle.TipManager.WeakTipCount())
+
+		assert.Equal(t, 2, tangle.TipManager.EvictStaleTips())
+		assert.Equal(t, map[MessageID]TipType{messages["old"].ID(): StrongTip, messages["oldWeak"].ID(): WeakTip}, evictedTips)
+		assert.Equal(t, []interface{}{messages["young"].ID()}, tangle.TipManager.strongTips.Keys())
+		assert.Equal(t, 0, tangle.TipManager.WeakTipCount())
+
+		// the remaining tip is not stale
+		assert.Equal(t, 0, tangle.TipManager.EvictStaleTips())
+	})
+
+	t.Run("MaxTipDepth", func(t *testing.T) {
+		tangle := New(WithoutOpinionFormer(true), TipEvictionConfig(TipEvictionParams{MaxTipDepth: 2}))
+		defer tangle.Shutdown()
+
+		// each message of the chain creates a new Marker in the same Sequence
+		messages := make([]*Message, 0)
+		parent := EmptyMessageID
+		for i := 0; i < 4; i++ {
+			message := newTestParentsDataMessage(strconv.Itoa(i), []MessageID{parent}, []MessageID{})
+			tangle.Storage.StoreMessage(message)
+			require.NoError(t, tangle.Booker.Book(message.ID()))
+			messages = append(messages, message)
+			parent = message.ID()
+		}
+
+		tangle.TipManager.Set(messages[0].ID(), messages[1].ID(), messages[3].ID())
+		assert.True(t, tangle.TipManager.IsStaleTip(messages[0].ID()))
+		assert.False(t, tangle.TipManager.IsStaleTip(messages[1].ID()))
+		assert.False(t, tangle.TipManager.IsStaleTip(EmptyMessageID))
+
+		assert.Equal(t, 1, tangle.TipManager.EvictStaleTips())
+		assert.ElementsMatch(t, []interface{}{messages[1].ID(), messages[3].ID()}, tangle.TipManager.strongTips.Keys())
+	})
+}
+
 func storeBookLikeMessage(t *testing.T, tangle *Tangle, message *Message) {
 	// we need to store and book transactions so that we also have attachments of transactions available
 	tangle.Storage.StoreMessage(message)
 	// TODO: CheckTransaction should be removed here once the booker passes on errors
 	if message.payload.Type() == ledgerstate.TransactionType {
-		_, err := tangle.LedgerState.utxoDAG.CheckTransaction(message.payload.(*ledgerstate.Transaction))
+		_, err := tangle.LedgerState.UTXODAG.CheckTransaction(message.payload.(*ledgerstate.Transaction))
 		require.NoError(t, err)
 	}
 	err := tangle.Booker.Book(message.ID())
@@ -0,0 +1,318 @@
+package tangle
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/clock"
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/goshimmer/packages/markers"
+	"github.com/iotaledger/hive.go/datastructure/walker"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// UniformTipSelection is the name of the TipSelectionStrategy that selects tips uniformly at random.
+	UniformTipSelection = "uniform"
+
+	// OldestFirstTipSelection is the name of the TipSelectionStrategy that selects the oldest tips first.
+	OldestFirstTipSelection = "oldestFirst"
+
+	// MaxAgeTipSelection is the name of the TipSelectionStrategy that selects tips uniformly at random from the tips
+	// that are not older than a maximum age.
+	MaxAgeTipSelection = "maxAge"
+
+	// ApprovalWeightTipSelection is the name of the TipSelectionStrategy that selects tips at random weighted by the
+	// approval weight of their past Markers.
+	ApprovalWeightTipSelection = "approvalWeight"
+
+	// LazyTipAvoidanceTipSelection is the name of the TipSelectionStrategy that selects tips uniformly at random from
+	// the tips whose past cone does not contain any disliked Branches.
+	LazyTipAvoidanceTipSelection = "lazyTipAvoidance"
+
+	// DefaultTipSelectionMaxAge defines the default maximum age of the tips that are selected by the MaxAge strategy.
+	DefaultTipSelectionMaxAge = time.Minute
+
+	// minTipSelectionWeight defines the weight of the tips without any approval weight, so that they can still be
+	// selected by the ApprovalWeight strategy.
+	minTipSelectionWeight = 0.01
+
+	// maxLazyTipPastConeSize defines the maximum number of Messages in the past cone of a tip that are checked by the
+	// LazyTipAvoidance strategy.
+	maxLazyTipPastConeSize = 1000
+)
+
+var (
+	// ErrUnknownTipSelectionStrategy is returned if a TipSelectionStrategy with an unknown name is requested.
+	ErrUnknownTipSelectionStrategy = errors.New("unknown tip selection strategy")
+)
+
+// region TipSelectionStrategy /////////////////////////////////////////////////////////////////////////////////////////
+
+// TipSelectionStrategy is the interface for the strategies that are used by the TipManager to select the strong
+// parents of new Messages from the current strong tips.
+type TipSelectionStrategy interface {
+	// Name returns the name of the strategy.
+	Name() string
+
+	// SelectTips selects up to count unique tips from the given candidates.
+	SelectTips(tangle *Tangle, candidates MessageIDs, count int) (selectedTips MessageIDs)
+}
+
+// TipSelectionStrategyNames returns the names of all the built-in TipSelectionStrategies.
+func TipSelectionStrategyNames() []string {
+	return []string{
+		UniformTipSelection,
+		OldestFirstTipSelection,
+		MaxAgeTipSelection,
+		ApprovalWeightTipSelection,
+		LazyTipAvoidanceTipSelection,
+	}
+}
+
+// NewTipSelectionStrategy returns the built-in TipSelectionStrategy with the given name. The maxAge is only used by
+// the MaxAge strategy.
+func NewTipSelectionStrategy(name string, maxAge time.Duration) (TipSelectionStrategy, error) {
+	switch name {
+	case UniformTipSelection:
+		return &UniformStrategy{}, nil
+	case OldestFirstTipSelection:
+		return &OldestFirstStrategy{}, nil
+	case MaxAgeTipSelection:
+		return &MaxAgeStrategy{MaxAge: maxAge}, nil
+	case ApprovalWeightTipSelection:
+		return &ApprovalWeightStrategy{}, nil
+	case LazyTipAvoidanceTipSelection:
+		return &LazyTipAvoidanceStrategy{}, nil
+	default:
+		return nil, xerrors.Errorf("failed to create tip selection strategy %s: %w", name, ErrUnknownTipSelectionStrategy)
+	}
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region UniformStrategy //////////////////////////////////////////////////////////////////////////////////////////////
+
+// UniformStrategy is a TipSelectionStrategy that selects tips uniformly at random.
+type UniformStrategy struct{}
+
+// Name returns the name of the strategy.
+func (u *UniformStrategy) Name() string {
+	return UniformTipSelection
+}
+
+// SelectTips selects up to count unique tips uniformly at random from the given candidates.
+func (u *UniformStrategy) SelectTips(_ *Tangle, candidates MessageIDs, count int) (selectedTips MessageIDs) {
+	return selectUniformTips(candidates, count)
+}
+
+// selectUniformTips is an internal utility function that selects up to count unique tips uniformly at random.
+func selectUniformTips(candidates MessageIDs, count int) (selectedTips MessageIDs) {
+	if count > len(candidates) {
+		count = len(candidates)
+	}
+
+	selectedTips = make(MessageIDs, 0, count)
+	for _, index := range rand.Perm(len(candidates))[:count] {
+		selectedTips = append(selectedTips, candidates[index])
+	}
+
+	return
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region OldestFirstStrategy //////////////////////////////////////////////////////////////////////////////////////////
+
+// OldestFirstStrategy is a TipSelectionStrategy that selects the tips with the oldest issuing time first.
+type OldestFirstStrategy struct{}
+
+// Name returns the name of the strategy.
+func (o *OldestFirstStrategy) Name() string {
+	return OldestFirstTipSelection
+}
+
+// SelectTips selects up to count tips with the oldest issuing time from the given candidates.
+func (o *OldestFirstStrategy) SelectTips(tangle *Tangle, candidates MessageIDs, count int) (selectedTips MessageIDs) {
+	issuingTimes := make(map[MessageID]time.Time, len(candidates))
+	for _, candidate := range candidates {
+		issuingTimes[candidate] = issuingTime(tangle, candidate)
+	}
+
+	selectedTips = make(MessageIDs, len(candidates))
+	copy(selectedTips, candidates)
+	sort.Slice(selectedTips, func(i, j int) bool {
+		return issuingTimes[selectedTips[i]].Before(issuingTimes[selectedTips[j]])
+	})
+
+	if count < len(selectedTips) {
+		selectedTips = selectedTips[:count]
+	}
+
+	return
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region MaxAgeStrategy ///////////////////////////////////////////////////////////////////////////////////////////////
+
+// MaxAgeStrategy is a TipSelectionStrategy that selects tips uniformly at random from the tips whose issuing time is
+// not older than the given maximum age.
+type MaxAgeStrategy struct {
+	// MaxAge defines the maximum age of the selected tips.
+	MaxAge time.Duration
+}
+
+// Name returns the name of the strategy.
+func (m *MaxAgeStrategy) Name() string {
+	return MaxAgeTipSelection
+}
+
+// SelectTips selects up to count unique tips uniformly at random from the candidates that are young enough.
+func (m *MaxAgeStrategy) SelectTips(tangle *Tangle, candidates MessageIDs, count int) (selectedTips MessageIDs) {
+	youngCandidates := make(MessageIDs, 0, len(candidates))
+	for _, candidate := range candidates {
+		if clock.SyncedTime().Sub(issuingTime(tangle, candidate)) <= m.MaxAge {
+			youngCandidates = append(youngCandidates, candidate)
+		}
+	}
+
+	return selectUniformTips(youngCandidates, count)
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region ApprovalWeightStrategy ///////////////////////////////////////////////////////////////////////////////////////
+
+// ApprovalWeightStrategy is a TipSelectionStrategy that selects tips at random where the probability of a tip to be
+// selected is proportional to the highest approval weight of its past Markers.
+type ApprovalWeightStrategy struct{}
+
+// Name returns the name of the strategy.
+func (a *ApprovalWeightStrategy) Name() string {
+	return ApprovalWeightTipSelection
+}
+
+// SelectTips selects up to count unique tips at random weighted by their approval weight.
+func (a *ApprovalWeightStrategy) SelectTips(tangle *Tangle, candidates MessageIDs, count int) (selectedTips MessageIDs) {
+	remainingCandidates := make(MessageIDs, len(candidates))
+	copy(remainingCandidates, candidates)
+
+	weights := make([]float64, len(candidates))
+	totalWeight := 0.0
+	for i, candidate := range candidates {
+		weights[i] = minTipSelectionWeight + approvalWeightOfTip(tangle, candidate)
+		totalWeight += weights[i]
+	}
+
+	selectedTips = make(MessageIDs, 0, count)
+	for len(selectedTips) < count && len(remainingCandidates) > 0 {
+		selectedIndex := len(remainingCandidates) - 1
+		for i, threshold := 0, rand.Float64()*totalWeight; i < len(remainingCandidates); i++ {
+			if threshold -= weights[i]; threshold < 0 {
+				selectedIndex = i
+				break
+			}
+		}
+		selectedTips = append(selectedTips, remainingCandidates[selectedIndex])
+
+		// remove the selected tip from the remaining candidates
+		totalWeight -= weights[selectedIndex]
+		lastIndex := len(remainingCandidates) - 1
+		remainingCandidates[selectedIndex], weights[selectedIndex] = remainingCandidates[lastIndex], weights[lastIndex]
+		remainingCandidates, weights = remainingCandidates[:lastIndex], weights[:lastIndex]
+	}
+
+	return
+}
+
+// approvalWeightOfTip returns the highest approval weight of the past Markers of the given tip.
+func approvalWeightOfTip(tangle *Tangle, messageID MessageID) (weight float64) {
+	tangle.Storage.MessageMetadata(messageID).Consume(func(messageMetadata *MessageMetadata) {
+		structureDetails := messageMetadata.StructureDetails()
+		if structureDetails == nil {
+			return
+		}
+
+		structureDetails.PastMarkers.ForEach(func(sequenceID markers.SequenceID, index markers.Index) bool {
+			if markerWeight := tangle.ApprovalWeightManager.Weight(markers.NewMarker(sequenceID, index)); markerWeight > weight {
+				weight = markerWeight
+			}
+			return true
+		})
+	})
+
+	return
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region LazyTipAvoidanceStrategy /////////////////////////////////////////////////////////////////////////////////////
+
+// LazyTipAvoidanceStrategy is a TipSelectionStrategy that selects tips uniformly at random from the tips whose past
+// cone does not contain any disliked Branches. The past cone of a tip is only walked until the confirmed Messages, as
+// their Branches are already decided.
+type LazyTipAvoidanceStrategy struct{}
+
+// Name returns the name of the strategy.
+func (l *LazyTipAvoidanceStrategy) Name() string {
+	return LazyTipAvoidanceTipSelection
+}
+
+// SelectTips selects up to count unique tips uniformly at random from the candidates that are not lazy.
+func (l *LazyTipAvoidanceStrategy) SelectTips(tangle *Tangle, candidates MessageIDs, count int) (selectedTips MessageIDs) {
+	likedCandidates := make(MessageIDs, 0, len(candidates))
+	for _, candidate := range candidates {
+		if !isLazyTip(tangle, candidate) {
+			likedCandidates = append(likedCandidates, candidate)
+		}
+	}
+
+	return selectUniformTips(likedCandidates, count)
+}
+
+// isLazyTip is an internal utility function that checks if the unconfirmed past cone of the given tip contains a
+// Message that is booked into a Branch that is not monotonically liked. Only the first maxLazyTipPastConeSize
+// Messages of the past cone are checked, so that a long unconfirmed history does not stall the tip selection.
+func isLazyTip(tangle *Tangle, tip MessageID) (lazy bool) {
+	visitedMessages := 0
+	tangle.Utils.WalkMessageAndMetadata(func(message *Message, messageMetadata *MessageMetadata, walker *walker.Walker) {
+		if visitedMessages++; visitedMessages > maxLazyTipPastConeSize {
+			walker.StopWalk()
+			return
+		}
+
+		if messageMetadata.IsConfirmed() {
+			return
+		}
+
+		tangle.LedgerState.BranchDAG.Branch(messageMetadata.BranchID()).Consume(func(branch ledgerstate.Branch) {
+			lazy = !branch.MonotonicallyLiked()
+		})
+		if lazy {
+			walker.StopWalk()
+			return
+		}
+
+		message.ForEachParent(func(parent Parent) {
+			if parent.ID != EmptyMessageID {
+				walker.Push(parent.ID)
+			}
+		})
+	}, MessageIDs{tip})
+
+	return
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// issuingTime is an internal utility function that returns the issuing time of the given Message.
+func issuingTime(tangle *Tangle, messageID MessageID) (issuingTime time.Time) {
+	tangle.Storage.Message(messageID).Consume(func(message *Message) {
+		issuingTime = message.IssuingTime()
+	})
+
+	return
+}
@@ -0,0 +1,102 @@
+package tangle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+)
+
+func TestNewTipSelectionStrategy(t *testing.T) {
+	for _, name := range TipSelectionStrategyNames() {
+		strategy, err := NewTipSelectionStrategy(name, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, name, strategy.Name())
+	}
+
+	_, err := NewTipSelectionStrategy("unknown", time.Minute)
+	assert.True(t, xerrors.Is(err, ErrUnknownTipSelectionStrategy))
+}
+
+func TestTipSelectionStrategies(t *testing.T) {
+	tangle := New()
+	defer tangle.Shutdown()
+
+	messages := make(map[string]*Message)
+	storeMessage := func(alias string, age time.Duration, branchID ledgerstate.BranchID) {
+		messages[alias] = newTestParentsDataWithTimestamp(alias, []MessageID{EmptyMessageID}, []MessageID{}, time.Now().Add(-age))
+		tangle.Storage.StoreMessage(messages[alias])
+		messages[alias].setMessageMetadata(tangle, true, branchID)
+	}
+	storeMessage("old", 2*time.Hour, ledgerstate.MasterBranchID)
+	storeMessage("older", 3*time.Hour, ledgerstate.MasterBranchID)
+	storeMessage("young", time.Second, ledgerstate.MasterBranchID)
+	storeMessage("lazy", time.Second, ledgerstate.InvalidBranchID)
+	candidates := MessageIDs{messages["old"].ID(), messages["older"].ID(), messages["young"].ID(), messages["lazy"].ID()}
+
+	t.Run("Uniform", func(t *testing.T) {
+		selectedTips := (&UniformStrategy{}).SelectTips(tangle, candidates, 3)
+		assert.Len(t, selectedTips, 3)
+		assert.Subset(t, candidates, selectedTips)
+
+		assert.ElementsMatch(t, candidates, (&UniformStrategy{}).SelectTips(tangle, candidates, 8))
+	})
+
+	t.Run("OldestFirst", func(t *testing.T) {
+		assert.Equal(t, MessageIDs{messages["older"].ID(), messages["old"].ID()}, (&OldestFirstStrategy{}).SelectTips(tangle, candidates, 2))
+	})
+
+	t.Run("MaxAge", func(t *testing.T) {
+		assert.ElementsMatch(t, MessageIDs{messages["young"].ID(), messages["lazy"].ID()}, (&MaxAgeStrategy{MaxAge: time.Hour}).SelectTips(tangle, candidates, 8))
+	})
+
+	t.Run("ApprovalWeight", func(t *testing.T) {
+		selectedTips := (&ApprovalWeightStrategy{}).SelectTips(tangle, candidates, 3)
+		assert.Len(t, selectedTips, 3)
+		assert.Subset(t, candidates, selectedTips)
+
+		assert.ElementsMatch(t, candidates, (&ApprovalWeightStrategy{}).SelectTips(tangle, candidates, 8))
+	})
+
+	t.Run("LazyTipAvoidance", func(t *testing.T) {
+		assert.NotContains(t, (&LazyTipAvoidanceStrategy{}).SelectTips(tangle, candidates, 8), messages["lazy"].ID())
+		assert.Len(t, (&LazyTipAvoidanceStrategy{}).SelectTips(tangle, candidates, 8), 3)
+
+		// tips are lazy if their unconfirmed past cone contains a disliked Branch
+		storeChild := func(alias string, parentAlias string) {
+			messages[alias] = newTestParentsDataWithTimestamp(alias, []MessageID{messages[parentAlias].ID()}, []MessageID{}, time.Now())
+			tangle.Storage.StoreMessage(messages[alias])
+			messages[alias].setMessageMetadata(tangle, true, ledgerstate.MasterBranchID)
+		}
+		storeChild("lazyChild", "lazy")
+		storeChild("lazyGrandChild", "lazyChild")
+		storeChild("youngChild", "young")
+		lazyCandidates := MessageIDs{messages["lazyChild"].ID(), messages["lazyGrandChild"].ID(), messages["youngChild"].ID()}
+		assert.Equal(t, MessageIDs{messages["youngChild"].ID()}, (&LazyTipAvoidanceStrategy{}).SelectTips(tangle, lazyCandidates, 8))
+
+		// the past cone is not walked beyond confirmed Messages
+		tangle.Storage.MessageMetadata(messages["lazyChild"].ID()).Consume(func(messageMetadata *MessageMetadata) {
+			messageMetadata.SetConfirmed(true)
+		})
+		assert.ElementsMatch(t, MessageIDs{messages["lazyGrandChild"].ID(), messages["youngChild"].ID()}, (&LazyTipAvoidanceStrategy{}).SelectTips(tangle, lazyCandidates[1:], 8))
+	})
+
+	t.Run("TipManager", func(t *testing.T) {
+		tangle.TipManager.Set(candidates...)
+		tangle.TipManager.SetTipSelectionStrategy(&OldestFirstStrategy{})
+		assert.Equal(t, OldestFirstTipSelection, tangle.TipManager.TipSelectionStrategy().Name())
+
+		strongParents, _, err := tangle.TipManager.Tips(nil, 2, 0)
+		require.NoError(t, err)
+		assert.Equal(t, MessageIDs{messages["older"].ID(), messages["old"].ID()}, strongParents)
+
+		// the genesis is selected if the configured strategy rejects all the tips
+		tangle.TipManager.SetTipSelectionStrategy(&MaxAgeStrategy{MaxAge: time.Millisecond})
+		strongParents, _, err = tangle.TipManager.Tips(nil, 2, 0)
+		require.NoError(t, err)
+		assert.Equal(t, MessageIDs{EmptyMessageID}, strongParents)
+	})
+}
@@ -5,7 +5,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"math/rand"
+	"sort"
 	"sync"
 	"time"
 
@@ -208,18 +210,13 @@ func (f *FPC) queryOpinions() ([]opinion.QueriedOpinions, error) {
 		return nil, ErrNoOpinionGiversAvailable
 	}
 
-	// select a random subset of opinion givers to query.
-	// if the same opinion giver is selected multiple times, we query it only once
-	// but use its opinion N selected times.
-	opinionGiversToQuery := map[opinion.OpinionGiver]int{}
-	for i := 0; i < f.paras.QuerySampleSize; i++ {
-		selected := opinionGivers[f.opinionGiverRng.Intn(len(opinionGivers))]
-		opinionGiversToQuery[selected]++
-	}
+	// select a random subset of opinion givers to query proportionally to their mana.
+	// if the same opinion giver is selected multiple times, we query it only once.
+	opinionGiversToQuery := ManaBasedSampling(opinionGivers, f.paras.QuerySampleSize, f.opinionGiverWeight, f.opinionGiverRng)
 
-	// votes per id
+	// votes per id (the sample is already proportional to mana, so every selection of an opinion giver counts once)
 	var voteMapMu sync.Mutex
-	voteMap := map[string]opinion.Opinions{}
+	voteMap := map[string]*weightedVotes{}
 
 	// holds queried opinions
 	allQueriedOpinions := []opinion.QueriedOpinions{}
@@ -241,40 +238,25 @@ func (f *FPC) queryOpinions() ([]opinion.QueriedOpinions, error) {
 				return
 			}
 
+			// the opinions are counted as many times as the opinion giver was selected.
 			queriedOpinions := opinion.QueriedOpinions{
 				OpinionGiverID: opinionGiverToQuery.ID().String(),
 				Opinions:       make(map[string]opinion.Opinion),
 				TimesCounted:   selectedCount,
+				Mana:           f.opinionGiverWeight(opinionGiverToQuery),
 			}
 
 			// add opinions to vote map
 			voteMapMu.Lock()
 			defer voteMapMu.Unlock()
-			for i, id := range conflictIDs {
-				votes, has := voteMap[id]
-				if !has {
-					votes = opinion.Opinions{}
-				}
-				// reuse the opinion N times selected.
-				// note this is always at least 1.
-				for j := 0; j < selectedCount; j++ {
-					votes = append(votes, opinions[i])
-				}
-				queriedOpinions.Opinions[id] = opinions[i]
-				voteMap[id] = votes
-			}
-			for i, id := range timestampIDs {
+			for i, id := range append(append([]string{}, conflictIDs...), timestampIDs...) {
 				votes, has := voteMap[id]
 				if !has {
-					votes = opinion.Opinions{}
-				}
-				// reuse the opinion N times selected.
-				// note this is always at least 1.
-				for j := 0; j < selectedCount; j++ {
-					votes = append(votes, opinions[i])
+					votes = &weightedVotes{}
+					voteMap[id] = votes
 				}
+				votes.add(opinions[i], float64(queriedOpinions.TimesCounted))
 				queriedOpinions.Opinions[id] = opinions[i]
-				voteMap[id] = votes
 			}
 			allQueriedOpinions = append(allQueriedOpinions, queriedOpinions)
 		}(opinionGiverToQuery, selectedCount)
@@ -285,29 +267,26 @@ func (f *FPC) queryOpinions() ([]opinion.QueriedOpinions, error) {
 	defer f.ctxsMu.RUnlock()
 	// compute liked percentage
 	for id, votes := range voteMap {
-		var likedSum float64
-		votedCount := float64(len(votes))
-
-		for _, o := range votes {
-			switch o {
-			case opinion.Unknown:
-				votedCount--
-			case opinion.Like:
-				likedSum++
-			}
-		}
-
 		// mark a round being done, even though there's no opinion,
 		// so this voting context will be cleared eventually
 		f.ctxs[id].Rounds++
-		if votedCount == 0 {
+		if votes.votedWeight == 0 {
 			continue
 		}
-		f.ctxs[id].Liked = likedSum / votedCount
+		f.ctxs[id].Liked = votes.likedWeight / votes.votedWeight
 	}
 	return allQueriedOpinions, nil
 }
 
+// opinionGiverWeight returns the weight of the given opinion giver which is its mana or the configured
+// UnknownManaWeight if its mana is unknown.
+func (f *FPC) opinionGiverWeight(opinionGiver opinion.OpinionGiver) float64 {
+	if mana := opinionGiver.Mana(); mana > 0 {
+		return mana
+	}
+	return f.paras.UnknownManaWeight
+}
+
 func (f *FPC) voteContextIDs() (conflictIDs []string, timestampIDs []string) {
 	f.ctxsMu.RLock()
 	defer f.ctxsMu.RUnlock()
@@ -321,3 +300,55 @@ func (f *FPC) voteContextIDs() (conflictIDs []string, timestampIDs []string) {
 	}
 	return conflictIDs, timestampIDs
 }
+
+// ManaBasedSampling selects sampleSize opinion givers with replacement, where the probability of an opinion giver to
+// be selected is proportional to its weight. It returns how many times each of the selected opinion givers was
+// selected.
+func ManaBasedSampling(opinionGivers []opinion.OpinionGiver, sampleSize int, weight func(opinion.OpinionGiver) float64, rng *rand.Rand) map[opinion.OpinionGiver]int {
+	cumulativeWeights := make([]float64, len(opinionGivers))
+	totalWeight := 0.0
+	for i, opinionGiver := range opinionGivers {
+		totalWeight += math.Max(weight(opinionGiver), 0)
+		cumulativeWeights[i] = totalWeight
+	}
+
+	selected := make(map[opinion.OpinionGiver]int)
+	if totalWeight == 0 {
+		// fall back to uniform sampling if nobody has any weight
+		for i := 0; i < sampleSize; i++ {
+			selected[opinionGivers[rng.Intn(len(opinionGivers))]]++
+		}
+		return selected
+	}
+
+	for i := 0; i < sampleSize; i++ {
+		r := rng.Float64() * totalWeight
+		index := sort.Search(len(cumulativeWeights), func(j int) bool {
+			return cumulativeWeights[j] > r
+		})
+		// guard against floating point inaccuracies at the upper end
+		if index == len(cumulativeWeights) {
+			index--
+		}
+		selected[opinionGivers[index]]++
+	}
+
+	return selected
+}
+
+// weightedVotes accumulates the weights of the opinions about a single vote context.
+type weightedVotes struct {
+	likedWeight float64
+	votedWeight float64
+}
+
+// add adds the given opinion with the given weight. Unknown opinions are ignored.
+func (w *weightedVotes) add(o opinion.Opinion, weight float64) {
+	switch o {
+	case opinion.Unknown:
+		return
+	case opinion.Like:
+		w.likedWeight += weight
+	}
+	w.votedWeight += weight
+}
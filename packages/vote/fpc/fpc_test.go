@@ -3,6 +3,7 @@ package fpc_test
 import (
 	"context"
 	"errors"
+	"math/rand"
 	"testing"
 
 	"github.com/iotaledger/goshimmer/packages/vote"
@@ -64,12 +65,17 @@ func TestFPCPreventSameIDMultipleTimes(t *testing.T) {
 type opiniongivermock struct {
 	roundsReplies []opinion.Opinions
 	roundIndex    int
+	mana          float64
 }
 
 func (ogm *opiniongivermock) ID() identity.ID {
 	return identity.GenerateIdentity().ID()
 }
 
+func (ogm *opiniongivermock) Mana() float64 {
+	return ogm.mana
+}
+
 func (ogm *opiniongivermock) Query(_ context.Context, _ []string, _ []string) (opinion.Opinions, error) {
 	if ogm.roundIndex >= len(ogm.roundsReplies) {
 		return ogm.roundsReplies[len(ogm.roundsReplies)-1], nil
@@ -189,3 +195,110 @@ func TestFPCVotingMultipleOpinionGivers(t *testing.T) {
 		assert.Equal(t, test.expectedOpinion, *finalOpinion)
 	}
 }
+
+func TestManaBasedSampling(t *testing.T) {
+	heavy := &opiniongivermock{mana: 90}
+	light := &opiniongivermock{mana: 10}
+	zero := &opiniongivermock{mana: 0}
+	opinionGivers := []opinion.OpinionGiver{heavy, light, zero}
+	weight := func(opinionGiver opinion.OpinionGiver) float64 {
+		return opinionGiver.Mana()
+	}
+
+	const sampleSize = 10000
+	selected := fpc.ManaBasedSampling(opinionGivers, sampleSize, weight, rand.New(rand.NewSource(0)))
+	assert.Equal(t, sampleSize, selected[heavy]+selected[light]+selected[zero])
+	assert.InDelta(t, 0.9*sampleSize, selected[heavy], 0.02*sampleSize)
+	assert.InDelta(t, 0.1*sampleSize, selected[light], 0.02*sampleSize)
+	assert.Zero(t, selected[zero])
+
+	// without any weight the opinion givers are sampled uniformly
+	noWeight := func(opinion.OpinionGiver) float64 { return 0 }
+	selected = fpc.ManaBasedSampling(opinionGivers, sampleSize, noWeight, rand.New(rand.NewSource(0)))
+	for _, opinionGiver := range opinionGivers {
+		assert.InDelta(t, sampleSize/3, selected[opinionGiver], 0.03*sampleSize)
+	}
+}
+
+func TestFPCManaWeightedVoting(t *testing.T) {
+	type testInput struct {
+		name            string
+		honestMana      float64
+		adversaryMana   float64
+		expectedOpinion opinion.Opinion
+	}
+	var tests = []testInput{
+		// few honest nodes holding most of the mana outweigh many sybil identities
+		{"sybil minority mana", 100, 1, opinion.Like},
+		// an adversary holding most of the mana dominates the vote
+		{"adversary majority mana", 1, 100, opinion.Dislike},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opinionGiverFunc := func() (givers []opinion.OpinionGiver, err error) {
+				// 2 honest opinion givers that like and 20 adversarial ones that dislike
+				for i := 0; i < 2; i++ {
+					givers = append(givers, &opiniongivermock{roundsReplies: []opinion.Opinions{{opinion.Like}}, mana: test.honestMana})
+				}
+				for i := 0; i < 20; i++ {
+					givers = append(givers, &opiniongivermock{roundsReplies: []opinion.Opinions{{opinion.Dislike}}, mana: test.adversaryMana})
+				}
+				return givers, nil
+			}
+
+			paras := fpc.DefaultParameters()
+			paras.FinalizationThreshold = 2
+			paras.CoolingOffPeriod = 2
+			voter := fpc.New(opinionGiverFunc, paras)
+			var finalOpinion *opinion.Opinion
+			voter.Events().Finalized.Attach(events.NewClosure(func(ev *vote.OpinionEvent) {
+				finalOpinion = &ev.Opinion
+			}))
+
+			assert.NoError(t, voter.Vote("a", vote.ConflictType, opinion.Like))
+			for i := 0; finalOpinion == nil && i < paras.MaxRoundsPerVoteContext; i++ {
+				assert.NoError(t, voter.Round(0.5))
+			}
+
+			require.NotNil(t, finalOpinion)
+			assert.Equal(t, test.expectedOpinion, *finalOpinion)
+		})
+	}
+}
+
+func TestFPCEta(t *testing.T) {
+	// the opinion givers are sampled proportionally to their mana, so a 3:1 mana split has to result in an eta of 0.75
+	// (weighting the sampled opinions by mana again would result in 9:1)
+	liker := &opiniongivermock{roundsReplies: []opinion.Opinions{{opinion.Like}}, mana: 3}
+	disliker := &opiniongivermock{roundsReplies: []opinion.Opinions{{opinion.Dislike}}, mana: 1}
+	opinionGiverFunc := func() (givers []opinion.OpinionGiver, err error) {
+		return []opinion.OpinionGiver{liker, disliker}, nil
+	}
+
+	paras := fpc.DefaultParameters()
+	paras.QuerySampleSize = 10000
+	voter := fpc.New(opinionGiverFunc, paras)
+	var roundStats *vote.RoundStats
+	voter.Events().RoundExecuted.Attach(events.NewClosure(func(stats *vote.RoundStats) {
+		roundStats = stats
+	}))
+
+	assert.NoError(t, voter.Vote("a", vote.ConflictType, opinion.Like))
+	assert.NoError(t, voter.Round(0.5))
+	require.NotNil(t, roundStats)
+
+	// every selection of an opinion giver counts exactly once
+	likedCount, votedCount := 0, 0
+	for _, queriedOpinions := range roundStats.QueriedOpinions {
+		if queriedOpinions.Opinions["a"] == opinion.Like {
+			likedCount += queriedOpinions.TimesCounted
+		}
+		votedCount += queriedOpinions.TimesCounted
+	}
+	assert.Equal(t, paras.QuerySampleSize, votedCount)
+
+	eta := roundStats.ActiveVoteContexts["a"].Liked
+	assert.Equal(t, float64(likedCount)/float64(votedCount), eta)
+	assert.InDelta(t, 0.75, eta, 0.02)
+}
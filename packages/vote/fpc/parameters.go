@@ -22,6 +22,8 @@ type Parameters struct {
 	MaxRoundsPerVoteContext int
 	// The max amount of time a query is allowed to take.
 	QueryTimeout time.Duration
+	// The sampling weight that is used for opinion givers whose consensus mana is unknown (or zero).
+	UnknownManaWeight float64
 }
 
 // DefaultParameters returns the default parameters used in FPC.
@@ -36,6 +38,7 @@ func DefaultParameters() *Parameters {
 		CoolingOffPeriod:                    0,
 		MaxRoundsPerVoteContext:             100,
 		QueryTimeout:                        6500 * time.Millisecond,
+		UnknownManaWeight:                   1,
 	}
 }
 
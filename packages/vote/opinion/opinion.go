@@ -13,6 +13,8 @@ type OpinionGiver interface {
 	Query(ctx context.Context, conflictIDs []string, timestampIDs []string) (Opinions, error)
 	// ID returns the ID of the opinion giver.
 	ID() identity.ID
+	// Mana returns the consensus mana of the opinion giver (or 0 if it is unknown).
+	Mana() float64
 }
 
 // QueriedOpinions represents queried opinions from a given opinion giver.
@@ -25,6 +27,8 @@ type QueriedOpinions struct {
 	// Usually this number is 1 but due to randomization of the queried opinion givers,
 	// the same opinion giver's opinions might be taken into account multiple times.
 	TimesCounted int `json:"times_counted"`
+	// The weight (consensus mana) with which the opinion giver was sampled.
+	Mana float64 `json:"mana"`
 }
 
 // OpinionGiverFunc is a function which gives a slice of OpinionGivers or an error.
@@ -0,0 +1,93 @@
+package assetregistry
+
+import (
+	"sync"
+
+	"github.com/iotaledger/goshimmer/packages/assetregistry"
+	databasePkg "github.com/iotaledger/goshimmer/packages/database"
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/goshimmer/packages/tangle"
+	"github.com/iotaledger/goshimmer/plugins/database"
+	"github.com/iotaledger/goshimmer/plugins/messagelayer"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/identity"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/hive.go/node"
+	"golang.org/x/xerrors"
+)
+
+// PluginName is the name of the asset registry plugin.
+const PluginName = "AssetRegistry"
+
+var (
+	// plugin is the plugin instance of the asset registry plugin.
+	plugin       *node.Plugin
+	pluginOnce   sync.Once
+	registry     *assetregistry.Registry
+	registryOnce sync.Once
+	log          *logger.Logger
+)
+
+// Plugin gets the plugin instance.
+func Plugin() *node.Plugin {
+	pluginOnce.Do(func() {
+		plugin = node.NewPlugin(PluginName, node.Enabled, configure)
+	})
+	return plugin
+}
+
+// Registry returns the Registry that indexes the AssetMetadata published in the Tangle.
+func Registry() *assetregistry.Registry {
+	registryOnce.Do(func() {
+		registry = assetregistry.New(database.StoreRealm([]byte{databasePkg.PrefixAssetRegistry}), retrieveTransaction, retrieveOutput)
+	})
+	return registry
+}
+
+func configure(*node.Plugin) {
+	log = logger.NewLogger(PluginName)
+
+	messagelayer.Tangle().Booker.Events.MessageBooked.Attach(events.NewClosure(func(messageID tangle.MessageID) {
+		messagelayer.Tangle().Storage.Message(messageID).Consume(func(message *tangle.Message) {
+			if message.Payload().Type() != assetregistry.Type {
+				return
+			}
+
+			assetMetadata := message.Payload().(*assetregistry.AssetMetadata)
+			if err := Registry().Register(assetMetadata, identity.NewID(message.IssuerPublicKey())); err != nil {
+				if xerrors.Is(err, assetregistry.ErrMintingTransactionUnknown) {
+					log.Debugf("postponed AssetMetadata of %s: %s", assetMetadata.Color(), err)
+					return
+				}
+
+				log.Debugf("dropped AssetMetadata of %s: %s", assetMetadata.Color(), err)
+				return
+			}
+			log.Infof("registered AssetMetadata of %s", assetMetadata.Color())
+		})
+	}))
+
+	messagelayer.Tangle().LedgerState.UTXODAG.Events.TransactionBooked.Attach(events.NewClosure(func(transactionID ledgerstate.TransactionID) {
+		if err := Registry().ProcessBookedTransaction(transactionID); err != nil {
+			log.Debugf("dropped AssetMetadata of Transaction with %s: %s", transactionID, err)
+		}
+	}))
+}
+
+// retrieveTransaction loads the Transaction with the given TransactionID from the ledger.
+func retrieveTransaction(transactionID ledgerstate.TransactionID) (transaction *ledgerstate.Transaction) {
+	messagelayer.Tangle().LedgerState.Transaction(transactionID).Consume(func(tx *ledgerstate.Transaction) {
+		transaction = tx
+	})
+
+	return
+}
+
+// retrieveOutput loads the Output with the given OutputID from the ledger.
+func retrieveOutput(outputID ledgerstate.OutputID) (output ledgerstate.Output) {
+	messagelayer.Tangle().LedgerState.Output(outputID).Consume(func(o ledgerstate.Output) {
+		output = o
+	})
+
+	return
+}
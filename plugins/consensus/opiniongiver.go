@@ -12,6 +12,7 @@ import (
 	"github.com/iotaledger/goshimmer/packages/vote/opinion"
 	"github.com/iotaledger/goshimmer/packages/vote/statement"
 	"github.com/iotaledger/goshimmer/plugins/autopeering"
+	"github.com/iotaledger/goshimmer/plugins/messagelayer"
 	"github.com/iotaledger/hive.go/autopeering/peer"
 	"github.com/iotaledger/hive.go/autopeering/peer/service"
 	"github.com/iotaledger/hive.go/identity"
@@ -26,6 +27,7 @@ type OpinionGiver struct {
 	id   identity.ID
 	view *statement.View
 	pog  *PeerOpinionGiver
+	mana float64
 }
 
 // OpinionGivers is a map of OpinionGiver.
@@ -51,6 +53,11 @@ func (o *OpinionGiver) ID() identity.ID {
 	return o.id
 }
 
+// Mana returns the consensus mana of the underlying Peer.
+func (o *OpinionGiver) Mana() float64 {
+	return o.mana
+}
+
 // OpinionGiverFunc returns a slice of opinion givers.
 func OpinionGiverFunc() (givers []opinion.OpinionGiver, err error) {
 	opinionGiversMap := make(map[identity.ID]*OpinionGiver)
@@ -78,6 +85,10 @@ func OpinionGiverFunc() (givers []opinion.OpinionGiver, err error) {
 	}
 
 	for _, v := range opinionGiversMap {
+		// opinion givers with unknown mana are weighted with the configured fallback by FPC
+		if consensusMana, _, err := messagelayer.GetConsensusMana(v.id); err == nil {
+			v.mana = consensusMana
+		}
 		opinionGivers = append(opinionGivers, v)
 	}
 
@@ -32,6 +32,9 @@ const (
 	// CfgFPCQuerySampleSize defines how many nodes will be queried each round.
 	CfgFPCQuerySampleSize = "fpc.querySampleSize"
 
+	// CfgFPCUnknownManaWeight defines the weight of opinion givers whose consensus mana is unknown.
+	CfgFPCUnknownManaWeight = "fpc.unknownManaWeight"
+
 	// CfgFPCRoundInterval defines how long a round lasts (in seconds)
 	CfgFPCRoundInterval = "fpc.roundInterval"
 
@@ -60,6 +63,7 @@ func init() {
 	flag.Bool(CfgFPCListen, true, "if the FPC service should listen")
 	flag.Bool(CfgWriteStatement, false, "if the node should make statements")
 	flag.Int(CfgFPCQuerySampleSize, 21, "Size of the voting quorum (k)")
+	flag.Float64(CfgFPCUnknownManaWeight, 1., "the weight of opinion givers whose consensus mana is unknown")
 	flag.Int64(CfgFPCRoundInterval, 10, "FPC round interval [s]")
 	flag.String(CfgFPCBindAddress, "0.0.0.0:10895", "the bind address on which the FPC vote server binds to")
 	flag.Int(CfgWaitForStatement, 5, "the time in seconds for which the node wait for receiveing the new statement")
@@ -129,7 +133,10 @@ func run(_ *node.Plugin) {
 // Voter returns the DRNGRoundBasedVoter instance used by the FPC plugin.
 func Voter() vote.DRNGRoundBasedVoter {
 	voterOnce.Do(func() {
-		voter = fpc.New(OpinionGiverFunc)
+		paras := fpc.DefaultParameters()
+		paras.QuerySampleSize = config.Node().Int(CfgFPCQuerySampleSize)
+		paras.UnknownManaWeight = config.Node().Float64(CfgFPCUnknownManaWeight)
+		voter = fpc.New(OpinionGiverFunc, paras)
 	})
 	return voter
 }
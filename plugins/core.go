@@ -1,6 +1,7 @@
 package plugins
 
 import (
+	"github.com/iotaledger/goshimmer/plugins/assetregistry"
 	"github.com/iotaledger/goshimmer/plugins/autopeering"
 	"github.com/iotaledger/goshimmer/plugins/banner"
 	"github.com/iotaledger/goshimmer/plugins/cli"
@@ -14,6 +15,7 @@ import (
 	"github.com/iotaledger/goshimmer/plugins/gracefulshutdown"
 	"github.com/iotaledger/goshimmer/plugins/issuer"
 	"github.com/iotaledger/goshimmer/plugins/logger"
+	"github.com/iotaledger/goshimmer/plugins/manualpeering"
 	"github.com/iotaledger/goshimmer/plugins/messagelayer"
 	"github.com/iotaledger/goshimmer/plugins/metrics"
 	"github.com/iotaledger/goshimmer/plugins/portcheck"
@@ -39,12 +41,15 @@ var Core = node.Plugins(
 	pow.Plugin(),
 	clock.Plugin(),
 	messagelayer.Plugin(),
+	messagelayer.ManaPlugin(),
 	gossip.Plugin(),
+	manualpeering.Plugin(),
 	issuer.Plugin(),
 	syncbeacon.Plugin(),
 	syncbeaconfollower.Plugin(),
 	drng.Plugin(),
 	faucet.Plugin(),
+	assetregistry.Plugin(),
 	consensus.Plugin(),
 	metrics.Plugin(),
 	spammer.Plugin(),
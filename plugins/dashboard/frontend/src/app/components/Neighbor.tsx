@@ -77,6 +77,11 @@ export class Neighbor extends React.Component<Props, any> {
                                             {' '}
                                             {last.connection_origin}
                                         </ListGroup.Item>
+                                        <ListGroup.Item>
+                                            Group:
+                                            {' '}
+                                            {last.group}
+                                        </ListGroup.Item>
                                     </ListGroup>
                                 </Col>
                                 <Col>
@@ -118,6 +118,7 @@ class NeighborMetric {
     id: string;
     address: string;
     connection_origin: number;
+    group: string;
     bytes_read: number;
     bytes_written: number;
     ts: number;
@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	gossipPkg "github.com/iotaledger/goshimmer/packages/gossip"
 	"github.com/iotaledger/goshimmer/packages/shutdown"
 	"github.com/iotaledger/goshimmer/plugins/autopeering"
 	"github.com/iotaledger/goshimmer/plugins/autopeering/local"
@@ -199,8 +200,12 @@ type neighbormetric struct {
 	ID               string `json:"id"`
 	Address          string `json:"address"`
 	ConnectionOrigin string `json:"connection_origin"`
+	Group            string `json:"group"`
 	BytesRead        uint64 `json:"bytes_read"`
 	BytesWritten     uint64 `json:"bytes_written"`
+	PacketsRead      uint64 `json:"packets_read"`
+	PacketsWritten   uint64 `json:"packets_written"`
+	Reputation       int    `json:"reputation"`
 }
 
 func neighborMetrics() []neighbormetric {
@@ -215,10 +220,16 @@ func neighborMetrics() []neighbormetric {
 	for _, neighbor := range neighbors {
 		// unfortunately the neighbor manager doesn't keep track of the origin of the connection
 		origin := "Inbound"
-		for _, peer := range autopeering.Selection().GetOutgoingNeighbors() {
-			if neighbor.Peer == peer {
+		if neighbor.Group() == gossipPkg.NeighborsGroupManual {
+			if neighbor.IsOutbound() {
 				origin = "Outbound"
-				break
+			}
+		} else {
+			for _, peer := range autopeering.Selection().GetOutgoingNeighbors() {
+				if neighbor.Peer == peer {
+					origin = "Outbound"
+					break
+				}
 			}
 		}
 
@@ -229,7 +240,11 @@ func neighborMetrics() []neighbormetric {
 			Address:          net.JoinHostPort(host, strconv.Itoa(port)),
 			BytesRead:        neighbor.BytesRead(),
 			BytesWritten:     neighbor.BytesWritten(),
+			PacketsRead:      neighbor.PacketsRead(),
+			PacketsWritten:   neighbor.PacketsWritten(),
+			Reputation:       neighbor.Reputation(),
 			ConnectionOrigin: origin,
+			Group:            neighbor.Group().String(),
 		})
 	}
 	return stats
@@ -141,6 +141,11 @@ func (c *Component) collectUTXOsForFunding() (inputs ledgerstate.Inputs, addrsIn
 		addr := c.seed.Address(i).Address()
 		cachedOutputs := messagelayer.Tangle().LedgerState.OutputsOnAddress(addr)
 		cachedOutputs.Consume(func(output ledgerstate.Output) {
+			// the faucet only spends outputs that are unlocked by a plain signature of its address
+			if output.Type() == ledgerstate.ExtendedLockedOutputType {
+				return
+			}
+
 			messagelayer.Tangle().LedgerState.OutputMetadata(output.ID()).Consume(func(outputMetadata *ledgerstate.OutputMetadata) {
 				if outputMetadata.ConsumerCount() > 0 || total == 0 {
 					return
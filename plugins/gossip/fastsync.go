@@ -0,0 +1,193 @@
+package gossip
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/gossip"
+	"github.com/iotaledger/goshimmer/packages/tangle"
+	"github.com/iotaledger/goshimmer/plugins/config"
+	"github.com/iotaledger/goshimmer/plugins/messagelayer"
+	"github.com/iotaledger/hive.go/typeutils"
+	"golang.org/x/xerrors"
+)
+
+const (
+	fastSyncName = "FastSync"
+
+	// fastSyncRetryInterval defines the time to wait before the neighbors are asked for their snapshot again.
+	fastSyncRetryInterval = 10 * time.Second
+)
+
+var (
+	// fastSyncPending is set while the node waits to be bootstrapped from the snapshot of a neighbor.
+	fastSyncPending typeutils.AtomicBool
+
+	// trustedSnapshotChecksum contains the configured checksum of the snapshot that is trusted for the fast sync.
+	trustedSnapshotChecksum []byte
+)
+
+// loads the given range of the latest local snapshot, so that it can be served to neighbors.
+func loadSnapshotChunk(offset int64, length int) (data []byte, size int64, err error) {
+	file, err := os.Open(config.Node().String(messagelayer.CfgMessageLayerLocalSnapshotFile))
+	if err != nil {
+		return nil, 0, xerrors.Errorf("failed to open local snapshot: %w", err)
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return nil, 0, xerrors.Errorf("failed to read size of local snapshot: %w", err)
+	}
+
+	data = make([]byte, length)
+	n, err := file.ReadAt(data, offset)
+	if err != nil && err != io.EOF {
+		return nil, 0, xerrors.Errorf("failed to read local snapshot: %w", err)
+	}
+
+	return data[:n], fileInfo.Size(), nil
+}
+
+// configureFastSync parses the trusted snapshot checksum and marks the fast sync as pending, so that no gossip is
+// processed before the node was bootstrapped from a snapshot.
+func configureFastSync() {
+	if !config.Node().Bool(CfgGossipFastSync) {
+		return
+	}
+	if snapshotFile := config.Node().String(messagelayer.CfgMessageLayerSnapshotFile); snapshotFile != "" {
+		log.Fatalf("%s cannot be combined with %s: %s", CfgGossipFastSync, messagelayer.CfgMessageLayerSnapshotFile, snapshotFile)
+	}
+	// only a fresh node (i.e. one without any messages and solid entry points) needs to be bootstrapped
+	if messagelayer.Tangle().Storage.HasMessages() || len(messagelayer.Tangle().Storage.SolidEntryPoints()) != 0 {
+		log.Info("Skipping fast sync: the node is not fresh")
+		return
+	}
+
+	if checksum := config.Node().String(CfgGossipFastSyncSnapshotChecksum); checksum != "" {
+		var err error
+		if trustedSnapshotChecksum, err = hex.DecodeString(checksum); err != nil || len(trustedSnapshotChecksum) != tangle.SnapshotChecksumLength {
+			log.Fatalf("Invalid %s: %s", CfgGossipFastSyncSnapshotChecksum, checksum)
+		}
+	} else if config.Node().Int(CfgGossipFastSyncQuorum) < 1 {
+		log.Fatalf("Invalid %s: %d", CfgGossipFastSyncQuorum, config.Node().Int(CfgGossipFastSyncQuorum))
+	}
+
+	fastSyncPending.Set()
+}
+
+// runFastSync downloads the latest snapshot of one of the neighbors and loads it, so that a fresh node starts
+// solidifying from the solid entry points of the snapshot instead of the genesis.
+func runFastSync(shutdownSignal <-chan struct{}) {
+	if !fastSyncPending.IsSet() {
+		return
+	}
+
+	ticker := time.NewTicker(fastSyncRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		checksum, sources := snapshotSources()
+		for _, neighbor := range sources {
+			if err := fastSyncFrom(neighbor, checksum); err != nil {
+				log.Warnf("Failed to fast sync from %s: %s", neighbor.ID(), err)
+				continue
+			}
+
+			log.Infof("Fast synced from %s", neighbor.ID())
+			fastSyncPending.UnSet()
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-shutdownSignal:
+			return
+		}
+	}
+}
+
+// snapshotSources returns the checksum of the snapshot that is trusted together with the neighbors that can serve it.
+// If no checksum was configured, the snapshot that is served by the most neighbors is trusted, as long as they reach
+// the configured quorum.
+func snapshotSources() (checksum []byte, sources []*gossip.Neighbor) {
+	if trustedSnapshotChecksum != nil {
+		return trustedSnapshotChecksum, Manager().AllNeighbors()
+	}
+
+	neighborsByChecksum := make(map[string][]*gossip.Neighbor)
+	for _, neighbor := range Manager().AllNeighbors() {
+		tail, err := Manager().RequestSnapshotTail(neighbor.ID(), tangle.SnapshotChecksumLength)
+		if err != nil {
+			log.Debugf("Failed to request snapshot checksum from %s: %s", neighbor.ID(), err)
+			continue
+		}
+
+		neighborsByChecksum[string(tail)] = append(neighborsByChecksum[string(tail)], neighbor)
+	}
+
+	for neighborChecksum, neighbors := range neighborsByChecksum {
+		if len(neighbors) > len(sources) {
+			checksum, sources = []byte(neighborChecksum), neighbors
+		}
+	}
+	if quorum := config.Node().Int(CfgGossipFastSyncQuorum); len(sources) < quorum {
+		log.Infof("Waiting for fast sync: %d of the required %d neighbors serve the same snapshot", len(sources), quorum)
+		return nil, nil
+	}
+
+	return checksum, sources
+}
+
+// fastSyncFrom downloads the snapshot of the given neighbor, verifies it against the trusted checksum and loads it into
+// the Tangle. The downloaded snapshot is kept as the local snapshot of the node afterwards, so that it can be served to
+// other nodes.
+func fastSyncFrom(neighbor *gossip.Neighbor, checksum []byte) (err error) {
+	snapshotFilePath := config.Node().String(messagelayer.CfgMessageLayerLocalSnapshotFile)
+	tmpFile, err := ioutil.TempFile(filepath.Dir(snapshotFilePath), filepath.Base(snapshotFilePath)+".download")
+	if err != nil {
+		return xerrors.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		_ = tmpFile.Close()
+		if err != nil {
+			_ = os.Remove(tmpFile.Name())
+		}
+	}()
+
+	if err = Manager().RequestSnapshot(tmpFile, neighbor.ID()); err != nil {
+		return xerrors.Errorf("failed to download snapshot: %w", err)
+	}
+
+	// the snapshot has to end with the trusted checksum and its content has to match the checksum
+	fileInfo, err := tmpFile.Stat()
+	if err != nil {
+		return xerrors.Errorf("failed to read size of snapshot: %w", err)
+	}
+	if fileInfo.Size() < tangle.SnapshotChecksumLength {
+		return xerrors.Errorf("snapshot is too small: %w", tangle.ErrInvalidSnapshot)
+	}
+	downloadedChecksum := make([]byte, tangle.SnapshotChecksumLength)
+	if _, err = tmpFile.ReadAt(downloadedChecksum, fileInfo.Size()-tangle.SnapshotChecksumLength); err != nil {
+		return xerrors.Errorf("failed to read snapshot checksum: %w", err)
+	}
+	if !bytes.Equal(downloadedChecksum, checksum) {
+		return xerrors.Errorf("snapshot does not match the trusted checksum: %w", tangle.ErrSnapshotChecksumMismatch)
+	}
+
+	// the snapshot is verified completely before anything is written to the ledger state
+	if err = messagelayer.LoadSnapshot(tmpFile); err != nil {
+		return xerrors.Errorf("failed to load snapshot: %w", err)
+	}
+
+	if err = os.Rename(tmpFile.Name(), snapshotFilePath); err != nil {
+		log.Warnf("Failed to keep downloaded snapshot as local snapshot: %s", err)
+	}
+
+	return nil
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/iotaledger/hive.go/autopeering/peer/service"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/hive.go/netutil"
+	"github.com/iotaledger/hive.go/node"
 	"github.com/iotaledger/hive.go/types"
 )
 
@@ -49,7 +50,13 @@ func createManager() {
 	if err := lPeer.UpdateService(service.GossipKey, "tcp", gossipPort); err != nil {
 		log.Fatalf("could not update services: %s", err)
 	}
-	mgr = gossip.NewManager(lPeer, loadMessage, log)
+	mgr = gossip.NewManager(lPeer, loadMessage, log,
+		gossip.LoadSnapshotChunk(loadSnapshotChunk),
+		gossip.MessageExists(messageExists),
+		gossip.BandwidthLimits(config.Node().Int(CfgGossipInboundBandwidth), config.Node().Int(CfgGossipOutboundBandwidth)),
+		gossip.RequestRateLimit(config.Node().Int(CfgGossipRequestRateLimit)),
+		gossip.BanDuration(config.Node().Duration(CfgGossipBanDuration)),
+	)
 }
 
 func start(shutdownSignal <-chan struct{}) {
@@ -73,22 +80,32 @@ func start(shutdownSignal <-chan struct{}) {
 	}
 	defer listener.Close()
 
-	srv := server.ServeTCP(lPeer, listener, log)
+	encryption, err := server.ParseEncryptionPolicy(config.Node().String(CfgGossipEncryption))
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", CfgGossipEncryption, err)
+	}
+
+	srv := server.ServeTCP(lPeer, listener, log, server.Encryption(encryption))
 	defer srv.Close()
 
 	mgr.Start(srv)
 	defer mgr.Close()
 
 	// trigger start of the autopeering selection
-	go func() { autopeering.StartSelection() }()
+	autopeeringEnabled := !node.IsSkipped(autopeering.Plugin())
+	if autopeeringEnabled {
+		go func() { autopeering.StartSelection() }()
+	}
 
-	log.Infof("%s started: age-threshold=%v bind-address=%s", PluginName, ageThreshold, localAddr.String())
+	log.Infof("%s started: age-threshold=%v bind-address=%s encryption=%s", PluginName, ageThreshold, localAddr.String(), encryption)
 
 	<-shutdownSignal
 	log.Info("Stopping " + PluginName + " ...")
 
 	// assure that the autopeering selection is always stopped before the gossip manager
-	autopeering.Selection().Close()
+	if autopeeringEnabled {
+		autopeering.Selection().Close()
+	}
 }
 
 // loads the given message from the message layer and returns it or an error if not found.
@@ -102,6 +119,13 @@ func loadMessage(msgID tangle.MessageID) ([]byte, error) {
 	return msg.Bytes(), nil
 }
 
+// messageExists returns whether the message with the given id is already stored in the tangle.
+func messageExists(msgID tangle.MessageID) bool {
+	cachedMessage := messagelayer.Tangle().Storage.Message(msgID)
+	defer cachedMessage.Release()
+	return cachedMessage.Exists()
+}
+
 // requestedMessages represents a list of requested messages that will not be gossiped.
 type requestedMessages struct {
 	sync.Mutex
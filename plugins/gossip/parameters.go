@@ -3,6 +3,7 @@ package gossip
 import (
 	"time"
 
+	"github.com/iotaledger/goshimmer/packages/gossip/server"
 	flag "github.com/spf13/pflag"
 )
 
@@ -13,10 +14,34 @@ const (
 	CfgGossipAgeThreshold = "gossip.ageThreshold"
 	// CfgGossipTipsBroadcastInterval the interval in which the oldest known tip is re-broadcast.
 	CfgGossipTipsBroadcastInterval = "gossip.tipsBroadcaster.interval"
+	// CfgGossipFastSync defines whether a fresh node bootstraps from the snapshot of one of its neighbors.
+	CfgGossipFastSync = "gossip.fastSync"
+	// CfgGossipFastSyncSnapshotChecksum defines the hex encoded checksum of the snapshot that is trusted for the fast sync.
+	CfgGossipFastSyncSnapshotChecksum = "gossip.fastSync.snapshotChecksum"
+	// CfgGossipFastSyncQuorum defines how many neighbors need to serve the same snapshot, if no checksum is configured.
+	CfgGossipFastSyncQuorum = "gossip.fastSync.quorum"
+	// CfgGossipInboundBandwidth defines the maximum number of bytes per second that are received from a neighbor.
+	CfgGossipInboundBandwidth = "gossip.bandwidth.inbound"
+	// CfgGossipOutboundBandwidth defines the maximum number of bytes per second that are sent to a neighbor.
+	CfgGossipOutboundBandwidth = "gossip.bandwidth.outbound"
+	// CfgGossipRequestRateLimit defines the maximum number of messages per second a neighbor is allowed to request.
+	CfgGossipRequestRateLimit = "gossip.requestRateLimit"
+	// CfgGossipBanDuration defines how long a neighbor is banned after its reputation dropped below the threshold.
+	CfgGossipBanDuration = "gossip.banDuration"
+	// CfgGossipEncryption defines whether the connections to the neighbors are encrypted (disabled, preferred, required).
+	CfgGossipEncryption = "gossip.encryption"
 )
 
 func init() {
 	flag.Int(CfgGossipPort, 14666, "tcp port for gossip connection")
 	flag.Duration(CfgGossipAgeThreshold, 5*time.Second, "message age threshold for gossip")
 	flag.Duration(CfgGossipTipsBroadcastInterval, 10*time.Second, "the interval in which the oldest known tip is re-broadcast")
+	flag.Int(CfgGossipInboundBandwidth, 0, "the maximum number of bytes per second received from a neighbor (0 = unlimited)")
+	flag.Int(CfgGossipOutboundBandwidth, 0, "the maximum number of bytes per second sent to a neighbor (0 = unlimited)")
+	flag.Int(CfgGossipRequestRateLimit, 1000, "the maximum number of messages per second a neighbor is allowed to request (0 = unlimited)")
+	flag.Duration(CfgGossipBanDuration, 10*time.Minute, "how long a misbehaving neighbor is banned")
+	flag.String(CfgGossipEncryption, server.EncryptionPreferred.String(), "whether the connections to the neighbors are encrypted (disabled, preferred or required)")
+	flag.Bool(CfgGossipFastSync, false, "bootstrap a fresh node from the snapshot of one of its neighbors (requires an empty messageLayer.snapshot.file)")
+	flag.String(CfgGossipFastSyncSnapshotChecksum, "", "the hex encoded checksum of the trusted snapshot (empty = agreement of gossip.fastSync.quorum neighbors)")
+	flag.Int(CfgGossipFastSyncQuorum, 3, "the number of neighbors that need to serve the same snapshot if no checksum is configured")
 }
@@ -15,8 +15,10 @@ import (
 	"github.com/iotaledger/hive.go/autopeering/selection"
 	"github.com/iotaledger/hive.go/daemon"
 	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/identity"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/hive.go/node"
+	"golang.org/x/xerrors"
 )
 
 // PluginName is the name of the gossip plugin.
@@ -50,7 +52,12 @@ func configure(*node.Plugin) {
 
 	configureLogging()
 	configureMessageLayer()
-	configureAutopeering()
+	configureFastSync()
+
+	// without autopeering, only the manually configured neighbors are used
+	if !node.IsSkipped(autopeering.Plugin()) {
+		configureAutopeering()
+	}
 }
 
 func run(*node.Plugin) {
@@ -60,6 +67,11 @@ func run(*node.Plugin) {
 	if err := daemon.BackgroundWorker(tipsBroadcasterName, startTipBroadcaster, shutdown.PriorityGossip); err != nil {
 		log.Panicf("Failed to start as daemon: %s", err)
 	}
+	if fastSyncPending.IsSet() {
+		if err := daemon.BackgroundWorker(fastSyncName, runFastSync, shutdown.PriorityGossip); err != nil {
+			log.Panicf("Failed to start as daemon: %s", err)
+		}
+	}
 }
 
 func configureAutopeering() {
@@ -70,7 +82,7 @@ func configureAutopeering() {
 	peerSel := autopeering.Selection()
 	peerSel.Events().Dropped.Attach(events.NewClosure(func(ev *selection.DroppedEvent) {
 		go func() {
-			if err := mgr.DropNeighbor(ev.DroppedID); err != nil {
+			if err := mgr.DropNeighbor(ev.DroppedID, gossip.NeighborsGroupAuto); err != nil {
 				log.Debugw("error dropping neighbor", "id", ev.DroppedID, "err", err)
 			}
 		}()
@@ -80,7 +92,7 @@ func configureAutopeering() {
 			return // ignore rejected peering
 		}
 		go func() {
-			if err := mgr.AddInbound(ev.Peer); err != nil {
+			if err := mgr.AddInbound(ev.Peer, gossip.NeighborsGroupAuto); err != nil {
 				log.Debugw("error adding inbound", "id", ev.Peer.ID(), "err", err)
 			}
 		}()
@@ -90,7 +102,7 @@ func configureAutopeering() {
 			return // ignore rejected peering
 		}
 		go func() {
-			if err := mgr.AddOutbound(ev.Peer); err != nil {
+			if err := mgr.AddOutbound(ev.Peer, gossip.NeighborsGroupAuto); err != nil {
 				log.Debugw("error adding outbound", "id", ev.Peer.ID(), "err", err)
 			}
 		}()
@@ -101,7 +113,9 @@ func configureAutopeering() {
 		peerSel.RemoveNeighbor(p.ID())
 	}))
 	mgr.Events().NeighborRemoved.Attach(events.NewClosure(func(n *gossip.Neighbor) {
-		peerSel.RemoveNeighbor(n.ID())
+		if n.Group() == gossip.NeighborsGroupAuto {
+			peerSel.RemoveNeighbor(n.ID())
+		}
 	}))
 }
 
@@ -114,10 +128,10 @@ func configureLogging() {
 		log.Infof("Connection to neighbor %s / %s failed: %s", gossip.GetAddress(p), p.ID(), err)
 	}))
 	mgr.Events().NeighborAdded.Attach(events.NewClosure(func(n *gossip.Neighbor) {
-		log.Infof("Neighbor added: %s / %s", gossip.GetAddress(n.Peer), n.ID())
+		log.Infof("Neighbor added: %s / %s (%s)", gossip.GetAddress(n.Peer), n.ID(), n.Group())
 	}))
 	mgr.Events().NeighborRemoved.Attach(events.NewClosure(func(n *gossip.Neighbor) {
-		log.Infof("Neighbor removed: %s / %s", gossip.GetAddress(n.Peer), n.ID())
+		log.Infof("Neighbor removed: %s / %s (%s)", gossip.GetAddress(n.Peer), n.ID(), n.Group())
 	}))
 }
 
@@ -127,6 +141,11 @@ func configureMessageLayer() {
 
 	// configure flow of incoming messages
 	mgr.Events().MessageReceived.Attach(events.NewClosure(func(event *gossip.MessageReceivedEvent) {
+		// the node does not solidify from the genesis while it waits for the snapshot of a neighbor
+		if fastSyncPending.IsSet() {
+			return
+		}
+
 		messagelayer.Tangle().ProcessGossipMessage(event.Data, event.Peer)
 	}))
 
@@ -148,9 +167,23 @@ func configureMessageLayer() {
 		})
 	}))
 
+	// decrease the reputation of neighbors that send invalid messages
+	messagelayer.Tangle().Parser.Events.BytesRejected.Attach(events.NewClosure(func(event *tangle.BytesRejectedEvent, err error) {
+		reportInvalidMessage(mgr, event.Peer, err)
+	}))
+	messagelayer.Tangle().Parser.Events.MessageRejected.Attach(events.NewClosure(func(event *tangle.MessageRejectedEvent, err error) {
+		reportInvalidMessage(mgr, event.Peer, err)
+	}))
+
 	// request missing messages
+	messagelayer.Tangle().Requester.SetNeighborsRetriever(func() (neighborIDs []identity.ID) {
+		for _, neighbor := range mgr.AllNeighbors() {
+			neighborIDs = append(neighborIDs, neighbor.ID())
+		}
+		return neighborIDs
+	})
 	messagelayer.Tangle().Requester.Events.SendRequest.Attach(events.NewClosure(func(sendRequest *tangle.SendRequestEvent) {
-		mgr.RequestMessage(sendRequest.ID[:])
+		requestMessages(mgr, sendRequest)
 	}))
 
 	messagelayer.Tangle().Storage.Events.MissingMessageStored.Attach(events.NewClosure(requestedMsgs.append))
@@ -158,3 +191,37 @@ func configureMessageLayer() {
 	// delete the message from requestedMsgs if it's invalid, otherwise it will always be in the list and never get removed in some cases.
 	messagelayer.Tangle().Events.MessageInvalid.Attach(events.NewClosure(func(messageID tangle.MessageID) { requestedMsgs.delete(messageID) }))
 }
+
+// requestMessages requests the messages of the given request from the chosen neighbor or from all neighbors, if no
+// neighbor was chosen or it is no longer connected.
+func requestMessages(mgr *gossip.Manager, sendRequest *tangle.SendRequestEvent) {
+	ids := make([][]byte, len(sendRequest.IDs))
+	for i := range sendRequest.IDs {
+		ids[i] = sendRequest.IDs[i].Bytes()
+	}
+
+	if sendRequest.Peer != (identity.ID{}) {
+		for _, nbr := range mgr.AllNeighbors() {
+			if nbr.ID() == sendRequest.Peer {
+				mgr.RequestMessages(ids, sendRequest.Peer)
+				return
+			}
+		}
+	}
+
+	mgr.RequestMessages(ids)
+}
+
+// reportInvalidMessage reports the neighbor that sent a message which was rejected by the parser. Only messages with an
+// invalid signature or an invalid PoW are reported, as the other checks of the parser also reject messages that honest
+// neighbors forward (e.g. duplicates or messages that are too old). Messages that were not received via gossip are
+// ignored.
+func reportInvalidMessage(mgr *gossip.Manager, p *peer.Peer, err error) {
+	if p == nil {
+		return
+	}
+
+	if xerrors.Is(err, tangle.ErrInvalidSignature) || xerrors.Is(err, tangle.ErrInvalidPOWDifficultly) || xerrors.Is(err, tangle.ErrMessageTooSmall) {
+		mgr.ReportMisbehavior(p.ID(), gossip.MisbehaviorInvalidMessage)
+	}
+}
@@ -0,0 +1,14 @@
+package manualpeering
+
+import (
+	flag "github.com/spf13/pflag"
+)
+
+const (
+	// CfgManualPeeringKnownPeers defines the config flag of the manually configured peers.
+	CfgManualPeeringKnownPeers = "manualPeering.knownPeers"
+)
+
+func init() {
+	flag.StringSlice(CfgManualPeeringKnownPeers, []string{}, "list of peers (publicKey@host:gossipPort) the node always stays connected to; the peers must know this node as well")
+}
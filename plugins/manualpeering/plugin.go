@@ -0,0 +1,91 @@
+package manualpeering
+
+import (
+	"sync"
+
+	"github.com/iotaledger/goshimmer/packages/manualpeering"
+	"github.com/iotaledger/goshimmer/packages/shutdown"
+	"github.com/iotaledger/goshimmer/plugins/autopeering/local"
+	"github.com/iotaledger/goshimmer/plugins/config"
+	"github.com/iotaledger/goshimmer/plugins/gossip"
+	"github.com/iotaledger/hive.go/autopeering/peer"
+	"github.com/iotaledger/hive.go/daemon"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/hive.go/node"
+)
+
+// PluginName is the name of the manual peering plugin.
+const PluginName = "ManualPeering"
+
+var (
+	// plugin is the plugin instance of the manual peering plugin.
+	plugin *node.Plugin
+	once   sync.Once
+
+	log *logger.Logger
+
+	mgr     *manualpeering.Manager
+	mgrOnce sync.Once
+)
+
+// Plugin gets the plugin instance.
+func Plugin() *node.Plugin {
+	once.Do(func() {
+		plugin = node.NewPlugin(PluginName, node.Enabled, configure, run)
+	})
+	return plugin
+}
+
+// Manager returns the manager instance of the manual peering plugin.
+func Manager() *manualpeering.Manager {
+	mgrOnce.Do(func() {
+		mgr = manualpeering.NewManager(gossip.Manager(), local.GetInstance(), logger.NewLogger(PluginName))
+	})
+	return mgr
+}
+
+func configure(*node.Plugin) {
+	log = logger.NewLogger(PluginName)
+
+	knownPeers, err := parseKnownPeers()
+	if err != nil {
+		log.Fatalf("Invalid %s: %s", CfgManualPeeringKnownPeers, err)
+	}
+	if err := Manager().AddPeer(knownPeers...); err != nil {
+		log.Fatalf("Failed to add known peers: %s", err)
+	}
+}
+
+func run(*node.Plugin) {
+	if err := daemon.BackgroundWorker(PluginName, start, shutdown.PriorityManualPeering); err != nil {
+		log.Panicf("Failed to start as daemon: %s", err)
+	}
+}
+
+func start(shutdownSignal <-chan struct{}) {
+	defer log.Info("Stopping " + PluginName + " ... done")
+
+	Manager().Start()
+	defer Manager().Stop()
+
+	log.Infof("%s started: known-peers=%d", PluginName, len(Manager().GetPeers()))
+
+	<-shutdownSignal
+	log.Info("Stopping " + PluginName + " ...")
+}
+
+func parseKnownPeers() (result []*peer.Peer, err error) {
+	for _, definition := range config.Node().Strings(CfgManualPeeringKnownPeers) {
+		if definition == "" {
+			continue
+		}
+
+		p, err := manualpeering.ParsePeer(definition)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,151 @@
+package messagelayer
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/goshimmer/packages/shutdown"
+	"github.com/iotaledger/goshimmer/packages/tangle"
+	"github.com/iotaledger/goshimmer/plugins/autopeering"
+	"github.com/iotaledger/goshimmer/plugins/config"
+	"github.com/iotaledger/hive.go/daemon"
+	"golang.org/x/xerrors"
+)
+
+// runLocalSnapshots starts the background worker that periodically creates a local snapshot and prunes the messages
+// that are older than the pruning window afterwards.
+func runLocalSnapshots() {
+	interval := config.Node().Duration(CfgMessageLayerLocalSnapshotInterval)
+	if interval <= 0 {
+		return
+	}
+
+	if err := daemon.BackgroundWorker("LocalSnapshot", func(shutdownSignal <-chan struct{}) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := createLocalSnapshotAndPrune(); err != nil {
+					log.Errorf("failed to create local snapshot: %s", err)
+				}
+			case <-shutdownSignal:
+				return
+			}
+		}
+	}, shutdown.PriorityLocalSnapshot); err != nil {
+		log.Panicf("Failed to start as daemon: %s", err)
+	}
+}
+
+// loadSnapshotFile restores the ledger state from the given snapshot file. Snapshots in the versioned format have to
+// belong to the network of the node, while snapshots in the legacy format are loaded unconditionally.
+func loadSnapshotFile(filePath string) (err error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return xerrors.Errorf("failed to open snapshot file: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	if !tangle.IsVersionedSnapshot(reader) {
+		snapshot := ledgerstate.Snapshot{}
+		if _, err = snapshot.ReadFrom(reader); err != nil {
+			return xerrors.Errorf("failed to read legacy snapshot: %w", err)
+		}
+		Tangle().LedgerState.LoadSnapshot(snapshot)
+
+		return nil
+	}
+
+	return LoadSnapshot(file)
+}
+
+// LoadSnapshot restores the ledger state and the solid entry points from the snapshot (in the versioned format) that is
+// read from the given reader. The snapshot has to belong to the network of the node, which is checked on its header
+// before anything is applied.
+func LoadSnapshot(reader io.ReadSeeker) (err error) {
+	if _, err = reader.Seek(0, io.SeekStart); err != nil {
+		return xerrors.Errorf("failed to rewind snapshot: %w", err)
+	}
+	snapshotReader, err := tangle.NewSnapshotReader(reader)
+	if err != nil {
+		return xerrors.Errorf("failed to read snapshot header: %w", err)
+	}
+	if header := snapshotReader.Header(); header.NetworkID != networkID() {
+		return xerrors.Errorf("snapshot belongs to network %d instead of %d: %w", header.NetworkID, networkID(), tangle.ErrInvalidSnapshot)
+	}
+
+	if _, err = reader.Seek(0, io.SeekStart); err != nil {
+		return xerrors.Errorf("failed to rewind snapshot: %w", err)
+	}
+	if _, err = Tangle().LoadSnapshotFrom(reader); err != nil {
+		return xerrors.Errorf("failed to load snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// createLocalSnapshotAndPrune writes a local snapshot to disk and prunes the Tangle once the snapshot was persisted.
+func createLocalSnapshotAndPrune() (err error) {
+	cutoff := time.Now().Add(-config.Node().Duration(CfgMessageLayerPruningWindow))
+
+	snapshotFilePath := config.Node().String(CfgMessageLayerLocalSnapshotFile)
+	if err = writeFileAtomically(snapshotFilePath, func(writer io.Writer) error {
+		return Tangle().WriteSnapshot(writer, networkID(), cutoff)
+	}); err != nil {
+		return xerrors.Errorf("failed to write local snapshot to %s: %w", snapshotFilePath, err)
+	}
+	log.Infof("wrote local snapshot to %s", snapshotFilePath)
+
+	prunedMessages, prunedBranches, err := Tangle().PruneOlderThan(cutoff)
+	if err != nil {
+		return xerrors.Errorf("failed to prune the tangle: %w", err)
+	}
+	log.Infof("pruned %d messages and %d branches older than %s", prunedMessages, prunedBranches, cutoff)
+
+	return nil
+}
+
+// networkID returns the identifier of the network that the node is part of.
+func networkID() uint32 {
+	return uint32(config.Node().Int(autopeering.CfgNetworkVersion))
+}
+
+// writeFileAtomically writes a file by writing to a temporary file in the same directory first and renaming it
+// afterwards, so that a crash never leaves a partially written file behind.
+func writeFileAtomically(filePath string, write func(writer io.Writer) error) (err error) {
+	tmpFile, err := ioutil.TempFile(filepath.Dir(filePath), filepath.Base(filePath)+".tmp")
+	if err != nil {
+		return xerrors.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmpFile.Name())
+		}
+	}()
+
+	if err = write(tmpFile); err != nil {
+		_ = tmpFile.Close()
+		return xerrors.Errorf("failed to write temporary file: %w", err)
+	}
+	if err = tmpFile.Sync(); err != nil {
+		_ = tmpFile.Close()
+		return xerrors.Errorf("failed to sync temporary file: %w", err)
+	}
+	if err = tmpFile.Close(); err != nil {
+		return xerrors.Errorf("failed to close temporary file: %w", err)
+	}
+
+	if err = os.Rename(tmpFile.Name(), filePath); err != nil {
+		return xerrors.Errorf("failed to rename temporary file: %w", err)
+	}
+
+	return nil
+}
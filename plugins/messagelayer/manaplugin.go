@@ -0,0 +1,344 @@
+package messagelayer
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/goshimmer/packages/mana"
+	"github.com/iotaledger/goshimmer/packages/shutdown"
+	"github.com/iotaledger/goshimmer/plugins/config"
+	"github.com/iotaledger/goshimmer/plugins/database"
+	"github.com/iotaledger/hive.go/daemon"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/identity"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/hive.go/node"
+	flag "github.com/spf13/pflag"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// ManaPluginName is the name of the mana plugin.
+	ManaPluginName = "Mana"
+
+	// CfgManaSnapshotInterval is the interval in which snapshots of the mana vectors are taken.
+	CfgManaSnapshotInterval = "mana.snapshotInterval"
+
+	// CfgManaSnapshotRetention is the time that snapshots of the mana vectors are kept before they get pruned.
+	CfgManaSnapshotRetention = "mana.snapshotRetention"
+
+	// CfgManaPersistInterval is the interval in which the mana vectors are written to the database.
+	CfgManaPersistInterval = "mana.persistInterval"
+)
+
+func init() {
+	flag.Duration(CfgManaSnapshotInterval, 10*time.Minute, "the interval in which snapshots of the mana vectors are taken")
+	flag.Duration(CfgManaSnapshotRetention, 7*24*time.Hour, "the time that snapshots of the mana vectors are kept before they get pruned")
+	flag.Duration(CfgManaPersistInterval, time.Minute, "the interval in which the mana vectors are written to the database")
+}
+
+var (
+	// manaPlugin is the plugin instance of the mana plugin.
+	manaPlugin      *node.Plugin
+	manaPluginOnce  sync.Once
+	manaLogger      *logger.Logger
+	baseManaVectors map[mana.Type]*mana.BaseManaVector
+	manaStorage     *mana.Storage
+
+	// bookedUntil is the solidification time of the last transaction whose mana was booked into the vectors.
+	bookedUntil      time.Time
+	bookedUntilMutex sync.Mutex
+)
+
+// ManaPlugin gets the mana plugin instance.
+func ManaPlugin() *node.Plugin {
+	manaPluginOnce.Do(func() {
+		manaPlugin = node.NewPlugin(ManaPluginName, node.Enabled, configureManaPlugin, runManaPlugin)
+	})
+	return manaPlugin
+}
+
+func configureManaPlugin(*node.Plugin) {
+	manaLogger = logger.NewLogger(ManaPluginName)
+
+	manaStorage = mana.NewStorage(database.Store())
+	baseManaVectors = map[mana.Type]*mana.BaseManaVector{
+		mana.AccessMana:    mana.NewBaseManaVector(mana.AccessMana),
+		mana.ConsensusMana: mana.NewBaseManaVector(mana.ConsensusMana),
+	}
+	// the persisted vectors keep the pledges of transactions that were pruned from or replaced in the ledger, so only
+	// the transactions that were booked after they were persisted need to be replayed
+	persistedTime, err := loadManaVectors()
+	if err != nil {
+		manaLogger.Panicf("Failed to load the mana vectors: %s", err)
+	}
+	replayManaVectors(persistedTime)
+
+	Tangle().LedgerState.UTXODAG.Events.TransactionBooked.Attach(events.NewClosure(onTransactionBooked))
+}
+
+func runManaPlugin(*node.Plugin) {
+	if err := daemon.BackgroundWorker("Mana", func(shutdownSignal <-chan struct{}) {
+		snapshotTicker := time.NewTicker(config.Node().Duration(CfgManaSnapshotInterval))
+		defer snapshotTicker.Stop()
+		persistTicker := time.NewTicker(config.Node().Duration(CfgManaPersistInterval))
+		defer persistTicker.Stop()
+
+		for {
+			select {
+			case <-snapshotTicker.C:
+				takeManaSnapshots(time.Now())
+			case <-persistTicker.C:
+				persistManaVectors()
+			case <-shutdownSignal:
+				persistManaVectors()
+				manaStorage.Shutdown()
+
+				return
+			}
+		}
+	}, shutdown.PriorityMana); err != nil {
+		manaLogger.Panicf("Failed to start as daemon: %s", err)
+	}
+}
+
+// onTransactionBooked books the mana of transactions that were booked into a branch that is not invalid or rejected.
+func onTransactionBooked(transactionID ledgerstate.TransactionID) {
+	var solidificationTime time.Time
+	Tangle().LedgerState.TransactionMetadata(transactionID).Consume(func(transactionMetadata *ledgerstate.TransactionMetadata) {
+		solidificationTime = transactionMetadata.SolidificationTime()
+	})
+
+	bookedUntilMutex.Lock()
+	defer bookedUntilMutex.Unlock()
+
+	if solidificationTime.After(bookedUntil) {
+		bookedUntil = solidificationTime
+	}
+
+	if !pledgesMana(transactionID) {
+		return
+	}
+
+	var txInfo *mana.TxInfo
+	Tangle().LedgerState.Transaction(transactionID).Consume(func(transaction *ledgerstate.Transaction) {
+		txInfo = txInfoFromTransaction(transaction)
+	})
+	if txInfo == nil {
+		return
+	}
+
+	for _, baseManaVector := range baseManaVectors {
+		baseManaVector.BookMana(txInfo)
+	}
+}
+
+// loadManaVectors restores the mana vectors from the database and returns the time up to which they contain the booked
+// transactions (the zero time if nothing was persisted, yet).
+func loadManaVectors() (persistedTime time.Time, err error) {
+	persistedTime, persisted, err := manaStorage.PersistedTime()
+	if err != nil || !persisted {
+		return
+	}
+
+	for _, baseManaVector := range baseManaVectors {
+		if err = manaStorage.LoadBaseManaVector(baseManaVector); err != nil {
+			return
+		}
+	}
+	bookedUntil = persistedTime
+
+	return
+}
+
+// replayManaVectors books the mana of all the transactions of the ledger that were solidified after the given time in
+// the order of their timestamps.
+func replayManaVectors(persistedTime time.Time) {
+	transactionIDs := make([]ledgerstate.TransactionID, 0)
+	timestamps := make(map[ledgerstate.TransactionID]time.Time)
+	Tangle().LedgerState.ForEachTransaction(func(transaction *ledgerstate.Transaction) bool {
+		Tangle().LedgerState.TransactionMetadata(transaction.ID()).Consume(func(transactionMetadata *ledgerstate.TransactionMetadata) {
+			if !transactionMetadata.SolidificationTime().After(persistedTime) {
+				return
+			}
+
+			transactionIDs = append(transactionIDs, transaction.ID())
+			timestamps[transaction.ID()] = transaction.Essence().Timestamp()
+		})
+
+		return true
+	})
+	sort.Slice(transactionIDs, func(i, j int) bool {
+		return timestamps[transactionIDs[i]].Before(timestamps[transactionIDs[j]])
+	})
+
+	for _, transactionID := range transactionIDs {
+		onTransactionBooked(transactionID)
+	}
+	manaLogger.Infof("Replayed %d transactions that were booked after %s into the mana vectors", len(transactionIDs), persistedTime)
+}
+
+// pledgesMana returns true if the transaction was booked into a branch that is neither invalid nor rejected.
+func pledgesMana(transactionID ledgerstate.TransactionID) bool {
+	branchID := Tangle().LedgerState.BranchID(transactionID)
+
+	return branchID != ledgerstate.InvalidBranchID && Tangle().LedgerState.BranchInclusionState(branchID) != ledgerstate.Rejected
+}
+
+// txInfoFromTransaction gathers the information about the given transaction and its inputs that is needed to book its
+// mana.
+func txInfoFromTransaction(transaction *ledgerstate.Transaction) (txInfo *mana.TxInfo) {
+	txInfo = &mana.TxInfo{
+		TimeStamp:     transaction.Essence().Timestamp(),
+		TransactionID: transaction.ID(),
+		PledgeID: map[mana.Type]identity.ID{
+			mana.AccessMana:    transaction.Essence().AccessPledgeID(),
+			mana.ConsensusMana: transaction.Essence().ConsensusPledgeID(),
+		},
+	}
+
+	for _, input := range transaction.Essence().Inputs() {
+		outputID := input.(*ledgerstate.UTXOInput).ReferencedOutputID()
+
+		inputInfo := mana.InputInfo{}
+		Tangle().LedgerState.Output(outputID).Consume(func(output ledgerstate.Output) {
+			output.Balances().ForEach(func(_ ledgerstate.Color, balance uint64) bool {
+				inputInfo.Amount += float64(balance)
+				return true
+			})
+		})
+
+		// the outputs of the snapshot have no creating transaction and therefore no previous pledge
+		Tangle().LedgerState.Transaction(outputID.TransactionID()).Consume(func(inputTransaction *ledgerstate.Transaction) {
+			inputInfo.TimeStamp = inputTransaction.Essence().Timestamp()
+			inputInfo.PledgeID = map[mana.Type]identity.ID{
+				mana.AccessMana:    inputTransaction.Essence().AccessPledgeID(),
+				mana.ConsensusMana: inputTransaction.Essence().ConsensusPledgeID(),
+			}
+		})
+
+		txInfo.TotalBalance += inputInfo.Amount
+		txInfo.InputInfos = append(txInfo.InputInfos, inputInfo)
+	}
+
+	return
+}
+
+// persistManaVectors writes the current state of all the mana vectors and the time up to which they contain the booked
+// transactions to the database.
+func persistManaVectors() {
+	bookedUntilMutex.Lock()
+	defer bookedUntilMutex.Unlock()
+
+	for _, baseManaVector := range baseManaVectors {
+		manaStorage.StoreBaseManaVector(baseManaVector)
+	}
+	if err := manaStorage.StorePersistedTime(bookedUntil); err != nil {
+		manaLogger.Errorf("Failed to persist the mana vectors: %s", err)
+	}
+}
+
+// takeManaSnapshots persists a snapshot of all the mana vectors and prunes the ones that exceeded their retention.
+func takeManaSnapshots(t time.Time) {
+	for _, baseManaVector := range baseManaVectors {
+		manaStorage.StoreSnapshot(mana.NewSnapshot(baseManaVector, t))
+	}
+	manaStorage.PruneSnapshots(t.Add(-config.Node().Duration(CfgManaSnapshotRetention)))
+}
+
+// GetAccessMana returns the access mana of the given node and the time it was calculated for.
+func GetAccessMana(nodeID identity.ID, optionalUpdateTime ...time.Time) (float64, time.Time, error) {
+	return GetMana(mana.AccessMana, nodeID, optionalUpdateTime...)
+}
+
+// GetConsensusMana returns the consensus mana of the given node and the time it was calculated for.
+func GetConsensusMana(nodeID identity.ID, optionalUpdateTime ...time.Time) (float64, time.Time, error) {
+	return GetMana(mana.ConsensusMana, nodeID, optionalUpdateTime...)
+}
+
+// GetMana returns the mana of the given Type of the given node and the time it was calculated for.
+func GetMana(manaType mana.Type, nodeID identity.ID, optionalUpdateTime ...time.Time) (float64, time.Time, error) {
+	baseManaVector, err := baseManaVector(manaType)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	return baseManaVector.GetMana(nodeID, optionalUpdateTime...)
+}
+
+// GetManaMap returns the mana of the given Type of all the nodes and the time it was calculated for.
+func GetManaMap(manaType mana.Type, optionalUpdateTime ...time.Time) (mana.NodeMap, time.Time, error) {
+	baseManaVector, err := baseManaVector(manaType)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	nodeMap, updateTime := baseManaVector.GetManaMap(optionalUpdateTime...)
+	return nodeMap, updateTime, nil
+}
+
+// GetHighestManaNodes returns the n nodes with the highest mana of the given Type in descending order (all nodes if
+// n is 0).
+func GetHighestManaNodes(manaType mana.Type, n uint) ([]mana.Node, time.Time, error) {
+	baseManaVector, err := baseManaVector(manaType)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	nodes, updateTime := baseManaVector.GetHighestManaNodes(n)
+	return nodes, updateTime, nil
+}
+
+// GetManaSnapshot returns the most recent snapshot of the given Type that was taken at or before the given time.
+func GetManaSnapshot(manaType mana.Type, t time.Time) (*mana.Snapshot, error) {
+	if manaStorage == nil {
+		return nil, xerrors.Errorf("mana plugin is not running: %w", mana.ErrSnapshotNotFound)
+	}
+
+	return manaStorage.Snapshot(manaType, t)
+}
+
+// accessManaRetriever returns the access mana of the given node or 0 if it is unknown.
+func accessManaRetriever(nodeID identity.ID) float64 {
+	accessMana, _, err := GetAccessMana(nodeID)
+	if err != nil {
+		return 0
+	}
+
+	return accessMana
+}
+
+// consensusManaRetriever returns the consensus mana of the given node or 0 if it is unknown.
+func consensusManaRetriever(nodeID identity.ID) float64 {
+	consensusMana, _, err := GetConsensusMana(nodeID)
+	if err != nil {
+		return 0
+	}
+
+	return consensusMana
+}
+
+// totalConsensusManaRetriever returns the sum of the consensus mana of all the nodes.
+func totalConsensusManaRetriever() (totalConsensusMana float64) {
+	consensusManaMap, _, err := GetManaMap(mana.ConsensusMana)
+	if err != nil {
+		return 0
+	}
+
+	for _, consensusMana := range consensusManaMap {
+		totalConsensusMana += consensusMana
+	}
+
+	return
+}
+
+func baseManaVector(manaType mana.Type) (*mana.BaseManaVector, error) {
+	baseManaVector, exists := baseManaVectors[manaType]
+	if !exists {
+		return nil, xerrors.Errorf("no base mana vector for mana type %s: %w", manaType, mana.ErrUnknownManaType)
+	}
+
+	return baseManaVector, nil
+}
@@ -2,11 +2,11 @@ package messagelayer
 
 import (
 	"errors"
-	"os"
 	"sync"
 	"time"
 
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/goshimmer/packages/markers"
 	"github.com/iotaledger/goshimmer/packages/shutdown"
 	"github.com/iotaledger/goshimmer/packages/tangle"
 	"github.com/iotaledger/goshimmer/plugins/autopeering/local"
@@ -29,11 +29,53 @@ const (
 	// CfgMessageLayerSnapshotFile is the path to the snapshot file.
 	CfgMessageLayerSnapshotFile = "messageLayer.snapshot.file"
 
+	// CfgMessageLayerLocalSnapshotFile is the path to the file that local snapshots are written to.
+	CfgMessageLayerLocalSnapshotFile = "messageLayer.localSnapshot.file"
+
+	// CfgMessageLayerLocalSnapshotInterval is the interval in which local snapshots are created (0 disables them).
+	CfgMessageLayerLocalSnapshotInterval = "messageLayer.localSnapshot.interval"
+
+	// CfgMessageLayerPruningWindow is the time that messages are kept before they get pruned after a local snapshot.
+	CfgMessageLayerPruningWindow = "messageLayer.pruning.window"
+
 	// CfgMessageLayerFCOBAverageNetworkDelay is the avg. network delay to use for FCoB rules
 	CfgMessageLayerFCOBAverageNetworkDelay = "messageLayer.fcob.averageNetworkDelay"
 
 	// CfgTangleWidth is the width of the Tangle.
 	CfgTangleWidth = "messageLayer.tangleWidth"
+
+	// CfgSchedulerRate is the time the scheduler waits between scheduling two messages.
+	CfgSchedulerRate = "messageLayer.scheduler.rate"
+
+	// CfgSchedulerMaxBufferSize is the maximum size (in bytes) of all the messages in the buffer of the scheduler.
+	CfgSchedulerMaxBufferSize = "messageLayer.scheduler.maxBufferSize"
+
+	// CfgApprovalWeightThreshold is the share of the total consensus mana that needs to approve a marker to confirm it.
+	CfgApprovalWeightThreshold = "messageLayer.approvalWeight.threshold"
+
+	// CfgTipSelectionStrategy is the name of the strategy that is used to select the strong tips.
+	CfgTipSelectionStrategy = "messageLayer.tipSelection.strategy"
+
+	// CfgTipSelectionMaxAge is the maximum age of the tips that are selected by the maxAge tip selection strategy.
+	CfgTipSelectionMaxAge = "messageLayer.tipSelection.maxAge"
+
+	// CfgTipEvictionMaxAge is the maximum age of a tip before it gets evicted (0 disables the limit).
+	CfgTipEvictionMaxAge = "messageLayer.tipEviction.maxAge"
+
+	// CfgTipEvictionMaxDepth is the maximum marker index distance of a tip before it gets evicted (0 disables the limit).
+	CfgTipEvictionMaxDepth = "messageLayer.tipEviction.maxDepth"
+
+	// CfgTipEvictionInterval is the interval in which stale tips are evicted (0 disables the periodic eviction).
+	CfgTipEvictionInterval = "messageLayer.tipEviction.interval"
+
+	// CfgRequesterRetryInterval is the time after which a missing message is requested again for the first time.
+	CfgRequesterRetryInterval = "messageLayer.requester.retryInterval"
+
+	// CfgRequesterMaxRetryInterval is the upper bound of the exponentially growing retry interval of missing messages.
+	CfgRequesterMaxRetryInterval = "messageLayer.requester.maxRetryInterval"
+
+	// CfgRequesterMaxAttempts is the number of requests after which a missing message is given up.
+	CfgRequesterMaxAttempts = "messageLayer.requester.maxAttempts"
 )
 
 var (
@@ -44,8 +86,22 @@ var (
 
 func init() {
 	flag.String(CfgMessageLayerSnapshotFile, "./snapshot.bin", "the path to the snapshot file")
+	flag.String(CfgMessageLayerLocalSnapshotFile, "./localsnapshot.bin", "the path to the file that local snapshots are written to")
+	flag.Duration(CfgMessageLayerLocalSnapshotInterval, 0, "the interval in which local snapshots are created (0 disables them)")
+	flag.Duration(CfgMessageLayerPruningWindow, 24*time.Hour, "the time that messages are kept before they get pruned after a local snapshot")
 	flag.Int(CfgMessageLayerFCOBAverageNetworkDelay, 5, "the avg. network delay to use for FCoB rules")
 	flag.Int(CfgTangleWidth, 0, "the width of the Tangle")
+	flag.Duration(CfgSchedulerRate, tangle.DefaultSchedulerRate, "the time the scheduler waits between scheduling two messages")
+	flag.Int(CfgSchedulerMaxBufferSize, tangle.DefaultMaxBufferSize, "the maximum size (in bytes) of all the messages in the buffer of the scheduler")
+	flag.String(CfgTipSelectionStrategy, tangle.UniformTipSelection, "the name of the strategy that is used to select the strong tips")
+	flag.Duration(CfgTipSelectionMaxAge, tangle.DefaultTipSelectionMaxAge, "the maximum age of the tips that are selected by the maxAge tip selection strategy")
+	flag.Duration(CfgTipEvictionMaxAge, 30*time.Minute, "the maximum age of a tip before it gets evicted (0 disables the limit)")
+	flag.Int(CfgTipEvictionMaxDepth, 0, "the maximum marker index distance of a tip before it gets evicted (0 disables the limit)")
+	flag.Duration(CfgTipEvictionInterval, 10*time.Second, "the interval in which stale tips are evicted (0 disables the periodic eviction)")
+	flag.Duration(CfgRequesterRetryInterval, tangle.DefaultRetryInterval, "the time after which a missing message is requested again for the first time")
+	flag.Duration(CfgRequesterMaxRetryInterval, tangle.DefaultMaxRetryInterval, "the upper bound of the exponentially growing retry interval of missing messages")
+	flag.Int(CfgRequesterMaxAttempts, tangle.DefaultMaxRequestAttempts, "the number of requests after which a missing message is given up")
+	flag.Float64(CfgApprovalWeightThreshold, tangle.DefaultConfirmationThreshold, "the share of the total consensus mana that needs to approve a marker to confirm it")
 }
 
 var (
@@ -72,12 +128,57 @@ func Tangle() *tangle.Tangle {
 			tangle.Store(database.Store()),
 			tangle.Identity(local.GetInstance().LocalIdentity()),
 			tangle.TangleWidth(config.Node().Int(CfgTangleWidth)),
+			tangle.SchedulerConfig(schedulerParams()),
+			tangle.ApprovalWeightConfig(approvalWeightParams()),
+			tangle.TipSelection(tipSelectionStrategy()),
+			tangle.TipEvictionConfig(tipEvictionParams()),
+			tangle.RequesterConfig(requesterOptions()...),
 		)
 	})
 
 	return tangleInstance
 }
 
+func schedulerParams() tangle.SchedulerParams {
+	return tangle.SchedulerParams{
+		Rate:                   config.Node().Duration(CfgSchedulerRate),
+		MaxBufferSize:          config.Node().Int(CfgSchedulerMaxBufferSize),
+		AccessManaRetrieveFunc: accessManaRetriever,
+	}
+}
+
+func approvalWeightParams() tangle.ApprovalWeightParams {
+	return tangle.ApprovalWeightParams{
+		ConsensusManaRetrieveFunc:      consensusManaRetriever,
+		TotalConsensusManaRetrieveFunc: totalConsensusManaRetriever,
+		ConfirmationThreshold:          config.Node().Float64(CfgApprovalWeightThreshold),
+	}
+}
+
+func requesterOptions() []tangle.RequesterOption {
+	return []tangle.RequesterOption{
+		tangle.RetryInterval(config.Node().Duration(CfgRequesterRetryInterval)),
+		tangle.MaxRetryInterval(config.Node().Duration(CfgRequesterMaxRetryInterval)),
+		tangle.MaxRequestAttempts(config.Node().Int(CfgRequesterMaxAttempts)),
+	}
+}
+
+func tipEvictionParams() tangle.TipEvictionParams {
+	return tangle.TipEvictionParams{
+		MaxTipAge:   config.Node().Duration(CfgTipEvictionMaxAge),
+		MaxTipDepth: markers.Index(config.Node().Int(CfgTipEvictionMaxDepth)),
+	}
+}
+
+func tipSelectionStrategy() tangle.TipSelectionStrategy {
+	strategy, err := tangle.NewTipSelectionStrategy(config.Node().String(CfgTipSelectionStrategy), config.Node().Duration(CfgTipSelectionMaxAge))
+	if err != nil {
+		panic(err)
+	}
+
+	return strategy
+}
+
 func configure(*node.Plugin) {
 	log = logger.NewLogger(PluginName)
 	Tangle().Setup()
@@ -86,18 +187,16 @@ func configure(*node.Plugin) {
 		log.Error(err)
 	}))
 
+	Tangle().Requester.Events.RequestFailed.Attach(events.NewClosure(func(messageID tangle.MessageID) {
+		log.Debugf("giving up on missing message %s", messageID)
+	}))
+
 	// read snapshot file
 	snapshotFilePath := config.Node().String(CfgMessageLayerSnapshotFile)
 	if len(snapshotFilePath) != 0 {
-		snapshot := ledgerstate.Snapshot{}
-		f, err := os.Open(snapshotFilePath)
-		if err != nil {
-			log.Panic("can not open snapshot file:", err)
-		}
-		if _, err := snapshot.ReadFrom(f); err != nil {
+		if err := loadSnapshotFile(snapshotFilePath); err != nil {
 			log.Panic("could not read snapshot file:", err)
 		}
-		Tangle().LedgerState.LoadSnapshot(snapshot)
 		log.Infof("read snapshot from %s", snapshotFilePath)
 	}
 
@@ -113,6 +212,9 @@ func run(*node.Plugin) {
 	}, shutdown.PriorityTangle); err != nil {
 		log.Panicf("Failed to start as daemon: %s", err)
 	}
+
+	runLocalSnapshots()
+	runTipEviction()
 }
 
 // AwaitMessageToBeBooked awaits maxAwait for the given message to get booked.
@@ -0,0 +1,36 @@
+package messagelayer
+
+import (
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/shutdown"
+	"github.com/iotaledger/goshimmer/plugins/config"
+	"github.com/iotaledger/hive.go/daemon"
+)
+
+// runTipEviction starts the background worker that periodically evicts the tips that exceeded the maximum tip age or
+// the maximum tip depth.
+func runTipEviction() {
+	interval := config.Node().Duration(CfgTipEvictionInterval)
+	if interval <= 0 {
+		return
+	}
+
+	if err := daemon.BackgroundWorker("TipEviction", func(shutdownSignal <-chan struct{}) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if evictedTips := Tangle().TipManager.EvictStaleTips(); evictedTips > 0 {
+					log.Debugf("evicted %d stale tips", evictedTips)
+				}
+			case <-shutdownSignal:
+				return
+			}
+		}
+	}, shutdown.PriorityTangle); err != nil {
+		log.Panicf("Failed to start as daemon: %s", err)
+	}
+}
@@ -2,13 +2,18 @@ package plugins
 
 import (
 	"github.com/iotaledger/goshimmer/plugins/webapi"
+	"github.com/iotaledger/goshimmer/plugins/webapi/assetregistry"
 	"github.com/iotaledger/goshimmer/plugins/webapi/autopeering"
 	"github.com/iotaledger/goshimmer/plugins/webapi/data"
 	"github.com/iotaledger/goshimmer/plugins/webapi/drng"
 	"github.com/iotaledger/goshimmer/plugins/webapi/faucet"
 	"github.com/iotaledger/goshimmer/plugins/webapi/healthz"
 	"github.com/iotaledger/goshimmer/plugins/webapi/info"
+	"github.com/iotaledger/goshimmer/plugins/webapi/mana"
+	"github.com/iotaledger/goshimmer/plugins/webapi/manualpeering"
 	"github.com/iotaledger/goshimmer/plugins/webapi/message"
+	"github.com/iotaledger/goshimmer/plugins/webapi/subscriptions"
+	"github.com/iotaledger/goshimmer/plugins/webapi/tipselection"
 	"github.com/iotaledger/goshimmer/plugins/webapi/tools"
 	"github.com/iotaledger/goshimmer/plugins/webapi/value"
 	"github.com/iotaledger/hive.go/node"
@@ -17,13 +22,18 @@ import (
 // WebAPI contains the webapi endpoint plugins of a GoShimmer node.
 var WebAPI = node.Plugins(
 	webapi.Plugin(),
+	assetregistry.Plugin(),
 	data.Plugin(),
 	drng.Plugin(),
 	faucet.Plugin(),
 	healthz.Plugin(),
 	message.Plugin(),
+	subscriptions.Plugin(),
 	autopeering.Plugin(),
 	info.Plugin(),
+	mana.Plugin(),
+	manualpeering.Plugin(),
+	tipselection.Plugin(),
 	value.Plugin(),
 	tools.Plugin(),
 )
@@ -0,0 +1,103 @@
+package assetregistry
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/iotaledger/goshimmer/packages/assetregistry"
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	assetRegistryPlugin "github.com/iotaledger/goshimmer/plugins/assetregistry"
+	"github.com/iotaledger/goshimmer/plugins/webapi"
+	"github.com/iotaledger/hive.go/node"
+	"github.com/labstack/echo"
+)
+
+// PluginName is the name of the web API asset registry endpoint plugin.
+const PluginName = "WebAPI asset registry Endpoint"
+
+var (
+	// plugin is the plugin instance of the web API asset registry endpoint plugin.
+	plugin *node.Plugin
+	once   sync.Once
+)
+
+// Plugin gets the plugin instance.
+func Plugin() *node.Plugin {
+	once.Do(func() {
+		plugin = node.NewPlugin(PluginName, node.Enabled, configure)
+	})
+	return plugin
+}
+
+func configure(_ *node.Plugin) {
+	webapi.Server().GET("assetRegistry/asset", assetHandler)
+	webapi.Server().GET("assetRegistry/assets", assetsHandler)
+}
+
+// assetHandler gets the published metadata of an asset.
+func assetHandler(c echo.Context) error {
+	color, err := ledgerstate.ColorFromBase58EncodedString(c.QueryParam("color"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, AssetResponse{Error: err.Error()})
+	}
+
+	assetMetadata, err := assetRegistryPlugin.Registry().AssetMetadata(color)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, AssetResponse{Error: err.Error()})
+	}
+	if assetMetadata == nil {
+		return c.JSON(http.StatusNotFound, AssetResponse{Error: "Asset not found"})
+	}
+
+	return c.JSON(http.StatusOK, AssetResponse{Asset: ParseAssetMetadata(assetMetadata)})
+}
+
+// assetsHandler gets the published metadata of all known assets.
+func assetsHandler(c echo.Context) error {
+	assets := make([]Asset, 0)
+	if err := assetRegistryPlugin.Registry().ForEach(func(assetMetadata *assetregistry.AssetMetadata) bool {
+		assets = append(assets, ParseAssetMetadata(assetMetadata))
+
+		return true
+	}); err != nil {
+		return c.JSON(http.StatusInternalServerError, AssetsResponse{Error: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, AssetsResponse{Assets: assets})
+}
+
+// ParseAssetMetadata converts the AssetMetadata of the registry into its JSON representation.
+func ParseAssetMetadata(assetMetadata *assetregistry.AssetMetadata) Asset {
+	return Asset{
+		Color:                assetMetadata.Color().Base58(),
+		MintingTransactionID: assetMetadata.MintingTransactionID().Base58(),
+		Timestamp:            assetMetadata.Timestamp().Unix(),
+		Name:                 assetMetadata.Name(),
+		Symbol:               assetMetadata.Symbol(),
+		Decimals:             assetMetadata.Decimals(),
+		URL:                  assetMetadata.URL(),
+	}
+}
+
+// Asset holds the published metadata of an asset.
+type Asset struct {
+	Color                string `json:"color"`
+	MintingTransactionID string `json:"minting_transaction_id"`
+	Timestamp            int64  `json:"timestamp"`
+	Name                 string `json:"name"`
+	Symbol               string `json:"symbol"`
+	Decimals             uint8  `json:"decimals"`
+	URL                  string `json:"url,omitempty"`
+}
+
+// AssetResponse is the HTTP response from retrieving the metadata of an asset.
+type AssetResponse struct {
+	Asset Asset  `json:"asset,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// AssetsResponse is the HTTP response from retrieving the metadata of all known assets.
+type AssetsResponse struct {
+	Assets []Asset `json:"assets,omitempty"`
+	Error  string  `json:"error,omitempty"`
+}
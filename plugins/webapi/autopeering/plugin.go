@@ -6,7 +6,9 @@ import (
 	"strconv"
 	"sync"
 
+	gossipPkg "github.com/iotaledger/goshimmer/packages/gossip"
 	"github.com/iotaledger/goshimmer/plugins/autopeering"
+	"github.com/iotaledger/goshimmer/plugins/gossip"
 	"github.com/iotaledger/goshimmer/plugins/webapi"
 	"github.com/iotaledger/hive.go/autopeering/peer"
 	"github.com/iotaledger/hive.go/autopeering/peer/service"
@@ -35,7 +37,7 @@ func Plugin() *node.Plugin {
 	return plugin
 }
 
-// getNeighbors returns the chosen and accepted neighbors of the node
+// getNeighbors returns the chosen and accepted neighbors of the node as well as the manually configured neighbors
 func getNeighbors(c echo.Context) error {
 
 	var chosen []Neighbor
@@ -55,7 +57,15 @@ func getNeighbors(c echo.Context) error {
 		accepted = append(accepted, createNeighborFromPeer(p))
 	}
 
-	return c.JSON(http.StatusOK, Response{KnownPeers: knownPeers, Chosen: chosen, Accepted: accepted})
+	// the manually configured neighbors are managed independently of the autopeering
+	var manual []Neighbor
+	for _, nbr := range gossip.Manager().AllNeighbors() {
+		if nbr.Group() == gossipPkg.NeighborsGroupManual {
+			manual = append(manual, createNeighborFromPeer(nbr.Peer))
+		}
+	}
+
+	return c.JSON(http.StatusOK, Response{KnownPeers: knownPeers, Chosen: chosen, Accepted: accepted, Manual: manual})
 }
 
 func createNeighborFromPeer(p *peer.Peer) Neighbor {
@@ -73,6 +83,7 @@ type Response struct {
 	KnownPeers []Neighbor `json:"known,omitempty"`
 	Chosen     []Neighbor `json:"chosen"`
 	Accepted   []Neighbor `json:"accepted"`
+	Manual     []Neighbor `json:"manual,omitempty"`
 	Error      string     `json:"error,omitempty"`
 }
 
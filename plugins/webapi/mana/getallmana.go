@@ -0,0 +1,37 @@
+package mana
+
+import (
+	"net/http"
+
+	"github.com/iotaledger/goshimmer/packages/mana"
+	"github.com/iotaledger/goshimmer/plugins/messagelayer"
+	"github.com/labstack/echo"
+)
+
+// getAllManaHandler returns the access and consensus mana of all the known nodes.
+func getAllManaHandler(c echo.Context) error {
+	access, accessTime, err := messagelayer.GetHighestManaNodes(mana.AccessMana, 0)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, GetAllManaResponse{Error: err.Error()})
+	}
+	consensus, consensusTime, err := messagelayer.GetHighestManaNodes(mana.ConsensusMana, 0)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, GetAllManaResponse{Error: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, GetAllManaResponse{
+		Access:             nodesFromManaNodes(access),
+		AccessTimestamp:    accessTime.Unix(),
+		Consensus:          nodesFromManaNodes(consensus),
+		ConsensusTimestamp: consensusTime.Unix(),
+	})
+}
+
+// GetAllManaResponse is the response of the mana/all endpoint.
+type GetAllManaResponse struct {
+	Access             []Node `json:"access"`
+	AccessTimestamp    int64  `json:"accessTimestamp"`
+	Consensus          []Node `json:"consensus"`
+	ConsensusTimestamp int64  `json:"consensusTimestamp"`
+	Error              string `json:"error,omitempty"`
+}
@@ -0,0 +1,51 @@
+package mana
+
+import (
+	"net/http"
+
+	"github.com/iotaledger/goshimmer/plugins/autopeering/local"
+	"github.com/iotaledger/goshimmer/plugins/messagelayer"
+	"github.com/labstack/echo"
+	"github.com/mr-tron/base58"
+)
+
+// getManaHandler returns the access and consensus mana of the node with the given ID (or of the own node if no ID is
+// given).
+func getManaHandler(c echo.Context) error {
+	nodeID := local.GetInstance().ID()
+	if nodeIDString := c.QueryParam("nodeID"); nodeIDString != "" {
+		var err error
+		if nodeID, err = nodeIDFromBase58(nodeIDString); err != nil {
+			return c.JSON(http.StatusBadRequest, GetManaResponse{Error: err.Error()})
+		}
+	}
+
+	accessMana, accessTime, err := messagelayer.GetAccessMana(nodeID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, GetManaResponse{Error: err.Error()})
+	}
+	consensusMana, consensusTime, err := messagelayer.GetConsensusMana(nodeID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, GetManaResponse{Error: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, GetManaResponse{
+		ShortNodeID:        nodeID.String(),
+		NodeID:             base58.Encode(nodeID.Bytes()),
+		Access:             accessMana,
+		AccessTimestamp:    accessTime.Unix(),
+		Consensus:          consensusMana,
+		ConsensusTimestamp: consensusTime.Unix(),
+	})
+}
+
+// GetManaResponse is the response of the mana endpoint.
+type GetManaResponse struct {
+	Error              string  `json:"error,omitempty"`
+	ShortNodeID        string  `json:"shortNodeID"`
+	NodeID             string  `json:"nodeID"`
+	Access             float64 `json:"access"`
+	AccessTimestamp    int64   `json:"accessTimestamp"`
+	Consensus          float64 `json:"consensus"`
+	ConsensusTimestamp int64   `json:"consensusTimestamp"`
+}
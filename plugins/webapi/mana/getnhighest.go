@@ -0,0 +1,44 @@
+package mana
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/iotaledger/goshimmer/packages/mana"
+	"github.com/iotaledger/goshimmer/plugins/messagelayer"
+	"github.com/labstack/echo"
+)
+
+// getNHighestAccessHandler returns the nodes with the highest access mana.
+func getNHighestAccessHandler(c echo.Context) error {
+	return nHighestHandler(c, mana.AccessMana)
+}
+
+// getNHighestConsensusHandler returns the nodes with the highest consensus mana.
+func getNHighestConsensusHandler(c echo.Context) error {
+	return nHighestHandler(c, mana.ConsensusMana)
+}
+
+func nHighestHandler(c echo.Context, manaType mana.Type) error {
+	number, err := strconv.ParseUint(c.QueryParam("number"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, GetNHighestResponse{Error: err.Error()})
+	}
+
+	highestNodes, updateTime, err := messagelayer.GetHighestManaNodes(manaType, uint(number))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, GetNHighestResponse{Error: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, GetNHighestResponse{
+		Nodes:     nodesFromManaNodes(highestNodes),
+		Timestamp: updateTime.Unix(),
+	})
+}
+
+// GetNHighestResponse is the response of the mana/access/nhighest and mana/consensus/nhighest endpoints.
+type GetNHighestResponse struct {
+	Error     string `json:"error,omitempty"`
+	Nodes     []Node `json:"nodes,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+}
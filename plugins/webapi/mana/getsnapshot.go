@@ -0,0 +1,52 @@
+package mana
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/mana"
+	"github.com/iotaledger/goshimmer/plugins/messagelayer"
+	"github.com/labstack/echo"
+	"golang.org/x/xerrors"
+)
+
+// getSnapshotHandler returns the most recent mana snapshot of the given type that was taken at or before the given
+// unix timestamp (or the latest one if no timestamp is given).
+func getSnapshotHandler(c echo.Context) error {
+	manaType, err := mana.TypeFromString(c.QueryParam("type"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, GetSnapshotResponse{Error: err.Error()})
+	}
+
+	t := time.Now()
+	if timestamp := c.QueryParam("timestamp"); timestamp != "" {
+		unixSeconds, parseErr := strconv.ParseInt(timestamp, 10, 64)
+		if parseErr != nil {
+			return c.JSON(http.StatusBadRequest, GetSnapshotResponse{Error: parseErr.Error()})
+		}
+		t = time.Unix(unixSeconds, 0)
+	}
+
+	snapshot, err := messagelayer.GetManaSnapshot(manaType, t)
+	if err != nil {
+		if xerrors.Is(err, mana.ErrSnapshotNotFound) {
+			return c.JSON(http.StatusNotFound, GetSnapshotResponse{Error: err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, GetSnapshotResponse{Error: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, GetSnapshotResponse{
+		Type:      manaType.String(),
+		Nodes:     nodesFromManaNodes(snapshot.NodeMap().ToNodes().Highest(0)),
+		Timestamp: snapshot.Timestamp().Unix(),
+	})
+}
+
+// GetSnapshotResponse is the response of the mana/snapshot endpoint.
+type GetSnapshotResponse struct {
+	Error     string `json:"error,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Nodes     []Node `json:"nodes,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+}
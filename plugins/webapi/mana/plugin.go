@@ -0,0 +1,72 @@
+package mana
+
+import (
+	"sync"
+
+	"github.com/iotaledger/goshimmer/packages/mana"
+	"github.com/iotaledger/goshimmer/plugins/webapi"
+	"github.com/iotaledger/hive.go/identity"
+	"github.com/iotaledger/hive.go/node"
+	"github.com/mr-tron/base58"
+	"golang.org/x/xerrors"
+)
+
+// PluginName is the name of the web API mana endpoint plugin.
+const PluginName = "WebAPI Mana Endpoint"
+
+var (
+	// plugin is the plugin instance of the web API mana endpoint plugin.
+	plugin *node.Plugin
+	once   sync.Once
+)
+
+// Plugin gets the plugin instance.
+func Plugin() *node.Plugin {
+	once.Do(func() {
+		plugin = node.NewPlugin(PluginName, node.Enabled, configure)
+	})
+	return plugin
+}
+
+func configure(_ *node.Plugin) {
+	webapi.Server().GET("mana", getManaHandler)
+	webapi.Server().GET("mana/all", getAllManaHandler)
+	webapi.Server().GET("mana/access/nhighest", getNHighestAccessHandler)
+	webapi.Server().GET("mana/consensus/nhighest", getNHighestConsensusHandler)
+	webapi.Server().GET("mana/snapshot", getSnapshotHandler)
+}
+
+// Node is a node and its mana value.
+type Node struct {
+	ShortNodeID string  `json:"shortNodeID"`
+	NodeID      string  `json:"nodeID"`
+	Mana        float64 `json:"mana"`
+}
+
+func nodesFromManaNodes(manaNodes []mana.Node) (nodes []Node) {
+	nodes = make([]Node, 0, len(manaNodes))
+	for _, manaNode := range manaNodes {
+		nodes = append(nodes, Node{
+			ShortNodeID: manaNode.ID.String(),
+			NodeID:      base58.Encode(manaNode.ID.Bytes()),
+			Mana:        manaNode.Mana,
+		})
+	}
+
+	return
+}
+
+func nodeIDFromBase58(base58String string) (nodeID identity.ID, err error) {
+	nodeIDBytes, err := base58.Decode(base58String)
+	if err != nil {
+		err = xerrors.Errorf("failed to decode node ID: %w", err)
+		return
+	}
+	if len(nodeIDBytes) != len(identity.ID{}) {
+		err = xerrors.Errorf("invalid node ID length: %d", len(nodeIDBytes))
+		return
+	}
+	copy(nodeID[:], nodeIDBytes)
+
+	return
+}
@@ -0,0 +1,126 @@
+package manualpeering
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/iotaledger/goshimmer/packages/manualpeering"
+	manualPeeringPlugin "github.com/iotaledger/goshimmer/plugins/manualpeering"
+	"github.com/iotaledger/goshimmer/plugins/webapi"
+	"github.com/iotaledger/hive.go/autopeering/peer"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/hive.go/node"
+	"github.com/labstack/echo"
+)
+
+// PluginName is the name of the web API manual peering endpoint plugin.
+const PluginName = "WebAPI manual peering Endpoint"
+
+var (
+	// plugin is the plugin instance of the web API manual peering endpoint plugin.
+	plugin *node.Plugin
+	once   sync.Once
+)
+
+// Plugin gets the plugin instance.
+func Plugin() *node.Plugin {
+	once.Do(func() {
+		plugin = node.NewPlugin(PluginName, node.Enabled, configure)
+	})
+	return plugin
+}
+
+func configure(_ *node.Plugin) {
+	webapi.Server().GET("manualpeering/peers", getPeersHandler)
+	webapi.Server().POST("manualpeering/peers", addPeersHandler)
+	webapi.Server().DELETE("manualpeering/peers", removePeersHandler)
+}
+
+// getPeersHandler returns the manually configured peers together with the state of their connection.
+func getPeersHandler(c echo.Context) error {
+	peers := make([]Peer, 0)
+	for _, knownPeer := range manualPeeringPlugin.Manager().GetPeers() {
+		peers = append(peers, Peer{
+			PublicKey:        knownPeer.PublicKey.String(),
+			Address:          knownPeer.Address,
+			Direction:        knownPeer.Direction.String(),
+			ConnectionStatus: knownPeer.ConnectionStatus.String(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, PeersResponse{Peers: peers})
+}
+
+// addPeersHandler adds the given peers to the manually configured peers.
+func addPeersHandler(c echo.Context) error {
+	var request AddPeersRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, PeersResponse{Error: err.Error()})
+	}
+
+	peers := make([]*peer.Peer, 0, len(request.Peers))
+	for _, peerToAdd := range request.Peers {
+		publicKey, err := manualpeering.ParsePublicKey(peerToAdd.PublicKey)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, PeersResponse{Error: err.Error()})
+		}
+		p, err := manualpeering.NewPeer(publicKey, peerToAdd.Address)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, PeersResponse{Error: err.Error()})
+		}
+		peers = append(peers, p)
+	}
+
+	if err := manualPeeringPlugin.Manager().AddPeer(peers...); err != nil {
+		return c.JSON(http.StatusInternalServerError, PeersResponse{Error: err.Error()})
+	}
+
+	return getPeersHandler(c)
+}
+
+// removePeersHandler removes the peers with the given public keys from the manually configured peers.
+func removePeersHandler(c echo.Context) error {
+	var request RemovePeersRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, PeersResponse{Error: err.Error()})
+	}
+
+	keys := make([]ed25519.PublicKey, 0, len(request.PublicKeys))
+	for _, base58EncodedPublicKey := range request.PublicKeys {
+		publicKey, err := manualpeering.ParsePublicKey(base58EncodedPublicKey)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, PeersResponse{Error: err.Error()})
+		}
+		keys = append(keys, publicKey)
+	}
+
+	if err := manualPeeringPlugin.Manager().RemovePeer(keys...); err != nil {
+		return c.JSON(http.StatusInternalServerError, PeersResponse{Error: err.Error()})
+	}
+
+	return getPeersHandler(c)
+}
+
+// Peer contains the information of a manually configured peer.
+type Peer struct {
+	PublicKey        string `json:"publicKey"`
+	Address          string `json:"address"`
+	Direction        string `json:"direction,omitempty"`
+	ConnectionStatus string `json:"connectionStatus,omitempty"`
+}
+
+// AddPeersRequest is the HTTP request for adding manually configured peers.
+type AddPeersRequest struct {
+	Peers []Peer `json:"peers"`
+}
+
+// RemovePeersRequest is the HTTP request for removing manually configured peers.
+type RemovePeersRequest struct {
+	PublicKeys []string `json:"publicKeys"`
+}
+
+// PeersResponse is the HTTP response containing the manually configured peers.
+type PeersResponse struct {
+	Peers []Peer `json:"peers,omitempty"`
+	Error string `json:"error,omitempty"`
+}
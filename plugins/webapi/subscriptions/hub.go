@@ -0,0 +1,485 @@
+package subscriptions
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/goshimmer/packages/tangle"
+	"github.com/iotaledger/goshimmer/plugins/messagelayer"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// subscriberEventBufferSize defines how many Events can be queued for a client before it is disconnected.
+	subscriberEventBufferSize = 1024
+
+	// maxSubscriptionsPerSubscriber defines how many Messages, Transactions and addresses a single client can subscribe
+	// to. It also limits the number of Transactions that are watched because they touch a subscribed address.
+	maxSubscriptionsPerSubscriber = 1000
+)
+
+// region hub //////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// hub keeps track of the connected subscribers and dispatches the events of the Tangle to them.
+type hub struct {
+	subscribers      map[*subscriber]bool
+	subscribersMutex sync.RWMutex
+}
+
+// newHub creates a hub without any subscribers.
+func newHub() *hub {
+	return &hub{
+		subscribers: make(map[*subscriber]bool),
+	}
+}
+
+// register adds a new subscriber for the given websocket connection.
+func (h *hub) register(conn *websocket.Conn) (s *subscriber) {
+	s = newSubscriber(conn)
+
+	h.subscribersMutex.Lock()
+	defer h.subscribersMutex.Unlock()
+
+	h.subscribers[s] = true
+
+	return
+}
+
+// unregister removes the subscriber, stops its writer and drops all of its subscriptions.
+func (h *hub) unregister(s *subscriber) {
+	h.subscribersMutex.Lock()
+	delete(h.subscribers, s)
+	h.subscribersMutex.Unlock()
+
+	s.close()
+	s.clear()
+}
+
+// update applies the Request of a client to its subscriber. Newly subscribed Messages and Transactions immediately
+// receive the Events of the states that they reached already, so clients can not miss them.
+func (h *hub) update(s *subscriber, request Request) (err error) {
+	var subscribe bool
+	switch request.Action {
+	case ActionSubscribe:
+		subscribe = true
+	case ActionUnsubscribe:
+	default:
+		return xerrors.Errorf("unknown action '%s'", request.Action)
+	}
+
+	transactionIDs := make([]ledgerstate.TransactionID, len(request.TransactionIDs))
+	for i, base58TransactionID := range request.TransactionIDs {
+		if transactionIDs[i], err = ledgerstate.TransactionIDFromBase58(base58TransactionID); err != nil {
+			return xerrors.Errorf("failed to parse TransactionID '%s': %w", base58TransactionID, err)
+		}
+	}
+	for _, base58Address := range request.Addresses {
+		if _, err = ledgerstate.AddressFromBase58EncodedString(base58Address); err != nil {
+			return xerrors.Errorf("failed to parse address '%s': %w", base58Address, err)
+		}
+	}
+	messageIDs := make([]tangle.MessageID, len(request.MessageIDs))
+	for i, base58MessageID := range request.MessageIDs {
+		if messageIDs[i], err = tangle.NewMessageID(base58MessageID); err != nil {
+			return xerrors.Errorf("failed to parse MessageID '%s': %w", base58MessageID, err)
+		}
+	}
+
+	if !subscribe {
+		s.unsubscribe(transactionIDs, request.Addresses, messageIDs)
+		return
+	}
+
+	if err = s.subscribe(transactionIDs, request.Addresses, messageIDs); err != nil {
+		return
+	}
+	for _, transactionID := range transactionIDs {
+		if messagelayer.Tangle().LedgerState.TransactionMetadata(transactionID).Consume(func(*ledgerstate.TransactionMetadata) {}) {
+			s.send(Event{Type: EventBooked, TransactionID: transactionID.Base58()})
+			h.evaluateTransaction(transactionID)
+		}
+	}
+	for _, messageID := range messageIDs {
+		messagelayer.Tangle().Storage.MessageMetadata(messageID).Consume(func(messageMetadata *tangle.MessageMetadata) {
+			if messageMetadata.IsBooked() {
+				s.send(Event{Type: EventBooked, MessageID: messageID.String()})
+			}
+			if messageMetadata.IsConfirmed() {
+				s.send(Event{Type: EventConfirmed, MessageID: messageID.String()})
+			}
+		})
+	}
+
+	return
+}
+
+// onMessageBooked notifies the subscribers of the Message, of its Transaction and of the addresses that the
+// Transaction touches.
+func (h *hub) onMessageBooked(messageID tangle.MessageID) {
+	h.forEachSubscriber(func(s *subscriber) {
+		if s.messageSubscribed(messageID) {
+			s.send(Event{Type: EventBooked, MessageID: messageID.String()})
+		}
+	})
+
+	transaction := messageTransaction(messageID)
+	if transaction == nil {
+		return
+	}
+
+	addresses := transactionAddresses(transaction)
+	h.forEachSubscriber(func(s *subscriber) {
+		if s.transactionSubscribed(transaction.ID()) {
+			s.send(Event{Type: EventBooked, TransactionID: transaction.ID().Base58(), MessageID: messageID.String()})
+		}
+		for _, address := range s.watchAddressTransaction(transaction.ID(), addresses) {
+			s.send(Event{Type: EventBooked, TransactionID: transaction.ID().Base58(), MessageID: messageID.String(), Address: address})
+		}
+	})
+
+	h.evaluateTransaction(transaction.ID())
+}
+
+// onPayloadOpinionFormed notifies the subscribers of the Transaction about the opinion of the node.
+func (h *hub) onPayloadOpinionFormed(opinionFormedEvent *tangle.OpinionFormedEvent) {
+	eventType := EventDisliked
+	if opinionFormedEvent.Opinion {
+		eventType = EventLiked
+	}
+
+	messagelayer.Tangle().Utils.ComputeIfTransaction(opinionFormedEvent.MessageID, func(transactionID ledgerstate.TransactionID) {
+		h.forEachSubscriber(func(s *subscriber) {
+			if address, watched := s.transactionWatched(transactionID); watched {
+				s.send(Event{Type: eventType, TransactionID: transactionID.Base58(), MessageID: opinionFormedEvent.MessageID.String(), Address: address})
+			}
+		})
+
+		h.evaluateTransaction(transactionID)
+	})
+}
+
+// onBranchLikedChanged notifies the subscribers of the Transaction of a ConflictBranch whenever the node changes its
+// opinion about the Branch.
+func (h *hub) onBranchLikedChanged(branchDAGEvent *ledgerstate.BranchDAGEvent, liked bool) {
+	defer branchDAGEvent.Release()
+
+	branch := branchDAGEvent.Branch.Unwrap()
+	if branch == nil || branch.Type() != ledgerstate.ConflictBranchType {
+		return
+	}
+
+	eventType := EventDisliked
+	if liked {
+		eventType = EventLiked
+	}
+
+	transactionID := ledgerstate.TransactionID(branch.ID())
+	h.forEachSubscriber(func(s *subscriber) {
+		if address, watched := s.transactionWatched(transactionID); watched {
+			s.send(Event{Type: eventType, TransactionID: transactionID.Base58(), Address: address})
+		}
+	})
+}
+
+// onMessageConfirmed notifies the subscribers of the Message and checks if its Transaction was confirmed.
+func (h *hub) onMessageConfirmed(messageID tangle.MessageID) {
+	h.forEachSubscriber(func(s *subscriber) {
+		if s.messageSubscribed(messageID) {
+			s.send(Event{Type: EventConfirmed, MessageID: messageID.String()})
+		}
+	})
+
+	messagelayer.Tangle().Utils.ComputeIfTransaction(messageID, h.evaluateTransaction)
+}
+
+// evaluateSubscribedTransactions checks if any of the watched Transactions reached a final InclusionState (i.e. after
+// the decision about a Branch).
+func (h *hub) evaluateSubscribedTransactions() {
+	transactionIDs := make(map[ledgerstate.TransactionID]bool)
+	h.forEachSubscriber(func(s *subscriber) {
+		for _, transactionID := range s.pendingTransactions() {
+			transactionIDs[transactionID] = true
+		}
+	})
+
+	for transactionID := range transactionIDs {
+		h.evaluateTransaction(transactionID)
+	}
+}
+
+// evaluateTransaction notifies the subscribers of the Transaction if it reached a final InclusionState.
+func (h *hub) evaluateTransaction(transactionID ledgerstate.TransactionID) {
+	inclusionState, err := messagelayer.Tangle().LedgerState.TransactionInclusionState(transactionID)
+	if err != nil || inclusionState == ledgerstate.Pending {
+		return
+	}
+
+	h.forEachSubscriber(func(s *subscriber) {
+		if address, finalized := s.finalizeTransaction(transactionID); finalized {
+			s.send(Event{Type: inclusionStateEvent(inclusionState), TransactionID: transactionID.Base58(), Address: address})
+		}
+	})
+}
+
+// forEachSubscriber calls the consumer for every connected subscriber.
+func (h *hub) forEachSubscriber(consumer func(s *subscriber)) {
+	h.subscribersMutex.RLock()
+	defer h.subscribersMutex.RUnlock()
+
+	for s := range h.subscribers {
+		consumer(s)
+	}
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region subscriber ///////////////////////////////////////////////////////////////////////////////////////////////////
+
+// subscriber represents a connected client together with the identifiers that it subscribed to.
+type subscriber struct {
+	conn   *websocket.Conn
+	events chan Event
+	closed chan struct{}
+
+	transactionIDs map[ledgerstate.TransactionID]bool
+	addresses      map[string]bool
+	messageIDs     map[tangle.MessageID]bool
+
+	// addressTransactions contains the Transactions that touched one of the subscribed addresses.
+	addressTransactions map[ledgerstate.TransactionID]string
+
+	// finalizedTransactions contains the Transactions whose final InclusionState was sent already.
+	finalizedTransactions map[ledgerstate.TransactionID]bool
+
+	closeOnce sync.Once
+	mutex     sync.Mutex
+}
+
+// newSubscriber creates a subscriber for the given websocket connection.
+func newSubscriber(conn *websocket.Conn) *subscriber {
+	return &subscriber{
+		conn:                  conn,
+		events:                make(chan Event, subscriberEventBufferSize),
+		closed:                make(chan struct{}),
+		transactionIDs:        make(map[ledgerstate.TransactionID]bool),
+		addresses:             make(map[string]bool),
+		messageIDs:            make(map[tangle.MessageID]bool),
+		addressTransactions:   make(map[ledgerstate.TransactionID]string),
+		finalizedTransactions: make(map[ledgerstate.TransactionID]bool),
+	}
+}
+
+// subscribe adds the given identifiers to the subscription. It returns an error and leaves the subscription unchanged
+// if the subscriber would exceed the maximum number of subscriptions.
+func (s *subscriber) subscribe(transactionIDs []ledgerstate.TransactionID, addresses []string, messageIDs []tangle.MessageID) (err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	subscriptionCount := len(s.transactionIDs) + len(s.addresses) + len(s.messageIDs)
+	for _, transactionID := range transactionIDs {
+		if !s.transactionIDs[transactionID] {
+			subscriptionCount++
+		}
+	}
+	for _, address := range addresses {
+		if !s.addresses[address] {
+			subscriptionCount++
+		}
+	}
+	for _, messageID := range messageIDs {
+		if !s.messageIDs[messageID] {
+			subscriptionCount++
+		}
+	}
+	if subscriptionCount > maxSubscriptionsPerSubscriber {
+		return xerrors.Errorf("subscription exceeds the maximum of %d identifiers per connection", maxSubscriptionsPerSubscriber)
+	}
+
+	for _, transactionID := range transactionIDs {
+		s.transactionIDs[transactionID] = true
+		delete(s.finalizedTransactions, transactionID)
+	}
+	for _, address := range addresses {
+		s.addresses[address] = true
+	}
+	for _, messageID := range messageIDs {
+		s.messageIDs[messageID] = true
+	}
+
+	return
+}
+
+// unsubscribe removes the given identifiers from the subscription.
+func (s *subscriber) unsubscribe(transactionIDs []ledgerstate.TransactionID, addresses []string, messageIDs []tangle.MessageID) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, transactionID := range transactionIDs {
+		delete(s.transactionIDs, transactionID)
+		delete(s.finalizedTransactions, transactionID)
+	}
+	for _, address := range addresses {
+		delete(s.addresses, address)
+		for transactionID, transactionAddress := range s.addressTransactions {
+			if transactionAddress == address {
+				delete(s.addressTransactions, transactionID)
+				delete(s.finalizedTransactions, transactionID)
+			}
+		}
+	}
+	for _, messageID := range messageIDs {
+		delete(s.messageIDs, messageID)
+	}
+}
+
+// clear drops all the subscriptions of the subscriber.
+func (s *subscriber) clear() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.transactionIDs = make(map[ledgerstate.TransactionID]bool)
+	s.addresses = make(map[string]bool)
+	s.messageIDs = make(map[tangle.MessageID]bool)
+	s.addressTransactions = make(map[ledgerstate.TransactionID]string)
+	s.finalizedTransactions = make(map[ledgerstate.TransactionID]bool)
+}
+
+// messageSubscribed returns true if the subscriber subscribed to the given Message.
+func (s *subscriber) messageSubscribed(messageID tangle.MessageID) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.messageIDs[messageID]
+}
+
+// transactionSubscribed returns true if the subscriber subscribed to the given Transaction.
+func (s *subscriber) transactionSubscribed(transactionID ledgerstate.TransactionID) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.transactionIDs[transactionID]
+}
+
+// transactionWatched returns true if the subscriber subscribed to the given Transaction or to one of the addresses that
+// it touches (the address is returned as well in the latter case).
+func (s *subscriber) transactionWatched(transactionID ledgerstate.TransactionID) (address string, watched bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.transactionWatchedLocked(transactionID)
+}
+
+// watchAddressTransaction starts to watch the given Transaction if it touches one of the subscribed addresses and
+// returns the matching addresses.
+func (s *subscriber) watchAddressTransaction(transactionID ledgerstate.TransactionID, transactionAddresses map[string]bool) (matchingAddresses []string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for address := range transactionAddresses {
+		if !s.addresses[address] {
+			continue
+		}
+
+		if _, watched := s.addressTransactions[transactionID]; !watched && len(s.addressTransactions) < maxSubscriptionsPerSubscriber {
+			s.addressTransactions[transactionID] = address
+		}
+		matchingAddresses = append(matchingAddresses, address)
+	}
+
+	return
+}
+
+// pendingTransactions returns the watched Transactions whose final InclusionState has not been sent, yet.
+func (s *subscriber) pendingTransactions() (transactionIDs []ledgerstate.TransactionID) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for transactionID := range s.transactionIDs {
+		if !s.finalizedTransactions[transactionID] {
+			transactionIDs = append(transactionIDs, transactionID)
+		}
+	}
+	for transactionID := range s.addressTransactions {
+		if !s.finalizedTransactions[transactionID] && !s.transactionIDs[transactionID] {
+			transactionIDs = append(transactionIDs, transactionID)
+		}
+	}
+
+	return
+}
+
+// finalizeTransaction marks the given Transaction as finalized and returns true if it is watched by the subscriber and
+// its final InclusionState was not sent before.
+func (s *subscriber) finalizeTransaction(transactionID ledgerstate.TransactionID) (address string, finalized bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.finalizedTransactions[transactionID] {
+		return
+	}
+	if address, finalized = s.transactionWatchedLocked(transactionID); !finalized {
+		return
+	}
+
+	// Transactions that are only watched because of an address are not needed anymore once they are final
+	if !s.transactionIDs[transactionID] {
+		delete(s.addressTransactions, transactionID)
+		return
+	}
+	s.finalizedTransactions[transactionID] = true
+
+	return
+}
+
+// transactionWatchedLocked contains the logic of transactionWatched (it expects the mutex to be locked).
+func (s *subscriber) transactionWatchedLocked(transactionID ledgerstate.TransactionID) (address string, watched bool) {
+	if s.transactionIDs[transactionID] {
+		return "", true
+	}
+
+	address, watched = s.addressTransactions[transactionID]
+
+	return
+}
+
+// send queues the Event for the client. Clients that do not keep up with their Events are disconnected, so they do
+// not silently miss any state changes.
+func (s *subscriber) send(event Event) {
+	select {
+	case <-s.closed:
+	case s.events <- event:
+	default:
+		s.close()
+	}
+}
+
+// writeEvents writes the queued Events to the websocket connection until the subscriber is closed.
+func (s *subscriber) writeEvents() {
+	for {
+		select {
+		case <-s.closed:
+			return
+		case event := <-s.events:
+			if err := s.conn.SetWriteDeadline(time.Now().Add(webSocketWriteTimeout)); err != nil {
+				s.close()
+				return
+			}
+			if err := s.conn.WriteJSON(event); err != nil {
+				s.close()
+				return
+			}
+		}
+	}
+}
+
+// close stops the writer of the subscriber and closes its connection.
+func (s *subscriber) close() {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+		_ = s.conn.Close()
+	})
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,183 @@
+package subscriptions
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/goshimmer/packages/tangle"
+	"github.com/iotaledger/goshimmer/plugins/messagelayer"
+	"github.com/iotaledger/goshimmer/plugins/webapi"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/node"
+	"github.com/labstack/echo"
+)
+
+// PluginName is the name of the web API subscriptions endpoint plugin.
+const PluginName = "WebAPI subscriptions Endpoint"
+
+const (
+	// ActionSubscribe is the action of a Request that adds the given identifiers to the subscription.
+	ActionSubscribe = "subscribe"
+
+	// ActionUnsubscribe is the action of a Request that removes the given identifiers from the subscription.
+	ActionUnsubscribe = "unsubscribe"
+)
+
+const (
+	// EventBooked is sent when a subscribed Message or Transaction (or a Transaction that touches a subscribed address)
+	// was booked.
+	EventBooked = "booked"
+
+	// EventLiked is sent when the node formed a positive opinion about a subscribed Transaction (or when it started to
+	// like the Branch of the Transaction).
+	EventLiked = "liked"
+
+	// EventDisliked is sent when the node formed a negative opinion about a subscribed Transaction (or when it stopped
+	// liking the Branch of the Transaction).
+	EventDisliked = "disliked"
+
+	// EventConfirmed is sent when a subscribed Message or Transaction was confirmed.
+	EventConfirmed = "confirmed"
+
+	// EventRejected is sent when a subscribed Transaction was rejected.
+	EventRejected = "rejected"
+
+	// EventError is sent when a Request of the client could not be processed.
+	EventError = "error"
+)
+
+// webSocketWriteTimeout defines the time after which a client that does not read its events is disconnected.
+const webSocketWriteTimeout = 3 * time.Second
+
+var (
+	// plugin is the plugin instance of the web API subscriptions endpoint plugin.
+	plugin *node.Plugin
+	once   sync.Once
+
+	// subscriptions contains the subscribers that are currently connected.
+	subscriptions = newHub()
+
+	// upgrader upgrades the HTTP requests of the subscriptions endpoint to websocket connections.
+	upgrader = websocket.Upgrader{
+		HandshakeTimeout: webSocketWriteTimeout,
+		CheckOrigin:      func(r *http.Request) bool { return true },
+	}
+)
+
+// Plugin gets the plugin instance.
+func Plugin() *node.Plugin {
+	once.Do(func() {
+		plugin = node.NewPlugin(PluginName, node.Enabled, configure)
+	})
+	return plugin
+}
+
+func configure(_ *node.Plugin) {
+	webapi.Server().GET("subscriptions", subscriptionsHandler)
+
+	messagelayer.Tangle().Booker.Events.MessageBooked.Attach(events.NewClosure(subscriptions.onMessageBooked))
+	messagelayer.Tangle().ApprovalWeightManager.Events.MessageConfirmed.Attach(events.NewClosure(subscriptions.onMessageConfirmed))
+	if messagelayer.Tangle().OpinionFormer != nil {
+		messagelayer.Tangle().OpinionFormer.Events.PayloadOpinionFormed.Attach(events.NewClosure(subscriptions.onPayloadOpinionFormed))
+	}
+
+	onBranchDecided := events.NewClosure(func(branchDAGEvent *ledgerstate.BranchDAGEvent) {
+		defer branchDAGEvent.Release()
+
+		subscriptions.evaluateSubscribedTransactions()
+	})
+	messagelayer.Tangle().LedgerState.BranchDAG.Events.BranchConfirmed.Attach(onBranchDecided)
+	messagelayer.Tangle().LedgerState.BranchDAG.Events.BranchRejected.Attach(onBranchDecided)
+	messagelayer.Tangle().LedgerState.BranchDAG.Events.BranchLiked.Attach(events.NewClosure(func(branchDAGEvent *ledgerstate.BranchDAGEvent) {
+		subscriptions.onBranchLikedChanged(branchDAGEvent, true)
+	}))
+	messagelayer.Tangle().LedgerState.BranchDAG.Events.BranchDisliked.Attach(events.NewClosure(func(branchDAGEvent *ledgerstate.BranchDAGEvent) {
+		subscriptions.onBranchLikedChanged(branchDAGEvent, false)
+	}))
+}
+
+// subscriptionsHandler upgrades the connection to a websocket and streams the events of the Messages, Transactions and
+// addresses that the client subscribes to.
+func subscriptionsHandler(c echo.Context) error {
+	conn, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	subscriber := subscriptions.register(conn)
+	defer subscriptions.unregister(subscriber)
+
+	go subscriber.writeEvents()
+
+	for {
+		var request Request
+		if err := conn.ReadJSON(&request); err != nil {
+			return nil
+		}
+
+		if err := subscriptions.update(subscriber, request); err != nil {
+			subscriber.send(Event{Type: EventError, Error: err.Error()})
+		}
+	}
+}
+
+// Request is the message that a client sends to change its subscription.
+type Request struct {
+	Action         string   `json:"action"`
+	TransactionIDs []string `json:"transaction_ids,omitempty"`
+	Addresses      []string `json:"addresses,omitempty"`
+	MessageIDs     []string `json:"message_ids,omitempty"`
+}
+
+// Event is the message that is sent to a client whenever the state of one of its subscriptions changes.
+type Event struct {
+	Type          string `json:"type"`
+	TransactionID string `json:"transaction_id,omitempty"`
+	MessageID     string `json:"message_id,omitempty"`
+	Address       string `json:"address,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// inclusionStateEvent returns the type of the Event that corresponds to the given final InclusionState.
+func inclusionStateEvent(inclusionState ledgerstate.InclusionState) string {
+	if inclusionState == ledgerstate.Confirmed {
+		return EventConfirmed
+	}
+
+	return EventRejected
+}
+
+// messageTransaction returns the Transaction that is contained in the given Message (or nil if it does not contain
+// one).
+func messageTransaction(messageID tangle.MessageID) (transaction *ledgerstate.Transaction) {
+	messagelayer.Tangle().Storage.Message(messageID).Consume(func(message *tangle.Message) {
+		if payload := message.Payload(); payload.Type() == ledgerstate.TransactionType {
+			transaction = payload.(*ledgerstate.Transaction)
+		}
+	})
+
+	return
+}
+
+// transactionAddresses returns the base58 encoded addresses that are credited or debited by the given Transaction.
+func transactionAddresses(transaction *ledgerstate.Transaction) (addresses map[string]bool) {
+	addresses = make(map[string]bool)
+	for _, output := range transaction.Essence().Outputs() {
+		addresses[output.Address().Base58()] = true
+	}
+	for _, input := range transaction.Essence().Inputs() {
+		if input.Type() != ledgerstate.UTXOInputType {
+			continue
+		}
+
+		messagelayer.Tangle().LedgerState.Output(input.(*ledgerstate.UTXOInput).ReferencedOutputID()).Consume(func(output ledgerstate.Output) {
+			addresses[output.Address().Base58()] = true
+		})
+	}
+
+	return
+}
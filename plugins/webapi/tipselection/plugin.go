@@ -0,0 +1,91 @@
+package tipselection
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/tangle"
+	"github.com/iotaledger/goshimmer/plugins/config"
+	"github.com/iotaledger/goshimmer/plugins/messagelayer"
+	"github.com/iotaledger/goshimmer/plugins/webapi"
+	"github.com/iotaledger/hive.go/node"
+	"github.com/labstack/echo"
+)
+
+// PluginName is the name of the web API tip selection endpoint plugin.
+const PluginName = "WebAPI tip selection Endpoint"
+
+var (
+	// plugin is the plugin instance of the web API tip selection endpoint plugin.
+	plugin *node.Plugin
+	once   sync.Once
+)
+
+// Plugin gets the plugin instance.
+func Plugin() *node.Plugin {
+	once.Do(func() {
+		plugin = node.NewPlugin(PluginName, node.Enabled, configure)
+	})
+	return plugin
+}
+
+func configure(_ *node.Plugin) {
+	webapi.Server().GET("tipselection", getTipSelectionStrategyHandler)
+	webapi.Server().POST("tipselection", setTipSelectionStrategyHandler)
+}
+
+// getTipSelectionStrategyHandler returns the tip selection strategy that is currently used by the node.
+func getTipSelectionStrategyHandler(c echo.Context) error {
+	return c.JSON(http.StatusOK, newTipSelectionResponse(messagelayer.Tangle().TipManager.TipSelectionStrategy()))
+}
+
+// setTipSelectionStrategyHandler replaces the tip selection strategy that is used by the node.
+func setTipSelectionStrategyHandler(c echo.Context) error {
+	var request TipSelectionRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, TipSelectionResponse{Error: err.Error()})
+	}
+
+	maxAge := config.Node().Duration(messagelayer.CfgTipSelectionMaxAge)
+	if request.MaxAge != "" {
+		var err error
+		if maxAge, err = time.ParseDuration(request.MaxAge); err != nil {
+			return c.JSON(http.StatusBadRequest, TipSelectionResponse{Error: err.Error()})
+		}
+	}
+
+	strategy, err := tangle.NewTipSelectionStrategy(request.Strategy, maxAge)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, TipSelectionResponse{Error: err.Error()})
+	}
+	messagelayer.Tangle().TipManager.SetTipSelectionStrategy(strategy)
+
+	return c.JSON(http.StatusOK, newTipSelectionResponse(strategy))
+}
+
+func newTipSelectionResponse(strategy tangle.TipSelectionStrategy) (response TipSelectionResponse) {
+	response = TipSelectionResponse{
+		Strategy:            strategy.Name(),
+		AvailableStrategies: tangle.TipSelectionStrategyNames(),
+	}
+	if maxAgeStrategy, ok := strategy.(*tangle.MaxAgeStrategy); ok {
+		response.MaxAge = maxAgeStrategy.MaxAge.String()
+	}
+
+	return
+}
+
+// TipSelectionRequest is the request to change the tip selection strategy.
+type TipSelectionRequest struct {
+	Strategy string `json:"strategy"`
+	MaxAge   string `json:"maxAge,omitempty"`
+}
+
+// TipSelectionResponse is the response of the tip selection endpoints.
+type TipSelectionResponse struct {
+	Strategy            string   `json:"strategy,omitempty"`
+	MaxAge              string   `json:"maxAge,omitempty"`
+	AvailableStrategies []string `json:"availableStrategies,omitempty"`
+	Error               string   `json:"error,omitempty"`
+}
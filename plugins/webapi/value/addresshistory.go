@@ -0,0 +1,276 @@
+package value
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/goshimmer/plugins/messagelayer"
+	"github.com/labstack/echo"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// defaultAddressHistoryLimit defines the amount of entries that are returned if no limit was requested.
+	defaultAddressHistoryLimit = 100
+
+	// maxAddressHistoryLimit defines the maximum amount of entries that are returned by a single request.
+	maxAddressHistoryLimit = 1000
+)
+
+// addressHistoryHandler gets the Transactions that credited or debited an address.
+func addressHistoryHandler(c echo.Context) error {
+	query, err := parseAddressHistoryQuery(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, AddressHistoryResponse{Error: err.Error()})
+	}
+
+	page, more := messagelayer.Tangle().LedgerState.AddressHistoryPage(query.address, query.cursor, query.limit, query.matches)
+
+	entries := make([]AddressHistoryEntry, 0, len(page))
+	for _, addressHistoryEntry := range page {
+		entries = append(entries, AddressHistoryEntry{
+			TransactionID:  addressHistoryEntry.TransactionID().Base58(),
+			Timestamp:      addressHistoryEntry.Timestamp().Unix(),
+			Received:       parseColoredBalances(addressHistoryEntry.Received()),
+			Spent:          parseColoredBalances(addressHistoryEntry.Spent()),
+			InclusionState: transactionInclusionState(addressHistoryEntry.TransactionID()),
+			BranchID:       messagelayer.Tangle().LedgerState.BranchID(addressHistoryEntry.TransactionID()).Base58(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, AddressHistoryResponse{
+		Address:    query.address.Base58(),
+		Entries:    entries,
+		NextCursor: nextAddressHistoryCursor(page, more),
+	})
+}
+
+// addressBalanceHistoryHandler gets the balances of an address after each of the confirmed Transactions that credited
+// or debited it. Pending, conflicting and rejected Transactions are not part of the balance history.
+func addressBalanceHistoryHandler(c echo.Context) error {
+	query, err := parseAddressHistoryQuery(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, AddressBalanceHistoryResponse{Error: err.Error()})
+	}
+
+	// the balances are accumulated over the confirmed entries (including the filtered ones) in the order of the
+	// history, so the iteration starts at the beginning and stops as soon as the page is complete
+	entries := make([]AddressBalanceHistoryEntry, 0, query.limit)
+	balances := make(map[ledgerstate.Color]uint64)
+	var lastEntry *ledgerstate.AddressHistoryEntry
+	more := false
+	messagelayer.Tangle().LedgerState.ForEachAddressHistoryEntry(query.address, func(addressHistoryEntry *ledgerstate.AddressHistoryEntry) bool {
+		inclusionState := transactionInclusionState(addressHistoryEntry.TransactionID())
+		if !inclusionState.Confirmed {
+			return true
+		}
+
+		partOfPage := query.matches(addressHistoryEntry) && (query.cursor == nil || query.cursor.Before(addressHistoryEntry.Cursor()))
+		if partOfPage && len(entries) == query.limit {
+			more = true
+			return false
+		}
+
+		addressHistoryEntry.Spent().ForEach(func(color ledgerstate.Color, balance uint64) bool {
+			// guard against an inconsistent history (i.e. caused by a pruned Transaction) instead of wrapping around
+			if balance > balances[color] {
+				balance = balances[color]
+			}
+			balances[color] -= balance
+			return true
+		})
+		addressHistoryEntry.Received().ForEach(func(color ledgerstate.Color, balance uint64) bool {
+			balances[color] += balance
+			return true
+		})
+
+		if partOfPage {
+			entries = append(entries, AddressBalanceHistoryEntry{
+				TransactionID:  addressHistoryEntry.TransactionID().Base58(),
+				Timestamp:      addressHistoryEntry.Timestamp().Unix(),
+				Balances:       parseColoredBalances(ledgerstate.NewColoredBalances(nonZeroBalances(balances))),
+				InclusionState: inclusionState,
+			})
+			lastEntry = addressHistoryEntry
+		}
+
+		return true
+	})
+
+	response := AddressBalanceHistoryResponse{
+		Address: query.address.Base58(),
+		Entries: entries,
+	}
+	if more {
+		response.NextCursor = lastEntry.Cursor().Base58()
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// nextAddressHistoryCursor returns the cursor that retrieves the page following the given one (or an empty string if
+// there are no more entries).
+func nextAddressHistoryCursor(page ledgerstate.AddressHistoryEntries, more bool) string {
+	if !more || len(page) == 0 {
+		return ""
+	}
+
+	return page[len(page)-1].Cursor().Base58()
+}
+
+// addressHistoryQuery contains the parsed filter and pagination parameters of the address history endpoints.
+type addressHistoryQuery struct {
+	address ledgerstate.Address
+	color   *ledgerstate.Color
+	from    *time.Time
+	to      *time.Time
+	cursor  *ledgerstate.AddressHistoryCursor
+	limit   int
+}
+
+// parseAddressHistoryQuery parses the query parameters of the address history endpoints.
+func parseAddressHistoryQuery(c echo.Context) (query *addressHistoryQuery, err error) {
+	query = &addressHistoryQuery{limit: defaultAddressHistoryLimit}
+	if query.address, err = ledgerstate.AddressFromBase58EncodedString(c.QueryParam("address")); err != nil {
+		return nil, xerrors.Errorf("failed to parse address: %w", err)
+	}
+
+	if colorString := c.QueryParam("color"); colorString != "" {
+		color, colorErr := ledgerstate.ColorFromBase58EncodedString(colorString)
+		if colorErr != nil {
+			return nil, xerrors.Errorf("failed to parse color: %w", colorErr)
+		}
+		query.color = &color
+	}
+	if query.from, err = parseUnixTimestamp(c.QueryParam("from")); err != nil {
+		return nil, xerrors.Errorf("failed to parse from: %w", err)
+	}
+	if query.to, err = parseUnixTimestamp(c.QueryParam("to")); err != nil {
+		return nil, xerrors.Errorf("failed to parse to: %w", err)
+	}
+	if cursorString := c.QueryParam("cursor"); cursorString != "" {
+		cursor, cursorErr := ledgerstate.AddressHistoryCursorFromBase58(cursorString)
+		if cursorErr != nil {
+			return nil, xerrors.Errorf("failed to parse cursor: %w", cursorErr)
+		}
+		query.cursor = &cursor
+	}
+	if limit := c.QueryParam("limit"); limit != "" {
+		if query.limit, err = strconv.Atoi(limit); err != nil || query.limit <= 0 || query.limit > maxAddressHistoryLimit {
+			return nil, xerrors.Errorf("limit needs to be between 1 and %d", maxAddressHistoryLimit)
+		}
+	}
+
+	return query, nil
+}
+
+// matches returns true if the given AddressHistoryEntry passes the color and time filters of the query.
+func (a *addressHistoryQuery) matches(addressHistoryEntry *ledgerstate.AddressHistoryEntry) bool {
+	if a.color != nil && !addressHistoryEntry.ContainsColor(*a.color) {
+		return false
+	}
+	if a.from != nil && addressHistoryEntry.Timestamp().Before(*a.from) {
+		return false
+	}
+	if a.to != nil && addressHistoryEntry.Timestamp().After(*a.to) {
+		return false
+	}
+
+	return true
+}
+
+// parseUnixTimestamp parses an optional unix timestamp (in seconds).
+func parseUnixTimestamp(timestamp string) (parsedTime *time.Time, err error) {
+	if timestamp == "" {
+		return
+	}
+
+	unixSeconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return
+	}
+	convertedTime := time.Unix(unixSeconds, 0)
+	parsedTime = &convertedTime
+
+	return
+}
+
+// transactionInclusionState returns the InclusionState of the Transaction with the given TransactionID.
+func transactionInclusionState(transactionID ledgerstate.TransactionID) (inclusionState InclusionState) {
+	messagelayer.Tangle().LedgerState.TransactionMetadata(transactionID).Consume(func(transactionMetadata *ledgerstate.TransactionMetadata) {
+		inclusionState.Solid = transactionMetadata.Solid()
+		inclusionState.Finalized = transactionMetadata.Finalized()
+	})
+
+	txInclusionState, err := messagelayer.Tangle().LedgerState.TransactionInclusionState(transactionID)
+	if err != nil {
+		return
+	}
+	inclusionState.Confirmed = txInclusionState == ledgerstate.Confirmed
+	inclusionState.Rejected = txInclusionState == ledgerstate.Rejected
+	inclusionState.Conflicting = messagelayer.Tangle().LedgerState.TransactionConflicting(transactionID)
+
+	return
+}
+
+// parseColoredBalances converts the given ColoredBalances into their JSON representation.
+func parseColoredBalances(coloredBalances *ledgerstate.ColoredBalances) (balances []Balance) {
+	balances = make([]Balance, 0, coloredBalances.Size())
+	coloredBalances.ForEach(func(color ledgerstate.Color, balance uint64) bool {
+		balances = append(balances, Balance{
+			Value: int64(balance),
+			Color: color.String(),
+		})
+		return true
+	})
+
+	return
+}
+
+// nonZeroBalances returns a copy of the given balances without the Colors whose balance is zero.
+func nonZeroBalances(balances map[ledgerstate.Color]uint64) (result map[ledgerstate.Color]uint64) {
+	result = make(map[ledgerstate.Color]uint64)
+	for color, balance := range balances {
+		if balance != 0 {
+			result[color] = balance
+		}
+	}
+
+	return
+}
+
+// AddressHistoryEntry holds a Transaction that credited or debited an address.
+type AddressHistoryEntry struct {
+	TransactionID  string         `json:"transaction_id"`
+	Timestamp      int64          `json:"timestamp"`
+	Received       []Balance      `json:"received"`
+	Spent          []Balance      `json:"spent"`
+	InclusionState InclusionState `json:"inclusion_state"`
+	BranchID       string         `json:"branch_id"`
+}
+
+// AddressHistoryResponse is the HTTP response from retrieving the history of an address.
+type AddressHistoryResponse struct {
+	Address    string                `json:"address,omitempty"`
+	Entries    []AddressHistoryEntry `json:"entries,omitempty"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+	Error      string                `json:"error,omitempty"`
+}
+
+// AddressBalanceHistoryEntry holds the balances of an address after a Transaction credited or debited it.
+type AddressBalanceHistoryEntry struct {
+	TransactionID  string         `json:"transaction_id"`
+	Timestamp      int64          `json:"timestamp"`
+	Balances       []Balance      `json:"balances"`
+	InclusionState InclusionState `json:"inclusion_state"`
+}
+
+// AddressBalanceHistoryResponse is the HTTP response from retrieving the balance history of an address.
+type AddressBalanceHistoryResponse struct {
+	Address    string                       `json:"address,omitempty"`
+	Entries    []AddressBalanceHistoryEntry `json:"entries,omitempty"`
+	NextCursor string                       `json:"next_cursor,omitempty"`
+	Error      string                       `json:"error,omitempty"`
+}
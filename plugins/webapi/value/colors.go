@@ -0,0 +1,70 @@
+package value
+
+import (
+	"net/http"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/goshimmer/plugins/messagelayer"
+	"github.com/labstack/echo"
+)
+
+// colorHandler gets the supply information of a color.
+func colorHandler(c echo.Context) error {
+	color, err := ledgerstate.ColorFromBase58EncodedString(c.QueryParam("color"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ColorResponse{Error: err.Error()})
+	}
+
+	var response ColorResponse
+	if !messagelayer.Tangle().LedgerState.ColorMetadata(color).Consume(func(colorMetadata *ledgerstate.ColorMetadata) {
+		response = ColorResponse{Color: ParseColorMetadata(colorMetadata)}
+	}) {
+		return c.JSON(http.StatusNotFound, ColorResponse{Error: "Color not found"})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// colorsHandler gets the supply information of all known colors.
+func colorsHandler(c echo.Context) error {
+	colors := make([]Color, 0)
+	messagelayer.Tangle().LedgerState.ForEachColorMetadata(func(colorMetadata *ledgerstate.ColorMetadata) bool {
+		colors = append(colors, ParseColorMetadata(colorMetadata))
+
+		return true
+	})
+
+	return c.JSON(http.StatusOK, ColorsResponse{Colors: colors})
+}
+
+// ParseColorMetadata converts the ColorMetadata of the ledger into its JSON representation.
+func ParseColorMetadata(colorMetadata *ledgerstate.ColorMetadata) Color {
+	return Color{
+		Color:                colorMetadata.Color().Base58(),
+		MintingTransactionID: colorMetadata.MintingTransactionID().Base58(),
+		MintedSupply:         colorMetadata.MintedSupply(),
+		BurnedSupply:         colorMetadata.BurnedSupply(),
+		CirculatingSupply:    colorMetadata.CirculatingSupply(),
+	}
+}
+
+// Color holds the supply information of a color.
+type Color struct {
+	Color                string `json:"color"`
+	MintingTransactionID string `json:"minting_transaction_id"`
+	MintedSupply         uint64 `json:"minted_supply"`
+	BurnedSupply         uint64 `json:"burned_supply"`
+	CirculatingSupply    uint64 `json:"circulating_supply"`
+}
+
+// ColorResponse is the HTTP response from retrieving the supply information of a color.
+type ColorResponse struct {
+	Color Color  `json:"color,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// ColorsResponse is the HTTP response from retrieving the supply information of all known colors.
+type ColorsResponse struct {
+	Colors []Color `json:"colors,omitempty"`
+	Error  string  `json:"error,omitempty"`
+}
@@ -48,11 +48,16 @@ type Transaction struct {
 	DataPayload []byte   `json:"data_payload"`
 }
 
-// OutputID holds the output id and its inclusion state
+// OutputID holds the output id, its unlock conditions and its inclusion state
 type OutputID struct {
-	ID             string         `json:"id"`
-	Balances       []Balance      `json:"balances"`
-	InclusionState InclusionState `json:"inclusion_state"`
+	ID               string         `json:"id"`
+	Type             string         `json:"type"`
+	Address          string         `json:"address"`
+	TimeLock         int64          `json:"timelock,omitempty"`
+	FallbackAddress  string         `json:"fallback_address,omitempty"`
+	FallbackDeadline int64          `json:"fallback_deadline,omitempty"`
+	Balances         []Balance      `json:"balances"`
+	InclusionState   InclusionState `json:"inclusion_state"`
 }
 
 // UnspentOutput holds the address and the corresponding unspent output ids
@@ -40,8 +40,18 @@ func getTransactionByIDHandler(c echo.Context) error {
 	}
 	branch := cachedBranch.Unwrap()
 
+	conflictingTransactions := make([]string, 0)
+	if messagelayer.Tangle().LedgerState.TransactionConflicting(txID) {
+		for conflictingTransactionID := range messagelayer.Tangle().LedgerState.ConflictSet(txID) {
+			if conflictingTransactionID != txID {
+				conflictingTransactions = append(conflictingTransactions, conflictingTransactionID.Base58())
+			}
+		}
+	}
+
 	return c.JSON(http.StatusOK, GetTransactionByIDResponse{
-		Transaction: txn,
+		Transaction:             txn,
+		ConflictingTransactions: conflictingTransactions,
 		InclusionState: InclusionState{
 			Confirmed:   txInclusionState == ledgerstate.Confirmed,
 			Conflicting: messagelayer.Tangle().LedgerState.TransactionConflicting(txID),
@@ -56,7 +66,8 @@ func getTransactionByIDHandler(c echo.Context) error {
 
 // GetTransactionByIDResponse is the HTTP response from retrieving transaction.
 type GetTransactionByIDResponse struct {
-	Transaction    Transaction    `json:"transaction,omitempty"`
-	InclusionState InclusionState `json:"inclusion_state,omitempty"`
-	Error          string         `json:"error,omitempty"`
+	Transaction             Transaction    `json:"transaction,omitempty"`
+	InclusionState          InclusionState `json:"inclusion_state,omitempty"`
+	ConflictingTransactions []string       `json:"conflicting_transactions,omitempty"`
+	Error                   string         `json:"error,omitempty"`
 }
@@ -30,4 +30,8 @@ func configure(_ *node.Plugin) {
 	webapi.Server().POST("value/sendTransaction", sendTransactionHandler)
 	//webapi.Server().POST("value/sendTransactionByJson", sendTransactionByJSONHandler)
 	webapi.Server().GET("value/transactionByID", getTransactionByIDHandler)
+	webapi.Server().GET("value/color", colorHandler)
+	webapi.Server().GET("value/colors", colorsHandler)
+	webapi.Server().GET("value/address/history", addressHistoryHandler)
+	webapi.Server().GET("value/address/balanceHistory", addressBalanceHistoryHandler)
 }
@@ -56,11 +56,25 @@ func unspentOutputsHandler(c echo.Context) error {
 					inclusionState.Rejected = txInclusionState == ledgerstate.Rejected
 					inclusionState.Conflicting = len(messagelayer.Tangle().LedgerState.ConflictSet(txID)) == 0
 
-					outputids = append(outputids, OutputID{
+					outputID := OutputID{
 						ID:             output.ID().Base58(),
+						Type:           output.Type().String(),
+						Address:        output.Address().Base58(),
 						Balances:       b,
 						InclusionState: inclusionState,
-					})
+					}
+					// the unlock conditions of ExtendedLockedOutputs (the output is listed for its fallback address as well)
+					if extendedLockedOutput, isExtendedLockedOutput := output.(*ledgerstate.ExtendedLockedOutput); isExtendedLockedOutput {
+						if timelock := extendedLockedOutput.TimeLock(); !timelock.IsZero() {
+							outputID.TimeLock = timelock.Unix()
+						}
+						if fallbackAddress, fallbackDeadline := extendedLockedOutput.FallbackAddress(); fallbackAddress != nil {
+							outputID.FallbackAddress = fallbackAddress.Base58()
+							outputID.FallbackDeadline = fallbackDeadline.Unix()
+						}
+					}
+
+					outputids = append(outputids, outputID)
 				}
 			})
 		})
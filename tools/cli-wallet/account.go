@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/iotaledger/goshimmer/client/wallet"
+)
+
+func execAccountCommand(command *flag.FlagSet, cliWallet *wallet.Wallet) {
+	command.Usage = func() {
+		printUsage(command)
+	}
+
+	listPtr := command.Bool("list", false, "list all accounts")
+	createPtr := command.String("create", "", "create a new account with the given name")
+	helpPtr := command.Bool("help", false, "display this help screen")
+
+	err := command.Parse(os.Args[2:])
+	if err != nil {
+		printUsage(command, err.Error())
+	}
+	if *helpPtr {
+		printUsage(command)
+	}
+
+	// sanitize flags
+	if *listPtr && *createPtr != "" {
+		printUsage(command, "please provide only one option at a time")
+	}
+	if !*listPtr && *createPtr == "" {
+		printUsage(command)
+	}
+
+	if *createPtr != "" {
+		account, createErr := cliWallet.CreateAccount(*createPtr)
+		if createErr != nil {
+			printUsage(command, createErr.Error())
+		}
+
+		fmt.Println()
+		fmt.Println("Created Account: " + account.Name())
+		fmt.Println("Receive Address: " + account.ReceiveAddress().Address().Base58())
+
+		return
+	}
+
+	// initialize tab writer
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 2, '\t', 0)
+	defer w.Flush()
+
+	// print header
+	fmt.Println()
+	_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", "INDEX", "NAME", "RECEIVE ADDRESS", "ADDRESSES")
+	_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", "-----", "-------------------------", "--------------------------------------------", "---------")
+
+	for _, account := range cliWallet.Accounts() {
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%d\n", account.Index(), account.Name(), account.ReceiveAddress().Address().Base58(), len(account.Addresses()))
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"text/tabwriter"
 
 	"github.com/iotaledger/goshimmer/client/wallet"
+	"github.com/iotaledger/goshimmer/client/wallet/packages/address"
 )
 
 func execAddressCommand(command *flag.FlagSet, cliWallet *wallet.Wallet) {
@@ -19,6 +20,7 @@ func execAddressCommand(command *flag.FlagSet, cliWallet *wallet.Wallet) {
 	listPtr := command.Bool("list", false, "list all addresses")
 	listUnspentPtr := command.Bool("listunspent", false, "list all unspent addresses")
 	listSpentPtr := command.Bool("listspent", false, "list all spent addresses")
+	accountPtr := command.String("account", wallet.DefaultAccountName, "name of the account whose addresses are shown (optional)")
 	helpPtr := command.Bool("help", false, "display this help screen")
 
 	err := command.Parse(os.Args[2:])
@@ -53,82 +55,52 @@ func execAddressCommand(command *flag.FlagSet, cliWallet *wallet.Wallet) {
 		printUsage(command, "please provide only one option at a time")
 	}
 
+	account := cliWallet.Account(*accountPtr)
+	if account == nil {
+		printUsage(command, "unknown account: "+*accountPtr)
+	}
+
 	if *receivePtr {
 		fmt.Println()
-		fmt.Println("Latest Receive Address: " + cliWallet.ReceiveAddress().Address().Base58())
+		fmt.Println("Latest Receive Address: " + account.ReceiveAddress().Address().Base58())
 	}
 
 	if *newReceiveAddressPtr {
 		fmt.Println()
-		fmt.Println("New Receive Address: " + cliWallet.NewReceiveAddress().Address().Base58())
+		fmt.Println("New Receive Address: " + account.NewReceiveAddress().Address().Base58())
 	}
 
 	if *listPtr {
-		// initialize tab writer
-		w := new(tabwriter.Writer)
-		w.Init(os.Stdout, 0, 8, 2, '\t', 0)
-		defer w.Flush()
-
-		// print header
-		fmt.Println()
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", "INDEX", "ADDRESS", "SPENT")
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", "-----", "--------------------------------------------", "-----")
-
-		addressPrinted := false
-		for _, addr := range cliWallet.AddressManager().Addresses() {
-			_, _ = fmt.Fprintf(w, "%d\t%s\t%t\n", addr.Index, addr.String(), cliWallet.AddressManager().IsAddressSpent(addr.Index))
-
-			addressPrinted = true
-		}
-
-		if !addressPrinted {
-			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", "<EMPTY>", "<EMPTY>", "<EMPTY>")
-		}
+		printAddresses(account, account.Addresses())
 	}
 
 	if *listUnspentPtr {
-		// initialize tab writer
-		w := new(tabwriter.Writer)
-		w.Init(os.Stdout, 0, 8, 2, '\t', 0)
-		defer w.Flush()
-
-		// print header
-		fmt.Println()
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", "INDEX", "ADDRESS", "SPENT")
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", "-------", "--------------------------------------------", "-------")
-
-		addressPrinted := false
-		for _, addr := range cliWallet.AddressManager().UnspentAddresses() {
-			_, _ = fmt.Fprintf(w, "%d\t%s\t%t\n", addr.Index, addr.String(), cliWallet.AddressManager().IsAddressSpent(addr.Index))
-
-			addressPrinted = true
-		}
-
-		if !addressPrinted {
-			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", "<EMPTY>", "<EMPTY>", "<EMPTY>")
-		}
+		printAddresses(account, account.UnspentAddresses())
 	}
 
 	if *listSpentPtr {
-		// initialize tab writer
-		w := new(tabwriter.Writer)
-		w.Init(os.Stdout, 0, 8, 2, '\t', 0)
-		defer w.Flush()
-
-		// print header
-		fmt.Println()
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", "INDEX", "ADDRESS", "SPENT")
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", "-------", "--------------------------------------------", "-------")
-
-		addressPrinted := false
-		for _, addr := range cliWallet.AddressManager().SpentAddresses() {
-			_, _ = fmt.Fprintf(w, "%d\t%s\t%t\n", addr.Index, addr.String(), cliWallet.AddressManager().IsAddressSpent(addr.Index))
+		printAddresses(account, account.SpentAddresses())
+	}
+}
 
-			addressPrinted = true
-		}
+// printAddresses prints the given addresses of the account together with their position in the chain they belong to.
+func printAddresses(account *wallet.Account, addresses []address.Address) {
+	// initialize tab writer
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 2, '\t', 0)
+	defer w.Flush()
+
+	// print header
+	fmt.Println()
+	_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", "CHAIN", "INDEX", "ADDRESS", "SPENT")
+	_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", "-------", "-------", "--------------------------------------------", "-------")
+
+	for _, addr := range addresses {
+		_, chain, position := wallet.DerivationPath(addr.Index)
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%s\t%t\n", chain, position, addr.String(), account.IsAddressSpent(addr))
+	}
 
-		if !addressPrinted {
-			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", "<EMPTY>", "<EMPTY>", "<EMPTY>")
-		}
+	if len(addresses) == 0 {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", "<EMPTY>", "<EMPTY>", "<EMPTY>", "<EMPTY>")
 	}
 }
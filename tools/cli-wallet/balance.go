@@ -10,16 +10,31 @@ import (
 )
 
 func execBalanceCommand(command *flag.FlagSet, cliWallet *wallet.Wallet) {
+	accountPtr := command.String("account", "", "only show the balances of the given account (optional)")
+
 	err := command.Parse(os.Args[2:])
 	if err != nil {
 		panic(err)
 	}
 
-	confirmedBalance, pendingBalance, err := cliWallet.Balance()
+	accountNames := make([]string, 0)
+	if *accountPtr != "" {
+		accountNames = append(accountNames, *accountPtr)
+	}
+
+	confirmedBalance, pendingBalance, err := cliWallet.Balance(accountNames...)
 	if err != nil {
-		printUsage(nil, err.Error())
+		printUsage(command, err.Error())
+	}
+
+	// print the balances of the individual accounts if the wallet has several ones
+	if *accountPtr == "" && len(cliWallet.Accounts()) > 1 {
+		defer printAccountBalances(cliWallet)
 	}
 
+	// load the assets of unknown colored tokens from the ledger (the balances are still shown if this fails)
+	_ = cliWallet.RefreshAssetRegistry()
+
 	// initialize tab writer
 	w := new(tabwriter.Writer)
 	w.Init(os.Stdout, 0, 8, 2, '\t', 0)
@@ -45,3 +60,29 @@ func execBalanceCommand(command *flag.FlagSet, cliWallet *wallet.Wallet) {
 		_, _ = fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", "[PEND]", amount, color.String(), cliWallet.AssetRegistry().Name(color))
 	}
 }
+
+// printAccountBalances prints the confirmed balances of the individual accounts of the wallet.
+func printAccountBalances(cliWallet *wallet.Wallet) {
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 2, '\t', 0)
+	defer w.Flush()
+
+	fmt.Println()
+	_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", "ACCOUNT", "BALANCE", "COLOR")
+	_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", "-------------------------", "---------------", "--------------------------------------------")
+	for _, account := range cliWallet.Accounts() {
+		confirmedBalance, _, err := cliWallet.Balance(account.Name())
+		if err != nil {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", account.Name(), "<ERROR>", err.Error())
+
+			continue
+		}
+
+		if len(confirmedBalance) == 0 {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", account.Name(), "<EMPTY>", "<EMPTY>")
+		}
+		for color, amount := range confirmedBalance {
+			_, _ = fmt.Fprintf(w, "%s\t%d %s\t%s\n", account.Name(), amount, cliWallet.AssetRegistry().Symbol(color), color.String())
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/iotaledger/goshimmer/client/wallet"
+)
+
+func execCombineCommand(command *flag.FlagSet) {
+	helpPtr := command.Bool("help", false, "show this help screen")
+	outPtr := command.String("out", "transaction.pst", "the file that the combined transaction is written to")
+
+	err := command.Parse(os.Args[2:])
+	if err != nil {
+		panic(err)
+	}
+
+	if *helpPtr {
+		printUsage(command)
+	}
+	if command.NArg() < 2 {
+		printUsage(command, "at least two transaction files have to be provided")
+	}
+
+	var combinedTransaction *wallet.PartiallySignedTransaction
+	for _, fileName := range command.Args() {
+		partiallySignedTransaction := readPartiallySignedTransactionFile(command, fileName)
+		if combinedTransaction == nil {
+			combinedTransaction = partiallySignedTransaction
+			continue
+		}
+
+		if err = combinedTransaction.Combine(partiallySignedTransaction); err != nil {
+			printUsage(command, err.Error())
+		}
+	}
+	writePartiallySignedTransactionFile(combinedTransaction, *outPtr)
+
+	fmt.Println()
+	fmt.Printf("Combining %d transactions ... [DONE]\n", command.NArg())
+	fmt.Printf("%d of %d inputs signed - written to %s\n", combinedTransaction.SignedInputs(), len(combinedTransaction.ConsumedOutputs()), *outPtr)
+}
@@ -18,6 +18,8 @@ func execCreateAssetCommand(command *flag.FlagSet, cliWallet *wallet.Wallet) {
 	amountPtr := command.Uint64("amount", 0, "the amount of tokens to be created")
 	namePtr := command.String("name", "", "the name of the tokens to create")
 	symbolPtr := command.String("symbol", "", "the symbol of the tokens to create")
+	precisionPtr := command.Int("precision", 0, "the amount of decimal places that are used to display the tokens")
+	urlPtr := command.String("url", "", "a link to further information about the tokens")
 
 	err := command.Parse(os.Args[2:])
 	if err != nil {
@@ -36,9 +38,11 @@ func execCreateAssetCommand(command *flag.FlagSet, cliWallet *wallet.Wallet) {
 	}
 
 	assetColor, err := cliWallet.CreateAsset(wallet.Asset{
-		Name:   *namePtr,
-		Symbol: *symbolPtr,
-		Amount: *amountPtr,
+		Name:      *namePtr,
+		Symbol:    *symbolPtr,
+		Precision: *precisionPtr,
+		URL:       *urlPtr,
+		Amount:    *amountPtr,
 	})
 	if err != nil {
 		printUsage(command, err.Error())
@@ -6,13 +6,10 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"unsafe"
 
 	"github.com/iotaledger/goshimmer/client"
 	"github.com/iotaledger/goshimmer/client/wallet"
 	walletseed "github.com/iotaledger/goshimmer/client/wallet/packages/seed"
-	"github.com/iotaledger/hive.go/bitmask"
-	"github.com/iotaledger/hive.go/crypto/ed25519"
 	"github.com/iotaledger/hive.go/marshalutil"
 	"github.com/mr-tron/base58"
 )
@@ -22,24 +19,59 @@ func printBanner() {
 }
 
 func loadWallet() *wallet.Wallet {
-	seed, lastAddressIndex, spentAddresses, assetRegistry, err := importWalletStateFile("wallet.dat")
+	accountManager, assetRegistry, err := importWalletStateFile("wallet.dat")
 	if err != nil {
 		panic(err)
 	}
+	pendingTransactionManager, err := importPendingTransactionsFile("pending.dat")
+	if err != nil {
+		panic(err)
+	}
+
+	return wallet.New(
+		wallet.WebAPI(config.WebAPI, clientOptions()...),
+		wallet.ImportAccounts(accountManager, assetRegistry),
+		wallet.PendingTransactions(pendingTransactionManager),
+	)
+}
 
+// clientOptions returns the options of the web API client that are defined in the config.
+func clientOptions() (options []client.Option) {
 	// configure basic-auth
-	options := []client.Option{}
 	if config.BasicAuth.IsEnabled() {
 		options = append(options, client.WithBasicAuth(config.BasicAuth.Credentials()))
 	}
 
-	return wallet.New(
-		wallet.WebAPI(config.WebAPI, options...),
-		wallet.Import(seed, lastAddressIndex, spentAddresses, assetRegistry),
-	)
+	return
+}
+
+// importPendingTransactionsFile restores the transactions that were issued by previous calls of the wallet and that are
+// not confirmed, yet.
+func importPendingTransactionsFile(filename string) (pendingTransactionManager *wallet.PendingTransactionManager, err error) {
+	pendingTransactionsBytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return wallet.NewPendingTransactionManager(), nil
+		}
+
+		return
+	}
+
+	pendingTransactionManager, _, err = wallet.ParsePendingTransactionManager(marshalutil.New(pendingTransactionsBytes))
+
+	return
+}
+
+// writePendingTransactionsFile persists the transactions that are tracked by the wallet.
+func writePendingTransactionsFile(cliWallet *wallet.Wallet, filename string) {
+	if err := ioutil.WriteFile(filename, cliWallet.PendingTransactionManager().Bytes(), 0644); err != nil {
+		panic(err)
+	}
 }
 
-func importWalletStateFile(filename string) (seed *walletseed.Seed, lastAddressIndex uint64, spentAddresses []bitmask.BitMask, assetRegistry *wallet.AssetRegistry, err error) {
+// importWalletStateFile loads the state of the wallet (or creates a new wallet if the init command is called). The state
+// files of previous versions of the wallet are upgraded to the versioned format when they are written back.
+func importWalletStateFile(filename string) (accountManager *wallet.AccountManager, assetRegistry *wallet.AssetRegistry, err error) {
 	walletStateBytes, err := ioutil.ReadFile(filename)
 	if err != nil {
 		if !os.IsNotExist(err) {
@@ -47,12 +79,12 @@ func importWalletStateFile(filename string) (seed *walletseed.Seed, lastAddressI
 		}
 
 		if len(os.Args) < 2 || os.Args[1] != "init" {
-			printUsage(nil, "no wallet file (wallet.dat) found: please call \""+filepath.Base(os.Args[0])+" init\"")
+			printUsage(nil, "no wallet file (wallet.dat) found: please call \""+filepath.Base(os.Args[0])+" init\" or \""+filepath.Base(os.Args[0])+" restore\"")
 		}
 
-		seed = walletseed.NewSeed()
-		lastAddressIndex = 0
-		spentAddresses = []bitmask.BitMask{}
+		seed := walletseed.NewSeed()
+		accountManager = wallet.NewAccountManager(seed)
+		assetRegistry = wallet.NewAssetRegistry()
 		err = nil
 
 		fmt.Println("GENERATING NEW WALLET ...                                 [DONE]")
@@ -72,25 +104,7 @@ func importWalletStateFile(filename string) (seed *walletseed.Seed, lastAddressI
 		printUsage(nil, "please remove the wallet.dat before trying to create a new wallet")
 	}
 
-	marshalUtil := marshalutil.New(walletStateBytes)
-
-	seedBytes, err := marshalUtil.ReadBytes(ed25519.SeedSize)
-	seed = walletseed.NewSeed(seedBytes)
-	if err != nil {
-		return
-	}
-
-	lastAddressIndex, err = marshalUtil.ReadUint64()
-	if err != nil {
-		return
-	}
-
-	assetRegistry, _, err = wallet.ParseAssetRegistry(marshalUtil)
-
-	spentAddressesBytes := marshalUtil.ReadRemainingBytes()
-	spentAddresses = *(*[]bitmask.BitMask)(unsafe.Pointer(&spentAddressesBytes))
-
-	return
+	return wallet.ParseState(walletStateBytes)
 }
 
 func writeWalletStateFile(wallet *wallet.Wallet, filename string) {
@@ -120,6 +134,26 @@ func writeWalletStateFile(wallet *wallet.Wallet, filename string) {
 	}
 }
 
+func readPartiallySignedTransactionFile(command *flag.FlagSet, filename string) *wallet.PartiallySignedTransaction {
+	partiallySignedTransactionBytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		printUsage(command, err.Error())
+	}
+
+	partiallySignedTransaction, err := wallet.PartiallySignedTransactionFromBytes(partiallySignedTransactionBytes)
+	if err != nil {
+		printUsage(command, err.Error())
+	}
+
+	return partiallySignedTransaction
+}
+
+func writePartiallySignedTransactionFile(partiallySignedTransaction *wallet.PartiallySignedTransaction, filename string) {
+	if err := ioutil.WriteFile(filename, partiallySignedTransaction.Bytes(), 0644); err != nil {
+		panic(err)
+	}
+}
+
 func printUsage(command *flag.FlagSet, optionalErrorMessage ...string) {
 	if len(optionalErrorMessage) >= 1 {
 		_, _ = fmt.Fprintf(os.Stderr, "\n")
@@ -134,16 +168,30 @@ func printUsage(command *flag.FlagSet, optionalErrorMessage ...string) {
 		fmt.Println("COMMANDS:")
 		fmt.Println("  balance")
 		fmt.Println("        show the balances held by this wallet")
+		fmt.Println("  pending")
+		fmt.Println("        reattach or rebuild the lost transfers and show the state of the pending ones")
 		fmt.Println("  send-funds")
 		fmt.Println("        initiate a value transfer")
+		fmt.Println("  prepare")
+		fmt.Println("        create an unsigned value transfer that can be signed offline")
+		fmt.Println("  sign")
+		fmt.Println("        sign the inputs of a prepared transfer that belong to this wallet")
+		fmt.Println("  combine")
+		fmt.Println("        merge the signatures of several signed copies of a prepared transfer")
+		fmt.Println("  submit")
+		fmt.Println("        issue a prepared transfer once all of its inputs are signed")
 		fmt.Println("  create-asset")
 		fmt.Println("        create an asset in the form of colored coins")
 		fmt.Println("  address")
 		fmt.Println("        start the address manager of this wallet")
+		fmt.Println("  account")
+		fmt.Println("        list or create the accounts of this wallet")
 		fmt.Println("  request-funds")
 		fmt.Println("        request funds from the testnet-faucet")
 		fmt.Println("  init")
 		fmt.Println("        generate a new wallet using a random seed")
+		fmt.Println("  restore")
+		fmt.Println("        restore a wallet and its accounts from an existing seed")
 		fmt.Println("  server-status")
 		fmt.Println("        display the server status")
 		fmt.Println("  help")
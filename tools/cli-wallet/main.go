@@ -24,9 +24,17 @@ func main() {
 		printUsage(nil)
 	}
 
+	// restore the wallet from its seed (this creates the wallet.dat, so it happens before loading the wallet)
+	if len(os.Args) >= 2 && os.Args[1] == "restore" {
+		execRestoreCommand(flag.NewFlagSet("restore", flag.ExitOnError))
+
+		return
+	}
+
 	// load wallet
 	wallet := loadWallet()
 	defer writeWalletStateFile(wallet, "wallet.dat")
+	defer writePendingTransactionsFile(wallet, "pending.dat")
 
 	// check if parameters potentially include sub commands
 	if len(os.Args) < 2 {
@@ -35,20 +43,38 @@ func main() {
 
 	// define sub commands
 	balanceCommand := flag.NewFlagSet("balance", flag.ExitOnError)
+	pendingCommand := flag.NewFlagSet("pending", flag.ExitOnError)
 	sendFundsCommand := flag.NewFlagSet("send-funds", flag.ExitOnError)
 	createAssetCommand := flag.NewFlagSet("create-asset", flag.ExitOnError)
 	addressCommand := flag.NewFlagSet("address", flag.ExitOnError)
+	accountCommand := flag.NewFlagSet("account", flag.ExitOnError)
 	requestFaucetFundsCommand := flag.NewFlagSet("request-funds", flag.ExitOnError)
 	serverStatusCommand := flag.NewFlagSet("server-status", flag.ExitOnError)
+	prepareCommand := flag.NewFlagSet("prepare", flag.ExitOnError)
+	signCommand := flag.NewFlagSet("sign", flag.ExitOnError)
+	combineCommand := flag.NewFlagSet("combine", flag.ExitOnError)
+	submitCommand := flag.NewFlagSet("submit", flag.ExitOnError)
 
 	// switch logic according to provided sub command
 	switch os.Args[1] {
 	case "balance":
 		execBalanceCommand(balanceCommand, wallet)
+	case "pending":
+		execPendingCommand(pendingCommand, wallet)
 	case "address":
 		execAddressCommand(addressCommand, wallet)
+	case "account":
+		execAccountCommand(accountCommand, wallet)
 	case "send-funds":
 		execSendFundsCommand(sendFundsCommand, wallet)
+	case "prepare":
+		execPrepareCommand(prepareCommand, wallet)
+	case "sign":
+		execSignCommand(signCommand, wallet)
+	case "combine":
+		execCombineCommand(combineCommand)
+	case "submit":
+		execSubmitCommand(submitCommand, wallet)
 	case "create-asset":
 		execCreateAssetCommand(createAssetCommand, wallet)
 	case "request-funds":
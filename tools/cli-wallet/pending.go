@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/iotaledger/goshimmer/client/wallet"
+)
+
+func execPendingCommand(command *flag.FlagSet, cliWallet *wallet.Wallet) {
+	err := command.Parse(os.Args[2:])
+	if err != nil {
+		printUsage(command, err.Error())
+	}
+
+	// reattach or rebuild the transfers that were lost or rejected
+	if err = cliWallet.ProcessPendingTransactions(); err != nil {
+		printUsage(command, err.Error())
+	}
+
+	pendingTransactions := cliWallet.PendingTransactions()
+	if len(pendingTransactions) == 0 {
+		fmt.Println()
+		fmt.Println("No pending transactions")
+
+		return
+	}
+
+	printPendingTransactions(pendingTransactions)
+}
+
+// printPendingTransactions prints the state of the transactions that were issued by the wallet and that are not
+// confirmed, yet (or that reached a final state since the last call).
+func printPendingTransactions(pendingTransactions []*wallet.PendingTransaction) {
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 2, '\t', 0)
+	defer w.Flush()
+
+	fmt.Println()
+	_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", "STATUS", "TRANSACTION ID", "ATTACHMENTS", "DETAILS")
+	_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", "-----------", "--------------------------------------------", "-----------", "-------------------------")
+	for _, pendingTransaction := range pendingTransactions {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", "["+pendingTransaction.State.String()+"]", pendingTransaction.Transaction.ID().Base58(), pendingTransaction.Reattachments+1, pendingTransactionDetails(pendingTransaction))
+	}
+}
+
+// pendingTransactionDetails returns a human readable explanation of the state of the given PendingTransaction.
+func pendingTransactionDetails(pendingTransaction *wallet.PendingTransaction) string {
+	details := make([]string, 0)
+	if len(pendingTransaction.ConflictingTransactionIDs) != 0 {
+		conflictingTransactions := make([]string, len(pendingTransaction.ConflictingTransactionIDs))
+		for i, conflictingTransactionID := range pendingTransaction.ConflictingTransactionIDs {
+			conflictingTransactions[i] = conflictingTransactionID.Base58()
+		}
+		details = append(details, "conflicts with "+strings.Join(conflictingTransactions, ", "))
+	}
+	if pendingTransaction.State == wallet.PendingTransactionReissued {
+		details = append(details, "reissued as "+pendingTransaction.ReissuedTransactionID.Base58())
+	}
+	if pendingTransaction.Error != "" {
+		details = append(details, "error: "+pendingTransaction.Error)
+	}
+
+	return strings.Join(details, "; ")
+}
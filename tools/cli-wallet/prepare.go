@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/iotaledger/goshimmer/client/wallet"
+)
+
+func execPrepareCommand(command *flag.FlagSet, cliWallet *wallet.Wallet) {
+	helpPtr := command.Bool("help", false, "show this help screen")
+	addressPtr := command.String("dest-addr", "", "destination address for the transfer")
+	amountPtr := command.Int64("amount", 0, "the amount of tokens that are supposed to be sent")
+	colorPtr := command.String("color", "IOTA", "color of the tokens to transfer (optional)")
+	filePtr := command.String("file", "transaction.pst", "the file that the unsigned transaction is written to")
+
+	err := command.Parse(os.Args[2:])
+	if err != nil {
+		panic(err)
+	}
+
+	if *helpPtr {
+		printUsage(command)
+	}
+
+	if *addressPtr == "" {
+		printUsage(command, "dest-addr has to be set")
+	}
+	if *amountPtr <= 0 {
+		printUsage(command, "amount has to be set and be bigger than 0")
+	}
+	if *colorPtr == "" {
+		printUsage(command, "color must be set")
+	}
+
+	partiallySignedTransaction, err := cliWallet.PrepareTransaction(parseDestination(command, *addressPtr, *amountPtr, *colorPtr))
+	if err != nil {
+		printUsage(command, err.Error())
+	}
+	writePartiallySignedTransactionFile(partiallySignedTransaction, *filePtr)
+
+	fmt.Println()
+	fmt.Printf("Preparing transaction with %d inputs ... [DONE]\n", len(partiallySignedTransaction.ConsumedOutputs()))
+	fmt.Println("Written to " + *filePtr)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/iotaledger/goshimmer/client/wallet"
+	walletseed "github.com/iotaledger/goshimmer/client/wallet/packages/seed"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/mr-tron/base58"
+)
+
+func execRestoreCommand(command *flag.FlagSet) {
+	command.Usage = func() {
+		printUsage(command)
+	}
+
+	seedPtr := command.String("seed", "", "base58 encoded seed of the wallet that is restored")
+	gapLimitPtr := command.Int("gap-limit", wallet.DefaultGapLimit, "number of consecutive unused addresses after which the search for used addresses stops")
+	helpPtr := command.Bool("help", false, "display this help screen")
+
+	err := command.Parse(os.Args[2:])
+	if err != nil {
+		printUsage(command, err.Error())
+	}
+	if *helpPtr {
+		printUsage(command)
+	}
+
+	if *seedPtr == "" {
+		printUsage(command, "seed has to be set")
+	}
+	if *gapLimitPtr <= 0 {
+		printUsage(command, "gap-limit has to be bigger than 0")
+	}
+	if _, err = os.Stat("wallet.dat"); err == nil {
+		printUsage(command, "please remove the wallet.dat before trying to restore a wallet")
+	}
+
+	seedBytes, err := base58.Decode(*seedPtr)
+	if err != nil {
+		printUsage(command, err.Error())
+	}
+	if len(seedBytes) != ed25519.SeedSize {
+		printUsage(command, fmt.Sprintf("seed has to be %d bytes long", ed25519.SeedSize))
+	}
+
+	fmt.Println()
+	fmt.Println("Restoring wallet ... [SCANNING ADDRESSES]                   (this can take a while)")
+
+	cliWallet := wallet.New(
+		wallet.WebAPI(config.WebAPI, clientOptions()...),
+		wallet.ImportAccounts(wallet.NewAccountManager(walletseed.NewSeed(seedBytes)), wallet.NewAssetRegistry()),
+	)
+	if err = cliWallet.RestoreAccounts(*gapLimitPtr); err != nil {
+		panic(err)
+	}
+	writeWalletStateFile(cliWallet, "wallet.dat")
+
+	fmt.Println("Restoring wallet ... [DONE]")
+	fmt.Println()
+	for _, account := range cliWallet.Accounts() {
+		fmt.Printf("Account %d (%s): %d addresses\n", account.Index(), account.Name(), len(account.Addresses()))
+	}
+}
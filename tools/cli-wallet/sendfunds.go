@@ -17,6 +17,7 @@ func execSendFundsCommand(command *flag.FlagSet, cliWallet *wallet.Wallet) {
 	addressPtr := command.String("dest-addr", "", "destination address for the transfer")
 	amountPtr := command.Int64("amount", 0, "the amount of tokens that are supposed to be sent")
 	colorPtr := command.String("color", "IOTA", "color of the tokens to transfer (optional)")
+	accountPtr := command.String("account", wallet.DefaultAccountName, "name of the account whose funds are sent (optional)")
 
 	err := command.Parse(os.Args[2:])
 	if err != nil {
@@ -37,20 +38,34 @@ func execSendFundsCommand(command *flag.FlagSet, cliWallet *wallet.Wallet) {
 		printUsage(command, "color must be set")
 	}
 
-	destinationAddress, err := ledgerstate.AddressFromBase58EncodedString(*addressPtr)
+	_, err = cliWallet.SendFunds(
+		parseDestination(command, *addressPtr, *amountPtr, *colorPtr),
+		wallet.SourceAccount(*accountPtr),
+	)
+	if err != nil {
+		printUsage(command, err.Error())
+	}
+
+	fmt.Println()
+	fmt.Println("Sending funds ... [DONE]")
+}
+
+// parseDestination parses the destination parameters of a transfer and prints the usage of the command if they are
+// invalid.
+func parseDestination(command *flag.FlagSet, base58Address string, amount int64, colorString string) wallet.SendFundsOption {
+	destinationAddress, err := ledgerstate.AddressFromBase58EncodedString(base58Address)
 	if err != nil {
 		printUsage(command, err.Error())
-		return
 	}
 
 	var color ledgerstate.Color
-	switch *colorPtr {
+	switch colorString {
 	case "IOTA":
 		color = ledgerstate.ColorIOTA
 	case "NEW":
 		color = ledgerstate.ColorMint
 	default:
-		colorBytes, parseErr := base58.Decode(*colorPtr)
+		colorBytes, parseErr := base58.Decode(colorString)
 		if parseErr != nil {
 			printUsage(command, parseErr.Error())
 		}
@@ -61,15 +76,7 @@ func execSendFundsCommand(command *flag.FlagSet, cliWallet *wallet.Wallet) {
 		}
 	}
 
-	_, err = cliWallet.SendFunds(
-		wallet.Destination(address.Address{
-			AddressBytes: destinationAddress.Array(),
-		}, uint64(*amountPtr), color),
-	)
-	if err != nil {
-		printUsage(command, err.Error())
-	}
-
-	fmt.Println()
-	fmt.Println("Sending funds ... [DONE]")
+	return wallet.Destination(address.Address{
+		AddressBytes: destinationAddress.Array(),
+	}, uint64(amount), color)
 }
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/iotaledger/goshimmer/client/wallet"
+)
+
+func execSignCommand(command *flag.FlagSet, cliWallet *wallet.Wallet) {
+	helpPtr := command.Bool("help", false, "show this help screen")
+	filePtr := command.String("file", "transaction.pst", "the file containing the transaction that is supposed to be signed")
+	outPtr := command.String("out", "", "the file that the signed transaction is written to (defaults to the input file)")
+
+	err := command.Parse(os.Args[2:])
+	if err != nil {
+		panic(err)
+	}
+
+	if *helpPtr {
+		printUsage(command)
+	}
+	if *outPtr == "" {
+		*outPtr = *filePtr
+	}
+
+	partiallySignedTransaction := readPartiallySignedTransactionFile(command, *filePtr)
+	signedInputs, err := cliWallet.SignTransaction(partiallySignedTransaction)
+	if err != nil {
+		printUsage(command, err.Error())
+	}
+	writePartiallySignedTransactionFile(partiallySignedTransaction, *outPtr)
+
+	fmt.Println()
+	fmt.Printf("Signing %d inputs ... [DONE]\n", signedInputs)
+	fmt.Printf("%d of %d inputs signed - written to %s\n", partiallySignedTransaction.SignedInputs(), len(partiallySignedTransaction.ConsumedOutputs()), *outPtr)
+}
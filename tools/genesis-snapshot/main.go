@@ -1,14 +1,19 @@
 package main
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"time"
 
 	"github.com/iotaledger/goshimmer/client/wallet"
 	"github.com/iotaledger/goshimmer/client/wallet/packages/address"
 	"github.com/iotaledger/goshimmer/client/wallet/packages/seed"
+	"github.com/iotaledger/goshimmer/packages/assetregistry"
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/goshimmer/packages/tangle"
 	"github.com/iotaledger/hive.go/bitmask"
 	"github.com/mr-tron/base58"
 	flag "github.com/spf13/pflag"
@@ -19,13 +24,22 @@ const (
 	cfgGenesisTokenAmount   = "token-amount"
 	cfgSnapshotFileName     = "snapshot-file"
 	cfgSnapshotGenesisSeed  = "seed"
+	cfgSnapshotFormat       = "format"
+	cfgSnapshotNetworkID    = "network-id"
+	cfgSnapshotConvert      = "convert"
 	defaultSnapshotFileName = "./snapshot.bin"
+
+	formatLegacy    = "legacy"
+	formatVersioned = "v1"
 )
 
 func init() {
 	flag.Int(cfgGenesisTokenAmount, 1000000000000000, "the amount of tokens to add to the genesis output")
 	flag.String(cfgSnapshotFileName, defaultSnapshotFileName, "the name of the generated snapshot file")
 	flag.String(cfgSnapshotGenesisSeed, "", "the genesis seed")
+	flag.String(cfgSnapshotFormat, formatLegacy, "the format of the generated snapshot file (legacy|v1)")
+	flag.Uint32(cfgSnapshotNetworkID, 16, "the network ID that is written to snapshot files in the v1 format")
+	flag.String(cfgSnapshotConvert, "", "the path to an existing snapshot file that is converted into the other format instead of creating a new snapshot")
 }
 
 func main() {
@@ -35,7 +49,17 @@ func main() {
 	}
 	genesisTokenAmount := viper.GetInt64(cfgGenesisTokenAmount)
 	snapshotFileName := viper.GetString(cfgSnapshotFileName)
-	log.Printf("creating snapshot %s...", snapshotFileName)
+
+	if inputFileName := viper.GetString(cfgSnapshotConvert); inputFileName != "" {
+		convertSnapshot(inputFileName, snapshotFileName, viper.GetUint32(cfgSnapshotNetworkID))
+		return
+	}
+
+	format := viper.GetString(cfgSnapshotFormat)
+	if format != formatLegacy && format != formatVersioned {
+		log.Fatalf("unknown snapshot format: %s", format)
+	}
+	log.Printf("creating snapshot %s in the %s format...", snapshotFileName, format)
 
 	seedStr := viper.GetString(cfgSnapshotGenesisSeed)
 	if seedStr == "" {
@@ -76,17 +100,57 @@ func main() {
 		},
 	}
 
-	f, err := os.OpenFile(snapshotFileName, os.O_RDWR|os.O_CREATE, 0666)
+	var writeTo func(writer io.Writer) (int64, error) = snapshot.WriteTo
+	if format == formatVersioned {
+		writeTo = tangle.SnapshotFromLegacy(snapshot, viper.GetUint32(cfgSnapshotNetworkID), time.Now()).WriteTo
+	}
+	writeSnapshotFile(snapshotFileName, writeTo)
+
+	log.Printf("created %s, bye", snapshotFileName)
+}
+
+// convertSnapshot converts the snapshot file at the given input path into the respective other format. Converting into
+// the legacy format drops the solid entry points and merges the Outputs of the same Transaction and Address.
+func convertSnapshot(inputFileName, outputFileName string, networkID uint32) {
+	inputFile, err := os.Open(inputFileName)
+	if err != nil {
+		log.Fatal("unable to open snapshot file", err)
+	}
+	defer inputFile.Close()
+
+	reader := bufio.NewReader(inputFile)
+	if tangle.IsVersionedSnapshot(reader) {
+		log.Printf("converting %s from the %s into the %s format...", inputFileName, formatVersioned, formatLegacy)
+
+		snapshot := &tangle.Snapshot{}
+		if _, err = snapshot.ReadFrom(reader); err != nil {
+			log.Fatal("unable to read snapshot file", err)
+		}
+		writeSnapshotFile(outputFileName, snapshot.Legacy().WriteTo)
+	} else {
+		log.Printf("converting %s from the %s into the %s format...", inputFileName, formatLegacy, formatVersioned)
+
+		snapshot := ledgerstate.Snapshot{}
+		if _, err = snapshot.ReadFrom(reader); err != nil {
+			log.Fatal("unable to read snapshot file", err)
+		}
+		writeSnapshotFile(outputFileName, tangle.SnapshotFromLegacy(snapshot, networkID, time.Now()).WriteTo)
+	}
+
+	log.Printf("created %s, bye", outputFileName)
+}
+
+// writeSnapshotFile creates the file with the given name and writes the snapshot to it.
+func writeSnapshotFile(fileName string, writeTo func(writer io.Writer) (int64, error)) {
+	f, err := os.OpenFile(fileName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
 	if err != nil {
 		log.Fatal("unable to create snapshot file", err)
 	}
 	defer f.Close()
 
-	if _, err = snapshot.WriteTo(f); err != nil {
+	if _, err = writeTo(f); err != nil {
 		log.Fatal("unable to write snapshot content to file", err)
 	}
-
-	log.Printf("created %s, bye", snapshotFileName)
 }
 
 func (connector *mockConnector) UnspentOutputs(addresses ...address.Address) (outputs map[address.Address]map[ledgerstate.OutputID]*wallet.Output, err error) {
@@ -106,6 +170,15 @@ func (connector *mockConnector) UnspentOutputs(addresses ...address.Address) (ou
 	return
 }
 
+func (connector *mockConnector) UsedAddresses(addresses ...address.Address) (usedAddresses map[address.Address]bool, err error) {
+	usedAddresses = make(map[address.Address]bool)
+	for _, addr := range addresses {
+		usedAddresses[addr] = len(connector.outputs[addr]) != 0
+	}
+
+	return
+}
+
 type mockConnector struct {
 	outputs map[address.Address]map[ledgerstate.OutputID]*wallet.Output
 }
@@ -132,7 +205,19 @@ func (connector *mockConnector) RequestFaucetFunds(addr address.Address) (err er
 	return
 }
 
+func (connector *mockConnector) Asset(color ledgerstate.Color) (asset wallet.Asset, err error) {
+	return wallet.Asset{Color: color}, nil
+}
+
+func (connector *mockConnector) PublishAssetMetadata(assetMetadata *assetregistry.AssetMetadata) (err error) {
+	return
+}
+
 func (connector *mockConnector) SendTransaction(tx *ledgerstate.Transaction) (err error) {
 	// mark outputs as spent
 	return
 }
+
+func (connector *mockConnector) TransactionInclusionState(transactionID ledgerstate.TransactionID) (inclusionState wallet.InclusionState, conflictingTransactionIDs []ledgerstate.TransactionID, err error) {
+	return wallet.InclusionState{Liked: true, Confirmed: true}, nil, nil
+}